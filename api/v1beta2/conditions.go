@@ -20,16 +20,6 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 )
 
-const (
-	// ClusterFinalizer allows the controller to clean up resources associated with ContaboCluster before
-	// removing it from the apiserver.
-	ClusterFinalizer = "contabocluster.infrastructure.cluster.x-k8s.io"
-
-	// MachineFinalizer allows the controller to clean up resources associated with ContaboMachine before
-	// removing it from the apiserver.
-	MachineFinalizer = "contabomachine.infrastructure.cluster.x-k8s.io"
-)
-
 // =============================================================================
 // CONTABO CLUSTER CONDITIONS
 // =============================================================================
@@ -47,6 +37,15 @@ const (
 
 	// ClusterSshKeyReadyCondition indicates the cluster sshkey are ready.
 	ClusterSshKeyReadyCondition = "ClusterSshKeyReady"
+
+	// ClusterEtcdBackupReadyCondition indicates the etcd snapshots are uploaded to object storage.
+	ClusterEtcdBackupReadyCondition = "ClusterEtcdBackupReady"
+
+	// ClusterMachinesReadyCondition aggregates the conditions of the machines of the cluster.
+	ClusterMachinesReadyCondition = "ClusterMachinesReady"
+
+	// ClusterCredentialsValidCondition indicates the Contabo API accepts the credentials the cluster is managed with.
+	ClusterCredentialsValidCondition = "CredentialsValid"
 )
 
 // ContaboCluster condition reasons.
@@ -60,8 +59,38 @@ const (
 	// DeletingReason indicates that the cluster infrastructure is being deleted.
 	ClusterDeletingReason = clusterv1.DeletingReason
 
+	// DeletionPreviewReason is the event listing the Contabo resources the deletion of the cluster destroys.
+	DeletionPreviewReason = "DeletionPreview"
+
+	// DeletionConfirmationRequiredReason indicates the deletion waits for the confirm-deletion annotation, as it
+	// destroys more Contabo resources than the deletion confirmation threshold.
+	DeletionConfirmationRequiredReason = "DeletionConfirmationRequired"
+
 	// AvailableReason indicates that the cluster infrastructure is ready and available.
 	ClusterAvailableReason = clusterv1.AvailableReason
+
+	// ClusterExternallyManagedReason indicates the cluster infrastructure is managed by an external controller, set
+	// with the cluster.x-k8s.io/managed-by annotation, and only observed by the provider.
+	ClusterExternallyManagedReason = "ExternallyManaged"
+
+	// ClusterWaitingForExternalResourceReason indicates a resource of an externally managed cluster infrastructure
+	// was not found in Contabo, the external controller has to create it.
+	ClusterWaitingForExternalResourceReason = "WaitingForExternalResource"
+
+	// CredentialsUnavailableReason indicates the Secret referenced by the credentialsRef of the cluster is missing or
+	// incomplete, the Contabo resources of the cluster cannot be reconciled without it.
+	CredentialsUnavailableReason = "CredentialsUnavailable"
+
+	// CredentialsValidReason indicates the Contabo API accepted the last requests of the cluster.
+	CredentialsValidReason = "CredentialsValid"
+
+	// CredentialsRejectedReason indicates the Contabo API rejected the access token of the credentials, e.g. the API
+	// user was deleted or lost its permissions.
+	CredentialsRejectedReason = "CredentialsRejected"
+
+	// AccessTokenFailedReason indicates no access token could be obtained from the Contabo identity provider with the
+	// credentials, e.g. a wrong client secret or API password.
+	AccessTokenFailedReason = "AccessTokenFailed"
 )
 
 // Control plane endpoint condition reasons.
@@ -77,6 +106,14 @@ const (
 
 	// WaitingForControlPlaneEndpointReason indicates waiting for the control plane endpoint to be set.
 	WaitingForControlPlaneEndpointReason = "WaitingForControlPlaneEndpoint"
+
+	// ControlPlaneEndpointVIPNotAvailableReason indicates no floating VIP of the region is free for the control
+	// plane endpoint.
+	ControlPlaneEndpointVIPNotAvailableReason = "ControlPlaneEndpointVIPNotAvailable"
+
+	// ControlPlaneEndpointVIPAssignedReason indicates the control plane endpoint VIP was assigned to a control plane
+	// instance.
+	ControlPlaneEndpointVIPAssignedReason = "ControlPlaneEndpointVIPAssigned"
 )
 
 // Cluster private network condition reasons.
@@ -95,6 +132,18 @@ const (
 
 	// ClusterPrivateNetworkSkippedReason indicates cluster private network configuration was skipped.
 	ClusterPrivateNetworkSkippedReason = "ClusterPrivateNetworkSkipped"
+
+	// ClusterPrivateNetworkRetainedReason indicates the cluster private network was not deleted because other clusters still reference it.
+	ClusterPrivateNetworkRetainedReason = "ClusterPrivateNetworkRetained"
+
+	// ClusterPrivateNetworkStaleAssignmentRemovedReason indicates an instance left in the private network after its machine was deleted or its instance released was removed.
+	ClusterPrivateNetworkStaleAssignmentRemovedReason = "ClusterPrivateNetworkStaleAssignmentRemoved"
+
+	// ClusterPrivateNetworkNotFoundReason indicates the private network to adopt does not exist and is not created.
+	ClusterPrivateNetworkNotFoundReason = "ClusterPrivateNetworkNotFound"
+
+	// ClusterPrivateNetworkCIDRMismatchReason indicates the range of the private network differs from the spec CIDR.
+	ClusterPrivateNetworkCIDRMismatchReason = "ClusterPrivateNetworkCIDRMismatch"
 )
 
 // Cluster sshkey condition reasons.
@@ -115,6 +164,42 @@ const (
 	ClusterSshKeySkippedReason = "ClusterSshKeySkipped"
 )
 
+// Cluster etcd backup condition reasons.
+const (
+	// ClusterEtcdBackupReadyReason indicates the bucket holds recent etcd snapshots of the cluster.
+	ClusterEtcdBackupReadyReason = "ClusterEtcdBackupReady"
+
+	// ClusterEtcdBackupFailedReason indicates the bucket or the credentials of the workload cluster could not be set up.
+	ClusterEtcdBackupFailedReason = "ClusterEtcdBackupFailed"
+
+	// ClusterEtcdBackupWaitingForWorkloadClusterReason indicates the workload cluster is not reachable yet.
+	ClusterEtcdBackupWaitingForWorkloadClusterReason = "ClusterEtcdBackupWaitingForWorkloadCluster"
+
+	// ClusterEtcdBackupWaitingForSnapshotReason indicates no snapshot of the cluster was uploaded yet.
+	ClusterEtcdBackupWaitingForSnapshotReason = "ClusterEtcdBackupWaitingForSnapshot"
+)
+
+// Cluster machines condition reasons.
+const (
+	// ClusterMachinesReadyReason indicates all the machines of the cluster are ready without failing conditions.
+	ClusterMachinesReadyReason = "ClusterMachinesReady"
+
+	// ClusterMachinesNotReadyReason indicates machines of the cluster are not ready or report failing conditions.
+	ClusterMachinesNotReadyReason = "ClusterMachinesNotReady"
+)
+
+// Cluster etcd backup event reasons.
+const (
+	// ClusterEtcdBackupPrunedReason indicates etcd snapshots beyond the retention were deleted.
+	ClusterEtcdBackupPrunedReason = "ClusterEtcdBackupPruned"
+)
+
+// Cluster kubeconfig event reasons.
+const (
+	// ClusterKubeconfigUpdatedReason indicates the kubeconfig variants now point to another control plane machine.
+	ClusterKubeconfigUpdatedReason = "ClusterKubeconfigUpdated"
+)
+
 // =============================================================================
 // CONTABO MACHINE CONDITIONS
 // =============================================================================
@@ -141,6 +226,63 @@ const (
 
 	// InstanceBootstrapCondition indicates the instance bootstrap process is complete.
 	InstanceBootstrapCondition = "InstanceBootstrap"
+
+	// InstanceFirstBootProbeCondition indicates the SSH first-boot probe verified sshd and cloud-init health.
+	InstanceFirstBootProbeCondition = "InstanceFirstBootProbe"
+
+	// InstanceMigrationCondition indicates the state of a data center migration of the instance.
+	InstanceMigrationCondition = "InstanceMigration"
+
+	// InstancePowerStateCondition indicates the instance power state matches the power state of the spec.
+	InstancePowerStateCondition = "InstancePowerState"
+
+	// InstanceHostStableCondition indicates the instance did not move to another host system recently.
+	InstanceHostStableCondition = "InstanceHostStable"
+
+	// InstanceManagedExclusivelyCondition indicates no other installation of the provider changes the instance.
+	InstanceManagedExclusivelyCondition = "InstanceManagedExclusively"
+
+	// InstanceRolloutCondition indicates the state of the in-place rollout of the template updates to the instance.
+	InstanceRolloutCondition = "InstanceRollout"
+
+	// InstanceJobsCondition indicates the progress of the jobs of the instance run outside of the reconciliation.
+	InstanceJobsCondition = "InstanceJobs"
+
+	// LifecycleHooksCondition indicates whether the lifecycle hooks of the machine hold its current lifecycle point.
+	LifecycleHooksCondition = "LifecycleHooks"
+)
+
+// ContaboMachine Ready condition reasons, the Ready condition summarizes the instance conditions and is mirrored into
+// the InfrastructureReady condition of the Machine.
+const (
+	// MachineReadyReason indicates the instance is ready and bootstrapped.
+	MachineReadyReason = clusterv1.ReadyReason
+
+	// MachineNotReadyReason indicates a condition of the instance summarized by the Ready condition is False.
+	MachineNotReadyReason = clusterv1.NotReadyReason
+
+	// MachineReadyUnknownReason indicates a condition of the instance summarized by the Ready condition is Unknown or
+	// not reported yet.
+	MachineReadyUnknownReason = clusterv1.ReadyUnknownReason
+
+	// MachineDeletingReason indicates the instance of the machine is being released.
+	MachineDeletingReason = clusterv1.DeletingReason
+)
+
+// ContaboMachine failure reasons, the values of status.failureReason. They are the machine status errors of Cluster
+// API, which the Machine controller copies from the infrastructure machine, while the InstanceReady condition keeps the
+// detailed reason of the failure.
+const (
+	// InvalidConfigurationMachineError indicates the spec of the machine cannot be provisioned, e.g. its product is
+	// no longer sold.
+	InvalidConfigurationMachineError = "InvalidConfiguration"
+
+	// CreateMachineError indicates the instance of the machine could not be created.
+	CreateMachineError = "CreateError"
+
+	// UpdateMachineError indicates the instance of the machine was lost after its creation, e.g. cancelled outside
+	// of Kubernetes.
+	UpdateMachineError = "UpdateError"
 )
 
 // Instance condition reasons.
@@ -151,6 +293,10 @@ const (
 	// InstanceWaitingForBootstrapDataReason indicates waiting for bootstrap data to be ready.
 	InstanceWaitingForBootstrapDataReason = "InstanceWaitingForBootstrapData"
 
+	// InstanceHostnameInvalidReason indicates the hostname pattern of the cluster renders an invalid hostname for the
+	// instance.
+	InstanceHostnameInvalidReason = "InstanceHostnameInvalid"
+
 	// InstanceWaitingForMachineSshKeyReason indicates waiting for machine sshkey to be ready.
 	InstanceWaitingForMachineSshKeyReason = "InstanceWaitingForMachineSshKey"
 
@@ -195,6 +341,193 @@ const (
 
 	// InstanceBootstrapedReason indicates the instance bootstrap process is complete.
 	InstanceBootstrapedReason = "InstanceBootstraped"
+
+	// InstanceFirstBootProbePendingReason indicates the first-boot probe is waiting for sshd and cloud-init.
+	InstanceFirstBootProbePendingReason = "InstanceFirstBootProbePending"
+
+	// InstanceFirstBootProbeSucceededReason indicates the first-boot probe succeeded.
+	InstanceFirstBootProbeSucceededReason = "InstanceFirstBootProbeSucceeded"
+
+	// InstanceFirstBootProbeFailedReason indicates the first-boot probe failed or timed out.
+	InstanceFirstBootProbeFailedReason = "InstanceFirstBootProbeFailed"
+
+	// InstanceMigrationInProgressReason indicates the instance is being migrated to another data center.
+	InstanceMigrationInProgressReason = "InstanceMigrationInProgress"
+
+	// InstanceMigrationWaitingForTargetReason indicates no free instance is available in the target data center yet.
+	InstanceMigrationWaitingForTargetReason = "InstanceMigrationWaitingForTarget"
+
+	// InstanceMigrationCompletedReason indicates the instance was migrated to another data center.
+	InstanceMigrationCompletedReason = "InstanceMigrationCompleted"
+
+	// InstanceMigrationFailedReason indicates the instance cannot be migrated.
+	InstanceMigrationFailedReason = "InstanceMigrationFailed"
+
+	// InstanceOrderPendingReason indicates the ordered instance did not appear or leave provisioning yet.
+	InstanceOrderPendingReason = "InstanceOrderPending"
+
+	// InstanceOrderTimeoutReason indicates the ordered instance did not appear or leave provisioning in time.
+	InstanceOrderTimeoutReason = "InstanceOrderTimeout"
+
+	// InstanceOrderRecreatedReason indicates a replacement instance is ordered after an order timed out.
+	InstanceOrderRecreatedReason = "InstanceOrderRecreated"
+
+	// InstanceOrderFailedReason indicates the instance orders kept timing out and no replacement is ordered anymore.
+	InstanceOrderFailedReason = "InstanceOrderFailed"
+
+	// InstanceCancelledReason indicates the instance was cancelled or removed outside of Kubernetes, e.g. in the
+	// Contabo panel, the machine is failed and replaced.
+	InstanceCancelledReason = "InstanceCancelled"
+
+	// InstanceContractCancelledReason indicates the contract of the instance of a deleted machine was cancelled, as
+	// requested by its Cancel reuse policy.
+	InstanceContractCancelledReason = "InstanceContractCancelled"
+
+	// InstanceContractCancelFailedReason indicates the contract of the instance of a deleted machine could not be
+	// cancelled, the deletion is retried.
+	InstanceContractCancelFailedReason = "InstanceContractCancelFailed"
+
+	// InstanceReturnedToFreePoolReason indicates the instance of a deleted machine was returned to the free pool.
+	InstanceReturnedToFreePoolReason = "InstanceReturnedToFreePool"
+
+	// ParkedInstanceClaimFailedReason indicates the instance parked by the ContaboMachinePool of the machine could not
+	// be claimed, e.g. it was cancelled, the machine uses another reusable or new instance instead.
+	ParkedInstanceClaimFailedReason = "ParkedInstanceClaimFailed"
+
+	// InstanceWaitingForControlPlaneGangReason indicates the first control plane machine waits for the instances of
+	// the other control plane machines of the quorum before it is bootstrapped.
+	InstanceWaitingForControlPlaneGangReason = "WaitingForControlPlaneGang"
+
+	// ControlPlaneGangOrderFailedReason indicates an instance of the control plane quorum could not be ordered.
+	ControlPlaneGangOrderFailedReason = "ControlPlaneGangOrderFailed"
+
+	// ControlPlaneGangTimedOutReason indicates the instances of the control plane quorum did not all exist within the
+	// timeout, the first control plane machine is bootstrapped anyway.
+	ControlPlaneGangTimedOutReason = "ControlPlaneGangTimedOut"
+
+	// InstanceWaitingForOperationSlotReason indicates the instance creation or reinstallation waits for the other
+	// instance operations of the cluster, limited by the MaxConcurrentOperations of the ContaboCluster.
+	InstanceWaitingForOperationSlotReason = "WaitingForOperationSlot"
+
+	// InstanceWaitingForPrivateNetworkReason indicates the private network assignment of the instance waits for the
+	// other assignments of the private network, processed one at a time by Contabo.
+	InstanceWaitingForPrivateNetworkReason = "WaitingForPrivateNetwork"
+
+	// InstanceSnapshotLimitReachedReason indicates the instance holds the maximum number of snapshots
+	// and none can be pruned.
+	InstanceSnapshotLimitReachedReason = "InstanceSnapshotLimitReached"
+
+	// InstanceOutOfStockReason indicates the product is out of stock in the failure domain of the instance order.
+	InstanceOutOfStockReason = "InstanceOutOfStock"
+
+	// InstancePlacementFallbackReason indicates the instance is ordered in another failure domain of the cluster,
+	// the product being out of stock in the previous one.
+	InstancePlacementFallbackReason = "InstancePlacementFallback"
+)
+
+// Instance host condition reasons.
+const (
+	// InstanceHostUnchangedReason indicates the instance runs on the same host system.
+	InstanceHostUnchangedReason = "InstanceHostUnchanged"
+
+	// InstanceHostChangedReason indicates the instance moved to another host system, e.g. a migration by Contabo
+	// after a hardware failure, which often explains reboots or performance changes.
+	InstanceHostChangedReason = "InstanceHostChanged"
+)
+
+// Boot logs event reasons.
+const (
+	// BootLogsCollectedReason indicates the boot logs of the instance were collected into a ConfigMap.
+	BootLogsCollectedReason = "BootLogsCollected"
+
+	// BootLogsCollectionFailedReason indicates the boot logs could not be collected, Contabo exposes no console output
+	// so they are only retrievable while the instance is reachable over SSH.
+	BootLogsCollectionFailedReason = "BootLogsCollectionFailed"
+)
+
+// Refresh event reasons.
+const (
+	// ClusterRefreshRequestedReason indicates a status refresh of all the machines of the cluster was requested.
+	ClusterRefreshRequestedReason = "ClusterRefreshRequested"
+)
+
+// Instance management condition reasons.
+const (
+	// InstanceManagedExclusivelyReason indicates the recent changes of the instance were made by this installation.
+	InstanceManagedExclusivelyReason = "InstanceManagedExclusively"
+
+	// ConcurrentManagerDetectedReason indicates another installation of the provider, with a different leader election
+	// ID or namespace, changed the instance, e.g. after a duplicate install, and both may fight over it.
+	ConcurrentManagerDetectedReason = "ConcurrentManagerDetected"
+)
+
+// Instance mutation event reasons.
+const (
+	// InstanceMutationReason indicates the controller is changing the instance on Contabo, the event lists the fields
+	// changing.
+	InstanceMutationReason = "InstanceMutation"
+)
+
+// Provider ID event reasons.
+const (
+	// ProviderIDRepairedReason indicates the provider ID of the machine or of its node was repaired to match the instance.
+	ProviderIDRepairedReason = "ProviderIDRepaired"
+
+	// ProviderIDMismatchReason indicates the Machine is linked to a node of a previous instance and cannot be repaired
+	// in place.
+	ProviderIDMismatchReason = "ProviderIDMismatch"
+
+	// ProviderIDMachineRecreatedReason indicates the Machine linked to a node of a previous instance was deleted to be
+	// replaced.
+	ProviderIDMachineRecreatedReason = "ProviderIDMachineRecreated"
+)
+
+// In-place rollout condition reasons.
+const (
+	// RolloutInProgressReason indicates the instance is being reinstalled with the updated template.
+	RolloutInProgressReason = "RolloutInProgress"
+
+	// RolloutCompletedReason indicates the instance runs the OS and bootstrap fields of the template.
+	RolloutCompletedReason = "RolloutCompleted"
+
+	// RolloutFailedReason indicates the instance could not be reinstalled with the updated template.
+	RolloutFailedReason = "RolloutFailed"
+
+	// RolloutBlockedReason indicates the updated template cannot be rolled out in place to the machine.
+	RolloutBlockedReason = "RolloutBlocked"
+)
+
+// Job condition reasons.
+const (
+	// JobPendingReason indicates a job waits for a worker of the job queue.
+	JobPendingReason = "JobPending"
+
+	// JobRunningReason indicates a worker of the job queue runs a job.
+	JobRunningReason = "JobRunning"
+
+	// JobsSucceededReason indicates the jobs of the instance completed.
+	JobsSucceededReason = "JobsSucceeded"
+
+	// JobFailedReason indicates the latest job of the instance failed after its last attempt.
+	JobFailedReason = "JobFailed"
+)
+
+// Power state condition reasons.
+const (
+	// PowerStateRunningReason indicates the instance is running as requested.
+	PowerStateRunningReason = "Running"
+
+	// PowerStateStoppedReason indicates the instance is stopped as requested.
+	PowerStateStoppedReason = "Stopped"
+
+	// PowerStateStartingReason indicates the instance is being started.
+	PowerStateStartingReason = "Starting"
+
+	// PowerStateStoppingReason indicates the instance is being shut down.
+	PowerStateStoppingReason = "Stopping"
+
+	// PowerStateBlockedReason indicates the instance cannot be stopped, e.g. it would break the control plane quorum.
+	PowerStateBlockedReason = "PowerStateBlocked"
 )
 
 // Machine private network condition reasons.
@@ -271,9 +604,260 @@ const (
 	// BootstrapDataMergeFailedReason indicates merging bootstrap data failed.
 	BootstrapDataMergeFailedReason = "BootstrapDataMergeFailed"
 
+	// BootstrapDataFormatUnsupportedReason indicates the bootstrap data is in a format other than cloud-config.
+	BootstrapDataFormatUnsupportedReason = "BootstrapDataFormatUnsupported"
+
+	// BootstrapDataUploadFailedReason indicates the bootstrap data could not be uploaded to the object storage.
+	BootstrapDataUploadFailedReason = "BootstrapDataUploadFailed"
+
+	// BootstrapTokenFailedReason indicates the bootstrap token of the instance could not be created in the workload
+	// cluster.
+	BootstrapTokenFailedReason = "BootstrapTokenFailed"
+
+	// BootstrapDataTooLargeReason indicates the bootstrap data is larger than the maximum user data size once
+	// compressed and no object storage is configured.
+	BootstrapDataTooLargeReason = "BootstrapDataTooLarge"
+
 	// ClusterInfrastructureReadyReason indicates the cluster infrastructure is ready.
 	ClusterInfrastructureReadyReason = "ClusterInfrastructureReady"
 
 	// ClusterInfrastructureFailedReason indicates the cluster infrastructure failed.
 	ClusterInfrastructureFailedReason = "ClusterInfrastructureFailed"
 )
+
+// =============================================================================
+// CONTABO QUOTA CONDITIONS
+// =============================================================================
+
+// ContaboQuota condition types.
+const (
+	// QuotaWithinLimitsCondition indicates the resource consumption of the namespace is within the quota limits.
+	QuotaWithinLimitsCondition = "QuotaWithinLimits"
+)
+
+// Quota condition reasons.
+const (
+	// QuotaWithinLimitsReason indicates the resource consumption is within the quota limits.
+	QuotaWithinLimitsReason = "QuotaWithinLimits"
+
+	// QuotaExceededReason indicates the resource consumption reached or exceeded the quota limits.
+	QuotaExceededReason = "QuotaExceeded"
+
+	// InstanceWaitingForQuotaReason indicates the instance cannot be provisioned until quota is available.
+	InstanceWaitingForQuotaReason = "InstanceWaitingForQuota"
+
+	// ClusterPrivateNetworkWaitingForQuotaReason indicates the private network cannot be created until quota is available.
+	ClusterPrivateNetworkWaitingForQuotaReason = "ClusterPrivateNetworkWaitingForQuota"
+)
+
+// =============================================================================
+// CONTABO PROVIDER SETTINGS CONDITIONS
+// =============================================================================
+
+// ContaboProviderSettings condition types.
+const (
+	// ProviderSettingsAppliedCondition indicates the settings are applied by the controllers.
+	ProviderSettingsAppliedCondition = "ProviderSettingsApplied"
+)
+
+// Provider settings condition reasons.
+const (
+	// ProviderSettingsAppliedReason indicates the settings are applied by the controllers.
+	ProviderSettingsAppliedReason = "ProviderSettingsApplied"
+)
+
+// Contabo API call event reasons.
+const (
+	// ContaboAPIHotLoopReason is the warning event of a ContaboCluster or ContaboMachine whose reconciliations sent
+	// more Contabo API requests than the hot loop threshold of the provider settings several times in a row.
+	ContaboAPIHotLoopReason = "ContaboAPIHotLoop"
+)
+
+// =============================================================================
+// CONTABO ACCOUNT INVENTORY CONDITIONS
+// =============================================================================
+
+// ContaboAccountInventory condition types.
+const (
+	// InventoryUpToDateCondition indicates the inventory was refreshed from the Contabo API.
+	InventoryUpToDateCondition = "InventoryUpToDate"
+)
+
+// Account inventory condition reasons.
+const (
+	// InventoryRefreshedReason indicates the inventory was refreshed from the Contabo API.
+	InventoryRefreshedReason = "InventoryRefreshed"
+
+	// InventoryRefreshFailedReason indicates the Contabo API could not be listed, the inventory is stale.
+	InventoryRefreshFailedReason = "InventoryRefreshFailed"
+
+	// InventoryOrphanedResourcesReason indicates new orphaned resources were found in the Contabo account.
+	InventoryOrphanedResourcesReason = "InventoryOrphanedResources"
+)
+
+// =============================================================================
+// CONTABO MACHINE POOL CONDITIONS
+// =============================================================================
+
+// ContaboMachinePool condition types.
+const (
+	// MachinePoolReplicasReadyCondition indicates the replicas of the pool are ready and created from its template.
+	MachinePoolReplicasReadyCondition = "ReplicasReady"
+)
+
+// Machine pool condition reasons.
+const (
+	// MachinePoolReplicasReadyReason indicates the replicas of the pool are ready and up-to-date.
+	MachinePoolReplicasReadyReason = "ReplicasReady"
+
+	// MachinePoolScalingReason indicates machines are created or deleted to match the replicas of the MachinePool.
+	MachinePoolScalingReason = "Scaling"
+
+	// MachinePoolScaledToZeroReason indicates the MachinePool has no replicas, the pool and its parked instances are
+	// kept to scale up again.
+	MachinePoolScaledToZeroReason = "ScaledToZero"
+
+	// MachinePoolRollingUpdateReason indicates the machines of a previous template are being replaced.
+	MachinePoolRollingUpdateReason = "RollingUpdate"
+
+	// MachinePoolWaitingForMachinePoolReason indicates the ContaboMachinePool is not owned by a MachinePool yet.
+	MachinePoolWaitingForMachinePoolReason = "WaitingForMachinePool"
+)
+
+// =============================================================================
+// CONTABO CATALOG CONDITIONS
+// =============================================================================
+
+// ContaboCatalog condition types.
+const (
+	// CatalogUpToDateCondition indicates the catalog was refreshed from the Contabo API.
+	CatalogUpToDateCondition = "CatalogUpToDate"
+)
+
+// Catalog condition reasons.
+const (
+	// CatalogRefreshedReason indicates the catalog was refreshed from the Contabo API.
+	CatalogRefreshedReason = "CatalogRefreshed"
+
+	// CatalogRefreshFailedReason indicates the Contabo API could not be listed, the catalog is stale.
+	CatalogRefreshFailedReason = "CatalogRefreshFailed"
+)
+
+// =============================================================================
+// CONTABO PRODUCT CONDITIONS
+// =============================================================================
+
+// Product availability condition types.
+const (
+	// ProductAvailableCondition indicates the Contabo product of a ContaboMachineTemplate can be ordered.
+	ProductAvailableCondition = "ProductAvailable"
+
+	// ClusterProductsAvailableCondition indicates all Contabo products used by the cluster machines can be ordered.
+	ClusterProductsAvailableCondition = "ProductsAvailable"
+)
+
+// Product availability condition reasons.
+const (
+	// ProductAvailableReason indicates the Contabo product can be ordered.
+	ProductAvailableReason = "ProductAvailable"
+
+	// ProductUnavailableReason indicates the Contabo product is end-of-sale or unavailable.
+	ProductUnavailableReason = "ProductUnavailable"
+)
+
+// =============================================================================
+// CONTABO INSTANCE CREATION BACK-PRESSURE CONDITIONS
+// =============================================================================
+
+// Instance creation back-pressure condition types, set on the ContaboClusters of a Contabo account.
+const (
+	// ClusterInstanceCreationHealthyCondition indicates the instance creations of the Contabo account of the cluster
+	// are not failing at a high rate.
+	ClusterInstanceCreationHealthyCondition = "InstanceCreationHealthy"
+)
+
+// Instance creation back-pressure condition reasons.
+const (
+	// InstanceCreationHealthyReason indicates the instance creations of the Contabo account succeed.
+	InstanceCreationHealthyReason = "InstanceCreationHealthy"
+
+	// InstanceCreationBackPressureReason indicates the instance creations of the Contabo account fail at a high rate,
+	// e.g. during a capacity or billing incident of Contabo, and the new creations are slowed down.
+	InstanceCreationBackPressureReason = "InstanceCreationBackPressure"
+
+	// InstanceCreationRecoveredReason indicates an instance creation succeeded again after the back-pressure.
+	InstanceCreationRecoveredReason = "InstanceCreationRecovered"
+)
+
+// =============================================================================
+// CONTABO PATCH SCHEDULE CONDITIONS
+// =============================================================================
+
+// ContaboPatchSchedule condition types.
+const (
+	// PatchingCondition indicates the state of the patch run of the ContaboPatchSchedule.
+	PatchingCondition = "Patching"
+
+	// InstancePatchCondition indicates the state of the OS patching of a ContaboMachine.
+	InstancePatchCondition = "InstancePatch"
+)
+
+// Patch schedule condition reasons.
+const (
+	// PatchWaitingForWindowReason indicates the maintenance window is closed.
+	PatchWaitingForWindowReason = "WaitingForWindow"
+
+	// PatchInProgressReason indicates machines are being patched.
+	PatchInProgressReason = "PatchInProgress"
+
+	// PatchCompletedReason indicates all the machines were patched during the run.
+	PatchCompletedReason = "PatchCompleted"
+
+	// PatchFailedReason indicates machines failed to be patched.
+	PatchFailedReason = "PatchFailed"
+
+	// PatchSuspendedReason indicates the schedule is suspended.
+	PatchSuspendedReason = "PatchSuspended"
+)
+
+// =============================================================================
+// CONTABO LIFECYCLE HOOK CONDITIONS
+// =============================================================================
+
+// Lifecycle hook condition reasons.
+const (
+	// WaitingForLifecycleHookReason indicates blocking lifecycle hooks or hook annotations hold the lifecycle point.
+	WaitingForLifecycleHookReason = "WaitingForLifecycleHook"
+
+	// LifecycleHooksCompletedReason indicates the lifecycle hooks of the lifecycle point completed.
+	LifecycleHooksCompletedReason = "LifecycleHooksCompleted"
+
+	// LifecycleHookFailedReason indicates the call of a lifecycle hook failed.
+	LifecycleHookFailedReason = "LifecycleHookFailed"
+)
+
+// =============================================================================
+// CONTABO API CREDENTIALS
+// =============================================================================
+
+// Contabo API credentials event reasons.
+const (
+	// ContaboCredentialsFailedReason indicates Contabo API credentials can no longer obtain a token or are rejected.
+	ContaboCredentialsFailedReason = "ContaboCredentialsFailed"
+)
+
+// =============================================================================
+// CONTABO PROVIDER READ-ONLY MODE
+// =============================================================================
+
+// Read-only mode condition types, set on the ContaboClusters and ContaboMachines.
+const (
+	// ReadOnlyCondition indicates the controller runs with --read-only, the Contabo resources are observed only.
+	ReadOnlyCondition = "ReadOnly"
+)
+
+// Read-only mode condition reasons.
+const (
+	// ReadOnlyModeReason indicates the changes to the Contabo resources are suspended by the read-only mode.
+	ReadOnlyModeReason = "ReadOnlyMode"
+)
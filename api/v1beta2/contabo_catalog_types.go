@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+// ContaboRegion is a Contabo region, the values are the CreateInstance regions of the Contabo API
+// +kubebuilder:validation:Enum=EU;US-central;US-east;US-west;SIN;UK;AUS;JPN;IND
+type ContaboRegion string
+
+const (
+	ContaboRegionEU        ContaboRegion = ContaboRegion(EU)
+	ContaboRegionUSCentral ContaboRegion = ContaboRegion(USCentral)
+	ContaboRegionUSEast    ContaboRegion = ContaboRegion(USEast)
+	ContaboRegionUSWest    ContaboRegion = ContaboRegion(USWest)
+	ContaboRegionSIN       ContaboRegion = ContaboRegion(SIN)
+	ContaboRegionUK        ContaboRegion = ContaboRegion(UK)
+	ContaboRegionAUS       ContaboRegion = ContaboRegion(AUS)
+	ContaboRegionJPN       ContaboRegion = ContaboRegion(JPN)
+	ContaboRegionIND       ContaboRegion = ContaboRegion(IND)
+)
+
+// ContaboRegions returns all the Contabo regions
+func ContaboRegions() []ContaboRegion {
+	return []ContaboRegion{
+		ContaboRegionEU,
+		ContaboRegionUSCentral,
+		ContaboRegionUSEast,
+		ContaboRegionUSWest,
+		ContaboRegionSIN,
+		ContaboRegionUK,
+		ContaboRegionAUS,
+		ContaboRegionJPN,
+		ContaboRegionIND,
+	}
+}
+
+// DefaultProductId is the product of the machines created without a product, the default of the Contabo API
+const DefaultProductId = ContaboProductCloudVPS10SSD
+
+// DefaultImageId is the image of the machines created without an image, the Ubuntu 24.04 LTS standard image
+const DefaultImageId = "d64d5c6c-9dda-4e38-8174-0ee282474d8a"
+
+// ContaboProductId is a Contabo instance product ID. The Contabo catalog changes over time, so product IDs are
+// validated by format and the current catalog is listed as constants.
+// +kubebuilder:validation:Pattern=`^V[0-9]+$`
+type ContaboProductId string
+
+// Cloud VPS products
+const (
+	ContaboProductCloudVPS10NVMe    ContaboProductId = "V91"
+	ContaboProductCloudVPS10SSD     ContaboProductId = "V92"
+	ContaboProductCloudVPS10Storage ContaboProductId = "V93"
+	ContaboProductCloudVPS20NVMe    ContaboProductId = "V94"
+	ContaboProductCloudVPS20SSD     ContaboProductId = "V95"
+	ContaboProductCloudVPS20Storage ContaboProductId = "V96"
+	ContaboProductCloudVPS30NVMe    ContaboProductId = "V97"
+	ContaboProductCloudVPS30SSD     ContaboProductId = "V98"
+	ContaboProductCloudVPS30Storage ContaboProductId = "V99"
+	ContaboProductCloudVPS40NVMe    ContaboProductId = "V100"
+	ContaboProductCloudVPS40SSD     ContaboProductId = "V101"
+	ContaboProductCloudVPS40Storage ContaboProductId = "V102"
+	ContaboProductCloudVPS50NVMe    ContaboProductId = "V103"
+	ContaboProductCloudVPS50SSD     ContaboProductId = "V104"
+	ContaboProductCloudVPS50Storage ContaboProductId = "V105"
+)
+
+// Cloud VDS products
+const (
+	ContaboProductCloudVDSS   ContaboProductId = "V8"
+	ContaboProductCloudVDSM   ContaboProductId = "V9"
+	ContaboProductCloudVDSL   ContaboProductId = "V10"
+	ContaboProductCloudVDSXL  ContaboProductId = "V11"
+	ContaboProductCloudVDSXXL ContaboProductId = "V16"
+)
+
+// ContaboProducts returns the Contabo products of the current catalog
+func ContaboProducts() []ContaboProductId {
+	return []ContaboProductId{
+		ContaboProductCloudVPS10NVMe,
+		ContaboProductCloudVPS10SSD,
+		ContaboProductCloudVPS10Storage,
+		ContaboProductCloudVPS20NVMe,
+		ContaboProductCloudVPS20SSD,
+		ContaboProductCloudVPS20Storage,
+		ContaboProductCloudVPS30NVMe,
+		ContaboProductCloudVPS30SSD,
+		ContaboProductCloudVPS30Storage,
+		ContaboProductCloudVPS40NVMe,
+		ContaboProductCloudVPS40SSD,
+		ContaboProductCloudVPS40Storage,
+		ContaboProductCloudVPS50NVMe,
+		ContaboProductCloudVPS50SSD,
+		ContaboProductCloudVPS50Storage,
+		ContaboProductCloudVDSS,
+		ContaboProductCloudVDSM,
+		ContaboProductCloudVDSL,
+		ContaboProductCloudVDSXL,
+		ContaboProductCloudVDSXXL,
+	}
+}
+
+// ContaboDeprecatedProducts returns the products of the previous Cloud VPS generation, still orderable but replaced by
+// the Cloud VPS products of the current catalog
+func ContaboDeprecatedProducts() []ContaboProductId {
+	return []ContaboProductId{"V45", "V46", "V47", "V48", "V49", "V50"}
+}
+
+// ContaboProductDiskGb returns the disk size in GB of a product of the current catalog, 0 for products missing from
+// the catalog
+func ContaboProductDiskGb(productId ContaboProductId) int32 {
+	switch productId {
+	case ContaboProductCloudVPS10NVMe:
+		return 75
+	case ContaboProductCloudVPS10SSD:
+		return 150
+	case ContaboProductCloudVPS10Storage:
+		return 300
+	case ContaboProductCloudVPS20NVMe:
+		return 100
+	case ContaboProductCloudVPS20SSD:
+		return 200
+	case ContaboProductCloudVPS20Storage:
+		return 400
+	case ContaboProductCloudVPS30NVMe:
+		return 200
+	case ContaboProductCloudVPS30SSD:
+		return 400
+	case ContaboProductCloudVPS30Storage:
+		return 800
+	case ContaboProductCloudVPS40NVMe:
+		return 250
+	case ContaboProductCloudVPS40SSD:
+		return 500
+	case ContaboProductCloudVPS40Storage:
+		return 1000
+	case ContaboProductCloudVPS50NVMe:
+		return 300
+	case ContaboProductCloudVPS50SSD:
+		return 600
+	case ContaboProductCloudVPS50Storage:
+		return 1200
+	case ContaboProductCloudVDSS:
+		return 180
+	case ContaboProductCloudVDSM:
+		return 240
+	case ContaboProductCloudVDSL:
+		return 360
+	case ContaboProductCloudVDSXL:
+		return 480
+	case ContaboProductCloudVDSXXL:
+		return 720
+	}
+	return 0
+}
+
+// ContaboProductCpuCores returns the number of vCPU cores of a product of the current catalog, the dedicated cores of
+// a Cloud VDS. It returns 0 for products missing from the catalog.
+func ContaboProductCpuCores(productId ContaboProductId) int32 {
+	switch productId {
+	case ContaboProductCloudVPS10NVMe, ContaboProductCloudVPS10SSD, ContaboProductCloudVPS10Storage:
+		return 4
+	case ContaboProductCloudVPS20NVMe, ContaboProductCloudVPS20SSD, ContaboProductCloudVPS20Storage:
+		return 6
+	case ContaboProductCloudVPS30NVMe, ContaboProductCloudVPS30SSD, ContaboProductCloudVPS30Storage:
+		return 8
+	case ContaboProductCloudVPS40NVMe, ContaboProductCloudVPS40SSD, ContaboProductCloudVPS40Storage:
+		return 12
+	case ContaboProductCloudVPS50NVMe, ContaboProductCloudVPS50SSD, ContaboProductCloudVPS50Storage:
+		return 16
+	case ContaboProductCloudVDSS:
+		return 3
+	case ContaboProductCloudVDSM:
+		return 4
+	case ContaboProductCloudVDSL:
+		return 6
+	case ContaboProductCloudVDSXL:
+		return 8
+	case ContaboProductCloudVDSXXL:
+		return 12
+	}
+	return 0
+}
+
+// ContaboProductRamGb returns the memory size in GB of a product of the current catalog, 0 for products missing from
+// the catalog
+func ContaboProductRamGb(productId ContaboProductId) int32 {
+	switch productId {
+	case ContaboProductCloudVPS10NVMe, ContaboProductCloudVPS10SSD, ContaboProductCloudVPS10Storage:
+		return 8
+	case ContaboProductCloudVPS20NVMe, ContaboProductCloudVPS20SSD, ContaboProductCloudVPS20Storage:
+		return 12
+	case ContaboProductCloudVPS30NVMe, ContaboProductCloudVPS30SSD, ContaboProductCloudVPS30Storage:
+		return 24
+	case ContaboProductCloudVPS40NVMe, ContaboProductCloudVPS40SSD, ContaboProductCloudVPS40Storage:
+		return 48
+	case ContaboProductCloudVPS50NVMe, ContaboProductCloudVPS50SSD, ContaboProductCloudVPS50Storage:
+		return 64
+	case ContaboProductCloudVDSS:
+		return 24
+	case ContaboProductCloudVDSM:
+		return 32
+	case ContaboProductCloudVDSL:
+		return 48
+	case ContaboProductCloudVDSXL:
+		return 64
+	case ContaboProductCloudVDSXXL:
+		return 96
+	}
+	return 0
+}
+
+// ContaboProductPriceClass returns the tariff of a product of the current catalog, the storage variants of a Cloud
+// VPS share the same price. It returns an empty string for products missing from the catalog.
+func ContaboProductPriceClass(productId ContaboProductId) string {
+	switch productId {
+	case ContaboProductCloudVPS10NVMe, ContaboProductCloudVPS10SSD, ContaboProductCloudVPS10Storage:
+		return "Cloud VPS 10"
+	case ContaboProductCloudVPS20NVMe, ContaboProductCloudVPS20SSD, ContaboProductCloudVPS20Storage:
+		return "Cloud VPS 20"
+	case ContaboProductCloudVPS30NVMe, ContaboProductCloudVPS30SSD, ContaboProductCloudVPS30Storage:
+		return "Cloud VPS 30"
+	case ContaboProductCloudVPS40NVMe, ContaboProductCloudVPS40SSD, ContaboProductCloudVPS40Storage:
+		return "Cloud VPS 40"
+	case ContaboProductCloudVPS50NVMe, ContaboProductCloudVPS50SSD, ContaboProductCloudVPS50Storage:
+		return "Cloud VPS 50"
+	case ContaboProductCloudVDSS:
+		return "Cloud VDS S"
+	case ContaboProductCloudVDSM:
+		return "Cloud VDS M"
+	case ContaboProductCloudVDSL:
+		return "Cloud VDS L"
+	case ContaboProductCloudVDSXL:
+		return "Cloud VDS XL"
+	case ContaboProductCloudVDSXXL:
+		return "Cloud VDS XXL"
+	}
+	return ""
+}
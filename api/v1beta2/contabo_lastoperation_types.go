@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContaboLastOperationType is the stage of the lifecycle of a resource the controller works on
+// +kubebuilder:validation:Enum=Create;Reconcile;Delete
+type ContaboLastOperationType string
+
+const (
+	// ContaboLastOperationTypeCreate is the provisioning of the resource, until it is provisioned for the first time
+	ContaboLastOperationTypeCreate ContaboLastOperationType = "Create"
+
+	// ContaboLastOperationTypeReconcile keeps a provisioned resource in line with its spec
+	ContaboLastOperationTypeReconcile ContaboLastOperationType = "Reconcile"
+
+	// ContaboLastOperationTypeDelete releases the Contabo resources of a deleted resource
+	ContaboLastOperationTypeDelete ContaboLastOperationType = "Delete"
+)
+
+// ContaboLastOperationState is the state of the last operation of the controller
+// +kubebuilder:validation:Enum=Processing;Succeeded;Error;Failed
+type ContaboLastOperationState string
+
+const (
+	// ContaboLastOperationStateProcessing is an operation in progress, waiting for Contabo or for another resource
+	ContaboLastOperationStateProcessing ContaboLastOperationState = "Processing"
+
+	// ContaboLastOperationStateSucceeded is an operation which completed, the resource is ready
+	ContaboLastOperationStateSucceeded ContaboLastOperationState = "Succeeded"
+
+	// ContaboLastOperationStateError is an operation whose last step failed, it is retried
+	ContaboLastOperationStateError ContaboLastOperationState = "Error"
+
+	// ContaboLastOperationStateFailed is an operation which failed terminally, it is not retried
+	ContaboLastOperationStateFailed ContaboLastOperationState = "Failed"
+)
+
+// ContaboLastOperation is the last operation of the controller on a resource and the reason of its last decision, so
+// that the progress of the resource can be followed without reading its conditions and the controller logs
+type ContaboLastOperation struct {
+	// Type is the stage of the lifecycle of the resource the operation is for
+	Type ContaboLastOperationType `json:"type"`
+
+	// State is the state of the operation
+	State ContaboLastOperationState `json:"state"`
+
+	// Reason is the reason of the last decision of the controller, the reason of the condition which decided it
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the human readable detail of the reason, or the error of the last step
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartedAt is the time the operation started
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// FinishedAt is the time the operation succeeded or failed, unset while it is in progress
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// RequestID is the x-request-id of the last Contabo API request of the operation changing Contabo resources, to
+	// find it in the Contabo audits or to quote it to the Contabo support
+	// +optional
+	RequestID string `json:"requestId,omitempty"`
+}
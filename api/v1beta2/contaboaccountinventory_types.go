@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContaboAccountInventoryName is the name of the ContaboAccountInventory maintained by the controller.
+const ContaboAccountInventoryName = "default"
+
+// ContaboInventoryManagement is the management status of a Contabo resource
+// +kubebuilder:validation:Enum=Managed;Unmanaged;Orphan
+type ContaboInventoryManagement string
+
+const (
+	// ContaboInventoryManaged is a resource used by a ContaboCluster or a ContaboMachine
+	ContaboInventoryManaged ContaboInventoryManagement = "Managed"
+
+	// ContaboInventoryUnmanaged is a resource not created by the provider, including instances available for reuse
+	ContaboInventoryUnmanaged ContaboInventoryManagement = "Unmanaged"
+
+	// ContaboInventoryOrphan is a resource named by the provider but no longer used by any ContaboCluster or ContaboMachine
+	ContaboInventoryOrphan ContaboInventoryManagement = "Orphan"
+)
+
+// ContaboAccountInventorySpec is empty, the ContaboAccountInventory is read-only and maintained by the controller.
+type ContaboAccountInventorySpec struct{}
+
+// ContaboInventoryItem is a Contabo resource of the account
+type ContaboInventoryItem struct {
+	// Id is the identifier of the resource in the Contabo API
+	Id string `json:"id"`
+
+	// Name is the name, or display name, of the resource
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Region is the region of the resource
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Status is the Contabo status of the resource
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// Management is the management status of the resource
+	Management ContaboInventoryManagement `json:"management"`
+
+	// Owner is the ContaboCluster or ContaboMachine (namespace/name) using a managed resource
+	// +optional
+	Owner string `json:"owner,omitempty"`
+}
+
+// ContaboInventoryCount counts the resources of a kind per management status
+type ContaboInventoryCount struct {
+	// Total is the number of resources
+	Total int32 `json:"total"`
+
+	// Managed is the number of resources used by a ContaboCluster or a ContaboMachine
+	Managed int32 `json:"managed"`
+
+	// Unmanaged is the number of resources not created by the provider
+	Unmanaged int32 `json:"unmanaged"`
+
+	// Orphan is the number of resources named by the provider but no longer used
+	Orphan int32 `json:"orphan"`
+}
+
+// ContaboInventorySummary counts the resources of the account per kind
+type ContaboInventorySummary struct {
+	// Instances counts the compute instances
+	Instances ContaboInventoryCount `json:"instances"`
+
+	// PrivateNetworks counts the private networks
+	PrivateNetworks ContaboInventoryCount `json:"privateNetworks"`
+
+	// Images counts the custom images
+	Images ContaboInventoryCount `json:"images"`
+
+	// VIPs counts the virtual IPs
+	VIPs ContaboInventoryCount `json:"vips"`
+}
+
+// ContaboAccountInventoryStatus defines the observed state of ContaboAccountInventory.
+type ContaboAccountInventoryStatus struct {
+	// LastUpdated is the last time the inventory was refreshed from the Contabo API
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Summary counts the resources of the account per kind and management status
+	// +optional
+	Summary ContaboInventorySummary `json:"summary,omitempty"`
+
+	// Instances are the compute instances of the account
+	// +optional
+	Instances []ContaboInventoryItem `json:"instances,omitempty"`
+
+	// PrivateNetworks are the private networks of the account
+	// +optional
+	PrivateNetworks []ContaboInventoryItem `json:"privateNetworks,omitempty"`
+
+	// Images are the custom images of the account, standard images are not listed
+	// +optional
+	Images []ContaboInventoryItem `json:"images,omitempty"`
+
+	// VIPs are the virtual IPs of the account
+	// +optional
+	VIPs []ContaboInventoryItem `json:"vips,omitempty"`
+
+	// Conditions defines current service state of the ContaboAccountInventory.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Instances",type="integer",JSONPath=".status.summary.instances.total",description="Instances of the account"
+// +kubebuilder:printcolumn:name="Managed",type="integer",JSONPath=".status.summary.instances.managed",description="Instances used by ContaboMachines"
+// +kubebuilder:printcolumn:name="Orphan",type="integer",JSONPath=".status.summary.instances.orphan",description="Instances named by the provider but no longer used"
+// +kubebuilder:printcolumn:name="Networks",type="integer",JSONPath=".status.summary.privateNetworks.total",description="Private networks of the account"
+// +kubebuilder:printcolumn:name="Updated",type="date",JSONPath=".status.lastUpdated"
+// +kubebuilder:resource:path=contaboaccountinventories,scope=Cluster,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="ContaboAccountInventory is maintained by the controller and must be named 'default'"
+
+// ContaboAccountInventory is the Schema for the contaboaccountinventories API
+type ContaboAccountInventory struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec is empty, the ContaboAccountInventory is read-only
+	// +optional
+	Spec ContaboAccountInventorySpec `json:"spec,omitempty"`
+
+	// status defines the observed state of ContaboAccountInventory
+	// +optional
+	Status ContaboAccountInventoryStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContaboAccountInventoryList contains a list of ContaboAccountInventory
+type ContaboAccountInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContaboAccountInventory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContaboAccountInventory{}, &ContaboAccountInventoryList{})
+}
+
+// GetConditions returns the conditions of the ContaboAccountInventory.
+func (i *ContaboAccountInventory) GetConditions() []metav1.Condition {
+	return i.Status.Conditions
+}
+
+// SetConditions sets the conditions of the ContaboAccountInventory.
+func (i *ContaboAccountInventory) SetConditions(conditions []metav1.Condition) {
+	i.Status.Conditions = conditions
+}
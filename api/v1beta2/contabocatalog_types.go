@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContaboCatalogName is the name of the ContaboCatalog maintained by the controller.
+const ContaboCatalogName = "default"
+
+// ContaboCatalogSpec is empty, the ContaboCatalog is read-only and maintained by the controller.
+type ContaboCatalogSpec struct{}
+
+// ContaboCatalogDataCenter is a Contabo data center
+type ContaboCatalogDataCenter struct {
+	// Slug is the identifier of the data center, e.g. EU1
+	Slug string `json:"slug"`
+
+	// Name is the name of the data center
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Region is the region of the data center, as set in the region of a ContaboMachine
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// RegionName is the name of the region of the data center
+	// +optional
+	RegionName string `json:"regionName,omitempty"`
+
+	// Capabilities are the Contabo products available in the data center, e.g. VPS or ObjectStorage
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// ContaboCatalogImage is a Contabo standard image
+type ContaboCatalogImage struct {
+	// ImageId is the identifier of the image, as set in the image of a ContaboMachine
+	ImageId string `json:"imageId"`
+
+	// Name is the name of the image, e.g. ubuntu-24.04
+	Name string `json:"name"`
+
+	// Description is the description of the image
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// OsType is the type of the operating system of the image, e.g. Linux
+	// +optional
+	OsType string `json:"osType,omitempty"`
+}
+
+// ContaboCatalogProduct is a Contabo instance product with its specifications
+type ContaboCatalogProduct struct {
+	// ProductId is the identifier of the product, as set in the product of a ContaboMachine
+	ProductId ContaboProductId `json:"productId"`
+
+	// PriceClass is the tariff of the product, e.g. Cloud VPS 10
+	PriceClass string `json:"priceClass"`
+
+	// CpuCores is the number of vCPU cores of the product
+	CpuCores int32 `json:"cpuCores"`
+
+	// RamGb is the memory size of the product in GB
+	RamGb int32 `json:"ramGb"`
+
+	// DiskGb is the disk size of the product in GB
+	DiskGb int32 `json:"diskGb"`
+}
+
+// ContaboCatalogStatus defines the observed state of ContaboCatalog.
+type ContaboCatalogStatus struct {
+	// LastUpdated is the last time the catalog was refreshed from the Contabo API
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// DataCenters are the Contabo data centers
+	// +optional
+	DataCenters []ContaboCatalogDataCenter `json:"dataCenters,omitempty"`
+
+	// Regions are the regions instances can be ordered in
+	// +optional
+	Regions []ContaboRegion `json:"regions,omitempty"`
+
+	// Images are the standard images of Contabo, custom images are listed by the ContaboAccountInventory
+	// +optional
+	Images []ContaboCatalogImage `json:"images,omitempty"`
+
+	// Products are the instance products of the current catalog
+	// +optional
+	Products []ContaboCatalogProduct `json:"products,omitempty"`
+
+	// Conditions defines current service state of the ContaboCatalog.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Updated",type="date",JSONPath=".status.lastUpdated"
+// +kubebuilder:resource:path=contabocatalogs,scope=Cluster,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="ContaboCatalog is maintained by the controller and must be named 'default'"
+
+// ContaboCatalog is the Schema for the contabocatalogs API
+type ContaboCatalog struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec is empty, the ContaboCatalog is read-only
+	// +optional
+	Spec ContaboCatalogSpec `json:"spec,omitempty"`
+
+	// status defines the observed state of ContaboCatalog
+	// +optional
+	Status ContaboCatalogStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContaboCatalogList contains a list of ContaboCatalog
+type ContaboCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContaboCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContaboCatalog{}, &ContaboCatalogList{})
+}
+
+// GetConditions returns the conditions of the ContaboCatalog.
+func (c *ContaboCatalog) GetConditions() []metav1.Condition {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions of the ContaboCatalog.
+func (c *ContaboCatalog) SetConditions(conditions []metav1.Condition) {
+	c.Status.Conditions = conditions
+}
@@ -25,8 +25,12 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // ContaboClusterSpec defines the desired state of ContaboCluster
+// +kubebuilder:validation:XValidation:rule="has(self.credentialsRef) == has(oldSelf.credentialsRef)",message="credentialsRef cannot be added or removed, the cluster would move to another Contabo account"
 type ContaboClusterSpec struct {
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
+	// The port is also the API server bind and advertise port rendered into the control plane bootstrap data,
+	// defaults to 6443.
+	// +kubebuilder:validation:XValidation:rule="!has(self.port) || (self.port >= 0 && self.port <= 65535)",message="port must be between 1 and 65535, or 0 to use the default"
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
 
@@ -36,6 +40,214 @@ type ContaboClusterSpec struct {
 	// ClusterUUID is the identifier of the Contabo cluster.
 	// +optional
 	ClusterUUID string `json:"clusterUUID,omitempty"`
+
+	// MaxConcurrentOperations is the maximum number of instance creations and reinstallations running at once for
+	// the machines of the cluster, smoothing the Contabo API load and the workload disruption of large rollouts.
+	// An operation runs from the request to the end of the bootstrap. Unlimited when not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentOperations *int32 `json:"maxConcurrentOperations,omitempty"`
+
+	// Placement configures the failure domains of the machines and where instances are ordered when a product is
+	// out of stock.
+	// +optional
+	Placement *ContaboPlacementSpec `json:"placement,omitempty"`
+
+	// PartialAdoption restricts the provider to the instances carrying a Contabo tag, for the gradual migration of
+	// existing environments: the other instances, including the ones of the private network, are strictly ignored.
+	// +optional
+	PartialAdoption *ContaboPartialAdoptionSpec `json:"partialAdoption,omitempty"`
+
+	// RolloutStrategy is how the updates of the ContaboMachineTemplates of the cluster are rolled out to their
+	// machines, unless set on the template. Replace leaves the rollouts to Cluster API, which replaces the machines
+	// when they reference a new template. ReinstallInPlace reinstalls the instances of the worker machines when the
+	// OS and bootstrap fields of their template are updated in place, keeping the prepaid instances.
+	// +kubebuilder:default=Replace
+	// +optional
+	RolloutStrategy ContaboRolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// ProviderIDRepair is how a machine is repaired when its node still reports the provider ID of a previous
+	// instance, e.g. after a migration or a manual intervention. The nodes are always repaired in place when
+	// possible. RecreateMachine additionally deletes the worker Machines whose node reference cannot be repaired, so
+	// that their MachineSet replaces them. Default is RepairNode.
+	// +kubebuilder:default=RepairNode
+	// +optional
+	ProviderIDRepair ContaboProviderIDRepairPolicy `json:"providerIDRepair,omitempty"`
+
+	// HostnamePattern is the OS hostname set on the instances when they are bootstrapped, which is also the name of
+	// their node, as kubeadm identifies the node by the hostname. The placeholders {instanceName} (the Contabo
+	// instance name, e.g. vmi123456), {instanceId}, {cluster}, {machine}, {role} (control-plane, the
+	// MachineDeployment or worker) and {index} are replaced, and the result must be an RFC 1123 label. Changing the
+	// pattern only names the nodes of the instances bootstrapped afterwards. Default is {instanceName}.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^([a-z0-9-]|\{(instanceName|instanceId|cluster|machine|role|index)\})+$`
+	// +kubebuilder:validation:XValidation:rule="['{instanceName}', '{instanceId}', '{machine}', '{index}'].exists(p, self.contains(p))",message="hostnamePattern must contain {instanceName}, {instanceId}, {machine} or {index} for the node names to be unique"
+	// +kubebuilder:default="{instanceName}"
+	// +optional
+	HostnamePattern string `json:"hostnamePattern,omitempty"`
+
+	// EtcdBackup uploads periodic etcd snapshots of the control plane to a Contabo object storage bucket for disaster
+	// recovery. The kubeadm control plane machines bootstrapped afterwards take and upload the snapshots, the
+	// controller creates the bucket, hands its credentials to the workload cluster and deletes the snapshots beyond
+	// the retention. The snapshots are kept when the cluster is deleted.
+	// +optional
+	EtcdBackup *ContaboEtcdBackupSpec `json:"etcdBackup,omitempty"`
+
+	// ControlPlaneGang orders the instances of the whole control plane quorum at once when the cluster is created,
+	// while Cluster API creates the control plane machines one at a time, and only bootstraps the first control plane
+	// machine once all of them exist. A Contabo capacity shortage then delays the cluster before it is initialized
+	// instead of leaving a control plane without quorum. The next control plane machines adopt the ordered instances.
+	// +optional
+	ControlPlaneGang *ContaboControlPlaneGangSpec `json:"controlPlaneGang,omitempty"`
+
+	// DeletionConfirmationThreshold is the number of Contabo resources the deletion of the cluster may destroy
+	// without confirmation. When the deletion preview lists more, the deletion of the cluster and of its machines waits
+	// for the ConfirmDeletionAnnotation set to the cluster UUID. The deletions are never held when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DeletionConfirmationThreshold *int32 `json:"deletionConfirmationThreshold,omitempty"`
+
+	// CredentialsRef references the Secret holding the Contabo API credentials of the cluster, which is then managed
+	// in that Contabo account instead of the account of the controller. It is immutable, the Contabo resources of the
+	// cluster cannot move to another account.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="credentialsRef is immutable"
+	// +optional
+	CredentialsRef *ContaboCredentialsReference `json:"credentialsRef,omitempty"`
+}
+
+// ContaboCredentialsReference references the Secret holding Contabo API credentials
+type ContaboCredentialsReference struct {
+	// Name is the name of the Secret, in the namespace of the ContaboCluster, holding the OAuth2 client of the
+	// Contabo API in the clientId and clientSecret keys and the API user in the apiUser and apiPassword keys.
+	// The rotated credentials are used from the next reconciliation.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// ContaboControlPlaneGangSpec defines the gang provisioning of the control plane instances of a new cluster
+type ContaboControlPlaneGangSpec struct {
+	// Replicas is the number of control plane machines of the quorum, the replicas of the control plane. Default is 3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Timeout is how long the first control plane machine waits for the instances of the quorum, it is bootstrapped
+	// afterwards and the next control plane machines order the missing instances. Default is 30m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ContaboEtcdBackupSpec defines the etcd snapshots of the control plane uploaded to object storage
+type ContaboEtcdBackupSpec struct {
+	// Schedule is the cron schedule of the snapshots, in the UTC time zone of the instances. Only the etcd leader
+	// uploads a snapshot. Changing the schedule applies to the control plane machines bootstrapped afterwards.
+	// Default is every 6 hours.
+	// +kubebuilder:validation:Pattern=`^(\S+\s+){4}\S+$`
+	// +kubebuilder:default="0 */6 * * *"
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Retention is the number of snapshots kept in the bucket, the oldest ones are deleted. Default is 28.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default=28
+	// +optional
+	Retention int32 `json:"retention,omitempty"`
+
+	// ObjectStorage is the bucket the snapshots are uploaded to
+	// +kubebuilder:validation:Required
+	ObjectStorage ContaboEtcdBackupObjectStorage `json:"objectStorage"`
+}
+
+// ContaboEtcdBackupObjectStorage defines the object storage bucket of the etcd snapshots
+type ContaboEtcdBackupObjectStorage struct {
+	// Endpoint is the URL of the S3 compatible object storage, e.g. https://eu2.contabostorage.com.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+
+	// Region is the region used to sign the object storage requests. Default is us-east-1.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the bucket the snapshots are uploaded to, addressed path-style. It is created when missing and
+	// should not be public. The snapshots of the cluster are prefixed with its cluster UUID, the bucket can be shared.
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Required
+	Bucket string `json:"bucket"`
+
+	// CredentialsSecretName is the name of the Secret, in the namespace of the ContaboCluster, holding the S3
+	// credentials of the bucket in the accessKey and secretKey keys. They are copied to the workload cluster for the
+	// control plane machines to upload the snapshots.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	CredentialsSecretName string `json:"credentialsSecretName"`
+}
+
+// ContaboProviderIDRepairPolicy is how a machine whose node reports another provider ID is repaired
+// +kubebuilder:validation:Enum=RepairNode;RecreateMachine
+type ContaboProviderIDRepairPolicy string
+
+const (
+	// ContaboProviderIDRepairNode repairs the nodes in place and only reports the Machines it cannot repair
+	ContaboProviderIDRepairNode ContaboProviderIDRepairPolicy = "RepairNode"
+	// ContaboProviderIDRepairRecreateMachine deletes the worker Machines whose node reference cannot be repaired
+	ContaboProviderIDRepairRecreateMachine ContaboProviderIDRepairPolicy = "RecreateMachine"
+)
+
+// ContaboRolloutStrategy is how the updates of a ContaboMachineTemplate are rolled out to its machines
+// +kubebuilder:validation:Enum=Replace;ReinstallInPlace
+type ContaboRolloutStrategy string
+
+const (
+	// ContaboRolloutStrategyReplace leaves the rollouts to Cluster API, the machines are replaced by new ones
+	ContaboRolloutStrategyReplace ContaboRolloutStrategy = "Replace"
+	// ContaboRolloutStrategyReinstallInPlace reinstalls the instances of the worker machines one at a time
+	ContaboRolloutStrategyReinstallInPlace ContaboRolloutStrategy = "ReinstallInPlace"
+)
+
+// ContaboPartialAdoptionSpec defines the instances managed by the provider in a partially adopted cluster
+type ContaboPartialAdoptionSpec struct {
+	// ProviderTag is the Contabo tag of the instances managed by the provider. It is assigned to the instances of the
+	// machines, an existing instance is adopted by assigning it the tag before a machine claims it. Instances without
+	// the tag are never claimed, reset, removed from the private network or restarted.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	ProviderTag string `json:"providerTag"`
+}
+
+// ContaboPlacementFallbackPolicy is what happens when a product is out of stock in the failure domain of a machine
+// +kubebuilder:validation:Enum=None;NextFailureDomain
+type ContaboPlacementFallbackPolicy string
+
+const (
+	// ContaboPlacementFallbackPolicyNone keeps ordering the instance in the failure domain of the machine until the
+	// product is back in stock
+	ContaboPlacementFallbackPolicyNone ContaboPlacementFallbackPolicy = "None"
+
+	// ContaboPlacementFallbackPolicyNextFailureDomain orders the instance in the next failure domain of the cluster
+	ContaboPlacementFallbackPolicyNextFailureDomain ContaboPlacementFallbackPolicy = "NextFailureDomain"
+)
+
+// ContaboPlacementSpec defines the failure domains of the cluster machines
+type ContaboPlacementSpec struct {
+	// FailureDomains are the Contabo regions instances are ordered in, in order of preference. They are reported as
+	// the failure domains of the cluster so Cluster API spreads the machines across them. Only the private network
+	// region is used when empty. Contabo may refuse to assign instances of another region to the private network.
+	// +kubebuilder:validation:MaxItems=9
+	// +listType=set
+	// +optional
+	FailureDomains []ContaboRegion `json:"failureDomains,omitempty"`
+
+	// FallbackPolicy is what happens when the product of a machine is out of stock in its failure domain: None
+	// waits for the product to be back in stock, NextFailureDomain orders the instance in the next failure domain,
+	// in the order of the list. Default is None.
+	// +kubebuilder:default=None
+	// +optional
+	FallbackPolicy ContaboPlacementFallbackPolicy `json:"fallbackPolicy,omitempty"`
 }
 
 // ContaboClusterStatus defines the observed state of ContaboCluster.
@@ -48,10 +260,38 @@ type ContaboClusterStatus struct {
 	// +optional
 	PrivateNetwork *ContaboPrivateNetworkStatus `json:"privateNetwork,omitempty"`
 
+	// PrivateNetworkSharedWith lists the other ContaboClusters (namespace/name) referencing the same private network.
+	// The private network is only deleted with the last referencing ContaboCluster.
+	// +optional
+	PrivateNetworkSharedWith []string `json:"privateNetworkSharedWith,omitempty"`
+
+	// PrivateNetworkHints contains the MTU and routing details of the private network.
+	// +optional
+	PrivateNetworkHints *ContaboPrivateNetworkHintsStatus `json:"privateNetworkHints,omitempty"`
+
+	// UnavailableProducts lists the Contabo products used by the cluster machines that are end-of-sale or unavailable.
+	// +optional
+	UnavailableProducts []string `json:"unavailableProducts,omitempty"`
+
+	// UnavailableProductsLastObserved is the last time an instance order confirmed that a product of
+	// UnavailableProducts is unavailable. Admission only rejects these products while the observation is recent,
+	// they may be back in stock since.
+	// +optional
+	UnavailableProductsLastObserved *metav1.Time `json:"unavailableProductsLastObserved,omitempty"`
+
 	// SshKey contains the references to secrets used by the machine.
 	// +optional
 	SshKey *ContaboSshKeyStatus `json:"secrets,omitempty"`
 
+	// Kubeconfig contains the kubeconfig Secrets of the public and private network endpoints of the control plane.
+	// +optional
+	Kubeconfig *ContaboKubeconfigStatus `json:"kubeconfig,omitempty"`
+
+	// ControlPlaneEndpointVIP is the Contabo VIP published as the control plane endpoint when
+	// spec.controlPlaneEndpoint.host is empty, and the control plane instance holding it.
+	// +optional
+	ControlPlaneEndpointVIP *ContaboControlPlaneEndpointVIPStatus `json:"controlPlaneEndpointVIP,omitempty"`
+
 	// Initialization
 	Initialization *ContaboClusterInitializationStatus `json:"initialization,omitempty"`
 
@@ -62,16 +302,219 @@ type ContaboClusterStatus struct {
 	// FailureDomains is a list of failure domains that machines can be placed in.
 	// +optional
 	FailureDomains []clusterv1.FailureDomain `json:"failureDomains,omitempty"`
+
+	// Refresh is the last status refresh of the cluster machines requested with the RefreshAnnotation
+	// +optional
+	Refresh *ContaboClusterRefreshStatus `json:"refresh,omitempty"`
+
+	// Machines aggregates the conditions of the machines of the cluster, e.g. 9/10 machines Ready
+	// +optional
+	Machines *ContaboClusterMachinesStatus `json:"machines,omitempty"`
+
+	// EtcdBackup contains the etcd snapshots of the control plane found in object storage
+	// +optional
+	EtcdBackup *ContaboEtcdBackupStatus `json:"etcdBackup,omitempty"`
+
+	// DeletionPreview lists the Contabo resources the deletion of the cluster destroys, computed when the deletion
+	// of the ContaboCluster or of its Cluster is received
+	// +optional
+	DeletionPreview *ContaboClusterDeletionPreviewStatus `json:"deletionPreview,omitempty"`
+
+	// LastOperation is the last operation of the controller on the cluster and the reason of its last decision
+	// +optional
+	LastOperation *ContaboLastOperation `json:"lastOperation,omitempty"`
+}
+
+// ConfirmDeletionAnnotation confirms the deletion of a ContaboCluster whose deletion preview exceeds its
+// DeletionConfirmationThreshold, its value must be the cluster UUID
+const ConfirmDeletionAnnotation = "infrastructure.cluster.x-k8s.io/confirm-deletion"
+
+// ContaboDeletionAction is what the deletion of the cluster does to a Contabo resource
+// +kubebuilder:validation:Enum=Delete;Reset;Retain
+type ContaboDeletionAction string
+
+const (
+	// ContaboDeletionActionDelete deletes the resource
+	ContaboDeletionActionDelete ContaboDeletionAction = "Delete"
+	// ContaboDeletionActionReset resets the instance and returns it to the pool of available instances, its data
+	// is lost
+	ContaboDeletionActionReset ContaboDeletionAction = "Reset"
+	// ContaboDeletionActionRetain keeps the resource, e.g. a private network shared with another cluster
+	ContaboDeletionActionRetain ContaboDeletionAction = "Retain"
+)
+
+// ContaboClusterDeletionPreviewStatus defines the Contabo resources the deletion of the cluster destroys
+type ContaboClusterDeletionPreviewStatus struct {
+	// ComputeTime is the time the preview was computed
+	ComputeTime metav1.Time `json:"computeTime"`
+
+	// Resources are the Contabo resources of the cluster and what the deletion does to them
+	// +optional
+	Resources []ContaboDeletionPreviewResource `json:"resources,omitempty"`
+
+	// Destroyed is the number of resources deleted or reset
+	Destroyed int32 `json:"destroyed"`
+
+	// ConfirmationRequired is set when Destroyed exceeds the DeletionConfirmationThreshold, the deletion then waits
+	// for the ConfirmDeletionAnnotation
+	// +optional
+	ConfirmationRequired bool `json:"confirmationRequired,omitempty"`
+}
+
+// ContaboDeletionPreviewResource defines a Contabo resource of the deletion preview of a cluster
+type ContaboDeletionPreviewResource struct {
+	// Kind is the kind of the resource: Instance, PrivateNetwork, SshKey or EtcdSnapshots
+	Kind string `json:"kind"`
+
+	// ID is the Contabo ID of the resource, or the key prefix of the etcd snapshots
+	ID string `json:"id"`
+
+	// Name is the name of the resource, the ContaboMachine (namespace/name) of an instance
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Action is what the deletion does to the resource
+	Action ContaboDeletionAction `json:"action"`
+}
+
+// ContaboEtcdBackupStatus defines the etcd snapshots of the cluster found in object storage
+type ContaboEtcdBackupStatus struct {
+	// Prefix is the key prefix of the snapshots of the cluster in the bucket
+	Prefix string `json:"prefix"`
+
+	// Snapshots is the number of snapshots kept in the bucket
+	Snapshots int32 `json:"snapshots"`
+
+	// LastSnapshotKey is the key of the latest snapshot
+	// +optional
+	LastSnapshotKey string `json:"lastSnapshotKey,omitempty"`
+
+	// LastSnapshotTime is the upload time of the latest snapshot
+	// +optional
+	LastSnapshotTime *metav1.Time `json:"lastSnapshotTime,omitempty"`
+
+	// LastSyncTime is the last time the snapshots were listed and pruned
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// RefreshAnnotation requests an immediate status refresh of all the machines of the ContaboCluster, e.g. after a
+// Contabo maintenance. Its value identifies the request, the machines are refreshed again when it changes, e.g. a
+// timestamp.
+const RefreshAnnotation = "infrastructure.cluster.x-k8s.io/refresh"
+
+// ContaboClusterRefreshStatus defines the last status refresh of the machines of a cluster
+type ContaboClusterRefreshStatus struct {
+	// Request identifies the refresh, it is the value of the RefreshAnnotation
+	Request string `json:"request"`
+
+	// RequestTime is the time the controller observed the request
+	RequestTime metav1.Time `json:"requestTime"`
+
+	// Machines is the number of machines of the cluster to refresh
+	Machines int32 `json:"machines"`
+}
+
+// ContaboClusterMachinesStatus aggregates the conditions of the machines of a cluster
+type ContaboClusterMachinesStatus struct {
+	// Total is the number of machines of the cluster
+	Total int32 `json:"total"`
+
+	// Ready is the number of machines whose Ready condition is true
+	Ready int32 `json:"ready"`
+
+	// Conditions lists the machine conditions reporting a problem, e.g. a false InstanceHostStable condition, with
+	// the machines reporting them
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []ContaboClusterMachineConditionSummary `json:"conditions,omitempty"`
+
+	// Summary sums up the machines, e.g. "9/10 machines Ready, 1 InstanceHostStable"
+	Summary string `json:"summary"`
+}
+
+// ContaboClusterMachineConditionSummary counts the machines of a cluster reporting a problem with a condition
+type ContaboClusterMachineConditionSummary struct {
+	// Type is the type of the machine condition
+	Type string `json:"type"`
+
+	// Count is the number of machines reporting the problem
+	Count int32 `json:"count"`
+
+	// Machines are the names of the ContaboMachines reporting the problem
+	Machines []string `json:"machines"`
 }
 
 // ContaboPrivateNetworkSpec defines the desired state of a Contabo private network
 type ContaboPrivateNetworkSpec struct {
 	// Region Region where the Private Network should be located. Default is `EU`
 	// +kubebuilder:validation:Required
-	Region string `json:"region"`
+	Region ContaboRegion `json:"region"`
 	// Name is the name of the private network
 	// +optional
 	Name string `json:"name,omitempty"`
+
+	// CIDR is the expected address range of the private network, e.g. 10.0.0.0/22. Contabo assigns the range of the
+	// private networks, a private network with another range is not used.
+	// +kubebuilder:validation:XValidation:rule="isCIDR(self)",message="cidr must be a valid CIDR, e.g. 10.0.0.0/22"
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+
+	// CreateIfNotExists creates the private network when none has its name. Otherwise the private network must
+	// exist, it is adopted and retained when the cluster is deleted. Default is true.
+	// +kubebuilder:default=true
+	// +optional
+	CreateIfNotExists *bool `json:"createIfNotExists,omitempty"`
+
+	// MTU is set on the private network interface of the instances at boot, the MTU detected on the first
+	// ready instance is used when not set.
+	// +kubebuilder:validation:Minimum=1280
+	// +kubebuilder:validation:Maximum=9000
+	// +optional
+	MTU *int32 `json:"mtu,omitempty"`
+
+	// CNI renders the private network MTU and routing details into the bootstrap data, in
+	// /etc/capc/private-network.env, with a CNI MTU leaving room for the encapsulation overhead.
+	// +optional
+	CNI *ContaboPrivateNetworkCNISpec `json:"cni,omitempty"`
+}
+
+// ContaboPrivateNetworkCNISpec defines the CNI MTU rendered into the bootstrap data
+type ContaboPrivateNetworkCNISpec struct {
+	// EncapsulationOverhead is subtracted from the private network MTU to compute the CNI MTU. Default is 50, the VXLAN overhead.
+	// +kubebuilder:default=50
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=200
+	// +optional
+	EncapsulationOverhead *int32 `json:"encapsulationOverhead,omitempty"`
+}
+
+// ContaboPrivateNetworkHintsStatus defines the observed MTU and routing details of a Contabo private network
+type ContaboPrivateNetworkHintsStatus struct {
+	// MTU is the effective MTU of the private network, the spec MTU or the detected one.
+	// +optional
+	MTU int32 `json:"mtu,omitempty"`
+
+	// DetectedMTU is the MTU of the private network interface detected on an instance.
+	// +optional
+	DetectedMTU int32 `json:"detectedMTU,omitempty"`
+
+	// Interface is the private network interface detected on an instance.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+
+	// DetectedFrom is the ContaboMachine the MTU was detected on.
+	// +optional
+	DetectedFrom string `json:"detectedFrom,omitempty"`
+
+	// Gateway is the private network gateway reported by the Contabo API.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// CNIMTU is the MTU recommended for the CNI, the private network MTU minus the encapsulation overhead.
+	// +optional
+	CNIMTU int32 `json:"cniMTU,omitempty"`
 }
 
 // ContaboPrivateNetworkStatus defines the observed state of a Contabo private network
@@ -96,6 +539,50 @@ type ContaboSshKeyStatus struct {
 	Value string `json:"value"`
 }
 
+// ContaboControlPlaneEndpointVIPStatus defines the Contabo VIP serving the control plane endpoint. The VIP is assigned
+// to a control plane instance as soon as one exists, and moved to another control plane instance when its holder is
+// deleted.
+type ContaboControlPlaneEndpointVIPStatus struct {
+	// IP is the IPv4 address of the VIP, published as the control plane endpoint host.
+	IP string `json:"ip"`
+
+	// VipId is the ID of the VIP in Contabo.
+	// +optional
+	VipId string `json:"vipId,omitempty"`
+
+	// Port is the port of the control plane endpoint.
+	Port int32 `json:"port"`
+
+	// InstanceId is the ID of the control plane instance holding the VIP, unset until one exists.
+	// +optional
+	InstanceId *int64 `json:"instanceId,omitempty"`
+
+	// MachineName is the name of the ContaboMachine of the instance holding the VIP.
+	// +optional
+	MachineName string `json:"machineName,omitempty"`
+}
+
+// ContaboKubeconfigStatus defines the kubeconfig Secrets generated for the cluster. They are copies of the Cluster
+// API kubeconfig pointing to a control plane machine through its public IP or its private network IP, kept in sync
+// with the control plane machines and the Cluster API kubeconfig.
+type ContaboKubeconfigStatus struct {
+	// PublicSecretName is the name of the Secret holding the kubeconfig of the public endpoint.
+	// +optional
+	PublicSecretName string `json:"publicSecretName,omitempty"`
+
+	// PublicServer is the API server URL of the public kubeconfig.
+	// +optional
+	PublicServer string `json:"publicServer,omitempty"`
+
+	// PrivateSecretName is the name of the Secret holding the kubeconfig of the private network endpoint.
+	// +optional
+	PrivateSecretName string `json:"privateSecretName,omitempty"`
+
+	// PrivateServer is the API server URL of the private kubeconfig.
+	// +optional
+	PrivateServer string `json:"privateServer,omitempty"`
+}
+
 // ContaboClusterInitializationStatus defines the observed state of the initialization process
 type ContaboClusterInitializationStatus struct {
 	// Provisioned indicates if the initialization is complete
@@ -106,8 +593,12 @@ type ContaboClusterInitializationStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this ContaboCluster belongs"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Cluster infrastructure is ready"
+// +kubebuilder:printcolumn:name="Machines",type="string",JSONPath=".status.machines.summary",description="Machines of the cluster"
 // +kubebuilder:printcolumn:name="Private Network",type="string",JSONPath=".status.privateNetwork.name",description="Private Network"
 // +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.controlPlaneEndpoint.host",description="API Endpoint",priority=1
+// +kubebuilder:printcolumn:name="MTU",type="integer",JSONPath=".status.privateNetworkHints.mtu",description="Private network MTU",priority=1
+// +kubebuilder:printcolumn:name="Operation",type="string",JSONPath=".status.lastOperation.type",description="Last operation of the controller",priority=1
+// +kubebuilder:printcolumn:name="Operation State",type="string",JSONPath=".status.lastOperation.state",description="State of the last operation of the controller",priority=1
 // +kubebuilder:resource:path=contaboclusters,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
 
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PreCreateHookAnnotationPrefix prefixes the annotations of a ContaboMachine holding the acquisition of its
+	// instance, e.g. pre-create.hook.infrastructure.cluster.x-k8s.io/cmdb. The instance is ordered or reused once
+	// every annotation with the prefix is removed by its owner.
+	PreCreateHookAnnotationPrefix = "pre-create.hook.infrastructure.cluster.x-k8s.io/"
+
+	// PostProvisionHookAnnotationPrefix prefixes the annotations of a ContaboMachine holding its availability once
+	// its instance is bootstrapped. The machine is reported available once every annotation with the prefix is removed.
+	PostProvisionHookAnnotationPrefix = "post-provision.hook.infrastructure.cluster.x-k8s.io/"
+
+	// PreDeleteHookAnnotationPrefix prefixes the annotations of a ContaboMachine holding the release of its instance
+	// on deletion. The instance is released once every annotation with the prefix is removed.
+	PreDeleteHookAnnotationPrefix = "pre-delete.hook.infrastructure.cluster.x-k8s.io/"
+)
+
+// ContaboLifecycleHookPoint is a point of the lifecycle of a ContaboMachine at which the hooks are called
+// +kubebuilder:validation:Enum=PreCreate;PostProvision;PreDelete
+type ContaboLifecycleHookPoint string
+
+const (
+	// ContaboLifecycleHookPointPreCreate is before an instance is ordered or reused for the machine
+	ContaboLifecycleHookPointPreCreate ContaboLifecycleHookPoint = "PreCreate"
+
+	// ContaboLifecycleHookPointPostProvision is once the instance of the machine is bootstrapped, before the machine
+	// is reported available
+	ContaboLifecycleHookPointPostProvision ContaboLifecycleHookPoint = "PostProvision"
+
+	// ContaboLifecycleHookPointPreDelete is before the instance of a deleted machine is released
+	ContaboLifecycleHookPointPreDelete ContaboLifecycleHookPoint = "PreDelete"
+)
+
+// ContaboLifecycleHookFailurePolicy is what a blocking hook does when its call fails
+// +kubebuilder:validation:Enum=Fail;Ignore
+type ContaboLifecycleHookFailurePolicy string
+
+const (
+	// ContaboLifecycleHookFailurePolicyFail holds the lifecycle point and calls the hook again until it succeeds
+	ContaboLifecycleHookFailurePolicyFail ContaboLifecycleHookFailurePolicy = "Fail"
+
+	// ContaboLifecycleHookFailurePolicyIgnore records the failure and releases the lifecycle point
+	ContaboLifecycleHookFailurePolicyIgnore ContaboLifecycleHookFailurePolicy = "Ignore"
+)
+
+// ContaboLifecycleHookSpec defines the desired state of ContaboLifecycleHook.
+type ContaboLifecycleHookSpec struct {
+	// ClusterName restricts the hook to the ContaboMachines of a Cluster API cluster, the ContaboMachines of every
+	// cluster of the namespace by default.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Selector restricts the hook to the ContaboMachines matching the labels, all ContaboMachines by default.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Points are the lifecycle points the hook is called at.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Points []ContaboLifecycleHookPoint `json:"points"`
+
+	// URL is the endpoint the lifecycle events are posted to. Prefer URLSecretRef for URLs holding a token.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// URLSecretRef references a Secret of the namespace holding the endpoint in its 'url' key.
+	// +optional
+	URLSecretRef *corev1.LocalObjectReference `json:"urlSecretRef,omitempty"`
+
+	// Blocking holds the lifecycle point until the endpoint answers with a 2xx status code other than 202 Accepted,
+	// which asks to be called again. Non-blocking hooks are posted once in the background. Default is true.
+	// +kubebuilder:default=true
+	// +optional
+	Blocking *bool `json:"blocking,omitempty"`
+
+	// Timeout bounds each call of the endpoint. Default is 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// FailurePolicy is what a blocking hook does when its call fails. Default is Fail.
+	// +kubebuilder:default=Fail
+	// +optional
+	FailurePolicy ContaboLifecycleHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster of the hooked machines"
+// +kubebuilder:printcolumn:name="Points",type="string",JSONPath=".spec.points",description="Lifecycle points the hook is called at"
+// +kubebuilder:printcolumn:name="Blocking",type="boolean",JSONPath=".spec.blocking",description="Whether the hook holds the lifecycle points"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=contabolifecyclehooks,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+
+// ContaboLifecycleHook is the Schema for the contabolifecyclehooks API. It registers an HTTP endpoint called at
+// lifecycle points of the ContaboMachines, e.g. to register the instances in a CMDB.
+type ContaboLifecycleHook struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of ContaboLifecycleHook
+	// +required
+	Spec ContaboLifecycleHookSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContaboLifecycleHookList contains a list of ContaboLifecycleHook
+type ContaboLifecycleHookList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContaboLifecycleHook `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContaboLifecycleHook{}, &ContaboLifecycleHookList{})
+}
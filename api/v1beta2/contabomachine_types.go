@@ -18,6 +18,7 @@ package v1beta2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 )
@@ -31,12 +32,147 @@ type ContaboMachineSpec struct {
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
 
+	// FailureDomain is the failure domain, the Contabo region, the instance landed in. It is set by the provider
+	// and reported to the Machine by Cluster API.
+	// +optional
+	FailureDomain *string `json:"failureDomain,omitempty"`
+
 	// Instance is the type of instance to create.
 	Instance ContaboInstanceSpec `json:"instance"`
 
 	// Index is the index of the machine in the machine deployment.
 	// +optional
 	Index *int32 `json:"index,omitempty"`
+
+	// PowerState is the desired power state of the instance once provisioned. Stopped instances are shut down
+	// gracefully, then stopped after the shutdown timeout of the ContaboProviderSettings. Control plane machines
+	// are not stopped below the quorum of the control plane.
+	// +kubebuilder:default=Running
+	// +optional
+	PowerState ContaboPowerState `json:"powerState,omitempty"`
+
+	// ReusePolicy is what the deletion of the machine does to its instance, as Contabo instances are billed monthly.
+	// Reinstall returns the instance to the free pool, tagged with the FreePoolTag, where the next machines claim and
+	// reinstall it instead of ordering a new instance. Cancel cancels the contract of the instance, it is deleted by
+	// Contabo at the end of the paid period. Default is Reinstall.
+	// +kubebuilder:default=Reinstall
+	// +optional
+	ReusePolicy ContaboMachineReusePolicy `json:"reusePolicy,omitempty"`
+
+	// NetworkConfig is a raw cloud-init network-config (version 2, netplan) document, e.g. for bonded interfaces,
+	// static routes or custom DNS. It is written as a netplan configuration applied on top of the Contabo one before
+	// the bootstrap, the ${INTERNAL_IPV4}, ${INTERNAL_IPV4_CIDR}, ${EXTERNAL_IPV4} and ${EXTERNAL_IPV6} variables are replaced.
+	// +kubebuilder:validation:MaxLength=16384
+	// +optional
+	NetworkConfig *string `json:"networkConfig,omitempty"`
+
+	// DNS configures the resolvers of the instance instead of the Contabo ones, e.g. internal resolvers reachable
+	// over the private network. It is applied before the bootstrap, changes apply to the next reinstall of the instance.
+	// +optional
+	DNS *ContaboDNSSpec `json:"dns,omitempty"`
+
+	// NodeLabels are set on the node when it registers, rendered as the kubelet node-labels flag of the kubeadm
+	// nodeRegistration, so that node pools come up labeled. Labels in the kubernetes.io and k8s.io domains are
+	// restricted by the NodeRestriction admission plugin, only the node.kubernetes.io and kubelet.kubernetes.io
+	// prefixes are allowed. Changes apply to the next reinstall of the instance.
+	// +kubebuilder:validation:MaxProperties=64
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// NodeTaints are set on the node when it registers, added to the taints of the kubeadm nodeRegistration. The
+	// control plane taint kubeadm sets by default is kept. Changes apply to the next reinstall of the instance.
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	NodeTaints []corev1.Taint `json:"nodeTaints,omitempty"`
+
+	// PrivateOnly provisions the instance without relying on its public IPv4 connectivity, e.g. for security
+	// sensitive deployments. The controller reaches the instance over SSH through a bastion of the private network,
+	// and the instance egresses through a NAT gateway or an HTTP proxy of the private network. Contabo instances
+	// always have a public IPv4, it is still reported in the addresses. Changes apply to the next reinstall of the
+	// instance.
+	// +optional
+	PrivateOnly *ContaboPrivateOnlySpec `json:"privateOnly,omitempty"`
+
+	// EnableNodeMonitoring installs the Prometheus node-exporter on the instance, listening on port 9100 of its
+	// private IPv4 only, for hardware-level metrics such as the disk usage. The capc_machine_info metric ties the
+	// metrics of the instance back to its ContaboMachine. Changes apply to the next reinstall of the instance.
+	// +optional
+	EnableNodeMonitoring bool `json:"enableNodeMonitoring,omitempty"`
+}
+
+// ContaboPrivateOnlySpec defines how a private-only Contabo instance is reached and egresses
+type ContaboPrivateOnlySpec struct {
+	// Bastion is the SSH bastion reachable by the controller and attached to the private network of the cluster,
+	// the controller connects to the private IPv4 of the instance through it
+	Bastion ContaboBastionSpec `json:"bastion"`
+
+	// NATGateway is the private IPv4 of a NAT gateway of the private network. It is set as the default route of the
+	// instance at every boot, before the packages are installed, so that it does not egress over its public interface.
+	// +kubebuilder:validation:Format=ipv4
+	// +optional
+	NATGateway string `json:"natGateway,omitempty"`
+
+	// Proxy is the HTTP proxy of the private network used by the instance for the packages, the bootstrap downloads
+	// and the container image pulls
+	// +optional
+	Proxy *ContaboProxySpec `json:"proxy,omitempty"`
+}
+
+// ContaboBastionSpec defines the SSH bastion used to reach private-only Contabo instances
+type ContaboBastionSpec struct {
+	// Host is the address of the bastion reachable by the controller
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Port is the SSH port of the bastion
+	// +kubebuilder:default=22
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// User is the SSH user on the bastion
+	// +kubebuilder:default=root
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// SshKeySecretName is the name of the Secret, in the namespace of the machine, holding the private key of the
+	// bastion in its id_ed25519 or id_rsa key. The SSH key of the machine is used when unset, it must then be authorized
+	// on the bastion.
+	// +optional
+	SshKeySecretName string `json:"sshKeySecretName,omitempty"`
+}
+
+// ContaboProxySpec defines the HTTP proxy used by a Contabo instance
+type ContaboProxySpec struct {
+	// HTTPProxy is the proxy URL for HTTP requests, e.g. http://10.0.0.2:3128
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the proxy URL for HTTPS requests, e.g. http://10.0.0.2:3128
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy are the additional hosts, domains and CIDRs reached without the proxy. Localhost, the private network,
+	// the control plane endpoint and the cluster domains are always reached directly.
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+// ContaboDNSSpec defines the resolvers of a Contabo instance
+type ContaboDNSSpec struct {
+	// Nameservers are the IPv4 or IPv6 addresses of the resolvers, queried in order
+	// +kubebuilder:validation:MaxItems=3
+	// +optional
+	Nameservers []string `json:"nameservers,omitempty"`
+
+	// SearchDomains are the domains appended to unqualified names, in order
+	// +kubebuilder:validation:MaxItems=6
+	// +optional
+	SearchDomains []string `json:"searchDomains,omitempty"`
 }
 
 // ContaboMachineStatus defines the observed state of ContaboMachine.
@@ -64,6 +200,135 @@ type ContaboMachineStatus struct {
 	// +optional
 	Initialization *ContaboMachineInitializationStatus `json:"initialization,omitempty"`
 
+	// InstanceOrder tracks the instance ordered for the machine until it appears and leaves provisioning
+	// +optional
+	InstanceOrder *ContaboInstanceOrderStatus `json:"instanceOrder,omitempty"`
+
+	// ControlPlaneGang tracks the instances ordered by the first control plane machine for the other control plane
+	// machines of the quorum, see the ControlPlaneGang of the ContaboCluster
+	// +optional
+	ControlPlaneGang *ContaboControlPlaneGangStatus `json:"controlPlaneGang,omitempty"`
+
+	// FirstBootProbeStartTime is the time the SSH first-boot probe started for the current instance
+	// +optional
+	FirstBootProbeStartTime *metav1.Time `json:"firstBootProbeStartTime,omitempty"`
+
+	// AuditTrail contains the latest Contabo audit entries of the instance and its image, newest first
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	AuditTrail []ContaboAuditEntry `json:"auditTrail,omitempty"`
+
+	// AuditTrailLastUpdated is the last time the audit trail was refreshed from the Contabo API
+	// +optional
+	AuditTrailLastUpdated *metav1.Time `json:"auditTrailLastUpdated,omitempty"`
+
+	// Tags are the Contabo tags assigned to the instance by the provider
+	// +optional
+	Tags []ContaboTagStatus `json:"tags,omitempty"`
+
+	// TagsLastUpdated is the last time the tag assignments were compared with the Contabo API
+	// +optional
+	TagsLastUpdated *metav1.Time `json:"tagsLastUpdated,omitempty"`
+
+	// SshKey is the Contabo secret registered for the SSH key of spec.instance.sshKeySecretName
+	// +optional
+	SshKey *ContaboSshKeyStatus `json:"sshKey,omitempty"`
+
+	// Migration is the state of the data center migration requested with the MigrateToDataCenterAnnotation
+	// +optional
+	Migration *ContaboMachineMigrationStatus `json:"migration,omitempty"`
+
+	// Patch is the state of the OS patching requested with the PatchRequestAnnotation
+	// +optional
+	Patch *ContaboMachinePatchStatus `json:"patch,omitempty"`
+
+	// SnapshotCount is the number of snapshots of the instance when last checked
+	// +optional
+	SnapshotCount *int32 `json:"snapshotCount,omitempty"`
+
+	// PowerState is the observed power state of the instance, set once the power state of the spec is reached
+	// +optional
+	PowerState ContaboPowerState `json:"powerState,omitempty"`
+
+	// ShutdownTime is the time the graceful shutdown of the instance was requested, the instance is stopped if it
+	// does not shut down in time
+	// +optional
+	ShutdownTime *metav1.Time `json:"shutdownTime,omitempty"`
+
+	// CatalogSnapshot is the product and image metadata resolved when the instance was acquired, kept for debugging
+	// and cost audits whatever the current state of the Contabo catalog
+	// +optional
+	CatalogSnapshot *ContaboCatalogSnapshot `json:"catalogSnapshot,omitempty"`
+
+	// Placement is where the instance is ordered and the failure domains its product was out of stock in
+	// +optional
+	Placement *ContaboMachinePlacementStatus `json:"placement,omitempty"`
+
+	// UserData is how the bootstrap data was passed to the instance on its last reinstall
+	// +optional
+	UserData *ContaboUserDataStatus `json:"userData,omitempty"`
+
+	// BootstrapToken is the kubeadm bootstrap token created in the workload cluster for the instance, cleared once
+	// the node joined and the token is deleted
+	// +optional
+	BootstrapToken *ContaboBootstrapTokenStatus `json:"bootstrapToken,omitempty"`
+
+	// Host is the host system the instance runs on, tracked to detect moves of the instance between hosts
+	// +optional
+	Host *ContaboMachineHostStatus `json:"host,omitempty"`
+
+	// BootLogs is the state of the boot log collection requested with the CollectBootLogsAnnotation
+	// +optional
+	BootLogs *ContaboMachineBootLogsStatus `json:"bootLogs,omitempty"`
+
+	// RefreshRequest is the last refresh requested with the RefreshAnnotation of the ContaboCluster and applied to the
+	// machine
+	// +optional
+	RefreshRequest string `json:"refreshRequest,omitempty"`
+
+	// Rollout is the state of the in-place rollout of the template updates to the machine, see the ReinstallInPlace
+	// rollout strategy
+	// +optional
+	Rollout *ContaboMachineRolloutStatus `json:"rollout,omitempty"`
+
+	// NodeName is the OS hostname of the instance and the name of its node, rendered from the hostname pattern of
+	// the ContaboCluster when the instance was acquired
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Jobs are the long-running operations of the instance, such as snapshots, run by the job queue of the controller
+	// outside of the reconciliation, the unfinished jobs first and then the latest finished ones
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	Jobs []ContaboMachineJobStatus `json:"jobs,omitempty"`
+
+	// LifecycleHooks are the calls of the ContaboLifecycleHooks of the machine, a hook is called once per lifecycle
+	// point and instance
+	// +listType=map
+	// +listMapKey=name
+	// +listMapKey=point
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	LifecycleHooks []ContaboLifecycleHookCallStatus `json:"lifecycleHooks,omitempty"`
+
+	// IPv4Addresses are the public IPv4 addresses of the instance with their role, the primary address first
+	// +optional
+	IPv4Addresses []ContaboIPv4AddressStatus `json:"ipv4Addresses,omitempty"`
+
+	// AddOnUpgrades are the add-ons of spec.instance.addOns added to the instance with UpgradeInstance, or ordered
+	// with the instance when the controller created it, the instance is only upgraded once per add-on
+	// +listType=map
+	// +listMapKey=id
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	AddOnUpgrades []ContaboAddOnUpgradeStatus `json:"addOnUpgrades,omitempty"`
+
+	// LastOperation is the last operation of the controller on the machine and the reason of its last decision
+	// +optional
+	LastOperation *ContaboLastOperation `json:"lastOperation,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -103,6 +368,514 @@ type ContaboMachineStatus struct {
 	FailureMessage *string `json:"failureMessage,omitempty"`
 }
 
+// ContaboMachinePlacementStatus defines the failure domains an instance is ordered in
+type ContaboMachinePlacementStatus struct {
+	// RequestedFailureDomain is the failure domain requested by the Machine, or the first one of the cluster
+	RequestedFailureDomain string `json:"requestedFailureDomain"`
+
+	// FailureDomain is the failure domain the instance is ordered in, or landed in once acquired
+	FailureDomain string `json:"failureDomain"`
+
+	// OutOfStock lists the failure domains the product was out of stock in, in the order they were tried
+	// +optional
+	OutOfStock []string `json:"outOfStock,omitempty"`
+
+	// LastOutOfStockTime is the last time the product was out of stock
+	// +optional
+	LastOutOfStockTime *metav1.Time `json:"lastOutOfStockTime,omitempty"`
+}
+
+// ContaboUserDataMode is how the bootstrap data is passed to an instance
+type ContaboUserDataMode string
+
+const (
+	// ContaboUserDataModePlain passes the bootstrap data as is
+	ContaboUserDataModePlain ContaboUserDataMode = "Plain"
+
+	// ContaboUserDataModeGzip passes the gzipped bootstrap data in a cloud-init MIME multipart user data
+	ContaboUserDataModeGzip ContaboUserDataMode = "Gzip"
+
+	// ContaboUserDataModeObjectStorage passes a minimal user data including the bootstrap data from object storage
+	ContaboUserDataModeObjectStorage ContaboUserDataMode = "ObjectStorage"
+)
+
+// ContaboUserDataStatus defines how the bootstrap data was passed to an instance
+type ContaboUserDataStatus struct {
+	// Mode is how the bootstrap data was passed to the instance
+	Mode ContaboUserDataMode `json:"mode"`
+
+	// BootstrapDataSize is the size of the bootstrap data in bytes
+	BootstrapDataSize int32 `json:"bootstrapDataSize"`
+
+	// Size is the size of the user data sent to the Contabo API in bytes
+	Size int32 `json:"size"`
+
+	// ObjectKey is the key of the bootstrap data in the object storage bucket, cleared once it is deleted
+	// +optional
+	ObjectKey string `json:"objectKey,omitempty"`
+}
+
+// ContaboBootstrapTokenStatus defines the kubeadm bootstrap token of an instance, the token secret is only stored in
+// the workload cluster
+type ContaboBootstrapTokenStatus struct {
+	// TokenId is the public part of the bootstrap token
+	TokenId string `json:"tokenId"`
+
+	// ExpirationTime is the time the bootstrap token expires
+	ExpirationTime metav1.Time `json:"expirationTime"`
+}
+
+// ContaboMachineHostStatus defines the host system an instance runs on, the vHostId and vHostName of the Contabo API
+type ContaboMachineHostStatus struct {
+	// InstanceId is the instance the host system was observed for
+	InstanceId int64 `json:"instanceId"`
+
+	// VHostId is the ID of the host system the instance runs on
+	VHostId int64 `json:"vHostId"`
+
+	// VHostName is the name of the host system the instance runs on
+	// +optional
+	VHostName string `json:"vHostName,omitempty"`
+
+	// PreviousVHostId is the ID of the host system the instance ran on before its last move
+	// +optional
+	PreviousVHostId *int64 `json:"previousVHostId,omitempty"`
+
+	// PreviousVHostName is the name of the host system the instance ran on before its last move
+	// +optional
+	PreviousVHostName string `json:"previousVHostName,omitempty"`
+
+	// Changes is the number of host system changes observed for the instance
+	// +optional
+	Changes int32 `json:"changes,omitempty"`
+
+	// LastChangeTime is the last time the instance was observed on another host system
+	// +optional
+	LastChangeTime *metav1.Time `json:"lastChangeTime,omitempty"`
+
+	// LastChecked is the last time the host system was retrieved from the Contabo API
+	// +optional
+	LastChecked *metav1.Time `json:"lastChecked,omitempty"`
+}
+
+// CollectBootLogsAnnotation requests the collection of the boot logs of the ContaboMachine instance into a
+// ConfigMap. Its value identifies the request, the logs are collected again when it changes, e.g. a timestamp.
+const CollectBootLogsAnnotation = "infrastructure.cluster.x-k8s.io/collect-boot-logs"
+
+// ContaboMachineBootLogsStatus defines the state of the last boot log collection of a machine
+type ContaboMachineBootLogsStatus struct {
+	// Request identifies the collection, it is the value of the CollectBootLogsAnnotation
+	Request string `json:"request"`
+
+	// ConfigMapName is the ConfigMap holding the collected logs, empty when they could not be collected
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// CollectionTime is the time the logs were collected
+	CollectionTime metav1.Time `json:"collectionTime"`
+
+	// Message provides details about the collection
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// OperationCheckpointAnnotation holds the in-flight operations of the ContaboMachine (instance, order, migration and
+// patching). clusterctl move does not preserve the status, the controller of the target cluster rebuilds it from
+// this annotation.
+const OperationCheckpointAnnotation = "infrastructure.cluster.x-k8s.io/operation-checkpoint"
+
+// MigrateToDataCenterAnnotation requests the migration of the ContaboMachine instance to the given data center
+const MigrateToDataCenterAnnotation = "infrastructure.cluster.x-k8s.io/migrate-to-datacenter"
+
+// ContaboMachineMigrationPhase is the phase of a data center migration
+type ContaboMachineMigrationPhase string
+
+const (
+	// ContaboMachineMigrationPhaseSnapshotting indicates a snapshot of the original instance is being taken
+	ContaboMachineMigrationPhaseSnapshotting ContaboMachineMigrationPhase = "Snapshotting"
+	// ContaboMachineMigrationPhaseProvisioning indicates a replacement instance is being acquired in the target data center
+	ContaboMachineMigrationPhaseProvisioning ContaboMachineMigrationPhase = "Provisioning"
+	// ContaboMachineMigrationPhaseSwapping indicates the replacement instance is swapped in and the original retired
+	ContaboMachineMigrationPhaseSwapping ContaboMachineMigrationPhase = "Swapping"
+	// ContaboMachineMigrationPhaseCompleted indicates the migration is complete
+	ContaboMachineMigrationPhaseCompleted ContaboMachineMigrationPhase = "Completed"
+	// ContaboMachineMigrationPhaseFailed indicates the migration cannot be performed
+	ContaboMachineMigrationPhaseFailed ContaboMachineMigrationPhase = "Failed"
+)
+
+// ContaboMachineMigrationStatus defines the observed state of a data center migration
+type ContaboMachineMigrationStatus struct {
+	// TargetDataCenter is the data center the instance is migrated to
+	TargetDataCenter string `json:"targetDataCenter"`
+
+	// Phase is the current phase of the migration
+	Phase ContaboMachineMigrationPhase `json:"phase"`
+
+	// SourceInstanceId is the identifier of the original instance
+	SourceInstanceId int64 `json:"sourceInstanceId"`
+
+	// SnapshotId is the identifier of the snapshot taken on the original instance before the migration
+	// +optional
+	SnapshotId string `json:"snapshotId,omitempty"`
+
+	// TargetInstanceId is the identifier of the replacement instance
+	// +optional
+	TargetInstanceId int64 `json:"targetInstanceId,omitempty"`
+
+	// StartTime is the time the migration started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Message provides details about the migration
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ContaboMachinePatchPhase is the phase of the OS patching of a machine
+type ContaboMachinePatchPhase string
+
+const (
+	// ContaboMachinePatchPhaseCordoning indicates the node is being cordoned
+	ContaboMachinePatchPhaseCordoning ContaboMachinePatchPhase = "Cordoning"
+	// ContaboMachinePatchPhaseUpgrading indicates the upgrade command is running on the instance
+	ContaboMachinePatchPhaseUpgrading ContaboMachinePatchPhase = "Upgrading"
+	// ContaboMachinePatchPhaseRebooting indicates the instance was restarted and the node is expected back
+	ContaboMachinePatchPhaseRebooting ContaboMachinePatchPhase = "Rebooting"
+	// ContaboMachinePatchPhaseUncordoning indicates the node is being uncordoned
+	ContaboMachinePatchPhaseUncordoning ContaboMachinePatchPhase = "Uncordoning"
+	// ContaboMachinePatchPhaseCompleted indicates the machine is patched
+	ContaboMachinePatchPhaseCompleted ContaboMachinePatchPhase = "Completed"
+	// ContaboMachinePatchPhaseFailed indicates the machine could not be patched
+	ContaboMachinePatchPhaseFailed ContaboMachinePatchPhase = "Failed"
+)
+
+// ContaboMachinePatchStatus defines the observed state of the OS patching of a machine
+type ContaboMachinePatchStatus struct {
+	// Run identifies the patch run, it is the value of the PatchRequestAnnotation
+	Run string `json:"run"`
+
+	// Phase is the current phase of the patching
+	Phase ContaboMachinePatchPhase `json:"phase"`
+
+	// StartTime is the time the patching started
+	StartTime metav1.Time `json:"startTime"`
+
+	// RebootTime is the time the instance was restarted
+	// +optional
+	RebootTime *metav1.Time `json:"rebootTime,omitempty"`
+
+	// Message provides details about the patching
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ContaboMachineRolloutPhase is the phase of the in-place rollout of the template updates to a machine
+type ContaboMachineRolloutPhase string
+
+const (
+	// ContaboMachineRolloutPhaseCordoning indicates the node is being cordoned
+	ContaboMachineRolloutPhaseCordoning ContaboMachineRolloutPhase = "Cordoning"
+	// ContaboMachineRolloutPhaseDraining indicates the pods are being evicted from the node
+	ContaboMachineRolloutPhaseDraining ContaboMachineRolloutPhase = "Draining"
+	// ContaboMachineRolloutPhaseReinstalling indicates the instance is being reinstalled with the updated spec
+	ContaboMachineRolloutPhaseReinstalling ContaboMachineRolloutPhase = "Reinstalling"
+	// ContaboMachineRolloutPhaseCompleted indicates the instance runs the updated spec
+	ContaboMachineRolloutPhaseCompleted ContaboMachineRolloutPhase = "Completed"
+	// ContaboMachineRolloutPhaseFailed indicates the rollout failed, it is retried on the next template update
+	ContaboMachineRolloutPhaseFailed ContaboMachineRolloutPhase = "Failed"
+)
+
+// ContaboMachineRolloutStatus defines the observed state of the in-place rollout of the template updates to a machine
+type ContaboMachineRolloutStatus struct {
+	// InstalledSpecHash is the hash of the OS and bootstrap fields of the spec the instance was installed with
+	// +optional
+	InstalledSpecHash string `json:"installedSpecHash,omitempty"`
+
+	// TargetSpecHash is the hash of the OS and bootstrap fields of the spec being rolled out
+	// +optional
+	TargetSpecHash string `json:"targetSpecHash,omitempty"`
+
+	// Phase is the current phase of the rollout, empty when no rollout ran yet
+	// +optional
+	Phase ContaboMachineRolloutPhase `json:"phase,omitempty"`
+
+	// StartTime is the time the rollout started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Message provides details about the rollout
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ContaboMachineJobType is the operation run by a job
+// +kubebuilder:validation:Enum=Snapshot
+type ContaboMachineJobType string
+
+const (
+	// ContaboMachineJobTypeSnapshot takes a snapshot of the instance, pruning the oldest snapshots taken by the
+	// provider to stay within the snapshot limit
+	ContaboMachineJobTypeSnapshot ContaboMachineJobType = "Snapshot"
+)
+
+// ContaboMachineJobPhase is the phase of a job
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type ContaboMachineJobPhase string
+
+const (
+	// ContaboMachineJobPhasePending indicates the job waits for a worker
+	ContaboMachineJobPhasePending ContaboMachineJobPhase = "Pending"
+	// ContaboMachineJobPhaseRunning indicates a worker runs the job, it is retried after a failed attempt
+	ContaboMachineJobPhaseRunning ContaboMachineJobPhase = "Running"
+	// ContaboMachineJobPhaseSucceeded indicates the job completed
+	ContaboMachineJobPhaseSucceeded ContaboMachineJobPhase = "Succeeded"
+	// ContaboMachineJobPhaseFailed indicates the job failed after its last attempt
+	ContaboMachineJobPhaseFailed ContaboMachineJobPhase = "Failed"
+)
+
+// ContaboMachineJobStatus defines a long-running operation of the instance run by the job queue of the controller
+type ContaboMachineJobStatus struct {
+	// Name identifies the job within the machine, it also names the snapshot of a Snapshot job
+	// +kubebuilder:validation:MaxLength=25
+	Name string `json:"name"`
+
+	// Type is the operation run by the job
+	Type ContaboMachineJobType `json:"type"`
+
+	// InstanceId is the instance the job operates on
+	InstanceId int64 `json:"instanceId"`
+
+	// Description is the description of the snapshot of a Snapshot job
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Phase is the current phase of the job
+	Phase ContaboMachineJobPhase `json:"phase"`
+
+	// Attempts is the number of times a worker ran the job
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// SnapshotId is the identifier of the snapshot taken by a Snapshot job
+	// +optional
+	SnapshotId string `json:"snapshotId,omitempty"`
+
+	// CreationTime is the time the job was queued
+	CreationTime metav1.Time `json:"creationTime"`
+
+	// StartTime is the time a worker first ran the job
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the job succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message provides details about the job, the error of the last attempt while it is retried
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ContaboLifecycleHookCallPhase is the phase of the call of a lifecycle hook
+// +kubebuilder:validation:Enum=Pending;Sent;Succeeded;Failed
+type ContaboLifecycleHookCallPhase string
+
+const (
+	// ContaboLifecycleHookCallPhasePending indicates a blocking hook holds the lifecycle point, it is called again
+	ContaboLifecycleHookCallPhasePending ContaboLifecycleHookCallPhase = "Pending"
+	// ContaboLifecycleHookCallPhaseSent indicates a non-blocking hook was posted in the background
+	ContaboLifecycleHookCallPhaseSent ContaboLifecycleHookCallPhase = "Sent"
+	// ContaboLifecycleHookCallPhaseSucceeded indicates a blocking hook released the lifecycle point
+	ContaboLifecycleHookCallPhaseSucceeded ContaboLifecycleHookCallPhase = "Succeeded"
+	// ContaboLifecycleHookCallPhaseFailed indicates the call of a blocking hook ignoring its failures failed
+	ContaboLifecycleHookCallPhaseFailed ContaboLifecycleHookCallPhase = "Failed"
+)
+
+// ContaboLifecycleHookCallStatus defines the call of a ContaboLifecycleHook at a lifecycle point of the machine
+type ContaboLifecycleHookCallStatus struct {
+	// Name is the name of the ContaboLifecycleHook
+	Name string `json:"name"`
+
+	// Point is the lifecycle point the hook was called at
+	Point ContaboLifecycleHookPoint `json:"point"`
+
+	// Phase is the current phase of the call
+	Phase ContaboLifecycleHookCallPhase `json:"phase"`
+
+	// Attempts is the number of times the endpoint of the hook was called
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// LastCallTime is the time the endpoint of the hook was last called
+	// +optional
+	LastCallTime *metav1.Time `json:"lastCallTime,omitempty"`
+
+	// Message provides details about the call, the error of the last attempt while it is retried
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ContaboInstanceOrderStatus tracks an instance ordered from the Contabo API
+type ContaboInstanceOrderStatus struct {
+	// InstanceId is the identifier returned when ordering the instance, zero while the replacement is not ordered yet
+	// +optional
+	InstanceId int64 `json:"instanceId,omitempty"`
+
+	// OrderTime is the time the instance was ordered
+	// +optional
+	OrderTime *metav1.Time `json:"orderTime,omitempty"`
+
+	// Recreations is the number of orders replaced because their instance never appeared or left provisioning
+	// +optional
+	Recreations int32 `json:"recreations,omitempty"`
+}
+
+// ContaboControlPlaneGangStatus tracks the instances ordered for the control plane quorum of a new cluster
+type ContaboControlPlaneGangStatus struct {
+	// StartTime is the time the instances of the quorum were first ordered
+	StartTime metav1.Time `json:"startTime"`
+
+	// Orders are the instances ordered for the other control plane machines, by machine index
+	// +optional
+	Orders []ContaboControlPlaneGangOrder `json:"orders,omitempty"`
+
+	// CompletionTime is the time all the instances of the quorum existed or the timeout expired, the machine is
+	// bootstrapped afterwards
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// ContaboControlPlaneGangOrder is an instance ordered for another control plane machine of the quorum
+type ContaboControlPlaneGangOrder struct {
+	// Index is the index of the control plane machine the instance is ordered for, it is named after it
+	Index int32 `json:"index"`
+
+	// DisplayName is the display name of the instance, found by the control plane machine of the index
+	DisplayName string `json:"displayName"`
+
+	// InstanceId is the identifier returned when ordering the instance, zero while it is not ordered yet
+	// +optional
+	InstanceId int64 `json:"instanceId,omitempty"`
+}
+
+// ContaboCatalogSnapshot is the Contabo product and image metadata of an instance at the time it was acquired
+type ContaboCatalogSnapshot struct {
+	// RecordedAt is the time the snapshot was taken
+	RecordedAt metav1.Time `json:"recordedAt"`
+
+	// InstanceId is the instance the snapshot was taken for, a new snapshot is taken when the instance changes
+	InstanceId int64 `json:"instanceId"`
+
+	// ProductId is the Contabo product ID of the instance
+	ProductId string `json:"productId"`
+
+	// ProductName is the Contabo product name of the instance
+	// +optional
+	ProductName string `json:"productName,omitempty"`
+
+	// ProductType is the Contabo product type (storage class) of the instance
+	// +optional
+	ProductType string `json:"productType,omitempty"`
+
+	// PriceClass is the tariff of the product in the known catalog, products of a class share the same price.
+	// Empty for products missing from the catalog.
+	// +optional
+	PriceClass string `json:"priceClass,omitempty"`
+
+	// CpuCores is the number of CPU cores of the instance
+	CpuCores int64 `json:"cpuCores"`
+
+	// RamMb is the memory of the instance in MB
+	RamMb int64 `json:"ramMb"`
+
+	// DiskMb is the disk size of the instance in MB
+	DiskMb int64 `json:"diskMb"`
+
+	// Region is the region of the instance
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// DataCenter is the data center of the instance
+	// +optional
+	DataCenter string `json:"dataCenter,omitempty"`
+
+	// ImageId is the image the instance is installed with
+	ImageId string `json:"imageId"`
+
+	// ImageName is the name of the image
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+
+	// ImageOsType is the OS type of the image
+	// +optional
+	ImageOsType string `json:"imageOsType,omitempty"`
+
+	// ImageVersion is the version of the image
+	// +optional
+	ImageVersion string `json:"imageVersion,omitempty"`
+
+	// ImageCreationDate is the build date of the image
+	// +optional
+	ImageCreationDate *metav1.Time `json:"imageCreationDate,omitempty"`
+
+	// ImageLastModifiedDate is the last modification date of the image
+	// +optional
+	ImageLastModifiedDate *metav1.Time `json:"imageLastModifiedDate,omitempty"`
+
+	// StandardImage is true for images provided by Contabo
+	// +optional
+	StandardImage bool `json:"standardImage,omitempty"`
+}
+
+// ContaboTagStatus is a Contabo tag assigned to the instance of a machine
+type ContaboTagStatus struct {
+	// Name is the name of the tag
+	Name string `json:"name"`
+
+	// TagId is the ID of the tag in Contabo
+	TagId int64 `json:"tagId"`
+
+	// Provider is true for the provider tag of a partially adopted cluster, it stays on the instances released by
+	// the machine so that they remain in the managed reuse pool
+	// +optional
+	Provider bool `json:"provider,omitempty"`
+}
+
+// ContaboAuditEntry is a Contabo audit entry of a resource used by a machine
+type ContaboAuditEntry struct {
+	// Resource is the kind of audited resource (Instance or Image)
+	Resource string `json:"resource"`
+
+	// ResourceId is the identifier of the audited resource
+	ResourceId string `json:"resourceId"`
+
+	// Action is the type of the action
+	Action string `json:"action"`
+
+	// Username is the name of the user who led to the change
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// ChangedBy is the id of the user who performed the change
+	// +optional
+	ChangedBy string `json:"changedBy,omitempty"`
+
+	// Timestamp is when the change took place
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// RequestId is the requestId of the API call which led to the change
+	// +optional
+	RequestId string `json:"requestId,omitempty"`
+
+	// TraceId is the traceId of the API call which led to the change, identifying the installation of the provider
+	// which made it
+	// +optional
+	TraceId string `json:"traceId,omitempty"`
+
+	// Changes lists the names of the changed fields
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+}
+
 type ContaboMachineInitializationStatus struct {
 	// Provisioned indicates if the initialization is complete
 	Provisioned bool `json:"provisioned"`
@@ -120,19 +893,223 @@ const (
 	ContaboInstanceProvisioningTypeReuseOrCreate ContaboInstanceProvisioningType = "ReuseOrCreate"
 )
 
+// ContaboMachineReusePolicy is what the deletion of a machine does to its instance
+// +kubebuilder:validation:Enum=Reinstall;Cancel
+type ContaboMachineReusePolicy string
+
+const (
+	// ContaboMachineReusePolicyReinstall returns the instance to the free pool, it is reinstalled by the next machine
+	// claiming it
+	ContaboMachineReusePolicyReinstall ContaboMachineReusePolicy = "Reinstall"
+	// ContaboMachineReusePolicyCancel cancels the contract of the instance
+	ContaboMachineReusePolicyCancel ContaboMachineReusePolicy = "Cancel"
+)
+
+// FreePoolTag is the Contabo tag of the instances returned to the free pool by the deletion of their machine, it is
+// removed when a machine claims the instance
+const FreePoolTag = "capc-free-pool"
+
+// ContaboPowerState is the power state of a Contabo instance
+// +kubebuilder:validation:Enum=Running;Stopped
+type ContaboPowerState string
+
+const (
+	// ContaboPowerStateRunning is a started instance
+	ContaboPowerStateRunning ContaboPowerState = "Running"
+	// ContaboPowerStateStopped is a stopped instance, still billed by Contabo but not consuming resources of the cluster
+	ContaboPowerStateStopped ContaboPowerState = "Stopped"
+)
+
 // ContaboInstanceSpec defines the desired state of a Contabo instance
 type ContaboInstanceSpec struct {
 	// Name will force the controller to chooose an instance with the specified name
 	// +optional
 	Name *string `json:"name,omitempty"`
 
-	// ProductID is the Contabo product ID (instance type)
+	// ProductID is the Contabo product ID (instance type). It is immutable once set. Defaults to DefaultProductId
+	// when the machine is created, unless it reuses a given or existing instance.
 	// +optional
-	ProductId *string `json:"productId,omitempty"`
+	ProductId *ContaboProductId `json:"productId,omitempty"`
+
+	// ImageId is the Contabo image the instance is installed with, a standard image of the ContaboCatalog or a
+	// custom image of the account. The bootstrap requires an Ubuntu image with cloud-init. It is immutable once set.
+	// Defaults to DefaultImageId when the machine is created.
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
+	// +optional
+	ImageId *string `json:"imageId,omitempty"`
 
 	// Field to know if should create a new instance or reuse an existing one
 	// +optional
 	ProvisioningType *ContaboInstanceProvisioningType `json:"provisioningType,omitempty"`
+
+	// FirstBootProbe configures an optional SSH probe verifying sshd and cloud-init health after boot
+	// +optional
+	FirstBootProbe *ContaboFirstBootProbeSpec `json:"firstBootProbe,omitempty"`
+
+	// Snapshots configures the snapshot limit and retention of the instance
+	// +optional
+	Snapshots *ContaboSnapshotRetentionSpec `json:"snapshots,omitempty"`
+
+	// AdditionalIPv4 orders additional public IPv4 addresses with the instance, e.g. for egress IPs or ingress.
+	// Contabo only adds them when the instance is ordered, reused instances must already hold enough of them.
+	// +optional
+	AdditionalIPv4 *ContaboAdditionalIPv4Spec `json:"additionalIPv4,omitempty"`
+
+	// AddOns are the Contabo add-ons ordered with the instance by their ID, e.g. the automated backups, extra
+	// storage or additional IPv4 addresses. Contabo only orders most add-ons with new instances: reused instances must
+	// already hold them, unless the add-on sets the UpgradeInstance attribute adding it to an existing instance.
+	// +listType=map
+	// +listMapKey=id
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	AddOns []ContaboAddOnSpec `json:"addOns,omitempty"`
+
+	// Tags are the names of the Contabo tags assigned to the instance, the tags are created when missing. Removed
+	// tags are only unassigned when they were assigned by the provider.
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:Pattern=`^[A-Za-z0-9:_-]+$`
+	// +kubebuilder:validation:items:MaxLength=255
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// SshKeySecretName is the name of the Secret, in the namespace of the machine, holding the SSH key pair of the
+	// instance in its id_ed25519 and id_ed25519.pub keys, or id_rsa and id_rsa.pub. The public key is registered as a
+	// Contabo secret and replaces the SSH key of the cluster on the instance when it is created or reinstalled.
+	// +optional
+	SshKeySecretName string `json:"sshKeySecretName,omitempty"`
+
+	// ProviderSpecific holds raw properties of the Contabo CreateInstance request passed through as is when the
+	// instance is ordered, e.g. a license, an application or properties of a newer API the provider does not know
+	// yet. The properties set by the provider win, the add-ons are merged one by one. The properties are validated
+	// against the bundled Contabo OpenAPI specification at admission: unknown properties are warned about, values
+	// not matching the specification are denied.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	ProviderSpecific *apiextensionsv1.JSON `json:"providerSpecific,omitempty"`
+}
+
+// ContaboAdditionalIPv4Spec defines the additional public IPv4 addresses of a Contabo instance
+type ContaboAdditionalIPv4Spec struct {
+	// Count is the number of additional IPv4 addresses of the instance
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	// +optional
+	Count int32 `json:"count,omitempty"`
+
+	// AddOnId is the Contabo add-on ID of the additional IPv4 address, ordered Count times with the instance.
+	// When unset the additional IPs add-on of the instance order is requested, which provides a single address.
+	// +optional
+	AddOnId *int64 `json:"addOnId,omitempty"`
+
+	// Configure renders a cloud-init service adding the additional addresses to the public interface at every boot.
+	// Disable it when the addresses are configured by other means, e.g. a load balancer or egress gateway.
+	// +kubebuilder:default=true
+	// +optional
+	Configure *bool `json:"configure,omitempty"`
+}
+
+// ContaboPrivateNetworkingAddOnId is the Contabo add-on ID of private networking, ordered by the provider with every
+// instance
+const ContaboPrivateNetworkingAddOnId = 1477
+
+// ContaboAddOnUpgrade is the attribute of the UpgradeInstance request of the Contabo API adding an add-on to an
+// existing instance
+// +kubebuilder:validation:Enum=Backup
+type ContaboAddOnUpgrade string
+
+const (
+	// ContaboAddOnUpgradeBackup adds the automated backups add-on
+	ContaboAddOnUpgradeBackup ContaboAddOnUpgrade = "Backup"
+)
+
+// ContaboAddOnSpec defines a Contabo add-on ordered with an instance
+type ContaboAddOnSpec struct {
+	// Id is the Contabo add-on ID, listed with the add-ons of the Contabo product list
+	// +kubebuilder:validation:Minimum=1
+	Id int64 `json:"id"`
+
+	// Quantity is the number of add-ons ordered with the instance
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Quantity int32 `json:"quantity,omitempty"`
+
+	// Upgrade is the attribute of the UpgradeInstance request adding the add-on to the reused instances without it,
+	// e.g. Backup when Id is the automated backups add-on. An instance is upgraded once, the add-on being billed
+	// with every upgrade. Without it, the reused instances without the add-on are skipped.
+	// +optional
+	Upgrade ContaboAddOnUpgrade `json:"upgrade,omitempty"`
+}
+
+// ContaboAddOnUpgradeStatus defines an add-on of spec.instance.addOns added to the instance with UpgradeInstance
+type ContaboAddOnUpgradeStatus struct {
+	// Id is the Contabo add-on ID
+	Id int64 `json:"id"`
+
+	// InstanceId is the instance the add-on was added to, the add-ons of a replaced instance are upgraded again
+	InstanceId int64 `json:"instanceId"`
+
+	// UpgradeTime is the time the UpgradeInstance request or the instance order was accepted
+	UpgradeTime metav1.Time `json:"upgradeTime"`
+}
+
+// ContaboIPv4AddressRole is the role of a public IPv4 address of an instance
+// +kubebuilder:validation:Enum=Primary;Secondary
+type ContaboIPv4AddressRole string
+
+const (
+	// ContaboIPv4AddressRolePrimary is the main address of the instance, the source of its outgoing traffic
+	ContaboIPv4AddressRolePrimary ContaboIPv4AddressRole = "Primary"
+	// ContaboIPv4AddressRoleSecondary is an additional address of the instance
+	ContaboIPv4AddressRoleSecondary ContaboIPv4AddressRole = "Secondary"
+)
+
+// ContaboIPv4AddressStatus defines a public IPv4 address of an instance
+type ContaboIPv4AddressStatus struct {
+	// Address is the IPv4 address
+	Address string `json:"address"`
+
+	// Role is Primary for the main address of the instance and Secondary for the additional addresses
+	Role ContaboIPv4AddressRole `json:"role"`
+
+	// NetmaskCidr is the prefix length of the address
+	// +optional
+	NetmaskCidr int32 `json:"netmaskCidr,omitempty"`
+
+	// Gateway is the gateway of the address
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+}
+
+// ContaboSnapshotRetentionSpec defines how many snapshots an instance may hold and how they are pruned
+type ContaboSnapshotRetentionSpec struct {
+	// MaxSnapshots is the number of snapshots allowed by Contabo for the instance product
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSnapshots int32 `json:"maxSnapshots,omitempty"`
+
+	// PruneOldest deletes the oldest snapshots taken by the provider to make room for a new one.
+	// Snapshots taken outside of the provider are never deleted.
+	// +kubebuilder:default=true
+	// +optional
+	PruneOldest *bool `json:"pruneOldest,omitempty"`
+}
+
+// ContaboFirstBootProbeSpec defines the SSH first-boot health probe of a Contabo instance
+type ContaboFirstBootProbeSpec struct {
+	// Enabled runs the probe, using the cluster SSH key, before the machine is marked available
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// TimeoutSeconds is the time allowed for sshd and cloud-init to become healthy before the instance is marked as failed
+	// +kubebuilder:default=900
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 }
 
 // ContaboInstanceStatus defines the observed state of a Contabo instance
@@ -146,6 +1123,8 @@ type ContaboInstanceStatus = InstanceResponse
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine ready status"
 // +kubebuilder:printcolumn:name="ProviderID",type="string",JSONPath=".spec.providerID",description="Contabo instance ID"
 // +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object which owns this ContaboMachine"
+// +kubebuilder:printcolumn:name="Operation",type="string",JSONPath=".status.lastOperation.type",description="Last operation of the controller",priority=1
+// +kubebuilder:printcolumn:name="Operation State",type="string",JSONPath=".status.lastOperation.state",description="State of the last operation of the controller",priority=1
 // +kubebuilder:resource:path=contabomachines,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
 
@@ -180,30 +1159,11 @@ func init() {
 }
 
 // GetConditions returns the conditions of the ContaboMachine.
-func (m *ContaboMachine) GetConditions() clusterv1.Conditions {
-	conditions := make(clusterv1.Conditions, len(m.Status.Conditions))
-	for i, condition := range m.Status.Conditions {
-		conditions[i] = clusterv1.Condition{
-			Type:               clusterv1.ConditionType(condition.Type),
-			Status:             corev1.ConditionStatus(condition.Status),
-			LastTransitionTime: condition.LastTransitionTime,
-			Reason:             condition.Reason,
-			Message:            condition.Message,
-		}
-	}
-	return conditions
+func (m *ContaboMachine) GetConditions() []metav1.Condition {
+	return m.Status.Conditions
 }
 
 // SetConditions sets the conditions of the ContaboMachine.
-func (m *ContaboMachine) SetConditions(conditions clusterv1.Conditions) {
-	m.Status.Conditions = make([]metav1.Condition, len(conditions))
-	for i, condition := range conditions {
-		m.Status.Conditions[i] = metav1.Condition{
-			Type:               string(condition.Type),
-			Status:             metav1.ConditionStatus(condition.Status),
-			LastTransitionTime: condition.LastTransitionTime,
-			Reason:             condition.Reason,
-			Message:            condition.Message,
-		}
-	}
+func (m *ContaboMachine) SetConditions(conditions []metav1.Condition) {
+	m.Status.Conditions = conditions
 }
@@ -0,0 +1,171 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MachinePoolTemplateHashLabel is set on the ContaboMachines of a ContaboMachinePool to the hash of the template they
+// were created from, the machines of another hash are replaced.
+const MachinePoolTemplateHashLabel = "infrastructure.cluster.x-k8s.io/machine-pool-template-hash"
+
+// ParkedInstanceAnnotation is set by a ContaboMachinePool on the machines it scales down, their instance is released
+// with a reserved display name instead of the empty display name of the free instances so that only the pool claims
+// it back. It is also set on the machines created to claim a parked instance, until they claim it.
+const ParkedInstanceAnnotation = "infrastructure.cluster.x-k8s.io/parked-instance"
+
+// MaxParkedInstances is the maximum number of instances parked by a ContaboMachinePool, the instances of the machines
+// scaled down beyond it are released to the free instances.
+const MaxParkedInstances = 100
+
+// ContaboMachinePoolSpec defines the desired state of ContaboMachinePool
+type ContaboMachinePoolSpec struct {
+	// ProviderIDList are the provider IDs of the ready instances of the pool. It is set by the provider and reported
+	// to the MachinePool by Cluster API.
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// Template is the ContaboMachine created for each replica of the MachinePool. The instance name must not be set,
+	// the instances of the pool are named after their machine. Changing the template replaces the machines of the pool.
+	// +kubebuilder:validation:XValidation:rule="!has(self.spec.instance.name) || self.spec.instance.name == ''",message="the instance name of a machine pool template must be empty"
+	Template ContaboMachineTemplateResource `json:"template"`
+
+	// MaxSurge is the number of machines created above the replicas of the MachinePool while its machines are
+	// replaced, an outdated machine is deleted once a machine of the new template is ready.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSurge int32 `json:"maxSurge,omitempty"`
+}
+
+// ContaboMachinePoolInitializationStatus reports the initialization of the ContaboMachinePool
+type ContaboMachinePoolInitializationStatus struct {
+	// Provisioned is true once the replicas of the pool were ready for the first time
+	Provisioned bool `json:"provisioned"`
+}
+
+// ContaboMachinePoolParkedInstance is an instance released by a machine of the pool which was scaled down
+type ContaboMachinePoolParkedInstance struct {
+	// Name is the Contabo name of the instance, set as the instance name of the machine claiming it back
+	Name string `json:"name"`
+
+	// MachineName is the ContaboMachine which released the instance, the instance is claimed once it is deleted
+	MachineName string `json:"machineName"`
+
+	// TemplateHash is the template hash of the machine, the instances of a previous template are not claimed back
+	TemplateHash string `json:"templateHash"`
+
+	// ParkedTime is the time the machine was scaled down
+	ParkedTime metav1.Time `json:"parkedTime"`
+}
+
+// ContaboMachinePoolStatus defines the observed state of ContaboMachinePool.
+type ContaboMachinePoolStatus struct {
+	// Ready is true when all the replicas of the pool are ready and created from the current template
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Initialization reports the initialization of the pool to Cluster API
+	// +optional
+	Initialization *ContaboMachinePoolInitializationStatus `json:"initialization,omitempty"`
+
+	// Replicas is the number of ContaboMachines of the pool which are not being deleted
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of ready ContaboMachines of the pool
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// UpToDateReplicas is the number of ContaboMachines of the pool created from the current template
+	// +optional
+	UpToDateReplicas int32 `json:"upToDateReplicas"`
+
+	// TemplateHash is the hash of the current template, set in the MachinePoolTemplateHashLabel of its machines
+	// +optional
+	TemplateHash string `json:"templateHash,omitempty"`
+
+	// InfrastructureMachineKind is the kind of the machines of the pool, Cluster API creates a Machine for each of them
+	// +optional
+	InfrastructureMachineKind string `json:"infrastructureMachineKind,omitempty"`
+
+	// ParkedInstances are the instances released by the machines of the pool when it scaled down, e.g. to zero. They
+	// are reserved with the ParkedInstanceAnnotation and claimed back by name by the machines created when the pool
+	// scales up, so that scaling up does not wait for new instances. The instances of the machines deleted with the
+	// Cancel reuse policy are not parked. The instances parked by a previous template, or no longer listed, return to
+	// the free instances.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	ParkedInstances []ContaboMachinePoolParkedInstance `json:"parkedInstances,omitempty"`
+
+	// Conditions defines current service state of the ContaboMachinePool.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this ContaboMachinePool belongs"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Machines of the pool"
+// +kubebuilder:printcolumn:name="Ready Replicas",type="integer",JSONPath=".status.readyReplicas",description="Ready machines of the pool"
+// +kubebuilder:printcolumn:name="Up-to-date Replicas",type="integer",JSONPath=".status.upToDateReplicas",description="Machines of the current template"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine pool ready status"
+// +kubebuilder:resource:path=contabomachinepools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+
+// ContaboMachinePool is the Schema for the contabomachinepools API
+type ContaboMachinePool struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of ContaboMachinePool
+	// +required
+	Spec ContaboMachinePoolSpec `json:"spec"`
+
+	// status defines the observed state of ContaboMachinePool
+	// +optional
+	Status ContaboMachinePoolStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContaboMachinePoolList contains a list of ContaboMachinePool
+type ContaboMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContaboMachinePool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContaboMachinePool{}, &ContaboMachinePoolList{})
+}
+
+// GetConditions returns the conditions of the ContaboMachinePool.
+func (p *ContaboMachinePool) GetConditions() []metav1.Condition {
+	return p.Status.Conditions
+}
+
+// SetConditions sets the conditions of the ContaboMachinePool.
+func (p *ContaboMachinePool) SetConditions(conditions []metav1.Condition) {
+	p.Status.Conditions = conditions
+}
@@ -18,6 +18,7 @@ package v1beta2
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -26,21 +27,40 @@ import (
 // ContaboMachineTemplateSpec defines the desired state of ContaboMachineTemplate
 type ContaboMachineTemplateSpec struct {
 	Template ContaboMachineTemplateResource `json:"template"`
+
+	// RolloutStrategy overrides the rollout strategy of the ContaboCluster for the machines of the template. With
+	// ReinstallInPlace, the updates of the dns, instance.imageId, networkConfig, nodeLabels, nodeTaints and privateOnly
+	// fields of the template are copied to its worker machines, whose instances are then reinstalled one at a time.
+	// +optional
+	RolloutStrategy *ContaboRolloutStrategy `json:"rolloutStrategy,omitempty"`
 }
 
 // ContaboMachineTemplateResource describes the data needed to create a ContaboMachine from a template
 type ContaboMachineTemplateResource struct {
+	// ObjectMeta holds the labels and annotations Cluster API copies to the ContaboMachines created from the
+	// template, e.g. by a KubeadmControlPlane, a MachineSet or the topology of a ClusterClass.
+	// +optional
+	ObjectMeta clusterv1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// Spec is the spec of the ContaboMachines created from the template. The providerID and failureDomain of a
+	// machine are set by the provider and cannot be set in a template.
+	// +required
 	Spec ContaboMachineSpec `json:"spec"`
 }
 
 // ContaboMachineTemplateStatus defines the observed state of ContaboMachineTemplate.
-// NOTE: ContaboMachineTemplate is a template resource and does not have a status.
 type ContaboMachineTemplateStatus struct {
+	// Conditions defines current service state of the ContaboMachineTemplate, e.g. the availability of its product.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 // +kubebuilder:resource:path=contabomachinetemplates,scope=Namespaced,categories=cluster-api
+// +kubebuilder:printcolumn:name="Product Available",type="string",JSONPath=".status.conditions[?(@.type=='ProductAvailable')].status",description="Product of the template is available"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ContaboMachineTemplate"
+// +kubebuilder:storageversion
 
 // ContaboMachineTemplate is the Schema for the contabomachinetemplates API
 type ContaboMachineTemplate struct {
@@ -48,11 +68,15 @@ type ContaboMachineTemplate struct {
 
 	// metadata is a standard object metadata
 	// +optional
-	metav1.ObjectMeta `json:"metadata,omitempty"`
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
 
 	// spec defines the desired state of ContaboMachineTemplate
 	// +required
 	Spec ContaboMachineTemplateSpec `json:"spec"`
+
+	// status defines the observed state of ContaboMachineTemplate
+	// +optional
+	Status ContaboMachineTemplateStatus `json:"status,omitempty,omitzero"`
 }
 
 // +kubebuilder:object:root=true
@@ -67,3 +91,13 @@ type ContaboMachineTemplateList struct {
 func init() {
 	SchemeBuilder.Register(&ContaboMachineTemplate{}, &ContaboMachineTemplateList{})
 }
+
+// GetConditions returns the conditions of the ContaboMachineTemplate.
+func (t *ContaboMachineTemplate) GetConditions() []metav1.Condition {
+	return t.Status.Conditions
+}
+
+// SetConditions sets the conditions of the ContaboMachineTemplate.
+func (t *ContaboMachineTemplate) SetConditions(conditions []metav1.Condition) {
+	t.Status.Conditions = conditions
+}
@@ -3334,6 +3334,7 @@ type VipResponseResourceType string
 type VipResponseType string
 
 // InstanceStatus Instance's status
+// +kubebuilder:validation:Enum=error;installing;manual_provisioning;other;pending_payment;product_not_available;provisioning;rescue;reset_password;running;stopped;uninstalled;unknown;verification_required
 type InstanceStatus string
 
 // RetrieveImageListParams defines parameters for RetrieveImageList.
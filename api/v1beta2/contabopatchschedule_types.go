@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PatchRequestAnnotation is set by a ContaboPatchSchedule on the ContaboMachines of the current wave.
+	// Its value identifies the patch run, the machine is cordoned, upgraded, restarted and uncordoned once per run.
+	PatchRequestAnnotation = "infrastructure.cluster.x-k8s.io/patch-request"
+
+	// DefaultPatchCommand is the upgrade command run on the instances when the schedule does not set one.
+	DefaultPatchCommand = "sudo unattended-upgrade"
+)
+
+// ContaboPatchScheduleSpec defines the desired state of ContaboPatchSchedule.
+type ContaboPatchScheduleSpec struct {
+	// ClusterName is the name of the Cluster API cluster whose ContaboMachines are patched.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+
+	// Selector restricts the patched ContaboMachines, all ContaboMachines of the cluster are patched by default.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Window is the recurring maintenance window in which patch waves are started.
+	Window ContaboPatchWindow `json:"window"`
+
+	// WaveSize is the number of machines patched at the same time. Control plane machines are always patched one at a time, after the workers.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WaveSize int32 `json:"waveSize,omitempty"`
+
+	// Command is the upgrade command run over SSH on the instances. Default is "sudo unattended-upgrade".
+	// +optional
+	Command string `json:"command,omitempty"`
+
+	// Reboot restarts the instances through the Contabo API after the upgrade.
+	// +kubebuilder:default=true
+	// +optional
+	Reboot *bool `json:"reboot,omitempty"`
+
+	// Suspend stops starting new waves, machines being patched are completed.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// ContaboPatchWindow defines a recurring maintenance window.
+type ContaboPatchWindow struct {
+	// Days are the days of the week the window opens, every day by default.
+	// +optional
+	Days []ContaboPatchWeekday `json:"days,omitempty"`
+
+	// Start is the UTC time of day the window opens, formatted as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is the length of the window. Default is 2h.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// ContaboPatchWeekday is a day of the week
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type ContaboPatchWeekday string
+
+// ContaboPatchScheduleStatus defines the observed state of ContaboPatchSchedule.
+type ContaboPatchScheduleStatus struct {
+	// CurrentRun identifies the patch run of the current or last window.
+	// +optional
+	CurrentRun string `json:"currentRun,omitempty"`
+
+	// InProgressMachines are the ContaboMachines of the current wave.
+	// +optional
+	InProgressMachines []string `json:"inProgressMachines,omitempty"`
+
+	// PatchedMachines are the ContaboMachines patched during the current run.
+	// +optional
+	PatchedMachines []string `json:"patchedMachines,omitempty"`
+
+	// FailedMachines are the ContaboMachines which failed to be patched during the current run.
+	// +optional
+	FailedMachines []string `json:"failedMachines,omitempty"`
+
+	// LastCompletionTime is the time the last run patched all the machines.
+	// +optional
+	LastCompletionTime *metav1.Time `json:"lastCompletionTime,omitempty"`
+
+	// NextWindowTime is the time the next window opens.
+	// +optional
+	NextWindowTime *metav1.Time `json:"nextWindowTime,omitempty"`
+
+	// Conditions defines current service state of the ContaboPatchSchedule.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster patched by the schedule"
+// +kubebuilder:printcolumn:name="Start",type="string",JSONPath=".spec.window.start",description="UTC time the window opens"
+// +kubebuilder:printcolumn:name="Patching",type="string",JSONPath=".status.conditions[?(@.type=='Patching')].reason",description="Patch run state"
+// +kubebuilder:printcolumn:name="Next Window",type="date",JSONPath=".status.nextWindowTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=contabopatchschedules,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+
+// ContaboPatchSchedule is the Schema for the contabopatchschedules API
+type ContaboPatchSchedule struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of ContaboPatchSchedule
+	// +required
+	Spec ContaboPatchScheduleSpec `json:"spec"`
+
+	// status defines the observed state of ContaboPatchSchedule
+	// +optional
+	Status ContaboPatchScheduleStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContaboPatchScheduleList contains a list of ContaboPatchSchedule
+type ContaboPatchScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContaboPatchSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContaboPatchSchedule{}, &ContaboPatchScheduleList{})
+}
+
+// GetConditions returns the conditions of the ContaboPatchSchedule.
+func (s *ContaboPatchSchedule) GetConditions() []metav1.Condition {
+	return s.Status.Conditions
+}
+
+// SetConditions sets the conditions of the ContaboPatchSchedule.
+func (s *ContaboPatchSchedule) SetConditions(conditions []metav1.Condition) {
+	s.Status.Conditions = conditions
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContaboProviderSettingsName is the name of the ContaboProviderSettings singleton.
+const ContaboProviderSettingsName = "default"
+
+// ContaboProviderSettingsSpec defines the runtime tunables of the provider controllers.
+// Unset values fall back to the controller defaults.
+type ContaboProviderSettingsSpec struct {
+	// Intervals tunes the requeue intervals per operation type.
+	// +optional
+	Intervals ContaboRequeueIntervals `json:"intervals,omitempty"`
+
+	// Timeouts tunes the timeouts per operation type.
+	// +optional
+	Timeouts ContaboTimeouts `json:"timeouts,omitempty"`
+
+	// Bootstrap tunes how the bootstrap data is passed to the instances.
+	// +optional
+	Bootstrap ContaboBootstrapSettings `json:"bootstrap,omitempty"`
+
+	// Notifications forwards the critical provider events to HTTP sinks, in addition to the Kubernetes Events.
+	// +optional
+	Notifications ContaboNotificationSettings `json:"notifications,omitempty"`
+
+	// APICalls tunes the detection of the reconciliations sending too many Contabo API requests.
+	// +optional
+	APICalls ContaboAPICallSettings `json:"apiCalls,omitempty"`
+}
+
+// ContaboAPICallSettings defines when the reconciliations of a resource are reported as a hot loop.
+type ContaboAPICallSettings struct {
+	// HotLoopThreshold is the number of Contabo API requests a single reconciliation of a ContaboCluster or
+	// ContaboMachine may send. Default is 50.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HotLoopThreshold *int32 `json:"hotLoopThreshold,omitempty"`
+
+	// HotLoopReconciles is the number of reconciliations in a row above the threshold before a ContaboAPIHotLoop
+	// warning event is recorded on the resource. Default is 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HotLoopReconciles *int32 `json:"hotLoopReconciles,omitempty"`
+}
+
+// ContaboNotificationFormat is the payload format of a notification sink
+// +kubebuilder:validation:Enum=Generic;Slack
+type ContaboNotificationFormat string
+
+const (
+	// ContaboNotificationFormatGeneric posts the notification as a JSON object
+	ContaboNotificationFormatGeneric ContaboNotificationFormat = "Generic"
+
+	// ContaboNotificationFormatSlack posts the notification as a Slack incoming webhook message, also understood by
+	// Mattermost, Rocket.Chat and Discord (/slack suffix)
+	ContaboNotificationFormatSlack ContaboNotificationFormat = "Slack"
+)
+
+// ContaboNotificationSettings defines where the critical provider events are forwarded.
+type ContaboNotificationSettings struct {
+	// Sinks are the HTTP endpoints the critical events are posted to.
+	// +optional
+	Sinks []ContaboNotificationSink `json:"sinks,omitempty"`
+}
+
+// ContaboNotificationSink defines an HTTP endpoint the critical events are posted to.
+type ContaboNotificationSink struct {
+	// Name identifies the sink in the logs.
+	Name string `json:"name"`
+
+	// Format is the payload format. Default is Generic.
+	// +optional
+	Format ContaboNotificationFormat `json:"format,omitempty"`
+
+	// URL is the endpoint the events are posted to. Prefer URLSecretRef for URLs holding a token.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// URLSecretRef references a Secret holding the endpoint in its 'url' key.
+	// +optional
+	URLSecretRef *corev1.SecretReference `json:"urlSecretRef,omitempty"`
+
+	// Reasons are the reasons of the Warning events posted to the sink. Default is every critical event: orphaned
+	// resources, Contabo credential failures and terminal machine failures.
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// ContaboUserDataCompression is when the bootstrap data is compressed
+// +kubebuilder:validation:Enum=Auto;Always;Never
+type ContaboUserDataCompression string
+
+const (
+	// ContaboUserDataCompressionAuto compresses the bootstrap data larger than the maximum user data size
+	ContaboUserDataCompressionAuto ContaboUserDataCompression = "Auto"
+
+	// ContaboUserDataCompressionAlways compresses the bootstrap data of every instance
+	ContaboUserDataCompressionAlways ContaboUserDataCompression = "Always"
+
+	// ContaboUserDataCompressionNever never compresses the bootstrap data
+	ContaboUserDataCompressionNever ContaboUserDataCompression = "Never"
+)
+
+// ContaboBootstrapSettings defines how the bootstrap data is passed to the instances.
+type ContaboBootstrapSettings struct {
+	// MaxUserDataSize is the largest user data, in bytes, sent to the Contabo API. Larger bootstrap data is
+	// compressed, then fetched by the instance from the object storage when still too large. Default is 16384, at
+	// most 65536, the limit of the Contabo API.
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65536
+	// +optional
+	MaxUserDataSize *int32 `json:"maxUserDataSize,omitempty"`
+
+	// Compression is when the bootstrap data is gzipped into a cloud-init MIME multipart user data: Auto when it is
+	// larger than MaxUserDataSize, Always or Never. Default is Auto.
+	// +optional
+	Compression ContaboUserDataCompression `json:"compression,omitempty"`
+
+	// ObjectStorage is the S3 compatible bucket the bootstrap data still larger than MaxUserDataSize once
+	// compressed is uploaded to. The instance only receives a minimal user data including the bootstrap data from
+	// a short-lived signed URL. The bootstrap data is sent as is when not set.
+	// +optional
+	ObjectStorage *ContaboBootstrapObjectStorage `json:"objectStorage,omitempty"`
+
+	// InstanceToken replaces the kubeadm bootstrap token shared by the machines of a cluster with a short-lived
+	// token created for each instance in the workload cluster, deleted once the node joined. Shared tokens are
+	// sent as is when not set.
+	// +optional
+	InstanceToken *ContaboBootstrapInstanceToken `json:"instanceToken,omitempty"`
+}
+
+// ContaboBootstrapInstanceToken defines the kubeadm bootstrap tokens created for each instance.
+type ContaboBootstrapInstanceToken struct {
+	// TTL is the time the instance has to join the cluster with its token, from the reinstall request. Default is
+	// 1h.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// ContaboBootstrapObjectStorage defines the S3 compatible bucket holding the large bootstrap data.
+type ContaboBootstrapObjectStorage struct {
+	// Endpoint is the URL of the S3 compatible object storage, e.g. https://eu2.contabostorage.com.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+
+	// Region is the region used to sign the object storage requests. Default is us-east-1.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the bucket the bootstrap data is uploaded to, addressed path-style. It should not be public.
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:Required
+	Bucket string `json:"bucket"`
+
+	// CredentialsSecretRef references the Secret holding the S3 credentials of the bucket, in the accessKey and
+	// secretKey keys.
+	// +kubebuilder:validation:Required
+	CredentialsSecretRef corev1.SecretReference `json:"credentialsSecretRef"`
+
+	// URLExpiry is how long the signed URL of the bootstrap data is valid. The bootstrap data is deleted from the
+	// bucket once cloud-init finished. Default is 1h.
+	// +optional
+	URLExpiry *metav1.Duration `json:"urlExpiry,omitempty"`
+}
+
+// ContaboRequeueIntervals defines the requeue intervals per operation type.
+type ContaboRequeueIntervals struct {
+	// Dependency is the interval while waiting for other resources (Cluster, ContaboCluster, bootstrap data...). Default is 15s.
+	// +optional
+	Dependency *metav1.Duration `json:"dependency,omitempty"`
+
+	// ResourceCreation is the interval after a Contabo resource was requested, or after a recoverable failure. Default is 5s.
+	// +optional
+	ResourceCreation *metav1.Duration `json:"resourceCreation,omitempty"`
+
+	// Instance is the interval while waiting for an instance to be provisioned, assigned or to join the cluster. Default is 15s.
+	// +optional
+	Instance *metav1.Duration `json:"instance,omitempty"`
+
+	// CloudInit is the interval while waiting for cloud-init to finish. Default is 20s.
+	// +optional
+	CloudInit *metav1.Duration `json:"cloudInit,omitempty"`
+
+	// Ssh is the interval before retrying a failed SSH connection. Default is 15s.
+	// +optional
+	Ssh *metav1.Duration `json:"ssh,omitempty"`
+
+	// Quota is the interval while waiting for ContaboQuota capacity. Default is 30s.
+	// +optional
+	Quota *metav1.Duration `json:"quota,omitempty"`
+
+	// AuditTrail is the interval between two refreshes of the ContaboMachine audit trail. Default is 10m.
+	// +optional
+	AuditTrail *metav1.Duration `json:"auditTrail,omitempty"`
+
+	// Inventory is the interval between two refreshes of the ContaboAccountInventory. Default is 5m.
+	// +optional
+	Inventory *metav1.Duration `json:"inventory,omitempty"`
+
+	// Catalog is the interval between two refreshes of the ContaboCatalog. Default is 1h.
+	// +optional
+	Catalog *metav1.Duration `json:"catalog,omitempty"`
+
+	// OutOfStock is the interval between two instance orders while the product is out of stock in every allowed
+	// failure domain. Default is 5m.
+	// +optional
+	OutOfStock *metav1.Duration `json:"outOfStock,omitempty"`
+
+	// Host is the interval between two checks of the host system of the ContaboMachine instances. Default is 10m.
+	// +optional
+	Host *metav1.Duration `json:"host,omitempty"`
+}
+
+// ContaboTimeouts defines the timeouts per operation type.
+type ContaboTimeouts struct {
+	// SshDial is the timeout to establish an SSH connection to an instance. Default is 10s.
+	// +optional
+	SshDial *metav1.Duration `json:"sshDial,omitempty"`
+
+	// FirstBootProbe is the first-boot probe timeout used when the ContaboMachine does not set one. Default is 15m.
+	// +optional
+	FirstBootProbe *metav1.Duration `json:"firstBootProbe,omitempty"`
+
+	// InstanceOrder is the time an ordered instance has to appear and leave provisioning before the order is
+	// cancelled and replaced. Default is 30m.
+	// +optional
+	InstanceOrder *metav1.Duration `json:"instanceOrder,omitempty"`
+
+	// Shutdown is the time a ContaboMachine stopped with its power state has to shut down gracefully before it is
+	// stopped. Default is 5m.
+	// +optional
+	Shutdown *metav1.Duration `json:"shutdown,omitempty"`
+
+	// StuckDeletion is the time a ContaboCluster or ContaboMachine may spend deleting before it is counted in the
+	// capc_stuck_deletions metric. Default is 1h.
+	// +optional
+	StuckDeletion *metav1.Duration `json:"stuckDeletion,omitempty"`
+}
+
+// ContaboProviderSettingsStatus defines the observed state of ContaboProviderSettings.
+type ContaboProviderSettingsStatus struct {
+	// ObservedGeneration is the latest generation applied by the controllers.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions defines current service state of the ContaboProviderSettings.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Applied",type="string",JSONPath=".status.conditions[?(@.type=='ProviderSettingsApplied')].status",description="Settings applied by the controllers"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=contaboprovidersettings,scope=Cluster,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="ContaboProviderSettings is a singleton and must be named 'default'"
+
+// ContaboProviderSettings is the Schema for the contaboprovidersettings API
+type ContaboProviderSettings struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the runtime tunables of ContaboProviderSettings
+	// +optional
+	Spec ContaboProviderSettingsSpec `json:"spec,omitempty"`
+
+	// status defines the observed state of ContaboProviderSettings
+	// +optional
+	Status ContaboProviderSettingsStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContaboProviderSettingsList contains a list of ContaboProviderSettings
+type ContaboProviderSettingsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContaboProviderSettings `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContaboProviderSettings{}, &ContaboProviderSettingsList{})
+}
+
+// GetConditions returns the conditions of the ContaboProviderSettings.
+func (s *ContaboProviderSettings) GetConditions() []metav1.Condition {
+	return s.Status.Conditions
+}
+
+// SetConditions sets the conditions of the ContaboProviderSettings.
+func (s *ContaboProviderSettings) SetConditions(conditions []metav1.Condition) {
+	s.Status.Conditions = conditions
+}
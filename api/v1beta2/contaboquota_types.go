@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContaboQuotaSpec defines the limits of Contabo resources that can be consumed in a namespace.
+// A nil limit means unlimited.
+type ContaboQuotaSpec struct {
+	// MaxInstances is the maximum number of Contabo instances used by ContaboMachines in the namespace.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxInstances *int32 `json:"maxInstances,omitempty"`
+
+	// MaxCpuCores is the maximum total number of vCPU cores of the instances in the namespace.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxCpuCores *int64 `json:"maxCpuCores,omitempty"`
+
+	// MaxRamMb is the maximum total RAM in MB of the instances in the namespace.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRamMb *int64 `json:"maxRamMb,omitempty"`
+
+	// MaxPrivateNetworks is the maximum number of private networks used by ContaboClusters in the namespace.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxPrivateNetworks *int32 `json:"maxPrivateNetworks,omitempty"`
+}
+
+// ContaboQuotaUsage contains the Contabo resources currently consumed in a namespace.
+type ContaboQuotaUsage struct {
+	// Instances is the number of Contabo instances used or ordered by ContaboMachines.
+	Instances int32 `json:"instances"`
+
+	// CpuCores is the total number of vCPU cores of the instances.
+	CpuCores int64 `json:"cpuCores"`
+
+	// RamMb is the total RAM in MB of the instances.
+	RamMb int64 `json:"ramMb"`
+
+	// PrivateNetworks is the number of private networks used by ContaboClusters.
+	PrivateNetworks int32 `json:"privateNetworks"`
+}
+
+// ContaboQuotaStatus defines the observed state of ContaboQuota.
+type ContaboQuotaStatus struct {
+	// Used is the current resource consumption in the namespace.
+	// +optional
+	Used ContaboQuotaUsage `json:"used"`
+
+	// Conditions defines current service state of the ContaboQuota.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Instances",type="string",JSONPath=".status.used.instances",description="Instances used"
+// +kubebuilder:printcolumn:name="Max Instances",type="string",JSONPath=".spec.maxInstances",description="Maximum instances"
+// +kubebuilder:printcolumn:name="CPU",type="string",JSONPath=".status.used.cpuCores",description="vCPU cores used"
+// +kubebuilder:printcolumn:name="RAM (MB)",type="string",JSONPath=".status.used.ramMb",description="RAM used in MB"
+// +kubebuilder:printcolumn:name="Private Networks",type="string",JSONPath=".status.used.privateNetworks",description="Private networks used"
+// +kubebuilder:resource:path=contaboquotas,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+
+// ContaboQuota is the Schema for the contaboquotas API
+type ContaboQuota struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the limits of ContaboQuota
+	// +required
+	Spec ContaboQuotaSpec `json:"spec"`
+
+	// status defines the observed usage of ContaboQuota
+	// +optional
+	Status ContaboQuotaStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContaboQuotaList contains a list of ContaboQuota
+type ContaboQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContaboQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContaboQuota{}, &ContaboQuotaList{})
+}
+
+// GetConditions returns the conditions of the ContaboQuota.
+func (q *ContaboQuota) GetConditions() []metav1.Condition {
+	return q.Status.Conditions
+}
+
+// SetConditions sets the conditions of the ContaboQuota.
+func (q *ContaboQuota) SetConditions(conditions []metav1.Condition) {
+	q.Status.Conditions = conditions
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+// Finalizers are named after the API group with the lowercase kind as path, as recommended by Kubernetes to avoid
+// conflicts with the other finalizer writers
+const (
+	// ClusterFinalizer allows the controller to clean up resources associated with ContaboCluster before
+	// removing it from the apiserver.
+	ClusterFinalizer = "infrastructure.cluster.x-k8s.io/contabocluster"
+
+	// MachineFinalizer allows the controller to clean up resources associated with ContaboMachine before
+	// removing it from the apiserver.
+	MachineFinalizer = "infrastructure.cluster.x-k8s.io/contabomachine"
+
+	// MachinePoolFinalizer allows the controller to delete the machines of a ContaboMachinePool before removing it
+	// from the apiserver.
+	MachinePoolFinalizer = "infrastructure.cluster.x-k8s.io/contabomachinepool"
+)
+
+// Legacy finalizers set by the versions of the provider naming them without path. They are replaced with the current
+// finalizers by the next reconciliation and removed with them, so that the resources of these versions stay deletable.
+const (
+	LegacyClusterFinalizer     = "contabocluster.infrastructure.cluster.x-k8s.io"
+	LegacyMachineFinalizer     = "contabomachine.infrastructure.cluster.x-k8s.io"
+	LegacyMachinePoolFinalizer = "contabomachinepool.infrastructure.cluster.x-k8s.io"
+)
+
+// LegacyFinalizer returns the legacy name of a finalizer, an empty string when it was never renamed
+func LegacyFinalizer(finalizer string) string {
+	switch finalizer {
+	case ClusterFinalizer:
+		return LegacyClusterFinalizer
+	case MachineFinalizer:
+		return LegacyMachineFinalizer
+	case MachinePoolFinalizer:
+		return LegacyMachinePoolFinalizer
+	}
+	return ""
+}
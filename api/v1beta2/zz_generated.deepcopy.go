@@ -21,6 +21,8 @@ limitations under the License.
 package v1beta2
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	corev1beta2 "sigs.k8s.io/cluster-api/api/core/v1beta2"
@@ -445,7 +447,332 @@ func (in *ClientSecretResponse) DeepCopy() *ClientSecretResponse {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboCluster) DeepCopyInto(out *ContaboCluster) {
+func (in *ContaboAPICallSettings) DeepCopyInto(out *ContaboAPICallSettings) {
+	*out = *in
+	if in.HotLoopThreshold != nil {
+		in, out := &in.HotLoopThreshold, &out.HotLoopThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HotLoopReconciles != nil {
+		in, out := &in.HotLoopReconciles, &out.HotLoopReconciles
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAPICallSettings.
+func (in *ContaboAPICallSettings) DeepCopy() *ContaboAPICallSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAPICallSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboAccountInventory) DeepCopyInto(out *ContaboAccountInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAccountInventory.
+func (in *ContaboAccountInventory) DeepCopy() *ContaboAccountInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAccountInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboAccountInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboAccountInventoryList) DeepCopyInto(out *ContaboAccountInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContaboAccountInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAccountInventoryList.
+func (in *ContaboAccountInventoryList) DeepCopy() *ContaboAccountInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAccountInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboAccountInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboAccountInventorySpec) DeepCopyInto(out *ContaboAccountInventorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAccountInventorySpec.
+func (in *ContaboAccountInventorySpec) DeepCopy() *ContaboAccountInventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAccountInventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboAccountInventoryStatus) DeepCopyInto(out *ContaboAccountInventoryStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	out.Summary = in.Summary
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]ContaboInventoryItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateNetworks != nil {
+		in, out := &in.PrivateNetworks, &out.PrivateNetworks
+		*out = make([]ContaboInventoryItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ContaboInventoryItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.VIPs != nil {
+		in, out := &in.VIPs, &out.VIPs
+		*out = make([]ContaboInventoryItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAccountInventoryStatus.
+func (in *ContaboAccountInventoryStatus) DeepCopy() *ContaboAccountInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAccountInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboAddOnSpec) DeepCopyInto(out *ContaboAddOnSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAddOnSpec.
+func (in *ContaboAddOnSpec) DeepCopy() *ContaboAddOnSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAddOnSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboAddOnUpgradeStatus) DeepCopyInto(out *ContaboAddOnUpgradeStatus) {
+	*out = *in
+	in.UpgradeTime.DeepCopyInto(&out.UpgradeTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAddOnUpgradeStatus.
+func (in *ContaboAddOnUpgradeStatus) DeepCopy() *ContaboAddOnUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAddOnUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboAdditionalIPv4Spec) DeepCopyInto(out *ContaboAdditionalIPv4Spec) {
+	*out = *in
+	if in.AddOnId != nil {
+		in, out := &in.AddOnId, &out.AddOnId
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Configure != nil {
+		in, out := &in.Configure, &out.Configure
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAdditionalIPv4Spec.
+func (in *ContaboAdditionalIPv4Spec) DeepCopy() *ContaboAdditionalIPv4Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAdditionalIPv4Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboAuditEntry) DeepCopyInto(out *ContaboAuditEntry) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboAuditEntry.
+func (in *ContaboAuditEntry) DeepCopy() *ContaboAuditEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboAuditEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboBastionSpec) DeepCopyInto(out *ContaboBastionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboBastionSpec.
+func (in *ContaboBastionSpec) DeepCopy() *ContaboBastionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboBastionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboBootstrapInstanceToken) DeepCopyInto(out *ContaboBootstrapInstanceToken) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboBootstrapInstanceToken.
+func (in *ContaboBootstrapInstanceToken) DeepCopy() *ContaboBootstrapInstanceToken {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboBootstrapInstanceToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboBootstrapObjectStorage) DeepCopyInto(out *ContaboBootstrapObjectStorage) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	if in.URLExpiry != nil {
+		in, out := &in.URLExpiry, &out.URLExpiry
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboBootstrapObjectStorage.
+func (in *ContaboBootstrapObjectStorage) DeepCopy() *ContaboBootstrapObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboBootstrapObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboBootstrapSettings) DeepCopyInto(out *ContaboBootstrapSettings) {
+	*out = *in
+	if in.MaxUserDataSize != nil {
+		in, out := &in.MaxUserDataSize, &out.MaxUserDataSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(ContaboBootstrapObjectStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceToken != nil {
+		in, out := &in.InstanceToken, &out.InstanceToken
+		*out = new(ContaboBootstrapInstanceToken)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboBootstrapSettings.
+func (in *ContaboBootstrapSettings) DeepCopy() *ContaboBootstrapSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboBootstrapSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboBootstrapTokenStatus) DeepCopyInto(out *ContaboBootstrapTokenStatus) {
+	*out = *in
+	in.ExpirationTime.DeepCopyInto(&out.ExpirationTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboBootstrapTokenStatus.
+func (in *ContaboBootstrapTokenStatus) DeepCopy() *ContaboBootstrapTokenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboBootstrapTokenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCatalog) DeepCopyInto(out *ContaboCatalog) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -453,18 +780,2065 @@ func (in *ContaboCluster) DeepCopyInto(out *ContaboCluster) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCluster.
-func (in *ContaboCluster) DeepCopy() *ContaboCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCatalog.
+func (in *ContaboCatalog) DeepCopy() *ContaboCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCatalogDataCenter) DeepCopyInto(out *ContaboCatalogDataCenter) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCatalogDataCenter.
+func (in *ContaboCatalogDataCenter) DeepCopy() *ContaboCatalogDataCenter {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCatalogDataCenter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCatalogImage) DeepCopyInto(out *ContaboCatalogImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCatalogImage.
+func (in *ContaboCatalogImage) DeepCopy() *ContaboCatalogImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCatalogImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCatalogList) DeepCopyInto(out *ContaboCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContaboCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCatalogList.
+func (in *ContaboCatalogList) DeepCopy() *ContaboCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCatalogProduct) DeepCopyInto(out *ContaboCatalogProduct) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCatalogProduct.
+func (in *ContaboCatalogProduct) DeepCopy() *ContaboCatalogProduct {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCatalogProduct)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCatalogSnapshot) DeepCopyInto(out *ContaboCatalogSnapshot) {
+	*out = *in
+	in.RecordedAt.DeepCopyInto(&out.RecordedAt)
+	if in.ImageCreationDate != nil {
+		in, out := &in.ImageCreationDate, &out.ImageCreationDate
+		*out = (*in).DeepCopy()
+	}
+	if in.ImageLastModifiedDate != nil {
+		in, out := &in.ImageLastModifiedDate, &out.ImageLastModifiedDate
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCatalogSnapshot.
+func (in *ContaboCatalogSnapshot) DeepCopy() *ContaboCatalogSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCatalogSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCatalogSpec) DeepCopyInto(out *ContaboCatalogSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCatalogSpec.
+func (in *ContaboCatalogSpec) DeepCopy() *ContaboCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCatalogStatus) DeepCopyInto(out *ContaboCatalogStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.DataCenters != nil {
+		in, out := &in.DataCenters, &out.DataCenters
+		*out = make([]ContaboCatalogDataCenter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]ContaboRegion, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ContaboCatalogImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make([]ContaboCatalogProduct, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCatalogStatus.
+func (in *ContaboCatalogStatus) DeepCopy() *ContaboCatalogStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCatalogStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCluster) DeepCopyInto(out *ContaboCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCluster.
+func (in *ContaboCluster) DeepCopy() *ContaboCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboClusterDeletionPreviewStatus) DeepCopyInto(out *ContaboClusterDeletionPreviewStatus) {
+	*out = *in
+	in.ComputeTime.DeepCopyInto(&out.ComputeTime)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ContaboDeletionPreviewResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterDeletionPreviewStatus.
+func (in *ContaboClusterDeletionPreviewStatus) DeepCopy() *ContaboClusterDeletionPreviewStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboClusterDeletionPreviewStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboClusterInitializationStatus) DeepCopyInto(out *ContaboClusterInitializationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterInitializationStatus.
+func (in *ContaboClusterInitializationStatus) DeepCopy() *ContaboClusterInitializationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboClusterInitializationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboClusterList) DeepCopyInto(out *ContaboClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContaboCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterList.
+func (in *ContaboClusterList) DeepCopy() *ContaboClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboClusterMachineConditionSummary) DeepCopyInto(out *ContaboClusterMachineConditionSummary) {
+	*out = *in
+	if in.Machines != nil {
+		in, out := &in.Machines, &out.Machines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterMachineConditionSummary.
+func (in *ContaboClusterMachineConditionSummary) DeepCopy() *ContaboClusterMachineConditionSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboClusterMachineConditionSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboClusterMachinesStatus) DeepCopyInto(out *ContaboClusterMachinesStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ContaboClusterMachineConditionSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterMachinesStatus.
+func (in *ContaboClusterMachinesStatus) DeepCopy() *ContaboClusterMachinesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboClusterMachinesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboClusterRefreshStatus) DeepCopyInto(out *ContaboClusterRefreshStatus) {
+	*out = *in
+	in.RequestTime.DeepCopyInto(&out.RequestTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterRefreshStatus.
+func (in *ContaboClusterRefreshStatus) DeepCopy() *ContaboClusterRefreshStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboClusterRefreshStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboClusterSpec) DeepCopyInto(out *ContaboClusterSpec) {
+	*out = *in
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	in.PrivateNetwork.DeepCopyInto(&out.PrivateNetwork)
+	if in.MaxConcurrentOperations != nil {
+		in, out := &in.MaxConcurrentOperations, &out.MaxConcurrentOperations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(ContaboPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PartialAdoption != nil {
+		in, out := &in.PartialAdoption, &out.PartialAdoption
+		*out = new(ContaboPartialAdoptionSpec)
+		**out = **in
+	}
+	if in.EtcdBackup != nil {
+		in, out := &in.EtcdBackup, &out.EtcdBackup
+		*out = new(ContaboEtcdBackupSpec)
+		**out = **in
+	}
+	if in.ControlPlaneGang != nil {
+		in, out := &in.ControlPlaneGang, &out.ControlPlaneGang
+		*out = new(ContaboControlPlaneGangSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionConfirmationThreshold != nil {
+		in, out := &in.DeletionConfirmationThreshold, &out.DeletionConfirmationThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(ContaboCredentialsReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterSpec.
+func (in *ContaboClusterSpec) DeepCopy() *ContaboClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboClusterStatus) DeepCopyInto(out *ContaboClusterStatus) {
+	*out = *in
+	if in.PrivateNetwork != nil {
+		in, out := &in.PrivateNetwork, &out.PrivateNetwork
+		*out = new(ContaboPrivateNetworkStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrivateNetworkSharedWith != nil {
+		in, out := &in.PrivateNetworkSharedWith, &out.PrivateNetworkSharedWith
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateNetworkHints != nil {
+		in, out := &in.PrivateNetworkHints, &out.PrivateNetworkHints
+		*out = new(ContaboPrivateNetworkHintsStatus)
+		**out = **in
+	}
+	if in.UnavailableProducts != nil {
+		in, out := &in.UnavailableProducts, &out.UnavailableProducts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnavailableProductsLastObserved != nil {
+		in, out := &in.UnavailableProductsLastObserved, &out.UnavailableProductsLastObserved
+		*out = (*in).DeepCopy()
+	}
+	if in.SshKey != nil {
+		in, out := &in.SshKey, &out.SshKey
+		*out = new(ContaboSshKeyStatus)
+		**out = **in
+	}
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = new(ContaboKubeconfigStatus)
+		**out = **in
+	}
+	if in.ControlPlaneEndpointVIP != nil {
+		in, out := &in.ControlPlaneEndpointVIP, &out.ControlPlaneEndpointVIP
+		*out = new(ContaboControlPlaneEndpointVIPStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Initialization != nil {
+		in, out := &in.Initialization, &out.Initialization
+		*out = new(ContaboClusterInitializationStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]corev1beta2.FailureDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Refresh != nil {
+		in, out := &in.Refresh, &out.Refresh
+		*out = new(ContaboClusterRefreshStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Machines != nil {
+		in, out := &in.Machines, &out.Machines
+		*out = new(ContaboClusterMachinesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EtcdBackup != nil {
+		in, out := &in.EtcdBackup, &out.EtcdBackup
+		*out = new(ContaboEtcdBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPreview != nil {
+		in, out := &in.DeletionPreview, &out.DeletionPreview
+		*out = new(ContaboClusterDeletionPreviewStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastOperation != nil {
+		in, out := &in.LastOperation, &out.LastOperation
+		*out = new(ContaboLastOperation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterStatus.
+func (in *ContaboClusterStatus) DeepCopy() *ContaboClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboControlPlaneEndpointVIPStatus) DeepCopyInto(out *ContaboControlPlaneEndpointVIPStatus) {
+	*out = *in
+	if in.InstanceId != nil {
+		in, out := &in.InstanceId, &out.InstanceId
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboControlPlaneEndpointVIPStatus.
+func (in *ContaboControlPlaneEndpointVIPStatus) DeepCopy() *ContaboControlPlaneEndpointVIPStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboControlPlaneEndpointVIPStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboControlPlaneGangOrder) DeepCopyInto(out *ContaboControlPlaneGangOrder) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboControlPlaneGangOrder.
+func (in *ContaboControlPlaneGangOrder) DeepCopy() *ContaboControlPlaneGangOrder {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboControlPlaneGangOrder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboControlPlaneGangSpec) DeepCopyInto(out *ContaboControlPlaneGangSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboControlPlaneGangSpec.
+func (in *ContaboControlPlaneGangSpec) DeepCopy() *ContaboControlPlaneGangSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboControlPlaneGangSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboControlPlaneGangStatus) DeepCopyInto(out *ContaboControlPlaneGangStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Orders != nil {
+		in, out := &in.Orders, &out.Orders
+		*out = make([]ContaboControlPlaneGangOrder, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboControlPlaneGangStatus.
+func (in *ContaboControlPlaneGangStatus) DeepCopy() *ContaboControlPlaneGangStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboControlPlaneGangStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboCredentialsReference) DeepCopyInto(out *ContaboCredentialsReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboCredentialsReference.
+func (in *ContaboCredentialsReference) DeepCopy() *ContaboCredentialsReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboCredentialsReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboDNSSpec) DeepCopyInto(out *ContaboDNSSpec) {
+	*out = *in
+	if in.Nameservers != nil {
+		in, out := &in.Nameservers, &out.Nameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboDNSSpec.
+func (in *ContaboDNSSpec) DeepCopy() *ContaboDNSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboDNSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboDeletionPreviewResource) DeepCopyInto(out *ContaboDeletionPreviewResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboDeletionPreviewResource.
+func (in *ContaboDeletionPreviewResource) DeepCopy() *ContaboDeletionPreviewResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboDeletionPreviewResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboEtcdBackupObjectStorage) DeepCopyInto(out *ContaboEtcdBackupObjectStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboEtcdBackupObjectStorage.
+func (in *ContaboEtcdBackupObjectStorage) DeepCopy() *ContaboEtcdBackupObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboEtcdBackupObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboEtcdBackupSpec) DeepCopyInto(out *ContaboEtcdBackupSpec) {
+	*out = *in
+	out.ObjectStorage = in.ObjectStorage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboEtcdBackupSpec.
+func (in *ContaboEtcdBackupSpec) DeepCopy() *ContaboEtcdBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboEtcdBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboEtcdBackupStatus) DeepCopyInto(out *ContaboEtcdBackupStatus) {
+	*out = *in
+	if in.LastSnapshotTime != nil {
+		in, out := &in.LastSnapshotTime, &out.LastSnapshotTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboEtcdBackupStatus.
+func (in *ContaboEtcdBackupStatus) DeepCopy() *ContaboEtcdBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboEtcdBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboFirstBootProbeSpec) DeepCopyInto(out *ContaboFirstBootProbeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboFirstBootProbeSpec.
+func (in *ContaboFirstBootProbeSpec) DeepCopy() *ContaboFirstBootProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboFirstBootProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboIPv4AddressStatus) DeepCopyInto(out *ContaboIPv4AddressStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboIPv4AddressStatus.
+func (in *ContaboIPv4AddressStatus) DeepCopy() *ContaboIPv4AddressStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboIPv4AddressStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboInstanceOrderStatus) DeepCopyInto(out *ContaboInstanceOrderStatus) {
+	*out = *in
+	if in.OrderTime != nil {
+		in, out := &in.OrderTime, &out.OrderTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboInstanceOrderStatus.
+func (in *ContaboInstanceOrderStatus) DeepCopy() *ContaboInstanceOrderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboInstanceOrderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboInstanceSpec) DeepCopyInto(out *ContaboInstanceSpec) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProductId != nil {
+		in, out := &in.ProductId, &out.ProductId
+		*out = new(ContaboProductId)
+		**out = **in
+	}
+	if in.ImageId != nil {
+		in, out := &in.ImageId, &out.ImageId
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProvisioningType != nil {
+		in, out := &in.ProvisioningType, &out.ProvisioningType
+		*out = new(ContaboInstanceProvisioningType)
+		**out = **in
+	}
+	if in.FirstBootProbe != nil {
+		in, out := &in.FirstBootProbe, &out.FirstBootProbe
+		*out = new(ContaboFirstBootProbeSpec)
+		**out = **in
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = new(ContaboSnapshotRetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalIPv4 != nil {
+		in, out := &in.AdditionalIPv4, &out.AdditionalIPv4
+		*out = new(ContaboAdditionalIPv4Spec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddOns != nil {
+		in, out := &in.AddOns, &out.AddOns
+		*out = make([]ContaboAddOnSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProviderSpecific != nil {
+		in, out := &in.ProviderSpecific, &out.ProviderSpecific
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboInstanceSpec.
+func (in *ContaboInstanceSpec) DeepCopy() *ContaboInstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboInstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboInventoryCount) DeepCopyInto(out *ContaboInventoryCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboInventoryCount.
+func (in *ContaboInventoryCount) DeepCopy() *ContaboInventoryCount {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboInventoryCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboInventoryItem) DeepCopyInto(out *ContaboInventoryItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboInventoryItem.
+func (in *ContaboInventoryItem) DeepCopy() *ContaboInventoryItem {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboInventoryItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboInventorySummary) DeepCopyInto(out *ContaboInventorySummary) {
+	*out = *in
+	out.Instances = in.Instances
+	out.PrivateNetworks = in.PrivateNetworks
+	out.Images = in.Images
+	out.VIPs = in.VIPs
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboInventorySummary.
+func (in *ContaboInventorySummary) DeepCopy() *ContaboInventorySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboInventorySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboKubeconfigStatus) DeepCopyInto(out *ContaboKubeconfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboKubeconfigStatus.
+func (in *ContaboKubeconfigStatus) DeepCopy() *ContaboKubeconfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboKubeconfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboLastOperation) DeepCopyInto(out *ContaboLastOperation) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboLastOperation.
+func (in *ContaboLastOperation) DeepCopy() *ContaboLastOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboLastOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboLifecycleHook) DeepCopyInto(out *ContaboLifecycleHook) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboLifecycleHook.
+func (in *ContaboLifecycleHook) DeepCopy() *ContaboLifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboLifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboLifecycleHook) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboLifecycleHookCallStatus) DeepCopyInto(out *ContaboLifecycleHookCallStatus) {
+	*out = *in
+	if in.LastCallTime != nil {
+		in, out := &in.LastCallTime, &out.LastCallTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboLifecycleHookCallStatus.
+func (in *ContaboLifecycleHookCallStatus) DeepCopy() *ContaboLifecycleHookCallStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboLifecycleHookCallStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboLifecycleHookList) DeepCopyInto(out *ContaboLifecycleHookList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContaboLifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboLifecycleHookList.
+func (in *ContaboLifecycleHookList) DeepCopy() *ContaboLifecycleHookList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboLifecycleHookList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboLifecycleHookList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboLifecycleHookSpec) DeepCopyInto(out *ContaboLifecycleHookSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Points != nil {
+		in, out := &in.Points, &out.Points
+		*out = make([]ContaboLifecycleHookPoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.URLSecretRef != nil {
+		in, out := &in.URLSecretRef, &out.URLSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Blocking != nil {
+		in, out := &in.Blocking, &out.Blocking
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboLifecycleHookSpec.
+func (in *ContaboLifecycleHookSpec) DeepCopy() *ContaboLifecycleHookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboLifecycleHookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachine) DeepCopyInto(out *ContaboMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachine.
+func (in *ContaboMachine) DeepCopy() *ContaboMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineBootLogsStatus) DeepCopyInto(out *ContaboMachineBootLogsStatus) {
+	*out = *in
+	in.CollectionTime.DeepCopyInto(&out.CollectionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineBootLogsStatus.
+func (in *ContaboMachineBootLogsStatus) DeepCopy() *ContaboMachineBootLogsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineBootLogsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineHostStatus) DeepCopyInto(out *ContaboMachineHostStatus) {
+	*out = *in
+	if in.PreviousVHostId != nil {
+		in, out := &in.PreviousVHostId, &out.PreviousVHostId
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastChangeTime != nil {
+		in, out := &in.LastChangeTime, &out.LastChangeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastChecked != nil {
+		in, out := &in.LastChecked, &out.LastChecked
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineHostStatus.
+func (in *ContaboMachineHostStatus) DeepCopy() *ContaboMachineHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineInitializationStatus) DeepCopyInto(out *ContaboMachineInitializationStatus) {
+	*out = *in
+	if in.ErrorMessage != nil {
+		in, out := &in.ErrorMessage, &out.ErrorMessage
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineInitializationStatus.
+func (in *ContaboMachineInitializationStatus) DeepCopy() *ContaboMachineInitializationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineInitializationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineJobStatus) DeepCopyInto(out *ContaboMachineJobStatus) {
+	*out = *in
+	in.CreationTime.DeepCopyInto(&out.CreationTime)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineJobStatus.
+func (in *ContaboMachineJobStatus) DeepCopy() *ContaboMachineJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineList) DeepCopyInto(out *ContaboMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContaboMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineList.
+func (in *ContaboMachineList) DeepCopy() *ContaboMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineMigrationStatus) DeepCopyInto(out *ContaboMachineMigrationStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineMigrationStatus.
+func (in *ContaboMachineMigrationStatus) DeepCopy() *ContaboMachineMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachinePatchStatus) DeepCopyInto(out *ContaboMachinePatchStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.RebootTime != nil {
+		in, out := &in.RebootTime, &out.RebootTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachinePatchStatus.
+func (in *ContaboMachinePatchStatus) DeepCopy() *ContaboMachinePatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachinePatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachinePlacementStatus) DeepCopyInto(out *ContaboMachinePlacementStatus) {
+	*out = *in
+	if in.OutOfStock != nil {
+		in, out := &in.OutOfStock, &out.OutOfStock
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastOutOfStockTime != nil {
+		in, out := &in.LastOutOfStockTime, &out.LastOutOfStockTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachinePlacementStatus.
+func (in *ContaboMachinePlacementStatus) DeepCopy() *ContaboMachinePlacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachinePlacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachinePool) DeepCopyInto(out *ContaboMachinePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachinePool.
+func (in *ContaboMachinePool) DeepCopy() *ContaboMachinePool {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboMachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachinePoolInitializationStatus) DeepCopyInto(out *ContaboMachinePoolInitializationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachinePoolInitializationStatus.
+func (in *ContaboMachinePoolInitializationStatus) DeepCopy() *ContaboMachinePoolInitializationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachinePoolInitializationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachinePoolList) DeepCopyInto(out *ContaboMachinePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContaboMachinePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachinePoolList.
+func (in *ContaboMachinePoolList) DeepCopy() *ContaboMachinePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachinePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboMachinePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachinePoolParkedInstance) DeepCopyInto(out *ContaboMachinePoolParkedInstance) {
+	*out = *in
+	in.ParkedTime.DeepCopyInto(&out.ParkedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachinePoolParkedInstance.
+func (in *ContaboMachinePoolParkedInstance) DeepCopy() *ContaboMachinePoolParkedInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachinePoolParkedInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachinePoolSpec) DeepCopyInto(out *ContaboMachinePoolSpec) {
+	*out = *in
+	if in.ProviderIDList != nil {
+		in, out := &in.ProviderIDList, &out.ProviderIDList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachinePoolSpec.
+func (in *ContaboMachinePoolSpec) DeepCopy() *ContaboMachinePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachinePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachinePoolStatus) DeepCopyInto(out *ContaboMachinePoolStatus) {
+	*out = *in
+	if in.Initialization != nil {
+		in, out := &in.Initialization, &out.Initialization
+		*out = new(ContaboMachinePoolInitializationStatus)
+		**out = **in
+	}
+	if in.ParkedInstances != nil {
+		in, out := &in.ParkedInstances, &out.ParkedInstances
+		*out = make([]ContaboMachinePoolParkedInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachinePoolStatus.
+func (in *ContaboMachinePoolStatus) DeepCopy() *ContaboMachinePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachinePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineRolloutStatus) DeepCopyInto(out *ContaboMachineRolloutStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineRolloutStatus.
+func (in *ContaboMachineRolloutStatus) DeepCopy() *ContaboMachineRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineSpec) DeepCopyInto(out *ContaboMachineSpec) {
+	*out = *in
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailureDomain != nil {
+		in, out := &in.FailureDomain, &out.FailureDomain
+		*out = new(string)
+		**out = **in
+	}
+	in.Instance.DeepCopyInto(&out.Instance)
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetworkConfig != nil {
+		in, out := &in.NetworkConfig, &out.NetworkConfig
+		*out = new(string)
+		**out = **in
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(ContaboDNSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrivateOnly != nil {
+		in, out := &in.PrivateOnly, &out.PrivateOnly
+		*out = new(ContaboPrivateOnlySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineSpec.
+func (in *ContaboMachineSpec) DeepCopy() *ContaboMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineStatus) DeepCopyInto(out *ContaboMachineStatus) {
+	*out = *in
+	if in.Instance != nil {
+		in, out := &in.Instance, &out.Instance
+		*out = new(ContaboInstanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]corev1beta2.MachineAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Initialization != nil {
+		in, out := &in.Initialization, &out.Initialization
+		*out = new(ContaboMachineInitializationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceOrder != nil {
+		in, out := &in.InstanceOrder, &out.InstanceOrder
+		*out = new(ContaboInstanceOrderStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneGang != nil {
+		in, out := &in.ControlPlaneGang, &out.ControlPlaneGang
+		*out = new(ContaboControlPlaneGangStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FirstBootProbeStartTime != nil {
+		in, out := &in.FirstBootProbeStartTime, &out.FirstBootProbeStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AuditTrail != nil {
+		in, out := &in.AuditTrail, &out.AuditTrail
+		*out = make([]ContaboAuditEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AuditTrailLastUpdated != nil {
+		in, out := &in.AuditTrailLastUpdated, &out.AuditTrailLastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]ContaboTagStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagsLastUpdated != nil {
+		in, out := &in.TagsLastUpdated, &out.TagsLastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.SshKey != nil {
+		in, out := &in.SshKey, &out.SshKey
+		*out = new(ContaboSshKeyStatus)
+		**out = **in
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(ContaboMachineMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Patch != nil {
+		in, out := &in.Patch, &out.Patch
+		*out = new(ContaboMachinePatchStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotCount != nil {
+		in, out := &in.SnapshotCount, &out.SnapshotCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ShutdownTime != nil {
+		in, out := &in.ShutdownTime, &out.ShutdownTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CatalogSnapshot != nil {
+		in, out := &in.CatalogSnapshot, &out.CatalogSnapshot
+		*out = new(ContaboCatalogSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(ContaboMachinePlacementStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(ContaboUserDataStatus)
+		**out = **in
+	}
+	if in.BootstrapToken != nil {
+		in, out := &in.BootstrapToken, &out.BootstrapToken
+		*out = new(ContaboBootstrapTokenStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Host != nil {
+		in, out := &in.Host, &out.Host
+		*out = new(ContaboMachineHostStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootLogs != nil {
+		in, out := &in.BootLogs, &out.BootLogs
+		*out = new(ContaboMachineBootLogsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ContaboMachineRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]ContaboMachineJobStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = make([]ContaboLifecycleHookCallStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IPv4Addresses != nil {
+		in, out := &in.IPv4Addresses, &out.IPv4Addresses
+		*out = make([]ContaboIPv4AddressStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AddOnUpgrades != nil {
+		in, out := &in.AddOnUpgrades, &out.AddOnUpgrades
+		*out = make([]ContaboAddOnUpgradeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastOperation != nil {
+		in, out := &in.LastOperation, &out.LastOperation
+		*out = new(ContaboLastOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineStatus.
+func (in *ContaboMachineStatus) DeepCopy() *ContaboMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineTemplate) DeepCopyInto(out *ContaboMachineTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplate.
+func (in *ContaboMachineTemplate) DeepCopy() *ContaboMachineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboMachineTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineTemplateList) DeepCopyInto(out *ContaboMachineTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContaboMachineTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplateList.
+func (in *ContaboMachineTemplateList) DeepCopy() *ContaboMachineTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboMachineTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineTemplateResource) DeepCopyInto(out *ContaboMachineTemplateResource) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplateResource.
+func (in *ContaboMachineTemplateResource) DeepCopy() *ContaboMachineTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineTemplateSpec) DeepCopyInto(out *ContaboMachineTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(ContaboRolloutStrategy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplateSpec.
+func (in *ContaboMachineTemplateSpec) DeepCopy() *ContaboMachineTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboMachineTemplateStatus) DeepCopyInto(out *ContaboMachineTemplateStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplateStatus.
+func (in *ContaboMachineTemplateStatus) DeepCopy() *ContaboMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboNotificationSettings) DeepCopyInto(out *ContaboNotificationSettings) {
+	*out = *in
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]ContaboNotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboNotificationSettings.
+func (in *ContaboNotificationSettings) DeepCopy() *ContaboNotificationSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboNotificationSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboNotificationSink) DeepCopyInto(out *ContaboNotificationSink) {
+	*out = *in
+	if in.URLSecretRef != nil {
+		in, out := &in.URLSecretRef, &out.URLSecretRef
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboNotificationSink.
+func (in *ContaboNotificationSink) DeepCopy() *ContaboNotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboNotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPartialAdoptionSpec) DeepCopyInto(out *ContaboPartialAdoptionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPartialAdoptionSpec.
+func (in *ContaboPartialAdoptionSpec) DeepCopy() *ContaboPartialAdoptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPartialAdoptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPatchSchedule) DeepCopyInto(out *ContaboPatchSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPatchSchedule.
+func (in *ContaboPatchSchedule) DeepCopy() *ContaboPatchSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPatchSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboPatchSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPatchScheduleList) DeepCopyInto(out *ContaboPatchScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContaboPatchSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPatchScheduleList.
+func (in *ContaboPatchScheduleList) DeepCopy() *ContaboPatchScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPatchScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContaboPatchScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPatchScheduleSpec) DeepCopyInto(out *ContaboPatchScheduleSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Window.DeepCopyInto(&out.Window)
+	if in.Reboot != nil {
+		in, out := &in.Reboot, &out.Reboot
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPatchScheduleSpec.
+func (in *ContaboPatchScheduleSpec) DeepCopy() *ContaboPatchScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPatchScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPatchScheduleStatus) DeepCopyInto(out *ContaboPatchScheduleStatus) {
+	*out = *in
+	if in.InProgressMachines != nil {
+		in, out := &in.InProgressMachines, &out.InProgressMachines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PatchedMachines != nil {
+		in, out := &in.PatchedMachines, &out.PatchedMachines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedMachines != nil {
+		in, out := &in.FailedMachines, &out.FailedMachines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCompletionTime != nil {
+		in, out := &in.LastCompletionTime, &out.LastCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextWindowTime != nil {
+		in, out := &in.NextWindowTime, &out.NextWindowTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPatchScheduleStatus.
+func (in *ContaboPatchScheduleStatus) DeepCopy() *ContaboPatchScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPatchScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPatchWindow) DeepCopyInto(out *ContaboPatchWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]ContaboPatchWeekday, len(*in))
+		copy(*out, *in)
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPatchWindow.
+func (in *ContaboPatchWindow) DeepCopy() *ContaboPatchWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPatchWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPlacementSpec) DeepCopyInto(out *ContaboPlacementSpec) {
+	*out = *in
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]ContaboRegion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPlacementSpec.
+func (in *ContaboPlacementSpec) DeepCopy() *ContaboPlacementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPlacementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPrivateNetworkCNISpec) DeepCopyInto(out *ContaboPrivateNetworkCNISpec) {
+	*out = *in
+	if in.EncapsulationOverhead != nil {
+		in, out := &in.EncapsulationOverhead, &out.EncapsulationOverhead
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPrivateNetworkCNISpec.
+func (in *ContaboPrivateNetworkCNISpec) DeepCopy() *ContaboPrivateNetworkCNISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPrivateNetworkCNISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPrivateNetworkHintsStatus) DeepCopyInto(out *ContaboPrivateNetworkHintsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPrivateNetworkHintsStatus.
+func (in *ContaboPrivateNetworkHintsStatus) DeepCopy() *ContaboPrivateNetworkHintsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPrivateNetworkHintsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPrivateNetworkSpec) DeepCopyInto(out *ContaboPrivateNetworkSpec) {
+	*out = *in
+	if in.CreateIfNotExists != nil {
+		in, out := &in.CreateIfNotExists, &out.CreateIfNotExists
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CNI != nil {
+		in, out := &in.CNI, &out.CNI
+		*out = new(ContaboPrivateNetworkCNISpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPrivateNetworkSpec.
+func (in *ContaboPrivateNetworkSpec) DeepCopy() *ContaboPrivateNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPrivateNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboPrivateOnlySpec) DeepCopyInto(out *ContaboPrivateOnlySpec) {
+	*out = *in
+	out.Bastion = in.Bastion
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ContaboProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPrivateOnlySpec.
+func (in *ContaboPrivateOnlySpec) DeepCopy() *ContaboPrivateOnlySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContaboPrivateOnlySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContaboProviderSettings) DeepCopyInto(out *ContaboProviderSettings) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboProviderSettings.
+func (in *ContaboProviderSettings) DeepCopy() *ContaboProviderSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboCluster)
+	out := new(ContaboProviderSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContaboCluster) DeepCopyObject() runtime.Object {
+func (in *ContaboProviderSettings) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -472,46 +2846,31 @@ func (in *ContaboCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboClusterInitializationStatus) DeepCopyInto(out *ContaboClusterInitializationStatus) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterInitializationStatus.
-func (in *ContaboClusterInitializationStatus) DeepCopy() *ContaboClusterInitializationStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(ContaboClusterInitializationStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboClusterList) DeepCopyInto(out *ContaboClusterList) {
+func (in *ContaboProviderSettingsList) DeepCopyInto(out *ContaboProviderSettingsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ContaboCluster, len(*in))
+		*out = make([]ContaboProviderSettings, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterList.
-func (in *ContaboClusterList) DeepCopy() *ContaboClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboProviderSettingsList.
+func (in *ContaboProviderSettingsList) DeepCopy() *ContaboProviderSettingsList {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboClusterList)
+	out := new(ContaboProviderSettingsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContaboClusterList) DeepCopyObject() runtime.Object {
+func (in *ContaboProviderSettingsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -519,40 +2878,28 @@ func (in *ContaboClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboClusterSpec) DeepCopyInto(out *ContaboClusterSpec) {
+func (in *ContaboProviderSettingsSpec) DeepCopyInto(out *ContaboProviderSettingsSpec) {
 	*out = *in
-	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
-	out.PrivateNetwork = in.PrivateNetwork
+	in.Intervals.DeepCopyInto(&out.Intervals)
+	in.Timeouts.DeepCopyInto(&out.Timeouts)
+	in.Bootstrap.DeepCopyInto(&out.Bootstrap)
+	in.Notifications.DeepCopyInto(&out.Notifications)
+	in.APICalls.DeepCopyInto(&out.APICalls)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterSpec.
-func (in *ContaboClusterSpec) DeepCopy() *ContaboClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboProviderSettingsSpec.
+func (in *ContaboProviderSettingsSpec) DeepCopy() *ContaboProviderSettingsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboClusterSpec)
+	out := new(ContaboProviderSettingsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboClusterStatus) DeepCopyInto(out *ContaboClusterStatus) {
+func (in *ContaboProviderSettingsStatus) DeepCopyInto(out *ContaboProviderSettingsStatus) {
 	*out = *in
-	if in.PrivateNetwork != nil {
-		in, out := &in.PrivateNetwork, &out.PrivateNetwork
-		*out = new(ContaboPrivateNetworkStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SshKey != nil {
-		in, out := &in.SshKey, &out.SshKey
-		*out = new(ContaboSshKeyStatus)
-		**out = **in
-	}
-	if in.Initialization != nil {
-		in, out := &in.Initialization, &out.Initialization
-		*out = new(ContaboClusterInitializationStatus)
-		**out = **in
-	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -560,57 +2907,40 @@ func (in *ContaboClusterStatus) DeepCopyInto(out *ContaboClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.FailureDomains != nil {
-		in, out := &in.FailureDomains, &out.FailureDomains
-		*out = make([]corev1beta2.FailureDomain, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboClusterStatus.
-func (in *ContaboClusterStatus) DeepCopy() *ContaboClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboProviderSettingsStatus.
+func (in *ContaboProviderSettingsStatus) DeepCopy() *ContaboProviderSettingsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboClusterStatus)
+	out := new(ContaboProviderSettingsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboInstanceSpec) DeepCopyInto(out *ContaboInstanceSpec) {
+func (in *ContaboProxySpec) DeepCopyInto(out *ContaboProxySpec) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
-	if in.ProductId != nil {
-		in, out := &in.ProductId, &out.ProductId
-		*out = new(string)
-		**out = **in
-	}
-	if in.ProvisioningType != nil {
-		in, out := &in.ProvisioningType, &out.ProvisioningType
-		*out = new(ContaboInstanceProvisioningType)
-		**out = **in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboInstanceSpec.
-func (in *ContaboInstanceSpec) DeepCopy() *ContaboInstanceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboProxySpec.
+func (in *ContaboProxySpec) DeepCopy() *ContaboProxySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboInstanceSpec)
+	out := new(ContaboProxySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachine) DeepCopyInto(out *ContaboMachine) {
+func (in *ContaboQuota) DeepCopyInto(out *ContaboQuota) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -618,18 +2948,18 @@ func (in *ContaboMachine) DeepCopyInto(out *ContaboMachine) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachine.
-func (in *ContaboMachine) DeepCopy() *ContaboMachine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboQuota.
+func (in *ContaboQuota) DeepCopy() *ContaboQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachine)
+	out := new(ContaboQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContaboMachine) DeepCopyObject() runtime.Object {
+func (in *ContaboQuota) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -637,51 +2967,31 @@ func (in *ContaboMachine) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineInitializationStatus) DeepCopyInto(out *ContaboMachineInitializationStatus) {
-	*out = *in
-	if in.ErrorMessage != nil {
-		in, out := &in.ErrorMessage, &out.ErrorMessage
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineInitializationStatus.
-func (in *ContaboMachineInitializationStatus) DeepCopy() *ContaboMachineInitializationStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(ContaboMachineInitializationStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineList) DeepCopyInto(out *ContaboMachineList) {
+func (in *ContaboQuotaList) DeepCopyInto(out *ContaboQuotaList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ContaboMachine, len(*in))
+		*out = make([]ContaboQuota, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineList.
-func (in *ContaboMachineList) DeepCopy() *ContaboMachineList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboQuotaList.
+func (in *ContaboQuotaList) DeepCopy() *ContaboQuotaList {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachineList)
+	out := new(ContaboQuotaList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContaboMachineList) DeepCopyObject() runtime.Object {
+func (in *ContaboQuotaList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -689,39 +2999,44 @@ func (in *ContaboMachineList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineSpec) DeepCopyInto(out *ContaboMachineSpec) {
+func (in *ContaboQuotaSpec) DeepCopyInto(out *ContaboQuotaSpec) {
 	*out = *in
-	if in.ProviderID != nil {
-		in, out := &in.ProviderID, &out.ProviderID
-		*out = new(string)
+	if in.MaxInstances != nil {
+		in, out := &in.MaxInstances, &out.MaxInstances
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxCpuCores != nil {
+		in, out := &in.MaxCpuCores, &out.MaxCpuCores
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxRamMb != nil {
+		in, out := &in.MaxRamMb, &out.MaxRamMb
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxPrivateNetworks != nil {
+		in, out := &in.MaxPrivateNetworks, &out.MaxPrivateNetworks
+		*out = new(int32)
 		**out = **in
 	}
-	in.Instance.DeepCopyInto(&out.Instance)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineSpec.
-func (in *ContaboMachineSpec) DeepCopy() *ContaboMachineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboQuotaSpec.
+func (in *ContaboQuotaSpec) DeepCopy() *ContaboQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachineSpec)
+	out := new(ContaboQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineStatus) DeepCopyInto(out *ContaboMachineStatus) {
+func (in *ContaboQuotaStatus) DeepCopyInto(out *ContaboQuotaStatus) {
 	*out = *in
-	if in.Instance != nil {
-		in, out := &in.Instance, &out.Instance
-		*out = new(ContaboInstanceStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]corev1beta2.MachineAddress, len(*in))
-		copy(*out, *in)
-	}
+	out.Used = in.Used
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -729,179 +3044,219 @@ func (in *ContaboMachineStatus) DeepCopyInto(out *ContaboMachineStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Initialization != nil {
-		in, out := &in.Initialization, &out.Initialization
-		*out = new(ContaboMachineInitializationStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.FailureReason != nil {
-		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(string)
-		**out = **in
-	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineStatus.
-func (in *ContaboMachineStatus) DeepCopy() *ContaboMachineStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboQuotaStatus.
+func (in *ContaboQuotaStatus) DeepCopy() *ContaboQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachineStatus)
+	out := new(ContaboQuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineTemplate) DeepCopyInto(out *ContaboMachineTemplate) {
+func (in *ContaboQuotaUsage) DeepCopyInto(out *ContaboQuotaUsage) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplate.
-func (in *ContaboMachineTemplate) DeepCopy() *ContaboMachineTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboQuotaUsage.
+func (in *ContaboQuotaUsage) DeepCopy() *ContaboQuotaUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachineTemplate)
+	out := new(ContaboQuotaUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContaboMachineTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineTemplateList) DeepCopyInto(out *ContaboMachineTemplateList) {
+func (in *ContaboRequeueIntervals) DeepCopyInto(out *ContaboRequeueIntervals) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ContaboMachineTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Dependency != nil {
+		in, out := &in.Dependency, &out.Dependency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ResourceCreation != nil {
+		in, out := &in.ResourceCreation, &out.ResourceCreation
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Instance != nil {
+		in, out := &in.Instance, &out.Instance
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CloudInit != nil {
+		in, out := &in.CloudInit, &out.CloudInit
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Ssh != nil {
+		in, out := &in.Ssh, &out.Ssh
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AuditTrail != nil {
+		in, out := &in.AuditTrail, &out.AuditTrail
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Catalog != nil {
+		in, out := &in.Catalog, &out.Catalog
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.OutOfStock != nil {
+		in, out := &in.OutOfStock, &out.OutOfStock
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Host != nil {
+		in, out := &in.Host, &out.Host
+		*out = new(v1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplateList.
-func (in *ContaboMachineTemplateList) DeepCopy() *ContaboMachineTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboRequeueIntervals.
+func (in *ContaboRequeueIntervals) DeepCopy() *ContaboRequeueIntervals {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachineTemplateList)
+	out := new(ContaboRequeueIntervals)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContaboMachineTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineTemplateResource) DeepCopyInto(out *ContaboMachineTemplateResource) {
+func (in *ContaboSnapshotRetentionSpec) DeepCopyInto(out *ContaboSnapshotRetentionSpec) {
 	*out = *in
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.PruneOldest != nil {
+		in, out := &in.PruneOldest, &out.PruneOldest
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplateResource.
-func (in *ContaboMachineTemplateResource) DeepCopy() *ContaboMachineTemplateResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboSnapshotRetentionSpec.
+func (in *ContaboSnapshotRetentionSpec) DeepCopy() *ContaboSnapshotRetentionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachineTemplateResource)
+	out := new(ContaboSnapshotRetentionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineTemplateSpec) DeepCopyInto(out *ContaboMachineTemplateSpec) {
+func (in *ContaboSshKey) DeepCopyInto(out *ContaboSshKey) {
 	*out = *in
-	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplateSpec.
-func (in *ContaboMachineTemplateSpec) DeepCopy() *ContaboMachineTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboSshKey.
+func (in *ContaboSshKey) DeepCopy() *ContaboSshKey {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachineTemplateSpec)
+	out := new(ContaboSshKey)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboMachineTemplateStatus) DeepCopyInto(out *ContaboMachineTemplateStatus) {
+func (in *ContaboSshKeyStatus) DeepCopyInto(out *ContaboSshKeyStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboMachineTemplateStatus.
-func (in *ContaboMachineTemplateStatus) DeepCopy() *ContaboMachineTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboSshKeyStatus.
+func (in *ContaboSshKeyStatus) DeepCopy() *ContaboSshKeyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboMachineTemplateStatus)
+	out := new(ContaboSshKeyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboPrivateNetworkSpec) DeepCopyInto(out *ContaboPrivateNetworkSpec) {
+func (in *ContaboTagStatus) DeepCopyInto(out *ContaboTagStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboPrivateNetworkSpec.
-func (in *ContaboPrivateNetworkSpec) DeepCopy() *ContaboPrivateNetworkSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboTagStatus.
+func (in *ContaboTagStatus) DeepCopy() *ContaboTagStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboPrivateNetworkSpec)
+	out := new(ContaboTagStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboSshKey) DeepCopyInto(out *ContaboSshKey) {
+func (in *ContaboTimeouts) DeepCopyInto(out *ContaboTimeouts) {
 	*out = *in
+	if in.SshDial != nil {
+		in, out := &in.SshDial, &out.SshDial
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.FirstBootProbe != nil {
+		in, out := &in.FirstBootProbe, &out.FirstBootProbe
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.InstanceOrder != nil {
+		in, out := &in.InstanceOrder, &out.InstanceOrder
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Shutdown != nil {
+		in, out := &in.Shutdown, &out.Shutdown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.StuckDeletion != nil {
+		in, out := &in.StuckDeletion, &out.StuckDeletion
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboSshKey.
-func (in *ContaboSshKey) DeepCopy() *ContaboSshKey {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboTimeouts.
+func (in *ContaboTimeouts) DeepCopy() *ContaboTimeouts {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboSshKey)
+	out := new(ContaboTimeouts)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContaboSshKeyStatus) DeepCopyInto(out *ContaboSshKeyStatus) {
+func (in *ContaboUserDataStatus) DeepCopyInto(out *ContaboUserDataStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboSshKeyStatus.
-func (in *ContaboSshKeyStatus) DeepCopy() *ContaboSshKeyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContaboUserDataStatus.
+func (in *ContaboUserDataStatus) DeepCopy() *ContaboUserDataStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContaboSshKeyStatus)
+	out := new(ContaboUserDataStatus)
 	in.DeepCopyInto(out)
 	return out
 }
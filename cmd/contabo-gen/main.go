@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// contabo-gen writes ready-to-apply cluster manifests for the common flavors, e.g.
+//
+//	contabo-gen --flavor ha --cluster-name prod --kubernetes-version v1.33.4 --control-plane-endpoint 203.0.113.10 | kubectl apply -f -
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/generator"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "contabo-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the flags and writes the manifests to stdout, or to the --output file
+func run(args []string, stdout io.Writer) error {
+	var opts generator.Options
+	var flavor, region, controlPlaneProduct, workerProduct, output string
+
+	flavors := []string{}
+	for _, f := range generator.Flavors() {
+		flavors = append(flavors, string(f))
+	}
+
+	fs := flag.NewFlagSet("contabo-gen", flag.ContinueOnError)
+	fs.StringVar(&flavor, "flavor", string(generator.FlavorDev), "Cluster flavor, one of "+strings.Join(flavors, ", "))
+	fs.StringVar(&opts.ClusterName, "cluster-name", "", "Name of the cluster (required)")
+	fs.StringVar(&opts.Namespace, "namespace", generator.DefaultNamespace, "Namespace of the cluster objects")
+	fs.StringVar(&opts.KubernetesVersion, "kubernetes-version", "", "Kubernetes version of the machines, e.g. v1.33.4 (required)")
+	fs.StringVar(&region, "region", string(generator.DefaultRegion), "Contabo region of the private network and the instances")
+	fs.StringVar(&controlPlaneProduct, "control-plane-product", string(generator.DefaultProduct), "Contabo product of the control plane machines")
+	fs.StringVar(&workerProduct, "worker-product", string(generator.DefaultProduct), "Contabo product of the worker machines")
+	fs.IntVar(&opts.ControlPlaneMachineCount, "control-plane-machine-count", 0, "Number of control plane machines, default 1 for the dev flavor and 3 otherwise")
+	fs.IntVar(&opts.WorkerMachineCount, "worker-machine-count", generator.DefaultWorkerMachineCount, "Number of worker machines")
+	fs.StringVar(&opts.ControlPlaneEndpoint, "control-plane-endpoint", "", "Host of the API server, the virtual IP announced by kube-vip for the ha and private flavors (required)")
+	fs.IntVar(&opts.APIServerPort, "api-server-port", generator.DefaultAPIServerPort, "Port of the API server")
+	fs.StringVar(&opts.PrivateNetworkName, "private-network-name", "", "Private network the cluster joins, e.g. the private network of the management cluster, default a private network of the cluster")
+	fs.StringVar(&opts.VIPInterface, "vip-interface", "", "Interface kube-vip announces the virtual IP on, default "+generator.DefaultPublicVIPInterface+" for the ha flavor and "+generator.DefaultPrivateVIPInterface+" for the private flavor")
+	fs.StringVar(&opts.KubeVIPImage, "kube-vip-image", generator.DefaultKubeVIPImage, "kube-vip image")
+	fs.StringVar(&opts.PodCIDR, "pod-cidr", generator.DefaultPodCIDR, "CIDR of the pods")
+	fs.StringVar(&opts.ServiceCIDR, "service-cidr", generator.DefaultServiceCIDR, "CIDR of the services")
+	fs.StringVar(&opts.EncryptionKey, "encryption-key", os.Getenv("ENCRYPTION_KEY"), "Base64 encoded 32-byte key encrypting the secrets at rest, generated when empty (or ENCRYPTION_KEY)")
+	fs.StringVar(&output, "output", "", "File the manifests are written to, default stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts.Flavor = generator.Flavor(flavor)
+	opts.Region = infrastructurev1beta2.ContaboRegion(region)
+	opts.ControlPlaneProduct = infrastructurev1beta2.ContaboProductId(controlPlaneProduct)
+	opts.WorkerProduct = infrastructurev1beta2.ContaboProductId(workerProduct)
+
+	if output == "" {
+		return generator.Generate(stdout, opts)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := generator.Generate(file, opts); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -18,34 +18,48 @@ package main
 
 import (
 	"context"
-	"crypto/sha256"
 	"crypto/tls"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
-	"github.com/google/uuid"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/certs"
 	"github.com/ctnr-io/cluster-api-provider-contabo/internal/controller"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/options"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/version"
+	webhookinfrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/internal/webhook/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/compat"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/ratelimit"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/readonly"
 	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/auth"
 	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
 	// +kubebuilder:scaffold:imports
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/retry"
 )
 
 var (
@@ -63,105 +77,129 @@ func init() {
 
 // nolint:gocyclo
 func main() {
-	var metricsAddr string
-	var metricsCertPath, metricsCertName, metricsCertKey string
-	var webhookCertPath, webhookCertName, webhookCertKey string
-	var enableLeaderElection bool
-	var probeAddr string
-	var secureMetrics bool
-	var enableHTTP2 bool
-	var contaboClientID string
-	var contaboClientSecret string
-	var contaboAPIUser string
-	var contaboAPIPassword string
-	var leaderElectionID string
 	var tlsOpts []func(*tls.Config)
-	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
-		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
-	flag.BoolVar(&secureMetrics, "metrics-secure", true,
-		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
-	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
-	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
-	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
-	flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
-		"The directory that contains the metrics server certificate.")
-	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
-	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
-	flag.BoolVar(&enableHTTP2, "enable-http2", false,
-		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
-	flag.StringVar(&contaboClientID, "contabo-client-id", "",
-		"The Contabo OAuth2 client ID. Can also be set via CONTABO_CLIENT_ID environment variable.")
-	flag.StringVar(&contaboClientSecret, "contabo-client-secret", "",
-		"The Contabo OAuth2 client secret. Can also be set via CONTABO_CLIENT_SECRET environment variable.")
-	flag.StringVar(&contaboAPIUser, "contabo-api-user", "",
-		"The Contabo API username. Can also be set via CONTABO_API_USER environment variable.")
-	flag.StringVar(&contaboAPIPassword, "contabo-api-password", "",
-		"The Contabo API password. Can also be set via CONTABO_API_PASSWORD environment variable.")
-	flag.StringVar(&leaderElectionID, "leader-election-id", "",
-		"Leader election ID. If not specified, a dynamic ID will be generated based on namespace and controller name.")
+	// Every option of the manager is a flag which can also be set by its environment variable
+	managerOpts := options.New(flag.CommandLine)
 	opts := zap.Options{
 		Development: true,
 	}
 	opts.BindFlags(flag.CommandLine)
-	flag.Parse()
+	if err := managerOpts.Parse(os.Args[1:], os.LookupEnv); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	// Get Contabo OAuth2 credentials from environment if not provided via flags
-	if contaboClientID == "" {
-		contaboClientID = os.Getenv("CONTABO_CLIENT_ID")
-	}
-	if contaboClientSecret == "" {
-		contaboClientSecret = os.Getenv("CONTABO_CLIENT_SECRET")
+	buildInfo := version.Get()
+	setupLog.Info("Cluster API Provider Contabo", "version", buildInfo.GitVersion, "gitCommit", buildInfo.GitCommit,
+		"buildDate", buildInfo.BuildDate, "contract", buildInfo.ContractVersion, "contaboAPI", buildInfo.ContaboAPIVersion, "supportedCAPIVersions", buildInfo.SupportedCAPIVersions)
+
+	if err := managerOpts.Validate(); err != nil {
+		setupLog.Error(err, "invalid options")
+		os.Exit(1)
 	}
-	if contaboAPIUser == "" {
-		contaboAPIUser = os.Getenv("CONTABO_API_USER")
+
+	// Create OAuth2 token managers for automatic token refresh, the primary credentials come first
+	primary, secondary := managerOpts.Credentials, managerOpts.SecondaryCredentials
+	if managerOpts.CredentialsDir != "" {
+		var err error
+		if primary, secondary, err = auth.ReadCredentialsDir(managerOpts.CredentialsDir); err != nil {
+			setupLog.Error(err, "failed to read the Contabo credentials", "dir", managerOpts.CredentialsDir)
+			os.Exit(1)
+		}
 	}
-	if contaboAPIPassword == "" {
-		contaboAPIPassword = os.Getenv("CONTABO_API_PASSWORD")
+	authMetrics := auth.NewMetrics()
+	tokenManagers := []*auth.TokenManager{
+		auth.NewTokenManager(primary.ClientID, primary.ClientSecret, primary.APIUser, primary.APIPassword).
+			WithMetrics(authMetrics, "primary"),
 	}
 
-	// Validate OAuth2 credentials
-	if contaboClientID == "" || contaboClientSecret == "" || contaboAPIUser == "" || contaboAPIPassword == "" {
-		setupLog.Error(fmt.Errorf("contabo OAuth2 credentials are required"),
-			"set via flags or environment variables",
-			"CONTABO_CLIENT_ID", "CONTABO_CLIENT_SECRET",
-			"CONTABO_API_USER", "CONTABO_API_PASSWORD")
-		os.Exit(1)
+	// Secondary credentials are optional, the validation ensures they are complete when provided
+	if secondary.IsComplete() {
+		setupLog.Info("Secondary Contabo credentials configured, failover enabled")
+		tokenManagers = append(tokenManagers, auth.NewTokenManager(
+			secondary.ClientID, secondary.ClientSecret, secondary.APIUser, secondary.APIPassword).
+			WithMetrics(authMetrics, "secondary"))
 	}
-
-	// Create OAuth2 token manager for automatic token refresh
-	tokenManager := auth.NewTokenManager(contaboClientID, contaboClientSecret, contaboAPIUser, contaboAPIPassword)
+	tokenManager := auth.NewFailoverTokenManager(tokenManagers...)
 
 	// Test initial token acquisition
-	_, err := tokenManager.GetToken()
+	_, _, err := tokenManager.GetToken()
 	if err != nil {
 		setupLog.Error(err, "failed to get initial OAuth2 access token")
 		os.Exit(1)
 	}
 
+	// Derive a stable leader election ID if not provided, every replica and restart must compete for the same lease
+	leaderElectionNamespace := getLeaderElectionNamespace(managerOpts.ControllerNamespace)
+	finalLeaderElectionID := generateLeaderElectionID(managerOpts.LeaderElectionID, leaderElectionNamespace)
+	setupLog.Info("Using leader election ID", "leaderElectionID", finalLeaderElectionID)
+
+	// The requests of the installation are traced in the Contabo audits to detect duplicate installations
+	managerTraceId := controller.ManagerTraceId(leaderElectionNamespace, finalLeaderElectionID)
+	setupLog.Info("Using Contabo API trace ID", "traceID", managerTraceId)
+
 	// Initialize Contabo OpenAPI client with token manager
+	// The failover transport authorizes each request and switches credentials on 401, the requests refused by the
+	// rate limit or failed by a transient error are then retried, each attempt waiting for the API budget of the
+	// account in the queue of its cluster
+	retryMetrics := retry.NewMetrics()
+	var contaboTransport http.RoundTripper = auth.NewFailoverTransport(
+		retry.NewTransport(
+			ratelimit.NewTransport(http.DefaultTransport, ratelimit.NewFairLimiter(managerOpts.ContaboAPIQPS, managerOpts.ContaboAPIBurst)),
+			managerOpts.ContaboAPIMaxRetries, managerOpts.ContaboAPIRetryDelay, retryMetrics,
+		),
+		tokenManager,
+	)
+	// In read-only mode the requests changing the Contabo resources are refused before being sent, whichever code
+	// path issues them
+	if managerOpts.ReadOnly {
+		setupLog.Info("Read-only mode enabled, the Contabo resources are only observed")
+		contaboTransport = readonly.NewTransport(contaboTransport)
+	}
 	contaboClient, err := contaboclient.NewClientWithResponses(
 		"https://api.contabo.com",
-		contaboclient.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
-			token, err := tokenManager.GetToken()
-			if err != nil {
-				return fmt.Errorf("failed to get access token: %w", err)
-			}
-			req.Header.Set("Authorization", "Bearer "+token)
-			req.Header.Set("x-request-id", uuid.New().String())
-			return nil
+		contaboclient.WithHTTPClient(&http.Client{
+			Transport: contaboTransport,
 		}),
+		// Fill the request ID of the context, e.g. to correlate the retries of a request, in the requests sent
+		// without parameters
+		contaboclient.WithRequestEditorFn(contabo.RequestEditor(managerTraceId)),
 	)
 	if err != nil {
 		setupLog.Error(err, "unable to create Contabo API client")
 		os.Exit(1)
 	}
 
+	// Refuse to run against an API the client is not generated for, rather than failing the reconciliations at random
+	if err := compat.CheckPinnedVersion(managerOpts.ContaboAPIVersion); err != nil {
+		setupLog.Error(err, "set --contabo-api-version to the specification version of the controller or upgrade it")
+		os.Exit(1)
+	}
+	switch policy := compat.Policy(managerOpts.ContaboAPICompatibility); policy {
+	case compat.PolicySkip:
+	case compat.PolicyFail, compat.PolicyWarn:
+		checkCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		report := compat.Check(checkCtx, contaboClient)
+		cancel()
+		for _, result := range report.Results {
+			if result.Status != compat.StatusCompatible {
+				setupLog.Info("Contabo API endpoint check", "endpoint", result.Endpoint, "critical", result.Critical,
+					"status", result.Status, "message", result.Message)
+			}
+		}
+		if err := report.Err(); err != nil {
+			if policy == compat.PolicyFail {
+				setupLog.Error(err, "refusing to start, set --contabo-api-compatibility=Warn to start anyway")
+				os.Exit(1)
+			}
+			setupLog.Error(err, "starting with an incompatible Contabo API")
+		} else {
+			setupLog.Info("Contabo API is compatible", "specVersion", report.SpecVersion)
+		}
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -173,38 +211,106 @@ func main() {
 		c.NextProtos = []string{"http/1.1"}
 	}
 
-	if !enableHTTP2 {
+	if !managerOpts.EnableHTTP2 {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
+	// The certificates provisioned with cert-manager by the manager itself are read from their directories like the
+	// certificates mounted by the kustomize configuration
+	var certsManager *certs.Manager
+	if managerOpts.ManageCerts {
+		if leaderElectionNamespace == "" {
+			setupLog.Error(nil, "The namespace of the manager is required to manage its certificates, set --controller-namespace")
+			os.Exit(1)
+		}
+		certsClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "Failed to create the client provisioning the certificates")
+			os.Exit(1)
+		}
+		certsManager = &certs.Manager{
+			Client:    certsClient,
+			Namespace: leaderElectionNamespace,
+			Dir:       managerOpts.ManagedCertsDir,
+		}
+		if managerOpts.EnableWebhooks {
+			certsManager.WebhookService = managerOpts.WebhookServiceName
+		}
+		if managerOpts.SecureMetrics && managerOpts.MetricsAddr != "0" {
+			certsManager.MetricsService = managerOpts.MetricsServiceName
+		}
+		setupLog.Info("Provisioning the certificates with cert-manager", "namespace", leaderElectionNamespace,
+			"webhook-service", certsManager.WebhookService, "metrics-service", certsManager.MetricsService)
+		provisionCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		err = certsManager.Provision(provisionCtx)
+		cancel()
+		if err != nil {
+			setupLog.Error(err, "Failed to provision the certificates")
+			os.Exit(1)
+		}
+		if certsManager.WebhookService != "" {
+			managerOpts.WebhookCertPath = certsManager.WebhookCertDir()
+		}
+		if certsManager.MetricsService != "" {
+			managerOpts.MetricsCertPath = certsManager.MetricsCertDir()
+		}
+	}
+
 	// Initial webhook TLS options
 	webhookTLSOpts := tlsOpts
-	webhookServerOptions := webhook.Options{
-		TLSOpts: webhookTLSOpts,
-	}
 
-	if len(webhookCertPath) > 0 {
+	// The certificates rotated on disk are reloaded without restarting the webhook server, the handshakes in flight
+	// keep the previous certificate
+	var webhookCertWatcher *certwatcher.CertWatcher
+	if len(managerOpts.WebhookCertPath) > 0 {
 		setupLog.Info("Initializing webhook certificate watcher using provided certificates",
-			"webhook-cert-path", webhookCertPath, "webhook-cert-name", webhookCertName, "webhook-cert-key", webhookCertKey)
+			"webhook-cert-path", managerOpts.WebhookCertPath, "webhook-cert-name", managerOpts.WebhookCertName, "webhook-cert-key", managerOpts.WebhookCertKey,
+			"webhook-cert-reload-interval", managerOpts.WebhookCertReloadInterval)
+
+		webhookCertWatcher, err = certwatcher.New(
+			filepath.Join(managerOpts.WebhookCertPath, managerOpts.WebhookCertName),
+			filepath.Join(managerOpts.WebhookCertPath, managerOpts.WebhookCertKey),
+		)
+		if err != nil {
+			setupLog.Error(err, "Failed to initialize webhook certificate watcher")
+			os.Exit(1)
+		}
+		webhookCertWatcher = webhookCertWatcher.WithWatchInterval(managerOpts.WebhookCertReloadInterval)
+		webhookCertWatcher.RegisterCallback(func(certificate tls.Certificate) {
+			if certificate.Leaf != nil {
+				setupLog.Info("Loaded webhook certificate", "notAfter", certificate.Leaf.NotAfter)
+			}
+		})
 
-		webhookServerOptions.CertDir = webhookCertPath
-		webhookServerOptions.CertName = webhookCertName
-		webhookServerOptions.KeyName = webhookCertKey
+		webhookTLSOpts = append(webhookTLSOpts, func(config *tls.Config) {
+			config.GetCertificate = webhookCertWatcher.GetCertificate
+		})
 	}
 
-	webhookServer := webhook.NewServer(webhookServerOptions)
+	webhookServer := webhook.NewServer(webhook.Options{
+		Host:    managerOpts.WebhookHost,
+		Port:    managerOpts.WebhookPort,
+		TLSOpts: webhookTLSOpts,
+	})
 
 	// Metrics endpoint is enabled in 'config/default/kustomization.yaml'. The Metrics options configure the server.
 	// More info:
 	// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/metrics/server
 	// - https://book.kubebuilder.io/reference/metrics.html
 	metricsServerOptions := metricsserver.Options{
-		BindAddress:   metricsAddr,
-		SecureServing: secureMetrics,
+		BindAddress:   managerOpts.MetricsAddr,
+		SecureServing: managerOpts.SecureMetrics,
 		TLSOpts:       tlsOpts,
+		// The build information is served next to the capc_build_info metric
+		ExtraHandlers: map[string]http.Handler{"/version": version.Handler()},
 	}
+	ctrlmetrics.Registry.MustRegister(version.NewBuildInfoCollector())
+	bootTimeProfiles := controller.NewBootTimeProfiles()
+	ctrlmetrics.Registry.MustRegister(bootTimeProfiles)
+	ctrlmetrics.Registry.MustRegister(retryMetrics)
+	ctrlmetrics.Registry.MustRegister(authMetrics)
 
-	if secureMetrics {
+	if managerOpts.SecureMetrics {
 		// FilterProvider is used to protect the metrics endpoint with authn/authz.
 		// These configurations ensure that only authorized users and service accounts
 		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
@@ -216,67 +322,219 @@ func main() {
 	// generate self-signed certificates for the metrics server. While convenient for development and testing,
 	// this setup is not recommended for production.
 	//
-	// TODO(user): If you enable certManager, uncomment the following lines:
+	// Either set --manage-certs, or uncomment the following lines:
 	// - [METRICS-WITH-CERTS] at config/default/kustomization.yaml to generate and use certificates
 	// managed by cert-manager for the metrics server.
 	// - [PROMETHEUS-WITH-CERTS] at config/prometheus/kustomization.yaml for TLS certification.
-	if len(metricsCertPath) > 0 {
+	if len(managerOpts.MetricsCertPath) > 0 {
 		setupLog.Info("Initializing metrics certificate watcher using provided certificates",
-			"metrics-cert-path", metricsCertPath, "metrics-cert-name", metricsCertName, "metrics-cert-key", metricsCertKey)
+			"metrics-cert-path", managerOpts.MetricsCertPath, "metrics-cert-name", managerOpts.MetricsCertName, "metrics-cert-key", managerOpts.MetricsCertKey)
 
-		metricsServerOptions.CertDir = metricsCertPath
-		metricsServerOptions.CertName = metricsCertName
-		metricsServerOptions.KeyName = metricsCertKey
+		metricsServerOptions.CertDir = managerOpts.MetricsCertPath
+		metricsServerOptions.CertName = managerOpts.MetricsCertName
+		metricsServerOptions.KeyName = managerOpts.MetricsCertKey
 	}
 
-	// Generate dynamic leader election ID if not provided
-	leaderElectionNamespace := getLeaderElectionNamespace()
-	finalLeaderElectionID := generateLeaderElectionID(leaderElectionID, leaderElectionNamespace)
-	setupLog.Info("Using leader election ID", "leaderElectionID", finalLeaderElectionID)
-
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                  scheme,
 		Metrics:                 metricsServerOptions,
 		WebhookServer:           webhookServer,
-		HealthProbeBindAddress:  probeAddr,
-		LeaderElection:          enableLeaderElection,
+		HealthProbeBindAddress:  managerOpts.ProbeAddr,
+		LeaderElection:          managerOpts.EnableLeaderElection,
 		LeaderElectionID:        finalLeaderElectionID,
 		LeaderElectionNamespace: leaderElectionNamespace,
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:           &managerOpts.LeaderElectionLeaseDuration,
+		RenewDeadline:           &managerOpts.LeaderElectionRenewDeadline,
+		RetryPeriod:             &managerOpts.LeaderElectionRetryPeriod,
+		// The leader steps down voluntarily when the manager ends so that a replacement pod, for instance after the
+		// node hosting the manager of a self-hosted cluster is drained, does not wait for the lease to expire.
+		// This is safe as the program ends immediately after the manager stops.
+		LeaderElectionReleaseOnCancel: true,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// The credentials rotated in the credentials directory replace the ones of the token managers
+	if managerOpts.CredentialsDir != "" {
+		credentialsWatcher := &auth.CredentialsDirWatcher{
+			Dir:      managerOpts.CredentialsDir,
+			Interval: managerOpts.CredentialsReloadInterval,
+			Primary:  tokenManagers[0],
+		}
+		if len(tokenManagers) > 1 {
+			credentialsWatcher.Secondary = tokenManagers[1]
+		}
+		setupLog.Info("Adding credentials directory watcher to manager", "dir", managerOpts.CredentialsDir)
+		if err := mgr.Add(credentialsWatcher); err != nil {
+			setupLog.Error(err, "unable to add credentials directory watcher to manager")
+			os.Exit(1)
+		}
+	}
+
+	if webhookCertWatcher != nil {
+		setupLog.Info("Adding webhook certificate watcher to manager")
+		if err := mgr.Add(webhookCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add webhook certificate watcher to manager")
+			os.Exit(1)
+		}
+	}
+
+	if certsManager != nil {
+		if err := mgr.Add(certsManager); err != nil {
+			setupLog.Error(err, "unable to add the certificates manager to manager")
+			os.Exit(1)
+		}
+	}
+
+	// Runtime tunables shared by the controllers, updated from the ContaboProviderSettings singleton
+	providerSettings := controller.NewProviderSettings()
+
+	// The Contabo API requests of each reconciliation are exported, the hot loops are reported on their resource
+	apiCallMetrics := controller.NewAPICallMetrics(providerSettings)
+	ctrlmetrics.Registry.MustRegister(apiCallMetrics)
+
+	// The time spent deleting is read from the manager cache on each scrape
+	ctrlmetrics.Registry.MustRegister(controller.NewDeletionMetrics(mgr.GetClient(), providerSettings))
+
+	// Critical events are forwarded to the webhook of the flags and the sinks of the ContaboProviderSettings
+	notificationSinks := []controller.NotificationSink{}
+	if managerOpts.NotificationWebhookURL != "" {
+		notificationSinks = append(notificationSinks, &controller.WebhookSink{
+			Name:   "flags",
+			URL:    managerOpts.NotificationWebhookURL,
+			Format: infrastructurev1beta2.ContaboNotificationFormat(managerOpts.NotificationWebhookFormat),
+		})
+	}
+	notifier := controller.NewNotifier(mgr.GetClient(), providerSettings, notificationSinks...)
+	tokenManager.SetFailureHandler(func(index int, err error) {
+		notifier.Notify(controller.Notification{
+			Reason:  infrastructurev1beta2.ContaboCredentialsFailedReason,
+			Message: fmt.Sprintf("Contabo credentials %d failed: %v", index, err),
+			Kind:    "Credentials",
+			Name:    strconv.Itoa(index),
+		})
+	})
+
+	// The clusters referencing their own credentials are managed in their Contabo account
+	credentials := controller.NewClusterCredentials(mgr.GetClient())
 	if err := (&controller.ContaboClusterReconciler{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
-		Recorder:      mgr.GetEventRecorderFor("contabocluster-controller"),
+		Recorder:      notifier.Recorder(mgr.GetEventRecorderFor("contabocluster-controller")),
 		ContaboClient: contaboClient,
+		Settings:      providerSettings,
+		ReadOnly:      managerOpts.ReadOnly,
+		Credentials:   credentials,
+		APICalls:      apiCallMetrics,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ContaboCluster")
 		os.Exit(1)
 	}
+	jobs := controller.NewJobQueue(mgr.GetClient(), contaboClient, providerSettings, managerOpts.JobWorkers)
+	jobs.ReadOnly = managerOpts.ReadOnly
+	jobs.Credentials = credentials
 	if err := (&controller.ContaboMachineReconciler{
+		Client:                      mgr.GetClient(),
+		Scheme:                      mgr.GetScheme(),
+		Recorder:                    notifier.Recorder(mgr.GetEventRecorderFor("contabomachine-controller")),
+		ContaboClient:               contaboClient,
+		Settings:                    providerSettings,
+		ManagerNodeName:             managerOpts.NodeName,
+		ManagerTraceId:              managerTraceId,
+		BootTimeProfiles:            bootTimeProfiles,
+		Jobs:                        jobs,
+		ReadOnly:                    managerOpts.ReadOnly,
+		Credentials:                 credentials,
+		APICalls:                    apiCallMetrics,
+		PrivateNetworkAssignWorkers: managerOpts.PrivateNetworkAssignWorkers,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ContaboMachine")
+		os.Exit(1)
+	}
+	if err := (&controller.ContaboMachinePoolReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: notifier.Recorder(mgr.GetEventRecorderFor("contabomachinepool-controller")),
+		Settings: providerSettings,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ContaboMachinePool")
+		os.Exit(1)
+	}
+	if err := (&controller.ContaboQuotaReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: notifier.Recorder(mgr.GetEventRecorderFor("contaboquota-controller")),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ContaboQuota")
+		os.Exit(1)
+	}
+	if err := (&controller.ContaboProviderSettingsReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Settings: providerSettings,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ContaboProviderSettings")
+		os.Exit(1)
+	}
+	if err := (&controller.ContaboPatchScheduleReconciler{
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Recorder:        notifier.Recorder(mgr.GetEventRecorderFor("contabopatchschedule-controller")),
+		Settings:        providerSettings,
+		ManagerNodeName: managerOpts.NodeName,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ContaboPatchSchedule")
+		os.Exit(1)
+	}
+	if err := (&controller.ContaboAccountInventoryReconciler{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
-		Recorder:      mgr.GetEventRecorderFor("contabomachine-controller"),
 		ContaboClient: contaboClient,
+		Settings:      providerSettings,
+		Recorder:      notifier.Recorder(mgr.GetEventRecorderFor("contaboaccountinventory-controller")),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "ContaboMachine")
+		setupLog.Error(err, "unable to create controller", "controller", "ContaboAccountInventory")
 		os.Exit(1)
 	}
+	if err := (&controller.ContaboCatalogReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		ContaboClient: contaboClient,
+		Settings:      providerSettings,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ContaboCatalog")
+		os.Exit(1)
+	}
+	if managerOpts.EnableWebhooks {
+		if err := webhookinfrastructurev1beta2.SetupContaboMachineTemplateWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ContaboMachineTemplate")
+			os.Exit(1)
+		}
+		if err := webhookinfrastructurev1beta2.SetupContaboMachineWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ContaboMachine")
+			os.Exit(1)
+		}
+		if err := webhookinfrastructurev1beta2.SetupContaboClusterWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ContaboCluster")
+			os.Exit(1)
+		}
+		if err := webhookinfrastructurev1beta2.SetupDeprecatedFieldsWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DeprecatedFields")
+			os.Exit(1)
+		}
+		if err := webhookinfrastructurev1beta2.SetupProviderSpecificWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ProviderSpecific")
+			os.Exit(1)
+		}
+		// The manager is only ready once the webhook server serves, so that the API server is not routed to a
+		// replica which cannot answer the admission requests yet
+		if err := mgr.AddReadyzCheck("webhook", webhookServer.StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -288,44 +546,59 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+
+	// Migrate the legacy resources before the controllers see them, with a direct client as the cache is not started.
+	// Each step is idempotent, replicas starting together may both run it.
+	if managerOpts.MigrateLegacyResources && managerOpts.ReadOnly {
+		setupLog.Info("Read-only mode enabled, the legacy resources migration runs on the next start without --read-only")
+	} else if managerOpts.MigrateLegacyResources {
+		migrationClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+		if err != nil {
+			setupLog.Error(err, "unable to create legacy migration client")
+			os.Exit(1)
+		}
+		if err := (&controller.LegacyMigration{
+			Client:        migrationClient,
+			ContaboClient: contaboClient,
+			Namespace:     leaderElectionNamespace,
+		}).Run(ctrl.LoggerInto(ctx, setupLog)); err != nil {
+			setupLog.Error(err, "unable to migrate legacy resources")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
 
-// generateLeaderElectionID creates a unique leader election ID using UUID hash
+// generateLeaderElectionID returns the leader election ID of the controller manager. The ID is derived from the
+// namespace only, so that all the replicas and restarted pods of a deployment compete for the same lease.
 func generateLeaderElectionID(customID string, namespace string) string {
 	// If a custom ID is provided, use it
 	if customID != "" {
 		return customID
 	}
 
-	// Generate a UUID for uniqueness
-	id := uuid.New()
-
-	// Create hash of UUID to get shorter identifier (8 chars)
-	hash := sha256.Sum256([]byte(id.String()))
-	shortID := fmt.Sprintf("%x", hash)[:8]
-
-	// Create leader election ID with optional namespace and short hash
 	if namespace != "" {
-		return fmt.Sprintf("contabo-%s-%s.cluster.x-k8s.io", namespace, shortID)
+		return fmt.Sprintf("contabo-%s.cluster.x-k8s.io", namespace)
 	}
-	return fmt.Sprintf("contabo-%s.cluster.x-k8s.io", shortID)
+	return "contabo.cluster.x-k8s.io"
 }
 
 // getLeaderElectionNamespace determines the namespace for leader election
-func getLeaderElectionNamespace() string {
-	// Try to get namespace from environment (set by Kubernetes deployment)
-	if namespace := os.Getenv("CONTROLLER_NAMESPACE"); namespace != "" {
+func getLeaderElectionNamespace(namespace string) string {
+	// Use the namespace of the options (set by Kubernetes deployment)
+	if namespace != "" {
 		return namespace
 	}
 
 	// Fallback: try to read from service account namespace
 	if data, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace"); err == nil {
-		return string(data)
+		return strings.TrimSpace(string(data))
 	}
 
 	// Return empty string to use default namespace behavior
@@ -0,0 +1,353 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certs provisions the serving certificates of the webhook and metrics servers with cert-manager, without
+// the cert-manager manifests of the kustomize configuration. The manager creates a CA issued by a self-signed Issuer,
+// an Issuer of this CA and the serving Certificates itself, writes the issued key pairs to the directories read by
+// the certificate watchers of the servers, and injects the CA into the webhook configurations. cert-manager renews
+// the serving certificates and the Secrets are synced again periodically, so that the rotated certificates are
+// served without restarting the manager.
+package certs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// DefaultSyncInterval is how often the issued certificates are synced to disk and the CA injected again
+	DefaultSyncInterval = time.Minute
+
+	// SelfSignedIssuerName is the name of the self-signed Issuer of the CA
+	SelfSignedIssuerName = "capc-selfsigned-issuer"
+	// CAName is the name of the CA Certificate, of its Secret and of the Issuer of the serving certificates
+	CAName = "capc-ca"
+	// WebhookCertificateName is the name of the webhook serving Certificate and of its Secret
+	WebhookCertificateName = "capc-webhook-server-cert"
+	// MetricsCertificateName is the name of the metrics serving Certificate and of its Secret
+	MetricsCertificateName = "capc-metrics-server-cert"
+
+	// fieldOwner is the field manager of the cert-manager resources created by the manager
+	fieldOwner = "cluster-api-provider-contabo"
+
+	// pollInterval is how often the Secret of a Certificate is read while it is issued
+	pollInterval = 2 * time.Second
+)
+
+var (
+	issuerGVK      = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"}
+	certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+	// ErrCertManagerNotInstalled is returned when the cert-manager CRDs are missing
+	ErrCertManagerNotInstalled = errors.New("cert-manager is not installed, install it or deploy the certificates with kustomize")
+)
+
+// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers;certificates,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations;mutatingwebhookconfigurations,verbs=get;list;watch;update;patch
+
+// Manager provisions the serving certificates of the manager. It is a manager.Runnable syncing the certificates on
+// every replica, once they are provisioned by Provision before the servers start.
+type Manager struct {
+	// Client is an uncached client, the manager cache is not started when the certificates are provisioned
+	Client client.Client
+	// Namespace is the namespace of the manager, holding the cert-manager resources and the Services
+	Namespace string
+	// Dir is the directory the key pairs are written to, in a subdirectory per server
+	Dir string
+	// WebhookService is the name of the Service of the webhook server, empty when the webhooks are disabled
+	WebhookService string
+	// MetricsService is the name of the Service of the metrics server, empty when the metrics are not served securely
+	MetricsService string
+	// SyncInterval is how often the certificates are synced, DefaultSyncInterval when zero
+	SyncInterval time.Duration
+}
+
+var _ manager.Runnable = &Manager{}
+var _ manager.LeaderElectionRunnable = &Manager{}
+
+// WebhookCertDir returns the directory of the webhook key pair, in the tls.crt and tls.key files
+func (m *Manager) WebhookCertDir() string {
+	return filepath.Join(m.Dir, "webhook")
+}
+
+// MetricsCertDir returns the directory of the metrics key pair, in the tls.crt and tls.key files
+func (m *Manager) MetricsCertDir() string {
+	return filepath.Join(m.Dir, "metrics")
+}
+
+// servers returns the directory and the Certificate name of each server by Service name
+func (m *Manager) servers() map[string][2]string {
+	servers := map[string][2]string{}
+	if m.WebhookService != "" {
+		servers[m.WebhookService] = [2]string{WebhookCertificateName, m.WebhookCertDir()}
+	}
+	if m.MetricsService != "" {
+		servers[m.MetricsService] = [2]string{MetricsCertificateName, m.MetricsCertDir()}
+	}
+	return servers
+}
+
+// Provision creates or updates the cert-manager resources, waits until the serving certificates are issued and
+// syncs them. It is called before the servers start as they read the key pairs when they are created.
+func (m *Manager) Provision(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	resources := []*unstructured.Unstructured{
+		m.issuer(SelfSignedIssuerName, map[string]any{"selfSigned": map[string]any{}}),
+		m.certificate(CAName, map[string]any{
+			"isCA":       true,
+			"commonName": CAName,
+			"secretName": CAName,
+			"privateKey": map[string]any{"algorithm": "ECDSA", "size": int64(256)},
+			"issuerRef":  map[string]any{"kind": issuerGVK.Kind, "name": SelfSignedIssuerName},
+		}),
+		m.issuer(CAName, map[string]any{"ca": map[string]any{"secretName": CAName}}),
+	}
+	for service, server := range m.servers() {
+		resources = append(resources, m.certificate(server[0], map[string]any{
+			"secretName": server[0],
+			"dnsNames": []any{
+				fmt.Sprintf("%s.%s.svc", service, m.Namespace),
+				fmt.Sprintf("%s.%s.svc.cluster.local", service, m.Namespace),
+			},
+			// The key is replaced on every renewal
+			"privateKey": map[string]any{"rotationPolicy": "Always"},
+			"issuerRef":  map[string]any{"kind": issuerGVK.Kind, "name": CAName},
+		}))
+	}
+	for _, resource := range resources {
+		if err := m.apply(ctx, resource); err != nil {
+			return err
+		}
+		log.Info("Provisioned cert-manager resource", "kind", resource.GetKind(), "name", resource.GetName())
+	}
+
+	for _, server := range m.servers() {
+		if err := wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+			secret, err := m.issuedSecret(ctx, server[0])
+			return secret != nil, err
+		}); err != nil {
+			return fmt.Errorf("certificate %s/%s was not issued, check its status: %w", m.Namespace, server[0], err)
+		}
+	}
+	return m.Sync(ctx)
+}
+
+// Start implements manager.Runnable, it syncs the certificates every SyncInterval until the context is done
+func (m *Manager) Start(ctx context.Context) error {
+	interval := m.SyncInterval
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := m.Sync(ctx); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to sync the managed certificates")
+		}
+	}, interval)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, every replica serves the certificates
+func (m *Manager) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync writes the key pairs of the issued certificates to their directory when they changed, and injects the CA of
+// the webhook certificate into the webhook configurations targeting the webhook Service
+func (m *Manager) Sync(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	var caBundle []byte
+	for service, server := range m.servers() {
+		secret, err := m.issuedSecret(ctx, server[0])
+		if err != nil {
+			return err
+		}
+		if secret == nil {
+			return fmt.Errorf("certificate %s/%s is not issued", m.Namespace, server[0])
+		}
+		for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, "ca.crt"} {
+			changed, err := writeFile(filepath.Join(server[1], key), secret.Data[key])
+			if err != nil {
+				return err
+			}
+			if changed {
+				log.Info("Wrote managed certificate", "certificate", server[0], "file", filepath.Join(server[1], key))
+			}
+		}
+		if service == m.WebhookService {
+			caBundle = secret.Data["ca.crt"]
+		}
+	}
+	if caBundle == nil {
+		return nil
+	}
+	return m.injectCABundle(ctx, caBundle)
+}
+
+// injectCABundle sets the CA bundle of the webhooks targeting the webhook Service
+func (m *Manager) injectCABundle(ctx context.Context, caBundle []byte) error {
+	log := logf.FromContext(ctx)
+
+	targets := func(config admissionregistrationv1.WebhookClientConfig) bool {
+		return config.Service != nil && config.Service.Namespace == m.Namespace && config.Service.Name == m.WebhookService &&
+			!bytes.Equal(config.CABundle, caBundle)
+	}
+
+	validating := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := m.Client.List(ctx, validating); err != nil {
+		return fmt.Errorf("failed to list validating webhook configurations: %w", err)
+	}
+	for i := range validating.Items {
+		configuration := &validating.Items[i]
+		injected := false
+		for j := range configuration.Webhooks {
+			if targets(configuration.Webhooks[j].ClientConfig) {
+				configuration.Webhooks[j].ClientConfig.CABundle = caBundle
+				injected = true
+			}
+		}
+		if !injected {
+			continue
+		}
+		if err := m.Client.Update(ctx, configuration); err != nil {
+			return fmt.Errorf("failed to inject the CA into validating webhook configuration %s: %w", configuration.Name, err)
+		}
+		log.Info("Injected the CA into the webhook configuration", "kind", "ValidatingWebhookConfiguration", "name", configuration.Name)
+	}
+
+	mutating := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := m.Client.List(ctx, mutating); err != nil {
+		return fmt.Errorf("failed to list mutating webhook configurations: %w", err)
+	}
+	for i := range mutating.Items {
+		configuration := &mutating.Items[i]
+		injected := false
+		for j := range configuration.Webhooks {
+			if targets(configuration.Webhooks[j].ClientConfig) {
+				configuration.Webhooks[j].ClientConfig.CABundle = caBundle
+				injected = true
+			}
+		}
+		if !injected {
+			continue
+		}
+		if err := m.Client.Update(ctx, configuration); err != nil {
+			return fmt.Errorf("failed to inject the CA into mutating webhook configuration %s: %w", configuration.Name, err)
+		}
+		log.Info("Injected the CA into the webhook configuration", "kind", "MutatingWebhookConfiguration", "name", configuration.Name)
+	}
+	return nil
+}
+
+// issuedSecret returns the Secret of a Certificate once it holds the key pair and the CA, nil before
+func (m *Manager) issuedSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := m.Client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: name}, secret); apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", m.Namespace, name, err)
+	}
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, "ca.crt"} {
+		if len(secret.Data[key]) == 0 {
+			return nil, nil
+		}
+	}
+	return secret, nil
+}
+
+func (m *Manager) issuer(name string, spec map[string]any) *unstructured.Unstructured {
+	return m.resource(issuerGVK, name, spec)
+}
+
+func (m *Manager) certificate(name string, spec map[string]any) *unstructured.Unstructured {
+	return m.resource(certificateGVK, name, spec)
+}
+
+func (m *Manager) resource(gvk schema.GroupVersionKind, name string, spec map[string]any) *unstructured.Unstructured {
+	resource := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	resource.SetGroupVersionKind(gvk)
+	resource.SetNamespace(m.Namespace)
+	resource.SetName(name)
+	resource.SetLabels(map[string]string{"app.kubernetes.io/managed-by": fieldOwner})
+	return resource
+}
+
+// apply creates the resource or updates its spec
+func (m *Manager) apply(ctx context.Context, resource *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(resource.GroupVersionKind())
+	err := m.Client.Get(ctx, client.ObjectKeyFromObject(resource), existing)
+	switch {
+	case meta.IsNoMatchError(err):
+		return ErrCertManagerNotInstalled
+	case apierrors.IsNotFound(err):
+		if err := m.Client.Create(ctx, resource, client.FieldOwner(fieldOwner)); err != nil {
+			return fmt.Errorf("failed to create %s %s: %w", resource.GetKind(), resource.GetName(), err)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get %s %s: %w", resource.GetKind(), resource.GetName(), err)
+	}
+	existing.Object["spec"] = resource.Object["spec"]
+	if err := m.Client.Update(ctx, existing, client.FieldOwner(fieldOwner)); err != nil {
+		return fmt.Errorf("failed to update %s %s: %w", resource.GetKind(), resource.GetName(), err)
+	}
+	return nil
+}
+
+// writeFile replaces the file with the content when it differs, through a rename so that the certificate watchers
+// never read a partial file. It returns true when the file was written.
+func writeFile(path string, content []byte) (bool, error) {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, content) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return false, fmt.Errorf("failed to create directory of %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const namespace = "capc-system"
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func issuedSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(name + "-crt"),
+			corev1.TLSPrivateKeyKey: []byte(name + "-key"),
+			"ca.crt":                []byte("ca"),
+		},
+	}
+}
+
+func webhookConfiguration(service string) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "validating-" + service},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: "vcontabomachine-v1beta2.kb.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{Namespace: namespace, Name: service},
+			},
+		}},
+	}
+}
+
+func TestProvision(t *testing.T) {
+	scheme := newScheme(t)
+	c := crfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			issuedSecret(WebhookCertificateName), issuedSecret(MetricsCertificateName),
+			webhookConfiguration("webhook-service"), webhookConfiguration("other-service"),
+		).
+		Build()
+	m := &Manager{
+		Client:         c,
+		Namespace:      namespace,
+		Dir:            t.TempDir(),
+		WebhookService: "webhook-service",
+		MetricsService: "metrics-service",
+	}
+	ctx := context.Background()
+	if err := m.Provision(ctx); err != nil {
+		t.Fatalf("Provision() error = %v", err)
+	}
+
+	for _, resource := range []struct {
+		kind string
+		name string
+	}{
+		{"Issuer", SelfSignedIssuerName}, {"Certificate", CAName}, {"Issuer", CAName},
+		{"Certificate", WebhookCertificateName}, {"Certificate", MetricsCertificateName},
+	} {
+		object := &unstructured.Unstructured{}
+		object.SetAPIVersion("cert-manager.io/v1")
+		object.SetKind(resource.kind)
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: resource.name}, object); err != nil {
+			t.Errorf("%s %s was not created: %v", resource.kind, resource.name, err)
+		}
+	}
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: WebhookCertificateName}, certificate); err != nil {
+		t.Fatal(err)
+	}
+	dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	if len(dnsNames) != 2 || dnsNames[0] != "webhook-service.capc-system.svc" {
+		t.Errorf("dnsNames = %v, want the DNS names of the webhook service", dnsNames)
+	}
+
+	for dir, name := range map[string]string{m.WebhookCertDir(): WebhookCertificateName, m.MetricsCertDir(): MetricsCertificateName} {
+		content, err := os.ReadFile(filepath.Join(dir, corev1.TLSCertKey))
+		if err != nil || string(content) != name+"-crt" {
+			t.Errorf("%s = %q, %v, want the certificate of %s", filepath.Join(dir, corev1.TLSCertKey), content, err, name)
+		}
+	}
+
+	for service, want := range map[string]string{"webhook-service": "ca", "other-service": ""} {
+		configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := c.Get(ctx, client.ObjectKey{Name: "validating-" + service}, configuration); err != nil {
+			t.Fatal(err)
+		}
+		if got := string(configuration.Webhooks[0].ClientConfig.CABundle); got != want {
+			t.Errorf("caBundle of the webhooks of %s = %q, want %q", service, got, want)
+		}
+	}
+
+	// Provisioning again updates the resources in place
+	if err := m.Provision(ctx); err != nil {
+		t.Fatalf("Provision() again error = %v", err)
+	}
+}
+
+func TestProvisionWithoutCertManager(t *testing.T) {
+	scheme := newScheme(t)
+	m := &Manager{
+		Client: crfake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if obj.GetObjectKind().GroupVersionKind().Group == issuerGVK.Group {
+					return &meta.NoKindMatchError{GroupKind: obj.GetObjectKind().GroupVersionKind().GroupKind()}
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build(),
+		Namespace:      namespace,
+		Dir:            t.TempDir(),
+		WebhookService: "webhook-service",
+	}
+	if err := m.Provision(context.Background()); !errors.Is(err, ErrCertManagerNotInstalled) {
+		t.Errorf("Provision() error = %v, want %v", err, ErrCertManagerNotInstalled)
+	}
+}
+
+func TestSyncRotation(t *testing.T) {
+	scheme := newScheme(t)
+	secret := issuedSecret(WebhookCertificateName)
+	c := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	m := &Manager{Client: c, Namespace: namespace, Dir: t.TempDir(), WebhookService: "webhook-service"}
+	ctx := context.Background()
+	if err := m.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	secret.Data[corev1.TLSCertKey] = []byte("renewed-crt")
+	if err := c.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(m.WebhookCertDir(), corev1.TLSCertKey))
+	if err != nil || string(content) != "renewed-crt" {
+		t.Errorf("certificate = %q, %v, want the renewed certificate", content, err)
+	}
+	entries, _ := os.ReadDir(m.WebhookCertDir())
+	if len(entries) != 3 {
+		t.Errorf("files = %d, want the certificate, the key and the CA without temporary files", len(entries))
+	}
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// additionalIPv4Count returns the number of additional IPv4 addresses requested for the instance of the machine
+func additionalIPv4Count(contaboMachine *infrastructurev1beta2.ContaboMachine) int {
+	spec := contaboMachine.Spec.Instance.AdditionalIPv4
+	if spec == nil {
+		return 0
+	}
+	if spec.Count <= 0 {
+		return 1
+	}
+	return int(spec.Count)
+}
+
+// setAdditionalIPv4AddOns orders the additional IPv4 addresses of the machine with the instance, with the add-on ID
+// when set, else with the additional IPs add-on which provides a single address
+func setAdditionalIPv4AddOns(addOns *models.CreateInstanceAddons, contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	count := additionalIPv4Count(contaboMachine)
+	if count == 0 {
+		return
+	}
+	addOnId := contaboMachine.Spec.Instance.AdditionalIPv4.AddOnId
+	if addOnId == nil {
+		addOns.AdditionalIps = ptr.To(map[string]interface{}{})
+		return
+	}
+	addOns.AddonsIds = ptr.To(append(ptr.Deref(addOns.AddonsIds, nil), models.AddOnRequest{
+		Id:       *addOnId,
+		Quantity: int64(count),
+	}))
+}
+
+// ipv4AddressesStatus returns the public IPv4 addresses of the instance with their role, the primary address first
+func ipv4AddressesStatus(instance *infrastructurev1beta2.ContaboInstanceStatus) []infrastructurev1beta2.ContaboIPv4AddressStatus {
+	addresses := []infrastructurev1beta2.ContaboIPv4AddressStatus{}
+	if instance.IpConfig.V4.Ip != "" {
+		addresses = append(addresses, infrastructurev1beta2.ContaboIPv4AddressStatus{
+			Address:     instance.IpConfig.V4.Ip,
+			Role:        infrastructurev1beta2.ContaboIPv4AddressRolePrimary,
+			NetmaskCidr: instance.IpConfig.V4.NetmaskCidr,
+			Gateway:     instance.IpConfig.V4.Gateway,
+		})
+	}
+	for _, additionalIp := range instance.AdditionalIps {
+		if additionalIp.V4.Ip == "" {
+			continue
+		}
+		addresses = append(addresses, infrastructurev1beta2.ContaboIPv4AddressStatus{
+			Address:     additionalIp.V4.Ip,
+			Role:        infrastructurev1beta2.ContaboIPv4AddressRoleSecondary,
+			NetmaskCidr: additionalIp.V4.NetmaskCidr,
+			Gateway:     additionalIp.V4.Gateway,
+		})
+	}
+	return addresses
+}
+
+// secondaryMachineAddresses returns the additional IPv4 addresses of the instance as external machine addresses
+func secondaryMachineAddresses(ipv4Addresses []infrastructurev1beta2.ContaboIPv4AddressStatus) []clusterv1.MachineAddress {
+	addresses := []clusterv1.MachineAddress{}
+	for _, address := range ipv4Addresses {
+		if address.Role != infrastructurev1beta2.ContaboIPv4AddressRoleSecondary {
+			continue
+		}
+		addresses = append(addresses, clusterv1.MachineAddress{
+			Type:    clusterv1.MachineExternalIP,
+			Address: address.Address,
+		})
+	}
+	return addresses
+}
+
+// additionalIPv4CloudConfig returns the cloud-config adding the additional IPv4 addresses of the instance to its
+// public interface at every boot, nil when the instance has none or their configuration is disabled.
+// The ${EXTERNAL_IPV4} variable is replaced with the rest of the cloud-config.
+func additionalIPv4CloudConfig(contaboMachine *infrastructurev1beta2.ContaboMachine) ([]byte, error) {
+	spec := contaboMachine.Spec.Instance.AdditionalIPv4
+	if spec == nil || !ptr.Deref(spec.Configure, true) || contaboMachine.Status.Instance == nil {
+		return nil, nil
+	}
+
+	commands := []string{}
+	for _, address := range ipv4AddressesStatus(contaboMachine.Status.Instance) {
+		if address.Role != infrastructurev1beta2.ContaboIPv4AddressRoleSecondary {
+			continue
+		}
+		netmaskCidr := address.NetmaskCidr
+		if netmaskCidr <= 0 || netmaskCidr > 32 {
+			netmaskCidr = 32
+		}
+		commands = append(commands, fmt.Sprintf(`ip -4 addr replace %s/%d dev "$iface"`, address.Address, netmaskCidr))
+	}
+	if len(commands) == 0 {
+		return nil, nil
+	}
+
+	script := strings.Join(append([]string{
+		"#!/bin/sh",
+		`iface=$(ip -o -4 addr show | awk -v ip="${EXTERNAL_IPV4}" 'index($4, ip "/") == 1 {print $2; exit}')`,
+		`[ -n "$iface" ] || { echo "[CAPC] Error: public interface not found for ${EXTERNAL_IPV4}"; exit 1; }`,
+	}, commands...), "\n")
+	service := strings.Join([]string{
+		"[Unit]",
+		"Description=Configure the Contabo additional IPv4 addresses",
+		"After=network-online.target",
+		"Wants=network-online.target",
+		"",
+		"[Service]",
+		"Type=oneshot",
+		"ExecStart=/usr/local/bin/contabo-additional-ipv4.sh",
+		"",
+		"[Install]",
+		"WantedBy=multi-user.target",
+	}, "\n")
+
+	return yaml.Marshal(map[string]interface{}{
+		"write_files": []interface{}{
+			map[string]interface{}{
+				"path":        "/usr/local/bin/contabo-additional-ipv4.sh",
+				"owner":       "root:root",
+				"permissions": "0755",
+				"content":     script,
+			},
+			map[string]interface{}{
+				"path":        "/etc/systemd/system/contabo-additional-ipv4.service",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     service,
+			},
+		},
+		"runcmd": []interface{}{
+			"systemctl daemon-reload && systemctl enable contabo-additional-ipv4.service && systemctl start contabo-additional-ipv4.service",
+		},
+	})
+}
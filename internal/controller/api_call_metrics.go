@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
+)
+
+// apiCallKey identifies the reconciliations of a resource by a controller
+type apiCallKey struct {
+	controller string
+	object     types.NamespacedName
+}
+
+// APICallMetrics exports the number of Contabo API requests sent by each reconciliation, counted on the context of
+// the reconciliation, and reports the resources whose reconciliations send more requests than the hot loop threshold
+// several times in a row with a warning event, as a hot loop silently burns the API budget shared by all the
+// clusters. A nil APICallMetrics records nothing.
+type APICallMetrics struct {
+	Settings *ProviderSettings
+
+	mu sync.Mutex
+	// exceeded is the number of reconciliations in a row above the threshold per resource
+	exceeded map[apiCallKey]int
+
+	calls *prometheus.HistogramVec
+}
+
+// NewAPICallMetrics returns the API call metrics using the hot loop threshold of the settings
+func NewAPICallMetrics(settings *ProviderSettings) *APICallMetrics {
+	return &APICallMetrics{
+		Settings: settings,
+		exceeded: map[apiCallKey]int{},
+		calls: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "capc_reconcile_contabo_api_calls",
+			Help:    "Number of Contabo API requests sent by a reconciliation per controller",
+			Buckets: []float64{0, 1, 2, 5, 10, 20, 50, 100, 200},
+		}, []string{"controller"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (m *APICallMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.calls.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *APICallMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.calls.Collect(ch)
+}
+
+// Record records the Contabo API requests counted on the context of a reconciliation of the resource, see
+// contextutil.WithAPICallCounter, and records a ContaboAPIHotLoop warning event once the resource exceeded the
+// threshold for the configured number of reconciliations in a row
+func (m *APICallMetrics) Record(ctx context.Context, controller string, obj client.Object, recorder record.EventRecorder) {
+	if m == nil {
+		return
+	}
+	calls := contextutil.APICallsFromContext(ctx)
+	m.calls.WithLabelValues(controller).Observe(float64(calls))
+
+	threshold := m.Settings.HotLoopThreshold()
+	reconciles := m.Settings.HotLoopReconciles()
+	key := apiCallKey{controller: controller, object: client.ObjectKeyFromObject(obj)}
+	m.mu.Lock()
+	if calls <= int64(threshold) {
+		delete(m.exceeded, key)
+		m.mu.Unlock()
+		return
+	}
+	m.exceeded[key]++
+	hotLoop := m.exceeded[key] >= reconciles
+	if hotLoop {
+		// Report again after as many reconciliations, rather than on every one
+		delete(m.exceeded, key)
+	}
+	m.mu.Unlock()
+
+	if hotLoop && recorder != nil {
+		recorder.Eventf(obj, corev1.EventTypeWarning, infrastructurev1beta2.ContaboAPIHotLoopReason,
+			"%d reconciliations in a row sent more than %d Contabo API requests, the last one %d",
+			reconciles, threshold, calls)
+	}
+}
+
+// forget drops the hot loop tracking of a deleted resource
+func (m *APICallMetrics) forget(controller string, key types.NamespacedName) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.exceeded, apiCallKey{controller: controller, object: key})
+}
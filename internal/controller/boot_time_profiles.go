@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// BootTimeProfileMinSamples is the number of observations of a product in a data center before its profile
+	// adapts the timeouts
+	BootTimeProfileMinSamples = 5
+	// BootTimeProfileMaxSamples is the number of latest observations kept per product and data center
+	BootTimeProfileMaxSamples = 50
+	// BootTimeProfileMargin is applied to the 95th percentile of the observations to get the adaptive timeout
+	BootTimeProfileMargin = 1.5
+	// BootTimeProfileMaxStretch caps the adaptive timeout to a multiple of the configured timeout
+	BootTimeProfileMaxStretch = 4
+)
+
+// bootTimePhase is a provisioning phase of an instance guarded by a timeout
+type bootTimePhase string
+
+const (
+	// bootTimePhaseOrder runs from the instance order until the instance leaves provisioning
+	bootTimePhaseOrder bootTimePhase = "order"
+	// bootTimePhaseFirstBoot runs from the first first-boot probe until sshd and cloud-init are healthy
+	bootTimePhaseFirstBoot bootTimePhase = "first_boot"
+)
+
+// bootTimeProfileKey identifies the observations of a phase for a product in a data center
+type bootTimeProfileKey struct {
+	phase      bootTimePhase
+	productId  string
+	dataCenter string
+}
+
+// BootTimeProfiles holds the provisioning times observed per product and data center, exported as metrics, so that
+// the timeouts resetting or replacing instances stretch for the naturally slower products and data centers instead
+// of remediating instances which are only slow. The profiles are kept in memory and rebuilt after a restart.
+// A nil BootTimeProfiles never adapts the timeouts.
+type BootTimeProfiles struct {
+	mu      sync.Mutex
+	samples map[bootTimeProfileKey][]time.Duration
+
+	durations *prometheus.HistogramVec
+	expected  *prometheus.GaugeVec
+}
+
+// NewBootTimeProfiles returns empty boot time profiles
+func NewBootTimeProfiles() *BootTimeProfiles {
+	labels := []string{"phase", "product", "data_center"}
+	return &BootTimeProfiles{
+		samples: map[bootTimeProfileKey][]time.Duration{},
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "capc_instance_provisioning_duration_seconds",
+			Help:    "Observed provisioning time of the instances per phase, product and data center",
+			Buckets: []float64{30, 60, 120, 300, 600, 900, 1200, 1800, 2700, 3600, 7200},
+		}, labels),
+		expected: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "capc_instance_provisioning_expected_seconds",
+			Help: "95th percentile of the latest provisioning times per phase, product and data center, once enough were observed to adapt the timeouts",
+		}, labels),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (p *BootTimeProfiles) Describe(ch chan<- *prometheus.Desc) {
+	p.durations.Describe(ch)
+	p.expected.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (p *BootTimeProfiles) Collect(ch chan<- prometheus.Metric) {
+	p.durations.Collect(ch)
+	p.expected.Collect(ch)
+}
+
+// newBootTimeProfileKey returns the profile key of the instance, false when its product or data center is unknown
+func newBootTimeProfileKey(phase bootTimePhase, instance *infrastructurev1beta2.ContaboInstanceStatus) (bootTimeProfileKey, bool) {
+	if instance == nil || instance.ProductId == "" || instance.DataCenter == "" {
+		return bootTimeProfileKey{}, false
+	}
+	return bootTimeProfileKey{phase: phase, productId: instance.ProductId, dataCenter: instance.DataCenter}, true
+}
+
+// observe records the provisioning time of a phase of the instance
+func (p *BootTimeProfiles) observe(phase bootTimePhase, instance *infrastructurev1beta2.ContaboInstanceStatus, duration time.Duration) {
+	key, ok := newBootTimeProfileKey(phase, instance)
+	if p == nil || !ok || duration <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	samples := append(p.samples[key], duration)
+	if len(samples) > BootTimeProfileMaxSamples {
+		samples = samples[len(samples)-BootTimeProfileMaxSamples:]
+	}
+	p.samples[key] = samples
+
+	p.durations.WithLabelValues(string(phase), key.productId, key.dataCenter).Observe(duration.Seconds())
+	if expected, ok := bootTimePercentile95(samples); ok {
+		p.expected.WithLabelValues(string(phase), key.productId, key.dataCenter).Set(expected.Seconds())
+	}
+}
+
+// timeout returns the timeout of a phase of the instance: the configured timeout, stretched to the 95th percentile
+// of the observed provisioning times with a margin, up to BootTimeProfileMaxStretch times the configured timeout.
+// It never shortens the configured timeout.
+func (p *BootTimeProfiles) timeout(phase bootTimePhase, instance *infrastructurev1beta2.ContaboInstanceStatus, configured time.Duration) time.Duration {
+	key, ok := newBootTimeProfileKey(phase, instance)
+	if p == nil || !ok {
+		return configured
+	}
+	p.mu.Lock()
+	expected, ok := bootTimePercentile95(p.samples[key])
+	p.mu.Unlock()
+	if !ok {
+		return configured
+	}
+
+	adaptive := time.Duration(float64(expected) * BootTimeProfileMargin)
+	return min(max(adaptive, configured), configured*BootTimeProfileMaxStretch)
+}
+
+// bootTimePercentile95 returns the 95th percentile of the samples, false while there are too few of them
+func bootTimePercentile95(samples []time.Duration) (time.Duration, bool) {
+	if len(samples) < BootTimeProfileMinSamples {
+		return 0, false
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	index := (len(sorted)*95+99)/100 - 1
+	return sorted[index], true
+}
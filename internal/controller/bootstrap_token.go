@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// DefaultBootstrapTokenTTL is the default time an instance has to join the cluster with its bootstrap token
+	DefaultBootstrapTokenTTL = time.Hour
+
+	// bootstrapTokenExtraGroups is the group of the kubeadm node bootstrap tokens, bound by kubeadm to the node
+	// bootstrapper and certificate approval roles
+	bootstrapTokenExtraGroups = "system:bootstrappers:kubeadm:default-node-token"
+
+	// bootstrapTokenTimeout bounds the workload cluster requests, the API server may be unreachable
+	bootstrapTokenTimeout = 10 * time.Second
+)
+
+// injectBootstrapToken replaces the bootstrap token of the kubeadm JoinConfiguration written by the bootstrap data.
+// It returns false when the bootstrap data joins no cluster, e.g. for the first control plane machine.
+func injectBootstrapToken(bootstrapData []byte, token string) ([]byte, bool, error) {
+	injected := false
+	data, err := updateKubeadmConfigFiles(bootstrapData, func(content string) (string, error) {
+		return updateKubeadmDocuments(content, func(kind string, document map[interface{}]interface{}) {
+			if kind != "JoinConfiguration" {
+				return
+			}
+			discovery, ok := document["discovery"].(map[interface{}]interface{})
+			if !ok {
+				return
+			}
+			if bootstrapToken, ok := discovery["bootstrapToken"].(map[interface{}]interface{}); ok && bootstrapToken["token"] != nil {
+				bootstrapToken["token"] = token
+				injected = true
+			}
+			if tlsBootstrapToken, ok := discovery["tlsBootstrapToken"].(string); ok && tlsBootstrapToken != "" {
+				discovery["tlsBootstrapToken"] = token
+				injected = true
+			}
+		})
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !injected {
+		return bootstrapData, false, nil
+	}
+	return data, true, nil
+}
+
+// bootstrapTokenSecret returns the workload cluster Secret of the bootstrap token of an instance
+func bootstrapTokenSecret(contaboMachine *infrastructurev1beta2.ContaboMachine, token string, expiration time.Time) *corev1.Secret {
+	tokenId, tokenSecret, _ := strings.Cut(token, ".")
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstraputil.BootstrapTokenSecretName(tokenId),
+			Namespace: metav1.NamespaceSystem,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: contaboMachine.Labels[clusterv1.ClusterNameLabel],
+				"component":                "bootstrap-token",
+			},
+		},
+		Type: bootstrapapi.SecretTypeBootstrapToken,
+		StringData: map[string]string{
+			bootstrapapi.BootstrapTokenDescriptionKey:      fmt.Sprintf("Bootstrap token of the ContaboMachine %s/%s", contaboMachine.Namespace, contaboMachine.Name),
+			bootstrapapi.BootstrapTokenIDKey:               tokenId,
+			bootstrapapi.BootstrapTokenSecretKey:           tokenSecret,
+			bootstrapapi.BootstrapTokenExpirationKey:       expiration.UTC().Format(time.RFC3339),
+			bootstrapapi.BootstrapTokenUsageAuthentication: "true",
+			bootstrapapi.BootstrapTokenUsageSigningKey:     "true",
+			bootstrapapi.BootstrapTokenExtraGroupsKey:      bootstrapTokenExtraGroups,
+		},
+	}
+}
+
+// reconcileBootstrapToken creates a short-lived bootstrap token for the instance in the workload cluster and writes
+// it in the JoinConfiguration of the bootstrap data, instead of the token shared by the machines of the cluster.
+// The token of a previous reinstall is deleted.
+func (r *ContaboMachineReconciler) reconcileBootstrapToken(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, bootstrapData string) (string, error) {
+	log := logf.FromContext(ctx)
+
+	settings := r.Settings.Bootstrap().InstanceToken
+	if settings == nil {
+		return bootstrapData, nil
+	}
+
+	token, err := bootstraputil.GenerateBootstrapToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate bootstrap token: %w", err)
+	}
+	data, injected, err := injectBootstrapToken([]byte(bootstrapData), token)
+	if err != nil {
+		return "", fmt.Errorf("failed to inject bootstrap token in bootstrap data: %w", err)
+	}
+	if !injected {
+		return bootstrapData, nil
+	}
+
+	r.deleteBootstrapToken(ctx, contaboMachine, contaboCluster)
+
+	ttl := DefaultBootstrapTokenTTL
+	if settings.TTL != nil && settings.TTL.Duration > 0 {
+		ttl = settings.TTL.Duration
+	}
+	expiration := time.Now().Add(ttl)
+	secret := bootstrapTokenSecret(contaboMachine, token, expiration)
+
+	kubeClient, err := r.getKubeClient(ctx, contaboCluster)
+	if err != nil {
+		return "", fmt.Errorf("failed to get workload cluster client: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, bootstrapTokenTimeout)
+	defer cancel()
+	if _, err := kubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return "", fmt.Errorf("failed to create bootstrap token in the workload cluster: %w", err)
+	}
+
+	tokenId := secret.StringData[bootstrapapi.BootstrapTokenIDKey]
+	log.Info("Created bootstrap token of the instance", "tokenID", tokenId, "expiration", expiration)
+	contaboMachine.Status.BootstrapToken = &infrastructurev1beta2.ContaboBootstrapTokenStatus{
+		TokenId:        tokenId,
+		ExpirationTime: metav1.NewTime(expiration),
+	}
+	return string(data), nil
+}
+
+// deleteBootstrapToken deletes the bootstrap token of the instance from the workload cluster once the node joined.
+// Failures are only logged, the token expires anyway.
+func (r *ContaboMachineReconciler) deleteBootstrapToken(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	status := contaboMachine.Status.BootstrapToken
+	if status == nil {
+		return
+	}
+	if time.Now().After(status.ExpirationTime.Time) {
+		contaboMachine.Status.BootstrapToken = nil
+		return
+	}
+
+	kubeClient, err := r.getKubeClient(ctx, contaboCluster)
+	if err != nil {
+		log.Info("Failed to get workload cluster client to delete the bootstrap token", "tokenID", status.TokenId, "error", err.Error())
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, bootstrapTokenTimeout)
+	defer cancel()
+	err = kubeClient.CoreV1().Secrets(metav1.NamespaceSystem).Delete(ctx, bootstraputil.BootstrapTokenSecretName(status.TokenId), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Info("Failed to delete the bootstrap token", "tokenID", status.TokenId, "error", err.Error())
+		return
+	}
+	log.Info("Deleted bootstrap token of the instance", "tokenID", status.TokenId)
+	contaboMachine.Status.BootstrapToken = nil
+}
@@ -0,0 +1,426 @@
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.yaml.in/yaml/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/service"
+)
+
+const (
+	// DefaultMaxUserDataSize is the largest user data sent to the Contabo API, larger bootstrap data is compressed
+	DefaultMaxUserDataSize = 16384
+
+	// ContaboMaxUserDataSize is the largest user data accepted by the Contabo API, larger user data is never sent
+	ContaboMaxUserDataSize = service.DefaultMaxUserDataSize
+
+	// DefaultBootstrapURLExpiry is how long the signed URL of the bootstrap data uploaded to object storage is valid
+	DefaultBootstrapURLExpiry = time.Hour
+
+	// DefaultObjectStorageRegion is the region used to sign the object storage requests
+	DefaultObjectStorageRegion = "us-east-1"
+
+	// userDataMIMEBoundary separates the parts of the MIME multipart user data
+	userDataMIMEBoundary = "==CAPC-BOUNDARY=="
+)
+
+// ErrBootstrapDataTooLarge is returned when the bootstrap data does not fit in the user data once compressed
+var ErrBootstrapDataTooLarge = errors.New("bootstrap data larger than the maximum user data size")
+
+// ErrUserDataTooLarge is returned when the user data exceeds the limit of the Contabo API, the instance cannot be
+// bootstrapped without the bootstrap object storage
+var ErrUserDataTooLarge = errors.New("user data larger than accepted by the Contabo API")
+
+// objectStorageHTTPClient uploads and deletes the bootstrap data in object storage
+var objectStorageHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// renderedUserData is the user data sent to the Contabo API and how it passes the bootstrap data
+type renderedUserData struct {
+	userData string
+	status   infrastructurev1beta2.ContaboUserDataStatus
+}
+
+// gzipUserData gzips the bootstrap data into a cloud-init MIME multipart user data. cloud-init decompresses parts
+// of the application/x-gzip content type, the base64 transfer encoding keeps the user data a valid JSON string.
+func gzipUserData(bootstrapData string) (string, error) {
+	var compressed bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := writer.Write([]byte(bootstrapData)); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(compressed.Bytes())
+	var userData strings.Builder
+	userData.WriteString("Content-Type: multipart/mixed; boundary=\"" + userDataMIMEBoundary + "\"\n")
+	userData.WriteString("MIME-Version: 1.0\n\n")
+	userData.WriteString("--" + userDataMIMEBoundary + "\n")
+	userData.WriteString("Content-Type: application/x-gzip\n")
+	userData.WriteString("MIME-Version: 1.0\n")
+	userData.WriteString("Content-Transfer-Encoding: base64\n")
+	userData.WriteString("Content-Disposition: attachment; filename=\"bootstrap.gz\"\n\n")
+	for len(encoded) > 76 {
+		userData.WriteString(encoded[:76] + "\n")
+		encoded = encoded[76:]
+	}
+	userData.WriteString(encoded + "\n")
+	userData.WriteString("--" + userDataMIMEBoundary + "--\n")
+	return userData.String(), nil
+}
+
+// kubeadmPackageVersion returns the minor version of the Kubernetes packages repository, v1.31 for v1.31.2
+func kubeadmPackageVersion(version string) (string, error) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid Kubernetes version %q, expected vMAJOR.MINOR.PATCH", version)
+	}
+	return parts[0] + "." + parts[1], nil
+}
+
+// renderCloudConfigVariables replaces the ${NAME} variables in the values of the cloud-config. The values are
+// replaced once decoded, so that a variable can never change the structure of the cloud-config or be truncated by a
+// YAML indicator, and in a single pass, so that a value is never replaced again. The values are single line as they
+// are also interpolated in the scripts of the cloud-config.
+func renderCloudConfigVariables(cloudConfig string, variables map[string]string) (string, error) {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	replacements := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		if strings.ContainsAny(variables[name], "\r\n") {
+			return "", fmt.Errorf("cloud-config variable %s has a line break", name)
+		}
+		replacements = append(replacements, "${"+name+"}", variables[name])
+	}
+
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal([]byte(cloudConfig), &parsed); err != nil {
+		return "", fmt.Errorf("failed to unmarshal cloud-config: %w", err)
+	}
+	rendered, err := yaml.Marshal(replaceCloudConfigValues(parsed, strings.NewReplacer(replacements...)))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cloud-config: %w", err)
+	}
+	return string(rendered), nil
+}
+
+// replaceCloudConfigValues applies the replacer to the strings of the decoded cloud-config, the keys are left as is
+func replaceCloudConfigValues(value interface{}, replacer *strings.Replacer) interface{} {
+	switch value := value.(type) {
+	case string:
+		return replacer.Replace(value)
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = replaceCloudConfigValues(item, replacer)
+		}
+	case map[interface{}]interface{}:
+		for key, item := range value {
+			value[key] = replaceCloudConfigValues(item, replacer)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = replaceCloudConfigValues(item, replacer)
+		}
+	}
+	return value
+}
+
+// includeUserData returns the minimal user data making cloud-init fetch and process the bootstrap data from the URL
+func includeUserData(bootstrapDataURL string) string {
+	return "#include\n" + bootstrapDataURL + "\n"
+}
+
+// selectUserData compresses the bootstrap data following the compression setting and the maximum user data size.
+// It returns ErrBootstrapDataTooLarge when the bootstrap data is still too large and must be fetched from object
+// storage.
+func selectUserData(bootstrapData string, settings infrastructurev1beta2.ContaboBootstrapSettings) (renderedUserData, error) {
+	maxSize := DefaultMaxUserDataSize
+	if settings.MaxUserDataSize != nil {
+		maxSize = int(*settings.MaxUserDataSize)
+	}
+	rendered := renderedUserData{
+		userData: bootstrapData,
+		status: infrastructurev1beta2.ContaboUserDataStatus{
+			Mode:              infrastructurev1beta2.ContaboUserDataModePlain,
+			BootstrapDataSize: int32(len(bootstrapData)),
+		},
+	}
+
+	compression := settings.Compression
+	if compression == "" {
+		compression = infrastructurev1beta2.ContaboUserDataCompressionAuto
+	}
+	// The user data is a JSON string, binary bootstrap data is always sent base64 encoded in the gzip part
+	if !utf8.ValidString(bootstrapData) {
+		compression = infrastructurev1beta2.ContaboUserDataCompressionAlways
+	}
+	if compression == infrastructurev1beta2.ContaboUserDataCompressionAlways ||
+		(compression == infrastructurev1beta2.ContaboUserDataCompressionAuto && len(bootstrapData) > maxSize) {
+		compressed, err := gzipUserData(bootstrapData)
+		if err != nil {
+			return rendered, fmt.Errorf("failed to compress bootstrap data: %w", err)
+		}
+		// Keep the plain bootstrap data when compression does not make it smaller
+		if compression == infrastructurev1beta2.ContaboUserDataCompressionAlways || len(compressed) < len(bootstrapData) {
+			rendered.userData = compressed
+			rendered.status.Mode = infrastructurev1beta2.ContaboUserDataModeGzip
+		}
+	}
+	rendered.status.Size = int32(len(rendered.userData))
+
+	if len(rendered.userData) > maxSize {
+		return rendered, fmt.Errorf("%w: %d bytes, maximum %d bytes", ErrBootstrapDataTooLarge, len(rendered.userData), maxSize)
+	}
+	return rendered, nil
+}
+
+// checkUserDataSize returns ErrUserDataTooLarge when the user data exceeds ContaboMaxUserDataSize
+func checkUserDataSize(rendered renderedUserData) error {
+	if len(rendered.userData) > ContaboMaxUserDataSize {
+		return fmt.Errorf("%w: %d bytes once rendered in %s mode, maximum %d bytes, configure the bootstrap object "+
+			"storage of the ContaboProviderSettings", ErrUserDataTooLarge, len(rendered.userData), rendered.status.Mode, ContaboMaxUserDataSize)
+	}
+	return nil
+}
+
+// renderUserData returns the user data passing the bootstrap data to the instance. Bootstrap data too large for the
+// user data once compressed is uploaded to the object storage, and fetched by the instance from a signed URL.
+// Without object storage, the largest user data is returned with ErrBootstrapDataTooLarge.
+func (r *ContaboMachineReconciler) renderUserData(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, bootstrapData string) (renderedUserData, error) {
+	log := logf.FromContext(ctx)
+
+	settings := r.Settings.Bootstrap()
+	rendered, err := selectUserData(bootstrapData, settings)
+	if !errors.Is(err, ErrBootstrapDataTooLarge) || settings.ObjectStorage == nil {
+		return rendered, err
+	}
+
+	storage, err := r.newBootstrapObjectStorage(ctx, settings.ObjectStorage)
+	if err != nil {
+		return rendered, err
+	}
+	key := fmt.Sprintf("capc/%s/%s-%d.yaml", contaboCluster.Spec.ClusterUUID, contaboMachine.UID, time.Now().Unix())
+	if err := storage.put(ctx, key, []byte(bootstrapData)); err != nil {
+		return rendered, err
+	}
+	log.Info("Uploaded bootstrap data to object storage", "bucket", storage.bucket, "key", key, "size", len(bootstrapData))
+
+	rendered.userData = includeUserData(storage.presign(http.MethodGet, key, time.Now(), storage.expiry))
+	rendered.status.Mode = infrastructurev1beta2.ContaboUserDataModeObjectStorage
+	rendered.status.Size = int32(len(rendered.userData))
+	rendered.status.ObjectKey = key
+	return rendered, nil
+}
+
+// deleteUserDataObject deletes the bootstrap data of the instance from the object storage once it was fetched
+func (r *ContaboMachineReconciler) deleteUserDataObject(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	log := logf.FromContext(ctx)
+
+	userData := contaboMachine.Status.UserData
+	if userData == nil || userData.ObjectKey == "" {
+		return
+	}
+	settings := r.Settings.Bootstrap()
+	if settings.ObjectStorage == nil {
+		log.Info("Object storage no longer configured, leaving the bootstrap data in the bucket", "key", userData.ObjectKey)
+		userData.ObjectKey = ""
+		return
+	}
+	storage, err := r.newBootstrapObjectStorage(ctx, settings.ObjectStorage)
+	if err == nil {
+		err = storage.delete(ctx, userData.ObjectKey)
+	}
+	if err != nil {
+		log.Error(err, "Failed to delete bootstrap data from object storage", "key", userData.ObjectKey)
+		return
+	}
+	log.Info("Deleted bootstrap data from object storage", "key", userData.ObjectKey)
+	userData.ObjectKey = ""
+}
+
+// bootstrapObjectStorage is an S3 compatible bucket signing its requests with AWS signature version 4
+type bootstrapObjectStorage struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	expiry    time.Duration
+}
+
+// newBootstrapObjectStorage returns the bucket of the bootstrap settings with the credentials of its Secret
+func (r *ContaboMachineReconciler) newBootstrapObjectStorage(ctx context.Context, spec *infrastructurev1beta2.ContaboBootstrapObjectStorage) (*bootstrapObjectStorage, error) {
+	endpoint, err := url.Parse(spec.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object storage endpoint %q: %w", spec.Endpoint, err)
+	}
+	credentials := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: spec.CredentialsSecretRef.Namespace, Name: spec.CredentialsSecretRef.Name}, credentials); err != nil {
+		return nil, fmt.Errorf("failed to get object storage credentials: %w", err)
+	}
+	storage := &bootstrapObjectStorage{
+		endpoint:  endpoint,
+		region:    spec.Region,
+		bucket:    spec.Bucket,
+		accessKey: string(credentials.Data["accessKey"]),
+		secretKey: string(credentials.Data["secretKey"]),
+		expiry:    DefaultBootstrapURLExpiry,
+	}
+	if storage.accessKey == "" || storage.secretKey == "" {
+		return nil, fmt.Errorf("object storage credentials secret %s/%s is missing 'accessKey' or 'secretKey' key", credentials.Namespace, credentials.Name)
+	}
+	if storage.region == "" {
+		storage.region = DefaultObjectStorageRegion
+	}
+	if spec.URLExpiry != nil && spec.URLExpiry.Duration > 0 {
+		storage.expiry = spec.URLExpiry.Duration
+	}
+	return storage, nil
+}
+
+// put uploads the object to the bucket
+func (s *bootstrapObjectStorage) put(ctx context.Context, key string, data []byte) error {
+	return s.do(ctx, http.MethodPut, key, data)
+}
+
+// delete removes the object from the bucket, deleting a missing object succeeds
+func (s *bootstrapObjectStorage) delete(ctx context.Context, key string) error {
+	return s.do(ctx, http.MethodDelete, key, nil)
+}
+
+// do sends a request signed with a short-lived presigned URL
+func (s *bootstrapObjectStorage) do(ctx context.Context, method string, key string, body []byte) error {
+	status, message, err := s.doRequest(ctx, method, "/"+s.bucket+"/"+key, nil, body)
+	if err != nil {
+		return fmt.Errorf("object storage %s %s failed: %w", method, key, err)
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("object storage %s %s failed: status %d: %s", method, key, status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// doRequest sends a request of the path and query signed with a short-lived presigned URL and returns the status and
+// the first MiB of the response body
+func (s *bootstrapObjectStorage) doRequest(ctx context.Context, method string, path string, params map[string]string, body []byte) (int, []byte, error) {
+	signed := presignURLQuery(method, s.endpoint, path, params, s.accessKey, s.secretKey, s.region, time.Now(), 5*time.Minute)
+	req, err := http.NewRequestWithContext(ctx, method, signed, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := objectStorageHTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	message, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, message, nil
+}
+
+// presign returns the path-style URL of the object signed with AWS signature version 4 query parameters
+func (s *bootstrapObjectStorage) presign(method string, key string, now time.Time, expiry time.Duration) string {
+	return presignURL(method, s.endpoint, "/"+s.bucket+"/"+key, s.accessKey, s.secretKey, s.region, now, expiry)
+}
+
+// presignURL signs the request of the path with AWS signature version 4 query parameters and an unsigned payload
+func presignURL(method string, endpoint *url.URL, path string, accessKey string, secretKey string, region string, now time.Time, expiry time.Duration) string {
+	return presignURLQuery(method, endpoint, path, nil, accessKey, secretKey, region, now, expiry)
+}
+
+// presignURLQuery signs the request of the path and query parameters, e.g. to list the objects of a bucket
+func presignURLQuery(method string, endpoint *url.URL, path string, params map[string]string, accessKey string, secretKey string, region string, now time.Time, expiry time.Duration) string {
+	now = now.UTC()
+	date := now.Format("20060102")
+	amzDate := now.Format("20060102T150405Z")
+	scope := date + "/" + region + "/s3/aws4_request"
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    accessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       fmt.Sprintf("%d", int64(expiry.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	for name, value := range params {
+		query[name] = value
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalQuery := make([]string, 0, len(names))
+	for _, name := range names {
+		canonicalQuery = append(canonicalQuery, sigV4Escape(name, true)+"="+sigV4Escape(query[name], true))
+	}
+
+	canonicalPath := sigV4Escape(path, false)
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalPath,
+		strings.Join(canonicalQuery, "&"),
+		"host:" + endpoint.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return endpoint.Scheme + "://" + endpoint.Host + canonicalPath + "?" + strings.Join(canonicalQuery, "&") + "&X-Amz-Signature=" + signature
+}
+
+// sigV4Escape URI encodes the value as AWS signature version 4 expects, keeping slashes of paths
+func sigV4Escape(value string, escapeSlash bool) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') || b == '-' || b == '_' || b == '.' || b == '~' || (b == '/' && !escapeSlash) {
+			escaped.WriteByte(b)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.yaml.in/yaml/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// The fuzz tests run their seeds with go test, and explore the user data rendering with e.g.
+// go test ./internal/controller -run '^$' -fuzz FuzzSelectUserData -fuzztime 1m
+
+const fuzzKubeadmBootstrapData = `## template: jinja
+#cloud-config
+write_files:
+- path: /run/kubeadm/kubeadm-join-config.yaml
+  owner: root:root
+  permissions: '0640'
+  content: |
+    apiVersion: kubeadm.k8s.io/v1beta4
+    kind: JoinConfiguration
+    nodeRegistration:
+      name: '{{ ds.meta_data.local_hostname }}'
+      kubeletExtraArgs:
+      - name: node-ip
+        value: 1.2.3.4
+runcmd:
+- kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml
+`
+
+const fuzzK3sBootstrapData = `write_files:
+- path: /etc/rancher/k3s/config.yaml
+  content: |
+    token: secret
+    node-label: env=prod
+runcmd:
+- curl -sfL https://get.k3s.io | sh -s - server
+`
+
+// fuzzCloudConfig decodes the cloud-config, failing when it is not a mapping
+func fuzzCloudConfig(t *testing.T, cloudConfig []byte) map[string]interface{} {
+	t.Helper()
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(cloudConfig, &parsed); err != nil {
+		t.Fatalf("rendered cloud-config is not valid: %v\n%s", err, cloudConfig)
+	}
+	return parsed
+}
+
+// fuzzKeys returns the sorted top-level keys of the cloud-config
+func fuzzKeys(cloudConfig map[string]interface{}) []string {
+	keys := make([]string, 0, len(cloudConfig))
+	for key := range cloudConfig {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func FuzzMergeCloudConfig(f *testing.F) {
+	f.Add(workerCloudConfig, fuzzKubeadmBootstrapData)
+	f.Add(controlplaneCloudConfig, fuzzKubeadmBootstrapData)
+	f.Add(baseCloudConfig, fuzzK3sBootstrapData)
+	f.Add("runcmd: [a]", "runcmd: b")
+	f.Add("null", "write_files: [{path: /a}]")
+	f.Add("", "")
+	f.Fuzz(func(t *testing.T, cloudConfig string, bootstrapData string) {
+		var config, data map[string]interface{}
+		if yaml.Unmarshal([]byte(cloudConfig), &config) != nil || yaml.Unmarshal([]byte(bootstrapData), &data) != nil {
+			return
+		}
+
+		merged, err := mergeCloudConfig([]byte(cloudConfig), []byte(bootstrapData))
+		if err != nil {
+			return
+		}
+		parsed := fuzzCloudConfig(t, merged)
+		for _, key := range append(fuzzKeys(config), fuzzKeys(data)...) {
+			if _, ok := parsed[key]; !ok {
+				t.Fatalf("merged cloud-config lost key %q\n%s", key, merged)
+			}
+		}
+		// The commands of the bootstrap data run after the ones of the provider cloud-config
+		configCommands, configOk := config["runcmd"].([]interface{})
+		dataCommands, dataOk := data["runcmd"].([]interface{})
+		if configOk && dataOk {
+			if commands, _ := parsed["runcmd"].([]interface{}); len(commands) != len(configCommands)+len(dataCommands) {
+				t.Fatalf("merged cloud-config has %d commands, expected %d", len(commands), len(configCommands)+len(dataCommands))
+			}
+		}
+	})
+}
+
+func FuzzInjectBootstrapData(f *testing.F) {
+	f.Add(fuzzKubeadmBootstrapData, "worker-0", "tier", "core", uint16(6443))
+	f.Add(fuzzK3sBootstrapData, "Worker 0", "node-role.kubernetes.io/edge", "", uint16(0))
+	f.Add("write_files: [{path: /etc/rancher/rke2/config.yaml, content: '[a]'}]", "", "a", "b", uint16(1))
+	f.Add("write_files: [{content: 'kind: JoinConfiguration\nnodeRegistration: 1'}]", "n", "a", "b", uint16(443))
+	f.Fuzz(func(t *testing.T, bootstrapData string, nodeName string, labelKey string, labelValue string, port uint16) {
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{
+			Spec: infrastructurev1beta2.ContaboMachineSpec{
+				NodeLabels: map[string]string{labelKey: labelValue},
+				NodeTaints: []corev1.Taint{{Key: labelKey, Value: labelValue, Effect: corev1.TaintEffectNoSchedule}},
+			},
+			Status: infrastructurev1beta2.ContaboMachineStatus{NodeName: nodeName},
+		}
+		contaboMachine.Labels = map[string]string{clusterv1.MachineControlPlaneLabel: ""}
+
+		data := []byte(bootstrapData)
+		flavor := detectBootstrapFlavor(data)
+		var err error
+		if data, err = injectKubeletExtraArgs(data, contaboKubeletExtraArgs(contaboMachine, "10.0.0.2")); err != nil {
+			return
+		}
+		if data, err = injectNodeLabelsAndTaints(data, contaboMachine.Spec.NodeLabels, contaboMachine.Spec.NodeTaints); err != nil {
+			return
+		}
+		if data, err = injectAPIServerBindPort(data, int32(port)); err != nil {
+			return
+		}
+		if flavor != bootstrapFlavorKubeadm {
+			if data, err = injectRancherConfig(data, flavor, contaboRancherConfig(flavor, contaboMachine, "10.0.0.2", int32(port))); err != nil {
+				return
+			}
+		}
+		fuzzCloudConfig(t, data)
+		if flavor != detectBootstrapFlavor(data) {
+			t.Fatalf("injecting the machine settings changed the distribution from %s to %s\n%s", flavor, detectBootstrapFlavor(data), data)
+		}
+	})
+}
+
+func FuzzRenderCloudConfigVariables(f *testing.F) {
+	f.Add("00000000-0000-0000-0000-00000000f022", "10.0.0.0/22", "v1.31.2", "contabo://123")
+	f.Add("'quoted' # comment", "[a, b]", "v1", "{a: b}")
+	f.Add("", "${CLUSTER_UUID}", "1.2", "- item")
+	f.Add("a\nb: c", "10.0.0.0/22", "v1.31.2", "contabo://123")
+	merged, err := mergeCloudConfig([]byte(controlplaneCloudConfig), []byte(fuzzKubeadmBootstrapData))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Fuzz(func(t *testing.T, clusterUUID string, cidr string, version string, providerID string) {
+		kubeadmVersion, err := kubeadmPackageVersion(version)
+		if err != nil {
+			return
+		}
+		rendered, err := renderCloudConfigVariables(string(merged), map[string]string{
+			"KUBEADM_VERSION":    kubeadmVersion,
+			"INTERNAL_IPV4_CIDR": cidr,
+			"PROVIDER_ID":        providerID,
+			"CLUSTER_UUID":       clusterUUID,
+		})
+		if err != nil {
+			if !strings.ContainsAny(clusterUUID+cidr+version+providerID, "\r\n") {
+				t.Fatalf("failed to render single line variables: %v", err)
+			}
+			return
+		}
+
+		// The values never change the structure of the cloud-config, and are written as is
+		parsed := fuzzCloudConfig(t, []byte(rendered))
+		if !slices.Equal(fuzzKeys(parsed), fuzzKeys(fuzzCloudConfig(t, merged))) {
+			t.Fatalf("rendering the variables changed the cloud-config keys to %v", fuzzKeys(parsed))
+		}
+		writeFiles, _ := parsed["write_files"].([]interface{})
+		for _, file := range writeFiles {
+			if file, _ := file.(map[interface{}]interface{}); file["path"] == "/etc/cluster-uuid" && file["content"] != clusterUUID {
+				t.Fatalf("cluster UUID file has content %#v, expected %q", file["content"], clusterUUID)
+			}
+		}
+	})
+}
+
+// fuzzGunzipUserData decodes the bootstrap data of the MIME multipart user data, as cloud-init does
+func fuzzGunzipUserData(t *testing.T, userData string) string {
+	t.Helper()
+	message, err := mail.ReadMessage(strings.NewReader(userData))
+	if err != nil {
+		t.Fatalf("user data is not a MIME message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("user data has content type %q: %v", mediaType, err)
+	}
+	part, err := multipart.NewReader(message.Body, params["boundary"]).NextPart()
+	if err != nil {
+		t.Fatalf("user data has no part: %v", err)
+	}
+	if contentType := part.Header.Get("Content-Type"); contentType != "application/x-gzip" {
+		t.Fatalf("user data part has content type %q", contentType)
+	}
+	reader, err := gzip.NewReader(base64.NewDecoder(base64.StdEncoding, part))
+	if err != nil {
+		t.Fatalf("user data part is not gzipped: %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to decompress user data part: %v", err)
+	}
+	return string(data)
+}
+
+func FuzzSelectUserData(f *testing.F) {
+	f.Add(workerCloudConfig, uint8(0), uint16(DefaultMaxUserDataSize))
+	f.Add(controlplaneCloudConfig, uint8(1), uint16(1024))
+	f.Add(strings.Repeat("runcmd: [a]\n", 2000), uint8(2), uint16(1024))
+	f.Add("", uint8(1), uint16(0))
+	f.Add("--"+userDataMIMEBoundary+"--\n\x00\xff", uint8(3), uint16(2048))
+	compressions := []infrastructurev1beta2.ContaboUserDataCompression{
+		"",
+		infrastructurev1beta2.ContaboUserDataCompressionAuto,
+		infrastructurev1beta2.ContaboUserDataCompressionAlways,
+		infrastructurev1beta2.ContaboUserDataCompressionNever,
+	}
+	f.Fuzz(func(t *testing.T, bootstrapData string, compression uint8, maxSize uint16) {
+		// The smallest maximum user data size allowed by the provider settings
+		maxUserDataSize := max(int32(maxSize), 1024)
+		settings := infrastructurev1beta2.ContaboBootstrapSettings{
+			MaxUserDataSize: ptr.To(maxUserDataSize),
+			Compression:     compressions[int(compression)%len(compressions)],
+		}
+
+		rendered, err := selectUserData(bootstrapData, settings)
+		if err != nil && !errors.Is(err, ErrBootstrapDataTooLarge) {
+			t.Fatalf("failed to select user data: %v", err)
+		}
+		if (err != nil) != (len(rendered.userData) > int(maxUserDataSize)) {
+			t.Fatalf("user data of %d bytes for a maximum of %d bytes returned %v", len(rendered.userData), maxUserDataSize, err)
+		}
+		if rendered.status.Size != int32(len(rendered.userData)) || rendered.status.BootstrapDataSize != int32(len(bootstrapData)) {
+			t.Fatalf("user data status %+v does not match %d bytes of user data for %d bytes of bootstrap data",
+				rendered.status, len(rendered.userData), len(bootstrapData))
+		}
+
+		switch rendered.status.Mode {
+		case infrastructurev1beta2.ContaboUserDataModePlain:
+			if rendered.userData != bootstrapData {
+				t.Fatalf("plain user data differs from the bootstrap data")
+			}
+			if settings.Compression == infrastructurev1beta2.ContaboUserDataCompressionAlways {
+				t.Fatalf("bootstrap data not compressed with the Always compression")
+			}
+			if !utf8.ValidString(bootstrapData) {
+				t.Fatalf("binary bootstrap data sent as plain user data")
+			}
+		case infrastructurev1beta2.ContaboUserDataModeGzip:
+			// Binary bootstrap data is compressed whatever the compression, the user data is a JSON string
+			if settings.Compression == infrastructurev1beta2.ContaboUserDataCompressionNever && utf8.ValidString(bootstrapData) {
+				t.Fatalf("bootstrap data compressed with the Never compression")
+			}
+			// The user data is passed as a JSON string to the Contabo API
+			if strings.ContainsFunc(rendered.userData, func(r rune) bool { return r > 0x7e || (r < 0x20 && r != '\n') }) {
+				t.Fatalf("gzipped user data is not printable ASCII")
+			}
+			if data := fuzzGunzipUserData(t, rendered.userData); data != bootstrapData {
+				t.Fatalf("gzipped user data decompresses to %d bytes, expected %d bytes", len(data), len(bootstrapData))
+			}
+		default:
+			t.Fatalf("unexpected user data mode %q", rendered.status.Mode)
+		}
+	})
+}
+
+func FuzzMachineCloudConfigs(f *testing.F) {
+	f.Add("worker-0", "1.1.1.1", "example.com", "version: 2\nethernets: {eth1: {dhcp4: true}}", "10.0.0.1")
+	f.Add("'a' # b", "[::1]", "a: b", "network: {version: 2}", "")
+	f.Add("", "", "", "version: '2'", "- x")
+	f.Fuzz(func(t *testing.T, nodeName string, nameserver string, searchDomain string, networkConfig string, natGateway string) {
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{
+			Spec: infrastructurev1beta2.ContaboMachineSpec{
+				DNS:           &infrastructurev1beta2.ContaboDNSSpec{Nameservers: []string{nameserver}, SearchDomains: []string{searchDomain}},
+				NetworkConfig: &networkConfig,
+				PrivateOnly:   &infrastructurev1beta2.ContaboPrivateOnlySpec{NATGateway: natGateway},
+			},
+		}
+		contaboCluster := &infrastructurev1beta2.ContaboCluster{}
+
+		cloudConfig := []byte(workerCloudConfig)
+		renderers := map[string]func() ([]byte, error){
+			"hostname":       func() ([]byte, error) { return hostnameCloudConfig(nodeName) },
+			"dns":            func() ([]byte, error) { return dnsCloudConfig(contaboMachine) },
+			"network-config": func() ([]byte, error) { return networkCloudConfig(contaboMachine) },
+			"private-only":   func() ([]byte, error) { return privateOnlyCloudConfig(contaboMachine, contaboCluster) },
+		}
+		for _, name := range []string{"hostname", "dns", "network-config", "private-only"} {
+			config, err := renderers[name]()
+			if err != nil || config == nil {
+				continue
+			}
+			fuzzCloudConfig(t, config)
+			merged, err := mergeCloudConfig(config, cloudConfig)
+			if err != nil {
+				t.Fatalf("failed to merge the %s cloud-config: %v\n%s", name, err, config)
+			}
+			cloudConfig = merged
+		}
+
+		parsed := fuzzCloudConfig(t, cloudConfig)
+		if hostname, ok := parsed["hostname"]; ok && hostname != nodeName && !(nodeName == "" && hostname == nil) {
+			t.Fatalf("hostname %#v differs from the node name %q", hostname, nodeName)
+		}
+		if !bytes.Contains(cloudConfig, []byte("kubeadm")) {
+			t.Fatalf("merged cloud-config lost the provider cloud-config")
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/auth"
+)
+
+// Keys of the Secret referenced by the credentialsRef of a ContaboCluster
+const (
+	CredentialsClientIDKey     = "clientId"
+	CredentialsClientSecretKey = "clientSecret"
+	CredentialsAPIUserKey      = "apiUser"
+	CredentialsAPIPasswordKey  = "apiPassword"
+)
+
+// ExternalSecretListGVK is the kind of the ExternalSecrets of the External Secrets Operator, which can sync the
+// credentials Secrets from a secret store. They are read as unstructured objects, the operator is optional.
+var ExternalSecretListGVK = schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1", Kind: "ExternalSecretList"}
+
+// ClusterCredentials authorizes the Contabo API requests of the clusters referencing their own credentials, the
+// other clusters use the credentials of the controller. A token manager is kept per Secret, so that the access
+// tokens are shared by the reconciliations, replaced when the Secret changes and evicted when the Secret or the
+// cluster is deleted.
+type ClusterCredentials struct {
+	Client client.Client
+
+	mu            sync.Mutex
+	tokenManagers map[types.NamespacedName]clusterTokenManager
+}
+
+// clusterTokenManager is the token manager built from a version of a credentials Secret
+type clusterTokenManager struct {
+	resourceVersion string
+	tokenManager    *auth.TokenManager
+}
+
+// NewClusterCredentials returns the cluster credentials reading the Secrets with the given client
+func NewClusterCredentials(c client.Client) *ClusterCredentials {
+	return &ClusterCredentials{
+		Client:        c,
+		tokenManagers: map[types.NamespacedName]clusterTokenManager{},
+	}
+}
+
+// IntoContext returns a context whose Contabo API requests are authorized with the credentials of the ContaboCluster,
+// the context is returned unchanged when the cluster uses the credentials of the controller
+func (c *ClusterCredentials) IntoContext(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) (context.Context, error) {
+	ref := contaboCluster.Spec.CredentialsRef
+	if ref == nil {
+		return ctx, nil
+	}
+	if c == nil {
+		return ctx, fmt.Errorf("credentials of ContaboCluster %s are not supported by this controller", contaboCluster.Name)
+	}
+
+	key := types.NamespacedName{Namespace: contaboCluster.Namespace, Name: ref.Name}
+	secret := &corev1.Secret{}
+	if err := c.Client.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			c.evict(key)
+			if status := c.externalSecretStatus(ctx, key); status != "" {
+				return ctx, fmt.Errorf("credentials Secret %s is not synced yet: %s", ref.Name, status)
+			}
+		}
+		return ctx, fmt.Errorf("failed to get credentials Secret %s: %w", ref.Name, err)
+	}
+	for _, k := range []string{CredentialsClientIDKey, CredentialsClientSecretKey, CredentialsAPIUserKey, CredentialsAPIPasswordKey} {
+		if len(secret.Data[k]) == 0 {
+			c.evict(key)
+			return ctx, fmt.Errorf("credentials Secret %s is missing the %s key", ref.Name, k)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tokenManagers == nil {
+		c.tokenManagers = map[types.NamespacedName]clusterTokenManager{}
+	}
+	cached, ok := c.tokenManagers[key]
+	if !ok || cached.resourceVersion != secret.ResourceVersion {
+		// Replace the token manager of the former version of the Secret, its access token is dropped with it
+		cached = clusterTokenManager{
+			resourceVersion: secret.ResourceVersion,
+			tokenManager: auth.NewTokenManager(
+				string(secret.Data[CredentialsClientIDKey]),
+				string(secret.Data[CredentialsClientSecretKey]),
+				string(secret.Data[CredentialsAPIUserKey]),
+				string(secret.Data[CredentialsAPIPasswordKey]),
+			),
+		}
+		c.tokenManagers[key] = cached
+	}
+	return auth.WithTokenManager(ctx, cached.tokenManager), nil
+}
+
+// secretMissing returns whether the credentials Secret referenced by the ContaboCluster does not exist, e.g. deleted
+// before the cluster
+func (c *ClusterCredentials) secretMissing(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) bool {
+	ref := contaboCluster.Spec.CredentialsRef
+	if c == nil || ref == nil {
+		return false
+	}
+	err := c.Client.Get(ctx, types.NamespacedName{Namespace: contaboCluster.Namespace, Name: ref.Name}, &corev1.Secret{})
+	return apierrors.IsNotFound(err)
+}
+
+// forget evicts the token manager of the credentials of a deleted ContaboCluster, the other clusters referencing the
+// same Secret build a new one on their next reconciliation
+func (c *ClusterCredentials) forget(contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	if c == nil || contaboCluster.Spec.CredentialsRef == nil {
+		return
+	}
+	c.evict(types.NamespacedName{Namespace: contaboCluster.Namespace, Name: contaboCluster.Spec.CredentialsRef.Name})
+}
+
+// evict drops the token manager of the credentials Secret
+func (c *ClusterCredentials) evict(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tokenManagers, key)
+}
+
+// externalSecretStatus returns the status of the ExternalSecret syncing the credentials Secret, so that the
+// ContaboCluster tells why its Secret is missing, e.g. the secret store rejecting the operator. It returns an empty
+// string when no ExternalSecret targets the Secret or the External Secrets Operator is not installed.
+func (c *ClusterCredentials) externalSecretStatus(ctx context.Context, key types.NamespacedName) string {
+	externalSecrets := &unstructured.UnstructuredList{}
+	externalSecrets.SetGroupVersionKind(ExternalSecretListGVK)
+	if err := c.Client.List(ctx, externalSecrets, client.InNamespace(key.Namespace)); err != nil {
+		if !meta.IsNoMatchError(err) {
+			log.FromContext(ctx).V(1).Info("Failed to list the ExternalSecrets", "error", err.Error())
+		}
+		return ""
+	}
+	for _, externalSecret := range externalSecrets.Items {
+		// The target Secret is named after the ExternalSecret by default
+		target, _, _ := unstructured.NestedString(externalSecret.Object, "spec", "target", "name")
+		if target == "" {
+			target = externalSecret.GetName()
+		}
+		if target != key.Name {
+			continue
+		}
+		statusConditions, _, _ := unstructured.NestedSlice(externalSecret.Object, "status", "conditions")
+		for _, statusCondition := range statusConditions {
+			condition, ok := statusCondition.(map[string]interface{})
+			if !ok || condition["type"] != "Ready" {
+				continue
+			}
+			return fmt.Sprintf("ExternalSecret %s is Ready=%v, %v: %v", externalSecret.GetName(),
+				condition["status"], condition["reason"], condition["message"])
+		}
+		return fmt.Sprintf("ExternalSecret %s has not reported its status yet", externalSecret.GetName())
+	}
+	return ""
+}
+
+// IntoContextForMachine returns a context whose Contabo API requests are authorized with the credentials of the
+// ContaboCluster of the machine
+func (c *ClusterCredentials) IntoContextForMachine(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) (context.Context, error) {
+	if c == nil {
+		return ctx, nil
+	}
+	cluster, err := util.GetClusterFromMetadata(ctx, c.Client, contaboMachine.ObjectMeta)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to get the Cluster of ContaboMachine %s: %w", contaboMachine.Name, err)
+	}
+	contaboCluster := &infrastructurev1beta2.ContaboCluster{}
+	if err := c.Client.Get(ctx, client.ObjectKey{Namespace: contaboMachine.Namespace, Name: cluster.Spec.InfrastructureRef.Name}, contaboCluster); err != nil {
+		return ctx, fmt.Errorf("failed to get the ContaboCluster of ContaboMachine %s: %w", contaboMachine.Name, err)
+	}
+	return c.IntoContext(ctx, contaboCluster)
+}
+
+// setCredentialsValidCondition sets the CredentialsValid condition of the ContaboCluster from the error of its
+// reconciliation, which always requests the Contabo API. The condition is left unchanged by the other errors, they
+// tell nothing about the credentials.
+func setCredentialsValidCondition(contaboCluster *infrastructurev1beta2.ContaboCluster, err error) {
+	message := "The Contabo API accepted the credentials of the cluster"
+	if contaboCluster.Spec.CredentialsRef == nil {
+		message = "The Contabo API accepted the credentials of the controller"
+	}
+	condition := metav1.Condition{
+		Type:    infrastructurev1beta2.ClusterCredentialsValidCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.CredentialsValidReason,
+		Message: message,
+	}
+	switch {
+	case errors.Is(err, auth.ErrAccessToken):
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, infrastructurev1beta2.AccessTokenFailedReason, err.Error()
+	case errors.Is(err, contabo.ErrUnauthorized):
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, infrastructurev1beta2.CredentialsRejectedReason, err.Error()
+	case err != nil:
+		return
+	}
+	conditions.Set(contaboCluster, condition)
+}
@@ -53,10 +53,12 @@ const (
 	// ClusterUUIDLabel is the label key used to store the unique cluster UUID
 	ClusterUUIDLabel = "cluster.x-k8s.io/capc-uuid"
 
-	// DefaultUbuntuImageID is the standardized Ubuntu image used for all cluster nodes
+	// DefaultUbuntuImageID is the standardized Ubuntu image used for the cluster nodes without an image
 	// Using a fixed image ensures consistency, security, and compatibility across the cluster
-	// This should be Ubuntu 24.04 LTS - you may need to adjust this ID based on available images in Contabo
-	DefaultUbuntuImageID = "d64d5c6c-9dda-4e38-8174-0ee282474d8a"
+	DefaultUbuntuImageID = infrastructurev1beta2.DefaultImageId
+
+	// DefaultAPIServerPort is the API server port used when the control plane endpoint port is not set
+	DefaultAPIServerPort int32 = 6443
 )
 
 // BuildProviderID constructs a provider ID from an instance ID
@@ -216,19 +218,32 @@ func Truncate[T []any | string](s T, maxLength int) T {
 	}
 }
 
-func FormatDisplayName(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) string {
-	// Determine role-based name
-	var roleName string
+// machineRoleName returns the role of the machine in its name: control-plane, its MachineDeployment or worker
+func machineRoleName(contaboMachine *infrastructurev1beta2.ContaboMachine) string {
 	if _, isControlPlane := contaboMachine.Labels[clusterv1.MachineControlPlaneLabel]; isControlPlane {
-		roleName = "control-plane"
+		return "control-plane"
 	} else if poolName, hasPool := contaboMachine.Labels[clusterv1.MachineDeploymentNameLabel]; hasPool {
-		roleName = poolName
-	} else {
-		roleName = "worker"
+		return poolName
 	}
+	return "worker"
+}
 
+func FormatDisplayName(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) string {
 	// Format: [capc] <clusterUUID> <role>-<index>
-	return Truncate(fmt.Sprintf("[capc] %s %s-%d", contaboCluster.Spec.ClusterUUID, roleName, *contaboMachine.Spec.Index), 255)
+	return Truncate(fmt.Sprintf("[capc] %s %s-%d", contaboCluster.Spec.ClusterUUID, machineRoleName(contaboMachine), *contaboMachine.Spec.Index), 255)
+}
+
+// parkedDisplayName is the display name reserving the instances parked by the ContaboMachinePools, see
+// ParkedInstanceAnnotation
+const parkedDisplayName = "[capc] parked"
+
+// releasedDisplayName returns the display name an instance is released with by its deleted machine, reserved when the
+// ContaboMachinePool of the machine parked it, empty to return it to the free instances otherwise
+func releasedDisplayName(contaboMachine *infrastructurev1beta2.ContaboMachine) string {
+	if _, parked := contaboMachine.Annotations[infrastructurev1beta2.ParkedInstanceAnnotation]; parked && !contaboMachine.DeletionTimestamp.IsZero() {
+		return parkedDisplayName
+	}
+	return ""
 }
 
 func FormatSshKeyContaboName(contaboCluster *infrastructurev1beta2.ContaboCluster) string {
@@ -0,0 +1,397 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// inventoryPageSize is the page size used to list the account resources
+	inventoryPageSize = 100
+
+	// inventoryNamePrefix is the prefix of the names given by the provider to the Contabo resources
+	inventoryNamePrefix = "[capc]"
+)
+
+// ContaboAccountInventoryReconciler maintains the ContaboAccountInventory summarizing the resources of the account
+type ContaboAccountInventoryReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	ContaboClient *contaboclient.ClientWithResponses
+	Settings      *ProviderSettings
+	Recorder      record.EventRecorder
+}
+
+// inventoryOwners maps the Contabo resources used by the ContaboClusters and ContaboMachines to their owner
+type inventoryOwners struct {
+	instances       map[string]string
+	privateNetworks map[string]string
+	images          map[string]string
+	endpoints       map[string]string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboaccountinventories,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboaccountinventories/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile refreshes the ContaboAccountInventory from the Contabo API every InventoryInterval
+func (r *ContaboAccountInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if req.Name != infrastructurev1beta2.ContaboAccountInventoryName {
+		log.Info("Ignoring ContaboAccountInventory, only the default inventory is maintained", "name", req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	inventory := &infrastructurev1beta2.ContaboAccountInventory{}
+	if err := r.Get(ctx, req.NamespacedName, inventory); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("ContaboAccountInventory removed, recreating it")
+			return ctrl.Result{}, r.ensureInventory(ctx)
+		}
+		return ctrl.Result{}, err
+	}
+
+	interval := r.Settings.InventoryInterval()
+	if last := inventory.Status.LastUpdated; last != nil && time.Since(last.Time) < interval {
+		return ctrl.Result{RequeueAfter: interval - time.Since(last.Time)}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(inventory, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	previous := inventory.Status.DeepCopy()
+	if err := r.refreshInventory(ctx, inventory); err != nil {
+		log.Error(err, "Failed to refresh the ContaboAccountInventory")
+		meta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InventoryUpToDateCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InventoryRefreshFailedReason,
+			Message: err.Error(),
+		})
+		if patchErr := patchHelper.Patch(ctx, inventory); patchErr != nil {
+			return ctrl.Result{}, patchErr
+		}
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	// The first refresh only records the orphans, they are reported by the summary
+	if orphans := newInventoryOrphans(previous, &inventory.Status); previous.LastUpdated != nil && len(orphans) > 0 {
+		log.Info("Found new orphaned resources in the Contabo account", "orphans", orphans)
+		r.Recorder.Eventf(inventory, corev1.EventTypeWarning, infrastructurev1beta2.InventoryOrphanedResourcesReason,
+			"Found %d new orphaned resources: %s", len(orphans), Truncate(strings.Join(orphans, ", "), 1024))
+	}
+
+	inventory.Status.LastUpdated = ptr.To(metav1.Now())
+	meta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+		Type:   infrastructurev1beta2.InventoryUpToDateCondition,
+		Status: metav1.ConditionTrue,
+		Reason: infrastructurev1beta2.InventoryRefreshedReason,
+	})
+	log.Info("Refreshed ContaboAccountInventory",
+		"instances", inventory.Status.Summary.Instances.Total,
+		"privateNetworks", inventory.Status.Summary.PrivateNetworks.Total,
+		"images", inventory.Status.Summary.Images.Total,
+		"vips", inventory.Status.Summary.VIPs.Total)
+
+	return ctrl.Result{RequeueAfter: interval}, patchHelper.Patch(ctx, inventory)
+}
+
+// ensureInventory creates the default ContaboAccountInventory if missing
+func (r *ContaboAccountInventoryReconciler) ensureInventory(ctx context.Context) error {
+	inventory := &infrastructurev1beta2.ContaboAccountInventory{
+		ObjectMeta: metav1.ObjectMeta{Name: infrastructurev1beta2.ContaboAccountInventoryName},
+	}
+	if err := r.Create(ctx, inventory); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create the ContaboAccountInventory: %w", err)
+	}
+	return nil
+}
+
+// refreshInventory lists the instances, private networks, custom images and VIPs of the account and classifies them
+func (r *ContaboAccountInventoryReconciler) refreshInventory(ctx context.Context, inventory *infrastructurev1beta2.ContaboAccountInventory) error {
+	owners, err := r.listInventoryOwners(ctx)
+	if err != nil {
+		return err
+	}
+
+	instances := []infrastructurev1beta2.ContaboInventoryItem{}
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{
+			Page: &page,
+			Size: ptr.To(int64(inventoryPageSize)),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to list instances: %w", err)
+		}
+		for _, instance := range resp.JSON200.Data {
+			instances = append(instances, newInventoryItem(strconv.FormatInt(instance.InstanceId, 10), instance.DisplayName, instance.Region, string(instance.Status), owners.instances))
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			break
+		}
+	}
+
+	privateNetworks := []infrastructurev1beta2.ContaboInventoryItem{}
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrievePrivateNetworkListWithResponse(ctx, &models.RetrievePrivateNetworkListParams{
+			Page: &page,
+			Size: ptr.To(int64(inventoryPageSize)),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to list private networks: %w", err)
+		}
+		for _, network := range resp.JSON200.Data {
+			privateNetworks = append(privateNetworks, newInventoryItem(strconv.FormatInt(network.PrivateNetworkId, 10), network.Name, network.Region, "", owners.privateNetworks))
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			break
+		}
+	}
+
+	images := []infrastructurev1beta2.ContaboInventoryItem{}
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrieveImageListWithResponse(ctx, &models.RetrieveImageListParams{
+			Page:          &page,
+			Size:          ptr.To(int64(inventoryPageSize)),
+			StandardImage: ptr.To(false),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to list images: %w", err)
+		}
+		for _, image := range resp.JSON200.Data {
+			images = append(images, newInventoryItem(image.ImageId, image.Name, "", image.Status, owners.images))
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			break
+		}
+	}
+
+	// VIPs are owned through the instance they are assigned to, or the control plane endpoint they serve
+	vips := []infrastructurev1beta2.ContaboInventoryItem{}
+	instanceManagement := map[string]infrastructurev1beta2.ContaboInventoryManagement{}
+	for _, instance := range instances {
+		instanceManagement[instance.Id] = instance.Management
+	}
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrieveVipListWithResponse(ctx, &models.RetrieveVipListParams{
+			Page: &page,
+			Size: ptr.To(int64(inventoryPageSize)),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to list VIPs: %w", err)
+		}
+		for _, vip := range resp.JSON200.Data {
+			ip := ""
+			if vip.V4 != nil {
+				ip = vip.V4.Ip
+			}
+			item := infrastructurev1beta2.ContaboInventoryItem{
+				Id:         vip.VipId,
+				Name:       ip,
+				Region:     vip.Region,
+				Management: infrastructurev1beta2.ContaboInventoryUnmanaged,
+			}
+			switch {
+			case owners.endpoints[ip] != "":
+				item.Management = infrastructurev1beta2.ContaboInventoryManaged
+				item.Owner = owners.endpoints[ip]
+			case owners.instances[vip.ResourceId] != "":
+				item.Management = infrastructurev1beta2.ContaboInventoryManaged
+				item.Owner = owners.instances[vip.ResourceId]
+			case instanceManagement[vip.ResourceId] == infrastructurev1beta2.ContaboInventoryOrphan:
+				item.Management = infrastructurev1beta2.ContaboInventoryOrphan
+			}
+			vips = append(vips, item)
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			break
+		}
+	}
+
+	inventory.Status.Instances = sortInventoryItems(instances)
+	inventory.Status.PrivateNetworks = sortInventoryItems(privateNetworks)
+	inventory.Status.Images = sortInventoryItems(images)
+	inventory.Status.VIPs = sortInventoryItems(vips)
+	inventory.Status.Summary = infrastructurev1beta2.ContaboInventorySummary{
+		Instances:       countInventoryItems(instances),
+		PrivateNetworks: countInventoryItems(privateNetworks),
+		Images:          countInventoryItems(images),
+		VIPs:            countInventoryItems(vips),
+	}
+	return nil
+}
+
+// listInventoryOwners returns the Contabo resources used by the ContaboClusters and ContaboMachines of all namespaces
+func (r *ContaboAccountInventoryReconciler) listInventoryOwners(ctx context.Context) (*inventoryOwners, error) {
+	owners := &inventoryOwners{
+		instances:       map[string]string{},
+		privateNetworks: map[string]string{},
+		images:          map[string]string{},
+		endpoints:       map[string]string{},
+	}
+
+	var contaboMachines infrastructurev1beta2.ContaboMachineList
+	if err := r.List(ctx, &contaboMachines); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboMachines: %w", err)
+	}
+	for _, contaboMachine := range contaboMachines.Items {
+		if contaboMachine.Status.Instance == nil {
+			continue
+		}
+		owner := contaboMachine.Namespace + "/" + contaboMachine.Name
+		owners.instances[strconv.FormatInt(contaboMachine.Status.Instance.InstanceId, 10)] = owner
+		if contaboMachine.Status.Instance.ImageId != "" {
+			owners.images[contaboMachine.Status.Instance.ImageId] = owner
+		}
+	}
+
+	var contaboClusters infrastructurev1beta2.ContaboClusterList
+	if err := r.List(ctx, &contaboClusters); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboClusters: %w", err)
+	}
+	for _, contaboCluster := range contaboClusters.Items {
+		owner := contaboCluster.Namespace + "/" + contaboCluster.Name
+		if contaboCluster.Status.PrivateNetwork != nil {
+			owners.privateNetworks[strconv.FormatInt(contaboCluster.Status.PrivateNetwork.PrivateNetworkId, 10)] = owner
+		}
+		if host := contaboCluster.Spec.ControlPlaneEndpoint.Host; host != "" {
+			owners.endpoints[host] = owner
+		}
+	}
+
+	return owners, nil
+}
+
+// newInventoryItem returns the inventory item of a resource, managed when it has an owner, orphan when it is named
+// by the provider without owner, unmanaged otherwise
+func newInventoryItem(id, name, region, status string, owners map[string]string) infrastructurev1beta2.ContaboInventoryItem {
+	item := infrastructurev1beta2.ContaboInventoryItem{
+		Id:         id,
+		Name:       name,
+		Region:     region,
+		Status:     status,
+		Management: infrastructurev1beta2.ContaboInventoryUnmanaged,
+	}
+	switch {
+	case owners[id] != "":
+		item.Management = infrastructurev1beta2.ContaboInventoryManaged
+		item.Owner = owners[id]
+	case strings.HasPrefix(name, inventoryNamePrefix):
+		item.Management = infrastructurev1beta2.ContaboInventoryOrphan
+	}
+	return item
+}
+
+// countInventoryItems counts the items per management status
+func countInventoryItems(items []infrastructurev1beta2.ContaboInventoryItem) infrastructurev1beta2.ContaboInventoryCount {
+	count := infrastructurev1beta2.ContaboInventoryCount{Total: int32(len(items))}
+	for _, item := range items {
+		switch item.Management {
+		case infrastructurev1beta2.ContaboInventoryManaged:
+			count.Managed++
+		case infrastructurev1beta2.ContaboInventoryOrphan:
+			count.Orphan++
+		default:
+			count.Unmanaged++
+		}
+	}
+	return count
+}
+
+// newInventoryOrphans returns the orphaned resources of the current inventory which were not orphans before
+func newInventoryOrphans(previous *infrastructurev1beta2.ContaboAccountInventoryStatus, current *infrastructurev1beta2.ContaboAccountInventoryStatus) []string {
+	orphans := func(status *infrastructurev1beta2.ContaboAccountInventoryStatus) map[string]string {
+		items := map[string]string{}
+		for resource, list := range map[string][]infrastructurev1beta2.ContaboInventoryItem{
+			"instance":        status.Instances,
+			"private network": status.PrivateNetworks,
+			"image":           status.Images,
+			"VIP":             status.VIPs,
+		} {
+			for _, item := range list {
+				if item.Management != infrastructurev1beta2.ContaboInventoryOrphan {
+					continue
+				}
+				description := fmt.Sprintf("%s %s", resource, item.Id)
+				if item.Name != "" {
+					description = fmt.Sprintf("%s (%s)", description, item.Name)
+				}
+				items[resource+"/"+item.Id] = description
+			}
+		}
+		return items
+	}
+
+	known := orphans(previous)
+	found := []string{}
+	for key, description := range orphans(current) {
+		if _, ok := known[key]; !ok {
+			found = append(found, description)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// sortInventoryItems sorts the items by identifier so that unchanged resources do not patch the status
+func sortInventoryItems(items []infrastructurev1beta2.ContaboInventoryItem) []infrastructurev1beta2.ContaboInventoryItem {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Id < items[j].Id
+	})
+	return items
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ContaboAccountInventoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The inventory is created by the elected manager, it is then refreshed on a timer
+	if err := mgr.Add(manager.RunnableFunc(r.ensureInventory)); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1beta2.ContaboAccountInventory{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("contaboaccountinventory").
+		Complete(r)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var _ = Describe("ContaboAccountInventory", func() {
+	Context("When classifying account resources", func() {
+		owners := map[string]string{"1": "default/machine-0"}
+
+		It("should report resources with an owner as managed", func() {
+			item := newInventoryItem("1", "[capc] 1234 worker-0", "EU", "running", owners)
+			Expect(item.Management).To(Equal(infrastructurev1beta2.ContaboInventoryManaged))
+			Expect(item.Owner).To(Equal("default/machine-0"))
+		})
+
+		It("should report provider named resources without owner as orphans", func() {
+			item := newInventoryItem("2", "[capc] 2 order timed out", "EU", "provisioning", owners)
+			Expect(item.Management).To(Equal(infrastructurev1beta2.ContaboInventoryOrphan))
+			Expect(item.Owner).To(BeEmpty())
+		})
+
+		It("should report other resources as unmanaged", func() {
+			Expect(newInventoryItem("3", "", "EU", "running", owners).Management).To(Equal(infrastructurev1beta2.ContaboInventoryUnmanaged))
+			Expect(newInventoryItem("4", "database", "EU", "running", owners).Management).To(Equal(infrastructurev1beta2.ContaboInventoryUnmanaged))
+		})
+
+		It("should count and sort the resources", func() {
+			items := sortInventoryItems([]infrastructurev1beta2.ContaboInventoryItem{
+				newInventoryItem("3", "", "EU", "", owners),
+				newInventoryItem("2", "[capc] old", "EU", "", owners),
+				newInventoryItem("1", "", "EU", "", owners),
+			})
+			Expect(items[0].Id).To(Equal("1"))
+			Expect(countInventoryItems(items)).To(Equal(infrastructurev1beta2.ContaboInventoryCount{
+				Total:     3,
+				Managed:   1,
+				Unmanaged: 1,
+				Orphan:    1,
+			}))
+		})
+	})
+
+	Context("When reporting orphaned resources", func() {
+		It("should only report the resources which were not orphans before", func() {
+			previous := &infrastructurev1beta2.ContaboAccountInventoryStatus{
+				Instances: []infrastructurev1beta2.ContaboInventoryItem{
+					{Id: "1", Name: "[capc] 1 order timed out", Management: infrastructurev1beta2.ContaboInventoryOrphan},
+					{Id: "2", Management: infrastructurev1beta2.ContaboInventoryManaged},
+				},
+			}
+			current := previous.DeepCopy()
+			current.Instances[1].Management = infrastructurev1beta2.ContaboInventoryOrphan
+			current.Images = []infrastructurev1beta2.ContaboInventoryItem{
+				{Id: "img", Name: "[capc] snapshot", Management: infrastructurev1beta2.ContaboInventoryOrphan},
+			}
+
+			Expect(newInventoryOrphans(previous, current)).To(Equal([]string{"image img ([capc] snapshot)", "instance 2"}))
+			Expect(newInventoryOrphans(current, current)).To(BeEmpty())
+		})
+	})
+})
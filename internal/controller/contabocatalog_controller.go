@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// ContaboCatalogReconciler maintains the ContaboCatalog publishing the data centers, standard images and products of
+// Contabo, so that cluster creation tools read them from the management cluster without Contabo credentials
+type ContaboCatalogReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	ContaboClient *contaboclient.ClientWithResponses
+	Settings      *ProviderSettings
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabocatalogs,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabocatalogs/status,verbs=get;update;patch
+
+// Reconcile refreshes the ContaboCatalog from the Contabo API every CatalogInterval
+func (r *ContaboCatalogReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if req.Name != infrastructurev1beta2.ContaboCatalogName {
+		log.Info("Ignoring ContaboCatalog, only the default catalog is maintained", "name", req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	catalog := &infrastructurev1beta2.ContaboCatalog{}
+	if err := r.Get(ctx, req.NamespacedName, catalog); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("ContaboCatalog removed, recreating it")
+			return ctrl.Result{}, r.ensureCatalog(ctx)
+		}
+		return ctrl.Result{}, err
+	}
+
+	interval := r.Settings.CatalogInterval()
+	if last := catalog.Status.LastUpdated; last != nil && time.Since(last.Time) < interval {
+		return ctrl.Result{RequeueAfter: interval - time.Since(last.Time)}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(catalog, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// A failed refresh keeps the previous catalog, stale data is more useful to the UIs than none
+	if err := r.refreshCatalog(ctx, catalog); err != nil {
+		log.Error(err, "Failed to refresh the ContaboCatalog")
+		meta.SetStatusCondition(&catalog.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.CatalogUpToDateCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.CatalogRefreshFailedReason,
+			Message: err.Error(),
+		})
+		if patchErr := patchHelper.Patch(ctx, catalog); patchErr != nil {
+			return ctrl.Result{}, patchErr
+		}
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	catalog.Status.LastUpdated = ptr.To(metav1.Now())
+	meta.SetStatusCondition(&catalog.Status.Conditions, metav1.Condition{
+		Type:   infrastructurev1beta2.CatalogUpToDateCondition,
+		Status: metav1.ConditionTrue,
+		Reason: infrastructurev1beta2.CatalogRefreshedReason,
+	})
+	log.Info("Refreshed ContaboCatalog",
+		"dataCenters", len(catalog.Status.DataCenters),
+		"images", len(catalog.Status.Images),
+		"products", len(catalog.Status.Products))
+
+	return ctrl.Result{RequeueAfter: interval}, patchHelper.Patch(ctx, catalog)
+}
+
+// ensureCatalog creates the default ContaboCatalog if missing
+func (r *ContaboCatalogReconciler) ensureCatalog(ctx context.Context) error {
+	catalog := &infrastructurev1beta2.ContaboCatalog{
+		ObjectMeta: metav1.ObjectMeta{Name: infrastructurev1beta2.ContaboCatalogName},
+	}
+	if err := r.Create(ctx, catalog); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create the ContaboCatalog: %w", err)
+	}
+	return nil
+}
+
+// refreshCatalog lists the data centers and standard images of Contabo, the products come from the compiled-in
+// catalog as the Contabo API does not list them
+func (r *ContaboCatalogReconciler) refreshCatalog(ctx context.Context, catalog *infrastructurev1beta2.ContaboCatalog) error {
+	dataCenters := []infrastructurev1beta2.ContaboCatalogDataCenter{}
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrieveDataCenterListWithResponse(ctx, &models.RetrieveDataCenterListParams{
+			Page: &page,
+			Size: ptr.To(int64(inventoryPageSize)),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to list data centers: %w", err)
+		}
+		for _, dataCenter := range resp.JSON200.Data {
+			dataCenters = append(dataCenters, newCatalogDataCenter(dataCenter))
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			break
+		}
+	}
+
+	images := []infrastructurev1beta2.ContaboCatalogImage{}
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrieveImageListWithResponse(ctx, &models.RetrieveImageListParams{
+			Page:          &page,
+			Size:          ptr.To(int64(inventoryPageSize)),
+			StandardImage: ptr.To(true),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to list images: %w", err)
+		}
+		for _, image := range resp.JSON200.Data {
+			if !image.StandardImage {
+				continue
+			}
+			images = append(images, infrastructurev1beta2.ContaboCatalogImage{
+				ImageId:     image.ImageId,
+				Name:        image.Name,
+				Description: image.Description,
+				OsType:      image.OsType,
+			})
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			break
+		}
+	}
+
+	// Sorted so that unchanged catalogs do not patch the status
+	sort.SliceStable(dataCenters, func(i, j int) bool { return dataCenters[i].Slug < dataCenters[j].Slug })
+	sort.SliceStable(images, func(i, j int) bool { return images[i].Name < images[j].Name })
+	catalog.Status.DataCenters = dataCenters
+	catalog.Status.Regions = catalogRegions(dataCenters)
+	catalog.Status.Images = images
+	catalog.Status.Products = catalogProducts()
+	return nil
+}
+
+// newCatalogDataCenter returns the catalog entry of a data center
+func newCatalogDataCenter(dataCenter models.DataCenterResponse) infrastructurev1beta2.ContaboCatalogDataCenter {
+	item := infrastructurev1beta2.ContaboCatalogDataCenter{
+		Slug:       dataCenter.Slug,
+		Name:       dataCenter.Name,
+		Region:     dataCenter.RegionSlug,
+		RegionName: dataCenter.RegionName,
+	}
+	for _, capability := range dataCenter.Capabilities {
+		item.Capabilities = append(item.Capabilities, string(capability))
+	}
+	return item
+}
+
+// catalogRegions returns the regions instances can be ordered in which have a data center, in the order of
+// ContaboRegions, or all of them when no data center is known
+func catalogRegions(dataCenters []infrastructurev1beta2.ContaboCatalogDataCenter) []infrastructurev1beta2.ContaboRegion {
+	if len(dataCenters) == 0 {
+		return infrastructurev1beta2.ContaboRegions()
+	}
+	regions := []infrastructurev1beta2.ContaboRegion{}
+	for _, region := range infrastructurev1beta2.ContaboRegions() {
+		if slices.ContainsFunc(dataCenters, func(dataCenter infrastructurev1beta2.ContaboCatalogDataCenter) bool {
+			return dataCenter.Region == string(region)
+		}) {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// catalogProducts returns the products of the current catalog with their specifications
+func catalogProducts() []infrastructurev1beta2.ContaboCatalogProduct {
+	products := []infrastructurev1beta2.ContaboCatalogProduct{}
+	for _, productId := range infrastructurev1beta2.ContaboProducts() {
+		products = append(products, infrastructurev1beta2.ContaboCatalogProduct{
+			ProductId:  productId,
+			PriceClass: infrastructurev1beta2.ContaboProductPriceClass(productId),
+			CpuCores:   infrastructurev1beta2.ContaboProductCpuCores(productId),
+			RamGb:      infrastructurev1beta2.ContaboProductRamGb(productId),
+			DiskGb:     infrastructurev1beta2.ContaboProductDiskGb(productId),
+		})
+	}
+	return products
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ContaboCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The catalog is created by the elected manager, it is then refreshed on a timer
+	if err := mgr.Add(manager.RunnableFunc(r.ensureCatalog)); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1beta2.ContaboCatalog{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("contabocatalog").
+		Complete(r)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+var _ = Describe("ContaboCatalog", func() {
+	Context("When refreshing the catalog", func() {
+		It("should list the data centers, standard images and products", func() {
+			backend := fake.NewBackend()
+			backend.AddDataCenter(models.DataCenterResponse{Slug: "US-east-1", Name: "New York", RegionSlug: "US-east", RegionName: "United States (East)"})
+			backend.AddDataCenter(models.DataCenterResponse{Slug: "EU1", Name: "Nuremberg 1", RegionSlug: "EU", RegionName: "European Union",
+				Capabilities: []models.DataCenterResponseCapabilities{"VPS", "ObjectStorage"}})
+			backend.AddImage(models.ImageResponse{ImageId: DefaultUbuntuImageID, Name: "ubuntu-24.04", OsType: "Linux", StandardImage: true})
+			backend.AddImage(models.ImageResponse{ImageId: "custom", Name: "[capc] snapshot", OsType: "Linux"})
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			reconciler := &ContaboCatalogReconciler{ContaboClient: contaboClient}
+			catalog := &infrastructurev1beta2.ContaboCatalog{}
+			Expect(reconciler.refreshCatalog(context.Background(), catalog)).To(Succeed())
+
+			Expect(catalog.Status.DataCenters).To(Equal([]infrastructurev1beta2.ContaboCatalogDataCenter{
+				{Slug: "EU1", Name: "Nuremberg 1", Region: "EU", RegionName: "European Union", Capabilities: []string{"VPS", "ObjectStorage"}},
+				{Slug: "US-east-1", Name: "New York", Region: "US-east", RegionName: "United States (East)"},
+			}))
+			Expect(catalog.Status.Regions).To(Equal([]infrastructurev1beta2.ContaboRegion{
+				infrastructurev1beta2.ContaboRegionEU,
+				infrastructurev1beta2.ContaboRegionUSEast,
+			}))
+			Expect(catalog.Status.Images).To(Equal([]infrastructurev1beta2.ContaboCatalogImage{
+				{ImageId: DefaultUbuntuImageID, Name: "ubuntu-24.04", OsType: "Linux"},
+			}))
+			Expect(catalog.Status.Products).To(HaveLen(len(infrastructurev1beta2.ContaboProducts())))
+			Expect(catalog.Status.Products).To(ContainElement(infrastructurev1beta2.ContaboCatalogProduct{
+				ProductId:  infrastructurev1beta2.ContaboProductCloudVPS10NVMe,
+				PriceClass: "Cloud VPS 10",
+				CpuCores:   4,
+				RamGb:      8,
+				DiskGb:     75,
+			}))
+		})
+
+		It("should list every region when no data center is known", func() {
+			Expect(catalogRegions(nil)).To(Equal(infrastructurev1beta2.ContaboRegions()))
+		})
+
+		It("should give every product of the catalog its specifications", func() {
+			for _, product := range catalogProducts() {
+				Expect(product.PriceClass).NotTo(BeEmpty(), string(product.ProductId))
+				Expect(product.CpuCores).To(BeNumerically(">", 0), string(product.ProductId))
+				Expect(product.RamGb).To(BeNumerically(">", 0), string(product.ProductId))
+				Expect(product.DiskGb).To(BeNumerically(">", 0), string(product.ProductId))
+			}
+		})
+	})
+})
@@ -18,17 +18,22 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -40,7 +45,11 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/deprecation"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/version"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
 	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
 	"github.com/google/uuid"
 )
 
@@ -50,13 +59,23 @@ type ContaboClusterReconciler struct {
 	Scheme        *runtime.Scheme
 	Recorder      record.EventRecorder
 	ContaboClient *contaboclient.ClientWithResponses
-	patchHelper   *patch.Helper
+	// Settings holds the runtime tunables from ContaboProviderSettings
+	Settings *ProviderSettings
+	// ReadOnly only observes the cluster infrastructure, set with --read-only to freeze the provider during incidents
+	ReadOnly bool
+	// Credentials authorizes the Contabo API requests of the clusters referencing their own credentials
+	Credentials *ClusterCredentials
+	// APICalls records the Contabo API requests of each reconciliation and reports the hot loops
+	APICalls    *APICallMetrics
+	patchHelper *patch.Helper
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboclusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;update;delete;get;list;watch
+// +kubebuilder:rbac:groups=external-secrets.io,resources=externalsecrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;create;update;patch;delete
 
@@ -70,9 +89,19 @@ func (r *ContaboClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// Fetch the ContaboCluster instance
 	contaboCluster := &infrastructurev1beta2.ContaboCluster{}
 	if err := r.Get(ctx, req.NamespacedName, contaboCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.APICalls.forget("contabocluster", req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Count the Contabo API requests of the reconciliation to report the hot loops
+	ctx = contextutil.WithAPICallCounter(ctx)
+	defer r.APICalls.Record(ctx, "contabocluster", contaboCluster, r.Recorder)
+
+	// Record the request ID of the Contabo API requests changing the cluster resources for the last operation
+	ctx = contextutil.WithChangeRecorder(ctx)
+
 	// Fetch the Cluster
 	cluster, err := util.GetOwnerCluster(ctx, r.Client, contaboCluster.ObjectMeta)
 	if err != nil {
@@ -90,18 +119,114 @@ func (r *ContaboClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	if annotations.IsPaused(cluster, contaboCluster) {
 		log.Info("ContaboCluster or linked Cluster is marked as paused. Won't reconcile")
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
 	}
 
+	// Queue the Contabo API requests with the other requests of the cluster
+	ctx = contextutil.WithCluster(ctx, client.ObjectKeyFromObject(cluster).String())
+
 	// Initialize the patch helper
 	r.patchHelper, err = patch.NewHelper(contaboCluster, r.Client)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Record the version of the controller reconciling the resource
+	version.Stamp(contaboCluster)
+
+	// Rename the finalizer set by the former versions
+	migrateFinalizer(contaboCluster, infrastructurev1beta2.ClusterFinalizer)
+
+	// Annotate the resource while it still uses deprecated fields, e.g. created while the webhook was not running
+	if err := deprecation.Mark(deprecation.Fields, "ContaboCluster", contaboCluster); err != nil {
+		log.Error(err, "Failed to check the deprecated fields")
+	}
+
+	// Manage the cluster in the Contabo account of its credentials, nothing is reconciled without them
+	ctx, err = r.Credentials.IntoContext(ctx, contaboCluster)
+	if err != nil && !contaboCluster.DeletionTimestamp.IsZero() && r.Credentials.secretMissing(ctx, contaboCluster) {
+		// The Contabo resources cannot be deleted without the credentials, they are left in the account instead of
+		// holding the deletion forever
+		log.Error(err, "Credentials Secret of the deleted cluster is missing, leaving the Contabo resources in the account")
+		r.Recorder.Eventf(contaboCluster, corev1.EventTypeWarning, infrastructurev1beta2.CredentialsUnavailableReason,
+			"Credentials Secret %s is missing, the Contabo resources of the cluster are left in the account", contaboCluster.Spec.CredentialsRef.Name)
+		r.Credentials.forget(contaboCluster)
+		removeFinalizer(contaboCluster, infrastructurev1beta2.ClusterFinalizer)
+		if patchErr := r.patchHelper.Patch(ctx, contaboCluster); patchErr != nil && !apierrors.IsNotFound(patchErr) {
+			log.Error(patchErr, "Failed to patch ContaboCluster", "cluster", contaboCluster.Name)
+			return ctrl.Result{}, patchErr
+		}
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		log.Error(err, "Failed to load the Contabo credentials of the cluster")
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.CredentialsUnavailableReason,
+			Message: err.Error(),
+		})
+		conditions.Set(contaboCluster, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterCredentialsValidCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.CredentialsUnavailableReason,
+			Message: err.Error(),
+		})
+		r.Recorder.Event(contaboCluster, corev1.EventTypeWarning, infrastructurev1beta2.CredentialsUnavailableReason, err.Error())
+		setClusterLastOperation(ctx, contaboCluster, nil)
+		if patchErr := r.patchHelper.Patch(ctx, contaboCluster); patchErr != nil && !apierrors.IsNotFound(patchErr) {
+			log.Error(patchErr, "Failed to patch ContaboCluster", "cluster", contaboCluster.Name)
+			return ctrl.Result{}, patchErr
+		}
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	// Only report the status of the infrastructure managed by an external controller, its resources are left to it
+	if annotations.IsExternallyManaged(contaboCluster) {
+		var result ctrl.Result
+		if contaboCluster.DeletionTimestamp.IsZero() {
+			result, err = r.reconcileExternallyManaged(ctx, contaboCluster)
+		} else {
+			r.Credentials.forget(contaboCluster)
+			removeFinalizer(contaboCluster, infrastructurev1beta2.ClusterFinalizer)
+		}
+		if patchErr := r.patchHelper.Patch(ctx, contaboCluster); patchErr != nil && !apierrors.IsNotFound(patchErr) {
+			log.Error(patchErr, "Failed to patch ContaboCluster", "cluster", contaboCluster.Name)
+			return ctrl.Result{}, patchErr
+		}
+		return result, err
+	}
+
+	// Only observe the infrastructure while the provider is frozen, the changes resume once the read-only mode ends
+	if r.ReadOnly {
+		result := r.reconcileReadOnly(ctx, contaboCluster)
+		if patchErr := r.patchHelper.Patch(ctx, contaboCluster); patchErr != nil && !apierrors.IsNotFound(patchErr) {
+			if apierrors.IsConflict(patchErr) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			log.Error(patchErr, "Failed to patch ContaboCluster", "cluster", contaboCluster.Name)
+			return ctrl.Result{}, patchErr
+		}
+		return result, nil
+	}
+	setReadOnlyCondition(&contaboCluster.Status.Conditions, contaboCluster.Generation, false, "")
+
+	// Publish what the deletion destroys before destroying anything, and wait for its confirmation when required
+	if !contaboCluster.DeletionTimestamp.IsZero() || !cluster.DeletionTimestamp.IsZero() {
+		if result, waiting := r.reconcileDeletionPreview(ctx, contaboCluster); waiting {
+			setClusterLastOperation(ctx, contaboCluster, nil)
+			if patchErr := r.patchHelper.Patch(ctx, contaboCluster); patchErr != nil && !apierrors.IsNotFound(patchErr) {
+				log.Error(patchErr, "Failed to patch ContaboCluster", "cluster", contaboCluster.Name)
+				return ctrl.Result{}, patchErr
+			}
+			return result, nil
+		}
+	}
+
 	// Handle deleted clusters
 	if !contaboCluster.DeletionTimestamp.IsZero() {
 		result := r.reconcileDelete(ctx, contaboCluster)
+		setClusterLastOperation(ctx, contaboCluster, nil)
 		// Patch to update status and remove finalizer
 		// Note: This may fail if finalizer was already removed, which is fine
 		_ = r.patchHelper.Patch(ctx, contaboCluster)
@@ -111,6 +236,10 @@ func (r *ContaboClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// Handle non-deleted clusters
 	result, err := r.reconcileApply(ctx, contaboCluster)
 
+	// Report whether the Contabo API accepted the credentials of the cluster
+	setCredentialsValidCondition(contaboCluster, err)
+	setClusterLastOperation(ctx, contaboCluster, err)
+
 	// Patch at the end
 	if patchErr := r.patchHelper.Patch(ctx, contaboCluster); patchErr != nil {
 		if apierrors.IsConflict(patchErr) {
@@ -131,6 +260,15 @@ func (r *ContaboClusterReconciler) reconcileApply(ctx context.Context, contaboCl
 	// Ensure cluster has a unique UUID for global identification
 	r.ensureClusterUUID(ctx, contaboCluster)
 
+	// Report the failure domains of the placement so Cluster API spreads the machines across them
+	contaboCluster.Status.FailureDomains = clusterFailureDomains(contaboCluster)
+
+	// Record the status refresh of the cluster machines requested with the refresh annotation
+	r.reconcileRefresh(ctx, contaboCluster)
+
+	// Aggregate the conditions of the cluster machines
+	r.reconcileMachines(ctx, contaboCluster)
+
 	// Check if private network was created
 	if result, err := r.reconcilePrivateNetwork(ctx, contaboCluster); err != nil || result.RequeueAfter != 0 {
 		return result, err
@@ -150,7 +288,8 @@ func (r *ContaboClusterReconciler) reconcileApply(ctx context.Context, contaboCl
 		return result, err
 	}
 
-	return ctrl.Result{}, nil
+	// Upload the etcd snapshots of the control plane to object storage when enabled
+	return r.reconcileEtcdBackup(ctx, contaboCluster), nil
 }
 
 // markClusterReady sets the cluster infrastructure as ready after private network and SSH keys are created
@@ -184,6 +323,12 @@ func (r *ContaboClusterReconciler) markClusterReadyAndProvisioned(ctx context.Co
 func (r *ContaboClusterReconciler) ensureClusterUUID(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) string {
 	log := logf.FromContext(ctx)
 
+	// Keep the UUID of the clusters created by older versions, their resources are named after it
+	if contaboCluster.Spec.ClusterUUID == "" && GetClusterUUID(contaboCluster) != "" {
+		contaboCluster.Spec.ClusterUUID = GetClusterUUID(contaboCluster)
+		log.Info("Adopted legacy cluster UUID label", "clusterUUID", contaboCluster.Spec.ClusterUUID)
+	}
+
 	if contaboCluster.Spec.ClusterUUID == "" {
 		clusterUUID := uuid.New().String()
 		contaboCluster.Spec.ClusterUUID = clusterUUID
@@ -227,6 +372,60 @@ func (r *ContaboClusterReconciler) reconcileDelete(ctx context.Context, contaboC
 	})
 	log.Info("Cluster marked for deletion, proceeding with resource cleanup")
 
+	// Retain the private network while other clusters still reference it
+	if contaboCluster.Status.PrivateNetwork != nil {
+		references, deleting, err := r.getPrivateNetworkReferences(ctx, contaboCluster)
+		if err != nil {
+			log.Error(err, "Failed to compute private network references, requeuing deletion")
+			return ctrl.Result{RequeueAfter: 5 * time.Second}
+		}
+		contaboCluster.Status.PrivateNetworkSharedWith = references
+		if deleting {
+			// The private network is deleted by the last of the clusters deleted together, wait for the machines of
+			// the other deleting clusters instead of retaining it for them
+			meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.ClusterPrivateNetworkRetainedReason,
+				Message: fmt.Sprintf("Private network still referenced by %s", strings.Join(references, ", ")),
+			})
+			log.Info("Private network still referenced by deleting clusters, requeuing deletion", "privateNetworkId", contaboCluster.Status.PrivateNetwork.PrivateNetworkId, "references", references)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}
+		}
+		if len(references) > 0 {
+			meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.ClusterPrivateNetworkRetainedReason,
+				Message: fmt.Sprintf("Private network still referenced by %s", strings.Join(references, ", ")),
+			})
+			log.Info("Private network still referenced by other clusters, skipping deletion", "privateNetworkId", contaboCluster.Status.PrivateNetwork.PrivateNetworkId, "references", references)
+			contaboCluster.Status.PrivateNetwork = nil
+		}
+	}
+
+	// Release the control plane endpoint VIP, it belongs to the account and is not deleted
+	if vip := contaboCluster.Status.ControlPlaneEndpointVIP; vip != nil {
+		if err := r.unassignControlPlaneEndpointVIP(ctx, vip); err != nil {
+			log.Error(err, "Failed to release control plane endpoint VIP, requeuing deletion", "ip", vip.IP)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}
+		}
+		log.Info("Released control plane endpoint VIP", "ip", vip.IP)
+		contaboCluster.Status.ControlPlaneEndpointVIP = nil
+	}
+
+	// Retain the private network adopted by the cluster, it was not created by the provider
+	if contaboCluster.Status.PrivateNetwork != nil && !ptr.Deref(contaboCluster.Spec.PrivateNetwork.CreateIfNotExists, true) {
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ClusterPrivateNetworkRetainedReason,
+			Message: "Private network adopted with createIfNotExists false",
+		})
+		log.Info("Private network adopted by the cluster, skipping deletion", "privateNetworkId", contaboCluster.Status.PrivateNetwork.PrivateNetworkId)
+		contaboCluster.Status.PrivateNetwork = nil
+	}
+
 	// Delete network infrastructure
 	if contaboCluster.Status.PrivateNetwork != nil {
 		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
@@ -237,9 +436,25 @@ func (r *ContaboClusterReconciler) reconcileDelete(ctx context.Context, contaboC
 		log.Info("Deleting private network", "privateNetworkId", contaboCluster.Status.PrivateNetwork.PrivateNetworkId)
 		// Check if private network exists in Contabo API
 		resp, err := r.ContaboClient.RetrievePrivateNetworkWithResponse(ctx, contaboCluster.Status.PrivateNetwork.PrivateNetworkId, nil)
-		if err != nil || resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		if err := contabo.CheckResponse(resp, err); errors.Is(err, contabo.ErrNotFound) {
 			// If the private network is not found, we can assume it has already been deleted
 			log.Info("Private network not found in Contabo API, assuming already deleted", "privateNetworkId", contaboCluster.Status.PrivateNetwork.PrivateNetworkId)
+		} else if err != nil {
+			log.Error(err, "Failed to retrieve private network, requeuing deletion", "privateNetworkId", contaboCluster.Status.PrivateNetwork.PrivateNetworkId)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}
+		} else if ignored, err := r.ignoredPrivateNetworkInstances(ctx, contaboCluster, resp.JSON200.Data[0].Instances); err != nil {
+			log.Error(err, "Failed to list the instances of the partially adopted cluster, requeuing deletion")
+			return ctrl.Result{RequeueAfter: 5 * time.Second}
+		} else if len(ignored) > 0 {
+			// The instances not managed by the provider are strictly left untouched, with their private network
+			meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.ClusterPrivateNetworkRetainedReason,
+				Message: fmt.Sprintf("Private network still holds instances %s without the provider tag %q", strings.Join(ignored, ", "), providerTag(contaboCluster)),
+			})
+			log.Info("Private network still holds instances not managed by the provider, skipping deletion", "privateNetworkId", contaboCluster.Status.PrivateNetwork.PrivateNetworkId, "instances", ignored)
+			contaboCluster.Status.PrivateNetwork = nil
 		} else {
 			privateNetwork := resp.JSON200.Data[0]
 
@@ -259,7 +474,7 @@ func (r *ContaboClusterReconciler) reconcileDelete(ctx context.Context, contaboC
 			}
 
 			// Delete private network
-			if _, err := r.ContaboClient.DeletePrivateNetwork(ctx, privateNetwork.PrivateNetworkId, nil); err != nil {
+			if err := contabo.CheckResponse(r.ContaboClient.DeletePrivateNetworkWithResponse(ctx, privateNetwork.PrivateNetworkId, nil)); err != nil {
 				log.Error(err, "Failed to delete private network, requeuing", "privateNetworkId", privateNetwork.PrivateNetworkId)
 			}
 
@@ -282,16 +497,25 @@ func (r *ContaboClusterReconciler) reconcileDelete(ctx context.Context, contaboC
 
 		// Check if SSH key exists in Contabo API
 		resp, err := r.ContaboClient.RetrieveSecretWithResponse(ctx, contaboCluster.Status.SshKey.SecretId, nil)
-		if err != nil || resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		if err := contabo.CheckResponse(resp, err); errors.Is(err, contabo.ErrNotFound) {
 			// If the SSH key is not found, we can assume it has already been deleted
 			log.Info("SSH key not found in Contabo API, assuming already deleted", "sshKeyID", contaboCluster.Status.SshKey.SecretId)
+		} else if err != nil {
+			log.Error(err, "Failed to retrieve SSH key, requeuing deletion", "sshKeyID", contaboCluster.Status.SshKey.SecretId)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}
 		} else {
 			// Delete SSH key
-			if _, err := r.ContaboClient.DeleteSecret(ctx, contaboCluster.Status.SshKey.SecretId, nil); err != nil {
+			if err := contabo.CheckResponse(r.ContaboClient.DeleteSecretWithResponse(ctx, contaboCluster.Status.SshKey.SecretId, nil)); err != nil {
 				log.Error(err, "Failed to delete SSH key, requeuing", "sshKeyID", contaboCluster.Status.SshKey.SecretId)
 			}
 		}
 
+		// Delete the SSH keys registered for the SSH key secrets of the machines
+		if err := r.deleteMachineSSHKeys(ctx, contaboCluster); err != nil {
+			log.Error(err, "Failed to delete machine SSH keys, requeuing deletion")
+			return ctrl.Result{RequeueAfter: 5 * time.Second}
+		}
+
 		// Update status to remove SSH key
 		sshKeyContaboName := contaboCluster.Status.SshKey.Name
 		contaboCluster.Status.SshKey = nil
@@ -327,7 +551,8 @@ func (r *ContaboClusterReconciler) reconcileDelete(ctx context.Context, contaboC
 
 	// 3. If there are no more contabomachines, remove the finalizer
 	log.Info("No more ContaboMachines in the cluster, removing finalizer")
-	controllerutil.RemoveFinalizer(contaboCluster, infrastructurev1beta2.ClusterFinalizer)
+	r.Credentials.forget(contaboCluster)
+	removeFinalizer(contaboCluster, infrastructurev1beta2.ClusterFinalizer)
 
 	return ctrl.Result{}
 }
@@ -343,10 +568,40 @@ func (r *ContaboClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(util.ClusterToInfrastructureMapFunc(context.TODO(), infrastructurev1beta2.GroupVersion.WithKind("ContaboCluster"), mgr.GetClient(), &infrastructurev1beta2.ContaboCluster{})),
 			builder.WithPredicates(predicates.ClusterUnpaused(mgr.GetScheme(), ctrl.LoggerFrom(context.TODO()))),
 		).
+		// Keep the endpoint slices and kubeconfig variants in sync with the control plane machines, and the
+		// aggregated conditions with the conditions of all the machines
+		Watches(
+			&infrastructurev1beta2.ContaboMachine{},
+			handler.EnqueueRequestsFromMapFunc(r.contaboMachineToContaboCluster),
+			builder.WithPredicates(machineConditionsChanged()),
+		).
+		// Keep the kubeconfig variants in sync with the Cluster API kubeconfig
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.kubeconfigSecretToContaboCluster),
+		).
 		Named("contabocluster").
 		Complete(r)
 }
 
+// contaboMachineToContaboCluster maps ContaboMachines to the ContaboCluster of their cluster
+func (r *ContaboClusterReconciler) contaboMachineToContaboCluster(ctx context.Context, obj client.Object) []ctrl.Request {
+	clusterName, ok := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if !ok {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: obj.GetNamespace(), Name: clusterName}}}
+}
+
+// kubeconfigSecretToContaboCluster maps the Cluster API kubeconfig Secret to the ContaboCluster of its cluster
+func (r *ContaboClusterReconciler) kubeconfigSecretToContaboCluster(ctx context.Context, obj client.Object) []ctrl.Request {
+	clusterName, ok := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if !ok || obj.GetName() != clusterName+"-kubeconfig" {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: obj.GetNamespace(), Name: clusterName}}}
+}
+
 // handleError centralizes error handling with status condition, logging, event recording, and patching
 func (r *ContaboClusterReconciler) handleError(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, err error, conditionType string, reason string, message string) error {
 	log := logf.FromContext(ctx)
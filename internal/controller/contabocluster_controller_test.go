@@ -18,17 +18,39 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 
 	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/auth"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
 )
 
 var _ = Describe("ContaboCluster Controller", func() {
@@ -90,4 +112,1212 @@ var _ = Describe("ContaboCluster Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When generating kubeconfig variants", func() {
+		const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://test-apiserver.default.svc:6443
+    certificate-authority-data: dGVzdA==
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: admin
+current-context: test
+users:
+- name: admin
+  user:
+    token: secret
+`
+
+		controlPlaneMachine := func(name string, ready bool, publicIp string, privateIp string) infrastructurev1beta2.ContaboMachine {
+			machine := infrastructurev1beta2.ContaboMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels: map[string]string{
+						clusterv1.ClusterNameLabel:         "test",
+						clusterv1.MachineControlPlaneLabel: "",
+					},
+				},
+			}
+			machine.Status.Ready = ready
+			machine.Status.Addresses = []clusterv1.MachineAddress{
+				{Type: clusterv1.MachineInternalIP, Address: privateIp},
+				{Type: clusterv1.MachineExternalIP, Address: "2001:db8::1"},
+				{Type: clusterv1.MachineExternalIP, Address: publicIp},
+			}
+			return machine
+		}
+
+		It("should prefer ready control plane machines", func() {
+			publicIp, privateIp := kubeconfigEndpoints([]infrastructurev1beta2.ContaboMachine{
+				controlPlaneMachine("a", false, "192.0.2.1", "10.0.0.1"),
+				controlPlaneMachine("b", true, "192.0.2.2", "10.0.0.2"),
+			})
+			Expect(publicIp).To(Equal("192.0.2.2"))
+			Expect(privateIp).To(Equal("10.0.0.2"))
+
+			publicIp, privateIp = kubeconfigEndpoints([]infrastructurev1beta2.ContaboMachine{controlPlaneMachine("a", true, "", "10.0.0.1")})
+			Expect(publicIp).To(BeEmpty())
+			Expect(privateIp).To(BeEmpty())
+		})
+
+		It("should point the kubeconfig to the server and keep verifying the original host", func() {
+			out, err := renderKubeconfigVariant([]byte(kubeconfig), "https://10.0.0.2:6443")
+			Expect(err).NotTo(HaveOccurred())
+			config, err := clientcmd.Load(out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Clusters["test"].Server).To(Equal("https://10.0.0.2:6443"))
+			Expect(config.Clusters["test"].TLSServerName).To(Equal("test-apiserver.default.svc"))
+			Expect(config.AuthInfos["admin"].Token).To(Equal("secret"))
+		})
+
+		It("should keep both variants in sync with the control plane machines", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "test-apiserver.default.svc", Port: 6443},
+				},
+			}
+			kubeconfigSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-kubeconfig", Namespace: "default"},
+				Data:       map[string][]byte{"value": []byte(kubeconfig)},
+			}
+			k8sClient := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(kubeconfigSecret).Build()
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+			machines := &infrastructurev1beta2.ContaboMachineList{Items: []infrastructurev1beta2.ContaboMachine{
+				controlPlaneMachine("a", true, "192.0.2.1", "10.0.0.1"),
+			}}
+			Expect(reconciler.reconcileKubeconfigs(ctx, contaboCluster, machines)).To(Succeed())
+			Expect(contaboCluster.Status.Kubeconfig.PublicServer).To(Equal("https://192.0.2.1:6443"))
+			Expect(contaboCluster.Status.Kubeconfig.PrivateServer).To(Equal("https://10.0.0.1:6443"))
+
+			machines.Items = []infrastructurev1beta2.ContaboMachine{controlPlaneMachine("b", true, "192.0.2.2", "10.0.0.2")}
+			Expect(reconciler.reconcileKubeconfigs(ctx, contaboCluster, machines)).To(Succeed())
+
+			for name, server := range map[string]string{
+				"test-kubeconfig-public":  "https://192.0.2.2:6443",
+				"test-kubeconfig-private": "https://10.0.0.2:6443",
+			} {
+				secret := &corev1.Secret{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, secret)).To(Succeed())
+				config, err := clientcmd.Load(secret.Data["value"])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Clusters["test"].Server).To(Equal(server))
+			}
+		})
+	})
+
+	Context("When publishing a VIP as the control plane endpoint", func() {
+		It("should assign the VIP to a control plane instance and move it when its holder is deleted", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			backend.AddVip("192.0.2.10", "EU")
+			backend.AddVip("192.0.2.11", "EU")
+			backend.AddVip("198.51.100.10", "US-east")
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			other := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other"},
+				Status: infrastructurev1beta2.ContaboClusterStatus{
+					ControlPlaneEndpointVIP: &infrastructurev1beta2.ContaboControlPlaneEndpointVIPStatus{IP: "192.0.2.10"},
+				},
+			}
+			recorder := record.NewFakeRecorder(10)
+			k8sClient := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(other).Build()
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder, ContaboClient: contaboClient}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "vip", Namespace: "default", UID: "vip"},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Port: 6443},
+					PrivateNetwork:       infrastructurev1beta2.ContaboPrivateNetworkSpec{Region: "EU"},
+				},
+			}
+
+			controlPlaneMachine := func(name string, ready bool) infrastructurev1beta2.ContaboMachine {
+				instanceId := backend.AddInstance(models.InstanceResponse{Status: models.InstanceStatusRunning})
+				machine := infrastructurev1beta2.ContaboMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{clusterv1.ClusterNameLabel: "vip", clusterv1.MachineControlPlaneLabel: ""},
+					},
+				}
+				machine.Status.Ready = ready
+				machine.Status.Instance = &infrastructurev1beta2.ContaboInstanceStatus{InstanceId: instanceId}
+				return machine
+			}
+
+			By("Publishing a VIP not published by another cluster before the control plane machines exist")
+			machines := &infrastructurev1beta2.ContaboMachineList{}
+			result, err := reconciler.reconcileControlPlaneEndpointVIP(ctx, contaboCluster, machines)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(contaboCluster.Spec.ControlPlaneEndpoint.Host).To(Equal("192.0.2.11"))
+			Expect(contaboCluster.Status.ControlPlaneEndpointVIP.Port).To(Equal(int32(6443)))
+			Expect(contaboCluster.Status.ControlPlaneEndpointVIP.InstanceId).To(BeNil())
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ControlPlaneEndpointReadyCondition)
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.ControlPlaneEndpointCreatingReason))
+
+			By("Assigning the VIP to the ready control plane instance")
+			machines.Items = []infrastructurev1beta2.ContaboMachine{controlPlaneMachine("cp-0", false), controlPlaneMachine("cp-1", true)}
+			_, err = reconciler.reconcileControlPlaneEndpointVIP(ctx, contaboCluster, machines)
+			Expect(err).NotTo(HaveOccurred())
+			holder := machines.Items[1].Status.Instance.InstanceId
+			Expect(backend.Vip("192.0.2.11").ResourceId).To(Equal(strconv.FormatInt(holder, 10)))
+			Expect(contaboCluster.Status.ControlPlaneEndpointVIP.MachineName).To(Equal("cp-1"))
+			Expect(meta.IsStatusConditionTrue(contaboCluster.Status.Conditions, infrastructurev1beta2.ControlPlaneEndpointReadyCondition)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring(infrastructurev1beta2.ControlPlaneEndpointVIPAssignedReason)))
+
+			By("Moving the VIP when its holder is deleted")
+			machines.Items[1].DeletionTimestamp = ptr.To(metav1.Now())
+			_, err = reconciler.reconcileControlPlaneEndpointVIP(ctx, contaboCluster, machines)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backend.Vip("192.0.2.11").ResourceId).To(Equal(strconv.FormatInt(machines.Items[0].Status.Instance.InstanceId, 10)))
+			Expect(contaboCluster.Status.ControlPlaneEndpointVIP.MachineName).To(Equal("cp-0"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Moved control plane endpoint VIP 192.0.2.11")))
+
+			By("Answering on the VIP on the control plane instances only")
+			cloudConfig, err := controlPlaneEndpointVIPCloudConfig(&machines.Items[0], contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cloudConfig)).To(ContainSubstring("ip -4 addr replace 192.0.2.11/32 dev lo"))
+			worker := &infrastructurev1beta2.ContaboMachine{}
+			Expect(controlPlaneEndpointVIPCloudConfig(worker, contaboCluster)).To(BeNil())
+
+			By("Releasing the VIP with the cluster")
+			reconciler.reconcileDelete(ctx, contaboCluster)
+			Expect(contaboCluster.Status.ControlPlaneEndpointVIP).To(BeNil())
+			Expect(backend.Vip("192.0.2.11").ResourceId).To(BeEmpty())
+		})
+
+		It("should wait for a floating VIP in the region of the cluster", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			backend.AddVip("198.51.100.10", "US-east")
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+			reconciler := &ContaboClusterReconciler{Client: crfake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme, Recorder: record.NewFakeRecorder(10), ContaboClient: contaboClient}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "vip", Namespace: "default"},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{Region: "EU"},
+				},
+			}
+
+			result, err := reconciler.reconcileControlPlaneEndpointVIP(ctx, contaboCluster, &infrastructurev1beta2.ContaboMachineList{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(contaboCluster.Spec.ControlPlaneEndpoint.Host).To(BeEmpty())
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ControlPlaneEndpointReadyCondition)
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.ControlPlaneEndpointVIPNotAvailableReason))
+		})
+	})
+
+	Context("When cleaning up stale private network assignments", func() {
+		It("should only unassign the released instances held by no machine", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+			privateNetworkId := backend.AddPrivateNetwork("[capc] test", "EU")
+			held := backend.AddInstance(models.InstanceResponse{DisplayName: "[capc] test worker-0"})
+			released := backend.AddInstance(models.InstanceResponse{})
+			failed := backend.AddInstance(models.InstanceResponse{DisplayName: "[capc] 1 failed to bootstrap"})
+			for _, instanceId := range []int64{held, released, failed} {
+				_, err := contaboClient.AssignInstancePrivateNetworkWithResponse(ctx, privateNetworkId, instanceId, nil)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			// Contabo does not apply the first unassignment
+			backend.SetFaults(fake.Faults{IgnoredUnassignments: 1})
+
+			contaboMachine := &infrastructurev1beta2.ContaboMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "default"},
+				Status: infrastructurev1beta2.ContaboMachineStatus{
+					Instance: &infrastructurev1beta2.ContaboInstanceStatus{InstanceId: held},
+				},
+			}
+			k8sClient := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(contaboMachine).Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder, ContaboClient: contaboClient}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+
+			privateNetwork := backend.PrivateNetwork(privateNetworkId)
+			remaining := reconciler.reconcilePrivateNetworkAssignments(ctx, contaboCluster, privateNetworkId, privateNetwork.Name, privateNetwork.Instances)
+
+			instanceIds := func(instances []models.Instances) []int64 {
+				ids := []int64{}
+				for _, instance := range instances {
+					ids = append(ids, instance.InstanceId)
+				}
+				return ids
+			}
+			Expect(instanceIds(remaining)).To(ConsistOf(held, failed))
+			Expect(instanceIds(backend.PrivateNetwork(privateNetworkId).Instances)).To(ConsistOf(held, failed))
+			Expect(recorder.Events).To(Receive(ContainSubstring(infrastructurev1beta2.ClusterPrivateNetworkStaleAssignmentRemovedReason)))
+
+			converted := convertPrivateNetworkInstances(remaining)
+			Expect(converted).To(HaveLen(2))
+			Expect(converted[0].PrivateIpConfig.V4).To(HaveLen(1))
+		})
+	})
+
+	Context("When the ContaboCluster is externally managed", func() {
+		It("should only report the infrastructure created by the external controller", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "default", UID: "cluster-external"}}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "external",
+					Namespace:   "default",
+					Annotations: map[string]string{clusterv1.ManagedByAnnotation: "gitops"},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: clusterv1.GroupVersion.String(),
+						Kind:       "Cluster",
+						Name:       cluster.Name,
+						UID:        cluster.UID,
+					}},
+				},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					ClusterUUID:    fixtureClusterUUID,
+					PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{Name: "gitops-network", Region: "EU"},
+				},
+			}
+			k8sClient := crfake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, contaboCluster).
+				WithStatusSubresource(&infrastructurev1beta2.ContaboCluster{}).
+				Build()
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10), ContaboClient: contaboClient}
+			key := types.NamespacedName{Name: "external", Namespace: "default"}
+
+			By("Waiting for the external controller to create the infrastructure")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(backend.Instances()).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, key, contaboCluster)).To(Succeed())
+			Expect(contaboCluster.Finalizers).To(BeEmpty())
+			Expect(contaboCluster.Status.Ready).To(BeFalse())
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterPrivateNetworkReadyCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.ClusterWaitingForExternalResourceReason))
+
+			By("Reporting the infrastructure created by the external controller")
+			privateNetworkId := backend.AddPrivateNetwork("gitops-network", "EU")
+			sshKeyId := backend.AddSecret("[capc] "+fixtureClusterUUID, models.SecretResponseTypeSsh, "ssh-ed25519 AAAA")
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(k8sClient.Get(ctx, key, contaboCluster)).To(Succeed())
+			Expect(contaboCluster.Finalizers).To(BeEmpty())
+			Expect(contaboCluster.Status.PrivateNetwork.PrivateNetworkId).To(Equal(privateNetworkId))
+			Expect(contaboCluster.Status.SshKey.SecretId).To(Equal(sshKeyId))
+			Expect(meta.IsStatusConditionTrue(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterPrivateNetworkReadyCondition)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterSshKeyReadyCondition)).To(BeTrue())
+		})
+	})
+
+	Context("When the ContaboCluster adopts its private network", func() {
+		It("should wait for the private network, check its range and retain it on deletion", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+			k8sClient := crfake.NewClientBuilder().WithScheme(scheme).Build()
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10), ContaboClient: contaboClient}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "adopting", Namespace: "default"},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					ClusterUUID: fixtureClusterUUID,
+					PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{
+						Name:              "shared-network",
+						Region:            "EU",
+						CIDR:              "10.0.1.0/22",
+						CreateIfNotExists: ptr.To(false),
+					},
+				},
+			}
+
+			By("Waiting for the private network instead of creating it")
+			result, err := reconciler.reconcilePrivateNetwork(ctx, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			list, err := contaboClient.RetrievePrivateNetworkListWithResponse(ctx, &models.RetrievePrivateNetworkListParams{})
+			Expect(contabo.CheckResponse(list, err)).To(Succeed())
+			Expect(list.JSON200.Data).To(BeEmpty())
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterPrivateNetworkReadyCondition)
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.ClusterPrivateNetworkNotFoundReason))
+
+			By("Refusing a private network with another range")
+			privateNetworkId := backend.AddPrivateNetwork("shared-network", "EU")
+			contaboCluster.Spec.PrivateNetwork.CIDR = "10.1.0.0/22"
+			result, err = reconciler.reconcilePrivateNetwork(ctx, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(contaboCluster.Status.PrivateNetwork).To(BeNil())
+			condition = meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterPrivateNetworkReadyCondition)
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.ClusterPrivateNetworkCIDRMismatchReason))
+
+			By("Adopting the private network with the expected range")
+			contaboCluster.Spec.PrivateNetwork.CIDR = "10.0.1.0/22"
+			_, err = reconciler.reconcilePrivateNetwork(ctx, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contaboCluster.Status.PrivateNetwork.PrivateNetworkId).To(Equal(privateNetworkId))
+			Expect(meta.IsStatusConditionTrue(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterPrivateNetworkReadyCondition)).To(BeTrue())
+
+			By("Retaining the adopted private network on deletion")
+			reconciler.reconcileDelete(ctx, contaboCluster)
+			Expect(contaboCluster.Status.PrivateNetwork).To(BeNil())
+			Expect(backend.PrivateNetwork(privateNetworkId)).NotTo(BeNil())
+			condition = meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterPrivateNetworkReadyCondition)
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.ClusterPrivateNetworkRetainedReason))
+		})
+	})
+
+	Context("When ContaboClusters share a private network", func() {
+		It("should only delete the private network once the other clusters and their machines are gone", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+			privateNetworkId := backend.AddPrivateNetwork("shared-network", "EU")
+			now := metav1.Now()
+			sharing := func(name string) *infrastructurev1beta2.ContaboCluster {
+				return &infrastructurev1beta2.ContaboCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name), Finalizers: []string{infrastructurev1beta2.ClusterFinalizer}},
+					Spec: infrastructurev1beta2.ContaboClusterSpec{
+						PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{Name: "shared-network", Region: "EU"},
+					},
+					Status: infrastructurev1beta2.ContaboClusterStatus{
+						PrivateNetwork: &infrastructurev1beta2.ContaboPrivateNetworkStatus{PrivateNetworkId: privateNetworkId},
+					},
+				}
+			}
+			deleted, deleting, live := sharing("deleted"), sharing("deleting"), sharing("live")
+			deleting.DeletionTimestamp = &now
+			live.Finalizers = nil
+			machine := &infrastructurev1beta2.ContaboMachine{ObjectMeta: metav1.ObjectMeta{
+				Name:      "deleting-worker",
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "deleting"},
+			}}
+			k8sClient := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(deleting, machine).Build()
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10), ContaboClient: contaboClient}
+
+			By("Waiting for the machines of the other deleting cluster")
+			result := reconciler.reconcileDelete(ctx, deleted)
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(deleted.Status.PrivateNetworkSharedWith).To(Equal([]string{"default/deleting"}))
+			Expect(deleted.Status.PrivateNetwork).NotTo(BeNil())
+			Expect(deleted.Finalizers).NotTo(BeEmpty())
+			Expect(backend.PrivateNetwork(privateNetworkId)).NotTo(BeNil())
+
+			By("Retaining the private network of a live cluster")
+			Expect(k8sClient.Create(ctx, live)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, machine)).To(Succeed())
+			retained := deleted.DeepCopy()
+			reconciler.reconcileDelete(ctx, retained)
+			Expect(retained.Status.PrivateNetworkSharedWith).To(Equal([]string{"default/live"}))
+			Expect(retained.Status.PrivateNetwork).To(BeNil())
+			Expect(backend.PrivateNetwork(privateNetworkId)).NotTo(BeNil())
+
+			By("Deleting the private network once the machines of the deleting cluster are gone")
+			Expect(k8sClient.Delete(ctx, live)).To(Succeed())
+			reconciler.reconcileDelete(ctx, deleted)
+			Expect(deleted.Status.PrivateNetworkSharedWith).To(BeEmpty())
+			Expect(deleted.Finalizers).To(BeEmpty())
+			Expect(backend.PrivateNetwork(privateNetworkId)).To(BeNil())
+		})
+	})
+
+	Context("When the ContaboCluster is partially adopted", func() {
+		It("should strictly ignore the instances without the provider tag", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+			privateNetworkId := backend.AddPrivateNetwork("legacy", "EU")
+			adopted := backend.AddInstance(models.InstanceResponse{})
+			legacy := backend.AddInstance(models.InstanceResponse{Name: "legacy-db"})
+			for _, instanceId := range []int64{adopted, legacy} {
+				_, err := contaboClient.AssignInstancePrivateNetworkWithResponse(ctx, privateNetworkId, instanceId, nil)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			backend.AddTag("capc-managed-extra")
+			backend.AssignTag(backend.AddTag("capc-managed"), adopted)
+
+			reconciler := &ContaboClusterReconciler{
+				Client:        crfake.NewClientBuilder().WithScheme(scheme).Build(),
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+				ContaboClient: contaboClient,
+			}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					PartialAdoption: &infrastructurev1beta2.ContaboPartialAdoptionSpec{ProviderTag: "capc-managed"},
+				},
+			}
+
+			managed, err := managedInstances(ctx, contaboClient, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(managed).To(Equal(map[int64]bool{adopted: true}))
+			Expect(ignoresInstance(managed, legacy)).To(BeTrue())
+			Expect(ignoresInstance(nil, legacy)).To(BeFalse())
+
+			By("Only removing the released instances carrying the provider tag from the private network")
+			privateNetwork := backend.PrivateNetwork(privateNetworkId)
+			remaining := reconciler.reconcilePrivateNetworkAssignments(ctx, contaboCluster, privateNetworkId, privateNetwork.Name, privateNetwork.Instances)
+			Expect(remaining).To(HaveLen(1))
+			Expect(remaining[0].InstanceId).To(Equal(legacy))
+
+			By("Retaining the private network of the instances without the provider tag")
+			ignored, err := reconciler.ignoredPrivateNetworkInstances(ctx, contaboCluster, backend.PrivateNetwork(privateNetworkId).Instances)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ignored).To(Equal([]string{"legacy-db"}))
+
+			By("Ignoring every instance while the provider tag does not exist")
+			contaboCluster.Spec.PartialAdoption.ProviderTag = "missing"
+			managed, err = managedInstances(ctx, contaboClient, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(managed).NotTo(BeNil())
+			Expect(ignoresInstance(managed, adopted)).To(BeTrue())
+		})
+	})
+
+	Context("When a refresh of the cluster machines is requested", func() {
+		It("should record the request once and refresh the machines which have not applied it", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					Namespace:   "default",
+					Labels:      map[string]string{clusterv1.ClusterNameLabel: "test"},
+					Annotations: map[string]string{infrastructurev1beta2.RefreshAnnotation: "2026-10-15T08:00:00Z"},
+				},
+			}
+			machine := func(name string, ready bool, refreshRequest string) *infrastructurev1beta2.ContaboMachine {
+				return &infrastructurev1beta2.ContaboMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "test"}},
+					Status:     infrastructurev1beta2.ContaboMachineStatus{Ready: ready, RefreshRequest: refreshRequest},
+				}
+			}
+			k8sClient := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				machine("provisioning", false, ""),
+				machine("ready", true, ""),
+				machine("refreshed", true, "2026-10-15T08:00:00Z"),
+			).Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder}
+
+			reconciler.reconcileRefresh(ctx, contaboCluster)
+			Expect(contaboCluster.Status.Refresh).NotTo(BeNil())
+			Expect(contaboCluster.Status.Refresh.Request).To(Equal("2026-10-15T08:00:00Z"))
+			Expect(contaboCluster.Status.Refresh.Machines).To(Equal(int32(3)))
+			Expect(recorder.Events).To(Receive(ContainSubstring(infrastructurev1beta2.ClusterRefreshRequestedReason)))
+
+			reconciler.reconcileRefresh(ctx, contaboCluster)
+			Expect(recorder.Events).NotTo(Receive())
+
+			machineReconciler := &ContaboMachineReconciler{Client: k8sClient, Scheme: scheme}
+			names := []string{}
+			for _, request := range machineReconciler.contaboClusterToContaboMachines(ctx, contaboCluster) {
+				names = append(names, request.Name)
+			}
+			Expect(names).To(ConsistOf("provisioning", "ready"))
+
+			contaboMachine := machine("ready", true, "")
+			contaboMachine.Status.AuditTrailLastUpdated = ptr.To(metav1.Now())
+			contaboMachine.Status.Host = &infrastructurev1beta2.ContaboMachineHostStatus{LastChecked: ptr.To(metav1.Now())}
+			machineReconciler.applyRefresh(ctx, contaboMachine, contaboCluster)
+			Expect(contaboMachine.Status.AuditTrailLastUpdated).To(BeNil())
+			Expect(contaboMachine.Status.Host.LastChecked).To(BeNil())
+			Expect(contaboMachine.Status.RefreshRequest).To(Equal("2026-10-15T08:00:00Z"))
+		})
+	})
+
+	Context("When aggregating the conditions of the cluster machines", func() {
+		It("should count the ready machines and the machines reporting a problem", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			}
+			machine := func(name string, conditions ...metav1.Condition) *infrastructurev1beta2.ContaboMachine {
+				return &infrastructurev1beta2.ContaboMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "test"}},
+					Status:     infrastructurev1beta2.ContaboMachineStatus{Conditions: conditions},
+				}
+			}
+			ready := metav1.Condition{Type: infrastructurev1beta2.MachineReadyCondition, Status: metav1.ConditionTrue}
+			k8sClient := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				machine("ready", ready, metav1.Condition{Type: infrastructurev1beta2.InstanceHostStableCondition, Status: metav1.ConditionTrue}),
+				machine("moved", ready, metav1.Condition{Type: infrastructurev1beta2.InstanceHostStableCondition, Status: metav1.ConditionFalse}),
+				machine("frozen", ready, metav1.Condition{Type: infrastructurev1beta2.ReadOnlyCondition, Status: metav1.ConditionTrue}),
+				machine("provisioning", metav1.Condition{Type: infrastructurev1beta2.MachineReadyCondition, Status: metav1.ConditionFalse}),
+			).Build()
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+			reconciler.reconcileMachines(ctx, contaboCluster)
+			Expect(contaboCluster.Status.Machines).NotTo(BeNil())
+			Expect(contaboCluster.Status.Machines.Total).To(Equal(int32(4)))
+			Expect(contaboCluster.Status.Machines.Ready).To(Equal(int32(3)))
+			Expect(contaboCluster.Status.Machines.Conditions).To(Equal([]infrastructurev1beta2.ContaboClusterMachineConditionSummary{
+				{Type: infrastructurev1beta2.InstanceHostStableCondition, Count: 1, Machines: []string{"moved"}},
+				{Type: infrastructurev1beta2.ReadOnlyCondition, Count: 1, Machines: []string{"frozen"}},
+			}))
+			Expect(contaboCluster.Status.Machines.Summary).To(Equal("3/4 machines Ready, 1 InstanceHostStable, 1 ReadOnly"))
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterMachinesReadyCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal(contaboCluster.Status.Machines.Summary))
+
+			Expect(summarizeMachines([]infrastructurev1beta2.ContaboMachine{*machine("ready", ready)}).Summary).To(Equal("1/1 machines Ready"))
+
+			predicate := machineConditionsChanged()
+			worker := machine("worker", ready)
+			updated := machine("worker", ready)
+			updated.Status.Addresses = []clusterv1.MachineAddress{{Type: clusterv1.MachineExternalIP, Address: "192.0.2.1"}}
+			Expect(predicate.Update(event.UpdateEvent{ObjectOld: worker, ObjectNew: updated})).To(BeFalse())
+			updated.Status.Conditions = []metav1.Condition{{Type: infrastructurev1beta2.MachineReadyCondition, Status: metav1.ConditionFalse}}
+			Expect(predicate.Update(event.UpdateEvent{ObjectOld: worker, ObjectNew: updated})).To(BeTrue())
+		})
+	})
+
+	Context("When backing up etcd to object storage", func() {
+		It("should create the bucket, hand the credentials to the workload cluster and prune the old snapshots", func() {
+			ctx := context.Background()
+			var mu sync.Mutex
+			bucketCreated := false
+			objects := map[string]time.Time{}
+			storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				Expect(req.URL.Query().Get("X-Amz-Signature")).NotTo(BeEmpty())
+				switch {
+				case req.Method == http.MethodPut && req.URL.Path == "/backups":
+					bucketCreated = true
+				case req.Method == http.MethodGet && req.URL.Path == "/backups":
+					Expect(req.URL.Query().Get("list-type")).To(Equal("2"))
+					prefix := req.URL.Query().Get("prefix")
+					_, _ = fmt.Fprint(w, "<ListBucketResult>")
+					for key, modified := range objects {
+						if strings.HasPrefix(key, prefix) {
+							_, _ = fmt.Fprintf(w, "<Contents><Key>%s</Key><LastModified>%s</LastModified></Contents>", key, modified.Format(time.RFC3339))
+						}
+					}
+					_, _ = fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+				case req.Method == http.MethodDelete:
+					delete(objects, strings.TrimPrefix(req.URL.Path, "/backups/"))
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer storage.Close()
+
+			scheme := newScheme()
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					ClusterUUID: "cluster-uuid",
+					EtcdBackup: &infrastructurev1beta2.ContaboEtcdBackupSpec{
+						Retention: 2,
+						ObjectStorage: infrastructurev1beta2.ContaboEtcdBackupObjectStorage{
+							Endpoint:              storage.URL,
+							Bucket:                "backups",
+							CredentialsSecretName: "etcd-backup-storage",
+						},
+					},
+				},
+			}
+			credentials := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "etcd-backup-storage", Namespace: "default"},
+				Data:       map[string][]byte{"accessKey": []byte("access"), "secretKey": []byte("secret")},
+			}
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &ContaboClusterReconciler{
+				Client:   crfake.NewClientBuilder().WithScheme(scheme).WithObjects(credentials).Build(),
+				Scheme:   scheme,
+				Recorder: recorder,
+			}
+
+			// The workload cluster has no kubeconfig yet
+			result := reconciler.reconcileEtcdBackup(ctx, contaboCluster)
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(bucketCreated).To(BeTrue())
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterEtcdBackupReadyCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.ClusterEtcdBackupWaitingForWorkloadClusterReason))
+
+			workloadCluster := kubefake.NewClientset()
+			objectStorage, err := newEtcdBackupObjectStorage(ctx, reconciler.Client, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applyEtcdBackupSecret(ctx, workloadCluster, etcdBackupSecretData(contaboCluster, objectStorage))).To(Succeed())
+			secret, err := workloadCluster.CoreV1().Secrets("kube-system").Get(ctx, EtcdBackupSecretName, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Data).To(HaveKeyWithValue("prefix", []byte("capc/cluster-uuid/etcd/")))
+			Expect(secret.Data).To(HaveKeyWithValue("bucket", []byte("backups")))
+			Expect(secret.Data).To(HaveKeyWithValue("secretKey", []byte("secret")))
+
+			objectStorage.secretKey = "rotated"
+			Expect(applyEtcdBackupSecret(ctx, workloadCluster, etcdBackupSecretData(contaboCluster, objectStorage))).To(Succeed())
+			secret, err = workloadCluster.CoreV1().Secrets("kube-system").Get(ctx, EtcdBackupSecretName, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Data).To(HaveKeyWithValue("secretKey", []byte("rotated")))
+
+			now := time.Now().UTC().Truncate(time.Second)
+			objects["capc/cluster-uuid/etcd/20261015T000000Z-cp-0.db"] = now.Add(-12 * time.Hour)
+			objects["capc/cluster-uuid/etcd/20261015T060000Z-cp-1.db"] = now.Add(-6 * time.Hour)
+			objects["capc/cluster-uuid/etcd/20261015T120000Z-cp-0.db"] = now
+			objects["capc/other-uuid/etcd/20261015T000000Z-cp-0.db"] = now.Add(-24 * time.Hour)
+
+			Expect(reconciler.syncEtcdSnapshots(ctx, contaboCluster, objectStorage)).To(Succeed())
+			Expect(objects).NotTo(HaveKey("capc/cluster-uuid/etcd/20261015T000000Z-cp-0.db"))
+			Expect(objects).To(HaveKey("capc/other-uuid/etcd/20261015T000000Z-cp-0.db"))
+			Expect(contaboCluster.Status.EtcdBackup.Snapshots).To(Equal(int32(2)))
+			Expect(contaboCluster.Status.EtcdBackup.LastSnapshotKey).To(Equal("capc/cluster-uuid/etcd/20261015T120000Z-cp-0.db"))
+			Expect(contaboCluster.Status.EtcdBackup.LastSnapshotTime.Time).To(BeTemporally("==", now))
+			Expect(recorder.Events).To(Receive(ContainSubstring(infrastructurev1beta2.ClusterEtcdBackupPrunedReason)))
+		})
+
+		It("should install the snapshot uploader on the kubeadm control plane machines only", func() {
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					EtcdBackup: &infrastructurev1beta2.ContaboEtcdBackupSpec{Schedule: "30 2 * * *"},
+				},
+			}
+			controlPlane := &infrastructurev1beta2.ContaboMachine{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{clusterv1.MachineControlPlaneLabel: ""}},
+			}
+			worker := &infrastructurev1beta2.ContaboMachine{}
+
+			config, err := etcdBackupCloudConfig(controlPlane, contaboCluster, bootstrapFlavorKubeadm)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(config)).To(ContainSubstring("30 2 * * * root /usr/local/bin/capc-etcd-backup-upload.sh"))
+			Expect(string(config)).To(ContainSubstring("get secret capc-etcd-backup"))
+			Expect(string(config)).NotTo(ContainSubstring("secretKey:"))
+
+			config, err = etcdBackupCloudConfig(worker, contaboCluster, bootstrapFlavorKubeadm)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(BeNil())
+
+			config, err = etcdBackupCloudConfig(controlPlane, contaboCluster, bootstrapFlavorK3s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(BeNil())
+		})
+	})
+
+	Context("When migrating legacy resources", func() {
+		It("should move the legacy cluster UUID and rename the legacy instances once", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+			bound := backend.AddInstance(models.InstanceResponse{DisplayName: StateClusterBound + " legacy-uuid"})
+			available := backend.AddInstance(models.InstanceResponse{DisplayName: StateAvailable})
+			failed := backend.AddInstance(models.InstanceResponse{DisplayName: StateError + " unknown"})
+			current := backend.AddInstance(models.InstanceResponse{DisplayName: "[capc] other worker-0"})
+
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+					Labels:    map[string]string{ClusterUUIDLabel: "legacy-uuid", clusterv1.ClusterNameLabel: "test"},
+				},
+			}
+			contaboMachine := &infrastructurev1beta2.ContaboMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "worker-0",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "test"},
+				},
+				Spec: infrastructurev1beta2.ContaboMachineSpec{Index: ptr.To(int32(0))},
+				Status: infrastructurev1beta2.ContaboMachineStatus{
+					Instance: &infrastructurev1beta2.ContaboInstanceStatus{InstanceId: bound},
+				},
+			}
+			k8sClient := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(contaboCluster, contaboMachine).Build()
+			migration := &LegacyMigration{Client: k8sClient, ContaboClient: contaboClient, Namespace: "capc-system"}
+			Expect(migration.Run(ctx)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test", Namespace: "default"}, contaboCluster)).To(Succeed())
+			Expect(contaboCluster.Spec.ClusterUUID).To(Equal("legacy-uuid"))
+			Expect(contaboCluster.Labels).NotTo(HaveKey(ClusterUUIDLabel))
+
+			displayNames := map[int64]string{}
+			for _, instance := range backend.Instances() {
+				displayNames[instance.InstanceId] = instance.DisplayName
+			}
+			Expect(displayNames).To(Equal(map[int64]string{
+				bound:     "[capc] legacy-uuid worker-0",
+				available: "",
+				failed:    StateError + " unknown",
+				current:   "[capc] other worker-0",
+			}))
+
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: LegacyMigrationConfigMapName, Namespace: "capc-system"}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("clusters", "1"))
+			Expect(configMap.Data).To(HaveKeyWithValue("instances", "2"))
+			Expect(configMap.Data).To(HaveKeyWithValue("skipped", fmt.Sprint(failed)))
+
+			// The recorded migration does not run again
+			backend.AddInstance(models.InstanceResponse{DisplayName: StateAvailable})
+			requests := backend.Requests()
+			Expect(migration.Run(ctx)).To(Succeed())
+			Expect(backend.Requests()).To(Equal(requests))
+		})
+	})
+
+	Context("When the controller runs in read-only mode", func() {
+		It("should refresh the status and postpone the deletion of the infrastructure", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			privateNetworkId := backend.AddPrivateNetwork("[capc] "+fixtureClusterUUID, "EU")
+			backend.AddSecret("[capc] "+fixtureClusterUUID, models.SecretResponseTypeSsh, "ssh-ed25519 AAAA")
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "frozen", Namespace: "default", UID: "cluster-frozen"}}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "frozen",
+					Namespace:  "default",
+					Finalizers: []string{infrastructurev1beta2.ClusterFinalizer},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: clusterv1.GroupVersion.String(),
+						Kind:       "Cluster",
+						Name:       cluster.Name,
+						UID:        cluster.UID,
+					}},
+				},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					ClusterUUID:    fixtureClusterUUID,
+					PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{Region: "EU"},
+				},
+				Status: infrastructurev1beta2.ContaboClusterStatus{
+					PrivateNetwork: &infrastructurev1beta2.ContaboPrivateNetworkStatus{
+						Name:             "[capc] " + fixtureClusterUUID,
+						PrivateNetworkId: privateNetworkId,
+						Region:           "EU",
+					},
+				},
+			}
+			k8sClient := crfake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, contaboCluster).
+				WithStatusSubresource(&infrastructurev1beta2.ContaboCluster{}).
+				Build()
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10), ContaboClient: contaboClient, ReadOnly: true}
+			key := types.NamespacedName{Name: "frozen", Namespace: "default"}
+
+			By("Refreshing the private network from the Contabo API")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(k8sClient.Get(ctx, key, contaboCluster)).To(Succeed())
+			Expect(contaboCluster.Status.PrivateNetwork.Cidr).To(Equal("10.0.0.0/22"))
+			Expect(contaboCluster.Status.SshKey).To(BeNil())
+			Expect(meta.IsStatusConditionTrue(contaboCluster.Status.Conditions, infrastructurev1beta2.ReadOnlyCondition)).To(BeTrue())
+
+			By("Postponing the deletion of the private network")
+			Expect(k8sClient.Delete(ctx, contaboCluster)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, contaboCluster)).To(Succeed())
+			Expect(contaboCluster.Finalizers).To(ContainElement(infrastructurev1beta2.ClusterFinalizer))
+			Expect(backend.PrivateNetwork(privateNetworkId)).NotTo(BeNil())
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ReadOnlyCondition)
+			Expect(condition.Message).To(ContainSubstring("deletion"))
+		})
+	})
+
+	Context("When deleting a cluster with a deletion confirmation threshold", func() {
+		It("should publish the deletion preview and wait for the confirmation", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			privateNetworkId := backend.AddPrivateNetwork("[capc] "+fixtureClusterUUID, "EU")
+			sshKeyId := backend.AddSecret("[capc] "+fixtureClusterUUID, models.SecretResponseTypeSsh, "ssh-ed25519 AAAA")
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "default", UID: "cluster-preview"}}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "preview",
+					Namespace:  "default",
+					Finalizers: []string{infrastructurev1beta2.ClusterFinalizer},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: clusterv1.GroupVersion.String(),
+						Kind:       "Cluster",
+						Name:       cluster.Name,
+						UID:        cluster.UID,
+					}},
+				},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					ClusterUUID:                   fixtureClusterUUID,
+					PrivateNetwork:                infrastructurev1beta2.ContaboPrivateNetworkSpec{Region: "EU"},
+					DeletionConfirmationThreshold: ptr.To(int32(1)),
+				},
+				Status: infrastructurev1beta2.ContaboClusterStatus{
+					PrivateNetwork: &infrastructurev1beta2.ContaboPrivateNetworkStatus{
+						Name:             "[capc] " + fixtureClusterUUID,
+						PrivateNetworkId: privateNetworkId,
+						Region:           "EU",
+					},
+					SshKey: &infrastructurev1beta2.ContaboSshKeyStatus{Name: "[capc] " + fixtureClusterUUID, SecretId: sshKeyId},
+				},
+			}
+			k8sClient := crfake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, contaboCluster).
+				WithStatusSubresource(&infrastructurev1beta2.ContaboCluster{}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder, ContaboClient: contaboClient}
+			key := types.NamespacedName{Name: "preview", Namespace: "default"}
+
+			By("Holding the deletion destroying more resources than the threshold")
+			Expect(k8sClient.Delete(ctx, contaboCluster)).To(Succeed())
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(k8sClient.Get(ctx, key, contaboCluster)).To(Succeed())
+			preview := contaboCluster.Status.DeletionPreview
+			Expect(preview).NotTo(BeNil())
+			Expect(preview.Destroyed).To(Equal(int32(2)))
+			Expect(preview.ConfirmationRequired).To(BeTrue())
+			Expect(preview.Resources).To(ContainElement(infrastructurev1beta2.ContaboDeletionPreviewResource{
+				Kind:   "PrivateNetwork",
+				ID:     strconv.FormatInt(privateNetworkId, 10),
+				Name:   "[capc] " + fixtureClusterUUID,
+				Action: infrastructurev1beta2.ContaboDeletionActionDelete,
+			}))
+			Expect(recorder.Events).To(Receive(ContainSubstring(infrastructurev1beta2.DeletionPreviewReason)))
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterReadyCondition)
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.DeletionConfirmationRequiredReason))
+			Expect(backend.PrivateNetwork(privateNetworkId)).NotTo(BeNil())
+
+			By("Ignoring a confirmation for another cluster")
+			contaboCluster.Annotations = map[string]string{infrastructurev1beta2.ConfirmDeletionAnnotation: "another-cluster"}
+			Expect(k8sClient.Update(ctx, contaboCluster)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backend.PrivateNetwork(privateNetworkId)).NotTo(BeNil())
+
+			By("Deleting the infrastructure once confirmed")
+			Expect(k8sClient.Get(ctx, key, contaboCluster)).To(Succeed())
+			contaboCluster.Annotations[infrastructurev1beta2.ConfirmDeletionAnnotation] = fixtureClusterUUID
+			Expect(k8sClient.Update(ctx, contaboCluster)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backend.PrivateNetwork(privateNetworkId)).To(BeNil())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, key, contaboCluster))).To(BeTrue())
+		})
+	})
+
+	Context("When the ContaboCluster references its own credentials", func() {
+		It("should wait for the credentials Secret and authorize the requests with it", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+
+			backend := fake.NewBackend()
+			contaboClient, err := backend.NewClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "default", UID: "cluster-tenant"}}
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tenant",
+					Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: clusterv1.GroupVersion.String(),
+						Kind:       "Cluster",
+						Name:       cluster.Name,
+						UID:        cluster.UID,
+					}},
+				},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					ClusterUUID:    fixtureClusterUUID,
+					PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{Region: "EU"},
+					CredentialsRef: &infrastructurev1beta2.ContaboCredentialsReference{Name: "tenant-contabo"},
+				},
+			}
+			k8sClient := crfake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, contaboCluster).
+				WithStatusSubresource(&infrastructurev1beta2.ContaboCluster{}).
+				Build()
+			credentials := NewClusterCredentials(k8sClient)
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &ContaboClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder, ContaboClient: contaboClient, Credentials: credentials}
+			key := types.NamespacedName{Name: "tenant", Namespace: "default"}
+
+			By("Reconciling nothing while the Secret is missing")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(k8sClient.Get(ctx, key, contaboCluster)).To(Succeed())
+			condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterReadyCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.CredentialsUnavailableReason))
+			Expect(recorder.Events).To(Receive(ContainSubstring(infrastructurev1beta2.CredentialsUnavailableReason)))
+			Expect(contaboCluster.Status.PrivateNetwork).To(BeNil())
+			condition = meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterCredentialsValidCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(infrastructurev1beta2.CredentialsUnavailableReason))
+
+			By("Rejecting an incomplete Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-contabo", Namespace: "default"},
+				Data: map[string][]byte{
+					CredentialsClientIDKey:     []byte("client"),
+					CredentialsClientSecretKey: []byte("secret"),
+					CredentialsAPIUserKey:      []byte("tenant@example.com"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			_, err = credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).To(MatchError(ContainSubstring(CredentialsAPIPasswordKey)))
+
+			By("Authorizing the requests with the credentials of the Secret")
+			secret.Data[CredentialsAPIPasswordKey] = []byte("password")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			clusterCtx, err := credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			tokenManager := auth.TokenManagerFromContext(clusterCtx)
+			Expect(tokenManager).NotTo(BeNil())
+			clusterCtx, err = credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(auth.TokenManagerFromContext(clusterCtx)).To(BeIdenticalTo(tokenManager))
+
+			By("Replacing the token manager when the credentials are rotated")
+			secret.Data[CredentialsAPIPasswordKey] = []byte("rotated")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			clusterCtx, err = credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(auth.TokenManagerFromContext(clusterCtx)).NotTo(BeIdenticalTo(tokenManager))
+			Expect(credentials.tokenManagers).To(HaveLen(1))
+
+			By("Evicting the token manager once the cluster is deleted")
+			credentials.forget(contaboCluster)
+			Expect(credentials.tokenManagers).To(BeEmpty())
+
+			By("Evicting the token manager once the Secret is deleted")
+			_, err = credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials.tokenManagers).To(HaveLen(1))
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			_, err = credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).To(HaveOccurred())
+			Expect(credentials.tokenManagers).To(BeEmpty())
+
+			By("Keeping the credentials of the controller for the other clusters")
+			contaboCluster.Spec.CredentialsRef = nil
+			clusterCtx, err = credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(auth.TokenManagerFromContext(clusterCtx)).To(BeNil())
+		})
+
+		It("should release the deleted cluster and machine whose credentials Secret is missing", func() {
+			ctx := context.Background()
+			chain := newOwnershipChain("tenant", "worker-a")
+			now := metav1.Now()
+			chain.ContaboCluster.Spec.CredentialsRef = &infrastructurev1beta2.ContaboCredentialsReference{Name: "tenant-contabo"}
+			chain.ContaboCluster.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Cluster",
+				Name:       chain.Cluster.Name,
+				UID:        chain.Cluster.UID,
+			}}
+			chain.ContaboCluster.Finalizers = []string{infrastructurev1beta2.ClusterFinalizer}
+			chain.ContaboCluster.DeletionTimestamp = &now
+			chain.ContaboMachine.Finalizers = []string{infrastructurev1beta2.MachineFinalizer}
+			chain.ContaboMachine.DeletionTimestamp = &now
+			privateNetworkId := chain.ContaboCluster.Status.PrivateNetwork.PrivateNetworkId
+			instanceId := chain.backend.AddInstance(models.InstanceResponse{DisplayName: "worker-a"})
+			machineReconciler, k8sClient := chain.build()
+			recorder := record.NewFakeRecorder(10)
+			machineReconciler.Recorder = recorder
+			machineReconciler.Credentials = NewClusterCredentials(k8sClient)
+			reconciler := &ContaboClusterReconciler{
+				Client:        k8sClient,
+				Scheme:        k8sClient.Scheme(),
+				Recorder:      recorder,
+				ContaboClient: machineReconciler.ContaboClient,
+				Credentials:   machineReconciler.Credentials,
+			}
+
+			By("Leaving the instance of the machine in the account")
+			machineKey := client.ObjectKeyFromObject(chain.ContaboMachine)
+			_, err := machineReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: machineKey})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, machineKey, &infrastructurev1beta2.ContaboMachine{}))).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("the instance of the machine is left in the account")))
+			Expect(chain.backend.Instances()).To(ContainElement(HaveField("InstanceId", instanceId)))
+
+			By("Leaving the private network of the cluster in the account")
+			clusterKey := client.ObjectKeyFromObject(chain.ContaboCluster)
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: clusterKey})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, clusterKey, &infrastructurev1beta2.ContaboCluster{}))).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("the Contabo resources of the cluster are left in the account")))
+			Expect(chain.backend.PrivateNetwork(privateNetworkId)).NotTo(BeNil())
+		})
+
+		It("should report the status of the ExternalSecret syncing the credentials Secret", func() {
+			ctx := context.Background()
+			scheme := newScheme()
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "default"},
+				Spec: infrastructurev1beta2.ContaboClusterSpec{
+					CredentialsRef: &infrastructurev1beta2.ContaboCredentialsReference{Name: "tenant-contabo"},
+				},
+			}
+
+			By("Ignoring the ExternalSecrets when the External Secrets Operator is not installed")
+			credentials := NewClusterCredentials(crfake.NewClientBuilder().WithScheme(scheme).Build())
+			_, err := credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).To(MatchError(ContainSubstring("failed to get credentials Secret tenant-contabo")))
+
+			By("Reporting the Ready condition of the ExternalSecret targeting the Secret")
+			scheme.AddKnownTypeWithName(ExternalSecretListGVK.GroupVersion().WithKind("ExternalSecret"), &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(ExternalSecretListGVK, &unstructured.UnstructuredList{})
+			externalSecret := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{"target": map[string]interface{}{"name": "tenant-contabo"}},
+				"status": map[string]interface{}{"conditions": []interface{}{map[string]interface{}{
+					"type":    "Ready",
+					"status":  "False",
+					"reason":  "SecretSyncedError",
+					"message": "could not get secret data from provider",
+				}}},
+			}}
+			externalSecret.SetGroupVersionKind(ExternalSecretListGVK.GroupVersion().WithKind("ExternalSecret"))
+			externalSecret.SetName("contabo")
+			externalSecret.SetNamespace("default")
+			credentials = NewClusterCredentials(crfake.NewClientBuilder().WithScheme(scheme).WithObjects(externalSecret).Build())
+			_, err = credentials.IntoContext(ctx, contaboCluster)
+			Expect(err).To(MatchError(ContainSubstring("ExternalSecret contabo is Ready=False, SecretSyncedError: could not get secret data from provider")))
+		})
+
+		It("should report whether the Contabo API accepts the credentials", func() {
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "default"}}
+			credentialsValid := func() *metav1.Condition {
+				return meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterCredentialsValidCondition)
+			}
+
+			setCredentialsValidCondition(contaboCluster, nil)
+			Expect(credentialsValid().Status).To(Equal(metav1.ConditionTrue))
+			Expect(credentialsValid().Reason).To(Equal(infrastructurev1beta2.CredentialsValidReason))
+
+			rejected := contabo.NewAPIError(&http.Response{StatusCode: http.StatusUnauthorized}, nil)
+			setCredentialsValidCondition(contaboCluster, fmt.Errorf("Failed to look up private network: %w", rejected))
+			Expect(credentialsValid().Status).To(Equal(metav1.ConditionFalse))
+			Expect(credentialsValid().Reason).To(Equal(infrastructurev1beta2.CredentialsRejectedReason))
+
+			By("Keeping the condition on the errors unrelated to the credentials")
+			setCredentialsValidCondition(contaboCluster, fmt.Errorf("Failed to look up private network: %w", errors.NewBadRequest("bad request")))
+			Expect(credentialsValid().Reason).To(Equal(infrastructurev1beta2.CredentialsRejectedReason))
+
+			setCredentialsValidCondition(contaboCluster, fmt.Errorf("Get \"https://api.contabo.com\": %w: status 401", auth.ErrAccessToken))
+			Expect(credentialsValid().Status).To(Equal(metav1.ConditionFalse))
+			Expect(credentialsValid().Reason).To(Equal(infrastructurev1beta2.AccessTokenFailedReason))
+		})
+	})
+
+	Context("When reporting the last operation", func() {
+		It("should follow the lifecycle of the cluster with the request ID of its last change", func() {
+			ctx := contextutil.WithChangeRecorder(context.Background())
+			contaboCluster := &infrastructurev1beta2.ContaboCluster{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "default"}}
+			setCondition := func(conditionType string, status metav1.ConditionStatus, reason string) {
+				meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{Type: conditionType, Status: status, Reason: reason})
+			}
+
+			By("Creating the cluster while its private network is missing")
+			setCondition(infrastructurev1beta2.ClusterCredentialsValidCondition, metav1.ConditionTrue, infrastructurev1beta2.CredentialsValidReason)
+			setCondition(infrastructurev1beta2.ClusterPrivateNetworkReadyCondition, metav1.ConditionFalse, infrastructurev1beta2.ClusterPrivateNetworkNotFoundReason)
+			setCondition(infrastructurev1beta2.ClusterReadyCondition, metav1.ConditionFalse, infrastructurev1beta2.ClusterCreatingReason)
+			contextutil.RecordChange(ctx, "create-private-network")
+			setClusterLastOperation(ctx, contaboCluster, nil)
+			operation := contaboCluster.Status.LastOperation
+			Expect(operation.Type).To(Equal(infrastructurev1beta2.ContaboLastOperationTypeCreate))
+			Expect(operation.State).To(Equal(infrastructurev1beta2.ContaboLastOperationStateProcessing))
+			Expect(operation.Reason).To(Equal(infrastructurev1beta2.ClusterPrivateNetworkNotFoundReason))
+			Expect(operation.RequestID).To(Equal("create-private-network"))
+			startedAt := operation.StartedAt
+
+			By("Finishing the creation once the cluster is ready")
+			setCondition(infrastructurev1beta2.ClusterPrivateNetworkReadyCondition, metav1.ConditionTrue, infrastructurev1beta2.ClusterPrivateNetworkReadyReason)
+			setCondition(infrastructurev1beta2.ClusterReadyCondition, metav1.ConditionTrue, infrastructurev1beta2.ClusterAvailableReason)
+			contaboCluster.Status.Initialization = &infrastructurev1beta2.ContaboClusterInitializationStatus{Provisioned: true}
+			setClusterLastOperation(context.Background(), contaboCluster, nil)
+			operation = contaboCluster.Status.LastOperation
+			Expect(operation.Type).To(Equal(infrastructurev1beta2.ContaboLastOperationTypeCreate))
+			Expect(operation.State).To(Equal(infrastructurev1beta2.ContaboLastOperationStateSucceeded))
+			Expect(operation.Reason).To(Equal(infrastructurev1beta2.ClusterAvailableReason))
+			Expect(operation.StartedAt).To(Equal(startedAt))
+			Expect(operation.FinishedAt).NotTo(BeNil())
+			Expect(operation.RequestID).To(Equal("create-private-network"))
+
+			By("Keeping the finished operation while nothing changes")
+			finishedAt := operation.FinishedAt
+			setClusterLastOperation(context.Background(), contaboCluster, nil)
+			Expect(contaboCluster.Status.LastOperation.Type).To(Equal(infrastructurev1beta2.ContaboLastOperationTypeCreate))
+			Expect(contaboCluster.Status.LastOperation.FinishedAt).To(Equal(finishedAt))
+
+			By("Starting a reconciliation on an error")
+			setClusterLastOperation(context.Background(), contaboCluster, fmt.Errorf("Failed to look up private network: status 500"))
+			operation = contaboCluster.Status.LastOperation
+			Expect(operation.Type).To(Equal(infrastructurev1beta2.ContaboLastOperationTypeReconcile))
+			Expect(operation.State).To(Equal(infrastructurev1beta2.ContaboLastOperationStateError))
+			Expect(operation.Message).To(Equal("Failed to look up private network: status 500"))
+			Expect(operation.FinishedAt).To(BeNil())
+			Expect(operation.RequestID).To(BeEmpty())
+
+			By("Deleting the cluster")
+			contaboCluster.DeletionTimestamp = ptr.To(metav1.Now())
+			setCondition(infrastructurev1beta2.ClusterReadyCondition, metav1.ConditionFalse, infrastructurev1beta2.ClusterDeletingReason)
+			setClusterLastOperation(context.Background(), contaboCluster, nil)
+			operation = contaboCluster.Status.LastOperation
+			Expect(operation.Type).To(Equal(infrastructurev1beta2.ContaboLastOperationTypeDelete))
+			Expect(operation.State).To(Equal(infrastructurev1beta2.ContaboLastOperationStateProcessing))
+			Expect(operation.Reason).To(Equal(infrastructurev1beta2.ClusterDeletingReason))
+		})
+	})
 })
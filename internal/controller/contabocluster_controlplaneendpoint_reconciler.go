@@ -27,6 +27,11 @@ func (r *ContaboClusterReconciler) reconcileControlPlaneEndpoint(ctx context.Con
 
 	log.Info("Reconciling control plane endpoint for ContaboCluster", "cluster", contaboCluster.Name)
 
+	// Default the API server port, it drives the bootstrap bind port and the endpoint slices
+	if contaboCluster.Spec.ControlPlaneEndpoint.Port == 0 {
+		contaboCluster.Spec.ControlPlaneEndpoint.Port = DefaultAPIServerPort
+	}
+
 	meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
 		Type:   clusterv1.ClusterControlPlaneAvailableCondition,
 		Status: metav1.ConditionTrue,
@@ -35,7 +40,16 @@ func (r *ContaboClusterReconciler) reconcileControlPlaneEndpoint(ctx context.Con
 
 	// Retrieve control plane endpoint from the first ContaboMachine in the cluster
 	controlPlaneMachines := &infrastructurev1beta2.ContaboMachineList{}
-	if err := r.List(ctx, controlPlaneMachines, client.InNamespace(contaboCluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: contaboCluster.Name}, client.HasLabels{clusterv1.MachineControlPlaneLabel}); err != nil || len(controlPlaneMachines.Items) == 0 {
+	err := r.List(ctx, controlPlaneMachines, client.InNamespace(contaboCluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: contaboCluster.Name}, client.HasLabels{clusterv1.MachineControlPlaneLabel})
+
+	// Publish a Contabo VIP as the control plane endpoint before the first control plane machine is created
+	if err == nil {
+		if result, err := r.reconcileControlPlaneEndpointVIP(ctx, contaboCluster, controlPlaneMachines); err != nil || result.RequeueAfter != 0 {
+			return result, err
+		}
+	}
+
+	if err != nil || len(controlPlaneMachines.Items) == 0 {
 		log.Info("No control plane machines found yet, requeuing")
 		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
 			Type:   clusterv1.ClusterControlPlaneAvailableCondition,
@@ -54,6 +68,11 @@ func (r *ContaboClusterReconciler) reconcileControlPlaneEndpoint(ctx context.Con
 		return ctrl.Result{}, err
 	}
 
+	// Generate the public and private network kubeconfig variants pointing to the control plane machines
+	if err := r.reconcileKubeconfigs(ctx, contaboCluster, controlPlaneMachines); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
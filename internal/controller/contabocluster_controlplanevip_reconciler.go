@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// reconcileControlPlaneEndpointVIP publishes a floating Contabo VIP as the control plane endpoint of the clusters
+// without a control plane endpoint host, and assigns it to a control plane instance, moving it to another control
+// plane instance when its holder is deleted
+func (r *ContaboClusterReconciler) reconcileControlPlaneEndpointVIP(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, controlPlaneMachines *infrastructurev1beta2.ContaboMachineList) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	endpoint := &contaboCluster.Spec.ControlPlaneEndpoint
+	vip := contaboCluster.Status.ControlPlaneEndpointVIP
+
+	// The VIP is only managed for the endpoint it was published as, it is released when the host is replaced
+	if endpoint.Host != "" && (vip == nil || vip.IP != endpoint.Host) {
+		if vip != nil {
+			if err := r.unassignControlPlaneEndpointVIP(ctx, vip); err != nil {
+				return ctrl.Result{}, err
+			}
+			log.Info("Released control plane endpoint VIP replaced by the control plane endpoint host", "ip", vip.IP, "host", endpoint.Host)
+			contaboCluster.Status.ControlPlaneEndpointVIP = nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if vip == nil {
+		available, err := r.availableControlPlaneEndpointVIP(ctx, contaboCluster, controlPlaneMachines)
+		if err != nil {
+			return ctrl.Result{}, r.handleError(
+				ctx,
+				contaboCluster,
+				err,
+				infrastructurev1beta2.ControlPlaneEndpointReadyCondition,
+				infrastructurev1beta2.ControlPlaneEndpointFailedReason,
+				"Failed to look up the floating VIPs for the control plane endpoint",
+			)
+		}
+		if available == nil {
+			log.Info("No floating VIP available for the control plane endpoint, requeuing", "region", contaboCluster.Spec.PrivateNetwork.Region)
+			meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.ControlPlaneEndpointReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.ControlPlaneEndpointVIPNotAvailableReason,
+				Message: fmt.Sprintf("No unassigned floating VIP in region %s, order one or set spec.controlPlaneEndpoint.host", contaboCluster.Spec.PrivateNetwork.Region),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+		}
+		vip = &infrastructurev1beta2.ContaboControlPlaneEndpointVIPStatus{IP: available.V4.Ip, VipId: available.VipId}
+		if instanceId, err := strconv.ParseInt(available.ResourceId, 10, 64); err == nil {
+			vip.InstanceId = &instanceId
+		}
+		contaboCluster.Status.ControlPlaneEndpointVIP = vip
+		endpoint.Host = vip.IP
+		log.Info("Published floating VIP as control plane endpoint", "ip", vip.IP, "vipId", vip.VipId)
+	}
+	vip.Port = endpoint.Port
+
+	holder := controlPlaneEndpointVIPHolder(vip, controlPlaneMachines)
+	if holder == nil {
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ControlPlaneEndpointReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ControlPlaneEndpointCreatingReason,
+			Message: fmt.Sprintf("Waiting for a control plane instance to hold VIP %s", vip.IP),
+		})
+		return ctrl.Result{}, nil
+	}
+
+	instanceId := holder.Status.Instance.InstanceId
+	if vip.InstanceId == nil || *vip.InstanceId != instanceId {
+		previous := vip.InstanceId
+		// Contabo refuses to assign a VIP held by another instance, the previous holder releases it first
+		if previous != nil {
+			if err := r.unassignControlPlaneEndpointVIP(ctx, vip); err != nil {
+				return ctrl.Result{}, r.handleError(
+					ctx,
+					contaboCluster,
+					err,
+					infrastructurev1beta2.ControlPlaneEndpointReadyCondition,
+					infrastructurev1beta2.ControlPlaneEndpointFailedReason,
+					fmt.Sprintf("Failed to unassign VIP %s from instance %d", vip.IP, *previous),
+				)
+			}
+			vip.InstanceId = nil
+			vip.MachineName = ""
+		}
+		resp, err := r.ContaboClient.AssignIpWithResponse(ctx, vip.IP, models.AssignIpParamsResourceTypeInstances, instanceId, nil)
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return ctrl.Result{}, r.handleError(
+				ctx,
+				contaboCluster,
+				err,
+				infrastructurev1beta2.ControlPlaneEndpointReadyCondition,
+				infrastructurev1beta2.ControlPlaneEndpointFailedReason,
+				fmt.Sprintf("Failed to assign VIP %s to instance %d", vip.IP, instanceId),
+			)
+		}
+		message := fmt.Sprintf("Assigned control plane endpoint VIP %s to instance %d of machine %s", vip.IP, instanceId, holder.Name)
+		if previous != nil {
+			message = fmt.Sprintf("Moved control plane endpoint VIP %s from instance %d to instance %d of machine %s", vip.IP, *previous, instanceId, holder.Name)
+		}
+		log.Info(message)
+		r.Recorder.Event(contaboCluster, corev1.EventTypeNormal, infrastructurev1beta2.ControlPlaneEndpointVIPAssignedReason, message)
+		vip.InstanceId = &instanceId
+	}
+	vip.MachineName = holder.Name
+
+	meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.ControlPlaneEndpointReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.ControlPlaneEndpointReadyReason,
+		Message: fmt.Sprintf("VIP %s held by instance %d of machine %s", vip.IP, instanceId, holder.Name),
+	})
+	return ctrl.Result{}, nil
+}
+
+// availableControlPlaneEndpointVIP returns the floating IPv4 VIP of the region of the cluster to publish as its
+// control plane endpoint: the one already assigned to an instance of the cluster, as when its status was lost, else
+// the first one neither assigned nor published by another ContaboCluster, nil when there is none
+func (r *ContaboClusterReconciler) availableControlPlaneEndpointVIP(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, controlPlaneMachines *infrastructurev1beta2.ContaboMachineList) (*models.ListVipResponseData, error) {
+	// VIPs are shared by the account, list the ones published by all ContaboClusters
+	contaboClusterList := &infrastructurev1beta2.ContaboClusterList{}
+	if err := r.List(ctx, contaboClusterList); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboClusters: %w", err)
+	}
+	published := map[string]bool{}
+	for _, other := range contaboClusterList.Items {
+		if other.UID != contaboCluster.UID && other.Status.ControlPlaneEndpointVIP != nil {
+			published[other.Status.ControlPlaneEndpointVIP.IP] = true
+		}
+	}
+	instances := map[string]bool{}
+	for _, machine := range controlPlaneMachines.Items {
+		if machine.Status.Instance != nil {
+			instances[strconv.FormatInt(machine.Status.Instance.InstanceId, 10)] = true
+		}
+	}
+
+	var available *models.ListVipResponseData
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrieveVipListWithResponse(ctx, &models.RetrieveVipListParams{
+			Page:      &page,
+			Size:      ptr.To(int64(100)),
+			Region:    ptr.To(string(contaboCluster.Spec.PrivateNetwork.Region)),
+			Type:      ptr.To(models.Floating),
+			IpVersion: ptr.To(models.V4),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return nil, fmt.Errorf("failed to list VIPs: %w", err)
+		}
+		for _, vip := range resp.JSON200.Data {
+			if vip.V4 == nil || vip.V4.Ip == "" || published[vip.V4.Ip] {
+				continue
+			}
+			if vip.ResourceId != "" && instances[vip.ResourceId] {
+				return &vip, nil
+			}
+			if vip.ResourceId == "" && available == nil {
+				available = &vip
+			}
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			break
+		}
+	}
+	return available, nil
+}
+
+// controlPlaneEndpointVIPHolder returns the control plane machine to hold the VIP: its current holder while it is
+// not deleted, else the ready machine with the lowest instance ID, else the machine with the lowest instance ID, nil
+// when no control plane machine has an instance
+func controlPlaneEndpointVIPHolder(vip *infrastructurev1beta2.ContaboControlPlaneEndpointVIPStatus, controlPlaneMachines *infrastructurev1beta2.ContaboMachineList) *infrastructurev1beta2.ContaboMachine {
+	candidates := []*infrastructurev1beta2.ContaboMachine{}
+	for i := range controlPlaneMachines.Items {
+		machine := &controlPlaneMachines.Items[i]
+		if !machine.DeletionTimestamp.IsZero() || machine.Status.Instance == nil || machine.Status.Instance.InstanceId == 0 {
+			continue
+		}
+		if vip.InstanceId != nil && machine.Status.Instance.InstanceId == *vip.InstanceId {
+			return machine
+		}
+		candidates = append(candidates, machine)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return slices.MinFunc(candidates, func(a, b *infrastructurev1beta2.ContaboMachine) int {
+		if a.Status.Ready != b.Status.Ready {
+			if a.Status.Ready {
+				return -1
+			}
+			return 1
+		}
+		return int(a.Status.Instance.InstanceId - b.Status.Instance.InstanceId)
+	})
+}
+
+// unassignControlPlaneEndpointVIP unassigns the VIP from the instance holding it, a VIP already unassigned, as when
+// its instance was cancelled, is ignored
+func (r *ContaboClusterReconciler) unassignControlPlaneEndpointVIP(ctx context.Context, vip *infrastructurev1beta2.ContaboControlPlaneEndpointVIPStatus) error {
+	if vip.InstanceId == nil {
+		return nil
+	}
+	resp, err := r.ContaboClient.UnassignIpWithResponse(ctx, vip.IP, models.UnassignIpParamsResourceTypeInstances, *vip.InstanceId, nil)
+	if err := contabo.CheckResponse(resp, err); err != nil && !errors.Is(err, contabo.ErrNotFound) {
+		return fmt.Errorf("failed to unassign VIP %s from instance %d: %w", vip.IP, *vip.InstanceId, err)
+	}
+	return nil
+}
+
+// controlPlaneEndpointVIPCloudConfig returns the cloud-config adding the control plane endpoint VIP of the cluster to
+// the loopback interface of a control plane instance at every boot, nil for the other machines and the clusters
+// without VIP. Contabo routes the VIP to the instance holding it, so every control plane instance answers on the VIP
+// without announcing it and the VIP moves without reconfiguring the instances.
+func controlPlaneEndpointVIPCloudConfig(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) ([]byte, error) {
+	vip := contaboCluster.Status.ControlPlaneEndpointVIP
+	if vip == nil || !isControlPlaneMachine(contaboMachine) {
+		return nil, nil
+	}
+
+	script := strings.Join([]string{
+		"#!/bin/sh",
+		"sysctl -q -w net.ipv4.conf.all.arp_ignore=1 net.ipv4.conf.all.arp_announce=2",
+		fmt.Sprintf("ip -4 addr replace %s/32 dev lo", vip.IP),
+	}, "\n")
+	service := strings.Join([]string{
+		"[Unit]",
+		"Description=Configure the Contabo control plane endpoint VIP",
+		"After=network-online.target",
+		"Wants=network-online.target",
+		"",
+		"[Service]",
+		"Type=oneshot",
+		"ExecStart=/usr/local/bin/contabo-control-plane-vip.sh",
+		"",
+		"[Install]",
+		"WantedBy=multi-user.target",
+	}, "\n")
+
+	return yaml.Marshal(map[string]interface{}{
+		"write_files": []interface{}{
+			map[string]interface{}{
+				"path":        "/usr/local/bin/contabo-control-plane-vip.sh",
+				"owner":       "root:root",
+				"permissions": "0755",
+				"content":     script,
+			},
+			map[string]interface{}{
+				"path":        "/etc/systemd/system/contabo-control-plane-vip.service",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     service,
+			},
+		},
+		"runcmd": []interface{}{
+			"systemctl daemon-reload && systemctl enable contabo-control-plane-vip.service && systemctl start contabo-control-plane-vip.service",
+		},
+	})
+}
@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// deletionPreviewEventResources is the maximum number of resources listed in the deletion preview event, the status
+// lists all of them
+const deletionPreviewEventResources = 20
+
+// reconcileDeletionPreview computes and publishes once the Contabo resources the deletion of the cluster destroys,
+// then returns true while the deletion waits for its confirmation. It runs as soon as the deletion of the Cluster is
+// received, as Cluster API deletes the machines before the ContaboCluster.
+func (r *ContaboClusterReconciler) reconcileDeletionPreview(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, bool) {
+	log := logf.FromContext(ctx)
+
+	if contaboCluster.Status.DeletionPreview == nil {
+		resources, err := r.deletionPreviewResources(ctx, contaboCluster)
+		if err != nil {
+			log.Info("Failed to compute the deletion preview, retrying", "error", err.Error())
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, true
+		}
+		preview := &infrastructurev1beta2.ContaboClusterDeletionPreviewStatus{
+			ComputeTime: metav1.Now(),
+			Resources:   resources,
+		}
+		for _, resource := range resources {
+			if resource.Action != infrastructurev1beta2.ContaboDeletionActionRetain {
+				preview.Destroyed++
+			}
+		}
+		threshold := contaboCluster.Spec.DeletionConfirmationThreshold
+		preview.ConfirmationRequired = threshold != nil && preview.Destroyed > *threshold
+		contaboCluster.Status.DeletionPreview = preview
+
+		log.Info("Computed the deletion preview", "destroyed", preview.Destroyed, "confirmationRequired", preview.ConfirmationRequired)
+		r.Recorder.Event(contaboCluster, corev1.EventTypeNormal, infrastructurev1beta2.DeletionPreviewReason, deletionPreviewMessage(preview))
+	}
+
+	if !deletionConfirmationPending(contaboCluster) {
+		return ctrl.Result{}, false
+	}
+	meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+		Type:   infrastructurev1beta2.ClusterReadyCondition,
+		Status: metav1.ConditionFalse,
+		Reason: infrastructurev1beta2.DeletionConfirmationRequiredReason,
+		Message: fmt.Sprintf("The deletion destroys %d Contabo resources, more than the threshold of %d, set the %s annotation to %s to confirm it",
+			contaboCluster.Status.DeletionPreview.Destroyed, *contaboCluster.Spec.DeletionConfirmationThreshold,
+			infrastructurev1beta2.ConfirmDeletionAnnotation, contaboCluster.Spec.ClusterUUID),
+	})
+	return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, true
+}
+
+// deletionConfirmationPending reports whether the deletion of the cluster and of its machines waits for the
+// ConfirmDeletionAnnotation. The annotation must hold the cluster UUID, so that it is not copied from another cluster.
+func deletionConfirmationPending(contaboCluster *infrastructurev1beta2.ContaboCluster) bool {
+	preview := contaboCluster.Status.DeletionPreview
+	return preview != nil && preview.ConfirmationRequired && contaboCluster.Spec.DeletionConfirmationThreshold != nil &&
+		contaboCluster.Annotations[infrastructurev1beta2.ConfirmDeletionAnnotation] != contaboCluster.Spec.ClusterUUID
+}
+
+// deletionPreviewResources returns the Contabo resources of the cluster and what its deletion does to them: the
+// instances of the machines are reset, the SSH key and the private network are deleted unless the private network is
+// shared with another cluster or holds instances not managed by the provider, and the etcd snapshots are kept.
+func (r *ContaboClusterReconciler) deletionPreviewResources(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) ([]infrastructurev1beta2.ContaboDeletionPreviewResource, error) {
+	resources := []infrastructurev1beta2.ContaboDeletionPreviewResource{}
+
+	contaboMachineList := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachineList, client.InNamespace(contaboCluster.Namespace), client.MatchingLabels{
+		clusterv1.ClusterNameLabel: contaboCluster.Name,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboMachines: %w", err)
+	}
+	for _, contaboMachine := range contaboMachineList.Items {
+		if contaboMachine.Status.Instance == nil {
+			continue
+		}
+		// The instances of the machines with the Cancel reuse policy are not returned to the free pool
+		action := infrastructurev1beta2.ContaboDeletionActionReset
+		if cancelsInstance(&contaboMachine) {
+			action = infrastructurev1beta2.ContaboDeletionActionDelete
+		}
+		resources = append(resources, infrastructurev1beta2.ContaboDeletionPreviewResource{
+			Kind:   "Instance",
+			ID:     strconv.FormatInt(contaboMachine.Status.Instance.InstanceId, 10),
+			Name:   client.ObjectKeyFromObject(&contaboMachine).String(),
+			Action: action,
+		})
+	}
+
+	if privateNetwork := contaboCluster.Status.PrivateNetwork; privateNetwork != nil {
+		action := infrastructurev1beta2.ContaboDeletionActionDelete
+		references, _, err := r.getPrivateNetworkReferences(ctx, contaboCluster)
+		if err != nil {
+			return nil, err
+		}
+		if len(references) > 0 || !ptr.Deref(contaboCluster.Spec.PrivateNetwork.CreateIfNotExists, true) {
+			action = infrastructurev1beta2.ContaboDeletionActionRetain
+		} else if contaboCluster.Spec.PartialAdoption != nil {
+			resp, err := r.ContaboClient.RetrievePrivateNetworkWithResponse(ctx, privateNetwork.PrivateNetworkId, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve private network %d: %w", privateNetwork.PrivateNetworkId, err)
+			}
+			if resp.JSON200 != nil && len(resp.JSON200.Data) > 0 {
+				ignored, err := r.ignoredPrivateNetworkInstances(ctx, contaboCluster, resp.JSON200.Data[0].Instances)
+				if err != nil {
+					return nil, err
+				}
+				if len(ignored) > 0 {
+					action = infrastructurev1beta2.ContaboDeletionActionRetain
+				}
+			}
+		}
+		resources = append(resources, infrastructurev1beta2.ContaboDeletionPreviewResource{
+			Kind:   "PrivateNetwork",
+			ID:     strconv.FormatInt(privateNetwork.PrivateNetworkId, 10),
+			Name:   privateNetwork.Name,
+			Action: action,
+		})
+	}
+
+	if sshKey := contaboCluster.Status.SshKey; sshKey != nil {
+		resources = append(resources, infrastructurev1beta2.ContaboDeletionPreviewResource{
+			Kind:   "SshKey",
+			ID:     strconv.FormatInt(sshKey.SecretId, 10),
+			Name:   sshKey.Name,
+			Action: infrastructurev1beta2.ContaboDeletionActionDelete,
+		})
+	}
+
+	if vip := contaboCluster.Status.ControlPlaneEndpointVIP; vip != nil {
+		resources = append(resources, infrastructurev1beta2.ContaboDeletionPreviewResource{
+			Kind:   "VIP",
+			ID:     vip.VipId,
+			Name:   vip.IP,
+			Action: infrastructurev1beta2.ContaboDeletionActionRetain,
+		})
+	}
+
+	if etcdBackup := contaboCluster.Status.EtcdBackup; etcdBackup != nil {
+		resources = append(resources, infrastructurev1beta2.ContaboDeletionPreviewResource{
+			Kind:   "EtcdSnapshots",
+			ID:     etcdBackup.Prefix,
+			Action: infrastructurev1beta2.ContaboDeletionActionRetain,
+		})
+	}
+	return resources, nil
+}
+
+// deletionPreviewMessage returns the message of the deletion preview event
+func deletionPreviewMessage(preview *infrastructurev1beta2.ContaboClusterDeletionPreviewStatus) string {
+	listed := []string{}
+	for i, resource := range preview.Resources {
+		if i == deletionPreviewEventResources {
+			listed = append(listed, fmt.Sprintf("and %d more", len(preview.Resources)-i))
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s %s %s", resource.Action, resource.Kind, resource.ID))
+	}
+	message := fmt.Sprintf("The deletion destroys %d Contabo resources", preview.Destroyed)
+	if len(listed) > 0 {
+		message += ": " + strings.Join(listed, ", ")
+	}
+	if preview.ConfirmationRequired {
+		message += fmt.Sprintf(", waiting for the %s annotation", infrastructurev1beta2.ConfirmDeletionAnnotation)
+	}
+	return message
+}
@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// EtcdBackupSyncInterval is how often the etcd snapshots of the clusters with etcd backups are listed and pruned
+	EtcdBackupSyncInterval = 15 * time.Minute
+
+	// etcdBackupRetryInterval is how often the etcd backups are set up again after a failure
+	etcdBackupRetryInterval = time.Minute
+)
+
+// reconcileEtcdBackup sets up the etcd backups of the cluster: it creates the bucket, copies its credentials to the
+// capc-etcd-backup Secret of the workload cluster for the control plane machines to upload the snapshots, and deletes
+// the snapshots beyond the retention. It is best effort, failures are reported in the ClusterEtcdBackupReady condition
+// without blocking the cluster. It returns when to list the snapshots again.
+func (r *ContaboClusterReconciler) reconcileEtcdBackup(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) ctrl.Result {
+	log := logf.FromContext(ctx)
+
+	if contaboCluster.Spec.EtcdBackup == nil {
+		contaboCluster.Status.EtcdBackup = nil
+		meta.RemoveStatusCondition(&contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterEtcdBackupReadyCondition)
+		return ctrl.Result{}
+	}
+
+	// List the snapshots at most once per sync interval while the backups are set up
+	condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterEtcdBackupReadyCondition)
+	setUp := condition != nil && condition.Reason != infrastructurev1beta2.ClusterEtcdBackupFailedReason &&
+		condition.Reason != infrastructurev1beta2.ClusterEtcdBackupWaitingForWorkloadClusterReason && condition.ObservedGeneration == contaboCluster.Generation
+	if status := contaboCluster.Status.EtcdBackup; setUp && status != nil && status.LastSyncTime != nil {
+		if elapsed := time.Since(status.LastSyncTime.Time); elapsed < EtcdBackupSyncInterval {
+			return ctrl.Result{RequeueAfter: EtcdBackupSyncInterval - elapsed}
+		}
+	}
+
+	storage, err := newEtcdBackupObjectStorage(ctx, r.Client, contaboCluster)
+	if err != nil {
+		r.setEtcdBackupCondition(contaboCluster, infrastructurev1beta2.ClusterEtcdBackupFailedReason, err.Error())
+		return ctrl.Result{RequeueAfter: etcdBackupRetryInterval}
+	}
+
+	if !setUp {
+		if err := storage.createBucket(ctx); err != nil {
+			log.Error(err, "Failed to create the etcd backup bucket", "bucket", storage.bucket)
+			r.setEtcdBackupCondition(contaboCluster, infrastructurev1beta2.ClusterEtcdBackupFailedReason, err.Error())
+			return ctrl.Result{RequeueAfter: etcdBackupRetryInterval}
+		}
+	}
+
+	// Keep the credentials of the workload cluster in sync, e.g. after a rotation
+	clientset, err := r.getKubeClient(ctx, contaboCluster)
+	if err == nil {
+		err = applyEtcdBackupSecret(ctx, clientset, etcdBackupSecretData(contaboCluster, storage))
+	}
+	if err != nil {
+		log.V(1).Info("Waiting for the workload cluster to hand it the etcd backup credentials", "reason", err.Error())
+		r.setEtcdBackupCondition(contaboCluster, infrastructurev1beta2.ClusterEtcdBackupWaitingForWorkloadClusterReason,
+			fmt.Sprintf("Failed to set the etcd backup credentials in the workload cluster: %v", err))
+		return ctrl.Result{RequeueAfter: etcdBackupRetryInterval}
+	}
+
+	if err := r.syncEtcdSnapshots(ctx, contaboCluster, storage); err != nil {
+		log.Error(err, "Failed to sync the etcd snapshots", "bucket", storage.bucket)
+		r.setEtcdBackupCondition(contaboCluster, infrastructurev1beta2.ClusterEtcdBackupFailedReason, err.Error())
+		return ctrl.Result{RequeueAfter: etcdBackupRetryInterval}
+	}
+
+	if contaboCluster.Status.EtcdBackup.Snapshots == 0 {
+		r.setEtcdBackupCondition(contaboCluster, infrastructurev1beta2.ClusterEtcdBackupWaitingForSnapshotReason,
+			fmt.Sprintf("Waiting for the control plane to upload an etcd snapshot on schedule %q", etcdBackupSchedule(contaboCluster.Spec.EtcdBackup)))
+	} else {
+		r.setEtcdBackupCondition(contaboCluster, infrastructurev1beta2.ClusterEtcdBackupReadyReason,
+			fmt.Sprintf("%d etcd snapshots in bucket %s", contaboCluster.Status.EtcdBackup.Snapshots, storage.bucket))
+	}
+	return ctrl.Result{RequeueAfter: EtcdBackupSyncInterval}
+}
+
+// setEtcdBackupCondition sets the ClusterEtcdBackupReady condition, true only with the ready reason
+func (r *ContaboClusterReconciler) setEtcdBackupCondition(contaboCluster *infrastructurev1beta2.ContaboCluster, reason string, message string) {
+	status := metav1.ConditionFalse
+	if reason == infrastructurev1beta2.ClusterEtcdBackupReadyReason {
+		status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+		Type:               infrastructurev1beta2.ClusterEtcdBackupReadyCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: contaboCluster.Generation,
+	})
+}
+
+// syncEtcdSnapshots deletes the oldest etcd snapshots of the cluster beyond the retention and records the remaining ones
+func (r *ContaboClusterReconciler) syncEtcdSnapshots(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, storage *bootstrapObjectStorage) error {
+	log := logf.FromContext(ctx)
+
+	prefix := etcdBackupPrefix(contaboCluster)
+	objects, err := storage.list(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	snapshots := make([]objectStorageObject, 0, len(objects))
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, ".db") {
+			snapshots = append(snapshots, object)
+		}
+	}
+	// Newest first, the keys start with the snapshot time
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].LastModified.Equal(snapshots[j].LastModified) {
+			return snapshots[i].LastModified.After(snapshots[j].LastModified)
+		}
+		return snapshots[i].Key > snapshots[j].Key
+	})
+
+	retention := etcdBackupRetention(contaboCluster.Spec.EtcdBackup)
+	var pruneErr error
+	pruned := 0
+	for len(snapshots) > retention {
+		expired := snapshots[len(snapshots)-1]
+		if pruneErr = storage.delete(ctx, expired.Key); pruneErr != nil {
+			break
+		}
+		log.Info("Deleted etcd snapshot beyond the retention", "key", expired.Key, "retention", retention)
+		snapshots = snapshots[:len(snapshots)-1]
+		pruned++
+	}
+	if pruned > 0 {
+		r.Recorder.Eventf(contaboCluster, corev1.EventTypeNormal, infrastructurev1beta2.ClusterEtcdBackupPrunedReason,
+			"Deleted %d etcd snapshots beyond the retention of %d", pruned, retention)
+	}
+
+	status := &infrastructurev1beta2.ContaboEtcdBackupStatus{
+		Prefix:       prefix,
+		Snapshots:    int32(len(snapshots)),
+		LastSyncTime: ptr.To(metav1.Now()),
+	}
+	if len(snapshots) > 0 {
+		status.LastSnapshotKey = snapshots[0].Key
+		status.LastSnapshotTime = ptr.To(metav1.NewTime(snapshots[0].LastModified))
+	}
+	contaboCluster.Status.EtcdBackup = status
+	return pruneErr
+}
+
+// applyEtcdBackupSecret creates or updates the capc-etcd-backup Secret of the workload cluster
+func applyEtcdBackupSecret(ctx context.Context, clientset kubernetes.Interface, data map[string][]byte) error {
+	secrets := clientset.CoreV1().Secrets(metav1.NamespaceSystem)
+	existing, err := secrets.Get(ctx, EtcdBackupSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      EtcdBackupSecretName,
+				Namespace: metav1.NamespaceSystem,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "cluster-api-provider-contabo"},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if maps.EqualFunc(existing.Data, data, func(a, b []byte) bool { return string(a) == string(b) }) {
+		return nil
+	}
+	existing.Data = data
+	_, err = secrets.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+// getKubeClient returns a clientset of the workload cluster from the Cluster API kubeconfig
+func (r *ContaboClusterReconciler) getKubeClient(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) (kubernetes.Interface, error) {
+	kubeconfigSecret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{
+		Name:      FormatKubeconfigKubernetesName(contaboCluster),
+		Namespace: contaboCluster.Namespace,
+	}, kubeconfigSecret); err != nil {
+		return nil, err
+	}
+	kubeconfig, ok := kubeconfigSecret.Data["value"]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig secret %s is missing 'value' key", kubeconfigSecret.Name)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// reconcileExternallyManaged reports the private network and SSH key of a ContaboCluster managed by an external
+// controller, e.g. a GitOps pipeline, with the cluster.x-k8s.io/managed-by annotation. Nothing is created, changed or
+// deleted in Contabo or in the management cluster, and the readiness of the infrastructure is set by the external
+// controller, as defined by the Cluster API contract.
+func (r *ContaboClusterReconciler) reconcileExternallyManaged(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	log.Info("ContaboCluster is externally managed, only reporting its status")
+
+	privateNetworkFound, err := r.observeExternalPrivateNetwork(ctx, contaboCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	sshKeyFound, err := r.observeExternalSSHKey(ctx, contaboCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The external controller creates the missing resources, they are looked up again
+	if !privateNetworkFound || !sshKeyFound {
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// observeExternalPrivateNetwork records the private network of an externally managed ContaboCluster in its status,
+// looked up by name, and returns false when it does not exist yet
+func (r *ContaboClusterReconciler) observeExternalPrivateNetwork(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) (bool, error) {
+	privateNetworkName := contaboCluster.Spec.PrivateNetwork.Name
+	if privateNetworkName == "" && contaboCluster.Spec.ClusterUUID != "" {
+		privateNetworkName = FormatPrivateNetworkName(contaboCluster)
+	}
+	if privateNetworkName == "" {
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ClusterWaitingForExternalResourceReason,
+			Message: "Set spec.privateNetwork.name or spec.clusterUUID to look up the private network",
+		})
+		return false, nil
+	}
+
+	resp, err := r.ContaboClient.RetrievePrivateNetworkListWithResponse(ctx, &models.RetrievePrivateNetworkListParams{
+		Name: &privateNetworkName,
+	})
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		return false, markTransient(fmt.Errorf("failed to retrieve private network %s: %w", privateNetworkName, err))
+	}
+	if len(resp.JSON200.Data) == 0 {
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ClusterWaitingForExternalResourceReason,
+			Message: fmt.Sprintf("Private network %s not found, it is created by the external controller", privateNetworkName),
+		})
+		return false, nil
+	}
+
+	privateNetwork := &resp.JSON200.Data[0]
+	contaboCluster.Status.PrivateNetwork = privateNetworkStatus(privateNetwork)
+	updatePrivateNetworkHints(contaboCluster)
+	contaboCluster.Status.PrivateNetworkHints.Gateway = privateNetworkGateway(privateNetwork.Instances)
+	meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.ClusterExternallyManagedReason,
+		Message: fmt.Sprintf("Private network %s is managed by the external controller", privateNetwork.Name),
+	})
+	return true, nil
+}
+
+// observeExternalSSHKey records the Contabo SSH key of an externally managed ContaboCluster in its status, looked up
+// by name, and returns false when it does not exist yet
+func (r *ContaboClusterReconciler) observeExternalSSHKey(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) (bool, error) {
+	if contaboCluster.Spec.ClusterUUID == "" {
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterSshKeyReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ClusterWaitingForExternalResourceReason,
+			Message: "Set spec.clusterUUID to look up the SSH key",
+		})
+		return false, nil
+	}
+
+	sshKeyContaboName := FormatSshKeyContaboName(contaboCluster)
+	resp, err := r.ContaboClient.RetrieveSecretListWithResponse(ctx, &models.RetrieveSecretListParams{
+		Name: &sshKeyContaboName,
+	})
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		return false, markTransient(fmt.Errorf("failed to retrieve SSH key %s: %w", sshKeyContaboName, err))
+	}
+	if len(resp.JSON200.Data) == 0 {
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterSshKeyReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ClusterWaitingForExternalResourceReason,
+			Message: fmt.Sprintf("SSH key %s not found, it is created by the external controller", sshKeyContaboName),
+		})
+		return false, nil
+	}
+
+	sshKey := &resp.JSON200.Data[0]
+	contaboCluster.Status.SshKey = &infrastructurev1beta2.ContaboSshKeyStatus{
+		Name:     sshKey.Name,
+		SecretId: int64(sshKey.SecretId),
+		Value:    sshKey.Value,
+	}
+	meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.ClusterSshKeyReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.ClusterExternallyManagedReason,
+		Message: fmt.Sprintf("SSH key %s is managed by the external controller", sshKey.Name),
+	})
+	return true, nil
+}
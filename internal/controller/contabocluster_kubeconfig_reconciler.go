@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+func FormatKubeconfigKubernetesName(contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	return Truncate(fmt.Sprintf("%s-kubeconfig", contaboCluster.Name), 253)
+}
+
+func FormatPublicKubeconfigKubernetesName(contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	return Truncate(fmt.Sprintf("%s-kubeconfig-public", contaboCluster.Name), 253)
+}
+
+func FormatPrivateKubeconfigKubernetesName(contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	return Truncate(fmt.Sprintf("%s-kubeconfig-private", contaboCluster.Name), 253)
+}
+
+// kubeconfigEndpoints returns the public and private IPv4 of the control plane machine the kubeconfig variants point
+// to, ready machines first then by name so the endpoint only changes when the machine goes away
+func kubeconfigEndpoints(controlPlaneMachines []infrastructurev1beta2.ContaboMachine) (string, string) {
+	machines := make([]infrastructurev1beta2.ContaboMachine, 0, len(controlPlaneMachines))
+	for _, machine := range controlPlaneMachines {
+		if machine.DeletionTimestamp.IsZero() {
+			machines = append(machines, machine)
+		}
+	}
+	sort.SliceStable(machines, func(i, j int) bool {
+		if machines[i].Status.Ready != machines[j].Status.Ready {
+			return machines[i].Status.Ready
+		}
+		return machines[i].Name < machines[j].Name
+	})
+
+	for _, machine := range machines {
+		publicIp, privateIp := "", ""
+		for _, address := range machine.Status.Addresses {
+			ip := net.ParseIP(address.Address)
+			if ip == nil || ip.To4() == nil {
+				continue
+			}
+			switch address.Type {
+			case clusterv1.MachineExternalIP:
+				publicIp = address.Address
+			case clusterv1.MachineInternalIP:
+				privateIp = address.Address
+			}
+		}
+		if publicIp != "" && privateIp != "" {
+			return publicIp, privateIp
+		}
+	}
+	return "", ""
+}
+
+// renderKubeconfigVariant points the clusters of the kubeconfig to the given server. The TLS server name is set to
+// the original host so the API server certificate is still verified against the control plane endpoint.
+func renderKubeconfigVariant(kubeconfig []byte, server string) ([]byte, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+	for _, cluster := range config.Clusters {
+		if cluster.TLSServerName == "" {
+			original, err := url.Parse(cluster.Server)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse kubeconfig server %q: %w", cluster.Server, err)
+			}
+			cluster.TLSServerName = original.Hostname()
+		}
+		cluster.Server = server
+	}
+	return clientcmd.Write(*config)
+}
+
+// reconcileKubeconfigs generates the kubeconfig Secrets of the public and private network endpoints from the Cluster
+// API kubeconfig, so that tooling running in Contabo uses the private network while operators use the public one.
+// Both are kept in sync with the control plane machines and the Cluster API kubeconfig.
+func (r *ContaboClusterReconciler) reconcileKubeconfigs(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, controlPlaneMachines *infrastructurev1beta2.ContaboMachineList) error {
+	log := logf.FromContext(ctx)
+
+	kubeconfigSecret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{
+		Name:      FormatKubeconfigKubernetesName(contaboCluster),
+		Namespace: contaboCluster.Namespace,
+	}, kubeconfigSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(1).Info("Waiting for the Cluster API kubeconfig to generate the kubeconfig variants")
+			return nil
+		}
+		return fmt.Errorf("failed to get kubeconfig secret: %w", err)
+	}
+	kubeconfig, ok := kubeconfigSecret.Data["value"]
+	if !ok {
+		return fmt.Errorf("kubeconfig secret %s is missing 'value' key", kubeconfigSecret.Name)
+	}
+
+	publicIp, privateIp := kubeconfigEndpoints(controlPlaneMachines.Items)
+	if publicIp == "" || privateIp == "" {
+		log.V(1).Info("Waiting for a control plane machine with public and private addresses to generate the kubeconfig variants")
+		return nil
+	}
+
+	port := strconv.Itoa(int(contaboCluster.Spec.ControlPlaneEndpoint.Port))
+	status := &infrastructurev1beta2.ContaboKubeconfigStatus{
+		PublicSecretName:  FormatPublicKubeconfigKubernetesName(contaboCluster),
+		PublicServer:      "https://" + net.JoinHostPort(publicIp, port),
+		PrivateSecretName: FormatPrivateKubeconfigKubernetesName(contaboCluster),
+		PrivateServer:     "https://" + net.JoinHostPort(privateIp, port),
+	}
+	if err := r.reconcileKubeconfigSecret(ctx, contaboCluster, status.PublicSecretName, "public", kubeconfig, status.PublicServer); err != nil {
+		return err
+	}
+	if err := r.reconcileKubeconfigSecret(ctx, contaboCluster, status.PrivateSecretName, "private", kubeconfig, status.PrivateServer); err != nil {
+		return err
+	}
+
+	if previous := contaboCluster.Status.Kubeconfig; previous != nil && (previous.PublicServer != status.PublicServer || previous.PrivateServer != status.PrivateServer) {
+		r.Recorder.Eventf(contaboCluster, corev1.EventTypeNormal, infrastructurev1beta2.ClusterKubeconfigUpdatedReason,
+			"Kubeconfig variants now point to %s and %s", status.PublicServer, status.PrivateServer)
+	}
+	contaboCluster.Status.Kubeconfig = status
+
+	return nil
+}
+
+// reconcileKubeconfigSecret creates or updates a kubeconfig variant Secret
+func (r *ContaboClusterReconciler) reconcileKubeconfigSecret(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, name string, variant string, kubeconfig []byte, server string) error {
+	log := logf.FromContext(ctx)
+
+	value, err := renderKubeconfigVariant(kubeconfig, server)
+	if err != nil {
+		return fmt.Errorf("failed to render %s kubeconfig: %w", variant, err)
+	}
+
+	existingSecret := &corev1.Secret{}
+	err = r.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: contaboCluster.Namespace,
+	}, existingSecret)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get %s kubeconfig secret: %w", variant, err)
+		}
+		log.Info("Creating kubeconfig secret", "secretName", name, "server", server)
+		err = r.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: contaboCluster.Namespace,
+				Annotations: map[string]string{
+					clusterv1.ClusterNameAnnotation: contaboCluster.Name,
+				},
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel: contaboCluster.Name,
+					"component":                "kubeconfig-" + variant,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: contaboCluster.APIVersion,
+						Kind:       contaboCluster.Kind,
+						Name:       contaboCluster.Name,
+						UID:        contaboCluster.UID,
+						Controller: ptr.To(true),
+					},
+				},
+			},
+			Type: clusterv1.ClusterSecretType,
+			Data: map[string][]byte{"value": value},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create %s kubeconfig secret: %w", variant, err)
+		}
+		return nil
+	}
+
+	if string(existingSecret.Data["value"]) == string(value) {
+		return nil
+	}
+	log.Info("Updating kubeconfig secret", "secretName", name, "server", server)
+	existingSecret.Data = map[string][]byte{"value": value}
+	if err := r.Update(ctx, existingSecret); err != nil {
+		return fmt.Errorf("failed to update %s kubeconfig secret: %w", variant, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// abnormalTrueMachineConditions are the machine conditions reporting a problem when true, the other conditions
+// report a problem when false
+var abnormalTrueMachineConditions = []string{infrastructurev1beta2.ReadOnlyCondition}
+
+// reconcileMachines aggregates the conditions of the machines of the cluster into the Machines status and the
+// ClusterMachinesReady condition, a single place to check the health of the cluster infrastructure. The
+// ClusterReady condition is left as is, it only reports the cluster infrastructure Cluster API waits for.
+func (r *ContaboClusterReconciler) reconcileMachines(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachines, client.InNamespace(contaboCluster.Namespace), client.MatchingLabels{
+		clusterv1.ClusterNameLabel: contaboCluster.Name,
+	}); err != nil {
+		log.Error(err, "Failed to list ContaboMachines to aggregate their conditions")
+		return
+	}
+
+	status := summarizeMachines(contaboMachines.Items)
+	contaboCluster.Status.Machines = status
+
+	condition := metav1.Condition{
+		Type:    infrastructurev1beta2.ClusterMachinesReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.ClusterMachinesReadyReason,
+		Message: status.Summary,
+	}
+	if status.Ready < status.Total || len(status.Conditions) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = infrastructurev1beta2.ClusterMachinesNotReadyReason
+	}
+	meta.SetStatusCondition(&contaboCluster.Status.Conditions, condition)
+}
+
+// summarizeMachines counts the ready machines and the machines reporting a problem with each condition
+func summarizeMachines(contaboMachines []infrastructurev1beta2.ContaboMachine) *infrastructurev1beta2.ContaboClusterMachinesStatus {
+	status := &infrastructurev1beta2.ContaboClusterMachinesStatus{Total: int32(len(contaboMachines))}
+	problems := map[string][]string{}
+	for i := range contaboMachines {
+		contaboMachine := &contaboMachines[i]
+		if meta.IsStatusConditionTrue(contaboMachine.Status.Conditions, infrastructurev1beta2.MachineReadyCondition) {
+			status.Ready++
+		}
+		for _, condition := range contaboMachine.Status.Conditions {
+			if condition.Type == infrastructurev1beta2.MachineReadyCondition {
+				continue
+			}
+			abnormal := metav1.ConditionFalse
+			if slices.Contains(abnormalTrueMachineConditions, condition.Type) {
+				abnormal = metav1.ConditionTrue
+			}
+			if condition.Status == abnormal {
+				problems[condition.Type] = append(problems[condition.Type], contaboMachine.Name)
+			}
+		}
+	}
+
+	summary := []string{fmt.Sprintf("%d/%d machines Ready", status.Ready, status.Total)}
+	for _, conditionType := range slices.Sorted(maps.Keys(problems)) {
+		machines := problems[conditionType]
+		slices.Sort(machines)
+		status.Conditions = append(status.Conditions, infrastructurev1beta2.ContaboClusterMachineConditionSummary{
+			Type:     conditionType,
+			Count:    int32(len(machines)),
+			Machines: machines,
+		})
+		summary = append(summary, fmt.Sprintf("%d %s", len(machines), conditionType))
+	}
+	status.Summary = strings.Join(summary, ", ")
+	return status
+}
+
+// machineConditionsChanged passes the ContaboMachine events changing what the Machines status of the cluster
+// aggregates, the control plane machines always pass as the endpoint and the kubeconfig variants follow them
+func machineConditionsChanged() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if _, isControlPlane := e.ObjectNew.GetLabels()[clusterv1.MachineControlPlaneLabel]; isControlPlane {
+				return true
+			}
+			oldMachine, ok := e.ObjectOld.(*infrastructurev1beta2.ContaboMachine)
+			if !ok {
+				return true
+			}
+			newMachine, ok := e.ObjectNew.(*infrastructurev1beta2.ContaboMachine)
+			if !ok {
+				return true
+			}
+			return !machineConditionStatusesEqual(oldMachine.Status.Conditions, newMachine.Status.Conditions)
+		},
+	}
+}
+
+// machineConditionStatusesEqual returns true when the conditions have the same types and statuses
+func machineConditionStatusesEqual(a, b []metav1.Condition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, condition := range a {
+		other := meta.FindStatusCondition(b, condition.Type)
+		if other == nil || other.Status != condition.Status {
+			return false
+		}
+	}
+	return true
+}
@@ -2,14 +2,21 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/netip"
+	"sort"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
 	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
 )
 
@@ -29,17 +36,50 @@ func (r *ContaboClusterReconciler) reconcilePrivateNetwork(ctx context.Context,
 	resp, err := r.ContaboClient.RetrievePrivateNetworkListWithResponse(ctx, &models.RetrievePrivateNetworkListParams{
 		Name: &privateNetworkName,
 	})
-	if err != nil || resp.JSON200 == nil || resp.JSON200.Data == nil || len(resp.JSON200.Data) == 0 {
+	// A failed lookup is never a missing private network, or a second one would be created
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		return ctrl.Result{}, r.handleError(
+			ctx,
+			contaboCluster,
+			err,
+			infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+			infrastructurev1beta2.ClusterPrivateNetworkFailedReason,
+			"Failed to look up private network",
+		)
+	}
+	if len(resp.JSON200.Data) == 0 && !ptr.Deref(contaboCluster.Spec.PrivateNetwork.CreateIfNotExists, true) {
+		log.Info("Private network not found in Contabo API, waiting for it to be created", "privateNetworkName", privateNetworkName)
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ClusterPrivateNetworkNotFoundReason,
+			Message: fmt.Sprintf("Private network %s not found, it is not created as createIfNotExists is false", privateNetworkName),
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+	if len(resp.JSON200.Data) == 0 {
 		log.Info("Private network not found in Contabo API, creating new one", "privateNetworkName", privateNetworkName)
 
+		// Enforce namespace ContaboQuota before creating a new private network
+		if err := checkQuota(ctx, r.Client, nil, client.ObjectKeyFromObject(contaboCluster), quotaRequest{PrivateNetworks: 1}); err != nil {
+			log.Info("Waiting for quota to create a new private network", "reason", err.Error())
+			meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.ClusterPrivateNetworkWaitingForQuotaReason,
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.QuotaInterval()}, nil
+		}
+
 		// Create private network if not found
 		description := "Private network created by Cluster API Provider Contabo"
 		privateNetworkCreateResp, err := r.ContaboClient.CreatePrivateNetworkWithResponse(ctx, nil, models.CreatePrivateNetworkJSONRequestBody{
 			Name:        privateNetworkName,
 			Description: &description,
-			Region:      &contaboCluster.Spec.PrivateNetwork.Region,
+			Region:      (*string)(&contaboCluster.Spec.PrivateNetwork.Region),
 		})
-		if err != nil || privateNetworkCreateResp.StatusCode() < 200 || privateNetworkCreateResp.StatusCode() >= 300 {
+		if err := contabo.CheckResponse(privateNetworkCreateResp, err); err != nil {
 			return ctrl.Result{}, r.handleError(
 				ctx,
 				contaboCluster,
@@ -58,28 +98,112 @@ func (r *ContaboClusterReconciler) reconcilePrivateNetwork(ctx context.Context,
 
 	privateNetwork := &resp.JSON200.Data[0]
 
+	// Machines are never attached to a private network with another range than the expected one
+	if cidr := contaboCluster.Spec.PrivateNetwork.CIDR; cidr != "" && !sameCIDR(cidr, privateNetwork.Cidr) {
+		log.Info("Private network range differs from the spec CIDR", "privateNetworkName", privateNetworkName, "cidr", privateNetwork.Cidr, "expected", cidr)
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ClusterPrivateNetworkCIDRMismatchReason,
+			Message: fmt.Sprintf("Private network %s has range %s, expected %s", privateNetworkName, privateNetwork.Cidr, cidr),
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	// Remove the instances left in the private network so that its membership reflects the machines
+	privateNetwork.Instances = r.reconcilePrivateNetworkAssignments(ctx, contaboCluster, privateNetwork.PrivateNetworkId, privateNetwork.Name, privateNetwork.Instances)
+
 	// Update status with private network info
-	contaboCluster.Status.PrivateNetwork = &infrastructurev1beta2.ContaboPrivateNetworkStatus{
+	contaboCluster.Status.PrivateNetwork = privateNetworkStatus(privateNetwork)
+	// Record the routing details of the private network, the MTU is detected on the first bootstrapped instance
+	updatePrivateNetworkHints(contaboCluster)
+	contaboCluster.Status.PrivateNetworkHints.Gateway = privateNetworkGateway(privateNetwork.Instances)
+
+	// Track other clusters sharing the private network to protect it on deletion
+	references, _, err := r.getPrivateNetworkReferences(ctx, contaboCluster)
+	if err != nil {
+		log.Error(err, "Failed to compute private network references")
+	} else {
+		contaboCluster.Status.PrivateNetworkSharedWith = references
+	}
+
+	meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
+		Type:   infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+		Status: metav1.ConditionTrue,
+		Reason: infrastructurev1beta2.ClusterAvailableReason,
+	})
+
+	log.Info("Private network reconciled", "privateNetworkName", privateNetwork.Name, "privateNetworkId", privateNetwork.PrivateNetworkId)
+
+	return ctrl.Result{}, nil
+}
+
+// sameCIDR returns true when both CIDRs are valid and hold the same range
+func sameCIDR(cidr, other string) bool {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return false
+	}
+	otherPrefix, err := netip.ParsePrefix(other)
+	return err == nil && prefix.Masked() == otherPrefix.Masked()
+}
+
+// getPrivateNetworkReferences returns the other ContaboClusters (namespace/name) referencing the same private network,
+// and whether one of them is being deleted. A deleting cluster references the private network until its machines are
+// gone, their instances are still assigned to it.
+func (r *ContaboClusterReconciler) getPrivateNetworkReferences(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) ([]string, bool, error) {
+	references := []string{}
+	deleting := false
+	if contaboCluster.Status.PrivateNetwork == nil {
+		return references, deleting, nil
+	}
+
+	// Private networks can be shared across namespaces, list all ContaboClusters
+	contaboClusterList := &infrastructurev1beta2.ContaboClusterList{}
+	if err := r.List(ctx, contaboClusterList); err != nil {
+		return nil, false, fmt.Errorf("failed to list ContaboClusters: %w", err)
+	}
+
+	for _, other := range contaboClusterList.Items {
+		if other.UID == contaboCluster.UID {
+			continue
+		}
+		if other.Status.PrivateNetwork == nil || other.Status.PrivateNetwork.PrivateNetworkId != contaboCluster.Status.PrivateNetwork.PrivateNetworkId {
+			continue
+		}
+		if !other.DeletionTimestamp.IsZero() {
+			contaboMachineList := &infrastructurev1beta2.ContaboMachineList{}
+			if err := r.List(ctx, contaboMachineList, client.InNamespace(other.Namespace), client.MatchingLabels{
+				clusterv1.ClusterNameLabel: other.Name,
+			}); err != nil {
+				return nil, false, fmt.Errorf("failed to list ContaboMachines of %s/%s: %w", other.Namespace, other.Name, err)
+			}
+			if len(contaboMachineList.Items) == 0 {
+				continue
+			}
+			deleting = true
+		}
+		references = append(references, fmt.Sprintf("%s/%s", other.Namespace, other.Name))
+	}
+	sort.Strings(references)
+
+	return references, deleting, nil
+}
+
+// privateNetworkStatus converts a private network of the Contabo API to the status of the ContaboCluster
+func privateNetworkStatus(privateNetwork *models.ListPrivateNetworkResponseData) *infrastructurev1beta2.ContaboPrivateNetworkStatus {
+	return &infrastructurev1beta2.ContaboPrivateNetworkStatus{
 		Name:             privateNetwork.Name,
 		PrivateNetworkId: privateNetwork.PrivateNetworkId,
 		Region:           privateNetwork.Region,
 		AvailableIps:     privateNetwork.AvailableIps,
 		Cidr:             privateNetwork.Cidr,
 		CreatedDate:      privateNetwork.CreatedDate.UTC().Unix(),
-		Instances:        []infrastructurev1beta2.Instances{},
+		Instances:        convertPrivateNetworkInstances(privateNetwork.Instances),
 		CustomerId:       privateNetwork.CustomerId,
 		TenantId:         privateNetwork.TenantId,
 		Description:      privateNetwork.Description,
 		DataCenter:       privateNetwork.DataCenter,
 		RegionName:       privateNetwork.RegionName,
 	}
-	meta.SetStatusCondition(&contaboCluster.Status.Conditions, metav1.Condition{
-		Type:   infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
-		Status: metav1.ConditionTrue,
-		Reason: infrastructurev1beta2.ClusterAvailableReason,
-	})
-
-	log.Info("Private network reconciled", "privateNetworkName", privateNetwork.Name, "privateNetworkId", privateNetwork.PrivateNetworkId)
-
-	return ctrl.Result{}, nil
 }
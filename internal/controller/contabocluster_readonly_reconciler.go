@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// reconcileReadOnly only observes the private network of the ContaboCluster while the controller runs with
+// --read-only: its status is refreshed from the Contabo API and nothing is created, changed or deleted in Contabo or
+// in the workload cluster. The deletion of the cluster waits for the read-only mode to end.
+func (r *ContaboClusterReconciler) reconcileReadOnly(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) ctrl.Result {
+	log := logf.FromContext(ctx)
+
+	message := "The controller runs with --read-only, the changes to the Contabo resources are suspended"
+	if !contaboCluster.DeletionTimestamp.IsZero() {
+		message = "The controller runs with --read-only, the deletion of the Contabo resources is postponed"
+	}
+	log.Info("Read-only mode, only observing the cluster infrastructure", "deleting", !contaboCluster.DeletionTimestamp.IsZero())
+	setReadOnlyCondition(&contaboCluster.Status.Conditions, contaboCluster.Generation, true, message)
+
+	if contaboCluster.Status.PrivateNetwork != nil {
+		r.observePrivateNetwork(ctx, contaboCluster)
+	}
+	r.reconcileMachines(ctx, contaboCluster)
+	return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}
+}
+
+// observePrivateNetwork refreshes the private network of the ContaboCluster status from the Contabo API, e.g. its
+// instances and available IPs. Failures are only logged, the previous status is kept.
+func (r *ContaboClusterReconciler) observePrivateNetwork(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	status := contaboCluster.Status.PrivateNetwork
+	resp, err := r.ContaboClient.RetrievePrivateNetworkListWithResponse(ctx, &models.RetrievePrivateNetworkListParams{
+		Name: &status.Name,
+	})
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		log.Info("Failed to retrieve private network", "privateNetworkId", status.PrivateNetworkId, "error", err)
+		return
+	}
+	for i := range resp.JSON200.Data {
+		privateNetwork := &resp.JSON200.Data[i]
+		if privateNetwork.PrivateNetworkId != status.PrivateNetworkId {
+			continue
+		}
+		contaboCluster.Status.PrivateNetwork = privateNetworkStatus(privateNetwork)
+		updatePrivateNetworkHints(contaboCluster)
+		contaboCluster.Status.PrivateNetworkHints.Gateway = privateNetworkGateway(privateNetwork.Instances)
+		return
+	}
+	log.Info("Private network not found in Contabo API", "privateNetworkId", status.PrivateNetworkId)
+}
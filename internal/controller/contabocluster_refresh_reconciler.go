@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// reconcileRefresh records the status refresh of the cluster machines requested with the RefreshAnnotation, once per
+// annotation value. The ContaboMachine controller watches the annotation and refreshes every machine of the cluster,
+// see applyRefresh.
+func (r *ContaboClusterReconciler) reconcileRefresh(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	request := contaboCluster.Annotations[infrastructurev1beta2.RefreshAnnotation]
+	if request == "" || (contaboCluster.Status.Refresh != nil && contaboCluster.Status.Refresh.Request == request) {
+		return
+	}
+
+	contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachines, client.InNamespace(contaboCluster.Namespace), client.MatchingLabels{
+		clusterv1.ClusterNameLabel: contaboCluster.Name,
+	}); err != nil {
+		log.Error(err, "Failed to list ContaboMachines to refresh")
+		return
+	}
+
+	log.Info("Refreshing the status of the cluster machines", "request", request, "machines", len(contaboMachines.Items))
+	contaboCluster.Status.Refresh = &infrastructurev1beta2.ContaboClusterRefreshStatus{
+		Request:     request,
+		RequestTime: metav1.Now(),
+		Machines:    int32(len(contaboMachines.Items)),
+	}
+	r.Recorder.Eventf(contaboCluster, corev1.EventTypeNormal, infrastructurev1beta2.ClusterRefreshRequestedReason,
+		"Refreshing the status of %d machines", len(contaboMachines.Items))
+}
+
+// refreshRequested returns the refresh requested on the ContaboCluster not yet applied to the machine, empty when
+// there is none
+func refreshRequested(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster client.Object) string {
+	request := contaboCluster.GetAnnotations()[infrastructurev1beta2.RefreshAnnotation]
+	if request == contaboMachine.Status.RefreshRequest {
+		return ""
+	}
+	return request
+}
+
+// applyRefresh makes the periodic refreshes of the machine run now when a refresh is requested on the ContaboCluster,
+// bypassing their intervals. The Contabo API requests still go through the rate limiter of the cluster, so
+// refreshing a large cluster is spread over time instead of exceeding the Contabo API budget.
+func (r *ContaboMachineReconciler) applyRefresh(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	request := refreshRequested(contaboMachine, contaboCluster)
+	if request == "" {
+		return
+	}
+
+	logf.FromContext(ctx).Info("Refreshing the machine status", "request", request)
+	contaboMachine.Status.AuditTrailLastUpdated = nil
+	if contaboMachine.Status.Host != nil {
+		contaboMachine.Status.Host.LastChecked = nil
+	}
+	contaboMachine.Status.RefreshRequest = request
+}
@@ -2,10 +2,10 @@ package controller
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,12 +19,22 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
 	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 )
 
+// Keys of the SSH key pair in the SSH key secrets. The ed25519 key pairs are generated by the provider, the RSA keys are
+// read from the secrets created by the previous versions and from the secrets provided by the users.
+const (
+	SSHPrivateKeyKey       = "id_ed25519"
+	SSHPublicKeyKey        = "id_ed25519.pub"
+	LegacySSHPrivateKeyKey = "id_rsa"
+	LegacySSHPublicKeyKey  = "id_rsa.pub"
+)
+
 // reconcileSSHKey ensures the SSH key exists and is configured
 func (r *ContaboClusterReconciler) reconcileSSHKey(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
@@ -79,8 +89,8 @@ func (r *ContaboClusterReconciler) reconcileKubernetesSSHKeySecret(ctx context.C
 
 		// Create new secret
 		secretData := map[string][]byte{
-			"id_rsa":     []byte(privateKey),
-			"id_rsa.pub": []byte(publicKey),
+			SSHPrivateKeyKey: []byte(privateKey),
+			SSHPublicKeyKey:  []byte(publicKey),
 		}
 		err = r.Create(ctx, &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -153,26 +163,47 @@ func (r *ContaboClusterReconciler) reconcileContaboSSHKeySecret(ctx context.Cont
 
 	log.Info("Found existing SSH key secret in Kubernetes", "secretName", sshKeyKubernetesName)
 
-	publicKey := string(sshKeySecret.Data["id_rsa.pub"])
+	_, publicKey, err := sshKeyPairFromSecret(sshKeySecret)
+	if err != nil {
+		return ctrl.Result{}, r.handleError(
+			ctx,
+			contaboCluster,
+			err,
+			infrastructurev1beta2.ClusterSshKeyReadyCondition,
+			infrastructurev1beta2.ClusterSshKeyFailedReason,
+			"Invalid SSH key secret",
+		)
+	}
 
 	// Check if SSH key with the same name already exists in Contabo API
 	resp, err := r.ContaboClient.RetrieveSecretListWithResponse(ctx, &models.RetrieveSecretListParams{
 		Name: &sshKeyContaboName,
 	})
 
-	if err != nil || resp.JSON200 == nil || resp.JSON200.Data == nil || len(resp.JSON200.Data) == 0 {
+	// A failed lookup is never a missing SSH key, or a second one would be created
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		return ctrl.Result{}, r.handleError(
+			ctx,
+			contaboCluster,
+			err,
+			infrastructurev1beta2.ClusterSshKeyReadyCondition,
+			infrastructurev1beta2.ClusterSshKeyFailedReason,
+			"Failed to look up SSH key in Contabo API",
+		)
+	}
+	if len(resp.JSON200.Data) == 0 {
 		log.Info("SSH key not found in Contabo API, creating new one", "sshKeyContaboName", sshKeyContaboName)
 
 		// Create SSH key if not found
 		// Trim the public key for Contabo API (remove trailing newline)
 		trimmedPublicKey := strings.TrimSpace(publicKey)
 
-		sshKeyCreateResp, err := r.ContaboClient.CreateSecretWithResponse(ctx, &models.CreateSecretParams{}, models.CreateSecretRequest{
+		sshKeyCreateResp, err := r.ContaboClient.CreateSecretWithResponse(ctx, contabo.NewParams[models.CreateSecretParams](ctx), models.CreateSecretRequest{
 			Name:  sshKeyContaboName,
 			Value: trimmedPublicKey,
 			Type:  "ssh",
 		})
-		if err != nil || sshKeyCreateResp.StatusCode() < 200 || sshKeyCreateResp.StatusCode() >= 300 {
+		if err := contabo.CheckResponse(sshKeyCreateResp, err); err != nil {
 			return ctrl.Result{}, r.handleError(
 				ctx,
 				contaboCluster,
@@ -184,8 +215,8 @@ func (r *ContaboClusterReconciler) reconcileContaboSSHKeySecret(ctx context.Cont
 		}
 
 		// Retrieve the created SSH key for further processing
-		sshKeyRetrieveResp, err := r.ContaboClient.RetrieveSecretWithResponse(ctx, int64(sshKeyCreateResp.JSON201.Data[0].SecretId), &models.RetrieveSecretParams{})
-		if err != nil || sshKeyRetrieveResp.StatusCode() < 200 || sshKeyRetrieveResp.StatusCode() >= 300 {
+		sshKeyRetrieveResp, err := r.ContaboClient.RetrieveSecretWithResponse(ctx, int64(sshKeyCreateResp.JSON201.Data[0].SecretId), contabo.NewParams[models.RetrieveSecretParams](ctx))
+		if err := contabo.CheckResponse(sshKeyRetrieveResp, err); err != nil {
 			return ctrl.Result{}, r.handleError(
 				ctx,
 				contaboCluster,
@@ -216,30 +247,74 @@ func (r *ContaboClusterReconciler) reconcileContaboSSHKeySecret(ctx context.Cont
 	return ctrl.Result{}, nil
 }
 
-// generateSSHKeyPair generates a new RSA SSH key pair and returns the public and private keys as strings
+// deleteMachineSSHKeys deletes the Contabo secrets registered for the SSH key secrets of the machines of the cluster,
+// see FormatMachineSshKeyContaboName
+func (r *ContaboClusterReconciler) deleteMachineSSHKeys(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster) error {
+	log := logf.FromContext(ctx)
+
+	prefix := FormatSshKeyContaboName(contaboCluster) + " "
+	secretIds := []int64{}
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrieveSecretListWithResponse(ctx, &models.RetrieveSecretListParams{
+			Page: &page,
+			Size: ptr.To(int64(100)),
+			Type: ptr.To(models.Ssh),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to list SSH keys: %w", err)
+		}
+		for _, secret := range resp.JSON200.Data {
+			if strings.HasPrefix(secret.Name, prefix) {
+				secretIds = append(secretIds, int64(secret.SecretId))
+			}
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			break
+		}
+	}
+
+	for _, secretId := range secretIds {
+		resp, err := r.ContaboClient.DeleteSecretWithResponse(ctx, secretId, nil)
+		if err := contabo.CheckResponse(resp, err); err != nil && !errors.Is(err, contabo.ErrNotFound) {
+			return fmt.Errorf("failed to delete SSH key %d: %w", secretId, err)
+		}
+		log.Info("Deleted machine SSH key", "sshKeyID", secretId)
+	}
+	return nil
+}
+
+// generateSSHKeyPair generates a new ed25519 SSH key pair and returns the private key in the OpenSSH PEM format and the
+// public key in the authorized_keys format
 func generateSSHKeyPair() (string, string, error) {
-	// generate private key
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
 	if err != nil {
 		return "", "", err
 	}
 
-	// write private key as PEM
-	var privKeyBuf strings.Builder
-
-	privateKeyPEM := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}
-	if err := pem.Encode(&privKeyBuf, privateKeyPEM); err != nil {
+	privateKeyPEM, err := ssh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
 		return "", "", err
 	}
 
-	// generate and write public key
-	pub, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	pub, err := ssh.NewPublicKey(publicKey)
 	if err != nil {
 		return "", "", err
 	}
 
-	var pubKeyBuf strings.Builder
-	pubKeyBuf.Write(ssh.MarshalAuthorizedKey(pub))
+	return string(pem.EncodeToMemory(privateKeyPEM)), string(ssh.MarshalAuthorizedKey(pub)), nil
+}
 
-	return privKeyBuf.String(), pubKeyBuf.String(), nil
+// sshKeyPairFromSecret returns the private and public keys of an SSH key secret, its ed25519 keys or else its RSA keys
+func sshKeyPairFromSecret(secret *corev1.Secret) ([]byte, string, error) {
+	for _, keys := range [][2]string{{SSHPrivateKeyKey, SSHPublicKeyKey}, {LegacySSHPrivateKeyKey, LegacySSHPublicKeyKey}} {
+		privateKey, publicKey := secret.Data[keys[0]], secret.Data[keys[1]]
+		if len(privateKey) == 0 && len(publicKey) == 0 {
+			continue
+		}
+		if len(privateKey) == 0 || len(publicKey) == 0 {
+			return nil, "", fmt.Errorf("SSH key secret %s/%s is missing '%s' or '%s' key or is empty", secret.Namespace, secret.Name, keys[0], keys[1])
+		}
+		return privateKey, string(publicKey), nil
+	}
+	return nil, "", fmt.Errorf("SSH key secret %s/%s has neither '%s' nor '%s' key", secret.Namespace, secret.Name, SSHPrivateKeyKey, LegacySSHPrivateKeyKey)
 }
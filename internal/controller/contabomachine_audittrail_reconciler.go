@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// AuditTrailMaxEntries is the maximum number of audit entries kept in the ContaboMachine status
+	AuditTrailMaxEntries = 10
+
+	// AuditTrailRefreshInterval is the default minimum time between two audit trail refreshes
+	AuditTrailRefreshInterval = 10 * time.Minute
+)
+
+// reconcileAuditTrail refreshes the latest Contabo audit entries of the machine instance and its image.
+// Failures are only logged as the audit trail is informational.
+func (r *ContaboMachineReconciler) reconcileAuditTrail(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	log := logf.FromContext(ctx)
+
+	instance := contaboMachine.Status.Instance
+	if instance == nil {
+		return
+	}
+	if contaboMachine.Status.AuditTrailLastUpdated != nil &&
+		time.Since(contaboMachine.Status.AuditTrailLastUpdated.Time) < r.Settings.AuditTrailInterval() {
+		return
+	}
+
+	entries := []infrastructurev1beta2.ContaboAuditEntry{}
+	orderBy := []string{"timestamp:DESC"}
+
+	instanceResp, err := r.ContaboClient.RetrieveInstancesAuditsListWithResponse(ctx, &models.RetrieveInstancesAuditsListParams{
+		InstanceId: &instance.InstanceId,
+		OrderBy:    &orderBy,
+		Size:       ptr.To(int64(AuditTrailMaxEntries)),
+	})
+	if err := contabo.CheckResponse(instanceResp, err); err != nil {
+		log.Info("Failed to retrieve instance audit entries", "instanceID", instance.InstanceId, "error", err)
+		return
+	}
+	for _, audit := range instanceResp.JSON200.Data {
+		entries = append(entries, infrastructurev1beta2.ContaboAuditEntry{
+			Resource:   "Instance",
+			ResourceId: strconv.FormatInt(audit.InstanceId, 10),
+			Action:     string(audit.Action),
+			Username:   audit.Username,
+			ChangedBy:  audit.ChangedBy,
+			Timestamp:  metav1.NewTime(audit.Timestamp),
+			RequestId:  audit.RequestId,
+			TraceId:    audit.TraceId,
+			Changes:    auditChangedFields(audit.Changes),
+		})
+	}
+	r.reconcileConcurrentManagers(ctx, contaboMachine, entries)
+
+	if instance.ImageId != "" {
+		imageResp, err := r.ContaboClient.RetrieveImageAuditsListWithResponse(ctx, &models.RetrieveImageAuditsListParams{
+			ImageId: &instance.ImageId,
+			OrderBy: &orderBy,
+			Size:    ptr.To(int64(AuditTrailMaxEntries)),
+		})
+		if err := contabo.CheckResponse(imageResp, err); err != nil {
+			log.Info("Failed to retrieve image audit entries", "imageID", instance.ImageId, "error", err)
+		} else {
+			for _, audit := range imageResp.JSON200.Data {
+				entries = append(entries, infrastructurev1beta2.ContaboAuditEntry{
+					Resource:   "Image",
+					ResourceId: audit.ImageId,
+					Action:     string(audit.Action),
+					Username:   audit.Username,
+					ChangedBy:  audit.ChangedBy,
+					Timestamp:  metav1.NewTime(audit.Timestamp),
+					RequestId:  audit.RequestId,
+					TraceId:    audit.TraceId,
+					Changes:    auditChangedFields(audit.Changes),
+				})
+			}
+		}
+	}
+
+	contaboMachine.Status.AuditTrail = latestAuditEntries(entries, AuditTrailMaxEntries)
+	contaboMachine.Status.AuditTrailLastUpdated = ptr.To(metav1.Now())
+}
+
+// latestAuditEntries returns at most limit entries, newest first
+func latestAuditEntries(entries []infrastructurev1beta2.ContaboAuditEntry, limit int) []infrastructurev1beta2.ContaboAuditEntry {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[j].Timestamp.Before(&entries[i].Timestamp)
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// auditChangedFields returns the sorted names of the changed fields of an audit entry
+func auditChangedFields(changes *map[string]interface{}) []string {
+	if changes == nil {
+		return nil
+	}
+	fields := make([]string, 0, len(*changes))
+	for field := range *changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
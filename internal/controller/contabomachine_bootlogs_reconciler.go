@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// bootLogsSectionMarker prefixes the ConfigMap key of each section of the boot logs command output
+	bootLogsSectionMarker = "==> capc:"
+
+	// bootLogsTailLines is the number of latest lines kept from each log
+	bootLogsTailLines = 500
+
+	// bootLogsMaxSectionBytes caps each section so that the ConfigMap stays under the 1MiB object size limit
+	bootLogsMaxSectionBytes = 128 * 1024
+)
+
+// bootLogsSections are the logs collected from the instance, keyed by their ConfigMap key. Contabo exposes no
+// console output, the kernel log of the previous boot is the closest to it, e.g. after a kernel panic.
+var bootLogsSections = []struct {
+	key     string
+	command string
+}{
+	{key: "previous-boot-kernel.log", command: "sudo journalctl -b -1 -k --no-pager"},
+	{key: "kernel.log", command: "sudo journalctl -b -k --no-pager"},
+	{key: "errors.log", command: "sudo journalctl -b -p err --no-pager"},
+	{key: "cloud-init-status.log", command: "cloud-init status --long"},
+	{key: "cloud-init-output.log", command: "sudo cat /var/log/cloud-init-output.log"},
+}
+
+// bootLogsCommand returns the command printing the tail of each boot log after its section marker
+func bootLogsCommand() string {
+	commands := make([]string, 0, len(bootLogsSections))
+	for _, section := range bootLogsSections {
+		commands = append(commands, fmt.Sprintf("echo '%s%s'; (%s) 2>&1 | tail -n %d", bootLogsSectionMarker, section.key, section.command, bootLogsTailLines))
+	}
+	return strings.Join(commands, "; ")
+}
+
+// parseBootLogs splits the boot logs command output into its sections, each truncated to its latest
+// bootLogsMaxSectionBytes. The output before the first marker, e.g. a login banner, is dropped.
+func parseBootLogs(output string) map[string]string {
+	logs := map[string]string{}
+	key := ""
+	var section strings.Builder
+	flush := func() {
+		if key == "" {
+			return
+		}
+		value := section.String()
+		if len(value) > bootLogsMaxSectionBytes {
+			value = value[len(value)-bootLogsMaxSectionBytes:]
+			if i := strings.IndexByte(value, '\n'); i >= 0 {
+				value = value[i+1:]
+			}
+		}
+		logs[key] = value
+	}
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if k, ok := strings.CutPrefix(strings.TrimRight(line, "\r\n"), bootLogsSectionMarker); ok && k != "" {
+			flush()
+			key = k
+			section.Reset()
+			continue
+		}
+		section.WriteString(line)
+	}
+	flush()
+	return logs
+}
+
+// bootLogsConfigMapName returns the name of the ConfigMap holding the boot logs of the machine
+func bootLogsConfigMapName(contaboMachine *infrastructurev1beta2.ContaboMachine) string {
+	return contaboMachine.Name + "-boot-logs"
+}
+
+// reconcileBootLogs collects the recent boot logs of the instance into a ConfigMap owned by the machine when
+// requested with the CollectBootLogsAnnotation, once per annotation value. Contabo exposes no console output API,
+// the logs are read over SSH so they are only retrievable while the instance is reachable, the failure is then
+// recorded in the status and an event. The collection is best effort and never blocks the reconciliation.
+func (r *ContaboMachineReconciler) reconcileBootLogs(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	request := contaboMachine.Annotations[infrastructurev1beta2.CollectBootLogsAnnotation]
+	if request == "" || contaboMachine.Status.Instance == nil {
+		return
+	}
+	if bootLogs := contaboMachine.Status.BootLogs; bootLogs != nil && bootLogs.Request == request {
+		return
+	}
+
+	fail := func(message string) {
+		log.Info("Failed to collect boot logs", "request", request, "reason", message)
+		contaboMachine.Status.BootLogs = &infrastructurev1beta2.ContaboMachineBootLogsStatus{
+			Request:        request,
+			CollectionTime: metav1.Now(),
+			Message:        message,
+		}
+		r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.BootLogsCollectionFailedReason, message)
+	}
+
+	if machineInstanceSshHost(contaboMachine) == "" {
+		fail("The instance has no IPv4 address yet, Contabo exposes no console output so the boot logs are only retrievable over SSH")
+		return
+	}
+	output, result, err := r.runMachineInstanceSshCommand(ctx, contaboMachine, contaboCluster, bootLogsCommand())
+	if err != nil || result.RequeueAfter > 0 {
+		message := "The instance is not reachable over SSH, Contabo exposes no console output so the boot logs are only retrievable over SSH"
+		if err != nil {
+			message = fmt.Sprintf("%s: %v", message, err)
+		}
+		fail(message)
+		return
+	}
+	logs := parseBootLogs(output)
+	if len(logs) == 0 {
+		fail("The boot logs command returned no output")
+		return
+	}
+
+	name := bootLogsConfigMapName(contaboMachine)
+	if err := r.writeBootLogsConfigMap(ctx, contaboMachine, name, request, logs); err != nil {
+		fail(err.Error())
+		return
+	}
+
+	log.Info("Collected boot logs", "request", request, "configMap", name)
+	contaboMachine.Status.BootLogs = &infrastructurev1beta2.ContaboMachineBootLogsStatus{
+		Request:        request,
+		ConfigMapName:  name,
+		CollectionTime: metav1.Now(),
+		Message:        fmt.Sprintf("Collected %d logs of instance %d", len(logs), contaboMachine.Status.Instance.InstanceId),
+	}
+	r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.BootLogsCollectedReason,
+		"Collected the boot logs of instance %d into ConfigMap %s", contaboMachine.Status.Instance.InstanceId, name)
+}
+
+// writeBootLogsConfigMap creates the boot logs ConfigMap of the machine, or replaces its logs
+func (r *ContaboMachineReconciler) writeBootLogsConfigMap(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, name string, request string, logs map[string]string) error {
+	existing := &corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: contaboMachine.Namespace, Name: name}, existing)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get boot logs ConfigMap %s: %w", name, err)
+		}
+		err = r.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: contaboMachine.Namespace,
+				Annotations: map[string]string{
+					infrastructurev1beta2.CollectBootLogsAnnotation: request,
+				},
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel: contaboMachine.Labels[clusterv1.ClusterNameLabel],
+					"component":                "boot-logs",
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: infrastructurev1beta2.GroupVersion.String(),
+						Kind:       "ContaboMachine",
+						Name:       contaboMachine.Name,
+						UID:        contaboMachine.UID,
+						Controller: ptr.To(true),
+					},
+				},
+			},
+			Data: logs,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create boot logs ConfigMap %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	existing.Annotations[infrastructurev1beta2.CollectBootLogsAnnotation] = request
+	existing.Data = logs
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update boot logs ConfigMap %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// reconcileCancelledInstance fails the ContaboMachine whose instance was cancelled or removed outside of Kubernetes,
+// e.g. in the Contabo panel, instead of retrying an instance which never comes back. The private network assignment,
+// the tags, the bootstrap data and the bootstrap token of the instance are cleaned up, the instance is released from
+// the machine and the Machine is marked for remediation so that its MachineSet replaces it. It returns true when the
+// machine was failed. Failures to retrieve the instance are left to the next steps of the reconciliation.
+func (r *ContaboMachineReconciler) reconcileCancelledInstance(ctx context.Context, machine *clusterv1.Machine, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, bool) {
+	log := logf.FromContext(ctx)
+
+	instance := contaboMachine.Status.Instance
+	if instance == nil {
+		return ctrl.Result{}, false
+	}
+	instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, instance.InstanceId, nil)
+	err = contabo.CheckResponse(instanceResp, err)
+
+	var message string
+	var current *models.InstanceResponse
+	switch {
+	case errors.Is(err, contabo.ErrNotFound):
+		message = fmt.Sprintf("Instance %d was removed from Contabo outside of Kubernetes", instance.InstanceId)
+	case err == nil && len(instanceResp.JSON200.Data) > 0 && instanceResp.JSON200.Data[0].CancelDate != nil:
+		current = &instanceResp.JSON200.Data[0]
+		message = fmt.Sprintf("Instance %d was cancelled outside of Kubernetes, it is removed on %s",
+			instance.InstanceId, current.CancelDate.Format(time.DateOnly))
+	default:
+		return ctrl.Result{}, false
+	}
+	log.Info(message, "instanceID", instance.InstanceId)
+
+	// The machine is only failed once its replacement is requested, the failure stops the reconciliation
+	if err := r.requestRemediation(ctx, machine); err != nil {
+		log.Info("Failed to request the remediation of the Machine", "error", err.Error())
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, true
+	}
+
+	// Best effort, the leftovers of the private network are removed by the periodic reconciliation of the cluster
+	if contaboCluster.Status.PrivateNetwork != nil {
+		if err := unassignPrivateNetwork(ctx, r.ContaboClient, contaboCluster.Status.PrivateNetwork.PrivateNetworkId, instance.InstanceId); err != nil {
+			log.Info("Failed to unassign the cancelled instance from the private network", "instanceID", instance.InstanceId, "error", err.Error())
+		}
+	}
+	r.deleteUserDataObject(ctx, contaboMachine)
+	r.deleteBootstrapToken(ctx, contaboMachine, contaboCluster)
+	if current != nil {
+		r.unassignTags(ctx, instance.InstanceId, contaboMachine.Status.Tags)
+		// Rename the instance until it is removed, so that the deletion of the machine does not find and reset it
+		displayName := Truncate(fmt.Sprintf("[capc] %d cancelled", instance.InstanceId), 255) // Contabo display name max length is 255 characters
+		if _, err := r.patchInstance(ctx, contaboMachine, instance.InstanceId, instance, models.PatchInstanceRequest{
+			DisplayName: &displayName,
+		}, "release the cancelled instance from the machine"); err != nil {
+			log.Info("Failed to rename the cancelled instance", "instanceID", instance.InstanceId, "error", err.Error())
+		}
+	}
+
+	contaboMachine.Status.Instance = nil
+	contaboMachine.Status.Tags = nil
+	contaboMachine.Status.Ready = false
+	contaboMachine.Status.Available = false
+	r.setMachineFailure(contaboMachine, infrastructurev1beta2.UpdateMachineError, infrastructurev1beta2.InstanceCancelledReason, message)
+	return ctrl.Result{}, true
+}
+
+// requestRemediation marks the Machine for remediation, its MachineHealthCheck then lets the owner of the Machine
+// replace it
+func (r *ContaboMachineReconciler) requestRemediation(ctx context.Context, machine *clusterv1.Machine) error {
+	if machine == nil {
+		return nil
+	}
+	if _, ok := machine.Annotations[clusterv1.RemediateMachineAnnotation]; ok {
+		return nil
+	}
+	original := machine.DeepCopy()
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+	machine.Annotations[clusterv1.RemediateMachineAnnotation] = ""
+	if err := r.Patch(ctx, machine, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to patch remediation annotation of Machine %s: %w", machine.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// newCatalogSnapshot builds the catalog snapshot of an instance installed with the image, the image metadata is
+// left empty when the image is unknown
+func newCatalogSnapshot(instance *infrastructurev1beta2.ContaboInstanceStatus, imageId string, image *models.ImageResponse, now time.Time) *infrastructurev1beta2.ContaboCatalogSnapshot {
+	snapshot := &infrastructurev1beta2.ContaboCatalogSnapshot{
+		RecordedAt:  metav1.NewTime(now),
+		InstanceId:  instance.InstanceId,
+		ProductId:   instance.ProductId,
+		ProductName: instance.ProductName,
+		ProductType: string(instance.ProductType),
+		PriceClass:  infrastructurev1beta2.ContaboProductPriceClass(infrastructurev1beta2.ContaboProductId(instance.ProductId)),
+		CpuCores:    instance.CpuCores,
+		RamMb:       int64(instance.RamMb),
+		DiskMb:      int64(instance.DiskMb),
+		Region:      instance.Region,
+		DataCenter:  instance.DataCenter,
+		ImageId:     imageId,
+	}
+	if image != nil {
+		snapshot.ImageName = image.Name
+		snapshot.ImageOsType = image.OsType
+		snapshot.ImageVersion = image.Version
+		snapshot.StandardImage = image.StandardImage
+		if !image.CreationDate.IsZero() {
+			snapshot.ImageCreationDate = &metav1.Time{Time: image.CreationDate}
+		}
+		if !image.LastModifiedDate.IsZero() {
+			snapshot.ImageLastModifiedDate = &metav1.Time{Time: image.LastModifiedDate}
+		}
+	}
+	return snapshot
+}
+
+// reconcileCatalogSnapshot records the product and image metadata of the instance once it is acquired. The snapshot
+// is never refreshed for the same instance, the image is the one the instance is bootstrapped with.
+func (r *ContaboMachineReconciler) reconcileCatalogSnapshot(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	log := logf.FromContext(ctx)
+
+	instance := contaboMachine.Status.Instance
+	if instance == nil {
+		return
+	}
+	if snapshot := contaboMachine.Status.CatalogSnapshot; snapshot != nil && snapshot.InstanceId == instance.InstanceId {
+		return
+	}
+
+	imageId := instanceImageId(contaboMachine)
+	imageResp, err := r.ContaboClient.RetrieveImageWithResponse(ctx, imageId, nil)
+	if err := contabo.CheckResponse(imageResp, err); err != nil || len(imageResp.JSON200.Data) == 0 {
+		// Retried on the next reconciliation, the snapshot is only useful with the image metadata
+		log.Info("Failed to retrieve the image metadata for the catalog snapshot", "imageID", imageId, "error", err)
+		return
+	}
+
+	contaboMachine.Status.CatalogSnapshot = newCatalogSnapshot(instance, imageId, &imageResp.JSON200.Data[0], time.Now())
+	log.Info("Recorded catalog snapshot",
+		"instanceID", instance.InstanceId,
+		"productID", instance.ProductId,
+		"priceClass", contaboMachine.Status.CatalogSnapshot.PriceClass,
+		"imageID", imageId)
+}
@@ -0,0 +1,587 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/readonly"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	chaosNamespace   = "default"
+	chaosClusterName = "chaos"
+	chaosClusterUUID = "00000000-0000-0000-0000-00000000c4a0"
+)
+
+// chaosEnvironment runs the ContaboMachine reconciler against a fake Contabo backend and a fake management cluster
+type chaosEnvironment struct {
+	backend          *fake.Backend
+	client           client.Client
+	reconciler       *ContaboMachineReconciler
+	privateNetworkId int64
+	workloadCluster  *httptest.Server
+}
+
+// newChaosEnvironment returns a ready ContaboCluster with its private network and SSH key in the fake backend. The
+// workload cluster has no node, so deleted machines release their instance right away.
+func newChaosEnvironment() *chaosEnvironment {
+	backend := fake.NewBackend()
+	backend.AddImage(models.ImageResponse{ImageId: DefaultUbuntuImageID, Name: "ubuntu-24.04", OsType: "Linux", StandardImage: true})
+	sshKeyId := backend.AddSecret("[capc] "+chaosClusterUUID, models.SecretResponseTypeSsh, "ssh-ed25519 AAAA")
+	privateNetworkId := backend.AddPrivateNetwork("[capc] "+chaosClusterUUID, "EU")
+	contaboClient, err := backend.NewClient()
+	Expect(err).NotTo(HaveOccurred())
+
+	workloadCluster := httptest.NewServer(http.NotFoundHandler())
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: chaos
+  cluster:
+    server: %s
+contexts:
+- name: chaos
+  context:
+    cluster: chaos
+    user: chaos
+current-context: chaos
+users:
+- name: chaos
+  user:
+    token: chaos
+`, workloadCluster.URL)
+
+	scheme := newScheme()
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: chaosClusterName, Namespace: chaosNamespace},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+				APIGroup: infrastructurev1beta2.GroupVersion.Group,
+				Kind:     "ContaboCluster",
+				Name:     chaosClusterName,
+			},
+		},
+	}
+	contaboCluster := &infrastructurev1beta2.ContaboCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: chaosClusterName, Namespace: chaosNamespace},
+		Spec: infrastructurev1beta2.ContaboClusterSpec{
+			ClusterUUID:    chaosClusterUUID,
+			PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{Region: "EU"},
+		},
+		Status: infrastructurev1beta2.ContaboClusterStatus{
+			Ready:          true,
+			PrivateNetwork: &infrastructurev1beta2.ContaboPrivateNetworkStatus{PrivateNetworkId: privateNetworkId, Region: "EU"},
+			SshKey:         &infrastructurev1beta2.ContaboSshKeyStatus{Name: "[capc] " + chaosClusterUUID, SecretId: sshKeyId},
+		},
+	}
+	kubeconfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: chaosClusterName + "-kubeconfig", Namespace: chaosNamespace},
+		Data:       map[string][]byte{"value": []byte(kubeconfig)},
+	}
+
+	k8sClient := crfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cluster, contaboCluster, kubeconfigSecret).
+		WithStatusSubresource(&infrastructurev1beta2.ContaboMachine{}, &infrastructurev1beta2.ContaboCluster{}).
+		Build()
+
+	return &chaosEnvironment{
+		backend: backend,
+		client:  k8sClient,
+		reconciler: &ContaboMachineReconciler{
+			Client:        k8sClient,
+			Scheme:        scheme,
+			Recorder:      record.NewFakeRecorder(100),
+			ContaboClient: contaboClient,
+		},
+		privateNetworkId: privateNetworkId,
+		workloadCluster:  workloadCluster,
+	}
+}
+
+// createMachine creates a worker Machine and its ContaboMachine, the Machine has no bootstrap data so the
+// reconciliation stops once the instance is provisioned. UIDs are set as the fake client leaves them empty.
+func (e *chaosEnvironment) createMachine(ctx context.Context, name string) types.NamespacedName {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: chaosNamespace,
+			UID:       types.UID("machine-" + name),
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: chaosClusterName},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: chaosClusterName,
+			InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+				APIGroup: infrastructurev1beta2.GroupVersion.Group,
+				Kind:     "ContaboMachine",
+				Name:     name,
+			},
+		},
+	}
+	Expect(e.client.Create(ctx, machine)).To(Succeed())
+
+	contaboMachine := &infrastructurev1beta2.ContaboMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: chaosNamespace,
+			UID:       types.UID("contabomachine-" + name),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Machine",
+				Name:       name,
+				UID:        machine.UID,
+			}},
+		},
+		Spec: infrastructurev1beta2.ContaboMachineSpec{
+			Instance: infrastructurev1beta2.ContaboInstanceSpec{
+				ProductId:        ptr.To(infrastructurev1beta2.ContaboProductId("V76")),
+				ProvisioningType: ptr.To(infrastructurev1beta2.ContaboInstanceProvisioningTypeReuseOrCreate),
+			},
+		},
+	}
+	Expect(e.client.Create(ctx, contaboMachine)).To(Succeed())
+	return client.ObjectKeyFromObject(contaboMachine)
+}
+
+// reconcile reconciles the ContaboMachines rounds times, errors are expected while faults are injected
+func (e *chaosEnvironment) reconcile(ctx context.Context, rounds int, keys ...types.NamespacedName) {
+	for range rounds {
+		for _, key := range keys {
+			_, _ = e.reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		}
+	}
+}
+
+// provisioned returns true once the instance of the ContaboMachine is running and assigned to the private network
+func (e *chaosEnvironment) provisioned(ctx context.Context, key types.NamespacedName) bool {
+	contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+	Expect(e.client.Get(ctx, key, contaboMachine)).To(Succeed())
+	Expect(contaboMachine.Status.FailureReason).To(BeNil(), "machine %s failed: %s", key.Name, ptr.Deref(contaboMachine.Status.FailureMessage, ""))
+	if contaboMachine.Status.Instance == nil || contaboMachine.Spec.ProviderID == nil ||
+		contaboMachine.Status.Initialization == nil || !contaboMachine.Status.Initialization.Provisioned ||
+		!meta.IsStatusConditionTrue(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceReadyCondition) {
+		return false
+	}
+	for _, instance := range e.backend.PrivateNetwork(e.privateNetworkId).Instances {
+		if instance.InstanceId == contaboMachine.Status.Instance.InstanceId {
+			return true
+		}
+	}
+	return false
+}
+
+// expectNoDuplicateInstances checks every display name is used by one active instance at most
+func (e *chaosEnvironment) expectNoDuplicateInstances() {
+	displayNames := map[string]int64{}
+	for _, instance := range e.backend.Instances() {
+		if instance.DisplayName == "" {
+			continue
+		}
+		Expect(displayNames).NotTo(HaveKey(instance.DisplayName), "instances %d and %d share the display name %q", displayNames[instance.DisplayName], instance.InstanceId, instance.DisplayName)
+		displayNames[instance.DisplayName] = instance.InstanceId
+	}
+}
+
+// roundTripperFunc sends the requests with a function, e.g. to the fake backend
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("ContaboMachine Controller under Contabo API faults", func() {
+	var (
+		ctx context.Context
+		env *chaosEnvironment
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		env = newChaosEnvironment()
+		DeferCleanup(env.workloadCluster.Close)
+	})
+
+	It("should not create duplicate instances when creations fail after the order is placed", func() {
+		keys := []types.NamespacedName{env.createMachine(ctx, "worker-a"), env.createMachine(ctx, "worker-b"), env.createMachine(ctx, "worker-c")}
+
+		env.backend.SetFaults(fake.Faults{PartialCreateFailures: 3, CreateFailures: 1, Latency: time.Millisecond})
+		env.reconcile(ctx, 5, keys...)
+		env.expectNoDuplicateInstances()
+
+		env.backend.SetFaults(fake.Faults{})
+		env.reconcile(ctx, 5, keys...)
+		for _, key := range keys {
+			Expect(env.provisioned(ctx, key)).To(BeTrue(), "machine %s is not provisioned", key.Name)
+		}
+		env.expectNoDuplicateInstances()
+		Expect(env.backend.Instances()).To(HaveLen(len(keys)))
+	})
+
+	It("should converge once a rate limiting storm is over", func() {
+		keys := []types.NamespacedName{env.createMachine(ctx, "worker-a"), env.createMachine(ctx, "worker-b")}
+
+		for storm := range 6 {
+			env.backend.SetFaults(fake.Faults{RateLimitedRequests: 2 + storm%3})
+			env.reconcile(ctx, 1, keys...)
+			env.expectNoDuplicateInstances()
+		}
+
+		env.backend.SetFaults(fake.Faults{})
+		env.reconcile(ctx, 5, keys...)
+		for _, key := range keys {
+			Expect(env.provisioned(ctx, key)).To(BeTrue(), "machine %s is not provisioned", key.Name)
+		}
+		Expect(env.backend.Instances()).To(HaveLen(len(keys)))
+	})
+
+	It("should release partially created instances and finalizers when deleted under faults", func() {
+		key := env.createMachine(ctx, "worker-a")
+
+		env.backend.SetFaults(fake.Faults{PartialCreateFailures: 1})
+		env.reconcile(ctx, 1, key)
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		Expect(contaboMachine.Status.Instance).To(BeNil())
+		Expect(contaboMachine.Finalizers).To(ContainElement(infrastructurev1beta2.MachineFinalizer))
+		Expect(env.backend.Instances()).To(HaveLen(1))
+
+		Expect(env.client.Delete(ctx, contaboMachine)).To(Succeed())
+		env.backend.SetFaults(fake.Faults{RateLimitedRequests: 3})
+		env.reconcile(ctx, 6, key)
+
+		err := env.client.Get(ctx, key, contaboMachine)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "ContaboMachine still exists with finalizers %v", contaboMachine.Finalizers)
+		instances := env.backend.Instances()
+		Expect(instances).To(HaveLen(1))
+		Expect(instances[0].DisplayName).To(BeEmpty(), "the instance was not released for reuse")
+		Expect(env.backend.PrivateNetwork(env.privateNetworkId).Instances).To(BeEmpty())
+	})
+
+	It("should reuse released instances instead of creating new ones", func() {
+		key := env.createMachine(ctx, "worker-a")
+		env.reconcile(ctx, 5, key)
+		Expect(env.provisioned(ctx, key)).To(BeTrue())
+
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		Expect(env.client.Delete(ctx, contaboMachine)).To(Succeed())
+		env.reconcile(ctx, 3, key)
+		Expect(apierrors.IsNotFound(env.client.Get(ctx, key, contaboMachine))).To(BeTrue())
+
+		env.backend.SetFaults(fake.Faults{RateLimitedRequests: 2, Latency: time.Millisecond})
+		key = env.createMachine(ctx, "worker-b")
+		env.reconcile(ctx, 3, key)
+		env.backend.SetFaults(fake.Faults{})
+		env.reconcile(ctx, 5, key)
+		Expect(env.provisioned(ctx, key)).To(BeTrue())
+		Expect(env.backend.Instances()).To(HaveLen(1))
+	})
+
+	It("should report the last operation with the request ID of the instance order", func() {
+		key := env.createMachine(ctx, "worker-a")
+		env.backend.SetFaults(fake.Faults{Errors: []fake.ErrorFault{{Method: http.MethodPost, PathPrefix: "/v1/compute/instances", StatusCode: http.StatusInternalServerError, Count: 10}}})
+		env.reconcile(ctx, 2, key)
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		failedOrder := contaboMachine.Status.LastOperation
+		Expect(failedOrder).NotTo(BeNil())
+		Expect(failedOrder.Type).To(Equal(infrastructurev1beta2.ContaboLastOperationTypeCreate))
+		Expect(failedOrder.State).To(Equal(infrastructurev1beta2.ContaboLastOperationStateProcessing))
+		Expect(failedOrder.Reason).To(Equal(infrastructurev1beta2.InstanceCreatingReason))
+		Expect(failedOrder.Message).To(ContainSubstring("status 500"))
+		Expect(uuid.Validate(failedOrder.RequestID)).To(Succeed())
+
+		By("Keeping the operation with the request ID of the accepted order")
+		env.backend.SetFaults(fake.Faults{})
+		env.reconcile(ctx, 5, key)
+		Expect(env.provisioned(ctx, key)).To(BeTrue())
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		operation := contaboMachine.Status.LastOperation
+		Expect(operation.Type).To(Equal(infrastructurev1beta2.ContaboLastOperationTypeCreate))
+		Expect(operation.StartedAt).To(Equal(failedOrder.StartedAt))
+		Expect(operation.FinishedAt).To(BeNil())
+		Expect(uuid.Validate(operation.RequestID)).To(Succeed())
+		Expect(operation.RequestID).NotTo(Equal(failedOrder.RequestID))
+
+		By("Starting the deletion")
+		Expect(env.client.Delete(ctx, contaboMachine)).To(Succeed())
+		env.backend.SetFaults(fake.Faults{RateLimitedRequests: 100})
+		env.reconcile(ctx, 1, key)
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		operation = contaboMachine.Status.LastOperation
+		Expect(operation.Type).To(Equal(infrastructurev1beta2.ContaboLastOperationTypeDelete))
+		Expect(operation.State).To(Equal(infrastructurev1beta2.ContaboLastOperationStateProcessing))
+		Expect(operation.Reason).To(Equal(infrastructurev1beta2.MachineDeletingReason))
+	})
+
+	It("should rename and release the legacy finalizers of the machines of former versions", func() {
+		keys := []types.NamespacedName{env.createMachine(ctx, "worker-a"), env.createMachine(ctx, "worker-b")}
+		env.reconcile(ctx, 5, keys...)
+		By("Setting the finalizers as a former version would have")
+		for i, key := range keys {
+			Expect(env.provisioned(ctx, key)).To(BeTrue())
+			contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+			Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+			contaboMachine.Finalizers = []string{infrastructurev1beta2.LegacyMachineFinalizer}
+			if i == 1 {
+				// Downgraded then upgraded again, both names are set
+				contaboMachine.Finalizers = append(contaboMachine.Finalizers, infrastructurev1beta2.MachineFinalizer)
+			}
+			Expect(env.client.Update(ctx, contaboMachine)).To(Succeed())
+		}
+
+		By("Renaming the legacy finalizers")
+		env.reconcile(ctx, 1, keys...)
+		for _, key := range keys {
+			contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+			Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+			Expect(contaboMachine.Finalizers).To(ConsistOf(infrastructurev1beta2.MachineFinalizer))
+		}
+
+		By("Releasing the machine deleted before being reconciled by the current version")
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+		Expect(env.client.Get(ctx, keys[0], contaboMachine)).To(Succeed())
+		contaboMachine.Finalizers = []string{infrastructurev1beta2.LegacyMachineFinalizer}
+		Expect(env.client.Update(ctx, contaboMachine)).To(Succeed())
+		Expect(env.client.Delete(ctx, contaboMachine)).To(Succeed())
+		env.reconcile(ctx, 3, keys[0])
+		err := env.client.Get(ctx, keys[0], contaboMachine)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "ContaboMachine still exists with finalizers %v", contaboMachine.Finalizers)
+		Expect(env.backend.Instances()[0].DisplayName).To(BeEmpty(), "the instance was not released for reuse")
+	})
+})
+
+var _ = Describe("ContaboMachine Controller in read-only mode", func() {
+	It("should only observe the instances and postpone the deletions", func() {
+		ctx := context.Background()
+		env := newChaosEnvironment()
+		DeferCleanup(env.workloadCluster.Close)
+		contaboClient := env.reconciler.ContaboClient
+		readOnlyClient, err := contaboclient.NewClientWithResponses(fake.Server, contaboclient.WithHTTPClient(&http.Client{
+			Transport: readonly.NewTransport(roundTripperFunc(env.backend.Do)),
+		}), contaboclient.WithRequestEditorFn(contabo.RequestEditor("")))
+		Expect(err).NotTo(HaveOccurred())
+		setReadOnly := func(readOnly bool) {
+			env.reconciler.ReadOnly = readOnly
+			env.reconciler.ContaboClient = contaboClient
+			if readOnly {
+				env.reconciler.ContaboClient = readOnlyClient
+			}
+		}
+
+		By("Not ordering the instance of a new machine")
+		setReadOnly(true)
+		key := env.createMachine(ctx, "worker-a")
+		env.reconcile(ctx, 3, key)
+		Expect(env.backend.Instances()).To(BeEmpty())
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		Expect(contaboMachine.Status.Instance).To(BeNil())
+		condition := meta.FindStatusCondition(contaboMachine.Status.Conditions, infrastructurev1beta2.ReadOnlyCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(infrastructurev1beta2.ReadOnlyModeReason))
+
+		By("Provisioning the machine once the read-only mode ends")
+		setReadOnly(false)
+		env.reconcile(ctx, 5, key)
+		Expect(env.provisioned(ctx, key)).To(BeTrue())
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		Expect(meta.FindStatusCondition(contaboMachine.Status.Conditions, infrastructurev1beta2.ReadOnlyCondition)).To(BeNil())
+
+		By("Reporting an instance in error without resetting it")
+		setReadOnly(true)
+		instance := env.backend.Instances()[0]
+		instance.Status = models.InstanceStatusError
+		env.backend.AddInstance(instance)
+		env.reconcile(ctx, 1, key)
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		Expect(contaboMachine.Status.Instance).NotTo(BeNil())
+		Expect(contaboMachine.Status.Instance.Status).To(Equal(infrastructurev1beta2.InstanceStatusError))
+		condition = meta.FindStatusCondition(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceReadyCondition)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(infrastructurev1beta2.InstanceFailedReason))
+		Expect(env.backend.Instances()[0].DisplayName).To(Equal(instance.DisplayName))
+
+		By("Postponing the deletion of the machine")
+		Expect(env.client.Delete(ctx, contaboMachine)).To(Succeed())
+		env.reconcile(ctx, 3, key)
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		Expect(contaboMachine.Finalizers).To(ContainElement(infrastructurev1beta2.MachineFinalizer))
+		Expect(env.backend.Instances()[0].DisplayName).To(Equal(instance.DisplayName))
+		Expect(env.backend.PrivateNetwork(env.privateNetworkId).Instances).To(HaveLen(1))
+
+		By("Releasing the instance once the read-only mode ends")
+		setReadOnly(false)
+		env.reconcile(ctx, 3, key)
+		Expect(apierrors.IsNotFound(env.client.Get(ctx, key, contaboMachine))).To(BeTrue())
+	})
+})
+
+var _ = Describe("ContaboMachine Controller with control plane gang provisioning", func() {
+	var (
+		ctx context.Context
+		env *chaosEnvironment
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		env = newChaosEnvironment()
+		DeferCleanup(env.workloadCluster.Close)
+
+		contaboCluster := &infrastructurev1beta2.ContaboCluster{}
+		Expect(env.client.Get(ctx, types.NamespacedName{Namespace: chaosNamespace, Name: chaosClusterName}, contaboCluster)).To(Succeed())
+		contaboCluster.Spec.ControlPlaneGang = &infrastructurev1beta2.ContaboControlPlaneGangSpec{Replicas: 3}
+		Expect(env.client.Update(ctx, contaboCluster)).To(Succeed())
+	})
+
+	// createControlPlaneMachine creates a control plane Machine and its ContaboMachine
+	createControlPlaneMachine := func(name string) types.NamespacedName {
+		key := env.createMachine(ctx, name)
+		for _, obj := range []client.Object{&clusterv1.Machine{}, &infrastructurev1beta2.ContaboMachine{}} {
+			Expect(env.client.Get(ctx, key, obj)).To(Succeed())
+			obj.SetLabels(map[string]string{clusterv1.ClusterNameLabel: chaosClusterName, clusterv1.MachineControlPlaneLabel: ""})
+			Expect(env.client.Update(ctx, obj)).To(Succeed())
+		}
+		return key
+	}
+
+	It("should order the instances of the quorum with the first control plane machine", func() {
+		first := createControlPlaneMachine("control-plane-a")
+		env.reconcile(ctx, 5, first)
+
+		displayNames := []string{}
+		for _, instance := range env.backend.Instances() {
+			displayNames = append(displayNames, instance.DisplayName)
+		}
+		Expect(displayNames).To(ConsistOf(
+			"[capc] "+chaosClusterUUID+" control-plane-0",
+			"[capc] "+chaosClusterUUID+" control-plane-1",
+			"[capc] "+chaosClusterUUID+" control-plane-2",
+		))
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+		Expect(env.client.Get(ctx, first, contaboMachine)).To(Succeed())
+		Expect(contaboMachine.Status.ControlPlaneGang).NotTo(BeNil())
+		Expect(contaboMachine.Status.ControlPlaneGang.Orders).To(HaveLen(2))
+		Expect(contaboMachine.Status.ControlPlaneGang.CompletionTime).NotTo(BeNil())
+		Expect(contaboMachine.Status.Ready).To(BeTrue())
+
+		By("Adopting the ordered instance with the next control plane machine")
+		second := createControlPlaneMachine("control-plane-b")
+		env.reconcile(ctx, 5, second)
+		Expect(env.client.Get(ctx, second, contaboMachine)).To(Succeed())
+		Expect(contaboMachine.Status.Instance).NotTo(BeNil())
+		Expect(contaboMachine.Status.Instance.DisplayName).To(Equal("[capc] " + chaosClusterUUID + " control-plane-1"))
+		Expect(env.backend.Instances()).To(HaveLen(3))
+		env.expectNoDuplicateInstances()
+	})
+})
+
+var _ = Describe("ContaboMachine Controller with instances cancelled outside of Kubernetes", func() {
+	var (
+		ctx context.Context
+		env *chaosEnvironment
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		env = newChaosEnvironment()
+		DeferCleanup(env.workloadCluster.Close)
+	})
+
+	// expectCancelled checks the ContaboMachine failed and released its instance, and its Machine is remediated
+	expectCancelled := func(key types.NamespacedName, instanceId int64) {
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+		Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+		Expect(contaboMachine.Status.FailureReason).To(Equal(ptr.To(infrastructurev1beta2.UpdateMachineError)))
+		Expect(contaboMachine.Status.Instance).To(BeNil())
+		Expect(meta.FindStatusCondition(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceReadyCondition).Reason).
+			To(Equal(infrastructurev1beta2.InstanceCancelledReason))
+		Expect(contaboMachine.Status.LastOperation.State).To(Equal(infrastructurev1beta2.ContaboLastOperationStateFailed))
+		Expect(contaboMachine.Status.LastOperation.Reason).To(Equal(infrastructurev1beta2.InstanceCancelledReason))
+		Expect(contaboMachine.Status.LastOperation.FinishedAt).NotTo(BeNil())
+		machine := &clusterv1.Machine{}
+		Expect(env.client.Get(ctx, key, machine)).To(Succeed())
+		Expect(machine.Annotations).To(HaveKey(clusterv1.RemediateMachineAnnotation))
+		for _, instance := range env.backend.PrivateNetwork(env.privateNetworkId).Instances {
+			Expect(instance.InstanceId).NotTo(Equal(instanceId), "the instance is still in the private network")
+		}
+	}
+
+	It("should fail the machines whose instance was cancelled or removed and release their finalizer", func() {
+		cancelled := env.createMachine(ctx, "worker-a")
+		removed := env.createMachine(ctx, "worker-b")
+		env.reconcile(ctx, 5, cancelled, removed)
+		Expect(env.provisioned(ctx, cancelled)).To(BeTrue())
+		Expect(env.provisioned(ctx, removed)).To(BeTrue())
+
+		contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+		Expect(env.client.Get(ctx, cancelled, contaboMachine)).To(Succeed())
+		cancelledId := contaboMachine.Status.Instance.InstanceId
+		resp, err := env.reconciler.ContaboClient.CancelInstanceWithResponse(ctx, cancelledId, contabo.NewParams[models.CancelInstanceParams](ctx), models.CancelInstanceRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode()).To(Equal(http.StatusCreated))
+		Expect(env.client.Get(ctx, removed, contaboMachine)).To(Succeed())
+		removedId := contaboMachine.Status.Instance.InstanceId
+		env.backend.RemoveInstance(removedId)
+
+		env.reconcile(ctx, 3, cancelled, removed)
+		expectCancelled(cancelled, cancelledId)
+		expectCancelled(removed, removedId)
+		instanceResp, err := env.reconciler.ContaboClient.RetrieveInstanceWithResponse(ctx, cancelledId, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instanceResp.JSON200.Data[0].DisplayName).To(Equal(fmt.Sprintf("[capc] %d cancelled", cancelledId)))
+
+		By("Deleting the failed machines without resetting the cancelled instance")
+		for _, key := range []types.NamespacedName{cancelled, removed} {
+			Expect(env.client.Get(ctx, key, contaboMachine)).To(Succeed())
+			Expect(env.client.Delete(ctx, contaboMachine)).To(Succeed())
+		}
+		env.reconcile(ctx, 2, cancelled, removed)
+		for _, key := range []types.NamespacedName{cancelled, removed} {
+			Expect(apierrors.IsNotFound(env.client.Get(ctx, key, contaboMachine))).To(BeTrue(), "ContaboMachine %s still exists", key.Name)
+		}
+		instanceResp, err = env.reconciler.ContaboClient.RetrieveInstanceWithResponse(ctx, cancelledId, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instanceResp.JSON200.Data[0].DisplayName).To(Equal(fmt.Sprintf("[capc] %d cancelled", cancelledId)))
+	})
+})
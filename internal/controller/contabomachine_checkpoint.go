@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// machineCheckpoint is the operation-tracking state of a ContaboMachine stored in the OperationCheckpointAnnotation
+type machineCheckpoint struct {
+	InstanceId              int64                                                `json:"instanceId,omitempty"`
+	InstanceOrder           *infrastructurev1beta2.ContaboInstanceOrderStatus    `json:"instanceOrder,omitempty"`
+	Migration               *infrastructurev1beta2.ContaboMachineMigrationStatus `json:"migration,omitempty"`
+	Patch                   *infrastructurev1beta2.ContaboMachinePatchStatus     `json:"patch,omitempty"`
+	FirstBootProbeStartTime *metav1.Time                                         `json:"firstBootProbeStartTime,omitempty"`
+}
+
+// checkpointOperations stores the operation-tracking state of the status in the OperationCheckpointAnnotation,
+// the annotation is removed when nothing is tracked
+func checkpointOperations(contaboMachine *infrastructurev1beta2.ContaboMachine) error {
+	checkpoint := machineCheckpoint{
+		InstanceOrder:           contaboMachine.Status.InstanceOrder,
+		Migration:               contaboMachine.Status.Migration,
+		Patch:                   contaboMachine.Status.Patch,
+		FirstBootProbeStartTime: contaboMachine.Status.FirstBootProbeStartTime,
+	}
+	if contaboMachine.Status.Instance != nil {
+		checkpoint.InstanceId = contaboMachine.Status.Instance.InstanceId
+	}
+
+	if checkpoint == (machineCheckpoint{}) {
+		delete(contaboMachine.Annotations, infrastructurev1beta2.OperationCheckpointAnnotation)
+		return nil
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode operation checkpoint: %w", err)
+	}
+	if contaboMachine.Annotations == nil {
+		contaboMachine.Annotations = map[string]string{}
+	}
+	contaboMachine.Annotations[infrastructurev1beta2.OperationCheckpointAnnotation] = string(data)
+	return nil
+}
+
+// restoreOperations rebuilds the operation-tracking state of an empty status from the OperationCheckpointAnnotation,
+// after a clusterctl move. It returns true when the status was restored.
+func (r *ContaboMachineReconciler) restoreOperations(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	data, ok := contaboMachine.Annotations[infrastructurev1beta2.OperationCheckpointAnnotation]
+	if !ok {
+		return false, nil
+	}
+	status := &contaboMachine.Status
+	if status.Instance != nil || status.InstanceOrder != nil || status.Migration != nil || status.Patch != nil {
+		return false, nil
+	}
+
+	checkpoint := machineCheckpoint{}
+	if err := json.Unmarshal([]byte(data), &checkpoint); err != nil {
+		return false, fmt.Errorf("failed to decode operation checkpoint: %w", err)
+	}
+	status.InstanceOrder = checkpoint.InstanceOrder
+	status.Migration = checkpoint.Migration
+	status.Patch = checkpoint.Patch
+	status.FirstBootProbeStartTime = checkpoint.FirstBootProbeStartTime
+
+	// The instance is retrieved again, the instance of a pending order is retrieved by reconcileInstanceOrder
+	if checkpoint.InstanceId != 0 && status.InstanceOrder == nil {
+		instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, checkpoint.InstanceId, nil)
+		if err == nil && instanceResp.JSON200 != nil && len(instanceResp.JSON200.Data) > 0 {
+			status.Instance = convertInstanceResponseData(&instanceResp.JSON200.Data[0])
+		} else {
+			// The instance is looked up by display name instead
+			log.Info("Failed to retrieve checkpointed instance", "instanceID", checkpoint.InstanceId, "error", err)
+		}
+	}
+
+	log.Info("Restored in-flight operations from checkpoint",
+		"instanceID", checkpoint.InstanceId,
+		"instanceOrder", checkpoint.InstanceOrder != nil,
+		"migration", checkpoint.Migration != nil,
+		"patch", checkpoint.Patch != nil)
+	return true, nil
+}
+
+// persistOperationCheckpoint patches the OperationCheckpointAnnotation immediately, so that an operation started
+// with the Contabo API is not lost if the ContaboMachine is moved before the end of the reconciliation
+func (r *ContaboMachineReconciler) persistOperationCheckpoint(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) error {
+	original := contaboMachine.DeepCopy()
+	if err := checkpointOperations(contaboMachine); err != nil {
+		return err
+	}
+	return r.Patch(ctx, contaboMachine, client.MergeFrom(original))
+}
+
+// pausedForMove re-reads the ContaboMachine and its Cluster right before a Contabo resource is ordered, clusterctl
+// move pauses the cluster and may copy the ContaboMachine while the reconciliation is in progress
+func (r *ContaboMachineReconciler) pausedForMove(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) (bool, error) {
+	latest := &infrastructurev1beta2.ContaboMachine{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(contaboMachine), latest); err != nil {
+		return false, err
+	}
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, latest.ObjectMeta)
+	if err != nil {
+		return false, err
+	}
+	return annotations.IsPaused(cluster, latest), nil
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// ManagerTraceIdPrefix prefixes the x-trace-id of the Contabo API requests of every installation of the provider
+const ManagerTraceIdPrefix = "capc-"
+
+// ManagerTraceId returns the x-trace-id of the Contabo API requests of an installation of the provider. It is derived
+// from the leader election namespace and ID so that the replicas and restarts of an installation share it, while
+// duplicate installations get their own.
+func ManagerTraceId(namespace string, leaderElectionID string) string {
+	sum := sha256.Sum256([]byte(namespace + "/" + leaderElectionID))
+	return ManagerTraceIdPrefix + hex.EncodeToString(sum[:8])
+}
+
+// concurrentManagerEntries returns the instance audit entries made by other installations of the provider since
+// this one last took over the instance. The entries older than the oldest entry of this installation were made by a
+// previous holder of the instance, all the entries are kept when this installation made none of the listed ones.
+func concurrentManagerEntries(entries []infrastructurev1beta2.ContaboAuditEntry, traceId string) []infrastructurev1beta2.ContaboAuditEntry {
+	var since *metav1.Time
+	for i := range entries {
+		if entries[i].TraceId == traceId && (since == nil || entries[i].Timestamp.Before(since)) {
+			since = &entries[i].Timestamp
+		}
+	}
+
+	concurrent := []infrastructurev1beta2.ContaboAuditEntry{}
+	for _, entry := range entries {
+		if entry.Resource != "Instance" || entry.TraceId == traceId || !strings.HasPrefix(entry.TraceId, ManagerTraceIdPrefix) {
+			continue
+		}
+		if since != nil && entry.Timestamp.Before(since) {
+			continue
+		}
+		concurrent = append(concurrent, entry)
+	}
+	return concurrent
+}
+
+// reconcileConcurrentManagers warns when another installation of the provider, e.g. a duplicate install with another
+// leader election ID or namespace, changed the instance according to its audit entries, before both installations
+// fight over it. Changes made outside of the provider, e.g. in the Contabo panel, are not reported.
+func (r *ContaboMachineReconciler) reconcileConcurrentManagers(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, entries []infrastructurev1beta2.ContaboAuditEntry) {
+	log := logf.FromContext(ctx)
+
+	if r.ManagerTraceId == "" {
+		return
+	}
+
+	concurrent := concurrentManagerEntries(entries, r.ManagerTraceId)
+	if len(concurrent) == 0 {
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceManagedExclusivelyCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  infrastructurev1beta2.InstanceManagedExclusivelyReason,
+			Message: "No other installation of the provider changed the instance",
+		})
+		return
+	}
+
+	managers := map[string][]string{}
+	for _, entry := range concurrent {
+		managers[entry.TraceId] = append(managers[entry.TraceId], fmt.Sprintf("%s by %s", entry.Action, entry.ChangedBy))
+	}
+	traceIds := make([]string, 0, len(managers))
+	for traceId := range managers {
+		traceIds = append(traceIds, traceId)
+	}
+	sort.Strings(traceIds)
+	details := make([]string, 0, len(traceIds))
+	for _, traceId := range traceIds {
+		details = append(details, fmt.Sprintf("%s (%s)", traceId, strings.Join(managers[traceId], ", ")))
+	}
+	message := fmt.Sprintf("Instance %d was changed by other installations of the provider: %s, this installation is %s. "+
+		"Check for duplicate installs with another leader election ID or namespace managing the same Contabo account",
+		contaboMachine.Status.Instance.InstanceId, strings.Join(details, "; "), r.ManagerTraceId)
+
+	if !meta.IsStatusConditionFalse(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceManagedExclusivelyCondition) {
+		log.Info("Concurrent installation of the provider detected", "instanceID", contaboMachine.Status.Instance.InstanceId, "traceIDs", traceIds)
+		r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.ConcurrentManagerDetectedReason, message)
+	}
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceManagedExclusivelyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.ConcurrentManagerDetectedReason,
+		Message: message,
+	})
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// machineReadyConditionTypes are the conditions summarized by the Ready condition of the ContaboMachine, in the order
+// their messages are reported
+var machineReadyConditionTypes = []string{
+	infrastructurev1beta2.InstanceReadyCondition,
+	infrastructurev1beta2.InstanceBootstrapCondition,
+	infrastructurev1beta2.MachineSshKeyReadyCondition,
+	infrastructurev1beta2.InstanceFirstBootProbeCondition,
+	infrastructurev1beta2.InstancePowerStateCondition,
+}
+
+// setMachineReadyCondition sets the Ready condition of the ContaboMachine, the summary of the conditions of its
+// instance which the Machine controller mirrors into the InfrastructureReady condition of the Machine. The conditions
+// only reported by some machines are ignored while missing.
+func setMachineReadyCondition(contaboMachine *infrastructurev1beta2.ContaboMachine) error {
+	if !contaboMachine.DeletionTimestamp.IsZero() {
+		conditions.Set(contaboMachine, metav1.Condition{
+			Type:    infrastructurev1beta2.MachineReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.MachineDeletingReason,
+			Message: "Releasing the Contabo instance",
+		})
+		return nil
+	}
+	return conditions.SetSummaryCondition(contaboMachine, contaboMachine, infrastructurev1beta2.MachineReadyCondition,
+		conditions.ForConditionTypes(machineReadyConditionTypes),
+		conditions.IgnoreTypesIfMissing{
+			infrastructurev1beta2.MachineSshKeyReadyCondition,
+			infrastructurev1beta2.InstanceFirstBootProbeCondition,
+			infrastructurev1beta2.InstancePowerStateCondition,
+		},
+		conditions.CustomMergeStrategy{
+			MergeStrategy: conditions.DefaultMergeStrategy(
+				conditions.ComputeReasonFunc(conditions.GetDefaultComputeMergeReasonFunc(
+					infrastructurev1beta2.MachineNotReadyReason,
+					infrastructurev1beta2.MachineReadyUnknownReason,
+					infrastructurev1beta2.MachineReadyReason,
+				)),
+			),
+		},
+	)
+}
+
+// setMachineFailure marks the ContaboMachine as failed on a terminal provider error: the failureReason is one of the
+// machine status errors of Cluster API so that the owner of the Machine and its MachineHealthCheck can tell why the
+// machine is replaced, while the InstanceReady condition and the event keep the detailed reason. The machine is no
+// longer reconciled until it is deleted.
+func (r *ContaboMachineReconciler) setMachineFailure(contaboMachine *infrastructurev1beta2.ContaboMachine, failureReason, reason, message string) {
+	contaboMachine.Status.FailureReason = ptr.To(failureReason)
+	contaboMachine.Status.FailureMessage = ptr.To(message)
+	conditions.Set(contaboMachine, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: Truncate(message, 1024),
+	})
+	r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, reason, message)
+}
@@ -41,14 +41,22 @@ import (
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/deprecation"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/version"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
 	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
 	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -61,32 +69,97 @@ var controlplaneCloudConfig string
 //go:embed templates/worker.cloud-config.yaml
 var workerCloudConfig string
 
+//go:embed templates/base.cloud-config.yaml
+var baseCloudConfig string
+
 // ContaboMachineReconciler reconciles a ContaboMachine object
 type ContaboMachineReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
 	Recorder      record.EventRecorder
 	ContaboClient *contaboclient.ClientWithResponses
+	// Settings holds the runtime tunables from ContaboProviderSettings
+	Settings *ProviderSettings
+	// Jobs runs the long-running operations of the machines, such as snapshots, outside of the reconciliation
+	Jobs *JobQueue
+	// BootTimeProfiles holds the provisioning times observed per product and data center, adapting the timeouts
+	BootTimeProfiles *BootTimeProfiles
+	// ManagerNodeName is the node running the controller manager, its instance is never reset when self-hosted
+	ManagerNodeName string
+	// ManagerTraceId is the x-trace-id of the Contabo API requests of this installation, see ManagerTraceId
+	ManagerTraceId string
+	// ReadOnly only observes the instances, set with --read-only to freeze the provider during incidents
+	ReadOnly bool
+	// Credentials authorizes the Contabo API requests of the clusters referencing their own credentials
+	Credentials *ClusterCredentials
+	// APICalls records the Contabo API requests of each reconciliation and reports the hot loops
+	APICalls *APICallMetrics
+	// PrivateNetworkAssignWorkers is the number of private network assignments sent at once, one per private network
+	PrivateNetworkAssignWorkers int
 	// instanceReuseMutex protects against concurrent instance reuse
 	instanceReuseMutex sync.Mutex
 	// indexAssignmentMutex protects against concurrent index assignment
 	indexAssignmentMutex sync.Mutex
+	// operationSlots limits the instance operations running at once per ContaboCluster
+	operationSlots operationSlots
+
+	// quotaReservations holds the instances granted by the ContaboQuotas until they show up in the machine status
+	quotaReservations quotaReservations
+	// instanceCreationBackPressure slows down the instance creations of the Contabo accounts failing at a high rate
+	instanceCreationBackPressure instanceCreationBackPressure
+	// privateNetworkAssignments serializes the private network assignments per private network
+	privateNetworkAssignments privateNetworkAssignments
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachines/finalizers,verbs=update
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabopatchschedules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabolifecyclehooks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachinetemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=create;update;delete;get;list;watch
-// (Node cordon/drain is handled by Cluster API; controller does not perform node eviction)
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+// (Node cordon/drain is handled by Cluster API; controller only evicts pods for the in-place rollouts, in the workload cluster)
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ContaboMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The jobs are run by the elected manager next to the controller
+	if r.Jobs != nil {
+		if err := mgr.Add(r.Jobs); err != nil {
+			return err
+		}
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&infrastructurev1beta2.ContaboMachine{}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
 		WithEventFilter(predicates.ResourceNotPaused(mgr.GetScheme(), ctrl.LoggerFrom(context.TODO()))).
+		// Requeue the machines waiting on the cluster infrastructure as soon as it transitions instead of polling
+		Watches(
+			&infrastructurev1beta2.ContaboCluster{},
+			handler.EnqueueRequestsFromMapFunc(r.contaboClusterToContaboMachines),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					oldCluster, okOld := e.ObjectOld.(*infrastructurev1beta2.ContaboCluster)
+					newCluster, okNew := e.ObjectNew.(*infrastructurev1beta2.ContaboCluster)
+					return okOld && okNew && (contaboClusterInfrastructureTransitioned(oldCluster, newCluster) ||
+						oldCluster.Annotations[infrastructurev1beta2.RefreshAnnotation] != newCluster.Annotations[infrastructurev1beta2.RefreshAnnotation])
+				},
+			}),
+		).
+		// Roll the in-place updates of the templates out to their machines
+		Watches(
+			&infrastructurev1beta2.ContaboMachineTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.contaboMachineTemplateToContaboMachines),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		// Uncomment to reconcile based on Machine, currently this is not what we need
 		// Watches(
 		// 	&clusterv1.Machine{},
@@ -96,6 +169,71 @@ func (r *ContaboMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
+// contaboMachineTemplateToContaboMachines maps a ContaboMachineTemplate to the ContaboMachines cloned from it
+func (r *ContaboMachineReconciler) contaboMachineTemplateToContaboMachines(ctx context.Context, obj client.Object) []ctrl.Request {
+	contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachines, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ContaboMachines of the ContaboMachineTemplate", "template", obj.GetName())
+		return nil
+	}
+	requests := []ctrl.Request{}
+	for _, contaboMachine := range contaboMachines.Items {
+		if contaboMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation] == obj.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&contaboMachine)})
+		}
+	}
+	return requests
+}
+
+// contaboClusterInfrastructureTransitioned returns true when the ContaboCluster changed in a way machines wait on:
+// readiness, private network, SSH key or control plane endpoint
+func contaboClusterInfrastructureTransitioned(oldCluster *infrastructurev1beta2.ContaboCluster, newCluster *infrastructurev1beta2.ContaboCluster) bool {
+	conditionStatus := func(contaboCluster *infrastructurev1beta2.ContaboCluster, conditionType string) metav1.ConditionStatus {
+		if condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, conditionType); condition != nil {
+			return condition.Status
+		}
+		return metav1.ConditionUnknown
+	}
+	return oldCluster.Status.Ready != newCluster.Status.Ready ||
+		(oldCluster.Status.PrivateNetwork == nil) != (newCluster.Status.PrivateNetwork == nil) ||
+		(oldCluster.Status.SshKey == nil) != (newCluster.Status.SshKey == nil) ||
+		conditionStatus(oldCluster, infrastructurev1beta2.ClusterPrivateNetworkReadyCondition) != conditionStatus(newCluster, infrastructurev1beta2.ClusterPrivateNetworkReadyCondition) ||
+		conditionStatus(oldCluster, infrastructurev1beta2.ClusterSshKeyReadyCondition) != conditionStatus(newCluster, infrastructurev1beta2.ClusterSshKeyReadyCondition) ||
+		oldCluster.Spec.ControlPlaneEndpoint != newCluster.Spec.ControlPlaneEndpoint
+}
+
+// contaboClusterToContaboMachines maps a ContaboCluster to the ContaboMachines of its cluster which are not ready yet,
+// or which have not applied the refresh requested on the ContaboCluster yet
+func (r *ContaboMachineReconciler) contaboClusterToContaboMachines(ctx context.Context, obj client.Object) []ctrl.Request {
+	log := logf.FromContext(ctx)
+
+	clusterName, ok := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if !ok {
+		for _, ownerReference := range obj.GetOwnerReferences() {
+			if ownerReference.Kind == "Cluster" {
+				clusterName = ownerReference.Name
+			}
+		}
+	}
+	if clusterName == "" {
+		return nil
+	}
+
+	contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachines, client.InNamespace(obj.GetNamespace()), client.MatchingLabels{clusterv1.ClusterNameLabel: clusterName}); err != nil {
+		log.Error(err, "Failed to list ContaboMachines of the ContaboCluster", "contaboCluster", obj.GetName())
+		return nil
+	}
+	requests := []ctrl.Request{}
+	for _, contaboMachine := range contaboMachines.Items {
+		if contaboMachine.Status.Ready && refreshRequested(&contaboMachine, obj) == "" {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&contaboMachine)})
+	}
+	return requests
+}
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *ContaboMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -106,9 +244,19 @@ func (r *ContaboMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// Fetch the ContaboMachine instance
 	contaboMachine := &infrastructurev1beta2.ContaboMachine{}
 	if err := r.Get(ctx, req.NamespacedName, contaboMachine); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.APICalls.forget("contabomachine", req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Count the Contabo API requests of the reconciliation to report the hot loops
+	ctx = contextutil.WithAPICallCounter(ctx)
+	defer r.APICalls.Record(ctx, "contabomachine", contaboMachine, r.Recorder)
+
+	// Record the request ID of the Contabo API requests changing the instance for the last operation
+	ctx = contextutil.WithChangeRecorder(ctx)
+
 	// Fetch the Machine
 	machine, err := util.GetOwnerMachine(ctx, r.Client, contaboMachine.ObjectMeta)
 	if err != nil {
@@ -135,11 +283,15 @@ func (r *ContaboMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	if annotations.IsPaused(cluster, contaboMachine) {
 		log.Info("ContaboMachine or linked Cluster is marked as paused. Won't reconcile")
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
 	}
 
 	log = log.WithValues("cluster", cluster.Name)
 
+	// Queue the Contabo API requests with the other requests of the cluster and label them with the machine
+	ctx = contextutil.WithCluster(ctx, client.ObjectKeyFromObject(cluster).String())
+	ctx = contextutil.WithMachine(ctx, client.ObjectKeyFromObject(contaboMachine).String())
+
 	contaboCluster := &infrastructurev1beta2.ContaboCluster{}
 	contaboClusterName := client.ObjectKey{
 		Namespace: contaboMachine.Namespace,
@@ -150,26 +302,50 @@ func (r *ContaboMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
+	// Manage the instance in the Contabo account of the cluster, the ContaboCluster reports the missing credentials
+	ctx, err = r.Credentials.IntoContext(ctx, contaboCluster)
+	if err != nil && !contaboMachine.DeletionTimestamp.IsZero() && r.Credentials.secretMissing(ctx, contaboCluster) {
+		// The instance cannot be released without the credentials, it is left in the account instead of holding the
+		// deletion forever
+		log.Error(err, "Credentials Secret of the cluster is missing, leaving the instance of the deleted machine in the account")
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.CredentialsUnavailableReason,
+			"Credentials Secret %s is missing, the instance of the machine is left in the account", contaboCluster.Spec.CredentialsRef.Name)
+		patchHelper, err := patch.NewHelper(contaboMachine, r.Client)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		removeFinalizer(contaboMachine, infrastructurev1beta2.MachineFinalizer)
+		if patchErr := patchHelper.Patch(ctx, contaboMachine); patchErr != nil && !apierrors.IsNotFound(patchErr) {
+			log.Error(patchErr, "Failed to patch ContaboMachine", "machine", contaboMachine.Name)
+			return ctrl.Result{}, patchErr
+		}
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		log.Info("Waiting for the Contabo credentials of the cluster", "error", err.Error())
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
 	// Wait for ContaboCluster to be ready before proceeding
 	if !contaboCluster.Status.Ready {
 		log.Info("Waiting for ContaboCluster to be ready",
 			"cluster", contaboCluster.Name,
 			"ready", contaboCluster.Status.Ready)
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
 	}
 
 	// Ensure Private Network is available before proceeding with machine provisioning
 	if contaboCluster.Status.PrivateNetwork == nil {
 		log.Info("Waiting for ContaboCluster Private Network to be configured",
 			"cluster", contaboCluster.Name)
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
 	}
 
 	// Ensure SSH key is available before proceeding with machine provisioning
 	if contaboCluster.Status.SshKey == nil {
 		log.Info("Waiting for ContaboCluster SSH key to be configured",
 			"cluster", contaboCluster.Name)
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
 	}
 
 	log.V(1).Info("ContaboCluster is ready, proceeding with machine reconciliation",
@@ -183,18 +359,83 @@ func (r *ContaboMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
+	// Record the version of the controller reconciling the resource
+	version.Stamp(contaboMachine)
+
+	// Rename the finalizer set by the former versions
+	migrateFinalizer(contaboMachine, infrastructurev1beta2.MachineFinalizer)
+
+	// Annotate the resource while it still uses deprecated fields, e.g. created while the webhook was not running
+	if err := deprecation.Mark(deprecation.Fields, "ContaboMachine", contaboMachine); err != nil {
+		log.Error(err, "Failed to check the deprecated fields")
+	}
+
+	// Rebuild the in-flight operations after a clusterctl move, the status is not moved
+	if _, err := r.restoreOperations(ctx, contaboMachine); err != nil {
+		log.Error(err, "Failed to restore in-flight operations from checkpoint")
+	}
+
+	// Only observe the instance while the provider is frozen, the operations resume once the read-only mode ends
+	if r.ReadOnly {
+		result := r.reconcileReadOnly(ctx, contaboMachine)
+		if contaboMachine.Status.Instance != nil {
+			r.reconcileAuditTrail(ctx, contaboMachine)
+			r.reconcileHost(ctx, contaboMachine)
+		}
+		if err := setMachineReadyCondition(contaboMachine); err != nil {
+			log.Error(err, "Failed to summarize the Ready condition")
+		}
+		if patchErr := patchHelper.Patch(ctx, contaboMachine); patchErr != nil && !apierrors.IsNotFound(patchErr) {
+			if apierrors.IsConflict(patchErr) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			log.Error(patchErr, "Failed to patch ContaboMachine", "machine", contaboMachine.Name)
+			return ctrl.Result{}, patchErr
+		}
+		return result, nil
+	}
+	setReadOnlyCondition(&contaboMachine.Status.Conditions, contaboMachine.Generation, false, "")
+
 	// Handle deleted machines
 	if !contaboMachine.DeletionTimestamp.IsZero() {
 		result := r.reconcileDelete(ctx, contaboMachine, contaboCluster)
+		if err := setMachineReadyCondition(contaboMachine); err != nil {
+			log.Error(err, "Failed to summarize the Ready condition")
+		}
+		setMachineLastOperation(ctx, contaboMachine, nil)
 		// Patch to update status and remove finalizer
 		// Note: This may fail if finalizer was already removed, which is fine
 		_ = patchHelper.Patch(ctx, contaboMachine)
 		return result, nil
 	}
 
+	// Bypass the refresh intervals when a refresh of the cluster machines is requested
+	r.applyRefresh(ctx, contaboMachine, contaboCluster)
+
 	// Handle non-deleted machines
 	result, err := r.reconcileNormal(ctx, machine, contaboMachine, contaboCluster)
 
+	// Surface provider-side history of the instance, refreshed periodically
+	if contaboMachine.Status.Instance != nil {
+		r.reconcileAuditTrail(ctx, contaboMachine)
+		r.reconcileHost(ctx, contaboMachine)
+		r.reconcileBootLogs(ctx, contaboMachine, contaboCluster)
+		if err == nil && result.IsZero() {
+			result = ctrl.Result{RequeueAfter: min(r.Settings.AuditTrailInterval(), r.Settings.HostInterval())}
+		}
+	}
+
+	// Checkpoint the in-flight operations in the metadata, moved by clusterctl
+	if err := checkpointOperations(contaboMachine); err != nil {
+		log.Error(err, "Failed to checkpoint in-flight operations")
+	}
+
+	// Summarize the conditions of the instance for the Machine
+	if err := setMachineReadyCondition(contaboMachine); err != nil {
+		log.Error(err, "Failed to summarize the Ready condition")
+	}
+	setMachineLastOperation(ctx, contaboMachine, err)
+
 	// Patch at the end
 	if patchErr := patchHelper.Patch(ctx, contaboMachine); patchErr != nil {
 		if apierrors.IsConflict(patchErr) {
@@ -221,6 +462,40 @@ func (r *ContaboMachineReconciler) reconcileNormal(ctx context.Context, machine
 		return ctrl.Result{}, nil
 	}
 
+	// Fail the machine whose instance was cancelled or removed outside of Kubernetes, so that it is replaced
+	if result, handled := r.reconcileCancelledInstance(ctx, machine, contaboMachine, contaboCluster); handled {
+		return result, nil
+	}
+
+	// Migrate the instance to another data center when requested
+	if result, handled, err := r.reconcileMigration(ctx, contaboMachine, contaboCluster); handled || err != nil {
+		return result, err
+	}
+
+	// Patch the instance OS when requested by a ContaboPatchSchedule
+	if result, handled, err := r.reconcilePatch(ctx, contaboMachine, contaboCluster); handled || err != nil {
+		return result, err
+	}
+
+	// Reinstall the instance with the in-place updates of its template
+	if result, handled, err := r.reconcileRollout(ctx, contaboMachine, contaboCluster); handled || err != nil {
+		return result, err
+	}
+
+	// Start or stop the instance to match the power state of the spec
+	if result, handled, err := r.reconcilePowerState(ctx, machine, contaboMachine, contaboCluster); handled || err != nil {
+		return result, err
+	}
+
+	// Assign the tags of the spec to the instance
+	r.reconcileTags(ctx, contaboMachine, contaboCluster)
+
+	// Resolve the failure domain of the instance order and record where the instance landed
+	r.reconcilePlacement(ctx, machine, contaboMachine, contaboCluster)
+
+	// Repair the provider ID of the machine and of its node after the instance was replaced
+	r.reconcileProviderID(ctx, machine, contaboMachine, contaboCluster)
+
 	// Check if machine is already fully ready - stop reconciliation to prevent infinite loops
 	if contaboMachine.Status.Ready &&
 		contaboMachine.Status.Available &&
@@ -253,6 +528,16 @@ func (r *ContaboMachineReconciler) reconcileNormal(ctx context.Context, machine
 		Reason: infrastructurev1beta2.InstanceProvisioningReason,
 	})
 
+	// Hold the acquisition of the instance while the pre-create hooks of the machine run
+	if contaboMachine.Status.Instance == nil && contaboMachine.Status.InstanceOrder == nil {
+		if result, held := r.reconcileLifecycleHooks(ctx, contaboMachine, infrastructurev1beta2.ContaboLifecycleHookPointPreCreate); held {
+			return result, nil
+		}
+	}
+
+	// Order the instances of the other control plane machines of a new cluster along with this one
+	r.orderControlPlaneGang(ctx, machine, contaboMachine, contaboCluster)
+
 	// Provision the instance for CAPI
 	result, err := r.provisionInstance(ctx, contaboMachine, contaboCluster)
 	if err != nil {
@@ -276,6 +561,17 @@ func (r *ContaboMachineReconciler) reconcileNormal(ctx context.Context, machine
 	// Set provider ID for CAPI
 	contaboMachine.Spec.ProviderID = ptr.To(BuildProviderID(contaboMachine.Status.Instance.Name))
 
+	// Name the node the instance is bootstrapped as
+	if err := reconcileNodeName(contaboMachine, contaboCluster); err != nil {
+		return ctrl.Result{}, r.handleError(
+			ctx,
+			contaboMachine,
+			err,
+			infrastructurev1beta2.InstanceHostnameInvalidReason,
+			"Failed to render the hostname of the instance",
+		)
+	}
+
 	// Update machine address for CAPI machine
 	if len(contaboMachine.Status.Addresses) == 0 {
 		if err := r.reconcileContaboMachineAddresses(ctx, contaboMachine, contaboCluster); err != nil {
@@ -283,6 +579,11 @@ func (r *ContaboMachineReconciler) reconcileNormal(ctx context.Context, machine
 		}
 	}
 
+	// Wait for the instances of the control plane quorum before bootstrapping the first control plane machine
+	if result, waiting := r.waitForControlPlaneGang(ctx, contaboMachine, contaboCluster); waiting {
+		return result, nil
+	}
+
 	// Set ContaboMachine ready for bootstrap data to be available by CABPK
 	contaboMachine.Status.Ready = true
 
@@ -296,6 +597,11 @@ func (r *ContaboMachineReconciler) reconcileNormal(ctx context.Context, machine
 		return result, nil
 	}
 
+	// Hold the availability of the machine while its post-provision hooks run
+	if result, held := r.reconcileLifecycleHooks(ctx, contaboMachine, infrastructurev1beta2.ContaboLifecycleHookPointPostProvision); held {
+		return result, nil
+	}
+
 	contaboMachine.Status.Available = true
 
 	// Update ContaboMachine status with instance details
@@ -351,7 +657,7 @@ func (r *ContaboMachineReconciler) setupContaboMachine(ctx context.Context, mach
 			Status: metav1.ConditionFalse,
 			Reason: infrastructurev1beta2.InstanceWaitingForPrivateNetworksReason,
 		})
-		return ctrl.Result{RequeueAfter: 15 * time.Second}
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}
 	}
 	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
 		Type:   infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
@@ -367,7 +673,7 @@ func (r *ContaboMachineReconciler) setupContaboMachine(ctx context.Context, mach
 			Status: metav1.ConditionFalse,
 			Reason: infrastructurev1beta2.InstanceWaitingForSshKeyReason,
 		})
-		return ctrl.Result{RequeueAfter: 15 * time.Second}
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}
 	}
 	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
 		Type:   infrastructurev1beta2.ClusterSshKeyReadyCondition,
@@ -375,15 +681,63 @@ func (r *ContaboMachineReconciler) setupContaboMachine(ctx context.Context, mach
 		Reason: infrastructurev1beta2.ClusterSshKeyReadyReason,
 	})
 
+	// Register the SSH key overriding the cluster SSH key, if any
+	if result := r.reconcileMachineSSHKey(ctx, contaboMachine, contaboCluster); result.RequeueAfter > 0 {
+		return result
+	}
+
 	return ctrl.Result{}
 }
 
+// cloudConfigPart is a cloud-config rendered for a machine and merged with the provider cloud-config
+type cloudConfigPart struct {
+	// render returns the cloud-config, nil when the machine does not need it
+	render func() ([]byte, error)
+	// first merges the cloud-config before the provider one, so that its settings and commands come first
+	first bool
+	// message describes the failure to render or merge the cloud-config
+	message string
+}
+
+// mergeCloudConfigParts renders the cloud-config parts of the machine and merges them in order with the provider
+// cloud-config
+func (r *ContaboMachineReconciler) mergeCloudConfigParts(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, cloudConfig string, parts []cloudConfigPart) (string, error) {
+	for _, part := range parts {
+		config, err := part.render()
+		if err == nil && config != nil {
+			var merged []byte
+			if part.first {
+				merged, err = mergeCloudConfig(config, []byte(cloudConfig))
+			} else {
+				merged, err = mergeCloudConfig([]byte(cloudConfig), config)
+			}
+			if err == nil {
+				cloudConfig = string(merged)
+			}
+		}
+		if err != nil {
+			return "", r.handleError(ctx, contaboMachine, err, infrastructurev1beta2.BootstrapDataMergeFailedReason, part.message)
+		}
+	}
+	return cloudConfig, nil
+}
+
 // getBootstrapData retrieves and validates bootstrap data
 func (r *ContaboMachineReconciler) getBootstrapData(ctx context.Context, machine *clusterv1.Machine, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (string, ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	// Get bootstrap data secret
-	if machine.Spec.Bootstrap.DataSecretName == nil {
+	// Get bootstrap data secret, the Machines of a ContaboMachinePool use the one of their MachinePool
+	dataSecretName := machine.Spec.Bootstrap.DataSecretName
+	if ptr.Deref(dataSecretName, "") == "" {
+		machinePoolDataSecretName, err := machinePoolBootstrapDataSecretName(ctx, r.Client, machine)
+		if err != nil {
+			return "", ctrl.Result{}, err
+		}
+		if machinePoolDataSecretName != nil {
+			dataSecretName = machinePoolDataSecretName
+		}
+	}
+	if ptr.Deref(dataSecretName, "") == "" {
 		log.Info("Bootstrap data secret is not available yet")
 		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
 			Type:   infrastructurev1beta2.BootstrapDataAvailableCondition,
@@ -397,7 +751,7 @@ func (r *ContaboMachineReconciler) getBootstrapData(ctx context.Context, machine
 	bootstrapDataSecret := &corev1.Secret{}
 	bootstrapDataSecretName := client.ObjectKey{
 		Namespace: contaboMachine.Namespace,
-		Name:      *machine.Spec.Bootstrap.DataSecretName,
+		Name:      *dataSecretName,
 	}
 	if err := r.Get(ctx, bootstrapDataSecretName, bootstrapDataSecret); err != nil {
 		return "", ctrl.Result{}, r.handleError(
@@ -419,27 +773,27 @@ func (r *ContaboMachineReconciler) getBootstrapData(ctx context.Context, machine
 		return "", ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
-	cloudConfig := workerCloudConfig
-	if contaboMachine.Labels[clusterv1.MachineControlPlaneLabel] != "false" {
-		cloudConfig = controlplaneCloudConfig
-	}
-
-	// Merge cloud-config with bootstrap data
-	mergedConfig, err := mergeCloudConfig([]byte(cloudConfig), bootstrapDataSecret.Data["value"])
-	if err != nil {
+	// Only cloud-config bootstrap data can be given to Contabo as user data
+	if err := validateBootstrapDataFormat(bootstrapDataSecret); err != nil {
 		return "", ctrl.Result{}, r.handleError(
 			ctx,
 			contaboMachine,
 			err,
-			infrastructurev1beta2.BootstrapDataMergeFailedReason,
-			"Failed to merge cloud-config with bootstrap data",
+			infrastructurev1beta2.BootstrapDataFormatUnsupportedReason,
+			"Unsupported bootstrap data format",
 		)
 	}
 
-	// Replace template variables in cloud-config
-	mergedConfigStr := string(mergedConfig)
-	kubadmVersion := strings.Join(strings.Split(machine.Spec.Version, ".")[:2], ".")
-	internalIpV4CIDR := contaboCluster.Status.PrivateNetwork.Cidr
+	// The k3s and rke2 bootstrap data install their own distribution, only the kubeadm one relies on the
+	// containerd and kubeadm packages of the provider cloud-config
+	flavor := detectBootstrapFlavor(bootstrapDataSecret.Data["value"])
+	cloudConfig := workerCloudConfig
+	if flavor != bootstrapFlavorKubeadm {
+		cloudConfig = baseCloudConfig
+	} else if contaboMachine.Labels[clusterv1.MachineControlPlaneLabel] != "false" {
+		cloudConfig = controlplaneCloudConfig
+	}
+
 	internalIpV4 := ""
 	for _, address := range contaboMachine.Status.Addresses {
 		if address.Type == clusterv1.MachineInternalIP {
@@ -451,14 +805,161 @@ func (r *ContaboMachineReconciler) getBootstrapData(ctx context.Context, machine
 		return "", ctrl.Result{}, fmt.Errorf("failed to find internal ipv4 address for instance %d", contaboMachine.Status.Instance.InstanceId)
 	}
 
-	// Replace all variables
-	mergedConfigStr = strings.ReplaceAll(mergedConfigStr, "${KUBEADM_VERSION}", kubadmVersion)
-	mergedConfigStr = strings.ReplaceAll(mergedConfigStr, "${INTERNAL_IPV4_CIDR}", internalIpV4CIDR)
-	mergedConfigStr = strings.ReplaceAll(mergedConfigStr, "${INTERNAL_IPV4}", net.ParseIP(internalIpV4).String())
-	mergedConfigStr = strings.ReplaceAll(mergedConfigStr, "${EXTERNAL_IPV4}", net.ParseIP(contaboMachine.Status.Instance.IpConfig.V4.Ip).String())
-	mergedConfigStr = strings.ReplaceAll(mergedConfigStr, "${EXTERNAL_IPV6}", net.ParseIP(contaboMachine.Status.Instance.IpConfig.V6.Ip).String())
-	mergedConfigStr = strings.ReplaceAll(mergedConfigStr, "${PROVIDER_ID}", *contaboMachine.Spec.ProviderID)
-	mergedConfigStr = strings.ReplaceAll(mergedConfigStr, "${CLUSTER_UUID}", contaboCluster.Spec.ClusterUUID)
+	// Inject Contabo specific kubelet flags in kubeadm nodeRegistration
+	bootstrapData, err := injectKubeletExtraArgs(bootstrapDataSecret.Data["value"], contaboKubeletExtraArgs(contaboMachine, net.ParseIP(internalIpV4).String()))
+	if err != nil {
+		return "", ctrl.Result{}, r.handleError(
+			ctx,
+			contaboMachine,
+			err,
+			infrastructurev1beta2.BootstrapDataMergeFailedReason,
+			"Failed to inject kubelet flags in bootstrap data",
+		)
+	}
+
+	// Register the node with the labels and taints of the machine
+	bootstrapData, err = injectNodeLabelsAndTaints(bootstrapData, contaboMachine.Spec.NodeLabels, contaboMachine.Spec.NodeTaints)
+	if err != nil {
+		return "", ctrl.Result{}, r.handleError(
+			ctx,
+			contaboMachine,
+			err,
+			infrastructurev1beta2.BootstrapDataMergeFailedReason,
+			"Failed to inject node labels and taints in bootstrap data",
+		)
+	}
+
+	// Bind the API server on the control plane endpoint port, the endpoint slices target the instances on this port
+	if contaboMachine.Labels[clusterv1.MachineControlPlaneLabel] != "false" && contaboCluster.Spec.ControlPlaneEndpoint.Port != 0 {
+		bootstrapData, err = injectAPIServerBindPort(bootstrapData, contaboCluster.Spec.ControlPlaneEndpoint.Port)
+		if err != nil {
+			return "", ctrl.Result{}, r.handleError(
+				ctx,
+				contaboMachine,
+				err,
+				infrastructurev1beta2.BootstrapDataMergeFailedReason,
+				"Failed to inject API server bind port in bootstrap data",
+			)
+		}
+	}
+
+	// Set the node name, address and labels in the k3s or rke2 configuration, the kubeadm ones above are left out
+	if flavor != bootstrapFlavorKubeadm {
+		bootstrapData, err = injectRancherConfig(bootstrapData, flavor,
+			contaboRancherConfig(flavor, contaboMachine, net.ParseIP(internalIpV4).String(), contaboCluster.Spec.ControlPlaneEndpoint.Port))
+		if err != nil {
+			return "", ctrl.Result{}, r.handleError(
+				ctx,
+				contaboMachine,
+				err,
+				infrastructurev1beta2.BootstrapDataMergeFailedReason,
+				fmt.Sprintf("Failed to inject %s configuration in bootstrap data", flavor),
+			)
+		}
+	}
+
+	// Render the cloud-configs of the machine and merge them in order with the provider cloud-config
+	cloudConfig, err = r.mergeCloudConfigParts(ctx, contaboMachine, cloudConfig, []cloudConfigPart{
+		{
+			// Set the private network MTU and render it for the CNI when configured on the cluster
+			render:  func() ([]byte, error) { return privateNetworkCloudConfig(contaboCluster) },
+			message: "Failed to render private network MTU in bootstrap data",
+		},
+		{
+			// Add the additional IPv4 addresses of the instance to its public interface
+			render:  func() ([]byte, error) { return additionalIPv4CloudConfig(contaboMachine) },
+			message: "Failed to render additional IPv4 addresses in bootstrap data",
+		},
+		{
+			// Answer on the control plane endpoint VIP of the cluster on the control plane instances
+			render:  func() ([]byte, error) { return controlPlaneEndpointVIPCloudConfig(contaboMachine, contaboCluster) },
+			message: "Failed to render control plane endpoint VIP in bootstrap data",
+		},
+		{
+			// Install the etcd snapshot uploader on the control plane machines of the clusters with etcd backups
+			render:  func() ([]byte, error) { return etcdBackupCloudConfig(contaboMachine, contaboCluster, flavor) },
+			message: "Failed to render etcd backup uploader in bootstrap data",
+		},
+		{
+			// Install the node-exporter on the machines with node monitoring
+			render:  func() ([]byte, error) { return nodeMonitoringCloudConfig(contaboMachine, contaboCluster) },
+			message: "Failed to render node-exporter in bootstrap data",
+		},
+		{
+			// Route the egress of private-only machines through the NAT gateway and the proxy before the bootstrap commands
+			render:  func() ([]byte, error) { return privateOnlyCloudConfig(contaboMachine, contaboCluster) },
+			first:   true,
+			message: "Failed to render private-only settings in bootstrap data",
+		},
+		{
+			// Set the hostname of the instance to the name of its node before kubeadm runs
+			render: func() ([]byte, error) {
+				if contaboMachine.Status.NodeName == "" {
+					return nil, nil
+				}
+				return hostnameCloudConfig(contaboMachine.Status.NodeName)
+			},
+			first:   true,
+			message: "Failed to render the hostname in bootstrap data",
+		},
+		{
+			// Set the resolvers of the machine before the bootstrap commands
+			render:  func() ([]byte, error) { return dnsCloudConfig(contaboMachine) },
+			first:   true,
+			message: "Failed to render DNS settings in bootstrap data",
+		},
+		{
+			// Apply the network-config of the machine before any other command
+			render:  func() ([]byte, error) { return networkCloudConfig(contaboMachine) },
+			first:   true,
+			message: "Failed to render network-config in bootstrap data",
+		},
+	})
+	if err != nil {
+		return "", ctrl.Result{}, err
+	}
+
+	// Merge cloud-config with bootstrap data
+	mergedConfig, err := mergeCloudConfig([]byte(cloudConfig), bootstrapData)
+	if err != nil {
+		return "", ctrl.Result{}, r.handleError(
+			ctx,
+			contaboMachine,
+			err,
+			infrastructurev1beta2.BootstrapDataMergeFailedReason,
+			"Failed to merge cloud-config with bootstrap data",
+		)
+	}
+
+	// Replace template variables in cloud-config
+	kubeadmVersion, err := kubeadmPackageVersion(machine.Spec.Version)
+	if err != nil {
+		return "", ctrl.Result{}, r.handleError(
+			ctx,
+			contaboMachine,
+			err,
+			infrastructurev1beta2.BootstrapDataMergeFailedReason,
+			"Failed to render the Kubernetes version in bootstrap data",
+		)
+	}
+	mergedConfigStr, err := renderCloudConfigVariables(string(mergedConfig), map[string]string{
+		"KUBEADM_VERSION":    kubeadmVersion,
+		"INTERNAL_IPV4_CIDR": contaboCluster.Status.PrivateNetwork.Cidr,
+		"INTERNAL_IPV4":      net.ParseIP(internalIpV4).String(),
+		"EXTERNAL_IPV4":      net.ParseIP(contaboMachine.Status.Instance.IpConfig.V4.Ip).String(),
+		"EXTERNAL_IPV6":      net.ParseIP(contaboMachine.Status.Instance.IpConfig.V6.Ip).String(),
+		"PROVIDER_ID":        *contaboMachine.Spec.ProviderID,
+		"CLUSTER_UUID":       contaboCluster.Spec.ClusterUUID,
+	})
+	if err != nil {
+		return "", ctrl.Result{}, r.handleError(
+			ctx,
+			contaboMachine,
+			err,
+			infrastructurev1beta2.BootstrapDataMergeFailedReason,
+			"Failed to render variables in bootstrap data",
+		)
+	}
 
 	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
 		Type:   infrastructurev1beta2.BootstrapDataAvailableCondition,
@@ -474,7 +975,7 @@ func (r *ContaboMachineReconciler) getBootstrapData(ctx context.Context, machine
 func (r *ContaboMachineReconciler) provisionInstance(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, error) {
 	// Check if SSH key is available before accessing it
 	if contaboCluster.Status.SshKey == nil {
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, fmt.Errorf("SSH key not yet available, waiting")
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, fmt.Errorf("SSH key not yet available, waiting")
 	}
 
 	// Find or create instance
@@ -483,6 +984,14 @@ func (r *ContaboMachineReconciler) provisionInstance(ctx context.Context, contab
 		return result, err
 	}
 
+	// Add the add-ons of the machine a reused instance does not hold
+	if err := r.reconcileAddOns(ctx, contaboMachine); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Record the product and image metadata of the acquired instance
+	r.reconcileCatalogSnapshot(ctx, contaboMachine)
+
 	// Validate instance status
 	if result, err := r.validateInstanceStatus(ctx, contaboMachine); err != nil || result.RequeueAfter > 0 {
 		return result, err
@@ -509,6 +1018,11 @@ func (r *ContaboMachineReconciler) findOrCreateInstance(ctx context.Context, con
 	r.instanceReuseMutex.Lock()
 	defer r.instanceReuseMutex.Unlock()
 
+	// Wait for the last ordered instance to appear, or replace it when it never does
+	if result, handled, err := r.reconcileInstanceOrder(ctx, contaboMachine); handled || err != nil {
+		return result, err
+	}
+
 	// Try to find existing instance
 	instance, err := r.getExistingInstance(ctx, contaboMachine, contaboCluster)
 	if err != nil {
@@ -520,6 +1034,20 @@ func (r *ContaboMachineReconciler) findOrCreateInstance(ctx context.Context, con
 		return ctrl.Result{}, nil
 	}
 
+	// Enforce namespace ContaboQuota before acquiring a new instance
+	if contaboMachine.Status.Instance == nil {
+		if err := checkQuota(ctx, r.Client, &r.quotaReservations, client.ObjectKeyFromObject(contaboMachine), instanceQuotaRequest(contaboMachine)); err != nil {
+			log.Info("Waiting for quota to acquire a new instance", "reason", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceWaitingForQuotaReason,
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.QuotaInterval()}, nil
+		}
+	}
+
 	// Look for reusable instance if none found
 	if contaboMachine.Status.Instance == nil {
 		instance, err = r.findReusableInstance(ctx, contaboMachine, contaboCluster)
@@ -528,33 +1056,109 @@ func (r *ContaboMachineReconciler) findOrCreateInstance(ctx context.Context, con
 		}
 		if instance != nil {
 			contaboMachine.Status.Instance = instance
+			delete(contaboMachine.Annotations, infrastructurev1beta2.ParkedInstanceAnnotation)
 			log.Info("Found reusable instance", "instanceID", instance.InstanceId)
-			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, nil
+		}
+		if _, ok := contaboMachine.Annotations[infrastructurev1beta2.ParkedInstanceAnnotation]; ok {
+			r.abandonParkedInstanceClaim(ctx, contaboMachine)
+			return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, nil
 		}
 	}
 
 	// Create new instance if none found and provisioning type allows
 	if contaboMachine.Status.Instance == nil {
+		// Never order an instance once clusterctl move paused the cluster, the order would be lost with the move
+		if paused, err := r.pausedForMove(ctx, contaboMachine); err != nil || paused {
+			log.Info("Cluster is paused, not ordering a new instance", "error", err)
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+		}
+
+		// Slow down the instance creations while most of the creations of the Contabo account fail
+		if wait := r.instanceCreationBackPressure.wait(contaboAccountKey(contaboCluster), time.Now()); wait > 0 {
+			message := fmt.Sprintf("Instance creations of the Contabo account fail at a high rate, waiting %s before the next attempt, see the %s condition of the ContaboCluster",
+				wait.Round(time.Second), infrastructurev1beta2.ClusterInstanceCreationHealthyCondition)
+			log.Info("Waiting for the instance creation back-pressure to create a new instance", "wait", wait)
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceCreationBackPressureReason,
+				Message: message,
+			})
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+
+		// Limit the instance operations running at once for the cluster
+		if err := r.acquireOperationSlot(ctx, contaboMachine, contaboCluster); err != nil {
+			log.Info("Waiting for an operation slot to create a new instance", "reason", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceWaitingForOperationSlotReason,
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
+		}
+
 		instance, err = r.createNewInstance(ctx, contaboMachine, contaboCluster)
+		if contaboMachine.Status.InstanceOrder != nil && contaboMachine.Status.InstanceOrder.InstanceId != 0 {
+			if err := r.persistOperationCheckpoint(ctx, contaboMachine); err != nil {
+				log.Error(err, "Failed to checkpoint the instance order")
+			}
+		} else {
+			r.releaseOperationSlot(contaboMachine)
+		}
+		if errors.Is(err, ErrTransientAPIFailure) {
+			// A partially created instance is found by display name on the next reconciliation
+			log.Info("Contabo API failed while creating a new instance, retrying", "error", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceCreatingReason,
+				Message: Truncate(err.Error(), 1024),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
+		}
+		if errors.Is(err, ErrOutOfStock) {
+			return r.reconcileOutOfStock(ctx, contaboMachine, contaboCluster, err), nil
+		}
+		if errors.Is(err, contabo.ErrUnauthorized) {
+			// Replacing the machine would not help, the ContaboCluster reports the credentials to fix
+			log.Info("Contabo API rejected the credentials while creating a new instance, retrying", "error", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.CredentialsRejectedReason,
+				Message: Truncate(err.Error(), 1024),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+		}
 		if err != nil {
 			log.Error(err, "Failed to create new instance")
-			// Set Failure condition instead
-			contaboMachine.Status.FailureReason = ptr.To(string(infrastructurev1beta2.InstanceCreatingReason))
-			contaboMachine.Status.FailureMessage = ptr.To("Failed to create new instance: " + err.Error())
+			if errors.Is(err, ErrProductUnavailable) {
+				// Surface discontinued products on templates and cluster instead of silently failing scale-ups
+				r.reportProductAvailability(ctx, contaboMachine, contaboCluster, string(ptr.Deref(contaboMachine.Spec.Instance.ProductId, "")), false, err.Error())
+				r.setMachineFailure(contaboMachine, infrastructurev1beta2.InvalidConfigurationMachineError, infrastructurev1beta2.ProductUnavailableReason, "Failed to create new instance: "+err.Error())
+			} else {
+				r.setMachineFailure(contaboMachine, infrastructurev1beta2.CreateMachineError, infrastructurev1beta2.InstanceFailedReason, "Failed to create new instance: "+err.Error())
+			}
 			// Return error to prevent calling validateInstanceStatus with nil instance
 			return ctrl.Result{}, fmt.Errorf("failed to create new instance: %w", err)
 		}
 		if instance != nil {
 			contaboMachine.Status.Instance = instance
 			log.Info("Created new instance", "instanceID", instance.InstanceId)
+			r.reportProductAvailability(ctx, contaboMachine, contaboCluster, string(ptr.Deref(contaboMachine.Spec.Instance.ProductId, "")), true, "")
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
+		// The order was accepted but the instance is not visible yet, reconcileInstanceOrder waits for it
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 	}
 
 	// Update instance state (display name and networking)
 	// This happens inside the mutex to prevent race conditions
 	if err := r.updateInstanceState(ctx, contaboMachine, contaboCluster, contaboMachine.Status.Instance); err != nil {
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, r.handleError(
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, r.handleError(
 			ctx,
 			contaboMachine,
 			err,
@@ -564,7 +1168,7 @@ func (r *ContaboMachineReconciler) findOrCreateInstance(ctx context.Context, con
 	}
 
 	// Force requeue to retrieve instance via display name
-	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, nil
 }
 
 // reconcilePrivateNetworkAssignment handles private network assignment for the instance
@@ -575,9 +1179,9 @@ func (r *ContaboMachineReconciler) reconcilePrivateNetworkAssignment(ctx context
 	var privateNetwork *models.PrivateNetworkResponse
 
 	// Retrieve private network details
-	privateNetworkGetResp, err := r.ContaboClient.RetrievePrivateNetworkWithResponse(ctx, contaboCluster.Status.PrivateNetwork.PrivateNetworkId, &models.RetrievePrivateNetworkParams{})
-	if err != nil || privateNetworkGetResp.StatusCode() < 200 || privateNetworkGetResp.StatusCode() >= 300 {
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, r.handleError(
+	privateNetworkGetResp, err := r.ContaboClient.RetrievePrivateNetworkWithResponse(ctx, contaboCluster.Status.PrivateNetwork.PrivateNetworkId, contabo.NewParams[models.RetrievePrivateNetworkParams](ctx))
+	if err := contabo.CheckResponse(privateNetworkGetResp, err); err != nil {
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, r.handleError(
 			ctx,
 			contaboMachine,
 			err,
@@ -601,9 +1205,36 @@ func (r *ContaboMachineReconciler) reconcilePrivateNetworkAssignment(ctx context
 		log.Info("Assigning instance to private network",
 			"instanceID", contaboMachine.Status.Instance.InstanceId,
 			"privateNetworkID", privateNetwork.PrivateNetworkId)
-		_, err := r.ContaboClient.AssignInstancePrivateNetwork(ctx, privateNetwork.PrivateNetworkId, contaboMachine.Status.Instance.InstanceId, nil)
+		// Contabo processes a single assignment per private network at a time
+		waitCtx, cancel := context.WithTimeout(ctx, privateNetworkAssignWait)
+		release, err := r.privateNetworkAssignments.acquire(waitCtx, r.PrivateNetworkAssignWorkers, privateNetwork.PrivateNetworkId, contaboMachine.Status.Instance.InstanceId)
+		cancel()
 		if err != nil {
-			return ctrl.Result{RequeueAfter: 15 * time.Second}, r.handleError(
+			log.Info("Waiting for the other assignments of the private network", "privateNetworkID", privateNetwork.PrivateNetworkId, "reason", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceWaitingForPrivateNetworkReason,
+				Message: fmt.Sprintf("Waiting for the other assignments of private network %d", privateNetwork.PrivateNetworkId),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
+		}
+		err = assignPrivateNetwork(ctx, r.ContaboClient, privateNetwork.PrivateNetworkId, contaboMachine.Status.Instance.InstanceId)
+		release()
+		if errors.Is(err, contabo.ErrConflict) {
+			log.Info("Private network still busy with other assignments", "privateNetworkID", privateNetwork.PrivateNetworkId, "error", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceWaitingForPrivateNetworkReason,
+				Message: fmt.Sprintf("Private network %d is busy with other assignments: %s", privateNetwork.PrivateNetworkId, err.Error()),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
+		}
+		// Reinstalling would not apply anything when the assignment fails, e.g. for an instance outside of the private
+		// network region
+		if err != nil {
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, r.handleError(
 				ctx,
 				contaboMachine,
 				err,
@@ -612,18 +1243,29 @@ func (r *ContaboMachineReconciler) reconcilePrivateNetworkAssignment(ctx context
 			)
 		}
 
-		// Need to Reinstall to apply network changes
+		// Need to Reinstall to apply network changes, within the instance operations allowed for the cluster
+		if err := r.acquireOperationSlot(ctx, contaboMachine, contaboCluster); err != nil {
+			log.Info("Waiting for an operation slot to reinstall the instance", "reason", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceWaitingForOperationSlotReason,
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
+		}
 		log.Info("Reinstalling instance to apply private network changes",
 			"instanceID", contaboMachine.Status.Instance.InstanceId)
-		sshKeys := []int64{contaboCluster.Status.SshKey.SecretId}
-		_, err = r.ContaboClient.ReinstallInstanceWithResponse(ctx, contaboMachine.Status.Instance.InstanceId, &models.ReinstallInstanceParams{}, models.ReinstallInstanceRequest{
+		sshKeys := []int64{machineSSHKeyID(contaboMachine, contaboCluster)}
+		_, err = r.reinstallInstance(ctx, contaboMachine, contaboMachine.Status.Instance, models.ReinstallInstanceRequest{
 			SshKeys:      &sshKeys,
 			DefaultUser:  ptr.To(models.ReinstallInstanceRequestDefaultUserAdmin),
-			ImageId:      DefaultUbuntuImageID,
+			ImageId:      instanceImageId(contaboMachine),
 			RootPassword: nil,
-		})
+		}, "apply the private network assignment")
 		if err != nil {
-			return ctrl.Result{RequeueAfter: 15 * time.Second}, r.handleError(
+			r.releaseOperationSlot(contaboMachine)
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, r.handleError(
 				ctx,
 				contaboMachine,
 				err,
@@ -633,7 +1275,7 @@ func (r *ContaboMachineReconciler) reconcilePrivateNetworkAssignment(ctx context
 		}
 
 		// Requeue to wait for the instance to be fully restarted
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 	}
 
 	return ctrl.Result{}, nil
@@ -646,9 +1288,12 @@ func (r *ContaboMachineReconciler) reconcileContaboMachineAddresses(ctx context.
 	log.Info("Updating contabo machine addresses")
 
 	// Get the private network
-	privateNetworkGetResp, err := r.ContaboClient.RetrievePrivateNetworkWithResponse(ctx, contaboCluster.Status.PrivateNetwork.PrivateNetworkId, &models.RetrievePrivateNetworkParams{})
-	if err != nil {
-		return err
+	privateNetworkGetResp, err := r.ContaboClient.RetrievePrivateNetworkWithResponse(ctx, contaboCluster.Status.PrivateNetwork.PrivateNetworkId, contabo.NewParams[models.RetrievePrivateNetworkParams](ctx))
+	if err := contabo.CheckResponse(privateNetworkGetResp, err); err != nil {
+		return fmt.Errorf("failed to retrieve private network %d: %w", contaboCluster.Status.PrivateNetwork.PrivateNetworkId, err)
+	}
+	if len(privateNetworkGetResp.JSON200.Data) == 0 {
+		return fmt.Errorf("failed to retrieve private network %d: not returned by the Contabo API", contaboCluster.Status.PrivateNetwork.PrivateNetworkId)
 	}
 	privateNetwork := &privateNetworkGetResp.JSON200.Data[0]
 
@@ -674,11 +1319,18 @@ func (r *ContaboMachineReconciler) reconcileContaboMachineAddresses(ctx context.
 		Type:    clusterv1.MachineExternalIP,
 		Address: contaboMachine.Status.Instance.IpConfig.V4.Ip,
 	})
+	// Add the additional ip v4 of the instance after the primary one
+	contaboMachine.Status.IPv4Addresses = ipv4AddressesStatus(contaboMachine.Status.Instance)
+	addresses = append(addresses, secondaryMachineAddresses(contaboMachine.Status.IPv4Addresses)...)
 
 	// Add hostname entry
+	hostname := contaboMachine.Status.NodeName
+	if hostname == "" {
+		hostname = contaboMachine.Status.Instance.Name
+	}
 	addresses = append(addresses, clusterv1.MachineAddress{
 		Type:    clusterv1.MachineHostName,
-		Address: contaboMachine.Status.Instance.Name,
+		Address: hostname,
 	})
 
 	// Update status addresses
@@ -712,7 +1364,7 @@ func (r *ContaboMachineReconciler) bootstrapInstance(ctx context.Context, machin
 		Reason: infrastructurev1beta2.InstanceReinstallingReason,
 	})
 	// Retrieve SSH key IDs
-	sshKeys := []int64{contaboCluster.Status.SshKey.SecretId}
+	sshKeys := []int64{machineSSHKeyID(contaboMachine, contaboCluster)}
 
 	// Required for clusterctl move to not reinstall instances again
 	log.Info("Check if instance is already reinstalled with bootstrap userdata by checking its cluster uuid", "instanceID", contaboMachine.Status.Instance.InstanceId)
@@ -728,41 +1380,103 @@ func (r *ContaboMachineReconciler) bootstrapInstance(ctx context.Context, machin
 		log.Info("Instance already has the correct clusterUUID, skipping reinstall",
 			"instanceID", contaboMachine.Status.Instance.InstanceId)
 	} else {
+		// Limit the instance operations running at once for the cluster
+		if err := r.acquireOperationSlot(ctx, contaboMachine, contaboCluster); err != nil {
+			log.Info("Waiting for an operation slot to reinstall the instance", "reason", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceBootstrapCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceWaitingForOperationSlotReason,
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
+		}
+
+		// Join with a short-lived token of the instance instead of the token shared by the machines of the cluster
+		bootstrapData, err = r.reconcileBootstrapToken(ctx, contaboMachine, contaboCluster, bootstrapData)
+		if err != nil {
+			r.releaseOperationSlot(contaboMachine)
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.BootstrapDataAvailableCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.BootstrapTokenFailedReason,
+				Message: err.Error(),
+			})
+			log.Error(err, "Failed to create the bootstrap token of the instance")
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+		}
+
+		// Compress the bootstrap data or upload it to object storage when too large for the user data
+		rendered, err := r.renderUserData(ctx, contaboMachine, contaboCluster, bootstrapData)
+		if sizeErr := checkUserDataSize(rendered); errors.Is(err, ErrBootstrapDataTooLarge) && sizeErr != nil {
+			// The Contabo API would refuse the reinstall, wait for the object storage or for smaller bootstrap data
+			r.releaseOperationSlot(contaboMachine)
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.BootstrapDataAvailableCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.BootstrapDataTooLargeReason,
+				Message: sizeErr.Error(),
+			})
+			r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.BootstrapDataTooLargeReason, sizeErr.Error())
+			log.Info("Bootstrap data is too large for the Contabo API, not reinstalling the instance", "reason", sizeErr.Error())
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+		} else if errors.Is(err, ErrBootstrapDataTooLarge) {
+			log.Info("Bootstrap data is too large, sending it anyway, configure the bootstrap object storage of the ContaboProviderSettings", "reason", err.Error())
+			r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.BootstrapDataTooLargeReason, err.Error())
+		} else if err != nil {
+			r.releaseOperationSlot(contaboMachine)
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.BootstrapDataAvailableCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.BootstrapDataUploadFailedReason,
+				Message: err.Error(),
+			})
+			log.Error(err, "Failed to upload bootstrap data to object storage")
+			return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, nil
+		}
+		// Delete the bootstrap data of a previous reinstall before replacing it
+		r.deleteUserDataObject(ctx, contaboMachine)
+		contaboMachine.Status.UserData = &rendered.status
+
 		// Reinstall instance with cloud-init bootstrap data
 		log.Info("Reinstalling instance with SSH keys and bootstrap data",
 			"instanceId", contaboMachine.Status.Instance.InstanceId,
 			"sshKeyIds", sshKeys,
 			"defaultUser", contaboMachine.Status.Instance.DefaultUser,
-			"imageId", DefaultUbuntuImageID)
+			"imageId", instanceImageId(contaboMachine),
+			"userDataMode", rendered.status.Mode,
+			"userDataSize", rendered.status.Size)
 
-		resp, err := r.ContaboClient.ReinstallInstanceWithResponse(ctx, contaboMachine.Status.Instance.InstanceId, &models.ReinstallInstanceParams{}, models.ReinstallInstanceRequest{
+		resp, err := r.reinstallInstance(ctx, contaboMachine, contaboMachine.Status.Instance, models.ReinstallInstanceRequest{
 			SshKeys:      &sshKeys,
 			DefaultUser:  ptr.To(models.ReinstallInstanceRequestDefaultUserAdmin),
-			ImageId:      DefaultUbuntuImageID,
+			ImageId:      instanceImageId(contaboMachine),
 			RootPassword: nil,
-			UserData:     &bootstrapData,
-		})
-		if err != nil || resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+			UserData:     &rendered.userData,
+		}, "bootstrap the machine")
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			r.releaseOperationSlot(contaboMachine)
 			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
 				Type:    infrastructurev1beta2.InstanceBootstrapCondition,
 				Status:  metav1.ConditionFalse,
 				Reason:  infrastructurev1beta2.InstanceReinstallingFailedReason,
-				Message: fmt.Sprintf("Failed to reinstall instance, statusCode: %d", resp.StatusCode()),
+				Message: fmt.Sprintf("Failed to reinstall instance: %s", err),
 			})
-			return ctrl.Result{RequeueAfter: 15 * time.Second}, r.handleError(
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, r.handleError(
 				ctx,
 				contaboMachine,
 				err,
 				infrastructurev1beta2.InstanceReinstallingFailedReason,
-				fmt.Sprintf("Failed to reinstall instance, statusCode: %d", resp.StatusCode()),
+				"Failed to reinstall instance",
 			)
 		}
 		log.Info("Reinstall instance request sent successfully",
 			"instanceId", contaboMachine.Status.Instance.InstanceId)
+		recordInstalledSpec(contaboMachine)
 
 		// Refresh instance status after reinstall
 		instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, contaboMachine.Status.Instance.InstanceId, nil)
-		if err == nil && instanceResp.StatusCode() >= 200 && instanceResp.StatusCode() < 300 {
+		if contabo.CheckResponse(instanceResp, err) == nil && len(instanceResp.JSON200.Data) > 0 {
 			contaboMachine.Status.Instance = convertInstanceResponseData(&instanceResp.JSON200.Data[0])
 		}
 
@@ -779,6 +1493,11 @@ func (r *ContaboMachineReconciler) bootstrapInstance(ctx context.Context, machin
 		Status: metav1.ConditionFalse,
 		Reason: infrastructurev1beta2.InstanceWaitingForCloudInitReason,
 	})
+
+	// Optionally verify sshd and cloud-init health, resetting instances that never complete their first boot
+	if result, err := r.reconcileFirstBootProbe(ctx, contaboMachine, contaboCluster); err != nil || result.RequeueAfter > 0 {
+		return result, err
+	}
 	// Wait for cloud-init to finish
 	log.Info("Waiting for cloud-init to finish on instance",
 		"instanceID", contaboMachine.Status.Instance.InstanceId,
@@ -812,21 +1531,30 @@ func (r *ContaboMachineReconciler) bootstrapInstance(ctx context.Context, machin
 			log.Error(err, "Failed to reset instance after cloud-init failure",
 				"instanceID", contaboMachine.Status.Instance.InstanceId)
 		}
-		return ctrl.Result{RequeueAfter: 5 * time.Second}, err
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, err
 	}
 
 	if strings.Contains(cloudInitStatus, "status: running") {
 		log.Info("cloud-init is still running, will retry", "output", cloudInitStatus, "requeueAfter", "20s")
-		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.CloudInitInterval()}, nil
 	}
 
 	log.Info("cloud-init has finished on instance",
 		"instanceID", contaboMachine.Status.Instance.InstanceId)
 
+	// The bootstrap data uploaded to object storage was fetched, it holds credentials
+	r.deleteUserDataObject(ctx, contaboMachine)
+
+	// Record the private network MTU on the cluster from the first bootstrapped instance
+	r.detectPrivateNetworkMTU(ctx, contaboMachine, contaboCluster)
+
 	if result, err := r.initializeNode(ctx, machine, contaboMachine, contaboCluster); err != nil || result.RequeueAfter > 0 {
 		return result, err
 	}
 
+	// The node joined, its bootstrap token is no longer needed
+	r.deleteBootstrapToken(ctx, contaboMachine, contaboCluster)
+
 	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
 		Type:   infrastructurev1beta2.InstanceBootstrapCondition,
 		Status: metav1.ConditionTrue,
@@ -861,14 +1589,14 @@ func (r *ContaboMachineReconciler) initializeNode(ctx context.Context, machine *
 		log.Info("Waiting to get kubeconfig from owner Cluster",
 			"clusterName", machine.Spec.ClusterName,
 			"namespace", machine.Namespace)
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 	}
 
-	nodeName, err := ParseProviderID(*contaboMachine.Spec.ProviderID)
+	nodeName, err := machineNodeName(contaboMachine)
 	if err != nil {
-		log.Error(err, "Failed to parse node name from provider ID",
-			"providerID", *contaboMachine.Spec.ProviderID)
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		log.Error(err, "Failed to get the node name of the machine",
+			"providerID", contaboMachine.Spec.ProviderID)
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 	}
 
 	log.Info("Node found in cluster, updating ips",
@@ -895,7 +1623,7 @@ func (r *ContaboMachineReconciler) initializeNode(ctx context.Context, machine *
 		patchBytes, err := json.Marshal(patchNode)
 		if err != nil {
 			log.Error(err, "Failed to marshal node status patch")
-			return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 		}
 
 		// Check if ips are already set to avoid unnecessary patching
@@ -903,10 +1631,10 @@ func (r *ContaboMachineReconciler) initializeNode(ctx context.Context, machine *
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				log.Info("Node not found in cluster yet, will retry", "nodeName", nodeName)
-				return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 			}
 			log.Info("Node not found in cluster, kubelet seems not ready, will retry", "nodeName", nodeName)
-			return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 		}
 
 		ipsAlreadySet := true
@@ -931,7 +1659,7 @@ func (r *ContaboMachineReconciler) initializeNode(ctx context.Context, machine *
 			_, err = k8sClient.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
 			if err != nil {
 				log.Error(err, "Failed to patch node with IPs", "nodeName", nodeName)
-				return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 			}
 		} else {
 			log.Info("Node IP addresses are already up to date, skipping patch",
@@ -948,13 +1676,17 @@ func (r *ContaboMachineReconciler) initializeNode(ctx context.Context, machine *
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				log.Info("Node not found in cluster yet, will retry", "nodeName", nodeName)
-				return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 			}
 			log.Info("Node not found in cluster, kubelet seems not ready, will retry", "nodeName", nodeName)
-			return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 		}
 
-		node.Spec.ProviderID = BuildProviderID(contaboMachine.Status.Instance.Name)
+		// The provider ID can only be set on a node registered without one, see reconcileProviderID
+		providerIDSet := node.Spec.ProviderID != ""
+		if !providerIDSet {
+			node.Spec.ProviderID = BuildProviderID(contaboMachine.Status.Instance.Name)
+		}
 
 		taintRemoved := true
 		for i, taint := range node.Spec.Taints {
@@ -964,7 +1696,7 @@ func (r *ContaboMachineReconciler) initializeNode(ctx context.Context, machine *
 				break
 			}
 		}
-		if !taintRemoved {
+		if !taintRemoved || !providerIDSet {
 			_, err = k8sClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
 			if err != nil {
 				log.Error(err, "Failed to initialize node", "nodeName", nodeName)
@@ -992,14 +1724,51 @@ func (r *ContaboMachineReconciler) reconcileDelete(ctx context.Context, contaboM
 	log.Info("Machine marked for deletion, proceeding with instance cleanup",
 		"name", contaboMachine.Name)
 
+	// The deletion of the cluster destroys more resources than its threshold, wait for its confirmation
+	if deletionConfirmationPending(contaboCluster) {
+		log.Info("Waiting for the confirmation of the cluster deletion before resetting the instance",
+			"annotation", infrastructurev1beta2.ConfirmDeletionAnnotation)
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.DeletionConfirmationRequiredReason,
+			Message: fmt.Sprintf("Waiting for the %s annotation on ContaboCluster %s", infrastructurev1beta2.ConfirmDeletionAnnotation, contaboCluster.Name),
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}
+	}
+
+	// Best effort, the bootstrap data of an instance which never finished cloud-init is still in object storage
+	r.deleteUserDataObject(ctx, contaboMachine)
+	// Best effort as well, the bootstrap token of an instance which never joined is still in the workload cluster
+	r.deleteBootstrapToken(ctx, contaboMachine, contaboCluster)
+
+	// An instance created by a request which failed is not in the status, look for it by display name so it is not
+	// leaked with the machine
+	if contaboMachine.Status.Instance == nil && contaboMachine.Spec.Index != nil {
+		instance, err := r.findInstanceByDisplayName(ctx, FormatDisplayName(contaboMachine, contaboCluster))
+		if err != nil {
+			log.Info("Failed to look for an instance of the machine by display name, retrying", "error", err.Error())
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
+		}
+		if instance != nil {
+			log.Info("Found instance of the machine by display name", "instanceID", instance.InstanceId)
+			contaboMachine.Status.Instance = instance
+		}
+	}
+
 	// Retrieve instance details
 	if contaboMachine.Status.Instance == nil {
 		log.Info("Instance is already nil, assuming it is deleted, removing finalizer",
 			"name", contaboMachine.Name)
-		controllerutil.RemoveFinalizer(contaboMachine, infrastructurev1beta2.MachineFinalizer)
+		removeFinalizer(contaboMachine, infrastructurev1beta2.MachineFinalizer)
 		return ctrl.Result{}
 	}
 
+	// Hold the release of the instance while the pre-delete hooks of the machine run
+	if result, held := r.reconcileLifecycleHooks(ctx, contaboMachine, infrastructurev1beta2.ContaboLifecycleHookPointPreDelete); held {
+		return result
+	}
+
 	instance := contaboMachine.Status.Instance
 
 	// Verify Cluster API has drained the node (do not cordon or evict pods ourselves)
@@ -1016,14 +1785,16 @@ func (r *ContaboMachineReconciler) reconcileDelete(ctx context.Context, contaboM
 		if err != nil {
 			log.Info("Waiting to get kubeconfig from owner Cluster to verify node drain",
 				"cluster", contaboCluster.Name)
-			return ctrl.Result{RequeueAfter: 15 * time.Second}
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
 		}
 
-		nodeName, err := ParseProviderID(providerID)
-		if err != nil {
-			log.Error(err, "Failed to parse node name from provider ID during deletion",
-				"providerID", providerID)
-			return ctrl.Result{RequeueAfter: 15 * time.Second}
+		nodeName := contaboMachine.Status.NodeName
+		if nodeName == "" {
+			if nodeName, err = ParseProviderID(providerID); err != nil {
+				log.Error(err, "Failed to parse node name from provider ID during deletion",
+					"providerID", providerID)
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
+			}
 		}
 
 		// Wait for node to be cordoned and drained by Cluster API before proceeding.
@@ -1039,19 +1810,19 @@ func (r *ContaboMachineReconciler) reconcileDelete(ctx context.Context, contaboM
 				log.Info("Node not found in cluster, proceeding with instance cleanup", "nodeName", nodeName)
 			} else {
 				log.Error(err, "Failed to get node during deletion", "nodeName", nodeName)
-				return ctrl.Result{RequeueAfter: 15 * time.Second}
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
 			}
 		} else {
 			if !node.Spec.Unschedulable {
 				log.Info("Waiting for Cluster API to cordon node before cleaning up instance", "nodeName", nodeName)
-				return ctrl.Result{RequeueAfter: 15 * time.Second}
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
 			}
 
 			// Node is cordoned; ensure there are no remaining non-daemonset pods
 			pods, err := k8sClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName)})
 			if err != nil {
 				log.Error(err, "Failed to list pods on node during deletion", "nodeName", nodeName)
-				return ctrl.Result{RequeueAfter: 15 * time.Second}
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
 			}
 
 			remaining := 0
@@ -1080,7 +1851,7 @@ func (r *ContaboMachineReconciler) reconcileDelete(ctx context.Context, contaboM
 
 			if remaining > 0 {
 				log.Info("Waiting for Cluster API to drain node before cleaning up instance", "nodeName", nodeName, "remainingPods", remaining)
-				return ctrl.Result{RequeueAfter: 15 * time.Second}
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
 			}
 
 			log.Info("Node is cordoned and drained, proceeding with instance cleanup", "nodeName", nodeName)
@@ -1094,19 +1865,67 @@ func (r *ContaboMachineReconciler) reconcileDelete(ctx context.Context, contaboM
 			"instanceID", instance.InstanceId)
 	}
 
+	// The contract is cancelled before the reset, while the instance still has the display name of the machine so
+	// that a failure is retried on the next reconciliation
+	cancelled := cancelsInstance(contaboMachine)
+	if cancelled {
+		if err := r.cancelInstanceContract(ctx, contaboMachine, instance.InstanceId); err != nil {
+			log.Error(err, "Failed to cancel instance during deletion",
+				"instanceID", instance.InstanceId)
+			r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.InstanceContractCancelFailedReason, err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceContractCancelFailedReason,
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
+		}
+	}
+
 	// Reset the instance by removing it from any private networks
 	if err := r.resetInstance(ctx, contaboMachine, instance, nil); err != nil {
 		log.Error(err, "Failed to reset instance during deletion",
 			"instanceID", instance.InstanceId)
+		// The instance still has the display name of the machine, it is found again on the next reconciliation
+		if errors.Is(err, ErrTransientAPIFailure) {
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
+		}
+	} else if !cancelled && instance.ErrorMessage == nil && releasedDisplayName(contaboMachine) == "" {
+		// Instances with an error message are renamed for investigation instead of being reused, the parked
+		// instances are reserved for their ContaboMachinePool
+		r.returnInstanceToFreePool(ctx, contaboMachine, instance.InstanceId)
 	}
 
 	// Remove finalizer
-	controllerutil.RemoveFinalizer(contaboMachine, infrastructurev1beta2.MachineFinalizer)
+	removeFinalizer(contaboMachine, infrastructurev1beta2.MachineFinalizer)
 	log.Info("Removed finalizer from ContaboMachine")
 
 	return ctrl.Result{}
 }
 
+// convertInstanceStatus converts an OAPI instance status, statuses unknown to the CRD enum are reported as other
+func convertInstanceStatus(status models.InstanceStatus) infrastructurev1beta2.InstanceStatus {
+	switch infrastructurev1beta2.InstanceStatus(status) {
+	case infrastructurev1beta2.InstanceStatusError,
+		infrastructurev1beta2.InstanceStatusInstalling,
+		infrastructurev1beta2.InstanceStatusManualProvisioning,
+		infrastructurev1beta2.InstanceStatusOther,
+		infrastructurev1beta2.InstanceStatusPendingPayment,
+		infrastructurev1beta2.InstanceStatusProductNotAvailable,
+		infrastructurev1beta2.InstanceStatusProvisioning,
+		infrastructurev1beta2.InstanceStatusRescue,
+		infrastructurev1beta2.InstanceStatusResetPassword,
+		infrastructurev1beta2.InstanceStatusRunning,
+		infrastructurev1beta2.InstanceStatusStopped,
+		infrastructurev1beta2.InstanceStatusUninstalled,
+		infrastructurev1beta2.InstanceStatusUnknown,
+		infrastructurev1beta2.InstanceStatusVerificationRequired:
+		return infrastructurev1beta2.InstanceStatus(status)
+	}
+	return infrastructurev1beta2.InstanceStatusOther
+}
+
 // Convert OAPI Instance models to CAPC Instance models
 func convertListInstanceResponseData(instanceList *models.ListInstancesResponseData) *infrastructurev1beta2.ContaboInstanceStatus {
 	if instanceList == nil {
@@ -1151,7 +1970,7 @@ func convertListInstanceResponseData(instanceList *models.ListInstancesResponseD
 
 	productType := infrastructurev1beta2.InstanceResponseProductType(string(instanceList.ProductType))
 	tenantId := infrastructurev1beta2.InstanceResponseTenantId(string(instanceList.TenantId))
-	status := infrastructurev1beta2.InstanceStatus(string(instanceList.Status))
+	status := convertInstanceStatus(instanceList.Status)
 	var cancelDate *string
 	if instanceList.CancelDate != nil {
 		cancelDate = ptr.To(instanceList.CancelDate.Format(time.RFC3339))
@@ -1238,7 +2057,7 @@ func convertInstanceResponseData(instanceList *models.InstanceResponse) *infrast
 
 	productType := infrastructurev1beta2.InstanceResponseProductType(string(instanceList.ProductType))
 	tenantId := infrastructurev1beta2.InstanceResponseTenantId(string(instanceList.TenantId))
-	status := infrastructurev1beta2.InstanceStatus(string(instanceList.Status))
+	status := convertInstanceStatus(instanceList.Status)
 	var cancelDate *string
 	if instanceList.CancelDate != nil {
 		cancelDate = ptr.To(instanceList.CancelDate.Format(time.RFC3339))
@@ -1288,22 +2107,16 @@ func (r *ContaboMachineReconciler) runMachineInstanceSshCommand(ctx context.Cont
 	sshKeySecret := &corev1.Secret{}
 	sshKeySecretMetadata := client.ObjectKey{
 		Namespace: contaboMachine.Namespace,
-		Name:      FormatSshKeyKubernetesName(contaboCluster),
+		Name:      machineSSHKeySecretName(contaboMachine, contaboCluster),
 	}
 	if err := r.Get(ctx, sshKeySecretMetadata, sshKeySecret); err != nil {
 		return "", ctrl.Result{}, fmt.Errorf("failed to get SSH private key secret %s/%s: %v", sshKeySecretMetadata.Namespace, sshKeySecretMetadata.Name, err)
 	}
 	// Connect to the instance via SSH and wait for cloud-init to finish
 	// We try to connect every 10 seconds for up to 15 minutes
-	sshPrivateKey, ok := sshKeySecret.Data["id_rsa"]
-	if !ok || len(sshPrivateKey) == 0 {
-		return "", ctrl.Result{}, fmt.Errorf("SSH private key secret is missing 'id_rsa' key or is empty")
-	}
-
-	// Also get the public key from secret for verification
-	sshPublicKeyFromSecret, ok := sshKeySecret.Data["id_rsa.pub"]
-	if !ok || len(sshPublicKeyFromSecret) == 0 {
-		return "", ctrl.Result{}, fmt.Errorf("SSH public key secret is missing 'id_rsa.pub' key or is empty")
+	sshPrivateKey, sshPublicKeyFromSecret, err := sshKeyPairFromSecret(sshKeySecret)
+	if err != nil {
+		return "", ctrl.Result{}, err
 	}
 
 	log.Info("Retrieved SSH keys from secret",
@@ -1312,7 +2125,11 @@ func (r *ContaboMachineReconciler) runMachineInstanceSshCommand(ctx context.Cont
 		"secretName", sshKeySecretMetadata.Name)
 	// SSH client configuration
 
-	host := contaboMachine.Status.Instance.IpConfig.V4.Ip
+	host := machineInstanceSshHost(contaboMachine)
+	if host == "" {
+		log.Info("Waiting for the private IPv4 of the private-only instance to connect through the bastion")
+		return "", ctrl.Result{RequeueAfter: r.Settings.SshInterval()}, nil
+	}
 	user := string(infrastructurev1beta2.InstanceResponseDefaultUserAdmin)
 	if contaboMachine.Status.Instance.DefaultUser != nil {
 		user = string(*contaboMachine.Status.Instance.DefaultUser)
@@ -1341,10 +2158,26 @@ func (r *ContaboMachineReconciler) runMachineInstanceSshCommand(ctx context.Cont
 			ssh.PublicKeys(signer),
 		},
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         10 * time.Second,
+		Timeout:         r.Settings.SshDialTimeout(),
 	}
 
-	sshClient, err = ssh.Dial("tcp", net.JoinHostPort(host, "22"), config)
+	if contaboMachine.Spec.PrivateOnly != nil {
+		// Private-only instances are reached on their private IPv4 through the bastion
+		bastionClient, err := r.dialBastion(ctx, contaboMachine, signer)
+		if err != nil {
+			log.Info("SSH connection to the bastion failed, will retry",
+				"bastion", contaboMachine.Spec.PrivateOnly.Bastion.Host,
+				"error", err.Error(),
+			)
+			return "", ctrl.Result{RequeueAfter: r.Settings.SshInterval()}, fmt.Errorf("failed to connect to bastion %s: %w", contaboMachine.Spec.PrivateOnly.Bastion.Host, err)
+		}
+		defer func() {
+			_ = bastionClient.Close()
+		}()
+		sshClient, err = dialThroughBastion(bastionClient, net.JoinHostPort(host, "22"), config)
+	} else {
+		sshClient, err = ssh.Dial("tcp", net.JoinHostPort(host, "22"), config)
+	}
 	if err != nil {
 		// Check for no supported methods remain - this usually means wrong SSH keys
 		if strings.Contains(err.Error(), "no supported methods remain") {
@@ -1352,21 +2185,21 @@ func (r *ContaboMachineReconciler) runMachineInstanceSshCommand(ctx context.Cont
 				"host", host,
 				"user", user,
 				"instanceID", contaboMachine.Status.Instance.InstanceId,
-				"clusterSSHKeyID", contaboCluster.Status.SshKey.SecretId,
+				"sshKeyID", machineSSHKeyID(contaboMachine, contaboCluster),
 				"instanceSSHKeys", contaboMachine.Status.Instance.SshKeys)
 
 			// Update keys
 			_, err := r.ContaboClient.ResetPasswordAction(ctx, contaboMachine.Status.Instance.InstanceId, nil, models.InstancesResetPasswordActionsRequest{
-				SshKeys: &[]int64{contaboCluster.Status.SshKey.SecretId},
+				SshKeys: &[]int64{machineSSHKeyID(contaboMachine, contaboCluster)},
 			})
 			if err != nil {
 				log.Error(err, "Failed to update instance SSH keys via Contabo API",
 					"instanceID", contaboMachine.Status.Instance.InstanceId,
-					"sshKeyID", contaboCluster.Status.SshKey.SecretId)
+					"sshKeyID", machineSSHKeyID(contaboMachine, contaboCluster))
 			}
 
 			// Return specific error to trigger reinstall
-			return "", ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+			return "", ctrl.Result{RequeueAfter: r.Settings.SshInterval()}, nil
 		}
 		// Provide more specific error information for better requeue handling
 		log.Info("SSH connection failed, will retry",
@@ -1374,7 +2207,7 @@ func (r *ContaboMachineReconciler) runMachineInstanceSshCommand(ctx context.Cont
 			"user", user,
 			"error", err.Error(),
 		)
-		return "", ctrl.Result{RequeueAfter: 15 * time.Second}, err
+		return "", ctrl.Result{RequeueAfter: r.Settings.SshInterval()}, err
 	}
 	defer func() {
 		if closeErr := sshClient.Close(); closeErr != nil {
@@ -1425,11 +2258,18 @@ func (r *ContaboMachineReconciler) handleError(ctx context.Context, contaboMachi
 func (r *ContaboMachineReconciler) resetInstance(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, instance *infrastructurev1beta2.ContaboInstanceStatus, errorMessage *string) error {
 	log := logf.FromContext(ctx)
 
+	// Reinstalling the node running the controller manager would stop the reset halfway
+	if instance != nil && isManagerMachine(contaboMachine, r.ManagerNodeName) {
+		return fmt.Errorf("refusing to reset instance %d running the controller manager", instance.InstanceId)
+	}
+
 	// Remove Instance from Status
+	tags := contaboMachine.Status.Tags
 	contaboMachine.Status = infrastructurev1beta2.ContaboMachineStatus{}
 
-	// Remove ProviderID
+	// Remove ProviderID and the failure domain the instance landed in
 	contaboMachine.Spec.ProviderID = nil
+	contaboMachine.Spec.FailureDomain = nil
 
 	hasErrorMessage := errorMessage != nil || (instance != nil && instance.ErrorMessage != nil)
 
@@ -1439,17 +2279,24 @@ func (r *ContaboMachineReconciler) resetInstance(ctx context.Context, contaboMac
 		return nil
 	}
 
+	// The tags of the machine do not follow the instance to its next machine
+	r.unassignTags(ctx, instance.InstanceId, tags)
+
 	// Set error on contabo instance displayName
-	displayName := ""
+	displayName := releasedDisplayName(contaboMachine)
 	if errorMessage != nil {
 		displayName = Truncate(fmt.Sprintf("[capc] %d %s", instance.InstanceId, *errorMessage), 255) // Contabo display name max length is 255 characters
 	} else if instance.ErrorMessage != nil {
 		displayName = Truncate(fmt.Sprintf("[capc] %d %s", instance.InstanceId, *instance.ErrorMessage), 255) // Contabo display name max length is 255 characters
 	}
-	_, err := r.ContaboClient.PatchInstanceWithResponse(ctx, instance.InstanceId, nil, models.PatchInstanceRequest{
+	patchResp, err := r.patchInstance(ctx, contaboMachine, instance.InstanceId, instance, models.PatchInstanceRequest{
 		DisplayName: &displayName,
-	})
-	if err != nil {
+	}, "release the instance from the machine")
+	var apiErr *contabo.APIError
+	if err := contabo.CheckResponse(patchResp, err); errors.As(err, &apiErr) && apiErr.Transient() {
+		// The instance keeps the display name of the machine, the reset must be retried
+		return fmt.Errorf("%w: failed to update display name of instance %d: %w", ErrTransientAPIFailure, instance.InstanceId, err)
+	} else if err != nil {
 		log.Error(err, "Failed to update instance display name to avoid reuse",
 			"instanceID", instance.InstanceId,
 			"newDisplayName", displayName)
@@ -1477,7 +2324,7 @@ func (r *ContaboMachineReconciler) resetInstance(ctx context.Context, contaboMac
 						Size: ptr.To(int64(100)),
 					})
 					// If there was an error or no more private networks, break the loop
-					if err != nil || privateNetworksResp.StatusCode() < 200 || privateNetworksResp.StatusCode() >= 300 || len(privateNetworksResp.JSON200.Data) == 0 {
+					if err := contabo.CheckResponse(privateNetworksResp, err); err != nil || len(privateNetworksResp.JSON200.Data) == 0 {
 						break
 					}
 					// If we successfully retrieved the private networks, check if instance is part of any
@@ -1487,15 +2334,13 @@ func (r *ContaboMachineReconciler) resetInstance(ctx context.Context, contaboMac
 								log.Info("Found instance in private network, unassigning",
 									"instanceID", instance.InstanceId,
 									"networkID", network.PrivateNetworkId)
-								unassignResp, err := r.ContaboClient.UnassignInstancePrivateNetworkWithResponse(ctx, network.PrivateNetworkId, instance.InstanceId, nil)
-								if err == nil && unassignResp.StatusCode() >= 200 && unassignResp.StatusCode() < 300 {
-									log.Info("Successfully unassigned private network from instance",
+								// Leftovers are removed by the periodic private network reconciliation of the cluster
+								if err := unassignPrivateNetwork(ctx, r.ContaboClient, network.PrivateNetworkId, instance.InstanceId); err != nil {
+									log.Error(err, "Failed to unassign private network from instance",
 										"instanceID", instance.InstanceId,
 										"networkID", network.PrivateNetworkId)
-									time.Sleep(1 * time.Second)
-
 								} else {
-									log.Error(err, "Failed to unassign private network from instance",
+									log.Info("Successfully unassigned private network from instance",
 										"instanceID", instance.InstanceId,
 										"networkID", network.PrivateNetworkId)
 								}
@@ -1513,10 +2358,10 @@ func (r *ContaboMachineReconciler) resetInstance(ctx context.Context, contaboMac
 
 	// Retrieve SSH key from ContaboCluster to keep access after reinstall
 	// Reinstall to clear any residual configuration
-	_, err = r.ContaboClient.ReinstallInstance(ctx, instance.InstanceId, &models.ReinstallInstanceParams{}, models.ReinstallInstanceRequest{
+	_, err = r.reinstallInstance(ctx, contaboMachine, instance, models.ReinstallInstanceRequest{
 		ImageId:     DefaultUbuntuImageID,
 		DefaultUser: ptr.To(models.ReinstallInstanceRequestDefaultUserAdmin),
-	})
+	}, "reset the released instance")
 	if err != nil {
 		log.Error(err, "Failed to reinstall instance to reset configuration",
 			"instanceID", instance.InstanceId)
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// DefaultControlPlaneGangTimeout is the default time the first control plane machine waits for the instances of the
+// control plane quorum
+const DefaultControlPlaneGangTimeout = 30 * time.Minute
+
+// controlPlaneGangTimeout returns the time the first control plane machine waits for the instances of the quorum
+func controlPlaneGangTimeout(gang *infrastructurev1beta2.ContaboControlPlaneGangSpec) time.Duration {
+	if gang.Timeout != nil && gang.Timeout.Duration > 0 {
+		return gang.Timeout.Duration
+	}
+	return DefaultControlPlaneGangTimeout
+}
+
+// controlPlaneGangReplicas returns the number of control plane machines of the quorum
+func controlPlaneGangReplicas(gang *infrastructurev1beta2.ContaboControlPlaneGangSpec) int32 {
+	if gang.Replicas > 0 {
+		return gang.Replicas
+	}
+	return 3
+}
+
+// orderControlPlaneGang orders the instances of the other control plane machines of the quorum along with the
+// instance of the first control plane machine of a new cluster, as Cluster API only creates the next control plane
+// machines once the first one is initialized. The instances are named after the control plane machine index they are
+// ordered for, so that the next control plane machines find them by display name. Failures are only logged and
+// retried on the next reconciliation, the missing instances are otherwise ordered by their machine.
+func (r *ContaboMachineReconciler) orderControlPlaneGang(ctx context.Context, machine *clusterv1.Machine, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	gang := contaboCluster.Spec.ControlPlaneGang
+	status := contaboMachine.Status.ControlPlaneGang
+	if gang == nil || !util.IsControlPlaneMachine(machine) || ptr.Deref(contaboMachine.Spec.Index, -1) != 0 ||
+		(status != nil && status.CompletionTime != nil) {
+		return
+	}
+	// Named instances are claimed, not ordered
+	if ptr.Deref(contaboMachine.Spec.Instance.ProvisioningType, "") != infrastructurev1beta2.ContaboInstanceProvisioningTypeReuseOrCreate ||
+		ptr.Deref(contaboMachine.Spec.Instance.Name, "") != "" {
+		return
+	}
+	// Only the quorum of a new cluster is ordered, the control plane of an initialized cluster is replaced one machine
+	// at a time by Cluster API
+	if status == nil {
+		cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
+		if err != nil {
+			log.Info("Failed to get the cluster of the control plane quorum", "error", err.Error())
+			return
+		}
+		if ptr.Deref(cluster.Status.Initialization.ControlPlaneInitialized, false) {
+			return
+		}
+		status = &infrastructurev1beta2.ContaboControlPlaneGangStatus{StartTime: metav1.Now()}
+		contaboMachine.Status.ControlPlaneGang = status
+	}
+
+	// Never order an instance once clusterctl move paused the cluster, the order would be lost with the move
+	if paused, err := r.pausedForMove(ctx, contaboMachine); err != nil || paused {
+		return
+	}
+
+	r.instanceReuseMutex.Lock()
+	defer r.instanceReuseMutex.Unlock()
+
+	for index := int32(1); index < controlPlaneGangReplicas(gang); index++ {
+		i := slices.IndexFunc(status.Orders, func(order infrastructurev1beta2.ContaboControlPlaneGangOrder) bool {
+			return order.Index == index
+		})
+		if i >= 0 && status.Orders[i].InstanceId != 0 {
+			continue
+		}
+
+		// The instance is ordered as if for the control plane machine of the index
+		member := contaboMachine.DeepCopy()
+		member.Spec.Index = ptr.To(index)
+		member.Status.InstanceOrder = nil
+		order := infrastructurev1beta2.ContaboControlPlaneGangOrder{Index: index, DisplayName: FormatDisplayName(member, contaboCluster)}
+
+		// A previous order whose response was lost is found by display name
+		instance, err := r.findInstanceByDisplayName(ctx, order.DisplayName)
+		if err != nil {
+			log.Info("Failed to look up the instance of the control plane quorum", "index", index, "error", err.Error())
+			return
+		}
+		if instance == nil {
+			key := types.NamespacedName{Namespace: contaboMachine.Namespace, Name: order.DisplayName}
+			if err := checkQuota(ctx, r.Client, &r.quotaReservations, key, instanceQuotaRequest(member)); err != nil {
+				log.Info("Waiting for quota to order the instance of the control plane quorum", "index", index, "reason", err.Error())
+				return
+			}
+			instance, err = r.createNewInstance(ctx, member, contaboCluster)
+			if err != nil {
+				log.Info("Failed to order the instance of the control plane quorum", "index", index, "error", err.Error())
+				if !errors.Is(err, ErrTransientAPIFailure) {
+					r.Recorder.Eventf(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.ControlPlaneGangOrderFailedReason,
+						"Failed to order the instance of control plane machine %d: %s", index, Truncate(err.Error(), 512))
+				}
+				return
+			}
+		}
+		if instance != nil {
+			order.InstanceId = instance.InstanceId
+		} else if member.Status.InstanceOrder != nil {
+			order.InstanceId = member.Status.InstanceOrder.InstanceId
+		}
+		log.Info("Ordered the instance of the control plane quorum", "index", index, "instanceID", order.InstanceId, "displayName", order.DisplayName)
+		if i >= 0 {
+			status.Orders[i] = order
+		} else {
+			status.Orders = append(status.Orders, order)
+		}
+	}
+}
+
+// waitForControlPlaneGang returns true while the first control plane machine waits for the instances of the other
+// control plane machines of the quorum to exist and leave provisioning, or for the timeout of the gang to expire.
+func (r *ContaboMachineReconciler) waitForControlPlaneGang(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, bool) {
+	log := logf.FromContext(ctx)
+
+	gang := contaboCluster.Spec.ControlPlaneGang
+	status := contaboMachine.Status.ControlPlaneGang
+	if gang == nil || status == nil || status.CompletionTime != nil {
+		return ctrl.Result{}, false
+	}
+
+	missing := []string{}
+	for index := int32(1); index < controlPlaneGangReplicas(gang); index++ {
+		i := slices.IndexFunc(status.Orders, func(order infrastructurev1beta2.ContaboControlPlaneGangOrder) bool {
+			return order.Index == index
+		})
+		if i < 0 || status.Orders[i].InstanceId == 0 {
+			missing = append(missing, strconv.Itoa(int(index)))
+			continue
+		}
+		instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, status.Orders[i].InstanceId, nil)
+		if err != nil || instanceResp.JSON200 == nil || len(instanceResp.JSON200.Data) == 0 ||
+			instanceOrderPending(convertInstanceStatus(instanceResp.JSON200.Data[0].Status)) {
+			missing = append(missing, strconv.Itoa(int(index)))
+		}
+	}
+
+	now := metav1.Now()
+	switch {
+	case len(missing) == 0:
+		log.Info("All the instances of the control plane quorum exist, bootstrapping the first control plane machine")
+		status.CompletionTime = &now
+		return ctrl.Result{}, false
+	case now.Sub(status.StartTime.Time) >= controlPlaneGangTimeout(gang):
+		message := fmt.Sprintf("The instances of control plane machines %s did not exist within %s, bootstrapping the first control plane machine",
+			strings.Join(missing, ", "), controlPlaneGangTimeout(gang))
+		log.Info(message)
+		r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.ControlPlaneGangTimedOutReason, message)
+		status.CompletionTime = &now
+		return ctrl.Result{}, false
+	}
+
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.InstanceWaitingForControlPlaneGangReason,
+		Message: fmt.Sprintf("Waiting for the instances of control plane machines %s of the quorum before bootstrapping", strings.Join(missing, ", ")),
+	})
+	return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// DefaultFirstBootProbeTimeout is used when the probe timeout is not set
+	DefaultFirstBootProbeTimeout = 15 * time.Minute
+
+	// firstBootProbeCommand reports sshd and cloud-init health on the instance
+	firstBootProbeCommand = "if systemctl is-active --quiet ssh || systemctl is-active --quiet sshd; then echo 'sshd: active'; else echo 'sshd: inactive'; fi; cloud-init status"
+)
+
+// firstBootProbeState is the outcome of a single first-boot probe run
+type firstBootProbeState int
+
+const (
+	firstBootProbePending firstBootProbeState = iota
+	firstBootProbeHealthy
+	firstBootProbeFailed
+)
+
+// evaluateFirstBootProbeOutput interprets the output of firstBootProbeCommand
+func evaluateFirstBootProbeOutput(output string) firstBootProbeState {
+	if strings.Contains(output, "status: error") {
+		return firstBootProbeFailed
+	}
+	if strings.Contains(output, "sshd: active") && strings.Contains(output, "status: done") {
+		return firstBootProbeHealthy
+	}
+	return firstBootProbePending
+}
+
+// reconcileFirstBootProbe verifies sshd and cloud-init health over SSH before the machine is marked available
+func (r *ContaboMachineReconciler) reconcileFirstBootProbe(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	probe := contaboMachine.Spec.Instance.FirstBootProbe
+	if probe == nil || !probe.Enabled {
+		return ctrl.Result{}, nil
+	}
+
+	// Probe only once per instance
+	if meta.IsStatusConditionTrue(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceFirstBootProbeCondition) {
+		return ctrl.Result{}, nil
+	}
+
+	if contaboMachine.Status.FirstBootProbeStartTime == nil {
+		contaboMachine.Status.FirstBootProbeStartTime = ptr.To(metav1.Now())
+	}
+
+	// Slower products and data centers get more time according to their boot time profile, unless the machine sets
+	// the timeout
+	timeout := r.BootTimeProfiles.timeout(bootTimePhaseFirstBoot, contaboMachine.Status.Instance, r.Settings.FirstBootProbeTimeout())
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+
+	state := firstBootProbePending
+	output, result, err := r.runMachineInstanceSshCommand(ctx, contaboMachine, contaboCluster, firstBootProbeCommand)
+	if err != nil && result.RequeueAfter == 0 {
+		// Not a connectivity issue (e.g. missing SSH key secret), surface it
+		return result, err
+	}
+	if err == nil && result.RequeueAfter == 0 {
+		state = evaluateFirstBootProbeOutput(output)
+	}
+
+	elapsed := time.Since(contaboMachine.Status.FirstBootProbeStartTime.Time)
+	if state == firstBootProbePending && elapsed > timeout {
+		state = firstBootProbeFailed
+		output = fmt.Sprintf("sshd and cloud-init not healthy after %s", timeout)
+	}
+
+	switch state {
+	case firstBootProbeHealthy:
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:   infrastructurev1beta2.InstanceFirstBootProbeCondition,
+			Status: metav1.ConditionTrue,
+			Reason: infrastructurev1beta2.InstanceFirstBootProbeSucceededReason,
+		})
+		log.Info("First-boot probe succeeded", "instanceID", contaboMachine.Status.Instance.InstanceId, "elapsed", elapsed.Round(time.Second).String())
+		r.BootTimeProfiles.observe(bootTimePhaseFirstBoot, contaboMachine.Status.Instance, elapsed)
+		return ctrl.Result{}, nil
+
+	case firstBootProbeFailed:
+		message := "first-boot probe failed: " + strings.TrimSpace(output)
+		probeErr := errors.New(message)
+		log.Error(probeErr, "First-boot probe failed, resetting instance", "instanceID", contaboMachine.Status.Instance.InstanceId)
+		// resetInstance clears the machine status, including the probe start time
+		if err := r.resetInstance(ctx, contaboMachine, contaboMachine.Status.Instance, &message); err != nil {
+			log.Error(err, "Failed to reset instance after first-boot probe failure")
+		}
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceFirstBootProbeCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstanceFirstBootProbeFailedReason,
+			Message: message,
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, probeErr
+
+	default:
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceFirstBootProbeCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstanceFirstBootProbePendingReason,
+			Message: fmt.Sprintf("Waiting for sshd and cloud-init, timeout in %s", (timeout - elapsed).Round(time.Second)),
+		})
+		log.Info("First-boot probe pending, will retry", "instanceID", contaboMachine.Status.Instance.InstanceId, "requeueAfter", "20s")
+		return ctrl.Result{RequeueAfter: r.Settings.CloudInitInterval()}, nil
+	}
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+)
+
+const (
+	// DefaultHostInterval is the default interval between two checks of the host system of an instance
+	DefaultHostInterval = 10 * time.Minute
+
+	// HostChangeWindow is the time the InstanceHostStable condition stays false after the instance moved host system
+	HostChangeWindow = 24 * time.Hour
+)
+
+// observeHost records the host system the instance was retrieved on. It returns true when the instance moved to
+// another host system, the first observation of an instance is recorded without reporting a change.
+func observeHost(host *infrastructurev1beta2.ContaboMachineHostStatus, instanceId int64, vHostId int64, vHostName string, now metav1.Time) (*infrastructurev1beta2.ContaboMachineHostStatus, bool) {
+	if host == nil || host.InstanceId != instanceId {
+		return &infrastructurev1beta2.ContaboMachineHostStatus{
+			InstanceId:  instanceId,
+			VHostId:     vHostId,
+			VHostName:   vHostName,
+			LastChecked: &now,
+		}, false
+	}
+
+	host = host.DeepCopy()
+	host.LastChecked = &now
+	if host.VHostId == vHostId {
+		host.VHostName = vHostName
+		return host, false
+	}
+	host.PreviousVHostId = ptr.To(host.VHostId)
+	host.PreviousVHostName = host.VHostName
+	host.VHostId = vHostId
+	host.VHostName = vHostName
+	host.Changes++
+	host.LastChangeTime = &now
+	return host, true
+}
+
+// hostDisplayName returns the name of the host system, its ID when Contabo returns no name
+func hostDisplayName(vHostId int64, vHostName string) string {
+	if vHostName != "" {
+		return vHostName
+	}
+	return fmt.Sprintf("%d", vHostId)
+}
+
+// reconcileHost tracks the host system the instance runs on, the vHostId returned by the Contabo API. When Contabo
+// moves the instance to another host, e.g. after a hardware failure, an event is emitted and the InstanceHostStable
+// condition is false for the HostChangeWindow, as it often explains reboots or performance changes.
+// Failures are only logged as the host system is informational.
+func (r *ContaboMachineReconciler) reconcileHost(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	log := logf.FromContext(ctx)
+
+	instance := contaboMachine.Status.Instance
+	if instance == nil {
+		return
+	}
+	host := contaboMachine.Status.Host
+	if host != nil && host.InstanceId == instance.InstanceId && host.LastChecked != nil &&
+		time.Since(host.LastChecked.Time) < r.Settings.HostInterval() {
+		return
+	}
+
+	instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, instance.InstanceId, nil)
+	if err := contabo.CheckResponse(instanceResp, err); err != nil || len(instanceResp.JSON200.Data) == 0 {
+		log.Info("Failed to retrieve instance host system", "instanceID", instance.InstanceId, "error", err)
+		return
+	}
+	data := instanceResp.JSON200.Data[0]
+	instance.VHostId = data.VHostId
+	instance.VHostName = data.VHostName
+	instance.VHostNumber = data.VHostNumber
+
+	host, changed := observeHost(host, instance.InstanceId, data.VHostId, data.VHostName, metav1.Now())
+	contaboMachine.Status.Host = host
+	if changed {
+		message := fmt.Sprintf("Instance %d moved from host %s to host %s",
+			instance.InstanceId,
+			hostDisplayName(ptr.Deref(host.PreviousVHostId, 0), host.PreviousVHostName),
+			hostDisplayName(host.VHostId, host.VHostName))
+		log.Info(message, "previousVHostId", ptr.Deref(host.PreviousVHostId, 0), "vHostId", host.VHostId)
+		r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.InstanceHostChangedReason, message)
+	}
+
+	if host.LastChangeTime != nil && time.Since(host.LastChangeTime.Time) < HostChangeWindow {
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:   infrastructurev1beta2.InstanceHostStableCondition,
+			Status: metav1.ConditionFalse,
+			Reason: infrastructurev1beta2.InstanceHostChangedReason,
+			Message: fmt.Sprintf("Instance moved from host %s to host %s at %s",
+				hostDisplayName(ptr.Deref(host.PreviousVHostId, 0), host.PreviousVHostName),
+				hostDisplayName(host.VHostId, host.VHostName),
+				host.LastChangeTime.UTC().Format(time.RFC3339)),
+		})
+		return
+	}
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceHostStableCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.InstanceHostUnchangedReason,
+		Message: fmt.Sprintf("Instance runs on host %s", hostDisplayName(host.VHostId, host.VHostName)),
+	})
+}
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// DefaultInstanceOrderTimeout is the time an ordered instance has to appear and leave provisioning
+	DefaultInstanceOrderTimeout = 30 * time.Minute
+
+	// MaxInstanceOrderRecreations is the number of replacement orders before the machine is marked as failed
+	MaxInstanceOrderRecreations = 3
+)
+
+// instanceOrderPending returns true while an ordered instance is still being provisioned by Contabo
+func instanceOrderPending(status infrastructurev1beta2.InstanceStatus) bool {
+	switch status {
+	case infrastructurev1beta2.InstanceStatusProvisioning,
+		infrastructurev1beta2.InstanceStatusPendingPayment,
+		infrastructurev1beta2.InstanceStatusUnknown:
+		return true
+	}
+	return false
+}
+
+// instanceOrderExpired returns true when the ordered instance had more than timeout to appear
+func instanceOrderExpired(order *infrastructurev1beta2.ContaboInstanceOrderStatus, now time.Time, timeout time.Duration) bool {
+	if order == nil || order.OrderTime == nil {
+		return false
+	}
+	return now.Sub(order.OrderTime.Time) >= timeout
+}
+
+// summarizeInstanceAudits describes the audit entries of an ordered instance, newest first
+func summarizeInstanceAudits(audits []models.InstancesAuditResponse) string {
+	if len(audits) == 0 {
+		return "no audit entry"
+	}
+	latest := audits[0]
+	return fmt.Sprintf("%d audit entries, latest %s at %s", len(audits), latest.Action, latest.Timestamp.UTC().Format(time.RFC3339))
+}
+
+// reconcileInstanceOrder waits for the instance ordered by createNewInstance to appear and leave provisioning.
+// Contabo may accept an order whose instance never shows up or stays provisioning: past the InstanceOrderTimeout
+// the audits of the instance are checked, the order is cancelled and a replacement is ordered.
+// It returns handled=true while the reconciliation must not go further.
+func (r *ContaboMachineReconciler) reconcileInstanceOrder(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) (ctrl.Result, bool, error) {
+	log := logf.FromContext(ctx)
+
+	order := contaboMachine.Status.InstanceOrder
+	if order == nil || order.InstanceId == 0 {
+		return ctrl.Result{}, false, nil
+	}
+
+	var instance *infrastructurev1beta2.ContaboInstanceStatus
+	instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, order.InstanceId, nil)
+	if err == nil && instanceResp.JSON200 != nil && len(instanceResp.JSON200.Data) > 0 {
+		instance = convertInstanceResponseData(&instanceResp.JSON200.Data[0])
+	}
+
+	if instance != nil && !instanceOrderPending(instance.Status) {
+		log.Info("Ordered instance is available", "instanceID", order.InstanceId, "status", instance.Status)
+		if order.OrderTime != nil {
+			r.BootTimeProfiles.observe(bootTimePhaseOrder, instance, time.Since(order.OrderTime.Time))
+		}
+		if contaboMachine.Status.Instance == nil {
+			contaboMachine.Status.Instance = instance
+		}
+		contaboMachine.Status.InstanceOrder = nil
+		return ctrl.Result{}, false, nil
+	}
+
+	// Slower products and data centers get more time according to their boot time profile
+	timeout := r.BootTimeProfiles.timeout(bootTimePhaseOrder, instance, r.Settings.InstanceOrderTimeout())
+	if !instanceOrderExpired(order, time.Now(), timeout) {
+		message := fmt.Sprintf("Instance %d ordered at %s is not available yet", order.InstanceId, order.OrderTime.UTC().Format(time.RFC3339))
+		if instance != nil {
+			message = fmt.Sprintf("Instance %d ordered at %s is %s", order.InstanceId, order.OrderTime.UTC().Format(time.RFC3339), instance.Status)
+		}
+		log.Info(message)
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstanceOrderPendingReason,
+			Message: message,
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+	}
+
+	r.recreateInstanceOrder(ctx, contaboMachine, instance, timeout)
+	if contaboMachine.Status.FailureReason != nil {
+		return ctrl.Result{}, true, nil
+	}
+	return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+}
+
+// recreateInstanceOrder gives up an instance order which timed out: the audits of the instance are recorded in an
+// event, the order is cancelled, the instance renamed so it is never picked by display name, and a replacement is
+// ordered on the next reconciliation. Cancellation failures are reported but do not block the replacement.
+func (r *ContaboMachineReconciler) recreateInstanceOrder(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, instance *infrastructurev1beta2.ContaboInstanceStatus, timeout time.Duration) {
+	log := logf.FromContext(ctx)
+	order := contaboMachine.Status.InstanceOrder
+
+	state := "never appeared"
+	if instance != nil {
+		state = fmt.Sprintf("is still %s", instance.Status)
+	}
+	audits := "audits unavailable"
+	auditsResp, err := r.ContaboClient.RetrieveInstancesAuditsListWithResponse(ctx, &models.RetrieveInstancesAuditsListParams{
+		InstanceId: &order.InstanceId,
+		OrderBy:    &[]string{"timestamp:DESC"},
+		Size:       ptr.To(int64(AuditTrailMaxEntries)),
+	})
+	if err == nil && auditsResp.JSON200 != nil {
+		audits = summarizeInstanceAudits(auditsResp.JSON200.Data)
+	}
+	message := fmt.Sprintf("Instance %d ordered at %s %s after %s (%s)", order.InstanceId, order.OrderTime.UTC().Format(time.RFC3339), state, timeout, audits)
+	log.Info(message)
+	r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.InstanceOrderTimeoutReason, message)
+
+	cancelResp, err := r.ContaboClient.CancelInstanceWithResponse(ctx, order.InstanceId, contabo.NewParams[models.CancelInstanceParams](ctx), models.CancelInstanceRequest{})
+	if err := contabo.CheckResponse(cancelResp, err); err != nil {
+		cancelMessage := fmt.Sprintf("Failed to cancel the order of instance %d: %s", order.InstanceId, err)
+		log.Info(cancelMessage)
+		r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.InstanceOrderTimeoutReason, cancelMessage)
+	} else {
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.InstanceOrderTimeoutReason, "Cancelled the order of instance %d", order.InstanceId)
+	}
+
+	// Rename the instance in case it shows up later, the replacement uses the same display name
+	if instance != nil {
+		displayName := Truncate(fmt.Sprintf("[capc] %d order timed out", order.InstanceId), 255) // Contabo display name max length is 255 characters
+		if _, err := r.patchInstance(ctx, contaboMachine, order.InstanceId, instance, models.PatchInstanceRequest{
+			DisplayName: &displayName,
+		}, "keep the timed out order from being reused"); err != nil {
+			log.Error(err, "Failed to update instance display name to avoid reuse", "instanceID", order.InstanceId)
+		}
+	}
+
+	contaboMachine.Status.Instance = nil
+	if order.Recreations >= MaxInstanceOrderRecreations {
+		failureMessage := fmt.Sprintf("Instance orders timed out %d times, last instance %d", order.Recreations+1, order.InstanceId)
+		r.setMachineFailure(contaboMachine, infrastructurev1beta2.CreateMachineError, infrastructurev1beta2.InstanceOrderFailedReason, failureMessage)
+		contaboMachine.Status.InstanceOrder = &infrastructurev1beta2.ContaboInstanceOrderStatus{Recreations: order.Recreations}
+		return
+	}
+
+	contaboMachine.Status.InstanceOrder = &infrastructurev1beta2.ContaboInstanceOrderStatus{Recreations: order.Recreations + 1}
+	recreateMessage := fmt.Sprintf("Ordering a replacement of instance %d (%d/%d)", order.InstanceId, order.Recreations+1, MaxInstanceOrderRecreations)
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.InstanceOrderRecreatedReason,
+		Message: recreateMessage,
+	})
+	r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.InstanceOrderRecreatedReason, recreateMessage)
+}
@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// DefaultLifecycleHookTimeout bounds a call of the endpoint of a lifecycle hook when the hook does not set a timeout
+const DefaultLifecycleHookTimeout = 10 * time.Second
+
+// lifecycleHookHTTPClient posts the lifecycle events to the endpoints of the hooks, the calls are bounded by the
+// timeout of their hook
+var lifecycleHookHTTPClient = &http.Client{}
+
+// LifecycleHookRequest is the lifecycle event of a ContaboMachine posted to the endpoint of a ContaboLifecycleHook
+type LifecycleHookRequest struct {
+	Source       string                                          `json:"source"`
+	Hook         string                                          `json:"hook"`
+	Point        infrastructurev1beta2.ContaboLifecycleHookPoint `json:"point"`
+	Namespace    string                                          `json:"namespace"`
+	Cluster      string                                          `json:"cluster,omitempty"`
+	Machine      string                                          `json:"machine"`
+	Labels       map[string]string                               `json:"labels,omitempty"`
+	InstanceId   int64                                           `json:"instanceId,omitempty"`
+	InstanceName string                                          `json:"instanceName,omitempty"`
+	ProviderID   string                                          `json:"providerID,omitempty"`
+	Region       string                                          `json:"region,omitempty"`
+	ProductId    string                                          `json:"productId,omitempty"`
+	Addresses    []clusterv1.MachineAddress                      `json:"addresses,omitempty"`
+	Time         time.Time                                       `json:"time"`
+}
+
+// lifecycleHookAnnotationPrefixes are the prefixes of the annotations holding the lifecycle points
+var lifecycleHookAnnotationPrefixes = map[infrastructurev1beta2.ContaboLifecycleHookPoint]string{
+	infrastructurev1beta2.ContaboLifecycleHookPointPreCreate:     infrastructurev1beta2.PreCreateHookAnnotationPrefix,
+	infrastructurev1beta2.ContaboLifecycleHookPointPostProvision: infrastructurev1beta2.PostProvisionHookAnnotationPrefix,
+	infrastructurev1beta2.ContaboLifecycleHookPointPreDelete:     infrastructurev1beta2.PreDeleteHookAnnotationPrefix,
+}
+
+// lifecycleHookAnnotations returns the sorted annotations of the machine holding the lifecycle point
+func lifecycleHookAnnotations(contaboMachine *infrastructurev1beta2.ContaboMachine, point infrastructurev1beta2.ContaboLifecycleHookPoint) []string {
+	hooks := []string{}
+	for key := range contaboMachine.Annotations {
+		if strings.HasPrefix(key, lifecycleHookAnnotationPrefixes[point]) {
+			hooks = append(hooks, key)
+		}
+	}
+	sort.Strings(hooks)
+	return hooks
+}
+
+// findLifecycleHookCall returns the call of the hook at the lifecycle point recorded in the status of the machine
+func findLifecycleHookCall(contaboMachine *infrastructurev1beta2.ContaboMachine, name string, point infrastructurev1beta2.ContaboLifecycleHookPoint) *infrastructurev1beta2.ContaboLifecycleHookCallStatus {
+	for i := range contaboMachine.Status.LifecycleHooks {
+		call := &contaboMachine.Status.LifecycleHooks[i]
+		if call.Name == name && call.Point == point {
+			return call
+		}
+	}
+	return nil
+}
+
+// reconcileLifecycleHooks runs the lifecycle hooks of the lifecycle point of the machine: the point is held while
+// an annotation with the prefix of the point is set, and the ContaboLifecycleHooks of the machine are called once per
+// point and instance, the blocking ones holding the point until they succeed. It returns true while the point is held.
+func (r *ContaboMachineReconciler) reconcileLifecycleHooks(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, point infrastructurev1beta2.ContaboLifecycleHookPoint) (ctrl.Result, bool) {
+	log := logf.FromContext(ctx)
+
+	hooks, err := r.machineLifecycleHooks(ctx, contaboMachine, point)
+	if err != nil {
+		log.Info("Failed to list the lifecycle hooks of the machine, retrying", "point", point, "error", err.Error())
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.LifecycleHooksCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.WaitingForLifecycleHookReason,
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, true
+	}
+	annotations := lifecycleHookAnnotations(contaboMachine, point)
+	if len(hooks) == 0 && len(annotations) == 0 {
+		return ctrl.Result{}, false
+	}
+
+	waiting := slices.Clone(annotations)
+	for i := range hooks {
+		if r.callLifecycleHook(ctx, contaboMachine, &hooks[i], point) {
+			waiting = append(waiting, hooks[i].Name)
+		}
+	}
+
+	if len(waiting) > 0 {
+		log.Info("Waiting for the lifecycle hooks", "point", point, "hooks", waiting)
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.LifecycleHooksCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.WaitingForLifecycleHookReason,
+			Message: fmt.Sprintf("%s held by %s", point, strings.Join(waiting, ", ")),
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, true
+	}
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.LifecycleHooksCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.LifecycleHooksCompletedReason,
+		Message: fmt.Sprintf("%s hooks completed", point),
+	})
+	return ctrl.Result{}, false
+}
+
+// machineLifecycleHooks returns the ContaboLifecycleHooks of the namespace called at the lifecycle point of the
+// machine, ordered by name
+func (r *ContaboMachineReconciler) machineLifecycleHooks(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, point infrastructurev1beta2.ContaboLifecycleHookPoint) ([]infrastructurev1beta2.ContaboLifecycleHook, error) {
+	hookList := &infrastructurev1beta2.ContaboLifecycleHookList{}
+	if err := r.List(ctx, hookList, client.InNamespace(contaboMachine.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboLifecycleHooks: %w", err)
+	}
+
+	hooks := []infrastructurev1beta2.ContaboLifecycleHook{}
+	for _, hook := range hookList.Items {
+		if !slices.Contains(hook.Spec.Points, point) ||
+			(hook.Spec.ClusterName != "" && hook.Spec.ClusterName != contaboMachine.Labels[clusterv1.ClusterNameLabel]) {
+			continue
+		}
+		if hook.Spec.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(hook.Spec.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector of ContaboLifecycleHook %s: %w", hook.Name, err)
+			}
+			if !selector.Matches(labels.Set(contaboMachine.Labels)) {
+				continue
+			}
+		}
+		hooks = append(hooks, hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Name < hooks[j].Name })
+	return hooks, nil
+}
+
+// callLifecycleHook calls the endpoint of the hook unless its call at the lifecycle point completed, and records the
+// call in the status of the machine. It returns true while the hook holds the lifecycle point.
+func (r *ContaboMachineReconciler) callLifecycleHook(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, hook *infrastructurev1beta2.ContaboLifecycleHook, point infrastructurev1beta2.ContaboLifecycleHookPoint) bool {
+	log := logf.FromContext(ctx).WithValues("hook", hook.Name, "point", point)
+
+	call := findLifecycleHookCall(contaboMachine, hook.Name, point)
+	if call == nil {
+		contaboMachine.Status.LifecycleHooks = append(contaboMachine.Status.LifecycleHooks, infrastructurev1beta2.ContaboLifecycleHookCallStatus{
+			Name:  hook.Name,
+			Point: point,
+			Phase: infrastructurev1beta2.ContaboLifecycleHookCallPhasePending,
+		})
+		call = &contaboMachine.Status.LifecycleHooks[len(contaboMachine.Status.LifecycleHooks)-1]
+	}
+	if call.Phase != infrastructurev1beta2.ContaboLifecycleHookCallPhasePending {
+		return false
+	}
+	// A pending hook is called again once per dependency interval, not on every reconciliation of the machine
+	if call.LastCallTime != nil && time.Since(call.LastCallTime.Time) < r.Settings.DependencyInterval() {
+		return true
+	}
+
+	request := lifecycleHookRequest(contaboMachine, hook.Name, point)
+	now := metav1.Now()
+	call.LastCallTime = &now
+	call.Attempts++
+
+	if !ptr.Deref(hook.Spec.Blocking, true) {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), lifecycleHookTimeout(hook))
+			defer cancel()
+			if _, err := r.postLifecycleHook(ctx, hook, request); err != nil {
+				log.Error(err, "Failed to call the non-blocking lifecycle hook")
+			}
+		}()
+		call.Phase = infrastructurev1beta2.ContaboLifecycleHookCallPhaseSent
+		call.Message = ""
+		return false
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, lifecycleHookTimeout(hook))
+	defer cancel()
+	accepted, err := r.postLifecycleHook(callCtx, hook, request)
+	switch {
+	case err == nil && accepted:
+		log.Info("Lifecycle hook accepted the event, calling it again later")
+		call.Message = "Accepted, the hook is called again until it completes"
+		return true
+	case err == nil:
+		log.Info("Lifecycle hook completed")
+		call.Phase = infrastructurev1beta2.ContaboLifecycleHookCallPhaseSucceeded
+		call.Message = ""
+		return false
+	case hook.Spec.FailurePolicy == infrastructurev1beta2.ContaboLifecycleHookFailurePolicyIgnore:
+		log.Info("Lifecycle hook failed, ignored by its failure policy", "error", err.Error())
+		call.Phase = infrastructurev1beta2.ContaboLifecycleHookCallPhaseFailed
+		call.Message = err.Error()
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.LifecycleHookFailedReason,
+			"Lifecycle hook %s failed at %s, ignored: %s", hook.Name, point, err.Error())
+		return false
+	default:
+		log.Info("Lifecycle hook failed, retrying", "attempts", call.Attempts, "error", err.Error())
+		call.Message = err.Error()
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.LifecycleHookFailedReason,
+			"Lifecycle hook %s failed at %s: %s", hook.Name, point, err.Error())
+		return true
+	}
+}
+
+// lifecycleHookTimeout returns the timeout of a call of the endpoint of the hook
+func lifecycleHookTimeout(hook *infrastructurev1beta2.ContaboLifecycleHook) time.Duration {
+	if hook.Spec.Timeout != nil && hook.Spec.Timeout.Duration > 0 {
+		return hook.Spec.Timeout.Duration
+	}
+	return DefaultLifecycleHookTimeout
+}
+
+// lifecycleHookRequest returns the lifecycle event of the machine posted to the hooks
+func lifecycleHookRequest(contaboMachine *infrastructurev1beta2.ContaboMachine, hookName string, point infrastructurev1beta2.ContaboLifecycleHookPoint) LifecycleHookRequest {
+	request := LifecycleHookRequest{
+		Source:     NotificationSource,
+		Hook:       hookName,
+		Point:      point,
+		Namespace:  contaboMachine.Namespace,
+		Cluster:    contaboMachine.Labels[clusterv1.ClusterNameLabel],
+		Machine:    contaboMachine.Name,
+		Labels:     contaboMachine.Labels,
+		ProviderID: ptr.Deref(contaboMachine.Spec.ProviderID, ""),
+		Addresses:  contaboMachine.Status.Addresses,
+		Time:       time.Now().UTC(),
+	}
+	if instance := contaboMachine.Status.Instance; instance != nil {
+		request.InstanceId = instance.InstanceId
+		request.InstanceName = instance.Name
+		request.Region = instance.Region
+		request.ProductId = instance.ProductId
+	}
+	return request
+}
+
+// postLifecycleHook posts the lifecycle event to the endpoint of the hook. It returns true when the endpoint answered
+// 202 Accepted to be called again, and an error when it did not answer with a 2xx status code.
+func (r *ContaboMachineReconciler) postLifecycleHook(ctx context.Context, hook *infrastructurev1beta2.ContaboLifecycleHook, request LifecycleHookRequest) (bool, error) {
+	url := hook.Spec.URL
+	if hook.Spec.URLSecretRef != nil {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: hook.Namespace, Name: hook.Spec.URLSecretRef.Name}, secret); err != nil {
+			return false, fmt.Errorf("failed to get the URL of the hook: %w", err)
+		}
+		url = string(secret.Data["url"])
+	}
+	if url == "" {
+		return false, fmt.Errorf("ContaboLifecycleHook %s has no URL", hook.Name)
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to create lifecycle hook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := lifecycleHookHTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to post lifecycle hook: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		if message := strings.TrimSpace(string(body)); message != "" {
+			return false, fmt.Errorf("failed to post lifecycle hook: status %d: %s", resp.StatusCode, message)
+		}
+		return false, fmt.Errorf("failed to post lifecycle hook: status %d", resp.StatusCode)
+	}
+	return resp.StatusCode == http.StatusAccepted, nil
+}
@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// FormatMigrationDisplayName returns the display name claiming the replacement instance during a migration
+func FormatMigrationDisplayName(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	return Truncate(FormatDisplayName(contaboMachine, contaboCluster)+" migration", 255)
+}
+
+// reconcileMigration migrates the machine instance to the data center requested with the MigrateToDataCenterAnnotation.
+// The original instance is snapshotted, a free instance of the target data center is claimed, swapped in and the
+// original is released. Contabo snapshots can only be restored on their own instance, so the replacement is
+// bootstrapped again from the bootstrap data while the snapshot allows rolling back the original instance.
+// It returns true when the migration handled the reconciliation.
+func (r *ContaboMachineReconciler) reconcileMigration(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, bool, error) {
+	log := logf.FromContext(ctx)
+
+	targetDataCenter := contaboMachine.Annotations[infrastructurev1beta2.MigrateToDataCenterAnnotation]
+	instance := contaboMachine.Status.Instance
+	if targetDataCenter == "" || instance == nil {
+		return ctrl.Result{}, false, nil
+	}
+
+	migration := contaboMachine.Status.Migration
+	if migration == nil || migration.TargetDataCenter != targetDataCenter || migration.Phase == infrastructurev1beta2.ContaboMachineMigrationPhaseCompleted {
+		if instance.DataCenter == targetDataCenter {
+			log.Info("Instance already in target data center, nothing to migrate", "dataCenter", targetDataCenter)
+			delete(contaboMachine.Annotations, infrastructurev1beta2.MigrateToDataCenterAnnotation)
+			return ctrl.Result{}, false, nil
+		}
+		migration = &infrastructurev1beta2.ContaboMachineMigrationStatus{
+			TargetDataCenter: targetDataCenter,
+			Phase:            infrastructurev1beta2.ContaboMachineMigrationPhaseSnapshotting,
+			SourceInstanceId: instance.InstanceId,
+			StartTime:        metav1.Now(),
+		}
+		// Control plane members hold etcd data that cannot be re-bootstrapped in place
+		if _, isControlPlane := contaboMachine.Labels[clusterv1.MachineControlPlaneLabel]; isControlPlane {
+			migration.Phase = infrastructurev1beta2.ContaboMachineMigrationPhaseFailed
+			migration.Message = "control plane machines cannot be migrated, scale the control plane instead"
+		}
+		contaboMachine.Status.Migration = migration
+	}
+
+	switch migration.Phase {
+	case infrastructurev1beta2.ContaboMachineMigrationPhaseFailed:
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceMigrationCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstanceMigrationFailedReason,
+			Message: migration.Message,
+		})
+		return ctrl.Result{}, false, nil
+
+	case infrastructurev1beta2.ContaboMachineMigrationPhaseSnapshotting:
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceMigrationCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstanceMigrationInProgressReason,
+			Message: fmt.Sprintf("Taking snapshot of instance %d", instance.InstanceId),
+		})
+		// The snapshot is taken by the job queue, the completion of the job reconciles the machine again
+		job := r.Jobs.Submit(contaboMachine, infrastructurev1beta2.ContaboMachineJobStatus{
+			Name:        fmt.Sprintf("migration-%d", migration.StartTime.Unix()),
+			Type:        infrastructurev1beta2.ContaboMachineJobTypeSnapshot,
+			InstanceId:  instance.InstanceId,
+			Description: fmt.Sprintf("Snapshot before migration to %s by Cluster API Provider Contabo", targetDataCenter),
+		})
+		switch job.Phase {
+		case infrastructurev1beta2.ContaboMachineJobPhaseSucceeded:
+			migration.SnapshotId = job.SnapshotId
+			migration.Phase = infrastructurev1beta2.ContaboMachineMigrationPhaseProvisioning
+			log.Info("Snapshot taken before migration", "instanceID", instance.InstanceId, "snapshotID", migration.SnapshotId)
+			return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+		case infrastructurev1beta2.ContaboMachineJobPhaseFailed:
+			// Queue the snapshot again on the next reconciliation
+			removeJob(contaboMachine, job.Name)
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, r.handleError(
+				ctx,
+				contaboMachine,
+				errors.New(job.Message),
+				infrastructurev1beta2.InstanceMigrationFailedReason,
+				"Failed to snapshot instance before migration",
+			)
+		case infrastructurev1beta2.ContaboMachineJobPhasePending:
+			// Only waiting for room in the snapshot limit leaves a pending job with a message
+			if job.Message != "" {
+				meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+					Type:    infrastructurev1beta2.InstanceMigrationCondition,
+					Status:  metav1.ConditionFalse,
+					Reason:  infrastructurev1beta2.InstanceSnapshotLimitReachedReason,
+					Message: job.Message,
+				})
+			}
+		}
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, true, nil
+
+	case infrastructurev1beta2.ContaboMachineMigrationPhaseProvisioning:
+		target, err := r.claimMigrationTargetInstance(ctx, contaboMachine, contaboCluster, targetDataCenter)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, r.handleError(
+				ctx,
+				contaboMachine,
+				err,
+				infrastructurev1beta2.InstanceMigrationFailedReason,
+				"Failed to claim replacement instance for migration",
+			)
+		}
+		if target == nil {
+			log.Info("No free instance in target data center yet, will retry", "dataCenter", targetDataCenter)
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceMigrationCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.InstanceMigrationWaitingForTargetReason,
+				Message: fmt.Sprintf("Waiting for a free instance in data center %s", targetDataCenter),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, true, nil
+		}
+		migration.TargetInstanceId = target.InstanceId
+		migration.Phase = infrastructurev1beta2.ContaboMachineMigrationPhaseSwapping
+		log.Info("Claimed replacement instance for migration", "instanceID", target.InstanceId, "dataCenter", target.DataCenter)
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+
+	case infrastructurev1beta2.ContaboMachineMigrationPhaseSwapping:
+		targetResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, migration.TargetInstanceId, nil)
+		if err := contabo.CheckResponse(targetResp, err); err != nil || len(targetResp.JSON200.Data) == 0 {
+			if err == nil {
+				err = fmt.Errorf("instance %d not returned by the Contabo API", migration.TargetInstanceId)
+			}
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, r.handleError(
+				ctx,
+				contaboMachine,
+				err,
+				infrastructurev1beta2.InstanceMigrationFailedReason,
+				"Failed to retrieve replacement instance for migration",
+			)
+		}
+		target := convertInstanceResponseData(&targetResp.JSON200.Data[0])
+
+		// Remove the node of the original instance from the workload cluster
+		if nodeName, err := machineNodeName(contaboMachine); err == nil {
+			if err := r.deleteMigratedNode(ctx, contaboCluster, nodeName); err != nil {
+				log.Error(err, "Failed to delete node of the original instance, continuing with migration")
+			}
+		}
+
+		// Retire the original instance, releasing its private network assignment; this resets the machine status
+		status := contaboMachine.Status.DeepCopy()
+		providerID, failureDomain := contaboMachine.Spec.ProviderID, contaboMachine.Spec.FailureDomain
+		if err := r.resetInstance(ctx, contaboMachine, instance, nil); err != nil {
+			// Keep swapping until the original instance is released, the machine still runs on it
+			contaboMachine.Status = *status
+			contaboMachine.Spec.ProviderID, contaboMachine.Spec.FailureDomain = providerID, failureDomain
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, r.handleError(
+				ctx,
+				contaboMachine,
+				err,
+				infrastructurev1beta2.InstanceMigrationFailedReason,
+				"Failed to reset original instance for migration",
+			)
+		}
+		r.returnInstanceToFreePool(ctx, contaboMachine, instance.InstanceId)
+		conditions := status.Conditions
+
+		// Swap in the replacement instance, the normal reconciliation bootstraps it again
+		displayName := FormatDisplayName(contaboMachine, contaboCluster)
+		patchResp, err := r.patchInstance(ctx, contaboMachine, target.InstanceId, target, models.PatchInstanceRequest{
+			DisplayName: &displayName,
+		}, "name the migration replacement after the machine")
+		if err := contabo.CheckResponse(patchResp, err); err != nil {
+			log.Error(err, "Failed to update replacement instance display name", "instanceID", target.InstanceId)
+		}
+		target.DisplayName = displayName
+
+		migration.Phase = infrastructurev1beta2.ContaboMachineMigrationPhaseCompleted
+		migration.Message = fmt.Sprintf("Migrated from instance %d to instance %d", migration.SourceInstanceId, target.InstanceId)
+		contaboMachine.Status.Instance = target
+		contaboMachine.Status.Migration = migration
+		contaboMachine.Status.Conditions = conditions
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceMigrationCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  infrastructurev1beta2.InstanceMigrationCompletedReason,
+			Message: migration.Message,
+		})
+		for _, conditionType := range []string{infrastructurev1beta2.InstanceBootstrapCondition, infrastructurev1beta2.InstanceFirstBootProbeCondition} {
+			meta.RemoveStatusCondition(&contaboMachine.Status.Conditions, conditionType)
+		}
+		delete(contaboMachine.Annotations, infrastructurev1beta2.MigrateToDataCenterAnnotation)
+		log.Info("Instance migrated", "sourceInstanceID", migration.SourceInstanceId, "targetInstanceID", target.InstanceId, "dataCenter", targetDataCenter)
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+	}
+
+	return ctrl.Result{}, false, nil
+}
+
+// claimMigrationTargetInstance claims a free instance of the same product in the target data center
+func (r *ContaboMachineReconciler) claimMigrationTargetInstance(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, dataCenter string) (*infrastructurev1beta2.ContaboInstanceStatus, error) {
+	// Share the reuse lock with instance provisioning to avoid claiming the same instance twice
+	r.instanceReuseMutex.Lock()
+	defer r.instanceReuseMutex.Unlock()
+
+	// Instance already claimed by a previous reconciliation
+	claimedDisplayName := FormatMigrationDisplayName(contaboMachine, contaboCluster)
+	claimedResp, err := r.ContaboClient.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{
+		DisplayName: &claimedDisplayName,
+	})
+	if err := contabo.CheckResponse(claimedResp, err); err != nil && !errors.Is(err, contabo.ErrNotFound) {
+		return nil, fmt.Errorf("failed to list claimed instances: %w", err)
+	} else if err == nil && len(claimedResp.JSON200.Data) > 0 {
+		return convertListInstanceResponseData(&claimedResp.JSON200.Data[0]), nil
+	}
+
+	displayNameEmpty := ""
+	resp, err := r.ContaboClient.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{
+		Size:        ptr.To(int64(100)),
+		DisplayName: &displayNameEmpty,
+		ProductIds:  (*string)(contaboMachine.Spec.Instance.ProductId),
+		Region:      (*string)(&contaboCluster.Spec.PrivateNetwork.Region),
+		DataCenter:  &dataCenter,
+	})
+	if err := contabo.CheckResponse(resp, err); errors.Is(err, contabo.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	// A partially adopted cluster only claims the instances carrying its provider tag
+	managed, err := managedInstances(ctx, r.ContaboClient, contaboCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp.JSON200.Data {
+		candidate := &resp.JSON200.Data[i]
+		if candidate.DisplayName != displayNameEmpty || candidate.CancelDate != nil || candidate.DataCenter != dataCenter ||
+			ignoresInstance(managed, candidate.InstanceId) {
+			continue
+		}
+		instance := convertListInstanceResponseData(candidate)
+		patchResp, err := r.patchInstance(ctx, contaboMachine, candidate.InstanceId, instance, models.PatchInstanceRequest{
+			DisplayName: &claimedDisplayName,
+		}, "claim the migration target instance")
+		if contabo.CheckResponse(patchResp, err) != nil {
+			continue
+		}
+		r.removeFromFreePool(ctx, candidate.InstanceId)
+		instance.DisplayName = claimedDisplayName
+		return instance, nil
+	}
+
+	return nil, nil
+}
+
+// deleteMigratedNode deletes the node of the original instance from the workload cluster
+func (r *ContaboMachineReconciler) deleteMigratedNode(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, nodeName string) error {
+	k8sClient, err := r.getKubeClient(ctx, contaboCluster)
+	if err != nil {
+		return err
+	}
+	if err := k8sClient.CoreV1().Nodes().Delete(ctx, nodeName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete node %s: %w", nodeName, err)
+	}
+	return nil
+}
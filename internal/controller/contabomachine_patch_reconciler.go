@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+)
+
+const (
+	// DefaultPatchRebootTimeout is the time allowed for a patched instance to come back after its restart
+	DefaultPatchRebootTimeout = 15 * time.Minute
+
+	// patchExitCodeMarker prefixes the exit code of the upgrade command in its output
+	patchExitCodeMarker = "capc-exit-code:"
+
+	// patchUptimeCommand reports the instance uptime in seconds
+	patchUptimeCommand = "cut -d. -f1 /proc/uptime"
+)
+
+var patchExitCodeRegexp = regexp.MustCompile(patchExitCodeMarker + `(\d+)`)
+
+// parsePatchCommandOutput returns the exit code appended to the upgrade command output, -1 when missing
+func parsePatchCommandOutput(output string) int {
+	matches := patchExitCodeRegexp.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return -1
+	}
+	exitCode, err := strconv.Atoi(matches[len(matches)-1][1])
+	if err != nil {
+		return -1
+	}
+	return exitCode
+}
+
+// reconcilePatch cordons, upgrades, restarts and uncordons the machine when requested with the PatchRequestAnnotation
+// by a ContaboPatchSchedule. It returns true when the patching handled the reconciliation.
+func (r *ContaboMachineReconciler) reconcilePatch(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, bool, error) {
+	log := logf.FromContext(ctx)
+
+	run := contaboMachine.Annotations[infrastructurev1beta2.PatchRequestAnnotation]
+	if run == "" || contaboMachine.Status.Instance == nil || contaboMachine.Spec.ProviderID == nil {
+		return ctrl.Result{}, false, nil
+	}
+
+	machinePatch := contaboMachine.Status.Patch
+	if machinePatch != nil && machinePatch.Run == run &&
+		(machinePatch.Phase == infrastructurev1beta2.ContaboMachinePatchPhaseCompleted || machinePatch.Phase == infrastructurev1beta2.ContaboMachinePatchPhaseFailed) {
+		// Waiting for the ContaboPatchSchedule to release the machine
+		return ctrl.Result{}, false, nil
+	}
+	if machinePatch == nil || machinePatch.Run != run {
+		if !contaboMachine.Status.Ready {
+			return ctrl.Result{}, false, nil
+		}
+		machinePatch = &infrastructurev1beta2.ContaboMachinePatchStatus{
+			Run:       run,
+			Phase:     infrastructurev1beta2.ContaboMachinePatchPhaseCordoning,
+			StartTime: metav1.Now(),
+		}
+		contaboMachine.Status.Patch = machinePatch
+		log.Info("Patching machine", "run", run)
+	}
+
+	// The annotation value is <schedule name>/<window start>
+	schedule := &infrastructurev1beta2.ContaboPatchSchedule{}
+	scheduleName, _, _ := strings.Cut(run, "/")
+	if err := r.Get(ctx, client.ObjectKey{Namespace: contaboMachine.Namespace, Name: scheduleName}, schedule); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.failPatch(ctx, contaboMachine, contaboCluster, fmt.Sprintf("ContaboPatchSchedule %s not found", scheduleName))
+		}
+		return ctrl.Result{}, true, err
+	}
+
+	nodeName, err := machineNodeName(contaboMachine)
+	if err != nil {
+		return r.failPatch(ctx, contaboMachine, contaboCluster, err.Error())
+	}
+
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstancePatchCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.PatchInProgressReason,
+		Message: fmt.Sprintf("%s (run %s)", machinePatch.Phase, run),
+	})
+
+	switch machinePatch.Phase {
+	case infrastructurev1beta2.ContaboMachinePatchPhaseCordoning:
+		if err := r.setNodeUnschedulable(ctx, contaboCluster, nodeName, true); err != nil {
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, err
+		}
+		log.Info("Node cordoned before patching", "node", nodeName)
+		machinePatch.Phase = infrastructurev1beta2.ContaboMachinePatchPhaseUpgrading
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+
+	case infrastructurev1beta2.ContaboMachinePatchPhaseUpgrading:
+		command := schedule.Spec.Command
+		if command == "" {
+			command = infrastructurev1beta2.DefaultPatchCommand
+		}
+		output, result, err := r.runMachineInstanceSshCommand(ctx, contaboMachine, contaboCluster, fmt.Sprintf("%s; echo \"%s$?\"", command, patchExitCodeMarker))
+		if result.RequeueAfter > 0 {
+			log.Info("Instance not reachable over SSH yet, will retry patching")
+			return result, true, nil
+		}
+		if err != nil {
+			return r.failPatch(ctx, contaboMachine, contaboCluster, err.Error())
+		}
+		if exitCode := parsePatchCommandOutput(output); exitCode != 0 {
+			return r.failPatch(ctx, contaboMachine, contaboCluster, fmt.Sprintf("upgrade command exited with code %d: %s", exitCode, Truncate(strings.TrimSpace(output), 512)))
+		}
+		log.Info("Instance upgraded", "instanceID", contaboMachine.Status.Instance.InstanceId)
+
+		if !ptr.Deref(schedule.Spec.Reboot, true) {
+			machinePatch.Phase = infrastructurev1beta2.ContaboMachinePatchPhaseUncordoning
+			return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+		}
+		// The phase is persisted before the restart, so that an upgrade is not run twice when the controller manager
+		// itself runs on the restarted instance
+		machinePatch.Phase = infrastructurev1beta2.ContaboMachinePatchPhaseRebooting
+		machinePatch.RebootTime = nil
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+
+	case infrastructurev1beta2.ContaboMachinePatchPhaseRebooting:
+		if machinePatch.RebootTime == nil {
+			resp, err := r.ContaboClient.RestartWithResponse(ctx, contaboMachine.Status.Instance.InstanceId, nil)
+			if err := contabo.CheckResponse(resp, err); err != nil {
+				log.Error(err, "Failed to restart instance after upgrade, will retry")
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+			}
+			machinePatch.RebootTime = ptr.To(metav1.Now())
+			log.Info("Instance restarted after upgrade", "instanceID", contaboMachine.Status.Instance.InstanceId)
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+		elapsed := time.Since(machinePatch.RebootTime.Time)
+		if elapsed > DefaultPatchRebootTimeout {
+			return r.failPatch(ctx, contaboMachine, contaboCluster, fmt.Sprintf("node %s not ready %s after restart", nodeName, DefaultPatchRebootTimeout))
+		}
+		// The instance rebooted when its uptime is lower than the time elapsed since the restart
+		output, result, _ := r.runMachineInstanceSshCommand(ctx, contaboMachine, contaboCluster, patchUptimeCommand)
+		uptime, err := strconv.Atoi(strings.TrimSpace(output))
+		if result.RequeueAfter > 0 || err != nil || time.Duration(uptime)*time.Second >= elapsed {
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+		ready, err := r.isNodeReady(ctx, contaboCluster, nodeName)
+		if err != nil || !ready {
+			log.Info("Waiting for node to be ready after restart", "node", nodeName)
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+		machinePatch.Phase = infrastructurev1beta2.ContaboMachinePatchPhaseUncordoning
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+
+	case infrastructurev1beta2.ContaboMachinePatchPhaseUncordoning:
+		if err := r.setNodeUnschedulable(ctx, contaboCluster, nodeName, false); err != nil {
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, err
+		}
+		machinePatch.Phase = infrastructurev1beta2.ContaboMachinePatchPhaseCompleted
+		machinePatch.Message = ""
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstancePatchCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  infrastructurev1beta2.PatchCompletedReason,
+			Message: fmt.Sprintf("Patched during run %s", run),
+		})
+		log.Info("Machine patched, node uncordoned", "node", nodeName)
+		return ctrl.Result{}, true, nil
+	}
+
+	return ctrl.Result{}, false, nil
+}
+
+// failPatch marks the patching as failed and uncordons the node, best effort
+func (r *ContaboMachineReconciler) failPatch(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, message string) (ctrl.Result, bool, error) {
+	log := logf.FromContext(ctx)
+
+	log.Info("Failed to patch machine", "reason", message)
+	if nodeName, err := machineNodeName(contaboMachine); err == nil {
+		if err := r.setNodeUnschedulable(ctx, contaboCluster, nodeName, false); err != nil {
+			log.Error(err, "Failed to uncordon node after patch failure", "node", nodeName)
+		}
+	}
+	contaboMachine.Status.Patch.Phase = infrastructurev1beta2.ContaboMachinePatchPhaseFailed
+	contaboMachine.Status.Patch.Message = message
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstancePatchCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.PatchFailedReason,
+		Message: message,
+	})
+	return ctrl.Result{}, true, nil
+}
+
+// setNodeUnschedulable cordons or uncordons the workload cluster node
+func (r *ContaboMachineReconciler) setNodeUnschedulable(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, nodeName string, unschedulable bool) error {
+	k8sClient, err := r.getKubeClient(ctx, contaboCluster)
+	if err != nil {
+		return err
+	}
+	node, err := k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+	node.Spec.Unschedulable = unschedulable
+	if _, err := k8sClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update node %s: %w", nodeName, err)
+	}
+	return nil
+}
+
+// isNodeReady reports whether the workload cluster node is ready
+func (r *ContaboMachineReconciler) isNodeReady(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, nodeName string) (bool, error) {
+	k8sClient, err := r.getKubeClient(ctx, contaboCluster)
+	if err != nil {
+		return false, err
+	}
+	node, err := k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
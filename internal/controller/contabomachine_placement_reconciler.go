@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// DefaultOutOfStockInterval is the interval between two instance orders while the product is out of stock in every
+// allowed failure domain
+const DefaultOutOfStockInterval = 5 * time.Minute
+
+// ErrOutOfStock is returned when Contabo refuses to create an instance because its product is out of stock in the
+// region, the product may be back in stock later or available in another region
+var ErrOutOfStock = errors.New("contabo product out of stock")
+
+// outOfStockHints are the error message fragments returned by the Contabo API for products temporarily out of stock
+var outOfStockHints = []string{
+	"out of stock",
+	"out-of-stock",
+	"sold out",
+	"no capacity",
+	"insufficient capacity",
+	"currently not available",
+	"temporarily unavailable",
+}
+
+// isOutOfStockResponse detects create instance failures caused by a product out of stock in the region
+func isOutOfStockResponse(statusCode int, body []byte) bool {
+	if statusCode < 400 || statusCode >= 500 || statusCode == 401 || statusCode == 403 || statusCode == 429 {
+		return false
+	}
+	message := strings.ToLower(string(body))
+	for _, hint := range outOfStockHints {
+		if strings.Contains(message, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// placementFailureDomains returns the failure domains of the cluster in order of preference, the private network
+// region when the placement sets none
+func placementFailureDomains(contaboCluster *infrastructurev1beta2.ContaboCluster) []string {
+	failureDomains := []string{}
+	if contaboCluster.Spec.Placement != nil {
+		for _, region := range contaboCluster.Spec.Placement.FailureDomains {
+			failureDomains = append(failureDomains, string(region))
+		}
+	}
+	if len(failureDomains) == 0 {
+		failureDomains = append(failureDomains, string(contaboCluster.Spec.PrivateNetwork.Region))
+	}
+	return failureDomains
+}
+
+// clusterFailureDomains returns the failure domains reported to Cluster API, none when the placement sets none so
+// the machines are not spread
+func clusterFailureDomains(contaboCluster *infrastructurev1beta2.ContaboCluster) []clusterv1.FailureDomain {
+	if contaboCluster.Spec.Placement == nil || len(contaboCluster.Spec.Placement.FailureDomains) == 0 {
+		return nil
+	}
+	failureDomains := []clusterv1.FailureDomain{}
+	for _, region := range contaboCluster.Spec.Placement.FailureDomains {
+		failureDomains = append(failureDomains, clusterv1.FailureDomain{
+			Name:         string(region),
+			ControlPlane: ptr.To(true),
+			Attributes:   map[string]string{"region": string(region)},
+		})
+	}
+	return failureDomains
+}
+
+// requestedFailureDomain returns the failure domain of the Machine when allowed, the first failure domain otherwise
+func requestedFailureDomain(machine *clusterv1.Machine, failureDomains []string) string {
+	if machine != nil && slices.Contains(failureDomains, machine.Spec.FailureDomain) {
+		return machine.Spec.FailureDomain
+	}
+	return failureDomains[0]
+}
+
+// nextFailureDomain returns the failure domain to order the instance in after the product was out of stock in the
+// failure domains of the placement, following the list from the requested one. It returns false when the policy
+// does not allow falling back or every failure domain was tried.
+func nextFailureDomain(placement *infrastructurev1beta2.ContaboMachinePlacementStatus, failureDomains []string, policy infrastructurev1beta2.ContaboPlacementFallbackPolicy) (string, bool) {
+	if policy != infrastructurev1beta2.ContaboPlacementFallbackPolicyNextFailureDomain {
+		return "", false
+	}
+	start := max(slices.Index(failureDomains, placement.RequestedFailureDomain), 0)
+	for i := range failureDomains {
+		candidate := failureDomains[(start+i)%len(failureDomains)]
+		if !slices.Contains(placement.OutOfStock, candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// placementRegion returns the region the instance of the machine is ordered and looked for in
+func placementRegion(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	if placement := contaboMachine.Status.Placement; placement != nil && placement.FailureDomain != "" {
+		return placement.FailureDomain
+	}
+	return string(contaboCluster.Spec.PrivateNetwork.Region)
+}
+
+// reconcilePlacement resolves the failure domain the instance is ordered in, the one of the Machine or the first one
+// of the cluster, and records the failure domain the acquired instance landed in for Cluster API
+func (r *ContaboMachineReconciler) reconcilePlacement(ctx context.Context, machine *clusterv1.Machine, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	failureDomains := placementFailureDomains(contaboCluster)
+	requested := requestedFailureDomain(machine, failureDomains)
+	placement := contaboMachine.Status.Placement
+	if placement == nil || placement.RequestedFailureDomain != requested {
+		placement = &infrastructurev1beta2.ContaboMachinePlacementStatus{
+			RequestedFailureDomain: requested,
+			FailureDomain:          requested,
+		}
+		contaboMachine.Status.Placement = placement
+	}
+
+	instance := contaboMachine.Status.Instance
+	if instance == nil || instance.Region == "" || ptr.Deref(contaboMachine.Spec.FailureDomain, "") == instance.Region {
+		return
+	}
+	placement.FailureDomain = instance.Region
+	contaboMachine.Spec.FailureDomain = ptr.To(instance.Region)
+	if instance.Region != placement.RequestedFailureDomain {
+		log.Info("Instance landed outside of the requested failure domain",
+			"instanceID", instance.InstanceId,
+			"failureDomain", instance.Region,
+			"requestedFailureDomain", placement.RequestedFailureDomain)
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.InstancePlacementFallbackReason,
+			"Instance %d landed in %s instead of %s", instance.InstanceId, instance.Region, placement.RequestedFailureDomain)
+	}
+}
+
+// reconcileOutOfStock moves the instance order to the next failure domain of the cluster when the product is out of
+// stock and the fallback policy allows it. Once every allowed failure domain was tried, the order waits for the
+// OutOfStockInterval and starts over from the requested failure domain.
+func (r *ContaboMachineReconciler) reconcileOutOfStock(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, cause error) ctrl.Result {
+	log := logf.FromContext(ctx)
+
+	failureDomains := placementFailureDomains(contaboCluster)
+	placement := contaboMachine.Status.Placement
+	if placement == nil {
+		placement = &infrastructurev1beta2.ContaboMachinePlacementStatus{
+			RequestedFailureDomain: failureDomains[0],
+			FailureDomain:          placementRegion(contaboMachine, contaboCluster),
+		}
+		contaboMachine.Status.Placement = placement
+	}
+	outOfStock := placement.FailureDomain
+	if !slices.Contains(placement.OutOfStock, outOfStock) {
+		placement.OutOfStock = append(placement.OutOfStock, outOfStock)
+	}
+	placement.LastOutOfStockTime = ptr.To(metav1.Now())
+	productId := string(ptr.Deref(contaboMachine.Spec.Instance.ProductId, ""))
+
+	policy := infrastructurev1beta2.ContaboPlacementFallbackPolicyNone
+	if contaboCluster.Spec.Placement != nil && contaboCluster.Spec.Placement.FallbackPolicy != "" {
+		policy = contaboCluster.Spec.Placement.FallbackPolicy
+	}
+	if next, ok := nextFailureDomain(placement, failureDomains, policy); ok {
+		placement.FailureDomain = next
+		message := fmt.Sprintf("Product %s is out of stock in %s, ordering the instance in %s", productId, outOfStock, next)
+		log.Info(message, "error", cause.Error())
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstancePlacementFallbackReason,
+			Message: message,
+		})
+		r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.InstancePlacementFallbackReason, message)
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}
+	}
+
+	message := fmt.Sprintf("Product %s is out of stock in %s, waiting for it to be back in stock", productId, strings.Join(placement.OutOfStock, ", "))
+	log.Info(message, "error", cause.Error())
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.InstanceOutOfStockReason,
+		Message: message,
+	})
+	r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.InstanceOutOfStockReason, message)
+	placement.OutOfStock = nil
+	placement.FailureDomain = placement.RequestedFailureDomain
+	return ctrl.Result{RequeueAfter: r.Settings.OutOfStockInterval()}
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+)
+
+const (
+	// DefaultShutdownTimeout is the time a stopped instance has to shut down gracefully before it is stopped
+	DefaultShutdownTimeout = 5 * time.Minute
+
+	// PowerStateSkipRemediationValue is the value of the skip remediation annotation set on the Machine of a stopped
+	// ContaboMachine, the annotation is only removed when it holds this value
+	PowerStateSkipRemediationValue = "contabo-power-state"
+)
+
+// desiredPowerState returns the power state of the spec, Running when not set
+func desiredPowerState(contaboMachine *infrastructurev1beta2.ContaboMachine) infrastructurev1beta2.ContaboPowerState {
+	if contaboMachine.Spec.PowerState == "" {
+		return infrastructurev1beta2.ContaboPowerStateRunning
+	}
+	return contaboMachine.Spec.PowerState
+}
+
+// isControlPlaneMachine returns true for the ContaboMachines of a control plane
+func isControlPlaneMachine(contaboMachine *infrastructurev1beta2.ContaboMachine) bool {
+	_, ok := contaboMachine.Labels[clusterv1.MachineControlPlaneLabel]
+	return ok
+}
+
+// controlPlaneQuorumAllowsStop returns whether the control plane keeps its quorum once self is stopped, with the
+// number of control plane machines left running and the quorum. Machines stopped, stopping or being deleted are not
+// counted as running.
+func controlPlaneQuorumAllowsStop(contaboMachines []infrastructurev1beta2.ContaboMachine, self types.NamespacedName) (bool, int, int) {
+	total, running := 0, 0
+	for i := range contaboMachines {
+		contaboMachine := &contaboMachines[i]
+		if !isControlPlaneMachine(contaboMachine) {
+			continue
+		}
+		total++
+		if client.ObjectKeyFromObject(contaboMachine) == self ||
+			!contaboMachine.DeletionTimestamp.IsZero() ||
+			desiredPowerState(contaboMachine) == infrastructurev1beta2.ContaboPowerStateStopped ||
+			contaboMachine.Status.PowerState == infrastructurev1beta2.ContaboPowerStateStopped {
+			continue
+		}
+		running++
+	}
+	quorum := total/2 + 1
+	return running >= quorum, running, quorum
+}
+
+// reconcilePowerState starts or stops the provisioned instance to match the power state of the spec. Stopped
+// instances are shut down gracefully first, then stopped after the shutdown timeout. Control plane machines are not
+// stopped below the quorum of the control plane and the instance running the controller manager is never stopped.
+// It returns handled=true while the power state of the spec is not reached, the rest of the reconciliation is skipped.
+func (r *ContaboMachineReconciler) reconcilePowerState(ctx context.Context, machine *clusterv1.Machine, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, bool, error) {
+	log := logf.FromContext(ctx)
+
+	if contaboMachine.Status.Instance == nil || !contaboMachine.Status.Available {
+		return ctrl.Result{}, false, nil
+	}
+	desired := desiredPowerState(contaboMachine)
+	if desired == infrastructurev1beta2.ContaboPowerStateRunning &&
+		contaboMachine.Status.PowerState != infrastructurev1beta2.ContaboPowerStateStopped &&
+		contaboMachine.Status.ShutdownTime == nil {
+		return ctrl.Result{}, false, nil
+	}
+
+	instanceId := contaboMachine.Status.Instance.InstanceId
+	instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, instanceId, nil)
+	if err := contabo.CheckResponse(instanceResp, err); err != nil || len(instanceResp.JSON200.Data) == 0 {
+		if err == nil {
+			err = errors.New("not returned by the Contabo API")
+		}
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, fmt.Errorf("failed to retrieve instance %d: %w", instanceId, err)
+	}
+	contaboMachine.Status.Instance = convertInstanceResponseData(&instanceResp.JSON200.Data[0])
+	status := contaboMachine.Status.Instance.Status
+
+	if desired == infrastructurev1beta2.ContaboPowerStateRunning {
+		switch status {
+		case infrastructurev1beta2.InstanceStatusRunning:
+			if err := r.setSkipRemediation(ctx, machine, false); err != nil {
+				return ctrl.Result{}, true, err
+			}
+			contaboMachine.Status.PowerState = infrastructurev1beta2.ContaboPowerStateRunning
+			contaboMachine.Status.ShutdownTime = nil
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:   infrastructurev1beta2.InstancePowerStateCondition,
+				Status: metav1.ConditionTrue,
+				Reason: infrastructurev1beta2.PowerStateRunningReason,
+			})
+			log.Info("Instance is running", "instanceID", instanceId)
+			return ctrl.Result{}, false, nil
+		case infrastructurev1beta2.InstanceStatusStopped:
+			resp, err := r.ContaboClient.StartWithResponse(ctx, instanceId, nil)
+			if err := contabo.CheckResponse(resp, err); err != nil {
+				log.Error(err, "Failed to start instance, will retry", "instanceID", instanceId)
+				return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+			}
+			contaboMachine.Status.ShutdownTime = nil
+			r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.PowerStateStartingReason, "Started instance %d", instanceId)
+		}
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstancePowerStateCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.PowerStateStartingReason,
+			Message: fmt.Sprintf("Waiting for instance %d to be running, current status is %s", instanceId, status),
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+	}
+
+	if status == infrastructurev1beta2.InstanceStatusStopped {
+		contaboMachine.Status.PowerState = infrastructurev1beta2.ContaboPowerStateStopped
+		contaboMachine.Status.ShutdownTime = nil
+		if !meta.IsStatusConditionTrue(contaboMachine.Status.Conditions, infrastructurev1beta2.InstancePowerStateCondition) ||
+			meta.FindStatusCondition(contaboMachine.Status.Conditions, infrastructurev1beta2.InstancePowerStateCondition).Reason != infrastructurev1beta2.PowerStateStoppedReason {
+			r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.PowerStateStoppedReason, "Instance %d is stopped", instanceId)
+		}
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:   infrastructurev1beta2.InstancePowerStateCondition,
+			Status: metav1.ConditionTrue,
+			Reason: infrastructurev1beta2.PowerStateStoppedReason,
+		})
+		return ctrl.Result{}, true, nil
+	}
+
+	// The guardrails are checked before the shutdown, a started shutdown is carried on
+	if contaboMachine.Status.ShutdownTime == nil {
+		if message, err := r.powerStateStopBlocked(ctx, contaboMachine, contaboCluster); err != nil {
+			return ctrl.Result{}, true, err
+		} else if message != "" {
+			if condition := meta.FindStatusCondition(contaboMachine.Status.Conditions, infrastructurev1beta2.InstancePowerStateCondition); condition == nil || condition.Message != message {
+				r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.PowerStateBlockedReason, message)
+			}
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstancePowerStateCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.PowerStateBlockedReason,
+				Message: message,
+			})
+			log.Info("Not stopping instance", "instanceID", instanceId, "reason", message)
+			// The guardrails are checked again until the power state of the spec changes
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+	}
+
+	// A stopped node would otherwise be remediated by a MachineHealthCheck
+	if err := r.setSkipRemediation(ctx, machine, true); err != nil {
+		return ctrl.Result{}, true, err
+	}
+
+	if contaboMachine.Status.ShutdownTime == nil {
+		resp, err := r.ContaboClient.ShutdownWithResponse(ctx, instanceId, nil)
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			log.Error(err, "Failed to shut down instance, will retry", "instanceID", instanceId)
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+		contaboMachine.Status.ShutdownTime = ptr.To(metav1.Now())
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.PowerStateStoppingReason, "Shutting down instance %d", instanceId)
+	} else if elapsed := time.Since(contaboMachine.Status.ShutdownTime.Time); elapsed >= r.Settings.ShutdownTimeout() {
+		resp, err := r.ContaboClient.StopWithResponse(ctx, instanceId, nil)
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			log.Error(err, "Failed to stop instance, will retry", "instanceID", instanceId)
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.PowerStateStoppingReason,
+			"Instance %d did not shut down within %s, stopped it", instanceId, r.Settings.ShutdownTimeout())
+	}
+
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstancePowerStateCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.PowerStateStoppingReason,
+		Message: fmt.Sprintf("Waiting for instance %d to be stopped, current status is %s", instanceId, status),
+	})
+	return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+}
+
+// powerStateStopBlocked returns why the instance of the ContaboMachine cannot be stopped, empty when it can
+func (r *ContaboMachineReconciler) powerStateStopBlocked(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (string, error) {
+	instanceId := contaboMachine.Status.Instance.InstanceId
+	if isManagerMachine(contaboMachine, r.ManagerNodeName) {
+		return fmt.Sprintf("Instance %d runs the controller manager and cannot be stopped", instanceId), nil
+	}
+	if !isControlPlaneMachine(contaboMachine) {
+		return "", nil
+	}
+
+	contaboMachineList := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachineList, client.InNamespace(contaboCluster.Namespace), client.MatchingLabels{
+		clusterv1.ClusterNameLabel: contaboCluster.Name,
+	}); err != nil {
+		return "", fmt.Errorf("failed to list ContaboMachines: %w", err)
+	}
+	allowed, running, quorum := controlPlaneQuorumAllowsStop(contaboMachineList.Items, client.ObjectKeyFromObject(contaboMachine))
+	if !allowed {
+		return fmt.Sprintf("Stopping control plane machine would leave %d running control plane machines, below the quorum of %d", running, quorum), nil
+	}
+	return "", nil
+}
+
+// setSkipRemediation adds or removes the skip remediation annotation of the Machine, an annotation set by someone
+// else is left untouched
+func (r *ContaboMachineReconciler) setSkipRemediation(ctx context.Context, machine *clusterv1.Machine, skip bool) error {
+	if machine == nil {
+		return nil
+	}
+	value, ok := machine.Annotations[clusterv1.MachineSkipRemediationAnnotation]
+	if skip == ok || (!skip && value != PowerStateSkipRemediationValue) {
+		return nil
+	}
+
+	original := machine.DeepCopy()
+	if skip {
+		if machine.Annotations == nil {
+			machine.Annotations = map[string]string{}
+		}
+		machine.Annotations[clusterv1.MachineSkipRemediationAnnotation] = PowerStateSkipRemediationValue
+	} else {
+		delete(machine.Annotations, clusterv1.MachineSkipRemediationAnnotation)
+	}
+	if err := r.Patch(ctx, machine, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to patch skip remediation annotation of Machine %s: %w", machine.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// providerIDTimeout bounds the workload cluster requests, the API server may be unreachable
+	providerIDTimeout = 10 * time.Second
+
+	// providerIDKubeletRestartCommand restarts the kubelet so that it registers its deleted node again
+	providerIDKubeletRestartCommand = "sudo systemctl restart kubelet"
+)
+
+// providerIDRepair is the repair of a machine whose node reports the provider ID of another instance
+type providerIDRepair int
+
+const (
+	// providerIDConsistent needs no repair
+	providerIDConsistent providerIDRepair = iota
+	// providerIDSetNode sets the provider ID of a node registered without one
+	providerIDSetNode
+	// providerIDReregisterNode deletes the node reporting another provider ID, immutable once set, for the kubelet
+	// to register it again
+	providerIDReregisterNode
+	// providerIDRecreateMachine replaces the Machine linked to the node of a previous instance, Cluster API never
+	// updates the node reference of a Machine
+	providerIDRecreateMachine
+)
+
+// providerIDRepairOf returns the repair of the node of the instance, nil when not found, and of the node the Machine
+// is linked to
+func providerIDRepairOf(providerID string, nodeName string, machine *clusterv1.Machine, node *corev1.Node) providerIDRepair {
+	switch {
+	case machine.Status.NodeRef.IsDefined() && machine.Status.NodeRef.Name != nodeName:
+		return providerIDRecreateMachine
+	case node == nil:
+		return providerIDConsistent
+	case node.Spec.ProviderID == "":
+		return providerIDSetNode
+	case node.Spec.ProviderID != providerID:
+		return providerIDReregisterNode
+	}
+	return providerIDConsistent
+}
+
+// reconcileProviderID verifies the Machine and its node are linked to the current instance of the ContaboMachine,
+// the instance changes when it is migrated, recreated or swapped manually. The provider ID of the ContaboMachine and
+// of the node are repaired in place, a Machine linked to the node of a previous instance is reported, or deleted to be
+// replaced with the RecreateMachine repair policy of the ContaboCluster. Best effort, retried on the next
+// reconciliation.
+func (r *ContaboMachineReconciler) reconcileProviderID(ctx context.Context, machine *clusterv1.Machine, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	instance := contaboMachine.Status.Instance
+	if instance == nil || instance.Name == "" || contaboMachine.Spec.ProviderID == nil ||
+		!meta.IsStatusConditionTrue(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceBootstrapCondition) {
+		return
+	}
+	providerID := BuildProviderID(instance.Name)
+	if *contaboMachine.Spec.ProviderID != providerID {
+		log.Info("Repairing the provider ID of the replaced instance", "providerID", *contaboMachine.Spec.ProviderID, "instanceProviderID", providerID)
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.ProviderIDRepairedReason,
+			"Provider ID updated from %s to %s of instance %d", *contaboMachine.Spec.ProviderID, providerID, instance.InstanceId)
+		contaboMachine.Spec.ProviderID = &providerID
+	}
+
+	nodeName := contaboMachine.Status.NodeName
+	if nodeName == "" {
+		nodeName = instance.Name
+	}
+
+	kubeClient, err := r.getKubeClient(ctx, contaboCluster)
+	if err != nil {
+		log.V(1).Info("Workload cluster not reachable, the provider ID of the node is verified later", "error", err.Error())
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, providerIDTimeout)
+	defer cancel()
+	node, err := kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		node = nil
+	} else if err != nil {
+		log.V(1).Info("Failed to get the node of the instance, the provider ID is verified later", "node", nodeName, "error", err.Error())
+		return
+	}
+
+	switch providerIDRepairOf(providerID, nodeName, machine, node) {
+	case providerIDSetNode:
+		patch := fmt.Appendf(nil, `{"spec":{"providerID":%q}}`, providerID)
+		if _, err := kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			log.Error(err, "Failed to set the provider ID of the node", "node", node.Name)
+			return
+		}
+		log.Info("Set the provider ID of the node", "node", node.Name, "providerID", providerID)
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.ProviderIDRepairedReason,
+			"Provider ID of node %s set to %s", node.Name, providerID)
+
+	case providerIDReregisterNode:
+		if err := kubeClient.CoreV1().Nodes().Delete(ctx, node.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to delete the node reporting another provider ID", "node", node.Name)
+			return
+		}
+		if _, result, _ := r.runMachineInstanceSshCommand(ctx, contaboMachine, contaboCluster, providerIDKubeletRestartCommand); result.RequeueAfter > 0 {
+			log.Info("Instance not reachable over SSH, the kubelet registers the node on its next restart", "node", node.Name)
+		}
+		log.Info("Deleted the node reporting another provider ID for the kubelet to register it again",
+			"node", node.Name, "nodeProviderID", node.Spec.ProviderID, "providerID", providerID)
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.ProviderIDRepairedReason,
+			"Node %s reported provider ID %s instead of %s, it was deleted and registered again by the kubelet", node.Name, node.Spec.ProviderID, providerID)
+
+	case providerIDRecreateMachine:
+		message := fmt.Sprintf("Machine %s is linked to node %s of a previous instance, the node of instance %d is %s",
+			machine.Name, machine.Status.NodeRef.Name, instance.InstanceId, nodeName)
+		_, controlPlane := machine.Labels[clusterv1.MachineControlPlaneLabel]
+		owner := metav1.GetControllerOf(machine)
+		if contaboCluster.Spec.ProviderIDRepair != infrastructurev1beta2.ContaboProviderIDRepairRecreateMachine ||
+			controlPlane || owner == nil || owner.Kind != "MachineSet" {
+			log.Info("Machine linked to the node of a previous instance", "nodeRef", machine.Status.NodeRef.Name, "node", nodeName)
+			r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.ProviderIDMismatchReason, message+", delete the Machine to replace it")
+			return
+		}
+		if !machine.DeletionTimestamp.IsZero() {
+			return
+		}
+		if err := r.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to delete the Machine linked to the node of a previous instance")
+			return
+		}
+		log.Info("Deleted the Machine linked to the node of a previous instance", "nodeRef", machine.Status.NodeRef.Name, "node", nodeName)
+		r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.ProviderIDMachineRecreatedReason, message+", it was deleted to be replaced by its MachineSet")
+	}
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+)
+
+// failedInstanceStatuses are the instance states reset by the reconciliation, see validateInstanceStatus
+var failedInstanceStatuses = []infrastructurev1beta2.InstanceStatus{
+	infrastructurev1beta2.InstanceStatusError,
+	infrastructurev1beta2.InstanceStatusUnknown,
+	infrastructurev1beta2.InstanceStatusManualProvisioning,
+	infrastructurev1beta2.InstanceStatusOther,
+	infrastructurev1beta2.InstanceStatusProductNotAvailable,
+	infrastructurev1beta2.InstanceStatusVerificationRequired,
+}
+
+// setReadOnlyCondition sets or removes the ReadOnly condition of the resource, with the message of the read-only mode
+func setReadOnlyCondition(conditions *[]metav1.Condition, generation int64, readOnly bool, message string) {
+	if !readOnly {
+		meta.RemoveStatusCondition(conditions, infrastructurev1beta2.ReadOnlyCondition)
+		return
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               infrastructurev1beta2.ReadOnlyCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             infrastructurev1beta2.ReadOnlyModeReason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// reconcileReadOnly only observes the instance of the ContaboMachine while the controller runs with --read-only: its
+// status is refreshed from the Contabo API and nothing is ordered, changed, reset or cancelled. An instance in error is
+// reported without being reset, and the deletion of the machine waits for the read-only mode to end as its instance
+// could not be released.
+func (r *ContaboMachineReconciler) reconcileReadOnly(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) ctrl.Result {
+	log := logf.FromContext(ctx)
+
+	message := "The controller runs with --read-only, the changes to the Contabo instance are suspended"
+	if !contaboMachine.DeletionTimestamp.IsZero() {
+		message = "The controller runs with --read-only, the deletion of the Contabo instance is postponed"
+	}
+	log.Info("Read-only mode, only observing the instance", "deleting", !contaboMachine.DeletionTimestamp.IsZero())
+	setReadOnlyCondition(&contaboMachine.Status.Conditions, contaboMachine.Generation, true, message)
+
+	if contaboMachine.Status.Instance != nil {
+		r.observeInstance(ctx, contaboMachine)
+	}
+	return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}
+}
+
+// observeInstance refreshes the instance of the ContaboMachine status from the Contabo API, and reports an instance
+// cancelled or in error in the InstanceReady condition without acting on it. Failures are only logged, the previous
+// status is kept.
+func (r *ContaboMachineReconciler) observeInstance(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	log := logf.FromContext(ctx)
+
+	instanceId := contaboMachine.Status.Instance.InstanceId
+	instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, instanceId, nil)
+	err = contabo.CheckResponse(instanceResp, err)
+	if errors.Is(err, contabo.ErrNotFound) {
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstanceNotFoundReason,
+			Message: fmt.Sprintf("Instance %d was not found in Contabo", instanceId),
+		})
+		return
+	}
+	if err != nil {
+		log.Info("Failed to retrieve instance", "instanceID", instanceId, "error", err.Error())
+		return
+	}
+	if len(instanceResp.JSON200.Data) == 0 {
+		log.Info("Instance not returned by the Contabo API", "instanceID", instanceId)
+		return
+	}
+
+	instance := convertInstanceResponseData(&instanceResp.JSON200.Data[0])
+	contaboMachine.Status.Instance = instance
+	switch {
+	case instance.ErrorMessage != nil && *instance.ErrorMessage != "":
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstanceFailedReason,
+			Message: fmt.Sprintf("Instance %d has error message: %s, not reset in read-only mode", instanceId, *instance.ErrorMessage),
+		})
+	case slices.Contains(failedInstanceStatuses, instance.Status):
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.InstanceReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.InstanceFailedReason,
+			Message: fmt.Sprintf("Instance %d is in %s state, not reset in read-only mode", instanceId, instance.Status),
+		})
+	}
+}
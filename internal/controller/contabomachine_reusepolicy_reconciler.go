@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// cancelsInstance returns true when the deletion of the machine cancels the contract of its instance
+func cancelsInstance(contaboMachine *infrastructurev1beta2.ContaboMachine) bool {
+	return contaboMachine.Spec.ReusePolicy == infrastructurev1beta2.ContaboMachineReusePolicyCancel
+}
+
+// cancelInstanceContract cancels the contract of the instance of a machine deleted with the Cancel reuse policy. The
+// instance is retrieved first so that an instance already cancelled, e.g. by a previous attempt, or gone is left as is.
+func (r *ContaboMachineReconciler) cancelInstanceContract(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, instanceId int64) error {
+	log := logf.FromContext(ctx)
+
+	instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, instanceId, nil)
+	if err := contabo.CheckResponse(instanceResp, err); errors.Is(err, contabo.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to retrieve instance %d: %w", instanceId, err)
+	}
+	if len(instanceResp.JSON200.Data) > 0 && instanceResp.JSON200.Data[0].CancelDate != nil {
+		log.Info("Instance contract already cancelled", "instanceID", instanceId, "cancelDate", instanceResp.JSON200.Data[0].CancelDate.String())
+		return nil
+	}
+
+	cancelResp, err := r.ContaboClient.CancelInstanceWithResponse(ctx, instanceId, contabo.NewParams[models.CancelInstanceParams](ctx), models.CancelInstanceRequest{})
+	if err := contabo.CheckResponse(cancelResp, err); err != nil {
+		return fmt.Errorf("failed to cancel instance %d: %w", instanceId, err)
+	}
+	log.Info("Cancelled the contract of the instance", "instanceID", instanceId)
+	r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.InstanceContractCancelledReason,
+		"Cancelled the contract of instance %d as requested by the %s reuse policy", instanceId, infrastructurev1beta2.ContaboMachineReusePolicyCancel)
+	return nil
+}
+
+// returnInstanceToFreePool tags the instance released by a machine deleted with the Reinstall reuse policy with the
+// FreePoolTag, on a best effort basis as the instance is claimed by its empty display name anyway
+// The instance is not warmed up, e.g. by pulling the Kubernetes images with a reinstall, as the machine claiming it
+// reinstalls it with its own bootstrap data, erasing the disk.
+func (r *ContaboMachineReconciler) returnInstanceToFreePool(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, instanceId int64) {
+	log := logf.FromContext(ctx)
+
+	tagId, err := r.findOrCreateTag(ctx, infrastructurev1beta2.FreePoolTag)
+	if err != nil {
+		log.Info("Failed to find or create the free pool tag", "tag", infrastructurev1beta2.FreePoolTag, "error", err)
+		return
+	}
+	resp, err := r.ContaboClient.CreateAssignmentWithResponse(ctx, tagId, tagResourceTypeInstance, strconv.FormatInt(instanceId, 10), nil)
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		log.Info("Failed to assign the free pool tag", "tag", infrastructurev1beta2.FreePoolTag, "instanceID", instanceId, "error", err)
+		return
+	}
+	log.Info("Returned the instance to the free pool", "instanceID", instanceId)
+	r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.InstanceReturnedToFreePoolReason,
+		"Returned instance %d to the free pool, it is reinstalled by the next machine claiming it", instanceId)
+}
+
+// removeFromFreePool removes the FreePoolTag from an instance claimed by a machine, on a best effort basis
+func (r *ContaboMachineReconciler) removeFromFreePool(ctx context.Context, instanceId int64) {
+	log := logf.FromContext(ctx)
+
+	tagIds, err := findTagIds(ctx, r.ContaboClient, infrastructurev1beta2.FreePoolTag)
+	if err != nil {
+		log.Info("Failed to find the free pool tag", "tag", infrastructurev1beta2.FreePoolTag, "error", err)
+		return
+	}
+	resourceId := strconv.FormatInt(instanceId, 10)
+	for _, tagId := range tagIds {
+		resp, err := r.ContaboClient.DeleteAssignmentWithResponse(ctx, tagId, tagResourceTypeInstance, resourceId, nil)
+		if err := contabo.CheckResponse(resp, err); err != nil && !errors.Is(err, contabo.ErrNotFound) {
+			log.Info("Failed to unassign the free pool tag from the claimed instance", "tag", infrastructurev1beta2.FreePoolTag, "instanceID", instanceId, "error", err)
+		}
+	}
+}
+
+// abandonParkedInstanceClaim unpins the machine created to claim an instance parked by its ContaboMachinePool when
+// the instance cannot be claimed, e.g. it was cancelled or claimed by another manager, so that the machine uses another
+// reusable or new instance instead of failing to create an instance of the given name
+func (r *ContaboMachineReconciler) abandonParkedInstanceClaim(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	instanceName := ptr.Deref(contaboMachine.Spec.Instance.Name, "")
+	logf.FromContext(ctx).Info("Parked instance not found, falling back to another instance", "instanceName", instanceName)
+	r.Recorder.Eventf(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.ParkedInstanceClaimFailedReason,
+		"Failed to claim the parked instance %s, using another reusable or new instance", instanceName)
+	contaboMachine.Spec.Instance.Name = nil
+	delete(contaboMachine.Annotations, infrastructurev1beta2.ParkedInstanceAnnotation)
+}
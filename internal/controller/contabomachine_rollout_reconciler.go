@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// DefaultRolloutDrainTimeout is the time allowed to evict the pods of a node before its in-place rollout fails
+	DefaultRolloutDrainTimeout = 30 * time.Minute
+
+	// rolloutClusterUUIDCommand removes the marker of a bootstrapped instance, so that it is reinstalled
+	rolloutClusterUUIDCommand = "sudo rm -f /etc/cluster-uuid"
+)
+
+// rolloutSpec holds the fields of the ContaboMachine spec rolled out in place, only applied by reinstalling the
+// instance
+type rolloutSpec struct {
+	ImageId              *string                                       `json:"imageId,omitempty"`
+	NetworkConfig        *string                                       `json:"networkConfig,omitempty"`
+	DNS                  *infrastructurev1beta2.ContaboDNSSpec         `json:"dns,omitempty"`
+	NodeLabels           map[string]string                             `json:"nodeLabels,omitempty"`
+	NodeTaints           []corev1.Taint                                `json:"nodeTaints,omitempty"`
+	PrivateOnly          *infrastructurev1beta2.ContaboPrivateOnlySpec `json:"privateOnly,omitempty"`
+	EnableNodeMonitoring bool                                          `json:"enableNodeMonitoring,omitempty"`
+}
+
+// newRolloutSpec returns the fields of the spec rolled out in place
+func newRolloutSpec(spec infrastructurev1beta2.ContaboMachineSpec) rolloutSpec {
+	return rolloutSpec{
+		ImageId:              spec.Instance.ImageId,
+		NetworkConfig:        spec.NetworkConfig,
+		DNS:                  spec.DNS,
+		NodeLabels:           spec.NodeLabels,
+		NodeTaints:           spec.NodeTaints,
+		PrivateOnly:          spec.PrivateOnly,
+		EnableNodeMonitoring: spec.EnableNodeMonitoring,
+	}
+}
+
+// apply copies the fields rolled out in place to the spec, the image is kept when the template does not set one as
+// the machine was created with the default image
+func (s rolloutSpec) apply(spec *infrastructurev1beta2.ContaboMachineSpec) {
+	if s.ImageId != nil {
+		spec.Instance.ImageId = s.ImageId
+	}
+	spec.NetworkConfig = s.NetworkConfig
+	spec.DNS = s.DNS
+	spec.NodeLabels = s.NodeLabels
+	spec.NodeTaints = s.NodeTaints
+	spec.PrivateOnly = s.PrivateOnly
+	spec.EnableNodeMonitoring = s.EnableNodeMonitoring
+}
+
+// rolloutSpecHash returns the hash of the fields of the spec rolled out in place
+func rolloutSpecHash(spec infrastructurev1beta2.ContaboMachineSpec) string {
+	// Marshalling a struct of plain fields and maps never fails, the map keys are sorted
+	data, _ := json.Marshal(newRolloutSpec(spec))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// recordInstalledSpec records the spec the instance is reinstalled with by the bootstrap
+func recordInstalledSpec(contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	if contaboMachine.Status.Rollout == nil {
+		contaboMachine.Status.Rollout = &infrastructurev1beta2.ContaboMachineRolloutStatus{}
+	}
+	contaboMachine.Status.Rollout.InstalledSpecHash = rolloutSpecHash(contaboMachine.Spec)
+}
+
+// machineTemplate returns the ContaboMachineTemplate the machine was cloned from, nil when it was not cloned from a
+// template or the template was deleted
+func (r *ContaboMachineReconciler) machineTemplate(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) (*infrastructurev1beta2.ContaboMachineTemplate, error) {
+	name := contaboMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation]
+	groupKind := contaboMachine.Annotations[clusterv1.TemplateClonedFromGroupKindAnnotation]
+	if name == "" || groupKind != infrastructurev1beta2.GroupVersion.WithKind("ContaboMachineTemplate").GroupKind().String() {
+		return nil, nil
+	}
+	template := &infrastructurev1beta2.ContaboMachineTemplate{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: contaboMachine.Namespace, Name: name}, template); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return template, nil
+}
+
+// rolloutStrategy returns the rollout strategy of the template, inherited from the ContaboCluster when not set
+func rolloutStrategy(template *infrastructurev1beta2.ContaboMachineTemplate, contaboCluster *infrastructurev1beta2.ContaboCluster) infrastructurev1beta2.ContaboRolloutStrategy {
+	if template != nil && template.Spec.RolloutStrategy != nil {
+		return *template.Spec.RolloutStrategy
+	}
+	if contaboCluster.Spec.RolloutStrategy == "" {
+		return infrastructurev1beta2.ContaboRolloutStrategyReplace
+	}
+	return contaboCluster.Spec.RolloutStrategy
+}
+
+// reconcileRollout rolls the in-place updates of the ContaboMachineTemplate out to a worker machine with the
+// ReinstallInPlace rollout strategy: the OS and bootstrap fields of the template are copied to the machine, the node
+// is cordoned and drained, then removed from the workload cluster and the instance is reinstalled by the bootstrap
+// with the updated spec. The machines of a template are rolled out one at a time. It returns true when the rollout
+// handled the reconciliation.
+func (r *ContaboMachineReconciler) reconcileRollout(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (ctrl.Result, bool, error) {
+	log := logf.FromContext(ctx)
+
+	rollout := contaboMachine.Status.Rollout
+	if rollout != nil && rollout.Phase == infrastructurev1beta2.ContaboMachineRolloutPhaseReinstalling {
+		return r.completeRollout(ctx, contaboMachine)
+	}
+
+	template, err := r.machineTemplate(ctx, contaboMachine)
+	if err != nil {
+		log.Error(err, "Failed to get the ContaboMachineTemplate of the machine")
+		return ctrl.Result{}, false, nil
+	}
+	_, controlPlane := contaboMachine.Labels[clusterv1.MachineControlPlaneLabel]
+	inPlace := rolloutStrategy(template, contaboCluster) == infrastructurev1beta2.ContaboRolloutStrategyReinstallInPlace && !controlPlane
+	if inPlace && template != nil {
+		newRolloutSpec(template.Spec.Template.Spec).apply(&contaboMachine.Spec)
+	}
+
+	// The machines not bootstrapped yet are installed with the current spec
+	if !contaboMachine.Status.Ready || !contaboMachine.Status.Available || contaboMachine.Spec.ProviderID == nil ||
+		!meta.IsStatusConditionTrue(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceBootstrapCondition) {
+		return ctrl.Result{}, false, nil
+	}
+	hash := rolloutSpecHash(contaboMachine.Spec)
+	if rollout == nil {
+		// Machines bootstrapped before the rollouts were recorded run their current spec
+		contaboMachine.Status.Rollout = &infrastructurev1beta2.ContaboMachineRolloutStatus{InstalledSpecHash: hash}
+		return ctrl.Result{}, false, nil
+	}
+
+	nodeName, err := machineNodeName(contaboMachine)
+	if err != nil {
+		return ctrl.Result{}, false, nil
+	}
+
+	switch rollout.Phase {
+	case "", infrastructurev1beta2.ContaboMachineRolloutPhaseCompleted, infrastructurev1beta2.ContaboMachineRolloutPhaseFailed:
+		if !inPlace || rollout.InstalledSpecHash == hash ||
+			(rollout.Phase == infrastructurev1beta2.ContaboMachineRolloutPhaseFailed && rollout.TargetSpecHash == hash) {
+			return ctrl.Result{}, false, nil
+		}
+		if r.Settings.Bootstrap().InstanceToken == nil {
+			// The bootstrap token of the machine expired long ago, the reinstalled instance would not join
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceRolloutCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.RolloutBlockedReason,
+				Message: "Reinstalling in place requires the instance bootstrap tokens of the ContaboProviderSettings",
+			})
+			return ctrl.Result{}, false, nil
+		}
+		rollingOut, err := r.templateRolloutInProgress(ctx, contaboMachine)
+		if err != nil {
+			return ctrl.Result{}, false, err
+		}
+		if rollingOut != "" {
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.InstanceRolloutCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.RolloutBlockedReason,
+				Message: fmt.Sprintf("Waiting for the rollout of machine %s", rollingOut),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+
+		rollout.TargetSpecHash = hash
+		rollout.Phase = infrastructurev1beta2.ContaboMachineRolloutPhaseCordoning
+		rollout.StartTime = ptr.To(metav1.Now())
+		rollout.Message = ""
+		log.Info("Rolling out the template updates in place", "specHash", hash)
+		r.Recorder.Event(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.RolloutInProgressReason,
+			"Reinstalling the instance with the template updates")
+	}
+
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceRolloutCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.RolloutInProgressReason,
+		Message: string(rollout.Phase),
+	})
+
+	switch rollout.Phase {
+	case infrastructurev1beta2.ContaboMachineRolloutPhaseCordoning:
+		if err := r.setNodeUnschedulable(ctx, contaboCluster, nodeName, true); err != nil {
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, err
+		}
+		log.Info("Node cordoned before reinstalling", "node", nodeName)
+		rollout.Phase = infrastructurev1beta2.ContaboMachineRolloutPhaseDraining
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+
+	case infrastructurev1beta2.ContaboMachineRolloutPhaseDraining:
+		remaining, err := r.drainNode(ctx, contaboCluster, nodeName)
+		if err != nil {
+			log.Info("Failed to drain node, will retry", "node", nodeName, "error", err.Error())
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+		if remaining > 0 {
+			if time.Since(rollout.StartTime.Time) > DefaultRolloutDrainTimeout {
+				return r.failRollout(ctx, contaboMachine, contaboCluster, fmt.Sprintf("%d pods still running on node %s after %s", remaining, nodeName, DefaultRolloutDrainTimeout))
+			}
+			log.Info("Waiting for the pods to be evicted before reinstalling", "node", nodeName, "remainingPods", remaining)
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+
+		// The reinstalled instance registers the node again, kubeadm refuses to join an existing ready node
+		kubeClient, err := r.getKubeClient(ctx, contaboCluster)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+		if err := kubeClient.CoreV1().Nodes().Delete(ctx, nodeName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			log.Info("Failed to delete node before reinstalling, will retry", "node", nodeName, "error", err.Error())
+			return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, true, nil
+		}
+		// The bootstrap skips the instances carrying the cluster UUID, see bootstrapInstance
+		if _, result, _ := r.runMachineInstanceSshCommand(ctx, contaboMachine, contaboCluster, rolloutClusterUUIDCommand); result.RequeueAfter > 0 {
+			log.Info("Instance not reachable over SSH yet, will retry reinstalling")
+			return result, true, nil
+		}
+		log.Info("Node drained and removed, reinstalling the instance", "node", nodeName)
+		rollout.Phase = infrastructurev1beta2.ContaboMachineRolloutPhaseReinstalling
+		contaboMachine.Status.Available = false
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:   infrastructurev1beta2.InstanceBootstrapCondition,
+			Status: metav1.ConditionFalse,
+			Reason: infrastructurev1beta2.InstanceReinstallingReason,
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, true, nil
+	}
+
+	return ctrl.Result{}, false, nil
+}
+
+// completeRollout records the rollout once the reinstalled instance is bootstrapped again, the reinstallation itself
+// is run by the bootstrap which records the installed spec
+func (r *ContaboMachineReconciler) completeRollout(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) (ctrl.Result, bool, error) {
+	if !meta.IsStatusConditionTrue(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceBootstrapCondition) {
+		return ctrl.Result{}, false, nil
+	}
+	rollout := contaboMachine.Status.Rollout
+	rollout.Phase = infrastructurev1beta2.ContaboMachineRolloutPhaseCompleted
+	rollout.Message = ""
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceRolloutCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.RolloutCompletedReason,
+		Message: fmt.Sprintf("Reinstalled in %s", time.Since(rollout.StartTime.Time).Round(time.Second)),
+	})
+	logf.FromContext(ctx).Info("Template updates rolled out in place", "specHash", rollout.InstalledSpecHash)
+	r.Recorder.Event(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.RolloutCompletedReason, "Instance reinstalled with the template updates")
+	return ctrl.Result{}, false, nil
+}
+
+// failRollout marks the rollout as failed and uncordons the node, best effort. The rollout is retried on the next
+// update of the template.
+func (r *ContaboMachineReconciler) failRollout(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, message string) (ctrl.Result, bool, error) {
+	log := logf.FromContext(ctx)
+
+	log.Info("Failed to roll out the template updates in place", "reason", message)
+	if nodeName, err := machineNodeName(contaboMachine); err == nil {
+		if err := r.setNodeUnschedulable(ctx, contaboCluster, nodeName, false); err != nil {
+			log.Error(err, "Failed to uncordon node after rollout failure", "node", nodeName)
+		}
+	}
+	contaboMachine.Status.Rollout.Phase = infrastructurev1beta2.ContaboMachineRolloutPhaseFailed
+	contaboMachine.Status.Rollout.Message = message
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.InstanceRolloutCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrastructurev1beta2.RolloutFailedReason,
+		Message: message,
+	})
+	r.Recorder.Event(contaboMachine, corev1.EventTypeWarning, infrastructurev1beta2.RolloutFailedReason, message)
+	return ctrl.Result{}, true, nil
+}
+
+// templateRolloutInProgress returns the name of another machine of the template being rolled out, empty when none
+func (r *ContaboMachineReconciler) templateRolloutInProgress(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) (string, error) {
+	contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachines, client.InNamespace(contaboMachine.Namespace), client.MatchingLabels{
+		clusterv1.ClusterNameLabel: contaboMachine.Labels[clusterv1.ClusterNameLabel],
+	}); err != nil {
+		return "", fmt.Errorf("failed to list the machines of the template: %w", err)
+	}
+	template := contaboMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation]
+	for _, other := range contaboMachines.Items {
+		if other.Name == contaboMachine.Name || other.Annotations[clusterv1.TemplateClonedFromNameAnnotation] != template || other.Status.Rollout == nil {
+			continue
+		}
+		switch other.Status.Rollout.Phase {
+		case infrastructurev1beta2.ContaboMachineRolloutPhaseCordoning, infrastructurev1beta2.ContaboMachineRolloutPhaseDraining, infrastructurev1beta2.ContaboMachineRolloutPhaseReinstalling:
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// drainNode evicts the pods of the cordoned node, respecting their disruption budgets, and returns the number of
+// pods still running. The mirror and DaemonSet pods are not evicted.
+func (r *ContaboMachineReconciler) drainNode(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, nodeName string) (int, error) {
+	log := logf.FromContext(ctx)
+
+	kubeClient, err := r.getKubeClient(ctx, contaboCluster)
+	if err != nil {
+		return 0, err
+	}
+	pods, err := kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pods on node %s: %w", nodeName, err)
+	}
+
+	remaining := 0
+	for _, pod := range pods.Items {
+		if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+			continue
+		}
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		remaining++
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		err := kubeClient.CoreV1().Pods(pod.Namespace).EvictV1(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsTooManyRequests(err) {
+			log.Info("Failed to evict pod", "pod", client.ObjectKeyFromObject(&pod), "error", err.Error())
+		}
+	}
+	return remaining, nil
+}
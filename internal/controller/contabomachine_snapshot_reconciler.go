@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// DefaultMaxSnapshots is the number of snapshots allowed per instance when not configured
+	DefaultMaxSnapshots = 2
+
+	// SnapshotNamePrefix prefixes the name of the snapshots taken by the provider
+	SnapshotNamePrefix = "capc "
+)
+
+// ErrSnapshotLimitReached is returned when an instance holds the maximum number of snapshots and none can be pruned
+var ErrSnapshotLimitReached = errors.New("snapshot limit reached")
+
+// snapshotRetention returns the snapshot limit and pruning policy of the machine instance
+func snapshotRetention(contaboMachine *infrastructurev1beta2.ContaboMachine) (int, bool) {
+	retention := contaboMachine.Spec.Instance.Snapshots
+	if retention == nil {
+		return DefaultMaxSnapshots, true
+	}
+	maxSnapshots := int(retention.MaxSnapshots)
+	if maxSnapshots <= 0 {
+		maxSnapshots = DefaultMaxSnapshots
+	}
+	return maxSnapshots, ptr.Deref(retention.PruneOldest, true)
+}
+
+// selectSnapshotsToPrune returns the oldest snapshots taken by the provider to delete so that a new snapshot fits
+// within maxSnapshots. It returns ErrSnapshotLimitReached when pruning is disabled or not enough snapshots can be pruned.
+func selectSnapshotsToPrune(snapshots []models.SnapshotResponse, maxSnapshots int, pruneOldest bool) ([]models.SnapshotResponse, error) {
+	excess := len(snapshots) - maxSnapshots + 1
+	if excess <= 0 {
+		return nil, nil
+	}
+	if !pruneOldest {
+		return nil, fmt.Errorf("%w: %d/%d snapshots and pruning is disabled", ErrSnapshotLimitReached, len(snapshots), maxSnapshots)
+	}
+
+	candidates := []models.SnapshotResponse{}
+	for _, snapshot := range snapshots {
+		if strings.HasPrefix(snapshot.Name, SnapshotNamePrefix) {
+			candidates = append(candidates, snapshot)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].CreatedDate.Before(candidates[j].CreatedDate)
+	})
+	if len(candidates) < excess {
+		return nil, fmt.Errorf("%w: %d/%d snapshots and only %d taken by the provider can be pruned", ErrSnapshotLimitReached, len(snapshots), maxSnapshots, len(candidates))
+	}
+	return candidates[:excess], nil
+}
+
+// runSnapshotJob takes the snapshot of the instance of a Snapshot job, pruning the oldest snapshots taken by the
+// provider according to the retention settings, and tracks the snapshot count in the machine status. The snapshot is
+// named after the job, so that a snapshot taken by an attempt whose outcome was not recorded is found again.
+func (q *JobQueue) runSnapshotJob(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, job *infrastructurev1beta2.ContaboMachineJobStatus) error {
+	log := logf.FromContext(ctx)
+
+	resp, err := q.ContaboClient.RetrieveSnapshotListWithResponse(ctx, job.InstanceId, &models.RetrieveSnapshotListParams{
+		Size: ptr.To(int64(100)),
+	})
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		return fmt.Errorf("failed to list snapshots of instance %d: %w", job.InstanceId, err)
+	}
+	snapshots := resp.JSON200.Data
+	contaboMachine.Status.SnapshotCount = ptr.To(int32(len(snapshots)))
+
+	name := Truncate(SnapshotNamePrefix+job.Name, 30)
+	if i := slices.IndexFunc(snapshots, func(snapshot models.SnapshotResponse) bool { return snapshot.Name == name }); i >= 0 {
+		log.Info("Snapshot already taken by a previous attempt", "snapshotID", snapshots[i].SnapshotId)
+		job.SnapshotId = snapshots[i].SnapshotId
+		return nil
+	}
+
+	maxSnapshots, pruneOldest := snapshotRetention(contaboMachine)
+	toPrune, err := selectSnapshotsToPrune(snapshots, maxSnapshots, pruneOldest)
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range toPrune {
+		log.Info("Pruning oldest snapshot to stay within the snapshot limit",
+			"snapshotID", snapshot.SnapshotId, "createdDate", snapshot.CreatedDate, "maxSnapshots", maxSnapshots)
+		deleteResp, err := q.ContaboClient.DeleteSnapshotWithResponse(ctx, job.InstanceId, snapshot.SnapshotId, nil)
+		if err := contabo.CheckResponse(deleteResp, err); err != nil {
+			return fmt.Errorf("failed to prune snapshot %s of instance %d: %w", snapshot.SnapshotId, job.InstanceId, err)
+		}
+		contaboMachine.Status.SnapshotCount = ptr.To(*contaboMachine.Status.SnapshotCount - 1)
+	}
+
+	request := models.CreateSnapshotJSONRequestBody{Name: name}
+	if job.Description != "" {
+		request.Description = ptr.To(job.Description)
+	}
+	createResp, err := q.ContaboClient.CreateSnapshotWithResponse(ctx, job.InstanceId, contabo.NewParams[models.CreateSnapshotParams](ctx), request)
+	if err := contabo.CheckResponse(createResp, err); err != nil || createResp.JSON201 == nil || len(createResp.JSON201.Data) == 0 {
+		if err == nil {
+			err = errors.New("no snapshot in the response")
+		}
+		return fmt.Errorf("failed to snapshot instance %d: %w", job.InstanceId, err)
+	}
+	job.SnapshotId = createResp.JSON201.Data[0].SnapshotId
+	contaboMachine.Status.SnapshotCount = ptr.To(*contaboMachine.Status.SnapshotCount + 1)
+	log.Info("Snapshot taken", "snapshotID", job.SnapshotId)
+	return nil
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// FormatMachineSshKeyContaboName returns the name of the Contabo secret of an SSH key secret overriding the cluster
+// SSH key. The name starts with the one of the cluster SSH key so that the secret is deleted with the cluster.
+func FormatMachineSshKeyContaboName(contaboCluster *infrastructurev1beta2.ContaboCluster, secretName string) string {
+	return Truncate(fmt.Sprintf("%s %s", FormatSshKeyContaboName(contaboCluster), secretName), 255)
+}
+
+// machineSSHKeyID returns the Contabo secret ID of the SSH key installed on the instance: the one of the SSH key
+// secret of the machine when registered, the cluster SSH key otherwise
+func machineSSHKeyID(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) int64 {
+	if contaboMachine.Spec.Instance.SshKeySecretName != "" && contaboMachine.Status.SshKey != nil {
+		return contaboMachine.Status.SshKey.SecretId
+	}
+	return contaboCluster.Status.SshKey.SecretId
+}
+
+// machineSSHKeySecretName returns the name of the Kubernetes secret holding the SSH key pair of the instance
+func machineSSHKeySecretName(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	if contaboMachine.Spec.Instance.SshKeySecretName != "" && contaboMachine.Status.SshKey != nil {
+		return contaboMachine.Spec.Instance.SshKeySecretName
+	}
+	return FormatSshKeyKubernetesName(contaboCluster)
+}
+
+// reconcileMachineSSHKey registers the public key of spec.instance.sshKeySecretName as a Contabo secret, updating
+// its value when the key pair of the secret was replaced. Machines without the field use the cluster SSH key.
+func (r *ContaboMachineReconciler) reconcileMachineSSHKey(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) ctrl.Result {
+	log := logf.FromContext(ctx)
+
+	secretName := contaboMachine.Spec.Instance.SshKeySecretName
+	if secretName == "" {
+		contaboMachine.Status.SshKey = nil
+		meta.RemoveStatusCondition(&contaboMachine.Status.Conditions, infrastructurev1beta2.MachineSshKeyReadyCondition)
+		return ctrl.Result{}
+	}
+
+	publicKey, err := r.machineSSHPublicKey(ctx, contaboMachine.Namespace, secretName)
+	if err != nil {
+		log.Info("Waiting for the SSH key secret of the machine", "secretName", secretName, "error", err.Error())
+		meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.MachineSshKeyReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.MachineSshKeyFailedReason,
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}
+	}
+
+	sshKeyContaboName := FormatMachineSshKeyContaboName(contaboCluster, secretName)
+	if sshKey := contaboMachine.Status.SshKey; sshKey == nil || sshKey.Name != sshKeyContaboName || sshKey.Value != publicKey {
+		sshKey, err := r.findOrCreateMachineSSHKey(ctx, sshKeyContaboName, publicKey)
+		if err != nil {
+			log.Info("Failed to register the SSH key of the machine", "sshKeyContaboName", sshKeyContaboName, "error", err.Error())
+			meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.MachineSshKeyReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.MachineSshKeyCreatingReason,
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}
+		}
+		log.Info("Registered the SSH key of the machine", "sshKeyContaboName", sshKey.Name, "sshKeyID", sshKey.SecretId)
+		contaboMachine.Status.SshKey = sshKey
+	}
+
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, metav1.Condition{
+		Type:    infrastructurev1beta2.MachineSshKeyReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrastructurev1beta2.MachineSshKeyReadyReason,
+		Message: fmt.Sprintf("SSH key of secret %s replaces the cluster SSH key", secretName),
+	})
+	return ctrl.Result{}
+}
+
+// machineSSHPublicKey returns the validated public key of an SSH key secret in the authorized_keys format
+func (r *ContaboMachineReconciler) machineSSHPublicKey(ctx context.Context, namespace, secretName string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); err != nil {
+		return "", fmt.Errorf("failed to get SSH key secret %s/%s: %w", namespace, secretName, err)
+	}
+	privateKey, publicKey, err := sshKeyPairFromSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the private key of SSH key secret %s/%s: %w", namespace, secretName, err)
+	}
+	parsedPublicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the public key of SSH key secret %s/%s: %w", namespace, secretName, err)
+	}
+	// A mismatched key pair would install a key the provider cannot log in with
+	if string(parsedPublicKey.Marshal()) != string(signer.PublicKey().Marshal()) {
+		return "", fmt.Errorf("the private and public keys of SSH key secret %s/%s do not match", namespace, secretName)
+	}
+	return strings.TrimSpace(publicKey), nil
+}
+
+// findOrCreateMachineSSHKey returns the Contabo secret with the name holding the public key, the secret is created when
+// missing and its value is updated when it holds another key
+func (r *ContaboMachineReconciler) findOrCreateMachineSSHKey(ctx context.Context, name, publicKey string) (*infrastructurev1beta2.ContaboSshKeyStatus, error) {
+	listResp, err := r.ContaboClient.RetrieveSecretListWithResponse(ctx, &models.RetrieveSecretListParams{
+		Name: &name,
+		Type: ptr.To(models.Ssh),
+	})
+	if err := contabo.CheckResponse(listResp, err); err != nil {
+		return nil, fmt.Errorf("failed to look up SSH key: %w", err)
+	}
+
+	var secret *models.SecretResponse
+	for i := range listResp.JSON200.Data {
+		if listResp.JSON200.Data[i].Name == name {
+			secret = &listResp.JSON200.Data[i]
+			break
+		}
+	}
+
+	switch {
+	case secret == nil:
+		createResp, err := r.ContaboClient.CreateSecretWithResponse(ctx, contabo.NewParams[models.CreateSecretParams](ctx), models.CreateSecretRequest{
+			Name:  name,
+			Value: publicKey,
+			Type:  models.CreateSecretRequestTypeSsh,
+		})
+		if err := contabo.CheckResponse(createResp, err); err != nil {
+			return nil, fmt.Errorf("failed to create SSH key: %w", err)
+		}
+		if createResp.JSON201 == nil || len(createResp.JSON201.Data) == 0 {
+			return nil, errors.New("failed to create SSH key: no secret in the response")
+		}
+		secret = &createResp.JSON201.Data[0]
+	case secret.Value != publicKey:
+		updateResp, err := r.ContaboClient.UpdateSecretWithResponse(ctx, int64(secret.SecretId), contabo.NewParams[models.UpdateSecretParams](ctx), models.UpdateSecretRequest{
+			Value: &publicKey,
+		})
+		if err := contabo.CheckResponse(updateResp, err); err != nil {
+			return nil, fmt.Errorf("failed to update SSH key %d: %w", int64(secret.SecretId), err)
+		}
+		secret.Value = publicKey
+	}
+
+	return &infrastructurev1beta2.ContaboSshKeyStatus{
+		Name:     secret.Name,
+		SecretId: int64(secret.SecretId),
+		Value:    secret.Value,
+	}, nil
+}
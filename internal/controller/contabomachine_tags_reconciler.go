@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// TagRefreshInterval is the minimum time between two comparisons of tag assignments already in sync
+	TagRefreshInterval = 10 * time.Minute
+
+	// TagDefaultColor is the color of the tags created by the provider, the Contabo default
+	TagDefaultColor = "#0A78C3"
+
+	// tagResourceTypeInstance is the assignment resource type of instances
+	tagResourceTypeInstance = "instance"
+
+	// tagPageSize is the page size used to list tags and tag assignments
+	tagPageSize = 100
+)
+
+// desiredTags returns the sorted tag names of the spec and the provider tag of the cluster without duplicates
+func desiredTags(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) []string {
+	tags := slices.Clone(contaboMachine.Spec.Instance.Tags)
+	if tag := providerTag(contaboCluster); tag != "" {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// managedTagNames returns the sorted names of the tags assigned by the provider
+func managedTagNames(tags []infrastructurev1beta2.ContaboTagStatus) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// diffTagAssignments returns the tags to assign and unassign to reach the desired tags from the current assignments.
+// Only the tags assigned by the provider are unassigned, tags assigned by someone else are left untouched.
+func diffTagAssignments(desired, managed []string, assigned map[string]bool) ([]string, []string) {
+	toAssign, toUnassign := []string{}, []string{}
+	for _, name := range desired {
+		if !assigned[name] {
+			toAssign = append(toAssign, name)
+		}
+	}
+	for _, name := range managed {
+		if !slices.Contains(desired, name) && assigned[name] {
+			toUnassign = append(toUnassign, name)
+		}
+	}
+	return toAssign, toUnassign
+}
+
+// reconcileTags assigns the tags of the spec to the instance. The assignments are compared with the Contabo API and
+// only the missing or removed ones are changed, tags in sync are compared again after TagRefreshInterval.
+// Failures are only logged and retried on the next reconciliation.
+func (r *ContaboMachineReconciler) reconcileTags(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	instance := contaboMachine.Status.Instance
+	if instance == nil {
+		return
+	}
+	desired := desiredTags(contaboMachine, contaboCluster)
+	managed := managedTagNames(contaboMachine.Status.Tags)
+	if slices.Equal(desired, managed) && (len(desired) == 0 ||
+		(contaboMachine.Status.TagsLastUpdated != nil && time.Since(contaboMachine.Status.TagsLastUpdated.Time) < TagRefreshInterval)) {
+		return
+	}
+
+	tagIds := map[string]int64{}
+	for _, tag := range contaboMachine.Status.Tags {
+		tagIds[tag.Name] = tag.TagId
+	}
+	for _, name := range desired {
+		if _, ok := tagIds[name]; ok {
+			continue
+		}
+		tagId, err := r.findOrCreateTag(ctx, name)
+		if err != nil {
+			log.Info("Failed to find or create tag", "tag", name, "error", err)
+			return
+		}
+		tagIds[name] = tagId
+	}
+
+	resourceId := strconv.FormatInt(instance.InstanceId, 10)
+	assigned := map[string]bool{}
+	for name, tagId := range tagIds {
+		isAssigned, found, err := r.tagAssigned(ctx, tagId, resourceId)
+		if err != nil {
+			log.Info("Failed to retrieve tag assignments", "tag", name, "tagID", tagId, "error", err)
+			return
+		}
+		// A tag deleted outside of the provider is created again
+		if !found && slices.Contains(desired, name) {
+			if tagIds[name], err = r.findOrCreateTag(ctx, name); err != nil {
+				log.Info("Failed to find or create tag", "tag", name, "error", err)
+				return
+			}
+		}
+		assigned[name] = isAssigned
+	}
+
+	toAssign, toUnassign := diffTagAssignments(desired, managed, assigned)
+	for _, name := range toAssign {
+		resp, err := r.ContaboClient.CreateAssignmentWithResponse(ctx, tagIds[name], tagResourceTypeInstance, resourceId, nil)
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			log.Info("Failed to assign tag", "tag", name, "instanceID", instance.InstanceId, "error", err)
+			return
+		}
+		log.Info("Assigned tag", "tag", name, "instanceID", instance.InstanceId)
+	}
+	for _, name := range toUnassign {
+		resp, err := r.ContaboClient.DeleteAssignmentWithResponse(ctx, tagIds[name], tagResourceTypeInstance, resourceId, nil)
+		if err := contabo.CheckResponse(resp, err); err != nil && !errors.Is(err, contabo.ErrNotFound) {
+			log.Info("Failed to unassign tag", "tag", name, "instanceID", instance.InstanceId, "error", err)
+			return
+		}
+		log.Info("Unassigned tag", "tag", name, "instanceID", instance.InstanceId)
+	}
+
+	tags := []infrastructurev1beta2.ContaboTagStatus{}
+	for _, name := range desired {
+		tags = append(tags, infrastructurev1beta2.ContaboTagStatus{Name: name, TagId: tagIds[name], Provider: name == providerTag(contaboCluster)})
+	}
+	contaboMachine.Status.Tags = tags
+	contaboMachine.Status.TagsLastUpdated = ptr.To(metav1.Now())
+}
+
+// unassignTags removes the tags assigned by the provider from an instance released by the machine, on a best
+// effort basis. The provider tag of a partially adopted cluster is kept, the instance stays managed.
+func (r *ContaboMachineReconciler) unassignTags(ctx context.Context, instanceId int64, tags []infrastructurev1beta2.ContaboTagStatus) {
+	log := logf.FromContext(ctx)
+
+	resourceId := strconv.FormatInt(instanceId, 10)
+	for _, tag := range tags {
+		if tag.Provider {
+			continue
+		}
+		resp, err := r.ContaboClient.DeleteAssignmentWithResponse(ctx, tag.TagId, tagResourceTypeInstance, resourceId, nil)
+		if err := contabo.CheckResponse(resp, err); err != nil && !errors.Is(err, contabo.ErrNotFound) {
+			log.Info("Failed to unassign tag from released instance", "tag", tag.Name, "instanceID", instanceId, "error", err)
+		}
+	}
+}
+
+// findOrCreateTag returns the ID of the tag with the exact name, the tag is created when missing
+func (r *ContaboMachineReconciler) findOrCreateTag(ctx context.Context, name string) (int64, error) {
+	tagIds, err := findTagIds(ctx, r.ContaboClient, name)
+	if err != nil {
+		return 0, err
+	}
+	if len(tagIds) > 0 {
+		return tagIds[0], nil
+	}
+
+	resp, err := r.ContaboClient.CreateTagWithResponse(ctx, nil, models.CreateTagRequest{
+		Name:  name,
+		Color: TagDefaultColor,
+	})
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		return 0, fmt.Errorf("failed to create tag: %w", err)
+	}
+	if resp.JSON201 == nil || len(resp.JSON201.Data) == 0 {
+		return 0, errors.New("failed to create tag: no tag in the response")
+	}
+	return resp.JSON201.Data[0].TagId, nil
+}
+
+// tagAssigned returns whether the tag is assigned to the instance and whether the tag exists
+func (r *ContaboMachineReconciler) tagAssigned(ctx context.Context, tagId int64, resourceId string) (bool, bool, error) {
+	for page := int64(1); ; page++ {
+		resp, err := r.ContaboClient.RetrieveAssignmentListWithResponse(ctx, tagId, &models.RetrieveAssignmentListParams{
+			Page:         &page,
+			Size:         ptr.To(int64(tagPageSize)),
+			ResourceType: ptr.To(tagResourceTypeInstance),
+		})
+		if err := contabo.CheckResponse(resp, err); errors.Is(err, contabo.ErrNotFound) {
+			return false, false, nil
+		} else if err != nil {
+			return false, false, err
+		}
+		for _, assignment := range resp.JSON200.Data {
+			if assignment.ResourceType == tagResourceTypeInstance && assignment.ResourceId == resourceId {
+				return true, true, nil
+			}
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			return false, true, nil
+		}
+	}
+}
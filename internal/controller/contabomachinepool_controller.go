@@ -0,0 +1,549 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	exputil "sigs.k8s.io/cluster-api/exp/util"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/labels/format"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// contaboMachineKind is the kind of the machines of a ContaboMachinePool, reported to the MachinePool
+const contaboMachineKind = "ContaboMachine"
+
+// ContaboMachinePoolReconciler reconciles a ContaboMachinePool object. A ContaboMachine is created for each replica of
+// the MachinePool, Cluster API then creates a Machine for each of them and the ContaboMachine controller provisions
+// their instances.
+type ContaboMachinePoolReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Settings *ProviderSettings
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachinepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachinepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachinepools/finalizers,verbs=update
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch;delete
+
+// Reconcile scales the machines of the pool to the replicas of its MachinePool and replaces the machines of a
+// previous template
+func (r *ContaboMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	log.V(1).Info("Reconciling ContaboMachinePool", "namespace", req.Namespace, "name", req.Name)
+
+	pool := &infrastructurev1beta2.ContaboMachinePool{}
+	if err := r.Get(ctx, req.NamespacedName, pool); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The machines are found through their owner reference, the MachinePool may already be gone
+	if !pool.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, pool)
+	}
+
+	machinePool, err := exputil.GetOwnerMachinePool(ctx, r.Client, pool.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machinePool == nil {
+		log.Info("MachinePool Controller has not yet set OwnerRef, requeuing", "contaboMachinePool", pool.Name)
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	log = log.WithValues("machinePool", machinePool.Name)
+	ctx = logf.IntoContext(ctx, log)
+
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		log.Info("MachinePool is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, nil
+	}
+	if annotations.IsPaused(cluster, pool) {
+		log.Info("ContaboMachinePool or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(pool, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	migrateFinalizer(pool, infrastructurev1beta2.MachinePoolFinalizer)
+	controllerutil.AddFinalizer(pool, infrastructurev1beta2.MachinePoolFinalizer)
+	result, err := r.reconcileNormal(ctx, pool, machinePool)
+
+	if patchErr := patchHelper.Patch(ctx, pool); patchErr != nil {
+		if apierrors.IsConflict(patchErr) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, patchErr
+	}
+	return result, err
+}
+
+// reconcileNormal creates the missing machines of the pool and deletes the extra, failed and outdated ones. The
+// outdated machines are only deleted once enough machines of the current template are ready, at most MaxSurge
+// machines are created above the replicas of the MachinePool meanwhile.
+func (r *ContaboMachinePoolReconciler) reconcileNormal(ctx context.Context, pool *infrastructurev1beta2.ContaboMachinePool, machinePool *clusterv1.MachinePool) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	pool.Status.InfrastructureMachineKind = contaboMachineKind
+	pool.Status.TemplateHash = machinePoolTemplateHash(pool.Spec.Template, machinePool.Spec.Template.Spec.Version)
+
+	contaboMachines, err := r.poolMachines(ctx, pool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	machines, err := r.poolMachineOwners(ctx, pool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The instances parked by a previous template are not claimed back, they return to the free instances, see
+	// findReusableInstance
+	pool.Status.ParkedInstances = slices.DeleteFunc(pool.Status.ParkedInstances, func(parked infrastructurev1beta2.ContaboMachinePoolParkedInstance) bool {
+		return parked.TemplateHash != pool.Status.TemplateHash
+	})
+
+	// Failed machines are replaced, e.g. their instance was cancelled outside of Kubernetes
+	upToDate := []*infrastructurev1beta2.ContaboMachine{}
+	outdated := []*infrastructurev1beta2.ContaboMachine{}
+	deleting := 0
+	for _, contaboMachine := range contaboMachines {
+		switch {
+		case machinePoolMachineDeleting(contaboMachine, machines):
+			deleting++
+		case contaboMachine.Status.FailureReason != nil:
+			log.Info("Replacing failed machine of the pool", "contaboMachine", contaboMachine.Name, "failureReason", *contaboMachine.Status.FailureReason)
+			if err := r.deletePoolMachine(ctx, contaboMachine, machines); err != nil {
+				return ctrl.Result{}, err
+			}
+			deleting++
+		case contaboMachine.Labels[infrastructurev1beta2.MachinePoolTemplateHashLabel] == pool.Status.TemplateHash:
+			upToDate = append(upToDate, contaboMachine)
+		default:
+			outdated = append(outdated, contaboMachine)
+		}
+	}
+
+	desired := int(ptr.Deref(machinePool.Spec.Replicas, 1))
+	limit := desired
+	if len(outdated) > 0 {
+		limit += int(max(pool.Spec.MaxSurge, 1))
+	}
+
+	// Create the machines of the current template up to the replicas, and above them up to MaxSurge while replacing
+	if missing := min(desired-len(upToDate), limit-len(upToDate)-len(outdated)); missing > 0 {
+		for range missing {
+			instanceName := claimParkedInstance(pool, contaboMachines)
+			contaboMachine, err := r.createPoolMachine(ctx, pool, machinePool, instanceName)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			log.Info("Created machine of the pool", "contaboMachine", contaboMachine.Name, "templateHash", pool.Status.TemplateHash,
+				"parkedInstance", instanceName)
+			r.Recorder.Eventf(pool, corev1.EventTypeNormal, infrastructurev1beta2.MachinePoolScalingReason, "Created ContaboMachine %s", contaboMachine.Name)
+			upToDate = append(upToDate, contaboMachine)
+		}
+	}
+
+	// Scale down the machines of the current template, the ones which are not ready first
+	sortPoolMachinesForDeletion(upToDate)
+	for len(upToDate) > desired {
+		if err := r.parkPoolMachineInstance(ctx, pool, upToDate[0]); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.deletePoolMachine(ctx, upToDate[0], machines); err != nil {
+			return ctrl.Result{}, err
+		}
+		deleting++
+		log.Info("Scaled down machine of the pool", "contaboMachine", upToDate[0].Name)
+		r.Recorder.Eventf(pool, corev1.EventTypeNormal, infrastructurev1beta2.MachinePoolScalingReason, "Deleted ContaboMachine %s", upToDate[0].Name)
+		upToDate = upToDate[1:]
+	}
+
+	// Delete the outdated machines once they are not needed to keep the replicas ready
+	ready := countReadyPoolMachines(upToDate) + countReadyPoolMachines(outdated)
+	sortPoolMachinesForDeletion(outdated)
+	for len(outdated) > 0 && len(upToDate)+len(outdated) > desired {
+		if outdated[0].Status.Ready {
+			if ready-1 < desired {
+				break
+			}
+			ready--
+		}
+		if err := r.deletePoolMachine(ctx, outdated[0], machines); err != nil {
+			return ctrl.Result{}, err
+		}
+		deleting++
+		log.Info("Replaced outdated machine of the pool", "contaboMachine", outdated[0].Name)
+		r.Recorder.Eventf(pool, corev1.EventTypeNormal, infrastructurev1beta2.MachinePoolRollingUpdateReason, "Deleted outdated ContaboMachine %s", outdated[0].Name)
+		outdated = outdated[1:]
+	}
+
+	r.updatePoolStatus(pool, desired, deleting, upToDate, outdated)
+	if pool.Status.Ready {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+}
+
+// updatePoolStatus reports the machines of the pool to the MachinePool. A pool scaled to zero is ready once the
+// instances of its machines are released.
+func (r *ContaboMachinePoolReconciler) updatePoolStatus(pool *infrastructurev1beta2.ContaboMachinePool, desired, deleting int, upToDate, outdated []*infrastructurev1beta2.ContaboMachine) {
+	providerIDs := []string{}
+	for _, contaboMachine := range slices.Concat(upToDate, outdated) {
+		if contaboMachine.Status.Ready && contaboMachine.Spec.ProviderID != nil {
+			providerIDs = append(providerIDs, *contaboMachine.Spec.ProviderID)
+		}
+	}
+	sort.Strings(providerIDs)
+
+	readyUpToDate := countReadyPoolMachines(upToDate)
+	pool.Spec.ProviderIDList = providerIDs
+	pool.Status.Replicas = int32(len(upToDate) + len(outdated))
+	pool.Status.ReadyReplicas = int32(len(providerIDs))
+	pool.Status.UpToDateReplicas = int32(len(upToDate))
+	pool.Status.Ready = readyUpToDate == desired && len(upToDate) == desired && len(outdated) == 0 && (desired > 0 || deleting == 0)
+	if pool.Status.Ready && pool.Status.Initialization == nil {
+		pool.Status.Initialization = &infrastructurev1beta2.ContaboMachinePoolInitializationStatus{Provisioned: true}
+	}
+
+	switch {
+	case pool.Status.Ready && desired == 0:
+		meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.MachinePoolReplicasReadyCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  infrastructurev1beta2.MachinePoolScaledToZeroReason,
+			Message: fmt.Sprintf("The pool is scaled to zero, %d instances are parked for reuse", len(pool.Status.ParkedInstances)),
+		})
+	case pool.Status.Ready:
+		meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+			Type:   infrastructurev1beta2.MachinePoolReplicasReadyCondition,
+			Status: metav1.ConditionTrue,
+			Reason: infrastructurev1beta2.MachinePoolReplicasReadyReason,
+		})
+	case len(outdated) > 0:
+		meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.MachinePoolReplicasReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.MachinePoolRollingUpdateReason,
+			Message: fmt.Sprintf("Replacing %d outdated machines, %d of %d machines are up-to-date and ready", len(outdated), readyUpToDate, desired),
+		})
+	case desired == 0:
+		meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.MachinePoolReplicasReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.MachinePoolScalingReason,
+			Message: fmt.Sprintf("Scaling to zero, releasing the instances of %d machines", deleting+len(upToDate)+len(outdated)),
+		})
+	default:
+		meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.MachinePoolReplicasReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.MachinePoolScalingReason,
+			Message: fmt.Sprintf("%d of %d machines are ready", readyUpToDate, desired),
+		})
+	}
+}
+
+// parkPoolMachineInstance records the instance of a machine of the pool scaled down, so that the pool claims it back
+// when it scales up. The machine is annotated with the ParkedInstanceAnnotation so that its instance is released with
+// the reserved display name. The instances cancelled by the reuse policy of the machine are not parked, nor the
+// instances beyond MaxParkedInstances which are released to the free instances.
+func (r *ContaboMachinePoolReconciler) parkPoolMachineInstance(ctx context.Context, pool *infrastructurev1beta2.ContaboMachinePool, contaboMachine *infrastructurev1beta2.ContaboMachine) error {
+	instance := contaboMachine.Status.Instance
+	if instance == nil || instance.Name == "" || cancelsInstance(contaboMachine) || slices.ContainsFunc(pool.Status.ParkedInstances, func(parked infrastructurev1beta2.ContaboMachinePoolParkedInstance) bool {
+		return parked.Name == instance.Name
+	}) {
+		return nil
+	}
+	if len(pool.Status.ParkedInstances) >= infrastructurev1beta2.MaxParkedInstances {
+		logf.FromContext(ctx).Info("Too many parked instances, releasing the instance of the machine to the free instances",
+			"contaboMachine", contaboMachine.Name, "instanceName", instance.Name, "maxParkedInstances", infrastructurev1beta2.MaxParkedInstances)
+		return nil
+	}
+
+	patchBase := client.MergeFrom(contaboMachine.DeepCopy())
+	if contaboMachine.Annotations == nil {
+		contaboMachine.Annotations = map[string]string{}
+	}
+	contaboMachine.Annotations[infrastructurev1beta2.ParkedInstanceAnnotation] = pool.Name
+	if err := r.Patch(ctx, contaboMachine, patchBase); err != nil {
+		return fmt.Errorf("failed to park the instance of ContaboMachine %s: %w", contaboMachine.Name, err)
+	}
+	pool.Status.ParkedInstances = append(pool.Status.ParkedInstances, infrastructurev1beta2.ContaboMachinePoolParkedInstance{
+		Name:         instance.Name,
+		MachineName:  contaboMachine.Name,
+		TemplateHash: contaboMachine.Labels[infrastructurev1beta2.MachinePoolTemplateHashLabel],
+		ParkedTime:   metav1.Now(),
+	})
+	return nil
+}
+
+// claimParkedInstance returns the name of a parked instance of the pool whose machine is deleted, and removes it from
+// the parked instances, or an empty name when none is released yet. The instance of a machine still being deleted is
+// not reset for reuse, the new machine would order a new instance instead of waiting for it.
+func claimParkedInstance(pool *infrastructurev1beta2.ContaboMachinePool, contaboMachines []*infrastructurev1beta2.ContaboMachine) string {
+	for i, parked := range pool.Status.ParkedInstances {
+		if slices.ContainsFunc(contaboMachines, func(contaboMachine *infrastructurev1beta2.ContaboMachine) bool {
+			return contaboMachine.Name == parked.MachineName
+		}) {
+			continue
+		}
+		pool.Status.ParkedInstances = slices.Delete(pool.Status.ParkedInstances, i, i+1)
+		return parked.Name
+	}
+	return ""
+}
+
+// reconcileDelete deletes the machines of the pool, the pool is removed once their instances are released
+func (r *ContaboMachinePoolReconciler) reconcileDelete(ctx context.Context, pool *infrastructurev1beta2.ContaboMachinePool) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if !hasFinalizer(pool, infrastructurev1beta2.MachinePoolFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	contaboMachines, err := r.poolMachines(ctx, pool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(contaboMachines) > 0 {
+		machines, err := r.poolMachineOwners(ctx, pool)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		for _, contaboMachine := range contaboMachines {
+			if machinePoolMachineDeleting(contaboMachine, machines) {
+				continue
+			}
+			if err := r.deletePoolMachine(ctx, contaboMachine, machines); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		log.Info("Waiting for the machines of the pool to be deleted", "machines", len(contaboMachines))
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(pool, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	removeFinalizer(pool, infrastructurev1beta2.MachinePoolFinalizer)
+	return ctrl.Result{}, client.IgnoreNotFound(patchHelper.Patch(ctx, pool))
+}
+
+// createPoolMachine creates a ContaboMachine from the template of the pool, reusing the parked instance named
+// instanceName when set. The MachinePool labels let Cluster API create its Machine, the owner reference lets the pool
+// find it back. The machine falls back to another instance when it fails to claim the parked one, see
+// claimParkedInstance of the ContaboMachine controller.
+func (r *ContaboMachinePoolReconciler) createPoolMachine(ctx context.Context, pool *infrastructurev1beta2.ContaboMachinePool, machinePool *clusterv1.MachinePool, instanceName string) (*infrastructurev1beta2.ContaboMachine, error) {
+	contaboMachine := &infrastructurev1beta2.ContaboMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pool.Name + "-",
+			Namespace:    pool.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:                         machinePool.Spec.ClusterName,
+				clusterv1.MachinePoolNameLabel:                     format.MustFormatValue(machinePool.Name),
+				infrastructurev1beta2.MachinePoolTemplateHashLabel: pool.Status.TemplateHash,
+			},
+			// Not the controller reference, it is set by the Machine
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: infrastructurev1beta2.GroupVersion.String(),
+				Kind:       "ContaboMachinePool",
+				Name:       pool.Name,
+				UID:        pool.UID,
+			}},
+		},
+		Spec: *pool.Spec.Template.Spec.DeepCopy(),
+	}
+	// The labels and annotations of the template are copied like Cluster API does, the pool labels win
+	for key, value := range pool.Spec.Template.ObjectMeta.Labels {
+		if _, ok := contaboMachine.Labels[key]; !ok {
+			contaboMachine.Labels[key] = value
+		}
+	}
+	if len(pool.Spec.Template.ObjectMeta.Annotations) > 0 {
+		contaboMachine.Annotations = maps.Clone(pool.Spec.Template.ObjectMeta.Annotations)
+	}
+	if instanceName != "" {
+		contaboMachine.Spec.Instance.Name = ptr.To(instanceName)
+		if contaboMachine.Annotations == nil {
+			contaboMachine.Annotations = map[string]string{}
+		}
+		contaboMachine.Annotations[infrastructurev1beta2.ParkedInstanceAnnotation] = pool.Name
+	}
+	if err := r.Create(ctx, contaboMachine); err != nil {
+		return nil, fmt.Errorf("failed to create ContaboMachine of ContaboMachinePool %s: %w", pool.Name, err)
+	}
+	return contaboMachine, nil
+}
+
+// deletePoolMachine deletes the Machine of a ContaboMachine of the pool, so that its node is drained before its
+// instance is released, or the ContaboMachine itself when Cluster API did not create its Machine yet
+func (r *ContaboMachinePoolReconciler) deletePoolMachine(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, machines map[string]*clusterv1.Machine) error {
+	if machine, ok := machines[contaboMachine.Name]; ok {
+		if err := r.Delete(ctx, machine); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete Machine %s: %w", machine.Name, err)
+		}
+		return nil
+	}
+	if err := r.Delete(ctx, contaboMachine); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete ContaboMachine %s: %w", contaboMachine.Name, err)
+	}
+	return nil
+}
+
+// poolMachines returns the ContaboMachines owned by the pool, oldest first
+func (r *ContaboMachinePoolReconciler) poolMachines(ctx context.Context, pool *infrastructurev1beta2.ContaboMachinePool) ([]*infrastructurev1beta2.ContaboMachine, error) {
+	contaboMachineList := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachineList, client.InNamespace(pool.Namespace), client.HasLabels{clusterv1.MachinePoolNameLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboMachines: %w", err)
+	}
+	contaboMachines := []*infrastructurev1beta2.ContaboMachine{}
+	for i := range contaboMachineList.Items {
+		contaboMachine := &contaboMachineList.Items[i]
+		if slices.ContainsFunc(contaboMachine.OwnerReferences, func(owner metav1.OwnerReference) bool {
+			return owner.UID == pool.UID
+		}) {
+			contaboMachines = append(contaboMachines, contaboMachine)
+		}
+	}
+	sort.SliceStable(contaboMachines, func(i, j int) bool {
+		if !contaboMachines[i].CreationTimestamp.Equal(&contaboMachines[j].CreationTimestamp) {
+			return contaboMachines[i].CreationTimestamp.Before(&contaboMachines[j].CreationTimestamp)
+		}
+		return contaboMachines[i].Name < contaboMachines[j].Name
+	})
+	return contaboMachines, nil
+}
+
+// poolMachineOwners returns the Machines created by Cluster API for the machines of the pool, by ContaboMachine name
+func (r *ContaboMachinePoolReconciler) poolMachineOwners(ctx context.Context, pool *infrastructurev1beta2.ContaboMachinePool) (map[string]*clusterv1.Machine, error) {
+	machineList := &clusterv1.MachineList{}
+	if err := r.List(ctx, machineList, client.InNamespace(pool.Namespace), client.HasLabels{clusterv1.MachinePoolNameLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list Machines: %w", err)
+	}
+	machines := map[string]*clusterv1.Machine{}
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+		if machine.Spec.InfrastructureRef.Kind == contaboMachineKind {
+			machines[machine.Spec.InfrastructureRef.Name] = machine
+		}
+	}
+	return machines, nil
+}
+
+// machinePoolMachineDeleting returns true when the ContaboMachine or its Machine is being deleted
+func machinePoolMachineDeleting(contaboMachine *infrastructurev1beta2.ContaboMachine, machines map[string]*clusterv1.Machine) bool {
+	if !contaboMachine.DeletionTimestamp.IsZero() {
+		return true
+	}
+	machine, ok := machines[contaboMachine.Name]
+	return ok && !machine.DeletionTimestamp.IsZero()
+}
+
+// sortPoolMachinesForDeletion orders the machines which are not ready first, then the newest first
+func sortPoolMachinesForDeletion(contaboMachines []*infrastructurev1beta2.ContaboMachine) {
+	slices.Reverse(contaboMachines)
+	sort.SliceStable(contaboMachines, func(i, j int) bool {
+		return !contaboMachines[i].Status.Ready && contaboMachines[j].Status.Ready
+	})
+}
+
+// countReadyPoolMachines returns the number of ready machines
+func countReadyPoolMachines(contaboMachines []*infrastructurev1beta2.ContaboMachine) int {
+	ready := 0
+	for _, contaboMachine := range contaboMachines {
+		if contaboMachine.Status.Ready {
+			ready++
+		}
+	}
+	return ready
+}
+
+// machinePoolTemplateHash returns the hash of the template of a pool and of the Kubernetes version of its MachinePool,
+// so that the upgrades of the MachinePool replace the machines as well
+func machinePoolTemplateHash(template infrastructurev1beta2.ContaboMachineTemplateResource, version string) string {
+	// Marshalling the spec never fails, the map keys are sorted
+	data, _ := json.Marshal(template.Spec)
+	sum := sha256.Sum256(append(data, version...))
+	return hex.EncodeToString(sum[:8])
+}
+
+// machinePoolBootstrapDataSecretName returns the bootstrap data secret of the MachinePool of a Machine created for a
+// ContaboMachinePool, Cluster API leaves the bootstrap of these Machines empty
+func machinePoolBootstrapDataSecretName(ctx context.Context, c client.Client, machine *clusterv1.Machine) (*string, error) {
+	if _, ok := machine.Labels[clusterv1.MachinePoolNameLabel]; !ok {
+		return nil, nil
+	}
+	machinePool, err := exputil.GetMachinePoolByLabels(ctx, c, machine.Namespace, machine.Labels)
+	if err != nil || machinePool == nil {
+		return nil, err
+	}
+	return machinePool.Spec.Template.Spec.Bootstrap.DataSecretName, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ContaboMachinePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1beta2.ContaboMachinePool{}).
+		// Scale the pool when the replicas of its MachinePool change
+		Watches(
+			&clusterv1.MachinePool{},
+			handler.EnqueueRequestsFromMapFunc(exputil.MachinePoolToInfrastructureMapFunc(context.TODO(),
+				infrastructurev1beta2.GroupVersion.WithKind("ContaboMachinePool"))),
+		).
+		// Report the machines of the pool as soon as they are ready or failed
+		Watches(
+			&infrastructurev1beta2.ContaboMachine{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrastructurev1beta2.ContaboMachinePool{}),
+		).
+		Named("contabomachinepool").
+		Complete(r)
+}
@@ -0,0 +1,437 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var _ = Describe("ContaboMachinePool Controller", func() {
+	const (
+		poolName      = "workers"
+		poolNamespace = "default"
+		poolCluster   = "pool-cluster"
+	)
+
+	var (
+		ctx         context.Context
+		k8sClient   client.Client
+		reconciler  *ContaboMachinePoolReconciler
+		machinePool *clusterv1.MachinePool
+		key         client.ObjectKey
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := newScheme()
+
+		cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: poolCluster, Namespace: poolNamespace}}
+		machinePool = &clusterv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      poolName,
+				Namespace: poolNamespace,
+				UID:       "machinepool-workers",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: poolCluster},
+			},
+			Spec: clusterv1.MachinePoolSpec{
+				ClusterName: poolCluster,
+				Replicas:    ptr.To(int32(2)),
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						ClusterName: poolCluster,
+						Version:     "v1.33.0",
+						Bootstrap:   clusterv1.Bootstrap{DataSecretName: ptr.To("workers-bootstrap")},
+					},
+				},
+			},
+		}
+		pool := &infrastructurev1beta2.ContaboMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      poolName,
+				Namespace: poolNamespace,
+				UID:       "contabomachinepool-workers",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: poolCluster},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "MachinePool",
+					Name:       machinePool.Name,
+					UID:        machinePool.UID,
+				}},
+			},
+			Spec: infrastructurev1beta2.ContaboMachinePoolSpec{
+				MaxSurge: 1,
+				Template: infrastructurev1beta2.ContaboMachineTemplateResource{
+					ObjectMeta: clusterv1.ObjectMeta{
+						Labels:      map[string]string{"environment": "development", clusterv1.ClusterNameLabel: "other"},
+						Annotations: map[string]string{"example.com/owner": "team-a"},
+					},
+					Spec: infrastructurev1beta2.ContaboMachineSpec{
+						Instance: infrastructurev1beta2.ContaboInstanceSpec{
+							ProductId: ptr.To(infrastructurev1beta2.ContaboProductCloudVPS10NVMe),
+						},
+					},
+				},
+			},
+		}
+		k8sClient = crfake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(cluster, machinePool, pool).
+			WithStatusSubresource(&infrastructurev1beta2.ContaboMachinePool{}, &infrastructurev1beta2.ContaboMachine{}).
+			Build()
+		reconciler = &ContaboMachinePoolReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+		key = client.ObjectKeyFromObject(pool)
+	})
+
+	reconcilePool := func() *infrastructurev1beta2.ContaboMachinePool {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		pool := &infrastructurev1beta2.ContaboMachinePool{}
+		Expect(k8sClient.Get(ctx, key, pool)).To(Succeed())
+		return pool
+	}
+
+	listPoolMachines := func() []infrastructurev1beta2.ContaboMachine {
+		contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+		Expect(k8sClient.List(ctx, contaboMachines, client.InNamespace(poolNamespace))).To(Succeed())
+		return contaboMachines.Items
+	}
+
+	// provisionPoolMachines plays the ContaboMachine controller, the machines are ready with a provider ID
+	provisionPoolMachines := func() {
+		for _, contaboMachine := range listPoolMachines() {
+			if contaboMachine.Status.Ready {
+				continue
+			}
+			contaboMachine.Spec.ProviderID = ptr.To("contabo://" + contaboMachine.Name)
+			Expect(k8sClient.Update(ctx, &contaboMachine)).To(Succeed())
+			contaboMachine.Status.Ready = true
+			Expect(k8sClient.Status().Update(ctx, &contaboMachine)).To(Succeed())
+		}
+	}
+
+	// createPoolMachineOwners plays Cluster API, a Machine is created for each machine of the pool
+	createPoolMachineOwners := func() {
+		for _, contaboMachine := range listPoolMachines() {
+			Expect(k8sClient.Create(ctx, &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       contaboMachine.Name,
+					Namespace:  poolNamespace,
+					Labels:     contaboMachine.Labels,
+					Finalizers: []string{clusterv1.MachineFinalizer},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: poolCluster,
+					InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+						APIGroup: infrastructurev1beta2.GroupVersion.Group,
+						Kind:     "ContaboMachine",
+						Name:     contaboMachine.Name,
+					},
+				},
+			})).To(Succeed())
+		}
+	}
+
+	It("should create a ContaboMachine per replica and report them once ready", func() {
+		pool := reconcilePool()
+		Expect(pool.Finalizers).To(ContainElement(infrastructurev1beta2.MachinePoolFinalizer))
+		Expect(pool.Status.InfrastructureMachineKind).To(Equal("ContaboMachine"))
+		Expect(pool.Status.Replicas).To(Equal(int32(2)))
+		Expect(pool.Status.Ready).To(BeFalse())
+
+		contaboMachines := listPoolMachines()
+		Expect(contaboMachines).To(HaveLen(2))
+		for _, contaboMachine := range contaboMachines {
+			Expect(contaboMachine.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, poolCluster))
+			Expect(contaboMachine.Labels).To(HaveKeyWithValue(clusterv1.MachinePoolNameLabel, poolName))
+			Expect(contaboMachine.Labels).To(HaveKeyWithValue(infrastructurev1beta2.MachinePoolTemplateHashLabel, pool.Status.TemplateHash))
+			Expect(contaboMachine.Labels).To(HaveKeyWithValue("environment", "development"))
+			Expect(contaboMachine.Annotations).To(HaveKeyWithValue("example.com/owner", "team-a"))
+			Expect(contaboMachine.OwnerReferences).To(ConsistOf(HaveField("UID", pool.UID)))
+			Expect(contaboMachine.Spec.Instance.ProductId).To(Equal(ptr.To(infrastructurev1beta2.ContaboProductCloudVPS10NVMe)))
+		}
+
+		provisionPoolMachines()
+		pool = reconcilePool()
+		Expect(listPoolMachines()).To(HaveLen(2))
+		Expect(pool.Status.Ready).To(BeTrue())
+		Expect(pool.Status.Initialization).To(Equal(&infrastructurev1beta2.ContaboMachinePoolInitializationStatus{Provisioned: true}))
+		Expect(pool.Status.ReadyReplicas).To(Equal(int32(2)))
+		Expect(pool.Spec.ProviderIDList).To(ConsistOf(
+			"contabo://"+contaboMachines[0].Name,
+			"contabo://"+contaboMachines[1].Name,
+		))
+		Expect(meta.IsStatusConditionTrue(pool.Status.Conditions, infrastructurev1beta2.MachinePoolReplicasReadyCondition)).To(BeTrue())
+	})
+
+	It("should replace the machines one at a time when the template changes", func() {
+		reconcilePool()
+		provisionPoolMachines()
+		pool := reconcilePool()
+		previousHash := pool.Status.TemplateHash
+
+		pool.Spec.Template.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductCloudVPS20NVMe)
+		Expect(k8sClient.Update(ctx, pool)).To(Succeed())
+
+		for range 10 {
+			pool = reconcilePool()
+			contaboMachines := listPoolMachines()
+			Expect(len(contaboMachines)).To(BeNumerically("<=", 3), "at most MaxSurge machines above the replicas")
+			ready := 0
+			for _, contaboMachine := range contaboMachines {
+				if contaboMachine.Status.Ready {
+					ready++
+				}
+			}
+			Expect(ready).To(BeNumerically(">=", 2), "the replicas stay ready during the replacement")
+			if pool.Status.Ready {
+				break
+			}
+			Expect(meta.FindStatusCondition(pool.Status.Conditions, infrastructurev1beta2.MachinePoolReplicasReadyCondition).Reason).
+				To(Equal(infrastructurev1beta2.MachinePoolRollingUpdateReason))
+			provisionPoolMachines()
+		}
+
+		Expect(pool.Status.Ready).To(BeTrue())
+		Expect(pool.Status.TemplateHash).NotTo(Equal(previousHash))
+		Expect(pool.Status.UpToDateReplicas).To(Equal(int32(2)))
+		contaboMachines := listPoolMachines()
+		Expect(contaboMachines).To(HaveLen(2))
+		for _, contaboMachine := range contaboMachines {
+			Expect(contaboMachine.Labels).To(HaveKeyWithValue(infrastructurev1beta2.MachinePoolTemplateHashLabel, pool.Status.TemplateHash))
+			Expect(contaboMachine.Spec.Instance.ProductId).To(Equal(ptr.To(infrastructurev1beta2.ContaboProductCloudVPS20NVMe)))
+		}
+	})
+
+	It("should replace the machines when the Kubernetes version of the MachinePool changes", func() {
+		reconcilePool()
+		provisionPoolMachines()
+		pool := reconcilePool()
+		previousHash := pool.Status.TemplateHash
+
+		machinePool.Spec.Template.Spec.Version = "v1.34.0"
+		Expect(k8sClient.Update(ctx, machinePool)).To(Succeed())
+		pool = reconcilePool()
+		Expect(pool.Status.TemplateHash).NotTo(Equal(previousHash))
+		Expect(pool.Status.UpToDateReplicas).To(Equal(int32(1)), "one machine created above the replicas")
+		Expect(listPoolMachines()).To(HaveLen(3))
+	})
+
+	It("should scale down through the Machines and replace failed machines", func() {
+		reconcilePool()
+		provisionPoolMachines()
+		createPoolMachineOwners()
+		reconcilePool()
+
+		By("Scaling the MachinePool down")
+		machinePool.Spec.Replicas = ptr.To(int32(1))
+		Expect(k8sClient.Update(ctx, machinePool)).To(Succeed())
+		pool := reconcilePool()
+		Expect(pool.Status.Replicas).To(Equal(int32(1)))
+		machines := &clusterv1.MachineList{}
+		Expect(k8sClient.List(ctx, machines, client.InNamespace(poolNamespace))).To(Succeed())
+		deleting := 0
+		for _, machine := range machines.Items {
+			if !machine.DeletionTimestamp.IsZero() {
+				deleting++
+			}
+		}
+		Expect(deleting).To(Equal(1), "the node of the machine is drained by Cluster API")
+		Expect(listPoolMachines()).To(HaveLen(2))
+
+		By("Failing the remaining machine")
+		var remaining *infrastructurev1beta2.ContaboMachine
+		for _, contaboMachine := range listPoolMachines() {
+			if !machinePoolMachineDeleting(&contaboMachine, mapMachines(machines.Items)) {
+				remaining = contaboMachine.DeepCopy()
+			}
+		}
+		Expect(remaining).NotTo(BeNil())
+		remaining.Status.FailureReason = ptr.To(infrastructurev1beta2.InstanceCancelledReason)
+		Expect(k8sClient.Status().Update(ctx, remaining)).To(Succeed())
+		pool = reconcilePool()
+		Expect(pool.Status.Replicas).To(Equal(int32(1)))
+		Expect(pool.Status.ReadyReplicas).To(BeZero())
+		Expect(listPoolMachines()).To(HaveLen(3), "a replacement is created while the failed machine is deleted")
+		machine := &clusterv1.Machine{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(remaining), machine)).To(Succeed())
+		Expect(machine.DeletionTimestamp.IsZero()).To(BeFalse())
+	})
+
+	It("should park the instances when scaled to zero and claim them back when scaled up", func() {
+		reconcilePool()
+		for _, contaboMachine := range listPoolMachines() {
+			contaboMachine.Status.Instance = &infrastructurev1beta2.ContaboInstanceStatus{Name: "vmi-" + contaboMachine.Name}
+			Expect(k8sClient.Status().Update(ctx, &contaboMachine)).To(Succeed())
+		}
+		provisionPoolMachines()
+		createPoolMachineOwners()
+		pool := reconcilePool()
+		Expect(pool.Status.Ready).To(BeTrue())
+		templateHash := pool.Status.TemplateHash
+
+		By("Scaling the MachinePool to zero")
+		machinePool.Spec.Replicas = ptr.To(int32(0))
+		Expect(k8sClient.Update(ctx, machinePool)).To(Succeed())
+		pool = reconcilePool()
+		Expect(pool.Status.Ready).To(BeFalse(), "the instances of the machines are not released yet")
+		Expect(pool.Status.ParkedInstances).To(HaveLen(2))
+		parked := []string{pool.Status.ParkedInstances[0].Name, pool.Status.ParkedInstances[1].Name}
+		for _, contaboMachine := range listPoolMachines() {
+			Expect(contaboMachine.Annotations).To(HaveKeyWithValue(infrastructurev1beta2.ParkedInstanceAnnotation, poolName))
+		}
+
+		// Cluster API deletes the Machines and their ContaboMachines once the nodes are drained
+		machines := &clusterv1.MachineList{}
+		Expect(k8sClient.List(ctx, machines, client.InNamespace(poolNamespace))).To(Succeed())
+		for _, machine := range machines.Items {
+			machine.Finalizers = nil
+			Expect(k8sClient.Update(ctx, &machine)).To(Succeed())
+		}
+		for _, contaboMachine := range listPoolMachines() {
+			Expect(k8sClient.Delete(ctx, &contaboMachine)).To(Succeed())
+		}
+		pool = reconcilePool()
+		Expect(pool.Status.Ready).To(BeTrue())
+		Expect(pool.Status.Replicas).To(BeZero())
+		Expect(pool.Spec.ProviderIDList).To(BeEmpty())
+		Expect(pool.Status.Initialization).To(Equal(&infrastructurev1beta2.ContaboMachinePoolInitializationStatus{Provisioned: true}))
+		Expect(pool.Status.TemplateHash).To(Equal(templateHash))
+		Expect(pool.Finalizers).To(ContainElement(infrastructurev1beta2.MachinePoolFinalizer))
+		Expect(meta.FindStatusCondition(pool.Status.Conditions, infrastructurev1beta2.MachinePoolReplicasReadyCondition).Reason).
+			To(Equal(infrastructurev1beta2.MachinePoolScaledToZeroReason))
+
+		By("Scaling the MachinePool up again")
+		machinePool.Spec.Replicas = ptr.To(int32(2))
+		Expect(k8sClient.Update(ctx, machinePool)).To(Succeed())
+		pool = reconcilePool()
+		Expect(pool.Status.ParkedInstances).To(BeEmpty())
+		instanceNames := []string{}
+		for _, contaboMachine := range listPoolMachines() {
+			Expect(contaboMachine.Spec.Instance.Name).NotTo(BeNil())
+			Expect(contaboMachine.Annotations).To(HaveKeyWithValue(infrastructurev1beta2.ParkedInstanceAnnotation, poolName))
+			instanceNames = append(instanceNames, *contaboMachine.Spec.Instance.Name)
+		}
+		Expect(instanceNames).To(ConsistOf(parked))
+	})
+
+	It("should not park the cancelled instances nor more than the maximum of parked instances", func() {
+		pool := &infrastructurev1beta2.ContaboMachinePool{}
+		Expect(k8sClient.Get(ctx, key, pool)).To(Succeed())
+		newMachine := func(name string) *infrastructurev1beta2.ContaboMachine {
+			contaboMachine := &infrastructurev1beta2.ContaboMachine{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: poolNamespace}}
+			contaboMachine.Status.Instance = &infrastructurev1beta2.ContaboInstanceStatus{Name: "vmi-" + name}
+			Expect(k8sClient.Create(ctx, contaboMachine)).To(Succeed())
+			return contaboMachine
+		}
+
+		cancelled := newMachine("cancelled")
+		cancelled.Spec.ReusePolicy = infrastructurev1beta2.ContaboMachineReusePolicyCancel
+		Expect(reconciler.parkPoolMachineInstance(ctx, pool, cancelled)).To(Succeed())
+		Expect(pool.Status.ParkedInstances).To(BeEmpty())
+		Expect(cancelled.Annotations).NotTo(HaveKey(infrastructurev1beta2.ParkedInstanceAnnotation))
+
+		for i := range infrastructurev1beta2.MaxParkedInstances {
+			pool.Status.ParkedInstances = append(pool.Status.ParkedInstances, infrastructurev1beta2.ContaboMachinePoolParkedInstance{Name: fmt.Sprintf("vmi-%d", i)})
+		}
+		overflow := newMachine("overflow")
+		Expect(reconciler.parkPoolMachineInstance(ctx, pool, overflow)).To(Succeed())
+		Expect(pool.Status.ParkedInstances).To(HaveLen(infrastructurev1beta2.MaxParkedInstances))
+		Expect(overflow.Annotations).NotTo(HaveKey(infrastructurev1beta2.ParkedInstanceAnnotation))
+	})
+
+	It("should delete its machines before being removed", func() {
+		reconcilePool()
+		pool := &infrastructurev1beta2.ContaboMachinePool{}
+		Expect(k8sClient.Get(ctx, key, pool)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, pool)).To(Succeed())
+
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(listPoolMachines()).To(BeEmpty())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, pool)).To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("should rename the legacy finalizer and still honor it on deletion", func() {
+		reconcilePool()
+		pool := &infrastructurev1beta2.ContaboMachinePool{}
+		Expect(k8sClient.Get(ctx, key, pool)).To(Succeed())
+		pool.Finalizers = []string{infrastructurev1beta2.LegacyMachinePoolFinalizer}
+		Expect(k8sClient.Update(ctx, pool)).To(Succeed())
+		Expect(reconcilePool().Finalizers).To(ConsistOf(infrastructurev1beta2.MachinePoolFinalizer))
+
+		By("Deleting a pool holding the legacy finalizer")
+		Expect(k8sClient.Get(ctx, key, pool)).To(Succeed())
+		pool.Finalizers = []string{infrastructurev1beta2.LegacyMachinePoolFinalizer}
+		Expect(k8sClient.Update(ctx, pool)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, pool)).To(Succeed())
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(listPoolMachines()).To(BeEmpty())
+		Expect(k8sClient.Get(ctx, key, pool)).To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("should bootstrap the machines of the pool with the data of the MachinePool", func() {
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-abcde", poolName),
+				Namespace: poolNamespace,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:     poolCluster,
+					clusterv1.MachinePoolNameLabel: poolName,
+				},
+			},
+			Spec: clusterv1.MachineSpec{Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("")}},
+		}
+		dataSecretName, err := machinePoolBootstrapDataSecretName(ctx, k8sClient, machine)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dataSecretName).To(Equal(ptr.To("workers-bootstrap")))
+
+		delete(machine.Labels, clusterv1.MachinePoolNameLabel)
+		dataSecretName, err = machinePoolBootstrapDataSecretName(ctx, k8sClient, machine)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dataSecretName).To(BeNil())
+	})
+})
+
+// mapMachines returns the Machines by the name of their infrastructure machine
+func mapMachines(machines []clusterv1.Machine) map[string]*clusterv1.Machine {
+	byName := map[string]*clusterv1.Machine{}
+	for i := range machines {
+		byName[machines[i].Spec.InfrastructureRef.Name] = &machines[i]
+	}
+	return byName
+}
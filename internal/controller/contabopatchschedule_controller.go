@@ -0,0 +1,417 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// DefaultPatchWindowDuration is the length of a maintenance window when not set
+const DefaultPatchWindowDuration = 2 * time.Hour
+
+// ContaboPatchScheduleReconciler reconciles a ContaboPatchSchedule object
+type ContaboPatchScheduleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Settings *ProviderSettings
+	// ManagerNodeName is the node running the controller manager, patched last when the cluster is self-hosted
+	ManagerNodeName string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabopatchschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabopatchschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contabomachines,verbs=get;list;watch;update;patch
+
+// Reconcile starts patch waves on the ContaboMachines of the cluster while the maintenance window is open.
+// Machines of a wave are annotated with the PatchRequestAnnotation, the ContaboMachine controller cordons,
+// upgrades, restarts and uncordons them, and the annotation is removed once they are patched.
+func (r *ContaboPatchScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	schedule := &infrastructurev1beta2.ContaboPatchSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, schedule); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// No wave is started while the cluster is paused, e.g. during a clusterctl move to a self-hosted cluster
+	cluster, err := util.GetClusterByName(ctx, r.Client, schedule.Namespace, schedule.Spec.ClusterName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if cluster != nil && annotations.IsPaused(cluster, schedule) {
+		log.Info("ContaboPatchSchedule or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(schedule, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result, err := r.reconcileNormal(ctx, schedule)
+	if patchErr := patchHelper.Patch(ctx, schedule); patchErr != nil {
+		log.Error(patchErr, "Failed to patch ContaboPatchSchedule")
+		if err == nil {
+			err = patchErr
+		}
+	}
+	return result, err
+}
+
+func (r *ContaboPatchScheduleReconciler) reconcileNormal(ctx context.Context, schedule *infrastructurev1beta2.ContaboPatchSchedule) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	now := time.Now().UTC()
+	windowStart, open := patchWindowStart(schedule.Spec.Window, now)
+	nextWindow := nextPatchWindowStart(schedule.Spec.Window, now)
+	schedule.Status.NextWindowTime = &metav1.Time{Time: nextWindow}
+
+	if open && !schedule.Spec.Suspend {
+		run := formatPatchRun(schedule, windowStart)
+		if schedule.Status.CurrentRun != run {
+			log.Info("Maintenance window opened, starting patch run", "run", run)
+			schedule.Status.CurrentRun = run
+			schedule.Status.PatchedMachines = nil
+			schedule.Status.FailedMachines = nil
+		}
+	}
+
+	machines, err := r.getPatchScheduleMachines(ctx, schedule)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Collect the machines being patched, release the ones done
+	inProgress := []string{}
+	annotated := map[string]bool{}
+	for i := range machines {
+		contaboMachine := &machines[i]
+		run, ok := contaboMachine.Annotations[infrastructurev1beta2.PatchRequestAnnotation]
+		if !ok || !strings.HasPrefix(run, schedule.Name+"/") {
+			continue
+		}
+		machinePatch := contaboMachine.Status.Patch
+		if machinePatch == nil || machinePatch.Run != run ||
+			(machinePatch.Phase != infrastructurev1beta2.ContaboMachinePatchPhaseCompleted && machinePatch.Phase != infrastructurev1beta2.ContaboMachinePatchPhaseFailed) {
+			annotated[contaboMachine.Name] = true
+			inProgress = append(inProgress, contaboMachine.Name)
+			continue
+		}
+		if run == schedule.Status.CurrentRun {
+			if machinePatch.Phase == infrastructurev1beta2.ContaboMachinePatchPhaseCompleted {
+				schedule.Status.PatchedMachines = appendUnique(schedule.Status.PatchedMachines, contaboMachine.Name)
+			} else {
+				schedule.Status.FailedMachines = appendUnique(schedule.Status.FailedMachines, contaboMachine.Name)
+				if r.Recorder != nil {
+					r.Recorder.Eventf(schedule, corev1.EventTypeWarning, infrastructurev1beta2.PatchFailedReason, "Failed to patch %s: %s", contaboMachine.Name, machinePatch.Message)
+				}
+			}
+		}
+		original := contaboMachine.DeepCopy()
+		delete(contaboMachine.Annotations, infrastructurev1beta2.PatchRequestAnnotation)
+		if err := r.Patch(ctx, contaboMachine, client.MergeFrom(original)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to release patched ContaboMachine %s: %w", contaboMachine.Name, err)
+		}
+		log.Info("Machine patch finished", "machine", contaboMachine.Name, "phase", machinePatch.Phase)
+	}
+	schedule.Status.InProgressMachines = inProgress
+
+	if schedule.Spec.Suspend || !open {
+		reason := infrastructurev1beta2.PatchWaitingForWindowReason
+		message := fmt.Sprintf("Next window opens at %s", nextWindow.Format(time.RFC3339))
+		if schedule.Spec.Suspend {
+			reason = infrastructurev1beta2.PatchSuspendedReason
+			message = "Schedule is suspended"
+		}
+		if len(inProgress) > 0 {
+			reason = infrastructurev1beta2.PatchInProgressReason
+			message = fmt.Sprintf("Waiting for %s to be patched, no new wave is started", strings.Join(inProgress, ", "))
+		}
+		if current := meta.FindStatusCondition(schedule.Status.Conditions, infrastructurev1beta2.PatchingCondition); current == nil ||
+			(current.Reason != infrastructurev1beta2.PatchCompletedReason && current.Reason != infrastructurev1beta2.PatchFailedReason) || len(inProgress) > 0 {
+			meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+				Type:    infrastructurev1beta2.PatchingCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  reason,
+				Message: message,
+			})
+		}
+		if len(inProgress) > 0 {
+			return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Until(nextWindow)}, nil
+	}
+
+	// Start the next wave
+	done := map[string]bool{}
+	for _, name := range append(slices.Clone(schedule.Status.PatchedMachines), schedule.Status.FailedMachines...) {
+		done[name] = true
+	}
+	wave := selectPatchWave(machines, annotated, done, int(schedule.Spec.WaveSize), r.ManagerNodeName)
+	for _, contaboMachine := range wave {
+		original := contaboMachine.DeepCopy()
+		if contaboMachine.Annotations == nil {
+			contaboMachine.Annotations = map[string]string{}
+		}
+		contaboMachine.Annotations[infrastructurev1beta2.PatchRequestAnnotation] = schedule.Status.CurrentRun
+		if err := r.Patch(ctx, contaboMachine, client.MergeFrom(original)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to request patch of ContaboMachine %s: %w", contaboMachine.Name, err)
+		}
+		log.Info("Requested machine patch", "machine", contaboMachine.Name, "run", schedule.Status.CurrentRun)
+		inProgress = append(inProgress, contaboMachine.Name)
+	}
+	schedule.Status.InProgressMachines = inProgress
+
+	remaining := 0
+	for _, contaboMachine := range machines {
+		if !done[contaboMachine.Name] {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.PatchingCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.PatchInProgressReason,
+			Message: fmt.Sprintf("%d/%d machines patched, patching %s", len(schedule.Status.PatchedMachines), len(machines), strings.Join(inProgress, ", ")),
+		})
+		return ctrl.Result{RequeueAfter: r.Settings.DependencyInterval()}, nil
+	}
+
+	// All the machines were handled during the run
+	current := meta.FindStatusCondition(schedule.Status.Conditions, infrastructurev1beta2.PatchingCondition)
+	if current == nil || current.Reason == infrastructurev1beta2.PatchInProgressReason || current.Reason == infrastructurev1beta2.PatchWaitingForWindowReason {
+		schedule.Status.LastCompletionTime = &metav1.Time{Time: now}
+	}
+	if len(schedule.Status.FailedMachines) > 0 {
+		meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.PatchingCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.PatchFailedReason,
+			Message: fmt.Sprintf("Failed to patch %s", strings.Join(schedule.Status.FailedMachines, ", ")),
+		})
+	} else {
+		meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.PatchingCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  infrastructurev1beta2.PatchCompletedReason,
+			Message: fmt.Sprintf("%d machines patched", len(schedule.Status.PatchedMachines)),
+		})
+	}
+	return ctrl.Result{RequeueAfter: time.Until(nextWindow)}, nil
+}
+
+// getPatchScheduleMachines returns the ready ContaboMachines of the cluster matching the schedule selector,
+// machines already annotated are always returned to follow their progress
+func (r *ContaboPatchScheduleReconciler) getPatchScheduleMachines(ctx context.Context, schedule *infrastructurev1beta2.ContaboPatchSchedule) ([]infrastructurev1beta2.ContaboMachine, error) {
+	selector := labels.Everything()
+	if schedule.Spec.Selector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(schedule.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %w", err)
+		}
+	}
+
+	machineList := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, machineList, client.InNamespace(schedule.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: schedule.Spec.ClusterName}); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboMachines: %w", err)
+	}
+
+	machines := []infrastructurev1beta2.ContaboMachine{}
+	for _, contaboMachine := range machineList.Items {
+		if _, annotated := contaboMachine.Annotations[infrastructurev1beta2.PatchRequestAnnotation]; annotated {
+			machines = append(machines, contaboMachine)
+			continue
+		}
+		if !contaboMachine.DeletionTimestamp.IsZero() || !contaboMachine.Status.Ready || !selector.Matches(labels.Set(contaboMachine.Labels)) {
+			continue
+		}
+		machines = append(machines, contaboMachine)
+	}
+	return machines, nil
+}
+
+// selectPatchWave returns the machines to patch next: workers first, up to waveSize machines at the same time,
+// then control plane machines one at a time. The machine of managerNodeName is patched last within its group so that
+// a self-hosted controller manager is moved at most once.
+func selectPatchWave(machines []infrastructurev1beta2.ContaboMachine, annotated map[string]bool, done map[string]bool, waveSize int, managerNodeName string) []*infrastructurev1beta2.ContaboMachine {
+	if waveSize <= 0 {
+		waveSize = 1
+	}
+
+	inProgress, controlPlaneInProgress := 0, false
+	workers, controlPlanes := []*infrastructurev1beta2.ContaboMachine{}, []*infrastructurev1beta2.ContaboMachine{}
+	for i := range machines {
+		contaboMachine := &machines[i]
+		_, isControlPlane := contaboMachine.Labels[clusterv1.MachineControlPlaneLabel]
+		if annotated[contaboMachine.Name] {
+			inProgress++
+			controlPlaneInProgress = controlPlaneInProgress || isControlPlane
+			continue
+		}
+		if done[contaboMachine.Name] {
+			continue
+		}
+		if isControlPlane {
+			controlPlanes = append(controlPlanes, contaboMachine)
+		} else {
+			workers = append(workers, contaboMachine)
+		}
+	}
+	byName := func(group []*infrastructurev1beta2.ContaboMachine) func(i, j int) bool {
+		return func(i, j int) bool {
+			iManager, jManager := isManagerMachine(group[i], managerNodeName), isManagerMachine(group[j], managerNodeName)
+			if iManager != jManager {
+				return jManager
+			}
+			return group[i].Name < group[j].Name
+		}
+	}
+	sort.Slice(workers, byName(workers))
+	sort.Slice(controlPlanes, byName(controlPlanes))
+
+	if len(workers) > 0 {
+		if controlPlaneInProgress || inProgress >= waveSize {
+			return nil
+		}
+		return workers[:min(waveSize-inProgress, len(workers))]
+	}
+	if len(controlPlanes) > 0 && inProgress == 0 {
+		return controlPlanes[:1]
+	}
+	return nil
+}
+
+// isManagerMachine reports whether the machine backs the node running the controller manager
+func isManagerMachine(contaboMachine *infrastructurev1beta2.ContaboMachine, managerNodeName string) bool {
+	if managerNodeName == "" {
+		return false
+	}
+	nodeName, err := machineNodeName(contaboMachine)
+	return err == nil && nodeName == managerNodeName
+}
+
+// patchWindowStart returns the start of the maintenance window containing now
+func patchWindowStart(window infrastructurev1beta2.ContaboPatchWindow, now time.Time) (time.Time, bool) {
+	duration := DefaultPatchWindowDuration
+	if window.Duration != nil && window.Duration.Duration > 0 {
+		duration = window.Duration.Duration
+	}
+	now = now.UTC()
+	for days := 0; days <= 7; days++ {
+		start, ok := patchWindowStartOn(window, now.AddDate(0, 0, -days))
+		if ok && !start.After(now) && now.Before(start.Add(duration)) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextPatchWindowStart returns the start of the next maintenance window after now
+func nextPatchWindowStart(window infrastructurev1beta2.ContaboPatchWindow, now time.Time) time.Time {
+	now = now.UTC()
+	for days := 0; days <= 7; days++ {
+		start, ok := patchWindowStartOn(window, now.AddDate(0, 0, days))
+		if ok && start.After(now) {
+			return start
+		}
+	}
+	return now.AddDate(0, 0, 7)
+}
+
+// patchWindowStartOn returns the start of the window on the given day, false if the window does not open that day
+func patchWindowStartOn(window infrastructurev1beta2.ContaboPatchWindow, day time.Time) (time.Time, bool) {
+	startOfDay, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if len(window.Days) > 0 && !slices.Contains(window.Days, infrastructurev1beta2.ContaboPatchWeekday(day.Weekday().String())) {
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), startOfDay.Hour(), startOfDay.Minute(), 0, 0, time.UTC), true
+}
+
+// formatPatchRun returns the PatchRequestAnnotation value of the schedule run started at windowStart
+func formatPatchRun(schedule *infrastructurev1beta2.ContaboPatchSchedule, windowStart time.Time) string {
+	return fmt.Sprintf("%s/%s", schedule.Name, windowStart.UTC().Format(time.RFC3339))
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+// machineToPatchSchedules maps a ContaboMachine to the ContaboPatchSchedules of its cluster
+func (r *ContaboPatchScheduleReconciler) machineToPatchSchedules(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusterName := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if clusterName == "" {
+		return nil
+	}
+	scheduleList := &infrastructurev1beta2.ContaboPatchScheduleList{}
+	if err := r.List(ctx, scheduleList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, schedule := range scheduleList.Items {
+		if schedule.Spec.ClusterName == clusterName {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&schedule),
+			})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ContaboPatchScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1beta2.ContaboPatchSchedule{}).
+		Watches(
+			&infrastructurev1beta2.ContaboMachine{},
+			handler.EnqueueRequestsFromMapFunc(r.machineToPatchSchedules),
+		).
+		Named("contabopatchschedule").
+		Complete(r)
+}
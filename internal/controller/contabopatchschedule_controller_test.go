@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var _ = Describe("ContaboPatchSchedule", func() {
+	Context("When computing maintenance windows", func() {
+		window := infrastructurev1beta2.ContaboPatchWindow{
+			Days:     []infrastructurev1beta2.ContaboPatchWeekday{"Sunday"},
+			Start:    "23:00",
+			Duration: &metav1.Duration{Duration: 2 * time.Hour},
+		}
+
+		It("should detect an open window spanning midnight", func() {
+			// Monday 00:30 UTC, the window opened on Sunday 23:00
+			now := time.Date(2025, time.June, 2, 0, 30, 0, 0, time.UTC)
+			start, open := patchWindowStart(window, now)
+			Expect(open).To(BeTrue())
+			Expect(start).To(Equal(time.Date(2025, time.June, 1, 23, 0, 0, 0, time.UTC)))
+		})
+
+		It("should compute the next window when closed", func() {
+			now := time.Date(2025, time.June, 2, 2, 0, 0, 0, time.UTC)
+			_, open := patchWindowStart(window, now)
+			Expect(open).To(BeFalse())
+			Expect(nextPatchWindowStart(window, now)).To(Equal(time.Date(2025, time.June, 8, 23, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("When selecting patch waves", func() {
+		machine := func(name string, controlPlane bool) infrastructurev1beta2.ContaboMachine {
+			m := infrastructurev1beta2.ContaboMachine{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+			if controlPlane {
+				m.Labels[clusterv1.MachineControlPlaneLabel] = ""
+			}
+			return m
+		}
+		machines := []infrastructurev1beta2.ContaboMachine{machine("cp-0", true), machine("cp-1", true), machine("worker-1", false), machine("worker-0", false)}
+
+		It("should patch workers first up to the wave size", func() {
+			wave := selectPatchWave(machines, map[string]bool{"worker-0": true}, map[string]bool{}, 2, "")
+			Expect(wave).To(HaveLen(1))
+			Expect(wave[0].Name).To(Equal("worker-1"))
+		})
+
+		It("should patch control plane machines one at a time after the workers", func() {
+			done := map[string]bool{"worker-0": true, "worker-1": true}
+			wave := selectPatchWave(machines, map[string]bool{}, done, 3, "")
+			Expect(wave).To(HaveLen(1))
+			Expect(wave[0].Name).To(Equal("cp-0"))
+			Expect(selectPatchWave(machines, map[string]bool{"cp-0": true}, done, 3, "")).To(BeEmpty())
+		})
+
+		It("should patch the machine running the controller manager last", func() {
+			selfHosted := []infrastructurev1beta2.ContaboMachine{machine("cp-0", true), machine("cp-1", true)}
+			selfHosted[0].Spec.ProviderID = ptr.To(ProviderIDPrefix + "100")
+			selfHosted[1].Spec.ProviderID = ptr.To(ProviderIDPrefix + "101")
+			wave := selectPatchWave(selfHosted, map[string]bool{}, map[string]bool{}, 1, "100")
+			Expect(wave).To(HaveLen(1))
+			Expect(wave[0].Name).To(Equal("cp-1"))
+		})
+	})
+
+	Context("When parsing the upgrade command output", func() {
+		It("should return the exit code", func() {
+			Expect(parsePatchCommandOutput("done\n" + patchExitCodeMarker + "0\n")).To(Equal(0))
+			Expect(parsePatchCommandOutput("E: lock\n" + patchExitCodeMarker + "100\n")).To(Equal(100))
+			Expect(parsePatchCommandOutput("connection closed")).To(Equal(-1))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// ContaboProviderSettingsReconciler applies the ContaboProviderSettings singleton to the running controllers
+type ContaboProviderSettingsReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Settings *ProviderSettings
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboprovidersettings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboprovidersettings/status,verbs=get;update;patch
+
+// Reconcile applies the settings without restarting the controllers, removing the singleton restores the defaults
+func (r *ContaboProviderSettingsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if req.Name != infrastructurev1beta2.ContaboProviderSettingsName {
+		log.Info("Ignoring ContaboProviderSettings, only the singleton is applied", "name", req.Name, "singleton", infrastructurev1beta2.ContaboProviderSettingsName)
+		return ctrl.Result{}, nil
+	}
+
+	settings := &infrastructurev1beta2.ContaboProviderSettings{}
+	if err := r.Get(ctx, req.NamespacedName, settings); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("ContaboProviderSettings removed, restoring defaults")
+			r.Settings.Update(infrastructurev1beta2.ContaboProviderSettingsSpec{})
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(settings, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.Settings.Update(settings.Spec)
+	log.Info("Applied ContaboProviderSettings", "generation", settings.Generation)
+
+	settings.Status.ObservedGeneration = settings.Generation
+	meta.SetStatusCondition(&settings.Status.Conditions, metav1.Condition{
+		Type:               infrastructurev1beta2.ProviderSettingsAppliedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             infrastructurev1beta2.ProviderSettingsAppliedReason,
+		ObservedGeneration: settings.Generation,
+	})
+
+	return ctrl.Result{}, patchHelper.Patch(ctx, settings)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ContaboProviderSettingsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1beta2.ContaboProviderSettings{}).
+		Named("contaboprovidersettings").
+		Complete(r)
+}
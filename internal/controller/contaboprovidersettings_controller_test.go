@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var _ = Describe("ContaboProviderSettings", func() {
+	Context("When resolving provider settings", func() {
+		It("should return the defaults without settings", func() {
+			var settings *ProviderSettings
+			Expect(settings.CloudInitInterval()).To(Equal(DefaultCloudInitInterval))
+			Expect(NewProviderSettings().SshDialTimeout()).To(Equal(DefaultSshDialTimeout))
+		})
+
+		It("should apply and revert updated settings", func() {
+			settings := NewProviderSettings()
+			settings.Update(infrastructurev1beta2.ContaboProviderSettingsSpec{
+				Intervals: infrastructurev1beta2.ContaboRequeueIntervals{
+					CloudInit: &metav1.Duration{Duration: time.Minute},
+					Quota:     &metav1.Duration{Duration: 0},
+				},
+			})
+			Expect(settings.CloudInitInterval()).To(Equal(time.Minute))
+			Expect(settings.QuotaInterval()).To(Equal(DefaultQuotaInterval))
+
+			settings.Update(infrastructurev1beta2.ContaboProviderSettingsSpec{})
+			Expect(settings.CloudInitInterval()).To(Equal(DefaultCloudInitInterval))
+		})
+	})
+})
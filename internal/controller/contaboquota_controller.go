@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// ContaboQuotaReconciler reconciles a ContaboQuota object
+type ContaboQuotaReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboquotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=contaboquotas/status,verbs=get;update;patch
+
+// Reconcile computes the resource usage of the namespace and reports it in the ContaboQuota status
+func (r *ContaboQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	log.V(1).Info("Reconciling ContaboQuota", "namespace", req.Namespace, "name", req.Name)
+
+	quota := &infrastructurev1beta2.ContaboQuota{}
+	if err := r.Get(ctx, req.NamespacedName, quota); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	patchHelper, err := patch.NewHelper(quota, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	usage, err := computeQuotaUsage(ctx, r.Client, quota.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	quota.Status.Used = usage
+
+	// Report limits that are reached, no additional resources are requested here
+	violations := quotaViolations(quota, usage, quotaRequest{Instances: 1, PrivateNetworks: 1})
+	if len(violations) > 0 {
+		meta.SetStatusCondition(&quota.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.QuotaWithinLimitsCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.QuotaExceededReason,
+			Message: "Quota limits reached: " + strings.Join(violations, ", "),
+		})
+	} else {
+		meta.SetStatusCondition(&quota.Status.Conditions, metav1.Condition{
+			Type:   infrastructurev1beta2.QuotaWithinLimitsCondition,
+			Status: metav1.ConditionTrue,
+			Reason: infrastructurev1beta2.QuotaWithinLimitsReason,
+		})
+	}
+
+	if err := patchHelper.Patch(ctx, quota); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// namespaceToQuotas maps any object to the ContaboQuotas of its namespace
+func (r *ContaboQuotaReconciler) namespaceToQuotas(ctx context.Context, obj client.Object) []reconcile.Request {
+	quotaList := &infrastructurev1beta2.ContaboQuotaList{}
+	if err := r.List(ctx, quotaList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(quotaList.Items))
+	for _, quota := range quotaList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&quota),
+		})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ContaboQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1beta2.ContaboQuota{}).
+		Watches(
+			&infrastructurev1beta2.ContaboMachine{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceToQuotas),
+		).
+		Watches(
+			&infrastructurev1beta2.ContaboCluster{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceToQuotas),
+		).
+		Named("contaboquota").
+		Complete(r)
+}
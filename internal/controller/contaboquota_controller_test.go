@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var _ = Describe("ContaboQuota", func() {
+	Context("When checking quota violations", func() {
+		quota := &infrastructurev1beta2.ContaboQuota{
+			Spec: infrastructurev1beta2.ContaboQuotaSpec{
+				MaxInstances:       ptr.To(int32(2)),
+				MaxCpuCores:        ptr.To(int64(8)),
+				MaxPrivateNetworks: ptr.To(int32(1)),
+			},
+		}
+
+		It("should allow a new instance within limits", func() {
+			usage := infrastructurev1beta2.ContaboQuotaUsage{Instances: 1, CpuCores: 4}
+			Expect(quotaViolations(quota, usage, quotaRequest{Instances: 1})).To(BeEmpty())
+		})
+
+		It("should refuse a new instance when the instance limit is reached", func() {
+			usage := infrastructurev1beta2.ContaboQuotaUsage{Instances: 2, CpuCores: 4}
+			Expect(quotaViolations(quota, usage, quotaRequest{Instances: 1})).To(ConsistOf("instances 2/2"))
+		})
+
+		It("should refuse a new instance when the cpu limit is reached", func() {
+			usage := infrastructurev1beta2.ContaboQuotaUsage{Instances: 1, CpuCores: 8}
+			Expect(quotaViolations(quota, usage, quotaRequest{Instances: 1})).To(ConsistOf("cpuCores 8/8"))
+		})
+
+		It("should add the size of the requested product to the usage", func() {
+			usage := infrastructurev1beta2.ContaboQuotaUsage{Instances: 1, CpuCores: 6}
+			vps10 := &infrastructurev1beta2.ContaboMachine{Spec: infrastructurev1beta2.ContaboMachineSpec{
+				Instance: infrastructurev1beta2.ContaboInstanceSpec{ProductId: ptr.To(infrastructurev1beta2.ContaboProductCloudVPS10NVMe)},
+			}}
+			Expect(instanceQuotaRequest(vps10)).To(Equal(quotaRequest{Instances: 1, CpuCores: 4, RamMb: 8192}))
+			Expect(quotaViolations(quota, usage, instanceQuotaRequest(vps10))).To(ConsistOf("cpuCores 6/8"))
+
+			By("Only blocking the products missing from the catalog once the limit is reached")
+			Expect(quotaViolations(quota, usage, quotaRequest{Instances: 1})).To(BeEmpty())
+		})
+
+		It("should only check private networks when requested", func() {
+			usage := infrastructurev1beta2.ContaboQuotaUsage{PrivateNetworks: 1}
+			Expect(quotaViolations(quota, usage, quotaRequest{Instances: 1})).To(BeEmpty())
+			Expect(quotaViolations(quota, usage, quotaRequest{PrivateNetworks: 1})).To(ConsistOf("privateNetworks 1/1"))
+		})
+	})
+
+	Context("When acquiring instances within a quota", func() {
+		It("should count the ordered instances and the instances granted to other machines", func() {
+			ctx := context.Background()
+			machine := func(name string) *infrastructurev1beta2.ContaboMachine {
+				return &infrastructurev1beta2.ContaboMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: infrastructurev1beta2.ContaboMachineSpec{
+						Instance: infrastructurev1beta2.ContaboInstanceSpec{ProductId: ptr.To(infrastructurev1beta2.ContaboProductCloudVPS10NVMe)},
+					},
+				}
+			}
+			ordered, first, second := machine("ordered"), machine("first"), machine("second")
+			ordered.Status.InstanceOrder = &infrastructurev1beta2.ContaboInstanceOrderStatus{InstanceId: 100}
+			quota := &infrastructurev1beta2.ContaboQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default"},
+				Spec:       infrastructurev1beta2.ContaboQuotaSpec{MaxCpuCores: ptr.To(int64(8))},
+			}
+			k8sClient := crfake.NewClientBuilder().WithScheme(newScheme()).
+				WithObjects(quota, ordered, first, second).
+				WithStatusSubresource(&infrastructurev1beta2.ContaboMachine{}).
+				Build()
+			usage, err := computeQuotaUsage(ctx, k8sClient, "default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usage).To(Equal(infrastructurev1beta2.ContaboQuotaUsage{Instances: 1, CpuCores: 4, RamMb: 8192}))
+
+			reservations := &quotaReservations{}
+			Expect(checkQuota(ctx, k8sClient, reservations, client.ObjectKeyFromObject(first), instanceQuotaRequest(first))).To(Succeed())
+			Expect(checkQuota(ctx, k8sClient, reservations, client.ObjectKeyFromObject(second), instanceQuotaRequest(second))).
+				To(MatchError(ContainSubstring("cpuCores 8/8")))
+
+			By("Replacing the reservation with the instance once it shows up in the status")
+			first.Status.Instance = &infrastructurev1beta2.ContaboInstanceStatus{InstanceId: 101, CpuCores: 4, RamMb: 8192}
+			Expect(k8sClient.Status().Update(ctx, first)).To(Succeed())
+			Expect(checkQuota(ctx, k8sClient, reservations, client.ObjectKeyFromObject(second), instanceQuotaRequest(second))).
+				To(MatchError(ContainSubstring("cpuCores 8/8")))
+			Expect(reservations.reserved).NotTo(HaveKey(client.ObjectKeyFromObject(first)))
+
+			By("Granting the instance once the ordered instance is released")
+			ordered.Status.InstanceOrder = nil
+			Expect(k8sClient.Status().Update(ctx, ordered)).To(Succeed())
+			Expect(checkQuota(ctx, k8sClient, reservations, client.ObjectKeyFromObject(second), instanceQuotaRequest(second))).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// DefaultStuckDeletionThreshold is the time a resource may spend deleting before it is reported as stuck
+	DefaultStuckDeletionThreshold = time.Hour
+
+	// deletionMetricsListTimeout bounds the listing of the resources on each scrape
+	deletionMetricsListTimeout = 10 * time.Second
+)
+
+var (
+	deletingSecondsDesc = prometheus.NewDesc(
+		"capc_deleting_seconds",
+		"Time spent deleting by the ContaboClusters and ContaboMachines held by their finalizer",
+		[]string{"kind", "namespace", "name", "cluster"}, nil,
+	)
+	stuckDeletionsDesc = prometheus.NewDesc(
+		"capc_stuck_deletions",
+		"Number of ContaboClusters and ContaboMachines deleting for longer than the stuck deletion threshold",
+		[]string{"kind"}, nil,
+	)
+)
+
+// DeletionMetrics exports the time the ContaboClusters and ContaboMachines spend deleting, so that stuck Contabo
+// cancellations are alerted on before the clusters are noticed to never finish tearing down. The resources are read
+// from the manager cache on each scrape, a resource whose finalizer was removed is no longer reported.
+type DeletionMetrics struct {
+	Client   client.Reader
+	Settings *ProviderSettings
+
+	// now returns the current time, replaced in the tests
+	now func() time.Time
+}
+
+// NewDeletionMetrics returns the deletion metrics of the resources read with the client
+func NewDeletionMetrics(reader client.Reader, settings *ProviderSettings) *DeletionMetrics {
+	return &DeletionMetrics{Client: reader, Settings: settings, now: time.Now}
+}
+
+// Describe implements prometheus.Collector
+func (m *DeletionMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- deletingSecondsDesc
+	ch <- stuckDeletionsDesc
+}
+
+// Collect implements prometheus.Collector
+func (m *DeletionMetrics) Collect(ch chan<- prometheus.Metric) {
+	log := logf.Log.WithName("deletion-metrics")
+	ctx, cancel := context.WithTimeout(context.Background(), deletionMetricsListTimeout)
+	defer cancel()
+
+	now := m.now()
+	threshold := m.Settings.StuckDeletionThreshold()
+	collect := func(kind string, objects []client.Object) {
+		stuck := 0
+		for _, obj := range objects {
+			deletionTimestamp := obj.GetDeletionTimestamp()
+			if deletionTimestamp == nil {
+				continue
+			}
+			deleting := now.Sub(deletionTimestamp.Time)
+			if deleting > threshold {
+				stuck++
+			}
+			ch <- prometheus.MustNewConstMetric(deletingSecondsDesc, prometheus.GaugeValue, deleting.Seconds(),
+				kind, obj.GetNamespace(), obj.GetName(), obj.GetLabels()[clusterv1.ClusterNameLabel])
+		}
+		ch <- prometheus.MustNewConstMetric(stuckDeletionsDesc, prometheus.GaugeValue, float64(stuck), kind)
+	}
+
+	contaboClusters := &infrastructurev1beta2.ContaboClusterList{}
+	if err := m.Client.List(ctx, contaboClusters); err != nil {
+		log.Error(err, "Failed to list ContaboClusters")
+	} else {
+		objects := make([]client.Object, 0, len(contaboClusters.Items))
+		for i := range contaboClusters.Items {
+			objects = append(objects, &contaboClusters.Items[i])
+		}
+		collect("ContaboCluster", objects)
+	}
+
+	contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+	if err := m.Client.List(ctx, contaboMachines); err != nil {
+		log.Error(err, "Failed to list ContaboMachines")
+	} else {
+		objects := make([]client.Object, 0, len(contaboMachines.Items))
+		for i := range contaboMachines.Items {
+			objects = append(objects, &contaboMachines.Items[i])
+		}
+		collect("ContaboMachine", objects)
+	}
+}
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// DefaultEtcdBackupSchedule is the cron schedule of the etcd snapshots
+	DefaultEtcdBackupSchedule = "0 */6 * * *"
+
+	// DefaultEtcdBackupRetention is the number of etcd snapshots kept in the bucket
+	DefaultEtcdBackupRetention = 28
+
+	// EtcdBackupSecretName is the Secret of the workload cluster kube-system namespace holding the bucket and the
+	// credentials the control plane machines upload the etcd snapshots with
+	EtcdBackupSecretName = "capc-etcd-backup"
+
+	// etcdBackupScriptPath is the script of the control plane machines taking and uploading the etcd snapshots
+	etcdBackupScriptPath = "/usr/local/bin/capc-etcd-backup-upload.sh"
+)
+
+// etcdBackupScript takes an etcd snapshot on the etcd leader and uploads it to the bucket of the capc-etcd-backup
+// Secret. The credentials are read from the workload cluster when the script runs, they are not part of the user data.
+const etcdBackupScript = `#!/bin/bash
+set -euo pipefail
+export KUBECONFIG=/etc/kubernetes/admin.conf
+
+secret() {
+  kubectl -n kube-system get secret ` + EtcdBackupSecretName + ` -o "jsonpath={.data.$1}" | base64 -d
+}
+
+etcdctl() {
+  kubectl -n kube-system exec "etcd-$(hostname)" -- etcdctl \
+    --endpoints=https://127.0.0.1:2379 \
+    --cacert=/etc/kubernetes/pki/etcd/ca.crt \
+    --cert=/etc/kubernetes/pki/etcd/server.crt \
+    --key=/etc/kubernetes/pki/etcd/server.key \
+    "$@"
+}
+
+# Only the etcd leader uploads, a single snapshot is taken per schedule
+STATUS=$(etcdctl endpoint status --write-out=json)
+LEADER=$(echo "$STATUS" | grep -o '"leader":[0-9]*' | head -n 1 | cut -d: -f2)
+MEMBER=$(echo "$STATUS" | grep -o '"member_id":[0-9]*' | head -n 1 | cut -d: -f2)
+if [ -z "$LEADER" ] || [ "$LEADER" != "$MEMBER" ]; then
+  echo "Not the etcd leader, skipping the snapshot"
+  exit 0
+fi
+
+ENDPOINT=$(secret endpoint)
+REGION=$(secret region)
+BUCKET=$(secret bucket)
+PREFIX=$(secret prefix)
+
+# etcd writes the snapshot to its data directory, mounted from the host
+NAME="$(date -u +%Y%m%dT%H%M%SZ)-$(hostname).db"
+FILE="/var/lib/etcd/backup/capc-$NAME"
+mkdir -p /var/lib/etcd/backup
+trap 'rm -f "$FILE"' EXIT
+etcdctl snapshot save "$FILE"
+
+# Pass the credentials on stdin rather than on the command line
+printf 'user = "%s:%s"\n' "$(secret accessKey)" "$(secret secretKey)" | curl --config - \
+  --fail --silent --show-error --retry 3 \
+  --aws-sigv4 "aws:amz:$REGION:s3" \
+  --upload-file "$FILE" "$ENDPOINT/$BUCKET/$PREFIX$NAME"
+echo "Uploaded etcd snapshot $PREFIX$NAME"
+`
+
+// etcdBackupPrefix returns the key prefix of the etcd snapshots of the cluster in the bucket
+func etcdBackupPrefix(contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	return fmt.Sprintf("capc/%s/etcd/", contaboCluster.Spec.ClusterUUID)
+}
+
+// etcdBackupSchedule returns the cron schedule of the etcd snapshots of the cluster
+func etcdBackupSchedule(spec *infrastructurev1beta2.ContaboEtcdBackupSpec) string {
+	if spec.Schedule == "" {
+		return DefaultEtcdBackupSchedule
+	}
+	return spec.Schedule
+}
+
+// etcdBackupRetention returns the number of etcd snapshots of the cluster kept in the bucket
+func etcdBackupRetention(spec *infrastructurev1beta2.ContaboEtcdBackupSpec) int {
+	if spec.Retention <= 0 {
+		return DefaultEtcdBackupRetention
+	}
+	return int(spec.Retention)
+}
+
+// etcdBackupCloudConfig returns the cloud-config installing the etcd snapshot uploader on the kubeadm control plane
+// machines of the clusters with etcd backups, nil otherwise. The k3s and rke2 distributions snapshot etcd themselves.
+func etcdBackupCloudConfig(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, flavor bootstrapFlavor) ([]byte, error) {
+	spec := contaboCluster.Spec.EtcdBackup
+	_, controlPlane := contaboMachine.Labels[clusterv1.MachineControlPlaneLabel]
+	if spec == nil || flavor != bootstrapFlavorKubeadm || !controlPlane {
+		return nil, nil
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"packages": []interface{}{"curl"},
+		"write_files": []interface{}{
+			map[string]interface{}{
+				"path":        etcdBackupScriptPath,
+				"owner":       "root:root",
+				"permissions": "0755",
+				"content":     etcdBackupScript,
+			},
+			map[string]interface{}{
+				"path":        "/etc/cron.d/capc-etcd-backup",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     fmt.Sprintf("%s root %s >> /var/log/capc-etcd-backup.log 2>&1\n", etcdBackupSchedule(spec), etcdBackupScriptPath),
+			},
+		},
+	})
+}
+
+// etcdBackupSecretData returns the data of the capc-etcd-backup Secret of the workload cluster
+func etcdBackupSecretData(contaboCluster *infrastructurev1beta2.ContaboCluster, storage *bootstrapObjectStorage) map[string][]byte {
+	return map[string][]byte{
+		"endpoint":  []byte(strings.TrimSuffix(storage.endpoint.String(), "/")),
+		"region":    []byte(storage.region),
+		"bucket":    []byte(storage.bucket),
+		"prefix":    []byte(etcdBackupPrefix(contaboCluster)),
+		"accessKey": []byte(storage.accessKey),
+		"secretKey": []byte(storage.secretKey),
+	}
+}
+
+// newEtcdBackupObjectStorage returns the bucket of the etcd snapshots of the cluster with the credentials of its Secret
+func newEtcdBackupObjectStorage(ctx context.Context, c client.Reader, contaboCluster *infrastructurev1beta2.ContaboCluster) (*bootstrapObjectStorage, error) {
+	spec := contaboCluster.Spec.EtcdBackup.ObjectStorage
+	endpoint, err := url.Parse(spec.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object storage endpoint %q: %w", spec.Endpoint, err)
+	}
+	credentials := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: contaboCluster.Namespace, Name: spec.CredentialsSecretName}, credentials); err != nil {
+		return nil, fmt.Errorf("failed to get object storage credentials: %w", err)
+	}
+	storage := &bootstrapObjectStorage{
+		endpoint:  endpoint,
+		region:    spec.Region,
+		bucket:    spec.Bucket,
+		accessKey: string(credentials.Data["accessKey"]),
+		secretKey: string(credentials.Data["secretKey"]),
+	}
+	if storage.accessKey == "" || storage.secretKey == "" {
+		return nil, fmt.Errorf("object storage credentials secret %s/%s is missing 'accessKey' or 'secretKey' key", credentials.Namespace, credentials.Name)
+	}
+	if storage.region == "" {
+		storage.region = DefaultObjectStorageRegion
+	}
+	return storage, nil
+}
+
+// objectStorageObject is an object listed in a bucket
+type objectStorageObject struct {
+	Key          string    `xml:"Key"`
+	LastModified time.Time `xml:"LastModified"`
+	Size         int64     `xml:"Size"`
+}
+
+// listObjectsResult is the response of the ListObjectsV2 request
+type listObjectsResult struct {
+	Contents              []objectStorageObject `xml:"Contents"`
+	IsTruncated           bool                  `xml:"IsTruncated"`
+	NextContinuationToken string                `xml:"NextContinuationToken"`
+}
+
+// createBucket creates the bucket, creating a bucket already owned by the credentials succeeds
+func (s *bootstrapObjectStorage) createBucket(ctx context.Context) error {
+	status, message, err := s.doRequest(ctx, http.MethodPut, "/"+s.bucket, nil, nil)
+	if err != nil {
+		return fmt.Errorf("object storage create bucket %s failed: %w", s.bucket, err)
+	}
+	if status == http.StatusConflict && strings.Contains(string(message), "BucketAlreadyOwnedByYou") {
+		return nil
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("object storage create bucket %s failed: status %d: %s", s.bucket, status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// list returns the objects of the bucket under the prefix
+func (s *bootstrapObjectStorage) list(ctx context.Context, prefix string) ([]objectStorageObject, error) {
+	objects := []objectStorageObject{}
+	continuationToken := ""
+	for {
+		params := map[string]string{"list-type": "2", "prefix": prefix}
+		if continuationToken != "" {
+			params["continuation-token"] = continuationToken
+		}
+		status, message, err := s.doRequest(ctx, http.MethodGet, "/"+s.bucket, params, nil)
+		if err != nil {
+			return nil, fmt.Errorf("object storage list %s failed: %w", prefix, err)
+		}
+		if status < 200 || status >= 300 {
+			return nil, fmt.Errorf("object storage list %s failed: status %d: %s", prefix, status, strings.TrimSpace(string(message)))
+		}
+		result := listObjectsResult{}
+		if err := xml.Unmarshal(message, &result); err != nil {
+			return nil, fmt.Errorf("object storage list %s failed: %w", prefix, err)
+		}
+		objects = append(objects, result.Contents...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
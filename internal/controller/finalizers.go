@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// hasFinalizer returns true when the object holds the finalizer, under its current or its legacy name
+func hasFinalizer(obj client.Object, finalizer string) bool {
+	legacy := infrastructurev1beta2.LegacyFinalizer(finalizer)
+	return controllerutil.ContainsFinalizer(obj, finalizer) || (legacy != "" && controllerutil.ContainsFinalizer(obj, legacy))
+}
+
+// removeFinalizer removes the finalizer from the object, under its current and its legacy name, so that the objects
+// of the former versions of the provider are not left undeletable
+func removeFinalizer(obj client.Object, finalizer string) {
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	if legacy := infrastructurev1beta2.LegacyFinalizer(finalizer); legacy != "" {
+		controllerutil.RemoveFinalizer(obj, legacy)
+	}
+}
+
+// migrateFinalizer replaces the legacy name of the finalizer with the current one. The objects being deleted keep
+// their legacy finalizer, no finalizer can be added to them, it is removed with the current one.
+func migrateFinalizer(obj client.Object, finalizer string) {
+	legacy := infrastructurev1beta2.LegacyFinalizer(finalizer)
+	if legacy == "" || !obj.GetDeletionTimestamp().IsZero() || !controllerutil.ContainsFinalizer(obj, legacy) {
+		return
+	}
+	controllerutil.RemoveFinalizer(obj, legacy)
+	controllerutil.AddFinalizer(obj, finalizer)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	fixtureNamespace   = "default"
+	fixtureClusterUUID = "00000000-0000-0000-0000-0000000f1c75"
+)
+
+// newScheme returns a scheme of the Kubernetes, Cluster API and provider types for the fake management clusters
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	Expect(infrastructurev1beta2.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+// ownershipChain is the Cluster → Machine → ContaboMachine ownership chain of a worker machine, with the ready
+// ContaboCluster of the Cluster and the bootstrap data Secret of the Machine. The chain is complete when built, the
+// options break it to test the edge cases of the reconcilers, and nil objects are left out of the fake client.
+type ownershipChain struct {
+	Cluster         *clusterv1.Cluster
+	ContaboCluster  *infrastructurev1beta2.ContaboCluster
+	Machine         *clusterv1.Machine
+	ContaboMachine  *infrastructurev1beta2.ContaboMachine
+	BootstrapSecret *corev1.Secret
+
+	backend *fake.Backend
+}
+
+// newOwnershipChain returns the complete ownership chain of the machine name in the cluster clusterName. The private
+// network and SSH key of the ContaboCluster are created in a fake Contabo backend.
+func newOwnershipChain(clusterName string, name string) *ownershipChain {
+	backend := fake.NewBackend()
+	backend.AddImage(models.ImageResponse{ImageId: DefaultUbuntuImageID, Name: "ubuntu-24.04", OsType: "Linux", StandardImage: true})
+	sshKeyId := backend.AddSecret("[capc] "+fixtureClusterUUID, models.SecretResponseTypeSsh, "ssh-ed25519 AAAA")
+	privateNetworkId := backend.AddPrivateNetwork("[capc] "+fixtureClusterUUID, "EU")
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: fixtureNamespace, UID: types.UID("cluster-" + clusterName)},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+				APIGroup: infrastructurev1beta2.GroupVersion.Group,
+				Kind:     "ContaboCluster",
+				Name:     clusterName,
+			},
+		},
+	}
+	contaboCluster := &infrastructurev1beta2.ContaboCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: fixtureNamespace, UID: types.UID("contabocluster-" + clusterName)},
+		Spec: infrastructurev1beta2.ContaboClusterSpec{
+			ClusterUUID:    fixtureClusterUUID,
+			PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{Region: "EU"},
+		},
+		Status: infrastructurev1beta2.ContaboClusterStatus{
+			Ready:          true,
+			PrivateNetwork: &infrastructurev1beta2.ContaboPrivateNetworkStatus{PrivateNetworkId: privateNetworkId, Region: "EU"},
+			SshKey:         &infrastructurev1beta2.ContaboSshKeyStatus{Name: "[capc] " + fixtureClusterUUID, SecretId: sshKeyId},
+		},
+	}
+	bootstrapSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-bootstrap", Namespace: fixtureNamespace},
+		Data:       map[string][]byte{"value": []byte("#cloud-config\nruncmd: []\n")},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fixtureNamespace,
+			UID:       types.UID("machine-" + name),
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: clusterName,
+			Bootstrap:   clusterv1.Bootstrap{DataSecretName: ptr.To(bootstrapSecret.Name)},
+			InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+				APIGroup: infrastructurev1beta2.GroupVersion.Group,
+				Kind:     "ContaboMachine",
+				Name:     name,
+			},
+		},
+	}
+	contaboMachine := &infrastructurev1beta2.ContaboMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fixtureNamespace,
+			UID:       types.UID("contabomachine-" + name),
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Machine",
+				Name:       name,
+				UID:        machine.UID,
+			}},
+		},
+		Spec: infrastructurev1beta2.ContaboMachineSpec{
+			Instance: infrastructurev1beta2.ContaboInstanceSpec{
+				ProductId:        ptr.To(infrastructurev1beta2.ContaboProductId("V76")),
+				ProvisioningType: ptr.To(infrastructurev1beta2.ContaboInstanceProvisioningTypeReuseOrCreate),
+			},
+		},
+	}
+
+	return &ownershipChain{
+		Cluster:         cluster,
+		ContaboCluster:  contaboCluster,
+		Machine:         machine,
+		ContaboMachine:  contaboMachine,
+		BootstrapSecret: bootstrapSecret,
+		backend:         backend,
+	}
+}
+
+// withoutOwner removes the owner reference the Machine controller sets on the ContaboMachine
+func (c *ownershipChain) withoutOwner() *ownershipChain {
+	c.ContaboMachine.OwnerReferences = nil
+	return c
+}
+
+// paused pauses the Cluster
+func (c *ownershipChain) paused() *ownershipChain {
+	c.Cluster.Spec.Paused = ptr.To(true)
+	return c
+}
+
+// withoutBootstrap leaves the Machine without bootstrap data, as before the bootstrap provider generated it
+func (c *ownershipChain) withoutBootstrap() *ownershipChain {
+	c.Machine.Spec.Bootstrap.DataSecretName = nil
+	c.BootstrapSecret = nil
+	return c
+}
+
+// withoutCluster deletes the Cluster and its ContaboCluster, the Machine and ContaboMachine are left behind
+func (c *ownershipChain) withoutCluster() *ownershipChain {
+	c.Cluster = nil
+	c.ContaboCluster = nil
+	return c
+}
+
+// objects returns the objects of the chain
+func (c *ownershipChain) objects() []client.Object {
+	objects := []client.Object{c.Machine, c.ContaboMachine}
+	if c.Cluster != nil {
+		objects = append(objects, c.Cluster, c.ContaboCluster)
+	}
+	if c.BootstrapSecret != nil {
+		objects = append(objects, c.BootstrapSecret)
+	}
+	return objects
+}
+
+// build returns a ContaboMachine reconciler running against a fake management cluster holding the chain and the
+// fake Contabo backend of the chain
+func (c *ownershipChain) build() (*ContaboMachineReconciler, client.Client) {
+	scheme := newScheme()
+
+	contaboClient, err := c.backend.NewClient()
+	Expect(err).NotTo(HaveOccurred())
+
+	k8sClient := crfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(c.objects()...).
+		WithStatusSubresource(&infrastructurev1beta2.ContaboMachine{}, &infrastructurev1beta2.ContaboCluster{}).
+		Build()
+
+	return &ContaboMachineReconciler{
+		Client:        k8sClient,
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(100),
+		ContaboClient: contaboClient,
+	}, k8sClient
+}
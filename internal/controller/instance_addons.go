@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// addOnQuantity returns the number of add-ons ordered, 1 when unset
+func addOnQuantity(addOn infrastructurev1beta2.ContaboAddOnSpec) int64 {
+	return int64(max(addOn.Quantity, 1))
+}
+
+// setInstanceAddOns orders the add-ons of the machine with the instance, by their ID
+func setInstanceAddOns(addOns *models.CreateInstanceAddons, contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	for _, addOn := range contaboMachine.Spec.Instance.AddOns {
+		addOns.AddonsIds = ptr.To(append(ptr.Deref(addOns.AddonsIds, nil), models.AddOnRequest{
+			Id:       addOn.Id,
+			Quantity: addOnQuantity(addOn),
+		}))
+	}
+}
+
+// missingAddOns returns the add-ons of the machine the instance does not hold in the ordered quantity
+func missingAddOns(instance *infrastructurev1beta2.ContaboInstanceStatus, contaboMachine *infrastructurev1beta2.ContaboMachine) []infrastructurev1beta2.ContaboAddOnSpec {
+	held := map[int64]int64{}
+	for _, addOn := range instance.AddOns {
+		held[addOn.Id] += addOn.Quantity
+	}
+	missing := []infrastructurev1beta2.ContaboAddOnSpec{}
+	for _, addOn := range contaboMachine.Spec.Instance.AddOns {
+		if held[addOn.Id] < addOnQuantity(addOn) {
+			missing = append(missing, addOn)
+		}
+	}
+	return missing
+}
+
+// recordOrderedAddOns records the add-ons ordered with the instance created for the machine, so that they are not
+// upgraded again while they do not show on the new instance yet
+func recordOrderedAddOns(contaboMachine *infrastructurev1beta2.ContaboMachine, instanceId int64) {
+	contaboMachine.Status.AddOnUpgrades = nil
+	for _, addOn := range contaboMachine.Spec.Instance.AddOns {
+		if addOn.Upgrade == "" {
+			continue
+		}
+		contaboMachine.Status.AddOnUpgrades = append(contaboMachine.Status.AddOnUpgrades, infrastructurev1beta2.ContaboAddOnUpgradeStatus{
+			Id:          addOn.Id,
+			InstanceId:  instanceId,
+			UpgradeTime: metav1.Now(),
+		})
+	}
+}
+
+// upgradeInstanceRequest returns the UpgradeInstance request adding the add-on to an existing instance
+func upgradeInstanceRequest(upgrade infrastructurev1beta2.ContaboAddOnUpgrade) (models.UpgradeInstanceRequest, error) {
+	switch upgrade {
+	case infrastructurev1beta2.ContaboAddOnUpgradeBackup:
+		return models.UpgradeInstanceRequest{Backup: &models.Backup{}}, nil
+	default:
+		return models.UpgradeInstanceRequest{}, fmt.Errorf("unknown add-on upgrade %q", upgrade)
+	}
+}
+
+// reconcileAddOns adds the add-ons of the machine a reused instance does not hold with UpgradeInstance. Each add-on
+// is added once per instance, as Contabo bills every upgrade and the add-on may only show on the instance later, and
+// never to the instances created with it.
+func (r *ContaboMachineReconciler) reconcileAddOns(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) error {
+	log := logf.FromContext(ctx)
+	instance := contaboMachine.Status.Instance
+	if instance == nil {
+		return nil
+	}
+
+	// The upgrades of the former instances of the machine do not count for this one
+	contaboMachine.Status.AddOnUpgrades = slices.DeleteFunc(contaboMachine.Status.AddOnUpgrades, func(upgrade infrastructurev1beta2.ContaboAddOnUpgradeStatus) bool {
+		return upgrade.InstanceId != instance.InstanceId
+	})
+
+	for _, addOn := range missingAddOns(instance, contaboMachine) {
+		if addOn.Upgrade == "" || slices.ContainsFunc(contaboMachine.Status.AddOnUpgrades, func(upgrade infrastructurev1beta2.ContaboAddOnUpgradeStatus) bool {
+			return upgrade.Id == addOn.Id
+		}) {
+			continue
+		}
+		request, err := upgradeInstanceRequest(addOn.Upgrade)
+		if err != nil {
+			return err
+		}
+
+		log.Info("Adding add-on to instance", "instanceID", instance.InstanceId, "addOnID", addOn.Id, "upgrade", addOn.Upgrade)
+		r.recordInstanceMutation(ctx, contaboMachine, "UpgradeInstance", instance.InstanceId, "add the add-on of the machine",
+			[]instanceFieldChange{{Field: string(addOn.Upgrade), Old: "<none>", New: fmt.Sprintf("add-on %d", addOn.Id)}})
+		resp, err := r.ContaboClient.UpgradeInstanceWithResponse(ctx, instance.InstanceId, contabo.NewParams[models.UpgradeInstanceParams](ctx), request)
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to add add-on %d to instance %d: %w", addOn.Id, instance.InstanceId, err)
+		}
+		contaboMachine.Status.AddOnUpgrades = append(contaboMachine.Status.AddOnUpgrades, infrastructurev1beta2.ContaboAddOnUpgradeStatus{
+			Id:          addOn.Id,
+			InstanceId:  instance.InstanceId,
+			UpgradeTime: metav1.Now(),
+		})
+	}
+	return nil
+}
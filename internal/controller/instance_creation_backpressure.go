@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// Instance creation back-pressure, the instance creations of a Contabo account are slowed down once most of them
+// fail, e.g. during a capacity or billing incident of Contabo, instead of sending a doomed order for every machine
+// the MachineSets create
+const (
+	// InstanceCreationFailureWindow is the time the outcomes of the instance creations are counted over
+	InstanceCreationFailureWindow = 10 * time.Minute
+	// InstanceCreationFailureThreshold is the number of failed instance creations within the window engaging the
+	// back-pressure
+	InstanceCreationFailureThreshold = 5
+	// InstanceCreationFailurePercent is the percentage of failed instance creations within the window engaging the
+	// back-pressure
+	InstanceCreationFailurePercent = 50
+	// InstanceCreationBackPressureDelay is the first delay between two instance creations under back-pressure,
+	// doubled by every failed creation up to InstanceCreationBackPressureMaxDelay
+	InstanceCreationBackPressureDelay = time.Minute
+	// InstanceCreationBackPressureMaxDelay is the longest delay between two instance creations under back-pressure
+	InstanceCreationBackPressureMaxDelay = 16 * time.Minute
+)
+
+// instanceCreationOutcome is the outcome of an instance creation of the account
+type instanceCreationOutcome struct {
+	time   time.Time
+	failed bool
+}
+
+// accountBackPressure is the back-pressure state of the instance creations of a Contabo account
+type accountBackPressure struct {
+	outcomes []instanceCreationOutcome
+	// engaged is true while the instance creations are slowed down
+	engaged bool
+	// delay is the current delay between two instance creations
+	delay time.Duration
+	// next is the time the next instance creation may be sent
+	next time.Time
+}
+
+// instanceCreationBackPressure holds the back-pressure state of the Contabo accounts used by the manager, keyed by
+// contaboAccountKey
+type instanceCreationBackPressure struct {
+	mu       sync.Mutex
+	accounts map[string]*accountBackPressure
+}
+
+// contaboAccountKey identifies the Contabo account of the cluster: the credentials Secret it references, or the
+// credentials of the controller
+func contaboAccountKey(contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	if ref := contaboCluster.Spec.CredentialsRef; ref != nil {
+		return contaboCluster.Namespace + "/" + ref.Name
+	}
+	return ""
+}
+
+// account returns the state of the account, created when missing. The caller holds the lock.
+func (b *instanceCreationBackPressure) account(key string) *accountBackPressure {
+	if b.accounts == nil {
+		b.accounts = map[string]*accountBackPressure{}
+	}
+	state, ok := b.accounts[key]
+	if !ok {
+		state = &accountBackPressure{}
+		b.accounts[key] = state
+	}
+	return state
+}
+
+// wait returns the time left before an instance creation of the account may be sent, zero when it may be sent now.
+// Under back-pressure a single creation is let through per delay, probing whether Contabo recovered.
+func (b *instanceCreationBackPressure) wait(key string, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := b.account(key)
+	if !state.engaged {
+		return 0
+	}
+	if now.Before(state.next) {
+		return state.next.Sub(now)
+	}
+	state.next = now.Add(state.delay)
+	return 0
+}
+
+// record counts the outcome of an instance creation of the account. It returns true when the outcome engaged or
+// released the back-pressure, and the failed and total creations within the window.
+func (b *instanceCreationBackPressure) record(key string, failed bool, now time.Time) (changed bool, failures, total int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := b.account(key)
+
+	outcomes := state.outcomes[:0]
+	for _, outcome := range state.outcomes {
+		if now.Sub(outcome.time) < InstanceCreationFailureWindow {
+			outcomes = append(outcomes, outcome)
+		}
+	}
+	state.outcomes = append(outcomes, instanceCreationOutcome{time: now, failed: failed})
+	for _, outcome := range state.outcomes {
+		if outcome.failed {
+			failures++
+		}
+	}
+	total = len(state.outcomes)
+
+	switch {
+	case !failed && state.engaged:
+		// A probe succeeded, the past failures are forgotten so that the back-pressure does not engage right away
+		*state = accountBackPressure{outcomes: []instanceCreationOutcome{{time: now}}}
+		return true, 0, 1
+	case failed && state.engaged:
+		state.delay = min(2*state.delay, InstanceCreationBackPressureMaxDelay)
+		state.next = now.Add(state.delay)
+	case failed && failures >= InstanceCreationFailureThreshold && failures*100 >= total*InstanceCreationFailurePercent:
+		state.engaged = true
+		state.delay = InstanceCreationBackPressureDelay
+		state.next = now.Add(state.delay)
+		return true, failures, total
+	}
+	return false, failures, total
+}
+
+// currentDelay returns the current delay between two instance creations of the account, zero without back-pressure
+func (b *instanceCreationBackPressure) currentDelay(key string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if state, ok := b.accounts[key]; ok && state.engaged {
+		return state.delay
+	}
+	return 0
+}
+
+// recordInstanceCreation counts the outcome of an instance creation sent to the Contabo API, and reports the
+// back-pressure on the ContaboClusters of the account when it engages or is released
+func (r *ContaboMachineReconciler) recordInstanceCreation(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, failed bool) {
+	key := contaboAccountKey(contaboCluster)
+	changed, failures, total := r.instanceCreationBackPressure.record(key, failed, time.Now())
+	if !changed {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:   infrastructurev1beta2.ClusterInstanceCreationHealthyCondition,
+		Status: metav1.ConditionTrue,
+		Reason: infrastructurev1beta2.InstanceCreationHealthyReason,
+	}
+	eventType, eventReason := corev1.EventTypeNormal, infrastructurev1beta2.InstanceCreationRecoveredReason
+	message := fmt.Sprintf("Instance creation of ContaboMachine %s/%s succeeded, the instance creations of the Contabo account are no longer slowed down",
+		contaboMachine.Namespace, contaboMachine.Name)
+	if failed {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = infrastructurev1beta2.InstanceCreationBackPressureReason
+		eventType, eventReason = corev1.EventTypeWarning, infrastructurev1beta2.InstanceCreationBackPressureReason
+		message = fmt.Sprintf("%d of the last %d instance creations of the Contabo account failed within %s, a single instance creation is sent every %s until one succeeds. "+
+			"Consider pausing the MachineDeployments and MachineSets scaling up until the Contabo incident is resolved",
+			failures, total, InstanceCreationFailureWindow, r.instanceCreationBackPressure.currentDelay(key))
+	}
+	condition.Message = message
+	logf.FromContext(ctx).Info("Instance creation back-pressure changed", "engaged", failed, "failures", failures, "total", total)
+	r.reportInstanceCreationHealth(ctx, key, condition, eventType, eventReason)
+}
+
+// reportInstanceCreationHealth sets the InstanceCreationHealthy condition of the ContaboClusters of the account,
+// with an event on the clusters whose condition changed
+func (r *ContaboMachineReconciler) reportInstanceCreationHealth(ctx context.Context, key string, condition metav1.Condition, eventType, eventReason string) {
+	log := logf.FromContext(ctx)
+
+	contaboClusters := &infrastructurev1beta2.ContaboClusterList{}
+	if err := r.List(ctx, contaboClusters); err != nil {
+		log.Error(err, "Failed to list ContaboClusters to report the instance creation back-pressure")
+		return
+	}
+	for i := range contaboClusters.Items {
+		contaboCluster := &contaboClusters.Items[i]
+		if contaboAccountKey(contaboCluster) != key {
+			continue
+		}
+		current := meta.FindStatusCondition(contaboCluster.Status.Conditions, condition.Type)
+		// Only report the recovery on the clusters previously slowed down
+		if (current == nil && condition.Status == metav1.ConditionTrue) || (current != nil && current.Status == condition.Status) {
+			continue
+		}
+		original := contaboCluster.DeepCopy()
+		meta.SetStatusCondition(&contaboCluster.Status.Conditions, condition)
+		if err := r.Status().Patch(ctx, contaboCluster, client.MergeFrom(original)); err != nil {
+			log.Error(err, "Failed to update the instance creation back-pressure of ContaboCluster", "contaboCluster", contaboCluster.Name)
+			continue
+		}
+		if r.Recorder != nil {
+			r.Recorder.Event(contaboCluster, eventType, eventReason, condition.Message)
+		}
+	}
+}
@@ -1,14 +1,20 @@
 package controller
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/service"
 	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -19,6 +25,25 @@ import (
 	_ "embed"
 )
 
+// ErrTransientAPIFailure is returned when the Contabo API fails in a way expected to clear on its own: rate limiting,
+// server errors and transport errors. The operation is retried instead of marking the machine as failed.
+var ErrTransientAPIFailure = errors.New("transient contabo api failure")
+
+// markTransient wraps the error of a Contabo API request, see contabo.CheckResponse, with ErrTransientAPIFailure when
+// it is expected to clear on its own: transport errors, rate limiting and server errors
+func markTransient(err error) error {
+	var apiErr *contabo.APIError
+	if err == nil || (errors.As(err, &apiErr) && !apiErr.Transient()) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrTransientAPIFailure, err)
+}
+
+// instanceImageId returns the image the instance of the machine is installed with, DefaultUbuntuImageID when unset
+func instanceImageId(contaboMachine *infrastructurev1beta2.ContaboMachine) string {
+	return ptr.Deref(contaboMachine.Spec.Instance.ImageId, DefaultUbuntuImageID)
+}
+
 // getExistingInstance attempts to find an existing instance either from status or by display name
 func (r *ContaboMachineReconciler) getExistingInstance(
 	ctx context.Context,
@@ -31,7 +56,10 @@ func (r *ContaboMachineReconciler) getExistingInstance(
 	if contaboMachine.Status.Instance != nil {
 		// Get the latest status from the instance
 		instanceResp, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, contaboMachine.Status.Instance.InstanceId, nil)
-		if err != nil || instanceResp.StatusCode() < 200 || instanceResp.StatusCode() >= 300 {
+		if err := contabo.CheckResponse(instanceResp, err); err != nil {
+			return nil, fmt.Errorf("failed to find instance %d from Contabo API: %w", contaboMachine.Status.Instance.InstanceId, err)
+		}
+		if len(instanceResp.JSON200.Data) == 0 {
 			return nil, fmt.Errorf("failed to find instance %d from Contabo API", contaboMachine.Status.Instance.InstanceId)
 		}
 		instance := convertInstanceResponseData(&instanceResp.JSON200.Data[0])
@@ -40,11 +68,19 @@ func (r *ContaboMachineReconciler) getExistingInstance(
 		}
 	}
 
-	// Try to find instance by display name
+	return r.findInstanceByDisplayName(ctx, displayName)
+}
+
+// findInstanceByDisplayName returns the instance with the display name, nil when there is none. A failed lookup is an
+// error, never a missing instance, or a second instance would be created for the machine.
+func (r *ContaboMachineReconciler) findInstanceByDisplayName(ctx context.Context, displayName string) (*infrastructurev1beta2.ContaboInstanceStatus, error) {
 	instanceListResp, err := r.ContaboClient.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{
 		DisplayName: &displayName,
 	})
-	if err == nil && len(instanceListResp.JSON200.Data) > 0 {
+	if err := contabo.CheckResponse(instanceListResp, err); err != nil {
+		return nil, markTransient(fmt.Errorf("failed to list instances by display name: %w", err))
+	}
+	if len(instanceListResp.JSON200.Data) > 0 {
 		return convertListInstanceResponseData(&instanceListResp.JSON200.Data[0]), nil
 	}
 
@@ -74,17 +110,30 @@ func (r *ContaboMachineReconciler) getUsedInstanceNames(ctx context.Context) (ma
 	return usedNames, nil
 }
 
-// findReusableInstance looks for available instances that can be reused
+// getParkedInstanceNames returns the names of the instances parked by the ContaboMachinePools, reserved for them
+func (r *ContaboMachineReconciler) getParkedInstanceNames(ctx context.Context) (map[string]bool, error) {
+	// List all ContaboMachinePools across all namespaces, like the ContaboMachines using the instances
+	var poolList infrastructurev1beta2.ContaboMachinePoolList
+	if err := r.List(ctx, &poolList); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboMachinePools: %w", err)
+	}
+	parkedNames := map[string]bool{}
+	for _, pool := range poolList.Items {
+		for _, parked := range pool.Status.ParkedInstances {
+			parkedNames[parked.Name] = true
+		}
+	}
+	return parkedNames, nil
+}
+
+// findReusableInstance looks for available instances that can be reused. A machine created to claim an instance
+// parked by its ContaboMachinePool only looks for this instance, the other machines look for the free instances first
+// and then for the parked instances no pool reserves anymore, e.g. parked by a previous template.
 func (r *ContaboMachineReconciler) findReusableInstance(
 	ctx context.Context,
 	contaboMachine *infrastructurev1beta2.ContaboMachine,
 	contaboCluster *infrastructurev1beta2.ContaboCluster,
 ) (*infrastructurev1beta2.ContaboInstanceStatus, error) {
-	log := logf.FromContext(ctx)
-	displayNameEmpty := ""
-	page := int64(1)
-	size := int64(100)
-
 	// Get map of instance names already in use by other ContaboMachines
 	// This prevents multiple machines from claiming the same user-specified instance
 	usedInstanceNames, err := r.getUsedInstanceNames(ctx)
@@ -92,47 +141,89 @@ func (r *ContaboMachineReconciler) findReusableInstance(
 		return nil, fmt.Errorf("failed to get used instance names: %w", err)
 	}
 
+	// A partially adopted cluster only claims the instances carrying its provider tag
+	managed, err := managedInstances(ctx, r.ContaboClient, contaboCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := contaboMachine.Annotations[infrastructurev1beta2.ParkedInstanceAnnotation]; ok {
+		return r.findReusableInstanceWithDisplayName(ctx, contaboMachine, contaboCluster, parkedDisplayName, usedInstanceNames, managed, nil)
+	}
+	instance, err := r.findReusableInstanceWithDisplayName(ctx, contaboMachine, contaboCluster, "", usedInstanceNames, managed, nil)
+	if instance != nil || err != nil {
+		return instance, err
+	}
+	parkedInstanceNames, err := r.getParkedInstanceNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parked instance names: %w", err)
+	}
+	return r.findReusableInstanceWithDisplayName(ctx, contaboMachine, contaboCluster, parkedDisplayName, usedInstanceNames, managed, parkedInstanceNames)
+}
+
+// findReusableInstanceWithDisplayName claims the first reusable instance with the given display name, skipping the
+// instances used by other machines and the reserved ones
+func (r *ContaboMachineReconciler) findReusableInstanceWithDisplayName(
+	ctx context.Context,
+	contaboMachine *infrastructurev1beta2.ContaboMachine,
+	contaboCluster *infrastructurev1beta2.ContaboCluster,
+	displayName string,
+	usedInstanceNames map[string]*infrastructurev1beta2.ContaboMachine,
+	managed map[int64]bool,
+	reservedInstanceNames map[string]bool,
+) (*infrastructurev1beta2.ContaboInstanceStatus, error) {
+	log := logf.FromContext(ctx)
+	page := int64(1)
+	size := int64(100)
+
 	for {
 		resp, err := r.ContaboClient.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{
 			Page:        &page,
 			Size:        &size,
-			DisplayName: &displayNameEmpty,
-			ProductIds:  contaboMachine.Spec.Instance.ProductId,
-			Region:      &contaboCluster.Spec.PrivateNetwork.Region,
+			DisplayName: &displayName,
+			ProductIds:  (*string)(contaboMachine.Spec.Instance.ProductId),
+			Region:      ptr.To(placementRegion(contaboMachine, contaboCluster)),
 			Name:        contaboMachine.Spec.Instance.Name,
 		})
+		err = contabo.CheckResponse(resp, err)
+		if errors.Is(err, contabo.ErrNotFound) {
+			return nil, nil // No instances found
+		}
+		var apiErr *contabo.APIError
+		if errors.As(err, &apiErr) {
+			// Retried on the next reconciliation when transient, sleeping here would hold the instance reuse mutex
+			return nil, markTransient(fmt.Errorf("failed to list instances: %w", err))
+		}
 		if err != nil {
-			body := []byte{}
-			if resp != nil && resp.Body != nil {
-				body = resp.Body
-			}
-			log.Info("Failed to find instance from Contabo API when looking for reusable instances",
-				"error", err, "body", string(body))
+			log.Info("Failed to find instance from Contabo API when looking for reusable instances", "error", err)
 			return nil, fmt.Errorf("failed to list instances: %w", err)
 		}
 
-		if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-			if resp.StatusCode() == 429 {
-				time.Sleep(60 * time.Second)
-				continue // Retry after rate limit cooldown
-			}
-			if resp.StatusCode() == 404 {
-				return nil, nil // No instances found
-			}
-			return nil, fmt.Errorf("failed to list instances, status code: %d", resp.StatusCode())
-		}
-
 		if resp.JSON200 != nil && resp.JSON200.Data != nil {
 			if len(resp.JSON200.Data) == 0 {
 				return nil, nil
 			}
 
-			// Find instance with empty display name that is not already in use
+			// Find instance with the display name that is not already in use
 			for i := range resp.JSON200.Data {
 				instance := &resp.JSON200.Data[i]
 
-				// Check if instance has empty display name and is not cancelled
-				if instance.DisplayName != displayNameEmpty || instance.CancelDate != nil {
+				// Check if instance has the display name and is not cancelled
+				if instance.DisplayName != displayName || instance.CancelDate != nil {
+					continue
+				}
+
+				if reservedInstanceNames[instance.Name] {
+					log.V(1).Info("Skipping instance parked by a ContaboMachinePool",
+						"instanceID", instance.InstanceId,
+						"instanceName", instance.Name)
+					continue
+				}
+
+				if ignoresInstance(managed, instance.InstanceId) {
+					log.V(1).Info("Skipping instance without the provider tag of the partially adopted cluster",
+						"instanceID", instance.InstanceId,
+						"providerTag", providerTag(contaboCluster))
 					continue
 				}
 
@@ -147,8 +238,27 @@ func (r *ContaboMachineReconciler) findReusableInstance(
 					continue
 				}
 
+				// Additional IPv4 addresses are only ordered with new instances
+				if len(instance.AdditionalIps) < additionalIPv4Count(contaboMachine) {
+					log.V(1).Info("Skipping instance without enough additional IPv4 addresses",
+						"instanceID", instance.InstanceId,
+						"additionalIPs", len(instance.AdditionalIps),
+						"requested", additionalIPv4Count(contaboMachine))
+					continue
+				}
+
 				convertedInstance := convertListInstanceResponseData(instance)
 
+				// The add-ons which cannot be added to an existing instance are only ordered with new instances
+				if missing := slices.DeleteFunc(missingAddOns(convertedInstance, contaboMachine), func(addOn infrastructurev1beta2.ContaboAddOnSpec) bool {
+					return addOn.Upgrade != ""
+				}); len(missing) > 0 {
+					log.V(1).Info("Skipping instance without the add-ons of the machine",
+						"instanceID", instance.InstanceId,
+						"missingAddOnID", missing[0].Id)
+					continue
+				}
+
 				// Reset the instance by removing any private network assignments
 				if err := r.resetInstance(ctx, contaboMachine, convertedInstance, nil); err != nil {
 					log.Error(err, "Failed to reset instance",
@@ -165,21 +275,23 @@ func (r *ContaboMachineReconciler) findReusableInstance(
 					"newDisplayName", displayName,
 					"forMachine", contaboMachine.Name)
 
-				patchResp, err := r.ContaboClient.PatchInstanceWithResponse(ctx, convertedInstance.InstanceId, nil, models.PatchInstanceRequest{
+				patchResp, err := r.patchInstance(ctx, contaboMachine, convertedInstance.InstanceId, convertedInstance, models.PatchInstanceRequest{
 					DisplayName: &displayName,
-				})
+				}, "claim the reusable instance for the machine")
 				if err != nil {
 					log.Error(err, "Failed to update instance display name to claim it (network error)",
 						"instanceID", convertedInstance.InstanceId)
 					continue
 				}
-				if patchResp.StatusCode() < 200 || patchResp.StatusCode() >= 300 {
-					return nil, fmt.Errorf("failed to update instance display name to claim it, status code: %d", patchResp.StatusCode())
+				if err := contabo.CheckResponse(patchResp, nil); err != nil {
+					return nil, fmt.Errorf("failed to update instance display name to claim it: %w", err)
 				}
 
 				log.Info("Successfully updated display name on Contabo API",
-					"instanceID", convertedInstance.InstanceId,
-					"statusCode", patchResp.StatusCode())
+					"instanceID", convertedInstance.InstanceId)
+
+				// The instance leaves the free pool it was returned to by its previous machine
+				r.removeFromFreePool(ctx, convertedInstance.InstanceId)
 
 				// Update the converted instance with the new display name
 				convertedInstance.DisplayName = displayName
@@ -192,6 +304,10 @@ func (r *ContaboMachineReconciler) findReusableInstance(
 
 				return convertedInstance, nil
 			}
+
+			if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+				return nil, nil
+			}
 		}
 
 		page++
@@ -217,7 +333,7 @@ func (r *ContaboMachineReconciler) createNewInstance(
 	case *contaboMachine.Spec.Instance.ProvisioningType == infrastructurev1beta2.ContaboInstanceProvisioningTypeReuseOrCreate:
 		log.Info("No reusable instance found in Contabo API, will create a new one",
 			"productID", contaboMachine.Spec.Instance.ProductId,
-			"region", contaboCluster.Spec.PrivateNetwork.Region)
+			"region", placementRegion(contaboMachine, contaboCluster))
 
 		if contaboMachine.Spec.Instance.Name != nil && *contaboMachine.Spec.Instance.Name != "" {
 			msg := fmt.Sprintf("instance name must not be specified to create a new instance: %s", *contaboMachine.Spec.Instance.Name)
@@ -228,12 +344,12 @@ func (r *ContaboMachineReconciler) createNewInstance(
 			return nil, errors.New(msg)
 		}
 
-		sshKeys := []int64{contaboCluster.Status.SshKey.SecretId}
-		imageId := DefaultUbuntuImageID
-		region := *ConvertRegionToCreateInstanceRegion(contaboCluster.Spec.PrivateNetwork.Region)
+		sshKeys := []int64{machineSSHKeyID(contaboMachine, contaboCluster)}
+		imageId := instanceImageId(contaboMachine)
+		region := *ConvertRegionToCreateInstanceRegion(placementRegion(contaboMachine, contaboCluster))
 
-		instanceCreateResp, err := r.ContaboClient.CreateInstanceWithResponse(ctx, &models.CreateInstanceParams{}, models.CreateInstanceRequest{
-			ProductId: contaboMachine.Spec.Instance.ProductId,
+		createInstanceRequest := models.CreateInstanceRequest{
+			ProductId: (*string)(contaboMachine.Spec.Instance.ProductId),
 			Period:    1,
 			ImageId:   &imageId,
 			Region:    &region,
@@ -243,21 +359,78 @@ func (r *ContaboMachineReconciler) createNewInstance(
 			},
 			DisplayName: ptr.To(FormatDisplayName(contaboMachine, contaboCluster)),
 			DefaultUser: ptr.To(models.CreateInstanceRequestDefaultUserAdmin),
-		})
-		if err != nil || instanceCreateResp.StatusCode() < 200 || instanceCreateResp.StatusCode() >= 300 {
-			log.Error(err, "Failed to create instance in Contabo API",
-				"statusCode", instanceCreateResp.StatusCode(),
-				"body", string(instanceCreateResp.Body))
-			return nil, fmt.Errorf("failed to create instance: %w", err)
+		}
+		setAdditionalIPv4AddOns(createInstanceRequest.AddOns, contaboMachine)
+		setInstanceAddOns(createInstanceRequest.AddOns, contaboMachine)
+
+		// The passthrough properties are sent as is, the known ones are validated with the rest of the request
+		var passthrough []byte
+		if contaboMachine.Spec.Instance.ProviderSpecific != nil {
+			passthrough = contaboMachine.Spec.Instance.ProviderSpecific.Raw
+		}
+		createInstanceBody, err := service.MergeCreateInstancePassthrough(createInstanceRequest, passthrough)
+		if err != nil {
+			return nil, fmt.Errorf("invalid create instance request: %w", err)
+		}
+		if err := json.Unmarshal(createInstanceBody, &createInstanceRequest); err != nil {
+			return nil, fmt.Errorf("invalid create instance request: %w", err)
+		}
+
+		// Pre-flight validation to fail fast with clear errors instead of API round-trips
+		if err := service.NewCreateInstanceValidator(r.ContaboClient).Validate(ctx, createInstanceRequest); err != nil {
+			// Field errors are aggregated, other errors are failures of the Contabo API while validating
+			var invalid utilerrors.Aggregate
+			if !errors.As(err, &invalid) {
+				return nil, fmt.Errorf("%w: failed to validate create instance request: %w", ErrTransientAPIFailure, err)
+			}
+			log.Error(err, "Invalid create instance request")
+			return nil, fmt.Errorf("invalid create instance request: %w", err)
+		}
+
+		instanceCreateResp, err := r.ContaboClient.CreateInstanceWithBodyWithResponse(ctx, contabo.NewParams[models.CreateInstanceParams](ctx), "application/json", bytes.NewReader(createInstanceBody))
+		if err != nil {
+			r.recordInstanceCreation(ctx, contaboMachine, contaboCluster, true)
+			// The order may have been accepted, it is found by display name on the next reconciliation
+			return nil, fmt.Errorf("%w: failed to create instance: %w", ErrTransientAPIFailure, err)
+		}
+		var apiErr *contabo.APIError
+		if errors.As(contabo.CheckResponse(instanceCreateResp, nil), &apiErr) {
+			log.Error(apiErr, "Failed to create instance in Contabo API", "body", string(instanceCreateResp.Body))
+			// The products out of stock or unavailable are handled per product, they do not count as account failures
+			if isOutOfStockResponse(apiErr.StatusCode, instanceCreateResp.Body) {
+				return nil, fmt.Errorf("%w: product %s in %s: %s", ErrOutOfStock, string(ptr.Deref(contaboMachine.Spec.Instance.ProductId, "")), region, apiErr.Message)
+			}
+			if isProductUnavailableResponse(apiErr.StatusCode, instanceCreateResp.Body) {
+				return nil, fmt.Errorf("%w: product %s: %s", ErrProductUnavailable, string(ptr.Deref(contaboMachine.Spec.Instance.ProductId, "")), apiErr.Message)
+			}
+			r.recordInstanceCreation(ctx, contaboMachine, contaboCluster, true)
+			return nil, markTransient(fmt.Errorf("failed to create instance: %w", apiErr))
+		}
+		r.recordInstanceCreation(ctx, contaboMachine, contaboCluster, false)
+		if instanceCreateResp.JSON201 == nil || len(instanceCreateResp.JSON201.Data) == 0 {
+			return nil, fmt.Errorf("failed to create instance: status %d without instance", instanceCreateResp.StatusCode())
 		}
 
 		instanceId := instanceCreateResp.JSON201.Data[0].InstanceId
 		log.Info("Created new instance in Contabo API",
 			"instanceID", instanceId)
 
+		// Track the order until the instance appears, it is replaced when it never does
+		recreations := int32(0)
+		if contaboMachine.Status.InstanceOrder != nil {
+			recreations = contaboMachine.Status.InstanceOrder.Recreations
+		}
+		contaboMachine.Status.InstanceOrder = &infrastructurev1beta2.ContaboInstanceOrderStatus{
+			InstanceId:  instanceId,
+			OrderTime:   ptr.To(metav1.Now()),
+			Recreations: recreations,
+		}
+		recordOrderedAddOns(contaboMachine, instanceId)
+
 		retrieveInstanceResponse, err := r.ContaboClient.RetrieveInstanceWithResponse(ctx, instanceId, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to retrieve newly created instance: %w", err)
+		if err := contabo.CheckResponse(retrieveInstanceResponse, err); err != nil || len(retrieveInstanceResponse.JSON200.Data) == 0 {
+			log.Info("Newly created instance is not available yet", "instanceID", instanceId, "error", err)
+			return nil, nil
 		}
 
 		instance := convertInstanceResponseData(&retrieveInstanceResponse.JSON200.Data[0])
@@ -285,9 +458,9 @@ func (r *ContaboMachineReconciler) updateInstanceState(
 			"instanceID", instance.InstanceId,
 			"oldDisplayName", instance.DisplayName,
 			"newDisplayName", displayName)
-		_, err := r.ContaboClient.PatchInstanceWithResponse(ctx, instance.InstanceId, nil, models.PatchInstanceRequest{
+		_, err := r.patchInstance(ctx, contaboMachine, instance.InstanceId, instance, models.PatchInstanceRequest{
 			DisplayName: &displayName,
-		})
+		}, "name the instance after the machine")
 		if err != nil {
 			return fmt.Errorf("failed to update instance display name: %w", err)
 		}
@@ -300,7 +473,7 @@ func (r *ContaboMachineReconciler) updateInstanceState(
 	// Add private networking if not already added
 	privateNetworkFound := false
 	for _, addons := range instance.AddOns {
-		if addons.Id == 1477 { // Private Networking addon ID
+		if addons.Id == infrastructurev1beta2.ContaboPrivateNetworkingAddOnId {
 			privateNetworkFound = true
 			break
 		}
@@ -341,7 +514,7 @@ func (r *ContaboMachineReconciler) validateInstanceStatus(ctx context.Context, c
 		instance := contaboMachine.Status.Instance
 		contaboMachine.Status.Instance = nil
 
-		return ctrl.Result{RequeueAfter: 5 * time.Second}, r.handleError(
+		return ctrl.Result{RequeueAfter: r.Settings.ResourceCreationInterval()}, r.handleError(
 			ctx,
 			contaboMachine,
 			errors.New(*instance.ErrorMessage),
@@ -400,7 +573,7 @@ func (r *ContaboMachineReconciler) validateInstanceStatus(ctx context.Context, c
 			Message: message,
 		})
 		// Always requeue to keep checking until instance is ready
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 	case infrastructurev1beta2.InstanceStatusInstalling:
 		message := fmt.Sprintf("Instance %d is installing, waiting for it to be running...", contaboMachine.Status.Instance.InstanceId)
 		log.Info(message)
@@ -411,7 +584,7 @@ func (r *ContaboMachineReconciler) validateInstanceStatus(ctx context.Context, c
 			Message: message,
 		})
 		// Always requeue to keep checking until instance is ready
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 	case infrastructurev1beta2.InstanceStatusStopped:
 		message := fmt.Sprintf("Instance %d is stopped, starting it...", contaboMachine.Status.Instance.InstanceId)
 		log.Info(message)
@@ -433,7 +606,7 @@ func (r *ContaboMachineReconciler) validateInstanceStatus(ctx context.Context, c
 			})
 		}
 		// Always requeue to verify the instance started successfully
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.Settings.InstanceInterval()}, nil
 	case infrastructurev1beta2.InstanceStatusRunning:
 		message := fmt.Sprintf("Instance %d is running", contaboMachine.Status.Instance.InstanceId)
 		log.Info(message)
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// instanceFieldChange is a field of a Contabo instance changed by the controller
+type instanceFieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// String formats the change as field: old → new
+func (c instanceFieldChange) String() string {
+	return fmt.Sprintf("%s: %s → %s", c.Field, c.Old, c.New)
+}
+
+// formatInstanceChanges returns the changes on one line, or that nothing changes
+func formatInstanceChanges(changes []instanceFieldChange) string {
+	if len(changes) == 0 {
+		return "no field changes"
+	}
+	formatted := make([]string, 0, len(changes))
+	for _, change := range changes {
+		formatted = append(formatted, change.String())
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// patchInstanceChanges returns the fields of the instance, nil when unknown, changed by the patch request
+func patchInstanceChanges(instance *infrastructurev1beta2.ContaboInstanceStatus, request models.PatchInstanceRequest) []instanceFieldChange {
+	changes := []instanceFieldChange{}
+	if request.DisplayName != nil {
+		old := "<unknown>"
+		if instance != nil {
+			if instance.DisplayName == *request.DisplayName {
+				return changes
+			}
+			old = fmt.Sprintf("%q", instance.DisplayName)
+		}
+		changes = append(changes, instanceFieldChange{Field: "displayName", Old: old, New: fmt.Sprintf("%q", *request.DisplayName)})
+	}
+	return changes
+}
+
+// reinstallInstanceChanges returns the fields of the instance, nil when unknown, changed by the reinstall request. The
+// disk is always wiped, the user data is summarized by its size and digest as it holds the bootstrap secrets.
+func reinstallInstanceChanges(instance *infrastructurev1beta2.ContaboInstanceStatus, request models.ReinstallInstanceRequest) []instanceFieldChange {
+	changes := []instanceFieldChange{}
+	if instance == nil {
+		instance = &infrastructurev1beta2.ContaboInstanceStatus{}
+	}
+
+	changes = append(changes, instanceFieldChange{Field: "imageId", Old: valueOrNone(instance.ImageId), New: valueOrNone(request.ImageId)})
+
+	if request.DefaultUser != nil {
+		old := ""
+		if instance.DefaultUser != nil {
+			old = string(*instance.DefaultUser)
+		}
+		if old != string(*request.DefaultUser) {
+			changes = append(changes, instanceFieldChange{Field: "defaultUser", Old: valueOrNone(old), New: string(*request.DefaultUser)})
+		}
+	}
+
+	sshKeys := []int64{}
+	if request.SshKeys != nil {
+		sshKeys = *request.SshKeys
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(instance.SshKeys)), slices.Sorted(slices.Values(sshKeys))) {
+		changes = append(changes, instanceFieldChange{Field: "sshKeys", Old: fmt.Sprint(instance.SshKeys), New: fmt.Sprint(sshKeys)})
+	}
+
+	userData := "<none>"
+	if request.UserData != nil {
+		digest := sha256.Sum256([]byte(*request.UserData))
+		userData = fmt.Sprintf("%d bytes sha256:%s", len(*request.UserData), hex.EncodeToString(digest[:])[:12])
+	}
+	changes = append(changes, instanceFieldChange{Field: "userData", Old: "<replaced>", New: userData})
+	return changes
+}
+
+// valueOrNone returns the value, or <none> when empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+// recordInstanceMutation logs and records an event with the changes the controller is about to make to an instance
+// on Contabo, and why, so that operators can audit them
+func (r *ContaboMachineReconciler) recordInstanceMutation(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, operation string, instanceId int64, why string, changes []instanceFieldChange) {
+	log := logf.FromContext(ctx)
+
+	formatted := formatInstanceChanges(changes)
+	log.Info("Changing instance on Contabo", "operation", operation, "instanceID", instanceId, "why", why, "changes", formatted)
+	if contaboMachine != nil {
+		r.Recorder.Eventf(contaboMachine, corev1.EventTypeNormal, infrastructurev1beta2.InstanceMutationReason,
+			"%s instance %d to %s: %s", operation, instanceId, why, Truncate(formatted, 512))
+	}
+}
+
+// patchInstance patches the instance, nil when unknown, after recording the fields changing. Patches changing nothing
+// are still sent, the instance of the status may be stale.
+func (r *ContaboMachineReconciler) patchInstance(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, instanceId int64, instance *infrastructurev1beta2.ContaboInstanceStatus, request models.PatchInstanceRequest, why string) (*contaboclient.PatchInstanceResponse, error) {
+	r.recordInstanceMutation(ctx, contaboMachine, "PatchInstance", instanceId, why, patchInstanceChanges(instance, request))
+	return r.ContaboClient.PatchInstanceWithResponse(ctx, instanceId, nil, request)
+}
+
+// reinstallInstance reinstalls the instance after recording the fields changing
+func (r *ContaboMachineReconciler) reinstallInstance(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, instance *infrastructurev1beta2.ContaboInstanceStatus, request models.ReinstallInstanceRequest, why string) (*contaboclient.ReinstallInstanceResponse, error) {
+	r.recordInstanceMutation(ctx, contaboMachine, "ReinstallInstance", instance.InstanceId, why, reinstallInstanceChanges(instance, request))
+	return r.ContaboClient.ReinstallInstanceWithResponse(ctx, instance.InstanceId, contabo.NewParams[models.ReinstallInstanceParams](ctx), request)
+}
@@ -0,0 +1,331 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
+)
+
+const (
+	// DefaultJobWorkers is the number of jobs run at once when not configured
+	DefaultJobWorkers = 2
+
+	// maxJobAttempts is the number of times a job is run before it fails
+	maxJobAttempts = 5
+
+	// maxFinishedJobs is the number of finished jobs kept in the status of a machine
+	maxFinishedJobs = 5
+
+	// jobAttemptTimeout bounds an attempt of a job, the Contabo API requests wait for the rate limiter of the cluster
+	jobAttemptTimeout = 5 * time.Minute
+)
+
+// JobQueue runs the long-running operations of the ContaboMachines, such as snapshots, on its own workers so that the
+// reconciliations stay fast. The jobs are persisted in the status of the machines: the reconciliations queue them and
+// read their outcome, the workers run them and record their progress, which triggers the reconciliation of the
+// machine. The unfinished jobs are queued again when the manager starts.
+type JobQueue struct {
+	Client        client.Client
+	ContaboClient *contaboclient.ClientWithResponses
+	Settings      *ProviderSettings
+	// Workers is the number of jobs run at once
+	Workers int
+	// ReadOnly keeps the jobs pending, set with --read-only to freeze the provider during incidents
+	ReadOnly bool
+	// Credentials authorizes the Contabo API requests of the clusters referencing their own credentials
+	Credentials *ClusterCredentials
+
+	queue workqueue.TypedRateLimitingInterface[types.NamespacedName]
+}
+
+// NewJobQueue returns a job queue running the jobs on the workers, DefaultJobWorkers when not positive
+func NewJobQueue(c client.Client, contaboClient *contaboclient.ClientWithResponses, settings *ProviderSettings, workers int) *JobQueue {
+	if workers <= 0 {
+		workers = DefaultJobWorkers
+	}
+	return &JobQueue{
+		Client:        c,
+		ContaboClient: contaboClient,
+		Settings:      settings,
+		Workers:       workers,
+		queue:         workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[types.NamespacedName]()),
+	}
+}
+
+// Start implements manager.Runnable, it queues the unfinished jobs and runs the workers until the manager stops
+func (q *JobQueue) Start(ctx context.Context) error {
+	defer q.queue.ShutDown()
+
+	contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+	if err := q.Client.List(ctx, contaboMachines); err != nil {
+		return fmt.Errorf("failed to list ContaboMachines with unfinished jobs: %w", err)
+	}
+	for i := range contaboMachines.Items {
+		if unfinishedJob(&contaboMachines.Items[i]) != nil {
+			q.queue.Add(client.ObjectKeyFromObject(&contaboMachines.Items[i]))
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	for range q.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q.processNextJob(ctx) {
+			}
+		}()
+	}
+	<-ctx.Done()
+	q.queue.ShutDown()
+	wg.Wait()
+	return nil
+}
+
+// Enqueue schedules the unfinished jobs of the machine, a nil queue runs no jobs
+func (q *JobQueue) Enqueue(key types.NamespacedName) {
+	if q == nil {
+		return
+	}
+	q.queue.Add(key)
+}
+
+// Submit queues the job on the machine unless a job of the same name exists, and returns the job of the machine.
+// The job runs once the status of the machine is persisted, its progress is reported by the InstanceJobs condition.
+func (q *JobQueue) Submit(contaboMachine *infrastructurev1beta2.ContaboMachine, job infrastructurev1beta2.ContaboMachineJobStatus) infrastructurev1beta2.ContaboMachineJobStatus {
+	existing := findJob(contaboMachine, job.Name)
+	if existing == nil {
+		job.Phase = infrastructurev1beta2.ContaboMachineJobPhasePending
+		job.CreationTime = metav1.Now()
+		contaboMachine.Status.Jobs = append(contaboMachine.Status.Jobs, job)
+		setJobsCondition(contaboMachine)
+		existing = &job
+	}
+	if !jobFinished(existing) {
+		q.Enqueue(client.ObjectKeyFromObject(contaboMachine))
+	}
+	return *existing
+}
+
+// processNextJob runs the jobs of the next machine, it returns false once the queue is shut down
+func (q *JobQueue) processNextJob(ctx context.Context) bool {
+	key, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer q.queue.Done(key)
+
+	log := logf.Log.WithName("job-queue").WithValues("contaboMachine", key)
+	requeueAfter, err := q.runJob(logf.IntoContext(ctx, log), key)
+	switch {
+	case err != nil:
+		log.Error(err, "Job attempt failed, will retry")
+		q.queue.AddRateLimited(key)
+	case requeueAfter > 0:
+		q.queue.Forget(key)
+		q.queue.AddAfter(key, requeueAfter)
+	default:
+		q.queue.Forget(key)
+	}
+	return true
+}
+
+// runJob runs an attempt of the first unfinished job of the machine and records its progress. It returns the time
+// after which the job runs again when it must wait, or an error when the attempt failed and is retried.
+func (q *JobQueue) runJob(ctx context.Context, key types.NamespacedName) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	contaboMachine := &infrastructurev1beta2.ContaboMachine{}
+	if err := q.Client.Get(ctx, key, contaboMachine); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	// The jobs of a deleted machine are abandoned with its instance
+	job := unfinishedJob(contaboMachine)
+	if job == nil || !contaboMachine.DeletionTimestamp.IsZero() {
+		return 0, nil
+	}
+	// The jobs change the Contabo resources, they run once the read-only mode ends
+	if q.ReadOnly {
+		log.V(1).Info("Read-only mode, job left pending", "job", job.Name)
+		return q.Settings.DependencyInterval(), nil
+	}
+	log = log.WithValues("job", job.Name, "type", job.Type, "instanceID", job.InstanceId)
+	ctx = logf.IntoContext(ctx, log)
+
+	// Queue the Contabo API requests with the other requests of the cluster and label them with the machine
+	ctx = contextutil.WithCluster(ctx, client.ObjectKey{
+		Namespace: contaboMachine.Namespace,
+		Name:      contaboMachine.Labels[clusterv1.ClusterNameLabel],
+	}.String())
+	ctx = contextutil.WithMachine(ctx, key.String())
+	var err error
+	ctx, err = q.Credentials.IntoContextForMachine(ctx, contaboMachine)
+	if err != nil {
+		log.Info("Waiting for the Contabo credentials of the cluster", "error", err.Error())
+		return q.Settings.DependencyInterval(), nil
+	}
+
+	// Record the attempt first, a stale cache fails the optimistic lock instead of running the job twice
+	original := contaboMachine.DeepCopy()
+	now := metav1.Now()
+	if job.StartTime == nil {
+		job.StartTime = &now
+	}
+	job.Phase = infrastructurev1beta2.ContaboMachineJobPhaseRunning
+	job.Attempts++
+	setJobsCondition(contaboMachine)
+	if err := q.Client.Status().Patch(ctx, contaboMachine, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return 0, fmt.Errorf("failed to record the start of job %s: %w", job.Name, err)
+	}
+	// The patch refreshed the machine, find the job again
+	job = findJob(contaboMachine, job.Name)
+	original = contaboMachine.DeepCopy()
+
+	log.Info("Running job", "attempt", job.Attempts)
+	attemptCtx, cancel := context.WithTimeout(ctx, jobAttemptTimeout)
+	switch job.Type {
+	case infrastructurev1beta2.ContaboMachineJobTypeSnapshot:
+		err = q.runSnapshotJob(attemptCtx, contaboMachine, job)
+	default:
+		err = fmt.Errorf("unknown job type %q", job.Type)
+	}
+	cancel()
+
+	var requeueAfter time.Duration
+	switch {
+	case errors.Is(err, ErrSnapshotLimitReached):
+		// Waiting for room does not count as an attempt
+		log.Info("Snapshot limit reached, waiting before running the job", "reason", err.Error())
+		job.Phase = infrastructurev1beta2.ContaboMachineJobPhasePending
+		job.Attempts--
+		job.Message = fmt.Sprintf("Cannot snapshot instance %d: %s, delete a snapshot or enable pruning", job.InstanceId, err.Error())
+		requeueAfter = q.Settings.DependencyInterval()
+		err = nil
+	case err != nil && job.Attempts >= maxJobAttempts:
+		log.Error(err, "Job failed after its last attempt", "attempts", job.Attempts)
+		job.Phase = infrastructurev1beta2.ContaboMachineJobPhaseFailed
+		job.CompletionTime = ptr.To(metav1.Now())
+		job.Message = err.Error()
+		err = nil
+	case err != nil:
+		job.Message = err.Error()
+	default:
+		log.Info("Job succeeded", "attempts", job.Attempts)
+		job.Phase = infrastructurev1beta2.ContaboMachineJobPhaseSucceeded
+		job.CompletionTime = ptr.To(metav1.Now())
+		job.Message = ""
+	}
+	trimFinishedJobs(contaboMachine)
+	setJobsCondition(contaboMachine)
+	if perr := q.Client.Status().Patch(ctx, contaboMachine, client.MergeFrom(original)); perr != nil {
+		return 0, errors.Join(err, fmt.Errorf("failed to record the outcome of job %s: %w", job.Name, perr))
+	}
+	if err != nil {
+		return 0, err
+	}
+	if requeueAfter == 0 && unfinishedJob(contaboMachine) != nil {
+		q.queue.Add(key)
+	}
+	return requeueAfter, nil
+}
+
+// jobFinished returns true when the job succeeded or failed
+func jobFinished(job *infrastructurev1beta2.ContaboMachineJobStatus) bool {
+	return job.Phase == infrastructurev1beta2.ContaboMachineJobPhaseSucceeded || job.Phase == infrastructurev1beta2.ContaboMachineJobPhaseFailed
+}
+
+// findJob returns the job of the machine with the name, nil when not found
+func findJob(contaboMachine *infrastructurev1beta2.ContaboMachine, name string) *infrastructurev1beta2.ContaboMachineJobStatus {
+	for i := range contaboMachine.Status.Jobs {
+		if contaboMachine.Status.Jobs[i].Name == name {
+			return &contaboMachine.Status.Jobs[i]
+		}
+	}
+	return nil
+}
+
+// unfinishedJob returns the first job of the machine which did not succeed or fail, nil when there is none
+func unfinishedJob(contaboMachine *infrastructurev1beta2.ContaboMachine) *infrastructurev1beta2.ContaboMachineJobStatus {
+	for i := range contaboMachine.Status.Jobs {
+		if !jobFinished(&contaboMachine.Status.Jobs[i]) {
+			return &contaboMachine.Status.Jobs[i]
+		}
+	}
+	return nil
+}
+
+// removeJob removes the job with the name from the machine, so that a job of the same name can be queued again
+func removeJob(contaboMachine *infrastructurev1beta2.ContaboMachine, name string) {
+	contaboMachine.Status.Jobs = slices.DeleteFunc(contaboMachine.Status.Jobs, func(job infrastructurev1beta2.ContaboMachineJobStatus) bool {
+		return job.Name == name
+	})
+	setJobsCondition(contaboMachine)
+}
+
+// trimFinishedJobs keeps the maxFinishedJobs latest finished jobs of the machine, jobs are kept in the order they
+// were queued
+func trimFinishedJobs(contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	finished := 0
+	for _, job := range contaboMachine.Status.Jobs {
+		if jobFinished(&job) {
+			finished++
+		}
+	}
+	contaboMachine.Status.Jobs = slices.DeleteFunc(contaboMachine.Status.Jobs, func(job infrastructurev1beta2.ContaboMachineJobStatus) bool {
+		if finished > maxFinishedJobs && jobFinished(&job) {
+			finished--
+			return true
+		}
+		return false
+	})
+}
+
+// setJobsCondition reports the progress of the jobs of the machine with the InstanceJobs condition
+func setJobsCondition(contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	if len(contaboMachine.Status.Jobs) == 0 {
+		meta.RemoveStatusCondition(&contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceJobsCondition)
+		return
+	}
+	condition := metav1.Condition{
+		Type:   infrastructurev1beta2.InstanceJobsCondition,
+		Status: metav1.ConditionTrue,
+		Reason: infrastructurev1beta2.JobsSucceededReason,
+	}
+	if job := unfinishedJob(contaboMachine); job != nil {
+		condition.Status = metav1.ConditionFalse
+		switch {
+		case job.Phase == infrastructurev1beta2.ContaboMachineJobPhaseRunning:
+			condition.Reason = infrastructurev1beta2.JobRunningReason
+			condition.Message = fmt.Sprintf("Running %s job %s, attempt %d/%d", job.Type, job.Name, job.Attempts, maxJobAttempts)
+		case job.Message != "":
+			condition.Reason = infrastructurev1beta2.JobPendingReason
+			condition.Message = fmt.Sprintf("%s job %s waiting: %s", job.Type, job.Name, job.Message)
+		default:
+			condition.Reason = infrastructurev1beta2.JobPendingReason
+			condition.Message = fmt.Sprintf("%s job %s waiting for a worker", job.Type, job.Name)
+		}
+		if job.Phase == infrastructurev1beta2.ContaboMachineJobPhaseRunning && job.Message != "" {
+			condition.Message += ", last error: " + job.Message
+		}
+	} else if last := contaboMachine.Status.Jobs[len(contaboMachine.Status.Jobs)-1]; last.Phase == infrastructurev1beta2.ContaboMachineJobPhaseFailed {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = infrastructurev1beta2.JobFailedReason
+		condition.Message = fmt.Sprintf("%s job %s failed after %d attempts: %s", last.Type, last.Name, last.Attempts, last.Message)
+	}
+	meta.SetStatusCondition(&contaboMachine.Status.Conditions, condition)
+}
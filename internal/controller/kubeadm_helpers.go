@@ -0,0 +1,340 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+	corev1 "k8s.io/api/core/v1"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// contaboKubeletExtraArgs returns the kubelet flags required for Contabo instances to join reliably
+func contaboKubeletExtraArgs(contaboMachine *infrastructurev1beta2.ContaboMachine, internalIPv4 string) map[string]string {
+	args := map[string]string{
+		// Nodes are initialized by the controller (placeholder for an external cloud provider)
+		"cloud-provider": "external",
+		// Use the private network address for node traffic
+		"node-ip": internalIPv4,
+	}
+	// Node name must match the hostname set on the instance from the hostname pattern of the cluster
+	if contaboMachine.Status.NodeName != "" {
+		args["hostname-override"] = contaboMachine.Status.NodeName
+	} else if contaboMachine.Status.Instance != nil && contaboMachine.Status.Instance.Name != "" {
+		args["hostname-override"] = strings.ToLower(contaboMachine.Status.Instance.Name)
+	}
+	return args
+}
+
+// injectKubeletExtraArgs adds kubelet flags to the kubeadm Init/JoinConfiguration written by the bootstrap data.
+// Flags already set by the user are kept untouched.
+func injectKubeletExtraArgs(bootstrapData []byte, args map[string]string) ([]byte, error) {
+	return updateKubeadmConfigFiles(bootstrapData, func(content string) (string, error) {
+		return injectKubeletExtraArgsInKubeadmConfig(content, args)
+	})
+}
+
+// injectNodeLabelsAndTaints adds the node labels and taints of the machine to the nodeRegistration of the kubeadm
+// Init/JoinConfiguration written by the bootstrap data. Labels and taints already set by the user are kept.
+func injectNodeLabelsAndTaints(bootstrapData []byte, labels map[string]string, taints []corev1.Taint) ([]byte, error) {
+	if len(labels) == 0 && len(taints) == 0 {
+		return bootstrapData, nil
+	}
+	return updateKubeadmConfigFiles(bootstrapData, func(content string) (string, error) {
+		return injectNodeLabelsAndTaintsInKubeadmConfig(content, labels, taints)
+	})
+}
+
+// injectAPIServerBindPort sets the API server bind port of the kubeadm Init/JoinConfiguration written by the bootstrap data.
+// Ports already set by the user are kept untouched.
+func injectAPIServerBindPort(bootstrapData []byte, port int32) ([]byte, error) {
+	return updateKubeadmConfigFiles(bootstrapData, func(content string) (string, error) {
+		return injectAPIServerBindPortInKubeadmConfig(content, port)
+	})
+}
+
+// updateKubeadmConfigFiles applies update to the content of each write_files entry holding a kubeadm Init/JoinConfiguration
+func updateKubeadmConfigFiles(bootstrapData []byte, update func(content string) (string, error)) ([]byte, error) {
+	var cloudConfig map[string]interface{}
+	if err := yaml.Unmarshal(bootstrapData, &cloudConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bootstrap data: %v", err)
+	}
+
+	writeFiles, ok := cloudConfig["write_files"].([]interface{})
+	if !ok {
+		return bootstrapData, nil
+	}
+
+	updated := false
+	for _, file := range writeFiles {
+		fileMap, ok := file.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		content, ok := fileMap["content"].(string)
+		if !ok || (!strings.Contains(content, "kind: InitConfiguration") && !strings.Contains(content, "kind: JoinConfiguration")) {
+			continue
+		}
+		newContent, err := update(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update kubeadm configuration %v: %v", fileMap["path"], err)
+		}
+		fileMap["content"] = newContent
+		updated = true
+	}
+	if !updated {
+		return bootstrapData, nil
+	}
+
+	return yaml.Marshal(cloudConfig)
+}
+
+// updateKubeadmDocuments applies update to each document of a multi-document kubeadm configuration
+func updateKubeadmDocuments(content string, update func(kind string, document map[interface{}]interface{})) (string, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	documents := []map[interface{}]interface{}{}
+	for {
+		var document map[interface{}]interface{}
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", err
+		}
+		if document == nil {
+			continue
+		}
+		documents = append(documents, document)
+	}
+
+	for _, document := range documents {
+		kind, _ := document["kind"].(string)
+		update(kind, document)
+	}
+
+	var buffer bytes.Buffer
+	for _, document := range documents {
+		out, err := yaml.Marshal(document)
+		if err != nil {
+			return "", err
+		}
+		buffer.WriteString("---\n")
+		buffer.Write(out)
+	}
+	return buffer.String(), nil
+}
+
+// injectKubeletExtraArgsInKubeadmConfig updates the nodeRegistration of each Init/JoinConfiguration document
+func injectKubeletExtraArgsInKubeadmConfig(content string, args map[string]string) (string, error) {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return updateKubeadmDocuments(content, func(kind string, document map[interface{}]interface{}) {
+		if kind != "InitConfiguration" && kind != "JoinConfiguration" {
+			return
+		}
+		nodeRegistration, ok := document["nodeRegistration"].(map[interface{}]interface{})
+		if !ok {
+			nodeRegistration = map[interface{}]interface{}{}
+			document["nodeRegistration"] = nodeRegistration
+		}
+
+		// Keep the registered node name consistent with the hostname override
+		if hostname, ok := args["hostname-override"]; ok {
+			name, _ := nodeRegistration["name"].(string)
+			if name == "" || strings.Contains(name, "{{") {
+				nodeRegistration["name"] = hostname
+			}
+		}
+
+		apiVersion, _ := document["apiVersion"].(string)
+		switch extraArgs := nodeRegistration["kubeletExtraArgs"].(type) {
+		case []interface{}:
+			nodeRegistration["kubeletExtraArgs"] = appendKubeletExtraArgsList(extraArgs, keys, args)
+		case map[interface{}]interface{}:
+			for _, key := range keys {
+				if _, exists := extraArgs[key]; !exists {
+					extraArgs[key] = args[key]
+				}
+			}
+		default:
+			// kubeadm v1beta4 uses a list of name/value pairs, older versions a map
+			if strings.HasSuffix(apiVersion, "/v1beta4") {
+				nodeRegistration["kubeletExtraArgs"] = appendKubeletExtraArgsList([]interface{}{}, keys, args)
+			} else {
+				extraArgsMap := map[interface{}]interface{}{}
+				for _, key := range keys {
+					extraArgsMap[key] = args[key]
+				}
+				nodeRegistration["kubeletExtraArgs"] = extraArgsMap
+			}
+		}
+	})
+}
+
+// injectAPIServerBindPortInKubeadmConfig sets the localAPIEndpoint bindPort of the InitConfiguration
+// and of the control plane JoinConfiguration documents, the bind port is also the advertised port.
+func injectAPIServerBindPortInKubeadmConfig(content string, port int32) (string, error) {
+	return updateKubeadmDocuments(content, func(kind string, document map[interface{}]interface{}) {
+		var parent map[interface{}]interface{}
+		switch kind {
+		case "InitConfiguration":
+			parent = document
+		case "JoinConfiguration":
+			// Worker nodes do not run an API server
+			controlPlane, ok := document["controlPlane"].(map[interface{}]interface{})
+			if !ok {
+				return
+			}
+			parent = controlPlane
+		default:
+			return
+		}
+		localAPIEndpoint, ok := parent["localAPIEndpoint"].(map[interface{}]interface{})
+		if !ok {
+			localAPIEndpoint = map[interface{}]interface{}{}
+			parent["localAPIEndpoint"] = localAPIEndpoint
+		}
+		if bindPort, ok := localAPIEndpoint["bindPort"].(int); !ok || bindPort == 0 {
+			localAPIEndpoint["bindPort"] = int(port)
+		}
+	})
+}
+
+// injectNodeLabelsAndTaintsInKubeadmConfig merges the labels in the node-labels kubelet flag and appends the taints
+// to the nodeRegistration of each Init/JoinConfiguration document
+func injectNodeLabelsAndTaintsInKubeadmConfig(content string, labels map[string]string, taints []corev1.Taint) (string, error) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return updateKubeadmDocuments(content, func(kind string, document map[interface{}]interface{}) {
+		if kind != "InitConfiguration" && kind != "JoinConfiguration" {
+			return
+		}
+		nodeRegistration, ok := document["nodeRegistration"].(map[interface{}]interface{})
+		if !ok {
+			nodeRegistration = map[interface{}]interface{}{}
+			document["nodeRegistration"] = nodeRegistration
+		}
+
+		if len(keys) > 0 {
+			apiVersion, _ := document["apiVersion"].(string)
+			nodeLabels := kubeletExtraArg(nodeRegistration, "node-labels")
+			existing := map[string]bool{}
+			for _, label := range strings.Split(nodeLabels, ",") {
+				if key, _, _ := strings.Cut(label, "="); key != "" {
+					existing[key] = true
+				}
+			}
+			for _, key := range keys {
+				if existing[key] {
+					continue
+				}
+				if nodeLabels != "" {
+					nodeLabels += ","
+				}
+				nodeLabels += key + "=" + labels[key]
+			}
+			setKubeletExtraArg(nodeRegistration, apiVersion, "node-labels", nodeLabels)
+		}
+
+		if len(taints) > 0 {
+			existing, ok := nodeRegistration["taints"].([]interface{})
+			if !ok {
+				existing = []interface{}{}
+				// Setting the taints replaces the control plane taint kubeadm sets when none are configured
+				if _, controlPlane := document["controlPlane"]; kind == "InitConfiguration" || controlPlane {
+					existing = append(existing, map[interface{}]interface{}{
+						"key":    "node-role.kubernetes.io/control-plane",
+						"effect": string(corev1.TaintEffectNoSchedule),
+					})
+				}
+			}
+			for _, taint := range taints {
+				if slices.ContainsFunc(existing, func(item interface{}) bool {
+					itemMap, ok := item.(map[interface{}]interface{})
+					return ok && itemMap["key"] == taint.Key && itemMap["effect"] == string(taint.Effect)
+				}) {
+					continue
+				}
+				item := map[interface{}]interface{}{"key": taint.Key, "effect": string(taint.Effect)}
+				if taint.Value != "" {
+					item["value"] = taint.Value
+				}
+				existing = append(existing, item)
+			}
+			nodeRegistration["taints"] = existing
+		}
+	})
+}
+
+// kubeletExtraArg returns the value of a kubelet flag of the nodeRegistration, in the kubeadm v1beta4 list or the
+// older map format
+func kubeletExtraArg(nodeRegistration map[interface{}]interface{}, name string) string {
+	switch extraArgs := nodeRegistration["kubeletExtraArgs"].(type) {
+	case []interface{}:
+		for _, arg := range extraArgs {
+			if argMap, ok := arg.(map[interface{}]interface{}); ok && argMap["name"] == name {
+				value, _ := argMap["value"].(string)
+				return value
+			}
+		}
+	case map[interface{}]interface{}:
+		value, _ := extraArgs[name].(string)
+		return value
+	}
+	return ""
+}
+
+// setKubeletExtraArg sets a kubelet flag of the nodeRegistration, in the format used by the kubeadm apiVersion when
+// no flag is set yet
+func setKubeletExtraArg(nodeRegistration map[interface{}]interface{}, apiVersion string, name string, value string) {
+	switch extraArgs := nodeRegistration["kubeletExtraArgs"].(type) {
+	case []interface{}:
+		for _, arg := range extraArgs {
+			if argMap, ok := arg.(map[interface{}]interface{}); ok && argMap["name"] == name {
+				argMap["value"] = value
+				return
+			}
+		}
+		nodeRegistration["kubeletExtraArgs"] = append(extraArgs, map[interface{}]interface{}{"name": name, "value": value})
+	case map[interface{}]interface{}:
+		extraArgs[name] = value
+	default:
+		if strings.HasSuffix(apiVersion, "/v1beta4") {
+			nodeRegistration["kubeletExtraArgs"] = []interface{}{map[interface{}]interface{}{"name": name, "value": value}}
+		} else {
+			nodeRegistration["kubeletExtraArgs"] = map[interface{}]interface{}{name: value}
+		}
+	}
+}
+
+// appendKubeletExtraArgsList appends the missing flags to a kubeadm v1beta4 kubeletExtraArgs list
+func appendKubeletExtraArgsList(extraArgs []interface{}, keys []string, args map[string]string) []interface{} {
+	existing := map[string]bool{}
+	for _, arg := range extraArgs {
+		if argMap, ok := arg.(map[interface{}]interface{}); ok {
+			if name, ok := argMap["name"].(string); ok {
+				existing[name] = true
+			}
+		}
+	}
+	for _, key := range keys {
+		if !existing[key] {
+			extraArgs = append(extraArgs, map[interface{}]interface{}{"name": key, "value": args[key]})
+		}
+	}
+	return extraArgs
+}
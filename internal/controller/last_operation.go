@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
+)
+
+// clusterLastOperationConditionTypes are the conditions of the ContaboCluster deciding its last operation, in the
+// order the reconciliation goes through them
+var clusterLastOperationConditionTypes = []string{
+	infrastructurev1beta2.ClusterCredentialsValidCondition,
+	infrastructurev1beta2.ClusterPrivateNetworkReadyCondition,
+	infrastructurev1beta2.ClusterSshKeyReadyCondition,
+	infrastructurev1beta2.ClusterReadyCondition,
+}
+
+// setMachineLastOperation reports the last operation of the controller on the ContaboMachine from its lifecycle, its
+// conditions and the error of the reconciliation, once its Ready condition is summarized
+func setMachineLastOperation(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, err error) {
+	status := &contaboMachine.Status
+	provisioned := status.Initialization != nil && status.Initialization.Provisioned
+	operationType := lastOperationType(contaboMachine.DeletionTimestamp, provisioned, status.LastOperation)
+
+	// The Ready condition tells the reason of the deletion, the instance ones the reason of the other operations
+	conditionTypes := slices.Concat(machineReadyConditionTypes, []string{infrastructurev1beta2.MachineReadyCondition})
+	if operationType == infrastructurev1beta2.ContaboLastOperationTypeDelete {
+		conditionTypes = []string{infrastructurev1beta2.MachineReadyCondition}
+	}
+	condition := decidingCondition(status.Conditions, conditionTypes)
+
+	state := lastOperationState(condition, err)
+	if status.FailureReason != nil && operationType != infrastructurev1beta2.ContaboLastOperationTypeDelete {
+		// The InstanceReady condition keeps the detailed reason of the terminal failure
+		state = infrastructurev1beta2.ContaboLastOperationStateFailed
+		condition = &metav1.Condition{Reason: *status.FailureReason, Message: ptr.Deref(status.FailureMessage, "")}
+		if instanceReady := meta.FindStatusCondition(status.Conditions, infrastructurev1beta2.InstanceReadyCondition); instanceReady != nil {
+			condition.Reason = instanceReady.Reason
+		}
+	}
+	setLastOperation(&status.LastOperation, operationType, state, condition, err, contextutil.LastChangeFromContext(ctx))
+}
+
+// setClusterLastOperation reports the last operation of the controller on the ContaboCluster from its lifecycle, its
+// conditions and the error of the reconciliation
+func setClusterLastOperation(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, err error) {
+	status := &contaboCluster.Status
+	provisioned := status.Initialization != nil && status.Initialization.Provisioned
+	operationType := lastOperationType(contaboCluster.DeletionTimestamp, provisioned, status.LastOperation)
+	conditionTypes := clusterLastOperationConditionTypes
+	if operationType == infrastructurev1beta2.ContaboLastOperationTypeDelete {
+		conditionTypes = []string{infrastructurev1beta2.ClusterReadyCondition}
+	}
+	condition := decidingCondition(status.Conditions, conditionTypes)
+	setLastOperation(&status.LastOperation, operationType, lastOperationState(condition, err), condition, err,
+		contextutil.LastChangeFromContext(ctx))
+}
+
+// lastOperationType returns the type of the operation of the controller on a resource: Create until the resource is
+// ready for the first time, failed creations included, then Reconcile, and Delete once deleted. The resources
+// provisioned by the former versions are reconciled.
+func lastOperationType(deletionTimestamp *metav1.Time, provisioned bool, lastOperation *infrastructurev1beta2.ContaboLastOperation) infrastructurev1beta2.ContaboLastOperationType {
+	switch {
+	case !deletionTimestamp.IsZero():
+		return infrastructurev1beta2.ContaboLastOperationTypeDelete
+	case lastOperation == nil && !provisioned:
+		return infrastructurev1beta2.ContaboLastOperationTypeCreate
+	case lastOperation != nil && lastOperation.Type == infrastructurev1beta2.ContaboLastOperationTypeCreate &&
+		lastOperation.State != infrastructurev1beta2.ContaboLastOperationStateSucceeded:
+		return infrastructurev1beta2.ContaboLastOperationTypeCreate
+	default:
+		return infrastructurev1beta2.ContaboLastOperationTypeReconcile
+	}
+}
+
+// decidingCondition returns the first of the conditions which is not true, the reason the resource is not ready, or
+// the last one found when all are true
+func decidingCondition(conditions []metav1.Condition, conditionTypes []string) *metav1.Condition {
+	var found *metav1.Condition
+	for _, conditionType := range conditionTypes {
+		condition := meta.FindStatusCondition(conditions, conditionType)
+		if condition == nil {
+			continue
+		}
+		if condition.Status != metav1.ConditionTrue {
+			return condition
+		}
+		found = condition
+	}
+	return found
+}
+
+// lastOperationState returns the state of an operation whose deciding condition is condition, Succeeded when the
+// last condition is true as all the others are
+func lastOperationState(condition *metav1.Condition, err error) infrastructurev1beta2.ContaboLastOperationState {
+	switch {
+	case err != nil:
+		return infrastructurev1beta2.ContaboLastOperationStateError
+	case condition != nil && condition.Status == metav1.ConditionTrue:
+		return infrastructurev1beta2.ContaboLastOperationStateSucceeded
+	default:
+		return infrastructurev1beta2.ContaboLastOperationStateProcessing
+	}
+}
+
+// setLastOperation updates the last operation with the state and the reason of the deciding condition, or the error
+// of the reconciliation. A new operation starts when a finished operation changes state, e.g. a machine which was
+// ready being rolled out, or when an operation in progress changes type, and finishes once it succeeds or fails. The request ID is the one
+// of the last Contabo API request of the operation changing resources.
+func setLastOperation(lastOperation **infrastructurev1beta2.ContaboLastOperation, operationType infrastructurev1beta2.ContaboLastOperationType, state infrastructurev1beta2.ContaboLastOperationState, condition *metav1.Condition, err error, requestID string) {
+	now := metav1.Now()
+	operation := *lastOperation
+	finished := operation != nil && operation.FinishedAt != nil
+	if operation == nil || (finished && operation.State != state) || (!finished && operation.Type != operationType) {
+		operation = &infrastructurev1beta2.ContaboLastOperation{Type: operationType, StartedAt: now}
+		*lastOperation = operation
+	}
+
+	operation.State = state
+	operation.Reason, operation.Message = "", ""
+	if condition != nil {
+		operation.Reason, operation.Message = condition.Reason, condition.Message
+	}
+	if err != nil {
+		operation.Message = err.Error()
+	}
+	operation.Message = Truncate(operation.Message, 1024)
+	if requestID != "" {
+		operation.RequestID = requestID
+	}
+
+	switch state {
+	case infrastructurev1beta2.ContaboLastOperationStateSucceeded, infrastructurev1beta2.ContaboLastOperationStateFailed:
+		if operation.FinishedAt == nil {
+			operation.FinishedAt = &now
+		}
+	default:
+		operation.FinishedAt = nil
+	}
+}
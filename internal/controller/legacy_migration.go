@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/version"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// LegacyMigrationConfigMapName is the ConfigMap of the controller namespace recording the completion of the legacy
+// resources migration, the migration is skipped while it exists
+const LegacyMigrationConfigMapName = "capc-legacy-migration"
+
+// legacyInstanceStates are the display name prefixes of the instance states of the provider versions predating the
+// [capc] display names
+var legacyInstanceStates = []string{StateAvailable, StateProvisioning, StateClusterBound, StateError}
+
+// LegacyMigration relabels once the resources created by older provider versions to the current scheme, before the
+// controllers start. Older versions recorded the cluster UUID in the ClusterUUIDLabel instead of the spec, and named
+// the instances after their state: the controllers would generate a new UUID, then consider the private network,
+// SSH key and instances named after the previous one as orphans, and order new instances for the machines.
+type LegacyMigration struct {
+	Client        client.Client
+	ContaboClient *contaboclient.ClientWithResponses
+	// Namespace is the namespace of the ConfigMap recording the completion, the controller namespace
+	Namespace string
+}
+
+// legacyMigrationResult counts the resources migrated
+type legacyMigrationResult struct {
+	clusters  int
+	instances int
+	// skipped lists the legacy instances without machine left untouched
+	skipped []string
+}
+
+// Run migrates the legacy resources unless the migration already completed, and records its completion. A failed
+// migration is not recorded and runs again on the next start, each step is idempotent.
+func (m *LegacyMigration) Run(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("legacy-migration")
+
+	configMap := &corev1.ConfigMap{}
+	err := m.Client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: LegacyMigrationConfigMapName}, configMap)
+	if err == nil {
+		log.Info("Legacy resources already migrated", "completedAt", configMap.Data["completedAt"], "version", configMap.Data["version"])
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the legacy migration ConfigMap: %w", err)
+	}
+
+	result := &legacyMigrationResult{}
+	if err := m.migrateClusterUUIDs(ctx, result); err != nil {
+		return err
+	}
+	if err := m.migrateInstanceDisplayNames(ctx, result); err != nil {
+		return err
+	}
+
+	configMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: LegacyMigrationConfigMapName, Namespace: m.Namespace},
+		Data: map[string]string{
+			"completedAt": time.Now().UTC().Format(time.RFC3339),
+			"version":     version.Get().String(),
+			"clusters":    strconv.Itoa(result.clusters),
+			"instances":   strconv.Itoa(result.instances),
+			"skipped":     strings.Join(result.skipped, ","),
+		},
+	}
+	if err := m.Client.Create(ctx, configMap); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to record the legacy migration: %w", err)
+	}
+	log.Info("Migrated legacy resources", "clusters", result.clusters, "instances", result.instances, "skipped", result.skipped)
+	return nil
+}
+
+// migrateClusterUUIDs moves the cluster UUID of the ClusterUUIDLabel to the spec of the ContaboClusters. A label
+// conflicting with the UUID of the spec is kept for an operator to decide.
+func (m *LegacyMigration) migrateClusterUUIDs(ctx context.Context, result *legacyMigrationResult) error {
+	log := logf.FromContext(ctx).WithName("legacy-migration")
+
+	contaboClusters := &infrastructurev1beta2.ContaboClusterList{}
+	if err := m.Client.List(ctx, contaboClusters); err != nil {
+		return fmt.Errorf("failed to list ContaboClusters: %w", err)
+	}
+	for i := range contaboClusters.Items {
+		contaboCluster := &contaboClusters.Items[i]
+		legacyUUID := contaboCluster.Labels[ClusterUUIDLabel]
+		if legacyUUID == "" {
+			continue
+		}
+		if contaboCluster.Spec.ClusterUUID != "" && contaboCluster.Spec.ClusterUUID != legacyUUID {
+			log.Info("ContaboCluster has a legacy cluster UUID label conflicting with its spec, keeping both",
+				"contaboCluster", client.ObjectKeyFromObject(contaboCluster), "clusterUUID", contaboCluster.Spec.ClusterUUID, "legacyClusterUUID", legacyUUID)
+			continue
+		}
+
+		original := contaboCluster.DeepCopy()
+		contaboCluster.Spec.ClusterUUID = legacyUUID
+		delete(contaboCluster.Labels, ClusterUUIDLabel)
+		if err := m.Client.Patch(ctx, contaboCluster, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to migrate the cluster UUID of ContaboCluster %s: %w", client.ObjectKeyFromObject(contaboCluster), err)
+		}
+		log.Info("Migrated legacy cluster UUID label", "contaboCluster", client.ObjectKeyFromObject(contaboCluster), "clusterUUID", legacyUUID)
+		result.clusters++
+	}
+	return nil
+}
+
+// migrateInstanceDisplayNames renames the instances named after a legacy state: the instances of a machine get the
+// display name of the machine, the available ones without machine go back to the pool of unnamed instances. The other
+// legacy instances without machine are left untouched and reported.
+func (m *LegacyMigration) migrateInstanceDisplayNames(ctx context.Context, result *legacyMigrationResult) error {
+	log := logf.FromContext(ctx).WithName("legacy-migration")
+
+	displayNames, err := m.machineDisplayNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	for page := int64(1); ; page++ {
+		resp, err := m.ContaboClient.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{
+			Page: &page,
+			Size: ptr.To(int64(inventoryPageSize)),
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to list instances: %w", err)
+		}
+		for _, instance := range resp.JSON200.Data {
+			if !isLegacyDisplayName(instance.DisplayName) {
+				continue
+			}
+			displayName, owned := displayNames[instance.InstanceId]
+			switch {
+			case owned:
+			case strings.HasPrefix(instance.DisplayName, StateAvailable):
+				displayName = ""
+			default:
+				result.skipped = append(result.skipped, strconv.FormatInt(instance.InstanceId, 10))
+				continue
+			}
+
+			patchResp, err := m.ContaboClient.PatchInstanceWithResponse(ctx, instance.InstanceId, nil, models.PatchInstanceRequest{DisplayName: &displayName})
+			if err := contabo.CheckResponse(patchResp, err); err != nil {
+				return fmt.Errorf("failed to rename legacy instance %d: %w", instance.InstanceId, err)
+			}
+			log.Info("Renamed legacy instance", "instanceID", instance.InstanceId, "oldDisplayName", instance.DisplayName, "newDisplayName", displayName)
+			result.instances++
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			return nil
+		}
+	}
+}
+
+// machineDisplayNames returns the display names of the instances of the ContaboMachines, by instance ID
+func (m *LegacyMigration) machineDisplayNames(ctx context.Context) (map[int64]string, error) {
+	contaboClusters := &infrastructurev1beta2.ContaboClusterList{}
+	if err := m.Client.List(ctx, contaboClusters); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboClusters: %w", err)
+	}
+	clusters := map[client.ObjectKey]*infrastructurev1beta2.ContaboCluster{}
+	for i := range contaboClusters.Items {
+		contaboCluster := &contaboClusters.Items[i]
+		clusters[client.ObjectKey{Namespace: contaboCluster.Namespace, Name: legacyClusterName(contaboCluster)}] = contaboCluster
+	}
+
+	contaboMachines := &infrastructurev1beta2.ContaboMachineList{}
+	if err := m.Client.List(ctx, contaboMachines); err != nil {
+		return nil, fmt.Errorf("failed to list ContaboMachines: %w", err)
+	}
+	displayNames := map[int64]string{}
+	for i := range contaboMachines.Items {
+		contaboMachine := &contaboMachines.Items[i]
+		contaboCluster := clusters[client.ObjectKey{Namespace: contaboMachine.Namespace, Name: contaboMachine.Labels[clusterv1.ClusterNameLabel]}]
+		if contaboMachine.Status.Instance == nil || contaboMachine.Spec.Index == nil || contaboCluster == nil || contaboCluster.Spec.ClusterUUID == "" {
+			continue
+		}
+		displayNames[contaboMachine.Status.Instance.InstanceId] = FormatDisplayName(contaboMachine, contaboCluster)
+	}
+	return displayNames, nil
+}
+
+// isLegacyDisplayName reports whether the display name is a state of the provider versions predating [capc]
+func isLegacyDisplayName(displayName string) bool {
+	for _, state := range legacyInstanceStates {
+		if displayName == state || strings.HasPrefix(displayName, state+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyClusterName returns the name of the Cluster of the ContaboCluster, from its label or its owner
+func legacyClusterName(contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	if clusterName := contaboCluster.Labels[clusterv1.ClusterNameLabel]; clusterName != "" {
+		return clusterName
+	}
+	for _, ownerReference := range contaboCluster.OwnerReferences {
+		if ownerReference.Kind == "Cluster" {
+			return ownerReference.Name
+		}
+	}
+	return contaboCluster.Name
+}
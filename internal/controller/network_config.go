@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// UserNetworkConfigFile is the netplan configuration of the instances holding the network-config of the machine, it
+// is applied after the Contabo netplan configuration
+const UserNetworkConfigFile = "/etc/netplan/60-capc-network-config.yaml"
+
+// parseNetworkConfig parses a cloud-init network-config version 2 document, with or without the top-level network
+// key, and returns it as a netplan configuration
+func parseNetworkConfig(raw string) (map[string]interface{}, error) {
+	document := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(raw), &document); err != nil {
+		return nil, fmt.Errorf("invalid network-config: %w", err)
+	}
+
+	network := document
+	if nested, ok := document["network"]; ok {
+		if len(document) != 1 {
+			return nil, errors.New("invalid network-config: network must be the only top-level key")
+		}
+		nestedMap, ok := nested.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.New("invalid network-config: network must be a mapping")
+		}
+		network = map[string]interface{}{}
+		for key, value := range nestedMap {
+			network[fmt.Sprint(key)] = value
+		}
+	}
+	if version, ok := network["version"].(int); !ok || version != 2 {
+		return nil, fmt.Errorf("invalid network-config: version must be 2, got %v", network["version"])
+	}
+
+	return map[string]interface{}{"network": network}, nil
+}
+
+// networkCloudConfig returns the cloud-config writing the network-config of the machine as a netplan configuration
+// and applying it, nil when the machine has no network-config
+func networkCloudConfig(contaboMachine *infrastructurev1beta2.ContaboMachine) ([]byte, error) {
+	if contaboMachine.Spec.NetworkConfig == nil || *contaboMachine.Spec.NetworkConfig == "" {
+		return nil, nil
+	}
+
+	netplan, err := parseNetworkConfig(*contaboMachine.Spec.NetworkConfig)
+	if err != nil {
+		return nil, err
+	}
+	content, err := yaml.Marshal(netplan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode network-config: %w", err)
+	}
+
+	return yaml.Marshal(map[string]interface{}{
+		"write_files": []interface{}{
+			map[string]interface{}{
+				"path":        UserNetworkConfigFile,
+				"owner":       "root:root",
+				"permissions": "0600",
+				"content":     string(content),
+			},
+		},
+		"runcmd": []interface{}{
+			"netplan generate && netplan apply",
+		},
+	})
+}
+
+// dnsCloudConfig returns the cloud-config setting the resolvers of the machine on the public interface at every boot,
+// with systemd-resolved when it runs and in /etc/resolv.conf otherwise, nil when the machine has no DNS settings.
+// The ${EXTERNAL_IPV4} variable is replaced with the rest of the cloud-config.
+func dnsCloudConfig(contaboMachine *infrastructurev1beta2.ContaboMachine) ([]byte, error) {
+	dns := contaboMachine.Spec.DNS
+	if dns == nil || (len(dns.Nameservers) == 0 && len(dns.SearchDomains) == 0) {
+		return nil, nil
+	}
+
+	resolvedCommands := []string{}
+	resolvConf := []string{}
+	if len(dns.Nameservers) > 0 {
+		resolvedCommands = append(resolvedCommands, fmt.Sprintf(`  resolvectl dns "$iface" %s`, strings.Join(dns.Nameservers, " ")))
+		for _, nameserver := range dns.Nameservers {
+			resolvConf = append(resolvConf, "nameserver "+nameserver)
+		}
+	}
+	if len(dns.SearchDomains) > 0 {
+		resolvedCommands = append(resolvedCommands, fmt.Sprintf(`  resolvectl domain "$iface" %s`, strings.Join(dns.SearchDomains, " ")))
+		resolvConf = append(resolvConf, "search "+strings.Join(dns.SearchDomains, " "))
+	}
+
+	script := strings.Join(append(append([]string{
+		"#!/bin/sh",
+		"if systemctl is-active --quiet systemd-resolved; then",
+		`  iface=$(ip -o -4 addr show | awk -v ip="${EXTERNAL_IPV4}" 'index($4, ip "/") == 1 {print $2; exit}')`,
+		`  [ -n "$iface" ] || { echo "[CAPC] Error: public interface not found for ${EXTERNAL_IPV4}"; exit 1; }`,
+	}, resolvedCommands...),
+		"else",
+		"  rm -f /etc/resolv.conf",
+		"  cat > /etc/resolv.conf <<EOF",
+		strings.Join(resolvConf, "\n"),
+		"EOF",
+		"fi",
+	), "\n")
+	service := strings.Join([]string{
+		"[Unit]",
+		"Description=Configure the DNS resolvers of the machine",
+		"After=network-online.target systemd-resolved.service",
+		"Wants=network-online.target",
+		"",
+		"[Service]",
+		"Type=oneshot",
+		"ExecStart=/usr/local/bin/capc-dns.sh",
+		"",
+		"[Install]",
+		"WantedBy=multi-user.target",
+	}, "\n")
+
+	return yaml.Marshal(map[string]interface{}{
+		"write_files": []interface{}{
+			map[string]interface{}{
+				"path":        "/usr/local/bin/capc-dns.sh",
+				"owner":       "root:root",
+				"permissions": "0755",
+				"content":     script,
+			},
+			map[string]interface{}{
+				"path":        "/etc/systemd/system/capc-dns.service",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     service,
+			},
+		},
+		"runcmd": []interface{}{
+			"systemctl daemon-reload && systemctl enable capc-dns.service && systemctl start capc-dns.service",
+		},
+	})
+}
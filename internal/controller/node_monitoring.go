@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// NodeExporterPort is the port the node-exporter of the machines with node monitoring listens on
+	NodeExporterPort = 9100
+
+	// nodeExporterTextfileDirectory is the directory of the textfile collector of the node-exporter package
+	nodeExporterTextfileDirectory = "/var/lib/prometheus/node-exporter"
+)
+
+// prometheusLabelValueReplacer escapes the label values of the Prometheus text format
+var prometheusLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// nodeMonitoringInfoMetric returns the capc_machine_info metric tying the metrics of the node-exporter back to the
+// ContaboMachine, in the Prometheus text format
+func nodeMonitoringInfoMetric(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	labels := [][2]string{
+		{"namespace", contaboMachine.Namespace},
+		{"contabo_machine", contaboMachine.Name},
+		{"cluster_uuid", contaboCluster.Spec.ClusterUUID},
+		{"role", machineRoleName(contaboMachine)},
+	}
+	if contaboMachine.Status.Instance != nil {
+		labels = append(labels, [2]string{"instance_id", fmt.Sprint(contaboMachine.Status.Instance.InstanceId)})
+	}
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, label[0], prometheusLabelValueReplacer.Replace(label[1])))
+	}
+	return strings.Join([]string{
+		"# HELP capc_machine_info The ContaboMachine of the instance.",
+		"# TYPE capc_machine_info gauge",
+		fmt.Sprintf("capc_machine_info{%s} 1", strings.Join(pairs, ",")),
+		"",
+	}, "\n")
+}
+
+// nodeMonitoringCloudConfig returns the cloud-config installing the node-exporter of the distribution on the machines
+// with node monitoring, nil otherwise. The node-exporter only listens on the private IPv4 of the machine, the
+// capc_machine_info metric is exposed with its textfile collector.
+// The ${INTERNAL_IPV4} variable is replaced with the rest of the cloud-config.
+func nodeMonitoringCloudConfig(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) ([]byte, error) {
+	if !contaboMachine.Spec.EnableNodeMonitoring {
+		return nil, nil
+	}
+
+	// The drop-in is written before the package is installed, so that the node-exporter never listens publicly
+	dropIn := strings.Join([]string{
+		"[Service]",
+		"ExecStart=",
+		fmt.Sprintf("ExecStart=/usr/bin/prometheus-node-exporter --web.listen-address=${INTERNAL_IPV4}:%d --collector.textfile.directory=%s",
+			NodeExporterPort, nodeExporterTextfileDirectory),
+	}, "\n")
+
+	return yaml.Marshal(map[string]interface{}{
+		"packages": []interface{}{"prometheus-node-exporter"},
+		"write_files": []interface{}{
+			map[string]interface{}{
+				"path":        "/etc/systemd/system/prometheus-node-exporter.service.d/capc.conf",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     dropIn,
+			},
+			map[string]interface{}{
+				"path":        nodeExporterTextfileDirectory + "/capc.prom",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     nodeMonitoringInfoMetric(contaboMachine, contaboCluster),
+			},
+		},
+		"runcmd": []interface{}{
+			"systemctl daemon-reload && systemctl enable prometheus-node-exporter.service && systemctl restart prometheus-node-exporter.service",
+		},
+	})
+}
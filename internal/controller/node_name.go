@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// DefaultHostnamePattern names the instances and their nodes after the Contabo instance name, which is also the
+// hostname Contabo sets on the instances
+const DefaultHostnamePattern = "{instanceName}"
+
+// renderHostname returns the hostname of the instance of the machine rendered from the hostname pattern of the
+// cluster, an error when the result is not a valid RFC 1123 label
+func renderHostname(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) (string, error) {
+	pattern := contaboCluster.Spec.HostnamePattern
+	if pattern == "" {
+		pattern = DefaultHostnamePattern
+	}
+	instance := contaboMachine.Status.Instance
+	if instance == nil {
+		return "", fmt.Errorf("machine has no instance to name")
+	}
+
+	index := ""
+	if contaboMachine.Spec.Index != nil {
+		index = strconv.Itoa(int(*contaboMachine.Spec.Index))
+	}
+	hostname := strings.NewReplacer(
+		"{instanceName}", instance.Name,
+		"{instanceId}", strconv.FormatInt(instance.InstanceId, 10),
+		"{cluster}", contaboCluster.Name,
+		"{machine}", contaboMachine.Name,
+		"{role}", machineRoleName(contaboMachine),
+		"{index}", index,
+	).Replace(pattern)
+	hostname = strings.ToLower(hostname)
+
+	if errs := validation.IsDNS1123Label(hostname); len(errs) > 0 {
+		return "", fmt.Errorf("hostname %q rendered from pattern %q is invalid: %s", hostname, pattern, strings.Join(errs, ", "))
+	}
+	return hostname, nil
+}
+
+// reconcileNodeName sets the node name of the machine for the instance to be bootstrapped with. The name is rendered
+// again until the instance is bootstrapped, and kept afterwards for the node to stay the same when the hostname
+// pattern changes. Machines bootstrapped before the node name was recorded keep the instance name.
+func reconcileNodeName(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) error {
+	if meta.IsStatusConditionTrue(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceBootstrapCondition) {
+		if contaboMachine.Status.NodeName == "" && contaboMachine.Status.Instance != nil {
+			contaboMachine.Status.NodeName = strings.ToLower(contaboMachine.Status.Instance.Name)
+		}
+		return nil
+	}
+	nodeName, err := renderHostname(contaboMachine, contaboCluster)
+	if err != nil {
+		return err
+	}
+	contaboMachine.Status.NodeName = nodeName
+	return nil
+}
+
+// machineNodeName returns the name of the node of the machine, the instance name of the provider ID for the machines
+// bootstrapped before the node name was recorded
+func machineNodeName(contaboMachine *infrastructurev1beta2.ContaboMachine) (string, error) {
+	if contaboMachine.Status.NodeName != "" {
+		return contaboMachine.Status.NodeName, nil
+	}
+	if contaboMachine.Spec.ProviderID == nil {
+		return "", fmt.Errorf("machine has no provider ID")
+	}
+	return ParseProviderID(*contaboMachine.Spec.ProviderID)
+}
+
+// hostnameCloudConfig returns the cloud-config setting the hostname of the instance to the name of its node, so that
+// kubeadm and the bootstrap commands identify the node by its hostname
+func hostnameCloudConfig(nodeName string) ([]byte, error) {
+	return yaml.Marshal(map[string]interface{}{
+		"hostname":          nodeName,
+		"preserve_hostname": false,
+		"manage_etc_hosts":  true,
+	})
+}
@@ -0,0 +1,316 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const (
+	// NotificationDedupWindow is the time the same notification of an object is not sent again
+	NotificationDedupWindow = time.Hour
+
+	// NotificationSource identifies the provider in the generic notification payload
+	NotificationSource = "cluster-api-provider-contabo"
+)
+
+// CriticalEventReasons are the reasons of the Warning events forwarded to the notification sinks: orphaned
+// resources, Contabo credential failures and terminal machine failures
+var CriticalEventReasons = []string{
+	infrastructurev1beta2.InventoryOrphanedResourcesReason,
+	infrastructurev1beta2.ContaboCredentialsFailedReason,
+	infrastructurev1beta2.InstanceFailedReason,
+	infrastructurev1beta2.InstanceOrderFailedReason,
+	infrastructurev1beta2.InstanceCancelledReason,
+	infrastructurev1beta2.ProductUnavailableReason,
+	infrastructurev1beta2.ProviderIDMismatchReason,
+}
+
+// notificationHTTPClient posts the notifications to the webhook sinks
+var notificationHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// Notification is a critical provider event forwarded to the notification sinks
+type Notification struct {
+	Source    string    `json:"source"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Kind      string    `json:"kind,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// NotificationSink delivers the notifications outside of the cluster
+type NotificationSink interface {
+	// Accepts returns true when the sink forwards the notifications of the given reason
+	Accepts(reason string) bool
+	// Send delivers the notification
+	Send(ctx context.Context, notification Notification) error
+}
+
+// WebhookSink posts the notifications to an HTTP endpoint, as a JSON object or a Slack incoming webhook message
+type WebhookSink struct {
+	Name    string
+	URL     string
+	Format  infrastructurev1beta2.ContaboNotificationFormat
+	Reasons []string
+}
+
+// Accepts implements NotificationSink
+func (s *WebhookSink) Accepts(reason string) bool {
+	if len(s.Reasons) == 0 {
+		return slices.Contains(CriticalEventReasons, reason)
+	}
+	return slices.Contains(s.Reasons, reason)
+}
+
+// Send implements NotificationSink
+func (s *WebhookSink) Send(ctx context.Context, notification Notification) error {
+	payload, err := notificationPayload(notification, s.Format)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := notificationHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to post notification: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// notificationPayload renders the notification in the format of the sink
+func notificationPayload(notification Notification, format infrastructurev1beta2.ContaboNotificationFormat) ([]byte, error) {
+	if format != infrastructurev1beta2.ContaboNotificationFormatSlack {
+		return json.Marshal(notification)
+	}
+	object := notification.Kind
+	if notification.Name != "" {
+		object = fmt.Sprintf("%s %s", notification.Kind, types.NamespacedName{Namespace: notification.Namespace, Name: notification.Name})
+		if notification.Namespace == "" {
+			object = fmt.Sprintf("%s %s", notification.Kind, notification.Name)
+		}
+	}
+	text := fmt.Sprintf(":warning: *%s* %s: %s", notification.Reason, object, notification.Message)
+	return json.Marshal(map[string]string{"text": text})
+}
+
+// Notifier forwards the critical provider events to the sinks configured by flags and in the
+// ContaboProviderSettings. Notifications are sent asynchronously, the same notification of an object is sent once
+// per NotificationDedupWindow as the reconcilers report the failures on every retry.
+// A nil Notifier sends nothing.
+type Notifier struct {
+	// Reader reads the Secrets holding the URL of the sinks of the ContaboProviderSettings
+	Reader   client.Reader
+	Settings *ProviderSettings
+	// Sinks are the sinks configured by flags
+	Sinks []NotificationSink
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// NewNotifier returns a notifier forwarding to the given sinks and the sinks of the provider settings
+func NewNotifier(reader client.Reader, settings *ProviderSettings, sinks ...NotificationSink) *Notifier {
+	return &Notifier{
+		Reader:   reader,
+		Settings: settings,
+		Sinks:    sinks,
+		sent:     map[string]time.Time{},
+	}
+}
+
+// Notify sends the notification in the background when a sink accepts it, unless it was sent recently
+func (n *Notifier) Notify(notification Notification) {
+	if n == nil || !n.accepts(notification.Reason) || !n.shouldSend(notification) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*notificationHTTPClient.Timeout)
+		defer cancel()
+		_ = n.deliver(ctx, notification)
+	}()
+}
+
+// accepts returns true when a sink forwards the notifications of the given reason, without resolving the URL of the
+// sinks of the provider settings
+func (n *Notifier) accepts(reason string) bool {
+	for _, sink := range n.Sinks {
+		if sink.Accepts(reason) {
+			return true
+		}
+	}
+	for _, spec := range n.Settings.Notifications().Sinks {
+		if (&WebhookSink{Reasons: spec.Reasons}).Accepts(reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldSend records the notification and returns false when it was sent during the NotificationDedupWindow
+func (n *Notifier) shouldSend(notification Notification) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	for key, sentTime := range n.sent {
+		if now.Sub(sentTime) >= NotificationDedupWindow {
+			delete(n.sent, key)
+		}
+	}
+	key := fmt.Sprintf("%s/%s/%s/%s", notification.Reason, notification.Kind, notification.Namespace, notification.Name)
+	if _, ok := n.sent[key]; ok {
+		return false
+	}
+	if n.sent == nil {
+		n.sent = map[string]time.Time{}
+	}
+	n.sent[key] = now
+	return true
+}
+
+// deliver sends the notification to every sink accepting its reason
+func (n *Notifier) deliver(ctx context.Context, notification Notification) error {
+	log := logf.FromContext(ctx).WithName("notifications")
+
+	if notification.Source == "" {
+		notification.Source = NotificationSource
+	}
+	if notification.Time.IsZero() {
+		notification.Time = time.Now().UTC()
+	}
+
+	var errs []error
+	for _, sink := range append(slices.Clone(n.Sinks), n.settingsSinks(ctx)...) {
+		if !sink.Accepts(notification.Reason) {
+			continue
+		}
+		if err := sink.Send(ctx, notification); err != nil {
+			log.Error(err, "Failed to send notification", "sink", sinkName(sink), "reason", notification.Reason)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// settingsSinks returns the sinks of the ContaboProviderSettings, skipping the ones whose URL cannot be resolved
+func (n *Notifier) settingsSinks(ctx context.Context) []NotificationSink {
+	log := logf.FromContext(ctx).WithName("notifications")
+
+	sinks := []NotificationSink{}
+	for _, spec := range n.Settings.Notifications().Sinks {
+		url := spec.URL
+		if spec.URLSecretRef != nil {
+			if n.Reader == nil {
+				continue
+			}
+			secret := &corev1.Secret{}
+			if err := n.Reader.Get(ctx, types.NamespacedName{Namespace: spec.URLSecretRef.Namespace, Name: spec.URLSecretRef.Name}, secret); err != nil {
+				log.Error(err, "Failed to get the URL of the notification sink", "sink", spec.Name)
+				continue
+			}
+			url = string(secret.Data["url"])
+		}
+		if url == "" {
+			log.Info("Notification sink has no URL, skipping", "sink", spec.Name)
+			continue
+		}
+		sinks = append(sinks, &WebhookSink{
+			Name:    spec.Name,
+			URL:     url,
+			Format:  spec.Format,
+			Reasons: spec.Reasons,
+		})
+	}
+	return sinks
+}
+
+// sinkName returns the name of the sink for the logs
+func sinkName(sink NotificationSink) string {
+	if webhook, ok := sink.(*WebhookSink); ok {
+		return webhook.Name
+	}
+	return reflect.TypeOf(sink).String()
+}
+
+// Recorder returns an event recorder forwarding the critical Warning events to the notifier
+func (n *Notifier) Recorder(recorder record.EventRecorder) record.EventRecorder {
+	if n == nil {
+		return recorder
+	}
+	return &notifyingRecorder{EventRecorder: recorder, notifier: n}
+}
+
+// notifyingRecorder records the events and forwards the critical Warning events to the notifier
+type notifyingRecorder struct {
+	record.EventRecorder
+	notifier *Notifier
+}
+
+// Event implements record.EventRecorder
+func (r *notifyingRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.notify(object, eventtype, reason, message)
+}
+
+// Eventf implements record.EventRecorder
+func (r *notifyingRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	r.notify(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf implements record.EventRecorder
+func (r *notifyingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	r.notify(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *notifyingRecorder) notify(object runtime.Object, eventtype, reason, message string) {
+	if eventtype != corev1.EventTypeWarning {
+		return
+	}
+	r.notifier.Notify(eventNotification(object, reason, message))
+}
+
+// eventNotification returns the notification of an event of the given object
+func eventNotification(object runtime.Object, reason, message string) Notification {
+	notification := Notification{
+		Reason:  reason,
+		Message: message,
+		Kind:    object.GetObjectKind().GroupVersionKind().Kind,
+	}
+	if notification.Kind == "" {
+		// Objects read with a typed client have no type meta
+		notification.Kind = reflect.Indirect(reflect.ValueOf(object)).Type().Name()
+	}
+	if accessor, err := meta.Accessor(object); err == nil {
+		notification.Namespace = accessor.GetNamespace()
+		notification.Name = accessor.GetName()
+	}
+	return notification
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// OperationSlotReservation is the time a started instance operation holds its slot before it shows up in the status
+// of the ContaboMachine
+const OperationSlotReservation = 2 * time.Minute
+
+// operationSlots holds the instance operations started by the manager until they show up in the ContaboMachine
+// status, concurrent reconciliations would otherwise all find a free slot in the cache
+type operationSlots struct {
+	mu       sync.Mutex
+	reserved map[types.NamespacedName]time.Time
+}
+
+// instanceOperationInProgress returns true while an instance creation or reinstallation of the machine is running,
+// from the request to the end of the bootstrap
+func instanceOperationInProgress(contaboMachine *infrastructurev1beta2.ContaboMachine) bool {
+	if !contaboMachine.DeletionTimestamp.IsZero() || contaboMachine.Status.FailureReason != nil {
+		return false
+	}
+	if order := contaboMachine.Status.InstanceOrder; order != nil && order.InstanceId != 0 {
+		return true
+	}
+	if instance := contaboMachine.Status.Instance; instance != nil &&
+		(instance.Status == infrastructurev1beta2.InstanceStatusProvisioning || instance.Status == infrastructurev1beta2.InstanceStatusInstalling) {
+		return true
+	}
+	bootstrap := meta.FindStatusCondition(contaboMachine.Status.Conditions, infrastructurev1beta2.InstanceBootstrapCondition)
+	return bootstrap != nil && bootstrap.Status == metav1.ConditionFalse &&
+		(bootstrap.Reason == infrastructurev1beta2.InstanceReinstallingReason || bootstrap.Reason == infrastructurev1beta2.InstanceWaitingForCloudInitReason)
+}
+
+// runningOperations returns the names of the machines, other than self, running an instance operation or holding a
+// reservation younger than OperationSlotReservation
+func runningOperations(contaboMachines []infrastructurev1beta2.ContaboMachine, self types.NamespacedName, reserved map[types.NamespacedName]time.Time, now time.Time) []string {
+	running := []string{}
+	for i := range contaboMachines {
+		contaboMachine := &contaboMachines[i]
+		key := client.ObjectKeyFromObject(contaboMachine)
+		if key == self {
+			continue
+		}
+		reservedAt, ok := reserved[key]
+		if instanceOperationInProgress(contaboMachine) || (ok && now.Sub(reservedAt) < OperationSlotReservation) {
+			running = append(running, contaboMachine.Name)
+		}
+	}
+	slices.Sort(running)
+	return running
+}
+
+// acquireOperationSlot reserves a slot for an instance operation of the ContaboMachine within the
+// MaxConcurrentOperations of the ContaboCluster. It returns a non-nil error describing the running operations if the
+// operation must wait.
+func (r *ContaboMachineReconciler) acquireOperationSlot(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) error {
+	limit := contaboCluster.Spec.MaxConcurrentOperations
+	if limit == nil {
+		return nil
+	}
+
+	contaboMachineList := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachineList, client.InNamespace(contaboCluster.Namespace), client.MatchingLabels{
+		clusterv1.ClusterNameLabel: contaboCluster.Name,
+	}); err != nil {
+		return fmt.Errorf("failed to list ContaboMachines: %w", err)
+	}
+
+	slots := &r.operationSlots
+	slots.mu.Lock()
+	defer slots.mu.Unlock()
+
+	now := time.Now()
+	if slots.reserved == nil {
+		slots.reserved = map[types.NamespacedName]time.Time{}
+	}
+	for key, reservedAt := range slots.reserved {
+		if now.Sub(reservedAt) >= OperationSlotReservation {
+			delete(slots.reserved, key)
+		}
+	}
+
+	self := client.ObjectKeyFromObject(contaboMachine)
+	running := runningOperations(contaboMachineList.Items, self, slots.reserved, now)
+	if len(running) >= int(*limit) {
+		return fmt.Errorf("%d/%d instance operations in progress for ContaboCluster %s: %s", len(running), *limit, contaboCluster.Name, strings.Join(running, ", "))
+	}
+	slots.reserved[self] = now
+	return nil
+}
+
+// releaseOperationSlot gives back the slot of an instance operation which could not be started
+func (r *ContaboMachineReconciler) releaseOperationSlot(contaboMachine *infrastructurev1beta2.ContaboMachine) {
+	slots := &r.operationSlots
+	slots.mu.Lock()
+	defer slots.mu.Unlock()
+	delete(slots.reserved, client.ObjectKeyFromObject(contaboMachine))
+}
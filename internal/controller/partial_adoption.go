@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"k8s.io/utils/ptr"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// providerTag returns the provider tag of a partially adopted cluster, empty when the provider manages every instance
+func providerTag(contaboCluster *infrastructurev1beta2.ContaboCluster) string {
+	if contaboCluster == nil || contaboCluster.Spec.PartialAdoption == nil {
+		return ""
+	}
+	return contaboCluster.Spec.PartialAdoption.ProviderTag
+}
+
+// managedInstances returns the instances carrying the provider tag of a partially adopted cluster. It returns nil
+// when the cluster is not partially adopted, every instance is then managed.
+func managedInstances(ctx context.Context, contaboClient *contaboclient.ClientWithResponses, contaboCluster *infrastructurev1beta2.ContaboCluster) (map[int64]bool, error) {
+	tag := providerTag(contaboCluster)
+	if tag == "" {
+		return nil, nil
+	}
+
+	managed := map[int64]bool{}
+	tagIds, err := findTagIds(ctx, contaboClient, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find provider tag %q: %w", tag, err)
+	}
+	for _, tagId := range tagIds {
+		for page := int64(1); ; page++ {
+			resp, err := contaboClient.RetrieveAssignmentListWithResponse(ctx, tagId, &models.RetrieveAssignmentListParams{
+				Page:         &page,
+				Size:         ptr.To(int64(tagPageSize)),
+				ResourceType: ptr.To(tagResourceTypeInstance),
+			})
+			if err := contabo.CheckResponse(resp, err); errors.Is(err, contabo.ErrNotFound) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to list the assignments of provider tag %q: %w", tag, err)
+			}
+			for _, assignment := range resp.JSON200.Data {
+				if assignment.ResourceType != tagResourceTypeInstance {
+					continue
+				}
+				if instanceId, err := strconv.ParseInt(assignment.ResourceId, 10, 64); err == nil {
+					managed[instanceId] = true
+				}
+			}
+			if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+				break
+			}
+		}
+	}
+	return managed, nil
+}
+
+// ignoresInstance returns true when the instance does not carry the provider tag of a partially adopted cluster,
+// managed being the result of managedInstances
+func ignoresInstance(managed map[int64]bool, instanceId int64) bool {
+	return managed != nil && !managed[instanceId]
+}
+
+// findTagIds returns the IDs of the tags with the exact name, none when the tag does not exist
+func findTagIds(ctx context.Context, contaboClient *contaboclient.ClientWithResponses, name string) ([]int64, error) {
+	tagIds := []int64{}
+	// The name filter is a substring match
+	for page := int64(1); ; page++ {
+		resp, err := contaboClient.RetrieveTagListWithResponse(ctx, &models.RetrieveTagListParams{
+			Page: &page,
+			Size: ptr.To(int64(tagPageSize)),
+			Name: &name,
+		})
+		if err := contabo.CheckResponse(resp, err); err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+		for _, tag := range resp.JSON200.Data {
+			if tag.Name == name {
+				tagIds = append(tagIds, tag.TagId)
+			}
+		}
+		if page >= int64(resp.JSON200.UnderscorePagination.TotalPages) {
+			return tagIds, nil
+		}
+	}
+}
+
+// ignoredPrivateNetworkInstances returns the names of the instances of the private network without the provider tag
+// of a partially adopted cluster
+func (r *ContaboClusterReconciler) ignoredPrivateNetworkInstances(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, instances []models.Instances) ([]string, error) {
+	managed, err := managedInstances(ctx, r.ContaboClient, contaboCluster)
+	if err != nil {
+		return nil, err
+	}
+	ignored := []string{}
+	for _, instance := range instances {
+		if ignoresInstance(managed, instance.InstanceId) {
+			ignored = append(ignored, instance.Name)
+		}
+	}
+	return ignored, nil
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// PrivateOnlyProxyEnvFile holds the proxy environment of private-only instances, read by containerd and the
+// bootstrap commands
+const PrivateOnlyProxyEnvFile = "/etc/capc/proxy.env"
+
+// machineInstanceSshHost returns the address the controller connects to over SSH, the private IPv4 of private-only
+// machines and the public IPv4 otherwise. It is empty until the address is known.
+func machineInstanceSshHost(contaboMachine *infrastructurev1beta2.ContaboMachine) string {
+	if contaboMachine.Spec.PrivateOnly == nil {
+		return contaboMachine.Status.Instance.IpConfig.V4.Ip
+	}
+	for _, address := range contaboMachine.Status.Addresses {
+		if address.Type == clusterv1.MachineInternalIP {
+			return address.Address
+		}
+	}
+	return ""
+}
+
+// dialBastion connects to the SSH bastion of a private-only machine, with the bastion key when set and the machine
+// key otherwise
+func (r *ContaboMachineReconciler) dialBastion(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, machineSigner ssh.Signer) (*ssh.Client, error) {
+	bastion := contaboMachine.Spec.PrivateOnly.Bastion
+
+	signer := machineSigner
+	if bastion.SshKeySecretName != "" {
+		secret := &corev1.Secret{}
+		key := client.ObjectKey{Namespace: contaboMachine.Namespace, Name: bastion.SshKeySecretName}
+		if err := r.Get(ctx, key, secret); err != nil {
+			return nil, fmt.Errorf("failed to get bastion SSH key secret %s/%s: %w", key.Namespace, key.Name, err)
+		}
+		privateKey := secret.Data[SSHPrivateKeyKey]
+		if len(privateKey) == 0 {
+			privateKey = secret.Data[LegacySSHPrivateKeyKey]
+		}
+		if len(privateKey) == 0 {
+			return nil, fmt.Errorf("bastion SSH key secret %s/%s is missing '%s' or '%s' key or is empty", key.Namespace, key.Name, SSHPrivateKeyKey, LegacySSHPrivateKeyKey)
+		}
+		var err error
+		if signer, err = ssh.ParsePrivateKey(privateKey); err != nil {
+			return nil, fmt.Errorf("failed to parse bastion SSH private key: %w", err)
+		}
+	}
+
+	port := bastion.Port
+	if port == 0 {
+		port = 22
+	}
+	user := bastion.User
+	if user == "" {
+		user = "root"
+	}
+	return ssh.Dial("tcp", net.JoinHostPort(bastion.Host, strconv.Itoa(int(port))), &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         r.Settings.SshDialTimeout(),
+	})
+}
+
+// dialThroughBastion opens an SSH connection to the address through the bastion
+func dialThroughBastion(bastion *ssh.Client, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	conn, err := bastion.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(clientConn, channels, requests), nil
+}
+
+// privateOnlyCloudConfig returns the cloud-config routing the egress of a private-only machine through the NAT
+// gateway and its packages, downloads and image pulls through the proxy, nil when the machine is not private-only.
+// The route is set with bootcmd to apply at every boot before the packages are installed, the proxy environment is
+// loaded by the first command so that the rest of the commands use it.
+// The ${INTERNAL_IPV4} and ${INTERNAL_IPV4_CIDR} variables are replaced with the rest of the cloud-config.
+func privateOnlyCloudConfig(contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) ([]byte, error) {
+	privateOnly := contaboMachine.Spec.PrivateOnly
+	if privateOnly == nil || (privateOnly.NATGateway == "" && privateOnly.Proxy == nil) {
+		return nil, nil
+	}
+
+	cloudConfig := map[string]interface{}{}
+	if privateOnly.NATGateway != "" {
+		cloudConfig["bootcmd"] = []interface{}{
+			strings.Join([]string{
+				`iface=$(ip -o -4 addr show | awk -v ip="${INTERNAL_IPV4}" 'index($4, ip "/") == 1 {print $2; exit}')`,
+				`if [ -n "$iface" ]; then ip route replace default via ` + privateOnly.NATGateway + ` dev "$iface"; else echo "[CAPC] Error: private network interface not found for ${INTERNAL_IPV4}"; fi`,
+			}, "\n"),
+		}
+	}
+
+	if proxy := privateOnly.Proxy; proxy != nil {
+		noProxy := []string{"localhost", "127.0.0.1", "${INTERNAL_IPV4_CIDR}", ".svc", ".cluster.local"}
+		if host := contaboCluster.Spec.ControlPlaneEndpoint.Host; host != "" {
+			noProxy = append(noProxy, host)
+		}
+		noProxy = append(noProxy, proxy.NoProxy...)
+
+		env := []string{}
+		apt := []string{}
+		if proxy.HTTPProxy != "" {
+			env = append(env, "HTTP_PROXY="+proxy.HTTPProxy, "http_proxy="+proxy.HTTPProxy)
+			apt = append(apt, fmt.Sprintf("Acquire::http::Proxy %q;", proxy.HTTPProxy))
+		}
+		if proxy.HTTPSProxy != "" {
+			env = append(env, "HTTPS_PROXY="+proxy.HTTPSProxy, "https_proxy="+proxy.HTTPSProxy)
+			apt = append(apt, fmt.Sprintf("Acquire::https::Proxy %q;", proxy.HTTPSProxy))
+		}
+		env = append(env, "NO_PROXY="+strings.Join(noProxy, ","), "no_proxy="+strings.Join(noProxy, ","))
+
+		cloudConfig["write_files"] = []interface{}{
+			map[string]interface{}{
+				"path":        PrivateOnlyProxyEnvFile,
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     strings.Join(env, "\n") + "\n",
+			},
+			map[string]interface{}{
+				"path":        "/etc/apt/apt.conf.d/90capc-proxy",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     strings.Join(apt, "\n") + "\n",
+			},
+			map[string]interface{}{
+				"path":        "/etc/systemd/system/containerd.service.d/capc-proxy.conf",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     "[Service]\nEnvironmentFile=" + PrivateOnlyProxyEnvFile + "\n",
+			},
+		}
+		// cloud-init runs the commands in a single script, the exported variables apply to the next commands
+		cloudConfig["runcmd"] = []interface{}{
+			"set -a; . " + PrivateOnlyProxyEnvFile + "; set +a",
+		}
+	}
+
+	return yaml.Marshal(cloudConfig)
+}
@@ -0,0 +1,289 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// privateNetworkUnassignAttempts is the number of times the unassignment of an instance is sent until the
+	// private network no longer lists it
+	privateNetworkUnassignAttempts = 3
+
+	// privateNetworkUnassignDelay is the time given to Contabo to process an unassignment before verifying it
+	privateNetworkUnassignDelay = time.Second
+
+	// DefaultPrivateNetworkAssignWorkers is the number of private network assignments sent at once when not configured
+	DefaultPrivateNetworkAssignWorkers = 4
+
+	// privateNetworkAssignAttempts is the number of times an assignment refused because the private network processes
+	// another one is sent
+	privateNetworkAssignAttempts = 4
+
+	// privateNetworkAssignRetryDelay is the delay before sending again an assignment refused because the private
+	// network processes another one, doubled for each following attempt
+	privateNetworkAssignRetryDelay = time.Second
+
+	// privateNetworkAssignWait bounds the wait of a reconciliation for the turn of its assignment, it requeues after
+	privateNetworkAssignWait = time.Minute
+)
+
+// privateNetworkAssignments serializes the private network assignments of the instances: Contabo refuses an
+// assignment while it processes another one of the same private network, which fails the attachments when many
+// machines are created at once. A single assignment per private network is sent at a time, by a bounded number of
+// workers across the private networks, and the waiting assignments of a private network are sent in the order of
+// their instance IDs so that the instances get their private IPs in a deterministic order.
+type privateNetworkAssignments struct {
+	mu      sync.Mutex
+	running int
+	busy    map[int64]bool
+	waiting map[int64][]int64
+	// changed is closed and replaced whenever an assignment ends or stops waiting
+	changed chan struct{}
+}
+
+// acquire waits for the turn of the assignment of the instance to the private network among the workers, and returns
+// the function releasing it once the assignment is sent. It returns the error of the context when it is done first.
+func (a *privateNetworkAssignments) acquire(ctx context.Context, workers int, privateNetworkId int64, instanceId int64) (func(), error) {
+	if workers <= 0 {
+		workers = DefaultPrivateNetworkAssignWorkers
+	}
+
+	a.mu.Lock()
+	if a.changed == nil {
+		a.busy = map[int64]bool{}
+		a.waiting = map[int64][]int64{}
+		a.changed = make(chan struct{})
+	}
+	index, _ := slices.BinarySearch(a.waiting[privateNetworkId], instanceId)
+	a.waiting[privateNetworkId] = slices.Insert(a.waiting[privateNetworkId], index, instanceId)
+
+	for {
+		if waiting := a.waiting[privateNetworkId]; !a.busy[privateNetworkId] && a.running < workers && waiting[0] == instanceId {
+			a.dequeue(privateNetworkId, 0)
+			a.busy[privateNetworkId] = true
+			a.running++
+			a.mu.Unlock()
+			return func() {
+				a.mu.Lock()
+				defer a.mu.Unlock()
+				delete(a.busy, privateNetworkId)
+				a.running--
+				a.notify()
+			}, nil
+		}
+
+		changed := a.changed
+		a.mu.Unlock()
+		select {
+		case <-changed:
+			a.mu.Lock()
+		case <-ctx.Done():
+			a.mu.Lock()
+			a.dequeue(privateNetworkId, slices.Index(a.waiting[privateNetworkId], instanceId))
+			a.notify()
+			a.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// dequeue removes the waiting assignment at the index from the private network, a.mu must be held
+func (a *privateNetworkAssignments) dequeue(privateNetworkId int64, index int) {
+	waiting := slices.Delete(a.waiting[privateNetworkId], index, index+1)
+	if len(waiting) == 0 {
+		delete(a.waiting, privateNetworkId)
+		return
+	}
+	a.waiting[privateNetworkId] = waiting
+}
+
+// notify wakes up the waiting assignments, a.mu must be held
+func (a *privateNetworkAssignments) notify() {
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+// assignPrivateNetwork assigns the instance to the private network, sending the assignment again with a backoff while
+// Contabo refuses it with 409 Conflict because it processes another assignment of the private network
+func assignPrivateNetwork(ctx context.Context, contaboClient *contaboclient.ClientWithResponses, privateNetworkId int64, instanceId int64) error {
+	log := logf.FromContext(ctx)
+
+	delay := privateNetworkAssignRetryDelay
+	for attempt := 1; ; attempt++ {
+		assignResp, err := contaboClient.AssignInstancePrivateNetworkWithResponse(ctx, privateNetworkId, instanceId, nil)
+		err = contabo.CheckResponse(assignResp, err)
+		if !errors.Is(err, contabo.ErrConflict) || attempt >= privateNetworkAssignAttempts {
+			return err
+		}
+
+		wait := delay
+		var apiErr *contabo.APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+		log.Info("Private network busy with another assignment, retrying", "instanceID", instanceId, "privateNetworkID", privateNetworkId, "attempt", attempt, "delay", wait, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// privateNetworkHasInstance returns true when the private network lists the instance
+func privateNetworkHasInstance(privateNetwork *models.PrivateNetworkResponse, instanceId int64) bool {
+	for _, instance := range privateNetwork.Instances {
+		if instance.InstanceId == instanceId {
+			return true
+		}
+	}
+	return false
+}
+
+// unassignPrivateNetwork removes the instance from the private network and verifies that Contabo no longer lists it,
+// sending the unassignment again when the instance is still listed
+func unassignPrivateNetwork(ctx context.Context, contaboClient *contaboclient.ClientWithResponses, privateNetworkId int64, instanceId int64) error {
+	log := logf.FromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		unassignResp, err := contaboClient.UnassignInstancePrivateNetworkWithResponse(ctx, privateNetworkId, instanceId, nil)
+		if err := contabo.CheckResponse(unassignResp, err); err != nil && !errors.Is(err, contabo.ErrNotFound) {
+			log.Info("Failed to unassign private network from instance", "instanceID", instanceId, "networkID", privateNetworkId, "attempt", attempt, "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(privateNetworkUnassignDelay):
+		}
+
+		privateNetworkResp, err := contaboClient.RetrievePrivateNetworkWithResponse(ctx, privateNetworkId, nil)
+		if err := contabo.CheckResponse(privateNetworkResp, err); errors.Is(err, contabo.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to verify unassignment of instance %d from private network %d: %w", instanceId, privateNetworkId, err)
+		}
+		if len(privateNetworkResp.JSON200.Data) == 0 {
+			return fmt.Errorf("failed to verify unassignment of instance %d from private network %d: not returned by the Contabo API", instanceId, privateNetworkId)
+		}
+		if !privateNetworkHasInstance(&privateNetworkResp.JSON200.Data[0], instanceId) {
+			return nil
+		}
+		if attempt >= privateNetworkUnassignAttempts {
+			return fmt.Errorf("instance %d still assigned to private network %d after %d unassignments", instanceId, privateNetworkId, attempt)
+		}
+	}
+}
+
+// stalePrivateNetworkInstances returns the instances of the private network released to the reuse pool, with an
+// empty display name, and held by no ContaboMachine. Instances named by the provider or by the user are kept, the
+// ones marked with an error are kept on purpose for investigation, and the ones without the provider tag of a
+// partially adopted cluster are ignored.
+func stalePrivateNetworkInstances(instances []models.Instances, heldInstances map[int64]bool, managed map[int64]bool) []models.Instances {
+	stale := []models.Instances{}
+	for _, instance := range instances {
+		if instance.DisplayName != "" || heldInstances[instance.InstanceId] || ignoresInstance(managed, instance.InstanceId) {
+			continue
+		}
+		stale = append(stale, instance)
+	}
+	return stale
+}
+
+// convertPrivateNetworkInstances converts the instances of a private network to the status of the ContaboCluster
+func convertPrivateNetworkInstances(instances []models.Instances) []infrastructurev1beta2.Instances {
+	converted := make([]infrastructurev1beta2.Instances, 0, len(instances))
+	for _, instance := range instances {
+		privateIpConfig := infrastructurev1beta2.PrivateIpConfig{V4: make([]infrastructurev1beta2.IpV4, 0, len(instance.PrivateIpConfig.V4))}
+		for _, v4 := range instance.PrivateIpConfig.V4 {
+			privateIpConfig.V4 = append(privateIpConfig.V4, infrastructurev1beta2.IpV4{
+				Gateway:     v4.Gateway,
+				Ip:          v4.Ip,
+				NetmaskCidr: v4.NetmaskCidr,
+			})
+		}
+		converted = append(converted, infrastructurev1beta2.Instances{
+			DisplayName:  instance.DisplayName,
+			ErrorMessage: instance.ErrorMessage,
+			InstanceId:   instance.InstanceId,
+			IpConfig: infrastructurev1beta2.IpConfig{
+				V4: infrastructurev1beta2.IpV4{
+					Gateway:     instance.IpConfig.V4.Gateway,
+					Ip:          instance.IpConfig.V4.Ip,
+					NetmaskCidr: instance.IpConfig.V4.NetmaskCidr,
+				},
+				V6: infrastructurev1beta2.IpV6{
+					Gateway:     instance.IpConfig.V6.Gateway,
+					Ip:          instance.IpConfig.V6.Ip,
+					NetmaskCidr: instance.IpConfig.V6.NetmaskCidr,
+				},
+			},
+			Name:            instance.Name,
+			PrivateIpConfig: privateIpConfig,
+			ProductId:       instance.ProductId,
+			Status:          infrastructurev1beta2.InstancesStatus(instance.Status),
+		})
+	}
+	return converted
+}
+
+// reconcilePrivateNetworkAssignments removes the instances left in the private network after their machine was
+// deleted or their instance released, e.g. when the unassignment failed or Contabo did not apply it, and returns the
+// remaining instances of the private network.
+// Failures are only logged, the cleanup is retried on the next reconciliation.
+func (r *ContaboClusterReconciler) reconcilePrivateNetworkAssignments(ctx context.Context, contaboCluster *infrastructurev1beta2.ContaboCluster, privateNetworkId int64, privateNetworkName string, instances []models.Instances) []models.Instances {
+	log := logf.FromContext(ctx)
+
+	// Private networks can be shared across namespaces, the instances of all ContaboMachines are held
+	contaboMachineList := &infrastructurev1beta2.ContaboMachineList{}
+	if err := r.List(ctx, contaboMachineList); err != nil {
+		log.Error(err, "Failed to list ContaboMachines to clean up the private network assignments")
+		return instances
+	}
+	heldInstances := map[int64]bool{}
+	for _, contaboMachine := range contaboMachineList.Items {
+		if contaboMachine.Status.Instance != nil {
+			heldInstances[contaboMachine.Status.Instance.InstanceId] = true
+		}
+	}
+
+	managed, err := managedInstances(ctx, r.ContaboClient, contaboCluster)
+	if err != nil {
+		log.Error(err, "Failed to list the instances of the partially adopted cluster to clean up the private network assignments")
+		return instances
+	}
+
+	removed := map[int64]bool{}
+	for _, instance := range stalePrivateNetworkInstances(instances, heldInstances, managed) {
+		log.Info("Removing stale private network assignment", "instanceID", instance.InstanceId, "privateNetworkId", privateNetworkId)
+		if err := unassignPrivateNetwork(ctx, r.ContaboClient, privateNetworkId, instance.InstanceId); err != nil {
+			log.Error(err, "Failed to remove stale private network assignment", "instanceID", instance.InstanceId, "privateNetworkId", privateNetworkId)
+			continue
+		}
+		removed[instance.InstanceId] = true
+		r.Recorder.Eventf(contaboCluster, corev1.EventTypeNormal, infrastructurev1beta2.ClusterPrivateNetworkStaleAssignmentRemovedReason,
+			"Removed instance %d (%s) left in private network %s", instance.InstanceId, instance.Name, privateNetworkName)
+	}
+
+	remaining := []models.Instances{}
+	for _, instance := range instances {
+		if !removed[instance.InstanceId] {
+			remaining = append(remaining, instance)
+		}
+	}
+	return remaining
+}
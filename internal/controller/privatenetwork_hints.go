@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// DefaultCNIEncapsulationOverhead is subtracted from the private network MTU to compute the CNI MTU, the VXLAN overhead
+	DefaultCNIEncapsulationOverhead = 50
+
+	// PrivateNetworkEnvFile is the file of the instances holding the private network MTU and routing details
+	PrivateNetworkEnvFile = "/etc/capc/private-network.env"
+
+	// privateNetworkMTUCommand prints the interface holding the internal IP and its MTU
+	privateNetworkMTUCommand = `iface=$(ip -o -4 addr show | awk -v ip="%s" 'index($4, ip "/") == 1 {print $2; exit}'); [ -n "$iface" ] && echo "$iface $(cat /sys/class/net/$iface/mtu)"`
+)
+
+// privateNetworkGateway returns the gateway of the private network reported for its instances
+func privateNetworkGateway(instances []models.Instances) string {
+	for _, instance := range instances {
+		for _, v4 := range instance.PrivateIpConfig.V4 {
+			if v4.Gateway != "" {
+				return v4.Gateway
+			}
+		}
+	}
+	return ""
+}
+
+// updatePrivateNetworkHints computes the effective MTU and the CNI MTU of the private network from the spec and
+// the detected MTU
+func updatePrivateNetworkHints(contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	hints := contaboCluster.Status.PrivateNetworkHints
+	if hints == nil {
+		hints = &infrastructurev1beta2.ContaboPrivateNetworkHintsStatus{}
+		contaboCluster.Status.PrivateNetworkHints = hints
+	}
+
+	hints.MTU = ptr.Deref(contaboCluster.Spec.PrivateNetwork.MTU, hints.DetectedMTU)
+	hints.CNIMTU = 0
+	if hints.MTU > 0 {
+		overhead := int32(DefaultCNIEncapsulationOverhead)
+		if cni := contaboCluster.Spec.PrivateNetwork.CNI; cni != nil && cni.EncapsulationOverhead != nil {
+			overhead = *cni.EncapsulationOverhead
+		}
+		hints.CNIMTU = hints.MTU - overhead
+	}
+}
+
+// parsePrivateNetworkMTUOutput parses the interface and MTU printed by the privateNetworkMTUCommand
+func parsePrivateNetworkMTUOutput(output string) (string, int32, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return "", 0, fmt.Errorf("unexpected private network MTU output %q", Truncate(strings.TrimSpace(output), 256))
+	}
+	mtu, err := strconv.ParseInt(fields[1], 10, 32)
+	if err != nil || mtu <= 0 {
+		return "", 0, fmt.Errorf("invalid private network MTU %q", fields[1])
+	}
+	return fields[0], int32(mtu), nil
+}
+
+// detectPrivateNetworkMTU reads the MTU of the private network interface of the instance, once per cluster,
+// and records it in the ContaboCluster status. Failures are logged and retried on the next bootstrapped machine.
+func (r *ContaboMachineReconciler) detectPrivateNetworkMTU(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster) {
+	log := logf.FromContext(ctx)
+
+	if hints := contaboCluster.Status.PrivateNetworkHints; hints != nil && hints.DetectedMTU > 0 {
+		return
+	}
+	internalIPv4 := ""
+	for _, address := range contaboMachine.Status.Addresses {
+		if address.Type == clusterv1.MachineInternalIP {
+			internalIPv4 = address.Address
+			break
+		}
+	}
+	if internalIPv4 == "" {
+		return
+	}
+
+	output, result, err := r.runMachineInstanceSshCommand(ctx, contaboMachine, contaboCluster, fmt.Sprintf(privateNetworkMTUCommand, internalIPv4))
+	if err != nil || result.RequeueAfter > 0 {
+		log.Info("Unable to detect the private network MTU, will retry on the next machine", "error", err)
+		return
+	}
+	iface, mtu, err := parsePrivateNetworkMTUOutput(output)
+	if err != nil {
+		log.Info("Unable to detect the private network MTU, will retry on the next machine", "error", err.Error())
+		return
+	}
+
+	original := contaboCluster.DeepCopy()
+	if contaboCluster.Status.PrivateNetworkHints == nil {
+		contaboCluster.Status.PrivateNetworkHints = &infrastructurev1beta2.ContaboPrivateNetworkHintsStatus{}
+	}
+	contaboCluster.Status.PrivateNetworkHints.DetectedMTU = mtu
+	contaboCluster.Status.PrivateNetworkHints.Interface = iface
+	contaboCluster.Status.PrivateNetworkHints.DetectedFrom = contaboMachine.Name
+	updatePrivateNetworkHints(contaboCluster)
+	if err := r.Status().Patch(ctx, contaboCluster, client.MergeFrom(original)); err != nil {
+		log.Error(err, "Failed to record the private network MTU on the ContaboCluster")
+		return
+	}
+	log.Info("Detected private network MTU", "interface", iface, "mtu", mtu)
+}
+
+// privateNetworkCloudConfig returns the cloud-config setting the private network interface MTU and rendering the
+// private network details in the PrivateNetworkEnvFile at every boot, nil when not configured on the cluster.
+// The ${INTERNAL_IPV4} and ${INTERNAL_IPV4_CIDR} variables are replaced with the rest of the cloud-config.
+func privateNetworkCloudConfig(contaboCluster *infrastructurev1beta2.ContaboCluster) ([]byte, error) {
+	spec := contaboCluster.Spec.PrivateNetwork
+	if spec.MTU == nil && spec.CNI == nil {
+		return nil, nil
+	}
+
+	overhead := int32(DefaultCNIEncapsulationOverhead)
+	if spec.CNI != nil && spec.CNI.EncapsulationOverhead != nil {
+		overhead = *spec.CNI.EncapsulationOverhead
+	}
+	mtu := ""
+	if spec.MTU != nil {
+		mtu = strconv.Itoa(int(*spec.MTU))
+	}
+	gateway := ""
+	if contaboCluster.Status.PrivateNetworkHints != nil {
+		gateway = contaboCluster.Status.PrivateNetworkHints.Gateway
+	}
+
+	script := strings.Join([]string{
+		"#!/bin/sh",
+		`iface=$(ip -o -4 addr show | awk -v ip="${INTERNAL_IPV4}" 'index($4, ip "/") == 1 {print $2; exit}')`,
+		`[ -n "$iface" ] || { echo "[CAPC] Error: private network interface not found for ${INTERNAL_IPV4}"; exit 1; }`,
+		fmt.Sprintf(`mtu="%s"`, mtu),
+		`if [ -n "$mtu" ]; then ip link set dev "$iface" mtu "$mtu"; else mtu=$(cat /sys/class/net/$iface/mtu); fi`,
+		"mkdir -p /etc/capc",
+		"cat > " + PrivateNetworkEnvFile + " <<EOF",
+		"PRIVATE_NETWORK_INTERFACE=$iface",
+		"PRIVATE_NETWORK_MTU=$mtu",
+		"PRIVATE_NETWORK_CIDR=${INTERNAL_IPV4_CIDR}",
+		"PRIVATE_NETWORK_GATEWAY=" + gateway,
+		fmt.Sprintf("CNI_MTU=$((mtu - %d))", overhead),
+		"EOF",
+	}, "\n")
+	service := strings.Join([]string{
+		"[Unit]",
+		"Description=Configure the Contabo private network MTU",
+		"After=network-online.target",
+		"Wants=network-online.target",
+		"",
+		"[Service]",
+		"Type=oneshot",
+		"ExecStart=/usr/local/bin/contabo-private-network-mtu.sh",
+		"",
+		"[Install]",
+		"WantedBy=multi-user.target",
+	}, "\n")
+
+	return yaml.Marshal(map[string]interface{}{
+		"write_files": []interface{}{
+			map[string]interface{}{
+				"path":        "/usr/local/bin/contabo-private-network-mtu.sh",
+				"owner":       "root:root",
+				"permissions": "0755",
+				"content":     script,
+			},
+			map[string]interface{}{
+				"path":        "/etc/systemd/system/contabo-private-network-mtu.service",
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     service,
+			},
+		},
+		"runcmd": []interface{}{
+			"systemctl daemon-reload && systemctl enable contabo-private-network-mtu.service && systemctl start contabo-private-network-mtu.service",
+		},
+	})
+}
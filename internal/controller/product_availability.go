@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// ErrProductUnavailable is returned when Contabo refuses to create an instance because its product is end-of-sale or unavailable
+var ErrProductUnavailable = errors.New("contabo product unavailable")
+
+// productUnavailableHints are the error message fragments returned by the Contabo API for discontinued products
+var productUnavailableHints = []string{
+	"not available",
+	"unavailable",
+	"discontinued",
+	"end of sale",
+	"end-of-sale",
+	"not orderable",
+	"invalid product",
+	"unknown product",
+}
+
+// isProductUnavailableResponse detects create instance failures caused by an end-of-sale or unavailable product
+func isProductUnavailableResponse(statusCode int, body []byte) bool {
+	if statusCode < 400 || statusCode >= 500 || statusCode == 401 || statusCode == 403 || statusCode == 429 {
+		return false
+	}
+	message := strings.ToLower(string(body))
+	if !strings.Contains(message, "product") {
+		return false
+	}
+	for _, hint := range productUnavailableHints {
+		if strings.Contains(message, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportProductAvailability updates the ProductAvailable condition of the ContaboMachineTemplates using the product
+// and the unavailable products of the ContaboCluster, prompting users to update their templates.
+func (r *ContaboMachineReconciler) reportProductAvailability(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine, contaboCluster *infrastructurev1beta2.ContaboCluster, productId string, available bool, message string) {
+	log := logf.FromContext(ctx)
+
+	if productId == "" {
+		return
+	}
+
+	// Templates of the namespace using the product
+	templateList := &infrastructurev1beta2.ContaboMachineTemplateList{}
+	if err := r.List(ctx, templateList, client.InNamespace(contaboMachine.Namespace)); err != nil {
+		log.Error(err, "Failed to list ContaboMachineTemplates to report product availability")
+	} else {
+		for i := range templateList.Items {
+			template := &templateList.Items[i]
+			if template.Spec.Template.Spec.Instance.ProductId == nil || string(*template.Spec.Template.Spec.Instance.ProductId) != productId {
+				continue
+			}
+			condition := metav1.Condition{
+				Type:    infrastructurev1beta2.ProductAvailableCondition,
+				Status:  metav1.ConditionTrue,
+				Reason:  infrastructurev1beta2.ProductAvailableReason,
+				Message: message,
+			}
+			if !available {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = infrastructurev1beta2.ProductUnavailableReason
+			}
+			current := meta.FindStatusCondition(template.Status.Conditions, condition.Type)
+			if current != nil && current.Status == condition.Status {
+				continue
+			}
+			// Only report availability on templates previously marked unavailable
+			if current == nil && available {
+				continue
+			}
+			original := template.DeepCopy()
+			meta.SetStatusCondition(&template.Status.Conditions, condition)
+			if err := r.Status().Patch(ctx, template, client.MergeFrom(original)); err != nil {
+				log.Error(err, "Failed to update ContaboMachineTemplate product availability", "template", template.Name)
+				continue
+			}
+			if !available && r.Recorder != nil {
+				r.Recorder.Event(template, corev1.EventTypeWarning, infrastructurev1beta2.ProductUnavailableReason, message)
+			}
+		}
+	}
+
+	// Cluster-level warning
+	cluster := &infrastructurev1beta2.ContaboCluster{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(contaboCluster), cluster); err != nil {
+		log.Error(err, "Failed to get ContaboCluster to report product availability")
+		return
+	}
+	unavailable := slices.Contains(cluster.Status.UnavailableProducts, productId)
+	if available && !unavailable {
+		return
+	}
+	original := cluster.DeepCopy()
+	if available {
+		cluster.Status.UnavailableProducts = slices.DeleteFunc(cluster.Status.UnavailableProducts, func(product string) bool {
+			return product == productId
+		})
+	} else {
+		if !unavailable {
+			cluster.Status.UnavailableProducts = append(cluster.Status.UnavailableProducts, productId)
+			slices.Sort(cluster.Status.UnavailableProducts)
+		}
+		// Admission only rejects the unavailable products while the observation is recent
+		cluster.Status.UnavailableProductsLastObserved = ptr.To(metav1.Now())
+	}
+	if len(cluster.Status.UnavailableProducts) > 0 {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    infrastructurev1beta2.ClusterProductsAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrastructurev1beta2.ProductUnavailableReason,
+			Message: fmt.Sprintf("Products end-of-sale or unavailable: %s, update the ContaboMachineTemplates using them", strings.Join(cluster.Status.UnavailableProducts, ", ")),
+		})
+	} else {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:   infrastructurev1beta2.ClusterProductsAvailableCondition,
+			Status: metav1.ConditionTrue,
+			Reason: infrastructurev1beta2.ProductAvailableReason,
+		})
+	}
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(original)); err != nil {
+		log.Error(err, "Failed to update ContaboCluster product availability")
+		return
+	}
+	if !available && !unavailable && r.Recorder != nil {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, infrastructurev1beta2.ProductUnavailableReason, message)
+	}
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// Default requeue intervals and timeouts, used when ContaboProviderSettings does not override them
+const (
+	DefaultDependencyInterval       = 15 * time.Second
+	DefaultResourceCreationInterval = 5 * time.Second
+	DefaultInstanceInterval         = 15 * time.Second
+	DefaultCloudInitInterval        = 20 * time.Second
+	DefaultSshInterval              = 15 * time.Second
+	DefaultQuotaInterval            = 30 * time.Second
+	DefaultSshDialTimeout           = 10 * time.Second
+	DefaultInventoryInterval        = 5 * time.Minute
+	DefaultCatalogInterval          = time.Hour
+	DefaultHotLoopThreshold         = 50
+	DefaultHotLoopReconciles        = 3
+)
+
+// ProviderSettings holds the runtime tunables applied from the ContaboProviderSettings singleton.
+// A nil ProviderSettings returns the defaults.
+type ProviderSettings struct {
+	mu   sync.RWMutex
+	spec infrastructurev1beta2.ContaboProviderSettingsSpec
+}
+
+// NewProviderSettings returns provider settings with the default values
+func NewProviderSettings() *ProviderSettings {
+	return &ProviderSettings{}
+}
+
+// Update replaces the current settings
+func (s *ProviderSettings) Update(spec infrastructurev1beta2.ContaboProviderSettingsSpec) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spec = *spec.DeepCopy()
+}
+
+// duration returns the selected duration if set and positive, the default otherwise
+func (s *ProviderSettings) duration(selector func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration, defaultDuration time.Duration) time.Duration {
+	if s == nil {
+		return defaultDuration
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if d := selector(&s.spec); d != nil && d.Duration > 0 {
+		return d.Duration
+	}
+	return defaultDuration
+}
+
+// DependencyInterval is the requeue interval while waiting for other resources
+func (s *ProviderSettings) DependencyInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.Dependency
+	}, DefaultDependencyInterval)
+}
+
+// ResourceCreationInterval is the requeue interval after a Contabo resource was requested or a recoverable failure
+func (s *ProviderSettings) ResourceCreationInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.ResourceCreation
+	}, DefaultResourceCreationInterval)
+}
+
+// InstanceInterval is the requeue interval while waiting for an instance
+func (s *ProviderSettings) InstanceInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.Instance
+	}, DefaultInstanceInterval)
+}
+
+// CloudInitInterval is the requeue interval while waiting for cloud-init
+func (s *ProviderSettings) CloudInitInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.CloudInit
+	}, DefaultCloudInitInterval)
+}
+
+// SshInterval is the requeue interval after a failed SSH connection
+func (s *ProviderSettings) SshInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.Ssh
+	}, DefaultSshInterval)
+}
+
+// QuotaInterval is the requeue interval while waiting for ContaboQuota capacity
+func (s *ProviderSettings) QuotaInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.Quota
+	}, DefaultQuotaInterval)
+}
+
+// AuditTrailInterval is the interval between two audit trail refreshes
+func (s *ProviderSettings) AuditTrailInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.AuditTrail
+	}, AuditTrailRefreshInterval)
+}
+
+// InventoryInterval is the interval between two ContaboAccountInventory refreshes
+func (s *ProviderSettings) InventoryInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.Inventory
+	}, DefaultInventoryInterval)
+}
+
+// CatalogInterval is the interval between two ContaboCatalog refreshes
+func (s *ProviderSettings) CatalogInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.Catalog
+	}, DefaultCatalogInterval)
+}
+
+// OutOfStockInterval is the interval between two instance orders while the product is out of stock
+func (s *ProviderSettings) OutOfStockInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.OutOfStock
+	}, DefaultOutOfStockInterval)
+}
+
+// HostInterval is the interval between two checks of the host system of an instance
+func (s *ProviderSettings) HostInterval() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Intervals.Host
+	}, DefaultHostInterval)
+}
+
+// SshDialTimeout is the timeout to establish an SSH connection
+func (s *ProviderSettings) SshDialTimeout() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Timeouts.SshDial
+	}, DefaultSshDialTimeout)
+}
+
+// FirstBootProbeTimeout is the first-boot probe timeout when the ContaboMachine does not set one
+func (s *ProviderSettings) FirstBootProbeTimeout() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Timeouts.FirstBootProbe
+	}, DefaultFirstBootProbeTimeout)
+}
+
+// InstanceOrderTimeout is the time an ordered instance has to appear and leave provisioning
+func (s *ProviderSettings) InstanceOrderTimeout() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Timeouts.InstanceOrder
+	}, DefaultInstanceOrderTimeout)
+}
+
+// ShutdownTimeout is the time a stopped instance has to shut down gracefully before it is stopped
+func (s *ProviderSettings) ShutdownTimeout() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Timeouts.Shutdown
+	}, DefaultShutdownTimeout)
+}
+
+// StuckDeletionThreshold is the time a resource may spend deleting before it is reported as stuck
+func (s *ProviderSettings) StuckDeletionThreshold() time.Duration {
+	return s.duration(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *metav1.Duration {
+		return spec.Timeouts.StuckDeletion
+	}, DefaultStuckDeletionThreshold)
+}
+
+// Bootstrap returns how the bootstrap data is passed to the instances
+func (s *ProviderSettings) Bootstrap() infrastructurev1beta2.ContaboBootstrapSettings {
+	if s == nil {
+		return infrastructurev1beta2.ContaboBootstrapSettings{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return *s.spec.Bootstrap.DeepCopy()
+}
+
+// Notifications returns where the critical provider events are forwarded
+func (s *ProviderSettings) Notifications() infrastructurev1beta2.ContaboNotificationSettings {
+	if s == nil {
+		return infrastructurev1beta2.ContaboNotificationSettings{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return *s.spec.Notifications.DeepCopy()
+}
+
+// count returns the selected count if set and positive, the default otherwise
+func (s *ProviderSettings) count(selector func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *int32, defaultCount int) int {
+	if s == nil {
+		return defaultCount
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if c := selector(&s.spec); c != nil && *c > 0 {
+		return int(*c)
+	}
+	return defaultCount
+}
+
+// HotLoopThreshold is the number of Contabo API requests a single reconciliation may send
+func (s *ProviderSettings) HotLoopThreshold() int {
+	return s.count(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *int32 {
+		return spec.APICalls.HotLoopThreshold
+	}, DefaultHotLoopThreshold)
+}
+
+// HotLoopReconciles is the number of reconciliations in a row above the hot loop threshold before it is reported
+func (s *ProviderSettings) HotLoopReconciles() int {
+	return s.count(func(spec *infrastructurev1beta2.ContaboProviderSettingsSpec) *int32 {
+		return spec.APICalls.HotLoopReconciles
+	}, DefaultHotLoopReconciles)
+}
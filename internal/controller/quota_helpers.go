@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// QuotaReservation is the time the resources granted by a ContaboQuota are held before they show up in the status of
+// the ContaboMachine acquiring them
+const QuotaReservation = 2 * time.Minute
+
+// quotaRequest describes the additional resources a reconciler wants to consume in a namespace
+type quotaRequest struct {
+	Instances       int32
+	CpuCores        int64
+	RamMb           int64
+	PrivateNetworks int32
+}
+
+// instanceQuotaRequest returns the quota request of a new instance of the product of the ContaboMachine, whose size is
+// taken from the catalog. The size of the products missing from the catalog is unknown.
+func instanceQuotaRequest(contaboMachine *infrastructurev1beta2.ContaboMachine) quotaRequest {
+	productId := ptr.Deref(contaboMachine.Spec.Instance.ProductId, "")
+	return quotaRequest{
+		Instances: 1,
+		CpuCores:  int64(infrastructurev1beta2.ContaboProductCpuCores(productId)),
+		RamMb:     int64(infrastructurev1beta2.ContaboProductRamGb(productId)) * 1024,
+	}
+}
+
+// quotaReservations holds the instances granted by the ContaboQuotas until they show up in the status of their
+// ContaboMachine, concurrent reconciliations would otherwise all fit into the same remaining quota
+type quotaReservations struct {
+	mu       sync.Mutex
+	reserved map[types.NamespacedName]quotaReservation
+}
+
+// quotaReservation is an instance granted to a ContaboMachine, or to the control plane machine of an instance display
+// name for the orders of the control plane gang
+type quotaReservation struct {
+	request    quotaRequest
+	reservedAt time.Time
+}
+
+// computeQuotaUsage sums up the Contabo resources consumed by ContaboMachines and ContaboClusters in a namespace,
+// including the instances ordered but not available yet
+func computeQuotaUsage(ctx context.Context, c client.Client, namespace string) (infrastructurev1beta2.ContaboQuotaUsage, error) {
+	usage, _, err := namespaceQuotaUsage(ctx, c, namespace)
+	return usage, err
+}
+
+// namespaceQuotaUsage returns the quota usage of the namespace and the keys of the reservations it already accounts
+// for: the ContaboMachines holding or ordering an instance and the display names of the control plane gang orders
+func namespaceQuotaUsage(ctx context.Context, c client.Client, namespace string) (infrastructurev1beta2.ContaboQuotaUsage, map[types.NamespacedName]bool, error) {
+	usage := infrastructurev1beta2.ContaboQuotaUsage{}
+	acquired := map[types.NamespacedName]bool{}
+
+	contaboMachineList := &infrastructurev1beta2.ContaboMachineList{}
+	if err := c.List(ctx, contaboMachineList, client.InNamespace(namespace)); err != nil {
+		return usage, nil, fmt.Errorf("failed to list ContaboMachines: %w", err)
+	}
+	add := func(request quotaRequest) {
+		usage.Instances += request.Instances
+		usage.CpuCores += request.CpuCores
+		usage.RamMb += request.RamMb
+	}
+	held := map[int64]bool{}
+	for i := range contaboMachineList.Items {
+		contaboMachine := &contaboMachineList.Items[i]
+		if instance := contaboMachine.Status.Instance; instance != nil {
+			held[instance.InstanceId] = true
+			acquired[client.ObjectKeyFromObject(contaboMachine)] = true
+			add(quotaRequest{Instances: 1, CpuCores: instance.CpuCores, RamMb: int64(instance.RamMb)})
+		} else if order := contaboMachine.Status.InstanceOrder; order != nil && order.InstanceId != 0 {
+			// The instance is ordered, its size is the one of the product until it is available
+			held[order.InstanceId] = true
+			acquired[client.ObjectKeyFromObject(contaboMachine)] = true
+			add(instanceQuotaRequest(contaboMachine))
+		}
+	}
+	// The instances ordered for the other control plane machines count until these machines hold them
+	for i := range contaboMachineList.Items {
+		contaboMachine := &contaboMachineList.Items[i]
+		if contaboMachine.Status.ControlPlaneGang == nil {
+			continue
+		}
+		for _, order := range contaboMachine.Status.ControlPlaneGang.Orders {
+			if order.InstanceId == 0 {
+				continue
+			}
+			acquired[types.NamespacedName{Namespace: namespace, Name: order.DisplayName}] = true
+			if !held[order.InstanceId] {
+				held[order.InstanceId] = true
+				add(instanceQuotaRequest(contaboMachine))
+			}
+		}
+	}
+
+	contaboClusterList := &infrastructurev1beta2.ContaboClusterList{}
+	if err := c.List(ctx, contaboClusterList, client.InNamespace(namespace)); err != nil {
+		return usage, nil, fmt.Errorf("failed to list ContaboClusters: %w", err)
+	}
+	for _, contaboCluster := range contaboClusterList.Items {
+		if contaboCluster.Status.PrivateNetwork != nil {
+			usage.PrivateNetworks++
+		}
+	}
+
+	return usage, acquired, nil
+}
+
+// quotaViolations returns the human readable list of limits of the quota that the usage reaches or exceeds
+// once the requested resources are added
+func quotaViolations(quota *infrastructurev1beta2.ContaboQuota, usage infrastructurev1beta2.ContaboQuotaUsage, request quotaRequest) []string {
+	violations := []string{}
+	spec := quota.Spec
+
+	if request.Instances > 0 {
+		if spec.MaxInstances != nil && usage.Instances+request.Instances > *spec.MaxInstances {
+			violations = append(violations, fmt.Sprintf("instances %d/%d", usage.Instances, *spec.MaxInstances))
+		}
+		// The size of a product missing from the catalog is unknown, its instances are only blocked once the limit
+		// is reached
+		if spec.MaxCpuCores != nil && exceedsQuota(usage.CpuCores, request.CpuCores, *spec.MaxCpuCores) {
+			violations = append(violations, fmt.Sprintf("cpuCores %d/%d", usage.CpuCores, *spec.MaxCpuCores))
+		}
+		if spec.MaxRamMb != nil && exceedsQuota(usage.RamMb, request.RamMb, *spec.MaxRamMb) {
+			violations = append(violations, fmt.Sprintf("ramMb %d/%d", usage.RamMb, *spec.MaxRamMb))
+		}
+	}
+
+	if request.PrivateNetworks > 0 && spec.MaxPrivateNetworks != nil && usage.PrivateNetworks+request.PrivateNetworks > *spec.MaxPrivateNetworks {
+		violations = append(violations, fmt.Sprintf("privateNetworks %d/%d", usage.PrivateNetworks, *spec.MaxPrivateNetworks))
+	}
+
+	return violations
+}
+
+// exceedsQuota returns true when the requested amount does not fit into the limit, or when the limit is reached for
+// an unknown amount
+func exceedsQuota(used, requested, limit int64) bool {
+	if requested == 0 {
+		return used >= limit
+	}
+	return used+requested > limit
+}
+
+// checkQuota verifies that the requested resources fit into every ContaboQuota of the namespace, counting the
+// resources reserved for the other keys. The request is reserved for the key when it fits, reservations can be nil.
+// It returns a non-nil error describing the exceeded quota if the request must be refused
+func checkQuota(ctx context.Context, c client.Client, reservations *quotaReservations, key types.NamespacedName, request quotaRequest) error {
+	quotaList := &infrastructurev1beta2.ContaboQuotaList{}
+	if err := c.List(ctx, quotaList, client.InNamespace(key.Namespace)); err != nil {
+		return fmt.Errorf("failed to list ContaboQuotas: %w", err)
+	}
+	if len(quotaList.Items) == 0 {
+		return nil
+	}
+
+	if reservations != nil {
+		reservations.mu.Lock()
+		defer reservations.mu.Unlock()
+	}
+
+	usage, acquired, err := namespaceQuotaUsage(ctx, c, key.Namespace)
+	if err != nil {
+		return err
+	}
+	if reservations != nil {
+		now := time.Now()
+		for reservedKey, reservation := range reservations.reserved {
+			if acquired[reservedKey] || now.Sub(reservation.reservedAt) >= QuotaReservation {
+				delete(reservations.reserved, reservedKey)
+				continue
+			}
+			if reservedKey.Namespace == key.Namespace && reservedKey != key {
+				usage.Instances += reservation.request.Instances
+				usage.CpuCores += reservation.request.CpuCores
+				usage.RamMb += reservation.request.RamMb
+				usage.PrivateNetworks += reservation.request.PrivateNetworks
+			}
+		}
+	}
+
+	for i := range quotaList.Items {
+		quota := &quotaList.Items[i]
+		if violations := quotaViolations(quota, usage, request); len(violations) > 0 {
+			return fmt.Errorf("ContaboQuota %s/%s exceeded: %s", quota.Namespace, quota.Name, strings.Join(violations, ", "))
+		}
+	}
+
+	if reservations != nil {
+		if reservations.reserved == nil {
+			reservations.reserved = map[types.NamespacedName]quotaReservation{}
+		}
+		reservations.reserved[key] = quotaReservation{request: request, reservedAt: time.Now()}
+	}
+	return nil
+}
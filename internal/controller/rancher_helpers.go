@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// BootstrapDataFormatCloudConfig is the only bootstrap data format the instances are reinstalled with, the format
+// key of the bootstrap data secret defaults to it
+const BootstrapDataFormatCloudConfig = "cloud-config"
+
+// bootstrapFlavor is the Kubernetes distribution installed by the bootstrap data
+type bootstrapFlavor string
+
+const (
+	// bootstrapFlavorKubeadm is installed by the kubeadm bootstrap provider on top of the containerd and kubeadm
+	// packages of the provider cloud-config
+	bootstrapFlavorKubeadm bootstrapFlavor = "kubeadm"
+	// bootstrapFlavorK3s is installed by the k3s bootstrap provider with its install script
+	bootstrapFlavorK3s bootstrapFlavor = "k3s"
+	// bootstrapFlavorRKE2 is installed by the rke2 bootstrap provider with its install script
+	bootstrapFlavorRKE2 bootstrapFlavor = "rke2"
+)
+
+// configPath returns the configuration file of the k3s and rke2 distributions
+func (f bootstrapFlavor) configPath() string {
+	return fmt.Sprintf("/etc/rancher/%s/config.yaml", f)
+}
+
+// validateBootstrapDataFormat returns an error when the bootstrap data secret is not in the cloud-config format,
+// Contabo reinstalls the instances with cloud-init user data only
+func validateBootstrapDataFormat(secret *corev1.Secret) error {
+	if format := string(secret.Data["format"]); format != "" && format != BootstrapDataFormatCloudConfig {
+		return fmt.Errorf("bootstrap data format %q is not supported, only %s is", format, BootstrapDataFormatCloudConfig)
+	}
+	return nil
+}
+
+// detectBootstrapFlavor returns the distribution installed by the cloud-config bootstrap data, from the configuration
+// file it writes or the install script it runs, kubeadm when it is neither k3s nor rke2
+func detectBootstrapFlavor(bootstrapData []byte) bootstrapFlavor {
+	var cloudConfig map[string]interface{}
+	if err := yaml.Unmarshal(bootstrapData, &cloudConfig); err != nil {
+		return bootstrapFlavorKubeadm
+	}
+
+	writeFiles, _ := cloudConfig["write_files"].([]interface{})
+	for _, file := range writeFiles {
+		fileMap, _ := file.(map[interface{}]interface{})
+		path, _ := fileMap["path"].(string)
+		for _, flavor := range []bootstrapFlavor{bootstrapFlavorK3s, bootstrapFlavorRKE2} {
+			if strings.HasPrefix(path, fmt.Sprintf("/etc/rancher/%s/", flavor)) {
+				return flavor
+			}
+		}
+	}
+
+	runcmd, _ := cloudConfig["runcmd"].([]interface{})
+	for _, command := range runcmd {
+		script := fmt.Sprint(command)
+		switch {
+		case strings.Contains(script, "get.k3s.io") || strings.Contains(script, "INSTALL_K3S_"):
+			return bootstrapFlavorK3s
+		case strings.Contains(script, "get.rke2.io") || strings.Contains(script, "INSTALL_RKE2_"):
+			return bootstrapFlavorRKE2
+		}
+	}
+	return bootstrapFlavorKubeadm
+}
+
+// contaboRancherConfig returns the k3s or rke2 settings required for Contabo instances to join reliably, the
+// counterpart of the kubelet flags of kubeadm. The API server of the k3s servers listens on the control plane
+// endpoint port, rke2 does not support another port than the default one.
+func contaboRancherConfig(flavor bootstrapFlavor, contaboMachine *infrastructurev1beta2.ContaboMachine, internalIPv4 string, apiServerPort int32) map[string]interface{} {
+	config := map[string]interface{}{
+		// Use the private network address for node traffic
+		"node-ip": internalIPv4,
+	}
+	// Node name must match the hostname set on the instance
+	if nodeName, ok := contaboKubeletExtraArgs(contaboMachine, internalIPv4)["hostname-override"]; ok {
+		config["node-name"] = nodeName
+	}
+
+	// Nodes are initialized by the controller (placeholder for an external cloud provider)
+	_, controlPlane := contaboMachine.Labels[clusterv1.MachineControlPlaneLabel]
+	switch flavor {
+	case bootstrapFlavorK3s:
+		config["kubelet-arg"] = []interface{}{"cloud-provider=external"}
+		if controlPlane {
+			config["disable-cloud-controller"] = true
+			if apiServerPort != 0 {
+				config["https-listen-port"] = int(apiServerPort)
+			}
+		}
+	case bootstrapFlavorRKE2:
+		config["cloud-provider-name"] = "external"
+	}
+
+	keys := make([]string, 0, len(contaboMachine.Spec.NodeLabels))
+	for key := range contaboMachine.Spec.NodeLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	labels := []interface{}{}
+	for _, key := range keys {
+		labels = append(labels, key+"="+contaboMachine.Spec.NodeLabels[key])
+	}
+	if len(labels) > 0 {
+		config["node-label"] = labels
+	}
+
+	taints := []interface{}{}
+	for _, taint := range contaboMachine.Spec.NodeTaints {
+		taints = append(taints, taint.ToString())
+	}
+	if len(taints) > 0 {
+		config["node-taint"] = taints
+	}
+	return config
+}
+
+// injectRancherConfig merges the settings in the k3s or rke2 configuration file written by the bootstrap data, or
+// writes it when the bootstrap data has none. Settings already set by the user are kept, the labels, taints and
+// kubelet flags are appended to the ones of the user.
+func injectRancherConfig(bootstrapData []byte, flavor bootstrapFlavor, settings map[string]interface{}) ([]byte, error) {
+	var cloudConfig map[string]interface{}
+	if err := yaml.Unmarshal(bootstrapData, &cloudConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bootstrap data: %v", err)
+	}
+	if cloudConfig == nil {
+		cloudConfig = map[string]interface{}{}
+	}
+
+	writeFiles, _ := cloudConfig["write_files"].([]interface{})
+	var configFile map[interface{}]interface{}
+	for _, file := range writeFiles {
+		if fileMap, ok := file.(map[interface{}]interface{}); ok && fileMap["path"] == flavor.configPath() {
+			configFile = fileMap
+			break
+		}
+	}
+	if configFile == nil {
+		configFile = map[interface{}]interface{}{"path": flavor.configPath(), "owner": "root:root", "permissions": "0600", "content": ""}
+		cloudConfig["write_files"] = append(writeFiles, configFile)
+	}
+
+	content, _ := configFile["content"].(string)
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s configuration %s: %v", flavor, flavor.configPath(), err)
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values, isList := settings[key].([]interface{})
+		if !isList {
+			if _, ok := config[key]; !ok {
+				config[key] = settings[key]
+			}
+			continue
+		}
+		var existing []interface{}
+		switch value := config[key].(type) {
+		case []interface{}:
+			existing = value
+		case string:
+			existing = []interface{}{value}
+		}
+		for _, value := range values {
+			if !slices.ContainsFunc(existing, func(v interface{}) bool { return sameRancherListEntry(key, v, value) }) {
+				existing = append(existing, value)
+			}
+		}
+		config[key] = existing
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	configFile["content"] = string(out)
+	return yaml.Marshal(cloudConfig)
+}
+
+// sameRancherListEntry reports whether two entries of a k3s or rke2 list setting set the same thing, the labels and
+// kubelet flags by their name and the taints by their value
+func sameRancherListEntry(key string, a interface{}, b interface{}) bool {
+	aString, bString := fmt.Sprint(a), fmt.Sprint(b)
+	if key == "node-label" || key == "kubelet-arg" {
+		aName, _, _ := strings.Cut(aString, "=")
+		bName, _, _ := strings.Cut(bString, "=")
+		return aName == bName
+	}
+	return aString == bString
+}
@@ -0,0 +1,56 @@
+import { yaml } from "jsr:@tmpl/core";
+import * as YAML from "jsr:@std/yaml";
+
+import * as clusterUUID from "./cloud-config/cluster-uuid.ts";
+import * as network from "./cloud-config/network.ts";
+
+import { Packages, RunCmd } from "./cloud-config/types.ts";
+
+// Base cloud-config of the bootstrap providers installing their own Kubernetes distribution (k3s, rke2)
+
+export const packageUpdate: boolean = [
+  clusterUUID.packageUpdate,
+  network.packageUpdate,
+]
+  .some((
+    x,
+  ) => x);
+
+export const packages: Packages = [
+  ...new Set([
+    clusterUUID.packages,
+    network.packages,
+    // Required by the install scripts of the distributions
+    ["sudo", "ca-certificates", "curl"],
+  ].flat()),
+];
+
+export const writeFiles = [
+  ...clusterUUID.writeFiles,
+  ...network.writeFiles,
+].map((item) => ({ ...item, content: item.content.noindent().trim() }));
+
+export const runcmd: RunCmd = [
+  clusterUUID.runcmd,
+  network.runcmd,
+].flat();
+
+export default yaml`
+#cloud-config
+package_update: ${packageUpdate}
+
+write_files:
+${YAML.stringify(writeFiles, { arrayIndent: true }).trim()}
+
+packages:
+  ${packages.map((line) => `- ${line}`).join("\n  ").trimStart()}
+
+runcmd:
+  ${
+  runcmd.map((script) =>
+    script.includes("\n")
+      ? script.startsWith("\n") ? `- |${script}` : `- |\n${script}`
+      : `- ${script}`
+  ).join("\n  ").trimStart()
+}
+`.trim();
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deprecation migrates the renamed fields of the provider resources. A renamed field keeps its deprecated
+// name in the API, marked Deprecated, until the next API version: the API server prunes the fields unknown to the
+// schema before the admission webhooks see them. The mutating webhook moves the deprecated fields to their
+// replacement with a warning, and the controllers annotate the resources still using deprecated fields, e.g. created
+// while the webhook was not running.
+package deprecation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Annotation lists the deprecated fields a resource still uses, set by the controllers
+const Annotation = "infrastructure.cluster.x-k8s.io/deprecated-fields"
+
+// Field is a field of a kind renamed in the API
+type Field struct {
+	// Kind is the kind of the resources holding the field, e.g. ContaboMachineTemplate
+	Kind string
+	// Path is the dotted JSON path of the deprecated field, e.g. spec.template.spec.instance.productId
+	Path string
+	// Replacement is the dotted JSON path of the field replacing it
+	Replacement string
+	// Since is the release deprecating the field
+	Since string
+}
+
+// Fields are the deprecated fields of the provider resources. Add an entry when renaming a field, together with the
+// new field and the deprecated one kept in the API as optional and omitempty, a ContaboMachine field being added for
+// the ContaboMachineTemplate as well.
+var Fields = []Field{}
+
+func (f Field) path() []string {
+	return strings.Split(f.Path, ".")
+}
+
+func (f Field) replacement() []string {
+	return strings.Split(f.Replacement, ".")
+}
+
+// InUse returns the paths of the deprecated fields of the kind set in the object
+func InUse(fields []Field, kind string, obj map[string]interface{}) []string {
+	inUse := []string{}
+	for _, field := range fields {
+		if field.Kind != kind {
+			continue
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(obj, field.path()...); found {
+			inUse = append(inUse, field.Path)
+		}
+	}
+	return inUse
+}
+
+// Migrate moves the deprecated fields of the kind set in the object to their replacement and returns a warning for
+// each of them. A deprecated field is dropped when its replacement is set as well, the replacement wins.
+func Migrate(fields []Field, kind string, obj map[string]interface{}) ([]string, error) {
+	warnings := []string{}
+	for _, field := range fields {
+		if field.Kind != kind {
+			continue
+		}
+		value, found, _ := unstructured.NestedFieldNoCopy(obj, field.path()...)
+		if !found {
+			continue
+		}
+		if _, replaced, _ := unstructured.NestedFieldNoCopy(obj, field.replacement()...); replaced {
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated since %s and ignored as %s is set, remove it", field.Path, field.Since, field.Replacement))
+		} else {
+			if err := unstructured.SetNestedField(obj, runtime.DeepCopyJSONValue(value), field.replacement()...); err != nil {
+				return warnings, fmt.Errorf("failed to migrate %s to %s: %w", field.Path, field.Replacement, err)
+			}
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated since %s, it was migrated to %s", field.Path, field.Since, field.Replacement))
+		}
+		unstructured.RemoveNestedField(obj, field.path()...)
+	}
+	return warnings, nil
+}
+
+// Mark records the deprecated fields still used by a reconciled resource of the kind in its annotations, and removes
+// the annotation once they are all migrated. The resource is only converted when a field of the kind is deprecated.
+func Mark(fields []Field, kind string, obj runtime.Object) error {
+	accessor, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("expected an object with metadata but got %T", obj)
+	}
+
+	inUse := []string{}
+	if slices.ContainsFunc(fields, func(field Field) bool { return field.Kind == kind }) {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s %s: %w", kind, accessor.GetName(), err)
+		}
+		inUse = InUse(fields, kind, content)
+		slices.Sort(inUse)
+	}
+	annotations := accessor.GetAnnotations()
+	if len(inUse) == 0 {
+		if _, ok := annotations[Annotation]; ok {
+			delete(annotations, Annotation)
+			accessor.SetAnnotations(annotations)
+		}
+		return nil
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[Annotation] = strings.Join(inUse, ",")
+	accessor.SetAnnotations(annotations)
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecation
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// testFields renames fields for the tests, the API has no deprecated field yet
+var testFields = []Field{
+	{Kind: "ContaboMachine", Path: "spec.dns", Replacement: "spec.resolvers", Since: "v0.9.0"},
+	{Kind: "ContaboMachine", Path: "spec.instance.product", Replacement: "spec.instance.productId", Since: "v0.9.0"},
+	{Kind: "ContaboCluster", Path: "spec.dns", Replacement: "spec.clusterDNS", Since: "v0.9.0"},
+}
+
+func TestMigrate(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"dns": map[string]interface{}{"nameservers": []interface{}{"10.0.0.53"}},
+			"instance": map[string]interface{}{
+				"product":   "V45",
+				"productId": "V91",
+			},
+		},
+	}
+
+	warnings, err := Migrate(testFields, "ContaboMachine", obj)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "migrated to spec.resolvers") || !strings.Contains(warnings[1], "ignored as spec.instance.productId is set") {
+		t.Errorf("warnings = %v", warnings)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj, "spec", "dns"); found {
+		t.Errorf("spec.dns was not removed: %v", obj)
+	}
+	if nameservers, _, _ := unstructured.NestedStringSlice(obj, "spec", "resolvers", "nameservers"); len(nameservers) != 1 || nameservers[0] != "10.0.0.53" {
+		t.Errorf("spec.resolvers.nameservers = %v, want the migrated nameservers", nameservers)
+	}
+	if productId, _, _ := unstructured.NestedString(obj, "spec", "instance", "productId"); productId != "V91" {
+		t.Errorf("spec.instance.productId = %q, want the replacement kept", productId)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj, "spec", "instance", "product"); found {
+		t.Errorf("spec.instance.product was not removed: %v", obj)
+	}
+
+	if warnings, err := Migrate(testFields, "ContaboMachine", obj); err != nil || len(warnings) != 0 {
+		t.Errorf("Migrate() of a migrated object = %v, %v, want no warning", warnings, err)
+	}
+}
+
+func TestMark(t *testing.T) {
+	contaboMachine := &infrastructurev1beta2.ContaboMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Annotations: map[string]string{"other": "kept"}},
+	}
+	contaboMachine.Spec.DNS = &infrastructurev1beta2.ContaboDNSSpec{Nameservers: []string{"10.0.0.53"}}
+
+	if err := Mark(testFields, "ContaboMachine", contaboMachine); err != nil {
+		t.Fatalf("Mark() error = %v", err)
+	}
+	if got := contaboMachine.Annotations[Annotation]; got != "spec.dns" {
+		t.Errorf("annotation = %q, want spec.dns", got)
+	}
+
+	contaboMachine.Spec.DNS = nil
+	if err := Mark(testFields, "ContaboMachine", contaboMachine); err != nil {
+		t.Fatalf("Mark() error = %v", err)
+	}
+	if _, ok := contaboMachine.Annotations[Annotation]; ok {
+		t.Errorf("annotation not removed once migrated: %v", contaboMachine.Annotations)
+	}
+	if contaboMachine.Annotations["other"] != "kept" {
+		t.Errorf("annotations = %v, want the other annotations kept", contaboMachine.Annotations)
+	}
+}
+
+func TestMarkWithoutDeprecatedFields(t *testing.T) {
+	contaboMachine := &infrastructurev1beta2.ContaboMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Annotations: map[string]string{Annotation: "spec.dns"}},
+	}
+
+	if err := Mark(nil, "ContaboMachine", contaboMachine); err != nil {
+		t.Fatalf("Mark() error = %v", err)
+	}
+	if _, ok := contaboMachine.Annotations[Annotation]; ok {
+		t.Errorf("annotation of a former release not removed: %v", contaboMachine.Annotations)
+	}
+	if err := Mark(testFields, "ContaboMachinePool", &infrastructurev1beta2.ContaboMachinePool{}); err != nil {
+		t.Errorf("Mark() of a kind without deprecated field error = %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package generator renders ready-to-apply Cluster, KubeadmControlPlane and MachineDeployment manifests for the
+// common cluster flavors, parameterized by product and region.
+package generator
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// Flavor is a cluster topology the generator renders
+type Flavor string
+
+const (
+	// FlavorDev is a single control plane machine, for development clusters
+	FlavorDev Flavor = "dev"
+	// FlavorHA is three control plane machines sharing a kube-vip virtual IP announced on the public interface
+	FlavorHA Flavor = "ha"
+	// FlavorPrivate is three control plane machines sharing a kube-vip virtual IP announced on the private network
+	// interface, the API server is only reachable from the private network
+	FlavorPrivate Flavor = "private"
+)
+
+// Flavors returns the flavors the generator renders
+func Flavors() []Flavor {
+	return []Flavor{FlavorDev, FlavorHA, FlavorPrivate}
+}
+
+// Default values of the options
+const (
+	DefaultNamespace           = "default"
+	DefaultRegion              = infrastructurev1beta2.ContaboRegionEU
+	DefaultProduct             = infrastructurev1beta2.ContaboProductCloudVPS10NVMe
+	DefaultWorkerMachineCount  = 2
+	DefaultAPIServerPort       = 6443
+	DefaultPodCIDR             = "192.168.0.0/16"
+	DefaultServiceCIDR         = "10.96.0.0/12"
+	DefaultKubeVIPImage        = "ghcr.io/kube-vip/kube-vip:v0.9.2"
+	DefaultPublicVIPInterface  = "eth0"
+	DefaultPrivateVIPInterface = "eth1"
+)
+
+//go:embed templates/cluster.yaml.tmpl
+var templates embed.FS
+
+// productIdPattern is the format of the Contabo product IDs, as validated by the ContaboMachine CRD
+var productIdPattern = regexp.MustCompile(`^V[0-9]+$`)
+
+// Options are the parameters of the generated manifests
+type Options struct {
+	// Flavor is the cluster topology
+	Flavor Flavor
+	// ClusterName is the name of the Cluster and the prefix of the other objects
+	ClusterName string
+	// Namespace is the namespace of the objects, default "default"
+	Namespace string
+	// KubernetesVersion is the Kubernetes version of the machines, e.g. v1.33.4
+	KubernetesVersion string
+	// Region is the Contabo region of the private network and the instances, default EU
+	Region infrastructurev1beta2.ContaboRegion
+	// ControlPlaneProduct is the Contabo product of the control plane machines, default V91
+	ControlPlaneProduct infrastructurev1beta2.ContaboProductId
+	// WorkerProduct is the Contabo product of the worker machines, default V91
+	WorkerProduct infrastructurev1beta2.ContaboProductId
+	// ControlPlaneMachineCount is the number of control plane machines, default 1 for the dev flavor and 3 otherwise
+	ControlPlaneMachineCount int
+	// WorkerMachineCount is the number of worker machines
+	WorkerMachineCount int
+	// ControlPlaneEndpoint is the host of the API server, the virtual IP announced by kube-vip for the ha and private
+	// flavors
+	ControlPlaneEndpoint string
+	// APIServerPort is the port of the API server, default 6443
+	APIServerPort int
+	// PrivateNetworkName is the private network the cluster joins, default a private network of the cluster
+	PrivateNetworkName string
+	// VIPInterface is the interface kube-vip announces the virtual IP on, default eth0 for the ha flavor and eth1 for
+	// the private flavor
+	VIPInterface string
+	// KubeVIPImage is the kube-vip image
+	KubeVIPImage string
+	// PodCIDR is the CIDR of the pods, default 192.168.0.0/16
+	PodCIDR string
+	// ServiceCIDR is the CIDR of the services, default 10.96.0.0/12
+	ServiceCIDR string
+	// EncryptionKey is the base64 encoded 32-byte secretbox key encrypting the secrets at rest, generated when empty
+	EncryptionKey string
+}
+
+// templateData are the options with the values derived from the flavor
+type templateData struct {
+	Options
+	KubeVIP bool
+}
+
+// Default sets the default values of the unset options
+func (o *Options) Default() error {
+	if o.Namespace == "" {
+		o.Namespace = DefaultNamespace
+	}
+	if o.Region == "" {
+		o.Region = DefaultRegion
+	}
+	if o.ControlPlaneProduct == "" {
+		o.ControlPlaneProduct = DefaultProduct
+	}
+	if o.WorkerProduct == "" {
+		o.WorkerProduct = DefaultProduct
+	}
+	if o.ControlPlaneMachineCount == 0 {
+		o.ControlPlaneMachineCount = 3
+		if o.Flavor == FlavorDev {
+			o.ControlPlaneMachineCount = 1
+		}
+	}
+	if o.APIServerPort == 0 {
+		o.APIServerPort = DefaultAPIServerPort
+	}
+	if o.VIPInterface == "" {
+		o.VIPInterface = DefaultPublicVIPInterface
+		if o.Flavor == FlavorPrivate {
+			o.VIPInterface = DefaultPrivateVIPInterface
+		}
+	}
+	if o.KubeVIPImage == "" {
+		o.KubeVIPImage = DefaultKubeVIPImage
+	}
+	if o.PodCIDR == "" {
+		o.PodCIDR = DefaultPodCIDR
+	}
+	if o.ServiceCIDR == "" {
+		o.ServiceCIDR = DefaultServiceCIDR
+	}
+	if o.EncryptionKey == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate the encryption key: %w", err)
+		}
+		o.EncryptionKey = base64.StdEncoding.EncodeToString(key)
+	}
+	return nil
+}
+
+// Validate returns the invalid options
+func (o *Options) Validate() error {
+	errs := []error{}
+	if !slices.Contains(Flavors(), o.Flavor) {
+		errs = append(errs, fmt.Errorf("unknown flavor %q, must be one of %s", o.Flavor, joinFlavors()))
+	}
+	for _, msg := range validation.IsDNS1123Label(o.ClusterName) {
+		errs = append(errs, fmt.Errorf("invalid cluster name %q: %s", o.ClusterName, msg))
+	}
+	for _, msg := range validation.IsDNS1123Label(o.Namespace) {
+		errs = append(errs, fmt.Errorf("invalid namespace %q: %s", o.Namespace, msg))
+	}
+	if !strings.HasPrefix(o.KubernetesVersion, "v") {
+		errs = append(errs, fmt.Errorf("invalid Kubernetes version %q, must start with v, e.g. v1.33.4", o.KubernetesVersion))
+	}
+	if !slices.Contains(infrastructurev1beta2.ContaboRegions(), o.Region) {
+		errs = append(errs, fmt.Errorf("unknown region %q", o.Region))
+	}
+	for _, product := range []infrastructurev1beta2.ContaboProductId{o.ControlPlaneProduct, o.WorkerProduct} {
+		if !productIdPattern.MatchString(string(product)) {
+			errs = append(errs, fmt.Errorf("invalid product %q, must be a Contabo product ID, e.g. %s", product, DefaultProduct))
+		}
+	}
+	if o.ControlPlaneMachineCount < 1 || o.ControlPlaneMachineCount%2 == 0 {
+		errs = append(errs, fmt.Errorf("invalid control plane machine count %d, must be odd to keep the etcd quorum", o.ControlPlaneMachineCount))
+	}
+	if o.Flavor == FlavorDev && o.ControlPlaneMachineCount != 1 {
+		errs = append(errs, fmt.Errorf("the %s flavor has a single control plane machine, use the %s flavor for more", FlavorDev, FlavorHA))
+	}
+	if o.WorkerMachineCount < 0 {
+		errs = append(errs, fmt.Errorf("invalid worker machine count %d", o.WorkerMachineCount))
+	}
+	if o.ControlPlaneEndpoint == "" {
+		errs = append(errs, errors.New("the control plane endpoint is required"))
+	} else if o.Flavor != FlavorDev && net.ParseIP(o.ControlPlaneEndpoint).To4() == nil {
+		errs = append(errs, fmt.Errorf("invalid control plane endpoint %q, the %s flavor announces it with kube-vip and requires an IPv4 address", o.ControlPlaneEndpoint, o.Flavor))
+	}
+	if o.APIServerPort < 1 || o.APIServerPort > 65535 {
+		errs = append(errs, fmt.Errorf("invalid API server port %d", o.APIServerPort))
+	}
+	for _, cidr := range []string{o.PodCIDR, o.ServiceCIDR} {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fmt.Errorf("invalid CIDR %q", cidr))
+		}
+	}
+	if key, err := base64.StdEncoding.DecodeString(o.EncryptionKey); err != nil || len(key) != 32 {
+		errs = append(errs, errors.New("invalid encryption key, must be 32 bytes encoded in base64"))
+	}
+	return errors.Join(errs...)
+}
+
+// Generate defaults and validates the options, and writes the manifests of the cluster
+func Generate(w io.Writer, opts Options) error {
+	if err := opts.Default(); err != nil {
+		return err
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("cluster.yaml.tmpl").ParseFS(templates, "templates/cluster.yaml.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to parse the cluster template: %w", err)
+	}
+	return tmpl.Execute(w, templateData{
+		Options: opts,
+		KubeVIP: opts.Flavor != FlavorDev,
+	})
+}
+
+// joinFlavors returns the flavors separated by commas
+func joinFlavors() string {
+	flavors := []string{}
+	for _, flavor := range Flavors() {
+		flavors = append(flavors, string(flavor))
+	}
+	return strings.Join(flavors, ", ")
+}
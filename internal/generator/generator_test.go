@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// documents decodes the YAML documents of the manifests
+func documents(t *testing.T, manifests []byte) []map[string]interface{} {
+	t.Helper()
+	docs := []map[string]interface{}{}
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		doc := map[string]interface{}{}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs
+		}
+		if err != nil {
+			t.Fatalf("invalid YAML: %v\n%s", err, manifests)
+		}
+		docs = append(docs, doc)
+	}
+}
+
+func TestGenerateFlavors(t *testing.T) {
+	for _, flavor := range Flavors() {
+		t.Run(string(flavor), func(t *testing.T) {
+			var out bytes.Buffer
+			err := Generate(&out, Options{
+				Flavor:               flavor,
+				ClusterName:          "prod",
+				Namespace:            "clusters",
+				KubernetesVersion:    "v1.33.4",
+				Region:               "US-east",
+				WorkerProduct:        "V94",
+				WorkerMachineCount:   4,
+				ControlPlaneEndpoint: "10.0.0.10",
+			})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			kinds := []string{}
+			for _, doc := range documents(t, out.Bytes()) {
+				kinds = append(kinds, doc["kind"].(string))
+				if doc["metadata"].(map[interface{}]interface{})["namespace"] != "clusters" {
+					t.Errorf("%s is not in the clusters namespace", doc["kind"])
+				}
+			}
+			want := []string{"Cluster", "Secret", "ContaboCluster", "KubeadmControlPlane", "ContaboMachineTemplate", "MachineDeployment", "ContaboMachineTemplate", "KubeadmConfigTemplate", "MachineHealthCheck"}
+			if strings.Join(kinds, ",") != strings.Join(want, ",") {
+				t.Errorf("kinds = %v, want %v", kinds, want)
+			}
+
+			manifests := out.String()
+			for _, want := range []string{"region: US-east", "productId: V91", "productId: V94", "replicas: 4", `host: "10.0.0.10"`} {
+				if !strings.Contains(manifests, want) {
+					t.Errorf("manifests do not contain %q", want)
+				}
+			}
+			replicas := "replicas: 3"
+			if flavor == FlavorDev {
+				replicas = "replicas: 1"
+			}
+			if !strings.Contains(manifests, replicas) {
+				t.Errorf("manifests do not contain %q", replicas)
+			}
+			if kubeVIP := strings.Contains(manifests, "/etc/kubernetes/manifests/kube-vip.yaml"); kubeVIP != (flavor != FlavorDev) {
+				t.Errorf("kube-vip rendered = %v for the %s flavor", kubeVIP, flavor)
+			}
+			if flavor == FlavorPrivate && !strings.Contains(manifests, "value: eth1") {
+				t.Errorf("kube-vip does not announce the virtual IP on the private network interface")
+			}
+		})
+	}
+}
+
+func TestGenerateValidatesOptions(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts Options
+		want string
+	}{
+		"unknown flavor":           {Options{Flavor: "edge"}, `unknown flavor "edge"`},
+		"missing endpoint":         {Options{Flavor: FlavorDev}, "the control plane endpoint is required"},
+		"kube-vip needs an IPv4":   {Options{Flavor: FlavorHA, ControlPlaneEndpoint: "api.example.com"}, "requires an IPv4 address"},
+		"even control plane":       {Options{Flavor: FlavorHA, ControlPlaneMachineCount: 2}, "must be odd"},
+		"dev with three machines":  {Options{Flavor: FlavorDev, ControlPlaneMachineCount: 3}, "single control plane machine"},
+		"unknown region":           {Options{Flavor: FlavorDev, Region: "eu"}, `unknown region "eu"`},
+		"invalid product":          {Options{Flavor: FlavorDev, WorkerProduct: "vps-s"}, `invalid product "vps-s"`},
+		"invalid encryption key":   {Options{Flavor: FlavorDev, EncryptionKey: "c2hvcnQ="}, "invalid encryption key"},
+		"missing version and name": {Options{Flavor: FlavorDev}, "invalid Kubernetes version"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Generate(io.Discard, tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Generate() error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
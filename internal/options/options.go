@@ -0,0 +1,341 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package options defines the options of the controller manager. Every option is a command line flag which can also
+// be set by the environment variable named after it, e.g. CONTABO_API_QPS for --contabo-api-qps, and the options are
+// enumerable so that deployment tooling, such as Helm values, can expose all of them.
+package options
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/controller"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/compat"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/retry"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/auth"
+)
+
+// Option describes an option of the controller manager
+type Option struct {
+	// Flag is the name of the command line flag of the option
+	Flag string
+	// EnvVar is the environment variable setting the option when the flag is not set
+	EnvVar string
+	// Usage describes the option
+	Usage string
+	// Default is the default value of the option, as set on the command line
+	Default string
+	// Sensitive is true for the credentials, which should be set from a Secret
+	Sensitive bool
+}
+
+// Credentials are the Contabo API credentials of the controller
+type Credentials = auth.Credentials
+
+// Options are the options of the controller manager
+type Options struct {
+	MetricsAddr               string
+	MetricsCertPath           string
+	MetricsCertName           string
+	MetricsCertKey            string
+	SecureMetrics             bool
+	ProbeAddr                 string
+	EnableHTTP2               bool
+	EnableWebhooks            bool
+	WebhookCertPath           string
+	WebhookCertName           string
+	WebhookCertKey            string
+	WebhookHost               string
+	WebhookPort               int
+	WebhookCertReloadInterval time.Duration
+	ManageCerts               bool
+	ManagedCertsDir           string
+	WebhookServiceName        string
+	MetricsServiceName        string
+
+	EnableLeaderElection        bool
+	LeaderElectionID            string
+	LeaderElectionLeaseDuration time.Duration
+	LeaderElectionRenewDeadline time.Duration
+	LeaderElectionRetryPeriod   time.Duration
+	ControllerNamespace         string
+	NodeName                    string
+
+	Credentials               Credentials
+	SecondaryCredentials      Credentials
+	CredentialsDir            string
+	CredentialsReloadInterval time.Duration
+	ContaboAPIQPS             float64
+	ContaboAPIBurst           int
+	ContaboAPIMaxRetries      int
+	ContaboAPIRetryDelay      time.Duration
+	ContaboAPIVersion         string
+	ContaboAPICompatibility   string
+
+	JobWorkers                  int
+	PrivateNetworkAssignWorkers int
+	ReadOnly                    bool
+	MigrateLegacyResources      bool
+	NotificationWebhookURL      string
+	NotificationWebhookFormat   string
+
+	fs       *flag.FlagSet
+	registry []Option
+}
+
+// EnvVar returns the environment variable of a flag, its name in upper case with underscores
+func EnvVar(flagName string) string {
+	return strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// New returns the options with their defaults, bound to the flags of fs
+func New(fs *flag.FlagSet) *Options {
+	o := &Options{fs: fs}
+
+	o.stringVar(&o.MetricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
+		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
+	o.stringVar(&o.MetricsCertPath, "metrics-cert-path", "",
+		"The directory that contains the metrics server certificate.")
+	o.stringVar(&o.MetricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
+	o.stringVar(&o.MetricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
+	o.boolVar(&o.SecureMetrics, "metrics-secure", true,
+		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
+	o.stringVar(&o.ProbeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	o.boolVar(&o.EnableHTTP2, "enable-http2", false,
+		"If set, HTTP/2 will be enabled for the metrics and webhook servers.")
+	o.boolVar(&o.EnableWebhooks, "enable-webhooks", true,
+		"If set, the admission webhooks are served. Use --enable-webhooks=false to run the controllers without them.")
+	o.stringVar(&o.WebhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
+	o.stringVar(&o.WebhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
+	o.stringVar(&o.WebhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
+	o.stringVar(&o.WebhookHost, "webhook-bind-host", "",
+		"The address the webhook server binds to. Default is all the addresses.")
+	o.intVar(&o.WebhookPort, "webhook-port", webhook.DefaultPort, "The port the webhook server listens on.")
+	o.durationVar(&o.WebhookCertReloadInterval, "webhook-cert-reload-interval", 10*time.Second,
+		"How often the webhook certificate of --webhook-cert-path is read again, in addition to the file change "+
+			"notifications, for certificates rotated on disk without cert-manager.")
+	o.boolVar(&o.ManageCerts, "manage-certs", false,
+		"If set, the manager provisions the webhook and metrics certificates with cert-manager itself, creating "+
+			"their Issuers and Certificates and injecting the CA into the webhook configurations, instead of the "+
+			"certificates mounted by the kustomize configuration. Requires cert-manager.")
+	o.stringVar(&o.ManagedCertsDir, "managed-certs-dir", "/tmp/k8s-managed-certs",
+		"The directory the certificates provisioned by --manage-certs are written to.")
+	o.stringVar(&o.WebhookServiceName, "webhook-service-name", "cluster-api-provider-contabo-webhook-service",
+		"The Service of the webhook server, the DNS name of the certificate provisioned by --manage-certs.")
+	o.stringVar(&o.MetricsServiceName, "metrics-service-name",
+		"cluster-api-provider-contabo-controller-manager-metrics-service",
+		"The Service of the metrics server, the DNS name of the certificate provisioned by --manage-certs.")
+
+	o.boolVar(&o.EnableLeaderElection, "leader-elect", false,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	o.stringVar(&o.LeaderElectionID, "leader-election-id", "",
+		"Leader election ID. If not specified, a stable ID is derived from the controller namespace.")
+	o.durationVar(&o.LeaderElectionLeaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"Interval at which non-leader candidates will wait to force acquire leadership (duration string). "+
+			"Raise it when the provider is self-hosted so that control plane restarts do not cause leadership flapping.")
+	o.durationVar(&o.LeaderElectionRenewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"Duration that the leading controller manager will retry refreshing leadership before giving up (duration string).")
+	o.durationVar(&o.LeaderElectionRetryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"Duration the LeaderElector clients should wait between tries of actions (duration string).")
+	o.stringVar(&o.ControllerNamespace, "controller-namespace", "",
+		"The namespace of the controller manager, holding its leader election lease and ConfigMaps. "+
+			"Default is the namespace of its service account.")
+	o.stringVar(&o.NodeName, "node-name", "",
+		"The node running the controller manager, its instance is never reset or reinstalled when the provider is "+
+			"self-hosted. Set from the downward API by the default deployment.")
+
+	o.secretVar(&o.Credentials.ClientID, "contabo-client-id", "The Contabo OAuth2 client ID.")
+	o.secretVar(&o.Credentials.ClientSecret, "contabo-client-secret", "The Contabo OAuth2 client secret.")
+	o.secretVar(&o.Credentials.APIUser, "contabo-api-user", "The Contabo API username.")
+	o.secretVar(&o.Credentials.APIPassword, "contabo-api-password", "The Contabo API password.")
+	o.secretVar(&o.SecondaryCredentials.ClientID, "contabo-secondary-client-id",
+		"The secondary Contabo OAuth2 client ID used for failover.")
+	o.secretVar(&o.SecondaryCredentials.ClientSecret, "contabo-secondary-client-secret",
+		"The secondary Contabo OAuth2 client secret used for failover.")
+	o.secretVar(&o.SecondaryCredentials.APIUser, "contabo-secondary-api-user",
+		"The secondary Contabo API username used for failover.")
+	o.secretVar(&o.SecondaryCredentials.APIPassword, "contabo-secondary-api-password",
+		"The secondary Contabo API password used for failover.")
+	o.stringVar(&o.CredentialsDir, "contabo-credentials-dir", "",
+		"If set, the directory the Contabo credentials are read from instead of the --contabo-*-id, -secret, -user and "+
+			"-password options, e.g. the contabo-credentials Secret mounted as a volume or the files rendered by the Vault "+
+			"agent, with the "+auth.ClientIDFile+", "+auth.ClientSecretFile+", "+auth.APIUserFile+" and "+auth.APIPasswordFile+
+			" files and the same files prefixed with "+auth.SecondaryFilePrefix+" for the secondary credentials. "+
+			"The rotated credentials are used without restarting the manager.")
+	o.durationVar(&o.CredentialsReloadInterval, "contabo-credentials-reload-interval", time.Minute,
+		"How often the files of --contabo-credentials-dir are read again to pick up the rotated credentials.")
+	o.float64Var(&o.ContaboAPIQPS, "contabo-api-qps", 10,
+		"The Contabo API requests per second of the account shared by the clusters, the waiting requests are served "+
+			"round robin across the clusters so that one cluster cannot starve the others. Set to 0 to disable.")
+	o.intVar(&o.ContaboAPIBurst, "contabo-api-burst", 20, "The Contabo API request burst of the account.")
+	o.intVar(&o.ContaboAPIMaxRetries, "contabo-api-max-retries", retry.DefaultMaxRetries,
+		"The number of retries of the Contabo API requests refused by the rate limit or failed by a transient error, "+
+			"honoring the Retry-After header of the responses. Set to 0 to disable.")
+	o.durationVar(&o.ContaboAPIRetryDelay, "contabo-api-retry-base-delay", retry.DefaultBaseDelay,
+		"The delay before the first retry of a Contabo API request, doubled with jitter for each following retry.")
+	o.stringVar(&o.ContaboAPIVersion, "contabo-api-version", "",
+		"If set, the Contabo API specification version the controller is pinned to, it refuses to start when its client "+
+			"is generated from another version (currently "+compat.SpecVersion+").")
+	o.stringVar(&o.ContaboAPICompatibility, "contabo-api-compatibility", string(compat.PolicyFail),
+		"What to do when an endpoint of the Contabo API required by the controllers answers differently than its client "+
+			"expects at startup: Fail (refuse to start), Warn (log and start) or Skip (do not check).")
+
+	o.intVar(&o.JobWorkers, "job-workers", controller.DefaultJobWorkers,
+		"The number of long-running jobs of the machines, such as snapshots, run at once.")
+	o.intVar(&o.PrivateNetworkAssignWorkers, "private-network-assign-workers", controller.DefaultPrivateNetworkAssignWorkers,
+		"The number of private network assignments sent at once across the private networks, Contabo processes a single "+
+			"assignment per private network at a time.")
+	o.boolVar(&o.ReadOnly, "read-only", false,
+		"If set, the controllers only observe the Contabo resources to report their status and conditions: the Contabo "+
+			"API requests changing them are refused and the deletions are postponed, to freeze the provider during incidents.")
+	o.boolVar(&o.MigrateLegacyResources, "migrate-legacy-resources", false,
+		"If set, the resources created by older provider versions are relabeled to the current scheme once before the "+
+			"controllers start, the completion is recorded in the "+controller.LegacyMigrationConfigMapName+" ConfigMap.")
+	o.stringVar(&o.NotificationWebhookURL, "notification-webhook-url", "",
+		"The HTTP endpoint the critical provider events (orphaned resources, credential failures, terminal machine "+
+			"failures) are posted to.")
+	o.stringVar(&o.NotificationWebhookFormat, "notification-webhook-format", string(infrastructurev1beta2.ContaboNotificationFormatGeneric),
+		"The payload format of the notification webhook, Generic (JSON object) or Slack (incoming webhook message).")
+
+	return o
+}
+
+// Registry returns the options in the order of their definition
+func (o *Options) Registry() []Option {
+	return append([]Option(nil), o.registry...)
+}
+
+// Parse parses the command line arguments, then sets the options whose flag is not set from their environment
+// variable, looked up with lookupEnv, e.g. os.LookupEnv. Empty environment variables are ignored.
+func (o *Options) Parse(args []string, lookupEnv func(string) (string, bool)) error {
+	if err := o.fs.Parse(args); err != nil {
+		return err
+	}
+	set := map[string]bool{}
+	o.fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	for _, option := range o.registry {
+		if set[option.Flag] {
+			continue
+		}
+		value, ok := lookupEnv(option.EnvVar)
+		if !ok || value == "" {
+			continue
+		}
+		if err := o.fs.Set(option.Flag, value); err != nil {
+			return fmt.Errorf("invalid value %q of the %s environment variable: %w", value, option.EnvVar, err)
+		}
+	}
+	return nil
+}
+
+// Validate returns the errors of the options
+func (o *Options) Validate() error {
+	var errs []error
+	switch {
+	case o.CredentialsDir != "":
+		// The credentials of the directory are checked when they are read
+		if !o.Credentials.IsEmpty() || !o.SecondaryCredentials.IsEmpty() {
+			errs = append(errs, errors.New("--contabo-credentials-dir replaces the other Contabo credentials options, "+
+				"unset them or their environment variables"))
+		}
+		if o.CredentialsReloadInterval <= 0 {
+			errs = append(errs, fmt.Errorf("--contabo-credentials-reload-interval must be positive, got %v", o.CredentialsReloadInterval))
+		}
+	case !o.Credentials.IsComplete():
+		errs = append(errs, errors.New("the Contabo OAuth2 credentials are required, set --contabo-client-id, "+
+			"--contabo-client-secret, --contabo-api-user and --contabo-api-password or their environment variables, "+
+			"or --contabo-credentials-dir"))
+	case !o.SecondaryCredentials.IsEmpty() && !o.SecondaryCredentials.IsComplete():
+		errs = append(errs, errors.New("the secondary Contabo OAuth2 credentials are incomplete, set all or none of "+
+			"the --contabo-secondary-* options"))
+	}
+	if o.ContaboAPIQPS < 0 {
+		errs = append(errs, fmt.Errorf("--contabo-api-qps must not be negative, got %v", o.ContaboAPIQPS))
+	}
+	if o.ContaboAPIMaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("--contabo-api-max-retries must not be negative, got %d", o.ContaboAPIMaxRetries))
+	}
+	if o.ContaboAPIRetryDelay <= 0 {
+		errs = append(errs, fmt.Errorf("--contabo-api-retry-base-delay must be positive, got %v", o.ContaboAPIRetryDelay))
+	}
+	if o.WebhookPort < 1 || o.WebhookPort > 65535 {
+		errs = append(errs, fmt.Errorf("--webhook-port must be between 1 and 65535, got %d", o.WebhookPort))
+	}
+	if o.ManageCerts && (o.WebhookCertPath != "" || o.MetricsCertPath != "") {
+		errs = append(errs, errors.New("--manage-certs provisions the certificates, unset --webhook-cert-path and "+
+			"--metrics-cert-path"))
+	}
+	switch compat.Policy(o.ContaboAPICompatibility) {
+	case compat.PolicyFail, compat.PolicyWarn, compat.PolicySkip:
+	default:
+		errs = append(errs, fmt.Errorf("unknown Contabo API compatibility policy %q, set --contabo-api-compatibility "+
+			"to Fail, Warn or Skip", o.ContaboAPICompatibility))
+	}
+	switch infrastructurev1beta2.ContaboNotificationFormat(o.NotificationWebhookFormat) {
+	case infrastructurev1beta2.ContaboNotificationFormatGeneric, infrastructurev1beta2.ContaboNotificationFormatSlack:
+	default:
+		errs = append(errs, fmt.Errorf("unknown notification webhook format %q, set --notification-webhook-format "+
+			"to Generic or Slack", o.NotificationWebhookFormat))
+	}
+	return errors.Join(errs...)
+}
+
+// define registers an option whose flag is bound by bind, the usage of the flag mentions its environment variable
+func (o *Options) define(name, usage string, sensitive bool, bind func(name, usage string)) {
+	envVar := EnvVar(name)
+	bind(name, fmt.Sprintf("%s Can also be set via the %s environment variable.", usage, envVar))
+	o.registry = append(o.registry, Option{
+		Flag:      name,
+		EnvVar:    envVar,
+		Usage:     usage,
+		Default:   o.fs.Lookup(name).DefValue,
+		Sensitive: sensitive,
+	})
+}
+
+func (o *Options) stringVar(p *string, name, value, usage string) {
+	o.define(name, usage, false, func(name, usage string) { o.fs.StringVar(p, name, value, usage) })
+}
+
+func (o *Options) secretVar(p *string, name, usage string) {
+	o.define(name, usage, true, func(name, usage string) { o.fs.StringVar(p, name, "", usage) })
+}
+
+func (o *Options) boolVar(p *bool, name string, value bool, usage string) {
+	o.define(name, usage, false, func(name, usage string) { o.fs.BoolVar(p, name, value, usage) })
+}
+
+func (o *Options) intVar(p *int, name string, value int, usage string) {
+	o.define(name, usage, false, func(name, usage string) { o.fs.IntVar(p, name, value, usage) })
+}
+
+func (o *Options) float64Var(p *float64, name string, value float64, usage string) {
+	o.define(name, usage, false, func(name, usage string) { o.fs.Float64Var(p, name, value, usage) })
+}
+
+func (o *Options) durationVar(p *time.Duration, name string, value time.Duration, usage string) {
+	o.define(name, usage, false, func(name, usage string) { o.fs.DurationVar(p, name, value, usage) })
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newOptions() *Options {
+	fs := flag.NewFlagSet("manager", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return New(fs)
+}
+
+func lookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+var credentialsEnv = map[string]string{
+	"CONTABO_CLIENT_ID":     "client",
+	"CONTABO_CLIENT_SECRET": "secret",
+	"CONTABO_API_USER":      "user@example.com",
+	"CONTABO_API_PASSWORD":  "password",
+}
+
+func TestRegistry(t *testing.T) {
+	o := newOptions()
+	envVars := map[string]string{}
+	sensitive := 0
+	for _, option := range o.Registry() {
+		if option.EnvVar != EnvVar(option.Flag) {
+			t.Errorf("option %s has environment variable %s, want %s", option.Flag, option.EnvVar, EnvVar(option.Flag))
+		}
+		if other, ok := envVars[option.EnvVar]; ok {
+			t.Errorf("options %s and %s share the environment variable %s", other, option.Flag, option.EnvVar)
+		}
+		envVars[option.EnvVar] = option.Flag
+		if option.Usage == "" {
+			t.Errorf("option %s has no usage", option.Flag)
+		}
+		if option.Sensitive {
+			sensitive++
+		}
+	}
+	if sensitive != 8 {
+		t.Errorf("sensitive options = %d, want the 8 primary and secondary credentials", sensitive)
+	}
+	for _, envVar := range []string{"CONTABO_CLIENT_ID", "CONTABO_SECONDARY_API_PASSWORD", "NOTIFICATION_WEBHOOK_URL",
+		"ENABLE_WEBHOOKS", "NODE_NAME", "CONTROLLER_NAMESPACE"} {
+		if _, ok := envVars[envVar]; !ok {
+			t.Errorf("environment variable %s is not bound to an option", envVar)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	env := map[string]string{
+		"CONTABO_API_QPS":             "2.5",
+		"LEADER_ELECT_LEASE_DURATION": "1m",
+		"ENABLE_WEBHOOKS":             "false",
+		"JOB_WORKERS":                 "4",
+		"READ_ONLY":                   "",
+	}
+	for key, value := range credentialsEnv {
+		env[key] = value
+	}
+	o := newOptions()
+	if err := o.Parse([]string{"--job-workers=8", "--contabo-api-user=flag@example.com"}, lookupEnv(env)); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if o.ContaboAPIQPS != 2.5 || o.LeaderElectionLeaseDuration != time.Minute || o.EnableWebhooks {
+		t.Errorf("options = %+v, want the environment variables applied", o)
+	}
+	if o.JobWorkers != 8 || o.Credentials.APIUser != "flag@example.com" {
+		t.Errorf("options = %+v, want the flags to take precedence over the environment variables", o)
+	}
+	if o.ReadOnly || !o.SecureMetrics || o.ContaboAPIBurst != 20 {
+		t.Errorf("options = %+v, want the defaults of the unset and empty environment variables", o)
+	}
+	if err := o.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := newOptions()
+	if err := invalid.Parse(nil, lookupEnv(map[string]string{"CONTABO_API_BURST": "many"})); err == nil ||
+		!strings.Contains(err.Error(), "CONTABO_API_BURST") {
+		t.Errorf("Parse() error = %v, want the invalid environment variable reported", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{name: "missing credentials", want: "credentials are required"},
+		{name: "incomplete secondary credentials", env: credentialsEnv, args: []string{"--contabo-secondary-client-id=other"}, want: "secondary"},
+		{name: "negative qps", env: credentialsEnv, args: []string{"--contabo-api-qps=-1"}, want: "contabo-api-qps"},
+		{name: "negative max retries", env: credentialsEnv, args: []string{"--contabo-api-max-retries=-1"}, want: "contabo-api-max-retries"},
+		{name: "zero retry delay", env: credentialsEnv, args: []string{"--contabo-api-retry-base-delay=0s"}, want: "contabo-api-retry-base-delay"},
+		{name: "unknown compatibility policy", env: credentialsEnv, args: []string{"--contabo-api-compatibility=Ignore"}, want: "compatibility"},
+		{name: "unknown notification format", env: credentialsEnv, args: []string{"--notification-webhook-format=Teams"}, want: "notification"},
+		{name: "managed and mounted certificates", env: credentialsEnv, args: []string{"--manage-certs", "--webhook-cert-path=/certs"}, want: "manage-certs"},
+		{name: "managed certificates", env: credentialsEnv, args: []string{"--manage-certs"}},
+		{name: "credentials directory", args: []string{"--contabo-credentials-dir=/var/run/secrets/contabo"}},
+		{name: "credentials directory and options", env: credentialsEnv, args: []string{"--contabo-credentials-dir=/var/run/secrets/contabo"}, want: "contabo-credentials-dir"},
+		{name: "zero credentials reload interval", args: []string{"--contabo-credentials-dir=/var/run/secrets/contabo", "--contabo-credentials-reload-interval=0s"}, want: "contabo-credentials-reload-interval"},
+		{name: "complete secondary credentials", env: credentialsEnv, args: []string{
+			"--contabo-secondary-client-id=c", "--contabo-secondary-client-secret=s",
+			"--contabo-secondary-api-user=u", "--contabo-secondary-api-password=p",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions()
+			if err := o.Parse(tt.args, lookupEnv(tt.env)); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			err := o.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version holds the build information of the provider, set at build time with
+// -ldflags "-X github.com/ctnr-io/cluster-api-provider-contabo/internal/version.gitVersion=<version> ...", and the
+// Cluster API versions it is compatible with.
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/compat"
+)
+
+const (
+	// ContractVersion is the Cluster API contract implemented by the provider
+	ContractVersion = "v1beta2"
+
+	// Annotation records the version of the controller which last reconciled a resource
+	Annotation = "infrastructure.cluster.x-k8s.io/controller-version"
+)
+
+// SupportedCAPIVersions are the Cluster API minor versions the provider is tested with
+var SupportedCAPIVersions = []string{"v1.11"}
+
+var (
+	gitVersion = "dev"
+	gitCommit  = ""
+	buildDate  = ""
+)
+
+// Info is the build information of the provider
+type Info struct {
+	GitVersion            string   `json:"gitVersion"`
+	GitCommit             string   `json:"gitCommit"`
+	BuildDate             string   `json:"buildDate"`
+	GoVersion             string   `json:"goVersion"`
+	Platform              string   `json:"platform"`
+	ContractVersion       string   `json:"contractVersion"`
+	ContaboAPIVersion     string   `json:"contaboAPIVersion"`
+	SupportedCAPIVersions []string `json:"supportedCAPIVersions"`
+}
+
+// Get returns the build information, the commit falls back to the VCS revision stamped by the Go toolchain when not
+// set at build time
+var Get = sync.OnceValue(get)
+
+func get() Info {
+	info := Info{
+		GitVersion:            gitVersion,
+		GitCommit:             gitCommit,
+		BuildDate:             buildDate,
+		GoVersion:             runtime.Version(),
+		Platform:              fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		ContractVersion:       ContractVersion,
+		ContaboAPIVersion:     compat.SpecVersion,
+		SupportedCAPIVersions: SupportedCAPIVersions,
+	}
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.GitCommit == "":
+				info.GitCommit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	if info.GitCommit == "" {
+		info.GitCommit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+// String returns the version and the short commit, the value of the controller version annotation
+func (i Info) String() string {
+	commit := i.GitCommit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	return fmt.Sprintf("%s+%s", i.GitVersion, commit)
+}
+
+// Stamp records the version of the controller in the annotations of a reconciled resource, so that fleet operators
+// can audit which provider versions manage which clusters
+func Stamp(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[Annotation] = Get().String()
+	obj.SetAnnotations(annotations)
+}
+
+// NewBuildInfoCollector returns the capc_build_info gauge, always 1, labelled with the build information
+func NewBuildInfoCollector() prometheus.Collector {
+	info := Get()
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "capc_build_info",
+		Help: "Build information of the Cluster API Provider Contabo, always 1",
+		ConstLabels: prometheus.Labels{
+			"version":                 info.GitVersion,
+			"git_commit":              info.GitCommit,
+			"build_date":              info.BuildDate,
+			"go_version":              info.GoVersion,
+			"contract_version":        info.ContractVersion,
+			"contabo_api_version":     info.ContaboAPIVersion,
+			"supported_capi_versions": strings.Join(info.SupportedCAPIVersions, ","),
+		},
+	})
+	buildInfo.Set(1)
+	return buildInfo
+}
+
+// Handler serves the build information as JSON
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Get())
+	})
+}
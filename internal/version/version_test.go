@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStamp(t *testing.T) {
+	obj := &metav1.ObjectMeta{Annotations: map[string]string{"other": "kept"}}
+	Stamp(obj)
+
+	if got := obj.Annotations[Annotation]; got != Get().String() || !strings.HasPrefix(got, Get().GitVersion+"+") {
+		t.Errorf("annotation = %q, want %q", got, Get().String())
+	}
+	if obj.Annotations["other"] != "kept" {
+		t.Errorf("annotations = %v, want the other annotations kept", obj.Annotations)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/version", nil))
+
+	info := Info{}
+	if err := json.NewDecoder(recorder.Body).Decode(&info); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if info.ContractVersion != ContractVersion || len(info.SupportedCAPIVersions) == 0 || info.GitCommit == "" {
+		t.Errorf("info = %+v, want the contract, supported Cluster API versions and commit", info)
+	}
+}
+
+func TestBuildInfoCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewBuildInfoCollector())
+
+	expected := `# HELP capc_build_info Build information of the Cluster API Provider Contabo, always 1
+# TYPE capc_build_info gauge
+`
+	info := Get()
+	expected += `capc_build_info{build_date="` + info.BuildDate + `",contabo_api_version="1.0.0",contract_version="v1beta2",git_commit="` + info.GitCommit +
+		`",go_version="` + info.GoVersion + `",supported_capi_versions="v1.11",version="` + info.GitVersion + `"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "capc_build_info"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var cataloglog = logf.Log.WithName("catalog")
+
+// CatalogFreshness is the time a ContaboCatalog or ContaboAccountInventory refresh is trusted to deny the references
+// it does not list, the references missing from older ones are only warned about as the controllers may have stopped
+// refreshing them
+const CatalogFreshness = 24 * time.Hour
+
+// catalog holds the regions, products and images the references of the resources are validated against. The
+// enumerations of the API types are completed with the ContaboCatalog and the custom images of the
+// ContaboAccountInventory once the controllers collected them, read from the manager cache so that admission never
+// calls the Contabo API. The references are only denied when the enumerations collected within the CatalogFreshness
+// do not list them, they are warned about before and once the enumerations are stale.
+type catalog struct {
+	// regions are the regions of the ContaboCatalog, nil until collected
+	regions []infrastructurev1beta2.ContaboRegion
+	// products are the products of the ContaboCatalog, nil until collected
+	products []infrastructurev1beta2.ContaboProductId
+	// images are the standard images of the ContaboCatalog and the custom images of the ContaboAccountInventory
+	images []string
+	// productsUpdated is the last refresh of the products, nil until collected
+	productsUpdated *time.Time
+	// imagesUpdated is the oldest refresh of the standard and the custom images, nil until both are collected
+	imagesUpdated *time.Time
+}
+
+// loadCatalog reads the ContaboCatalog and the ContaboAccountInventory, the missing ones are left out
+func loadCatalog(ctx context.Context, reader client.Reader) *catalog {
+	c := &catalog{}
+	if reader == nil {
+		return c
+	}
+
+	var standardImages *time.Time
+	contaboCatalog := &infrastructurev1beta2.ContaboCatalog{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: infrastructurev1beta2.ContaboCatalogName}, contaboCatalog); err != nil {
+		cataloglog.V(1).Info("ContaboCatalog not found, only the built-in enumerations are checked", "error", err.Error())
+	} else {
+		c.regions = contaboCatalog.Status.Regions
+		for _, product := range contaboCatalog.Status.Products {
+			c.products = append(c.products, product.ProductId)
+		}
+		for _, image := range contaboCatalog.Status.Images {
+			c.images = append(c.images, image.ImageId)
+		}
+		if updated := contaboCatalog.Status.LastUpdated; updated != nil {
+			if len(c.products) > 0 {
+				c.productsUpdated = &updated.Time
+			}
+			if len(contaboCatalog.Status.Images) > 0 {
+				standardImages = &updated.Time
+			}
+		}
+	}
+
+	inventory := &infrastructurev1beta2.ContaboAccountInventory{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: infrastructurev1beta2.ContaboAccountInventoryName}, inventory); err != nil {
+		cataloglog.V(1).Info("ContaboAccountInventory not found, the custom images are not checked", "error", err.Error())
+	} else {
+		for _, image := range inventory.Status.Images {
+			c.images = append(c.images, image.Id)
+		}
+		if updated := inventory.Status.LastUpdated; standardImages != nil && updated != nil {
+			c.imagesUpdated = standardImages
+			if updated.Time.Before(*standardImages) {
+				c.imagesUpdated = &updated.Time
+			}
+		}
+	}
+	return c
+}
+
+// validateRegion denies the regions the ContaboCatalog does not list, e.g. without a data center
+func (c *catalog) validateRegion(region infrastructurev1beta2.ContaboRegion, fldPath *field.Path) field.ErrorList {
+	if !slices.Contains(infrastructurev1beta2.ContaboRegions(), region) {
+		return field.ErrorList{field.NotSupported(fldPath, region, infrastructurev1beta2.ContaboRegions())}
+	}
+	if len(c.regions) > 0 && !slices.Contains(c.regions, region) {
+		return field.ErrorList{field.NotSupported(fldPath, region, c.regions)}
+	}
+	return nil
+}
+
+// validateProduct denies the products neither built-in nor listed by the ContaboCatalog, they are only warned about
+// while the ContaboCatalog is not collected or stale
+func (c *catalog) validateProduct(productId infrastructurev1beta2.ContaboProductId, fldPath *field.Path) (admission.Warnings, field.ErrorList) {
+	if slices.Contains(infrastructurev1beta2.ContaboProducts(), productId) ||
+		slices.Contains(infrastructurev1beta2.ContaboDeprecatedProducts(), productId) ||
+		slices.Contains(c.products, productId) {
+		return nil, nil
+	}
+	if c.productsUpdated == nil {
+		return admission.Warnings{fmt.Sprintf("product %s is not in the known Contabo catalog, check it is orderable", productId)}, nil
+	}
+	if time.Since(*c.productsUpdated) >= CatalogFreshness {
+		return admission.Warnings{fmt.Sprintf("product %s is not in the ContaboCatalog %s refreshed more than %s ago, check it is orderable",
+			productId, infrastructurev1beta2.ContaboCatalogName, CatalogFreshness)}, nil
+	}
+	return nil, field.ErrorList{field.Invalid(fldPath, productId,
+		fmt.Sprintf("product is not in the ContaboCatalog %s", infrastructurev1beta2.ContaboCatalogName))}
+}
+
+// validateImage denies the images which are neither the default image, a standard image of the ContaboCatalog nor
+// a custom image of the ContaboAccountInventory, they are only warned about while the images are not collected or
+// stale
+func (c *catalog) validateImage(imageId string, fldPath *field.Path) (admission.Warnings, field.ErrorList) {
+	if imageId == infrastructurev1beta2.DefaultImageId || slices.Contains(c.images, imageId) {
+		return nil, nil
+	}
+	if c.imagesUpdated == nil {
+		return admission.Warnings{fmt.Sprintf("image %s is not in the known Contabo images, check it exists and is an Ubuntu image with cloud-init", imageId)}, nil
+	}
+	if time.Since(*c.imagesUpdated) >= CatalogFreshness {
+		return admission.Warnings{fmt.Sprintf("image %s is not in the Contabo images refreshed more than %s ago, check it exists and is an Ubuntu image with cloud-init",
+			imageId, CatalogFreshness)}, nil
+	}
+	return nil, field.ErrorList{field.Invalid(fldPath, imageId,
+		fmt.Sprintf("image is neither a standard image of the ContaboCatalog %s nor a custom image of the ContaboAccountInventory %s",
+			infrastructurev1beta2.ContaboCatalogName, infrastructurev1beta2.ContaboAccountInventoryName))}
+}
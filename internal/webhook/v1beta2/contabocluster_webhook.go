@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var contaboclusterlog = logf.Log.WithName("contabocluster-resource")
+
+// SetupContaboClusterWebhookWithManager registers the webhooks for ContaboCluster in the manager.
+func SetupContaboClusterWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&infrastructurev1beta2.ContaboCluster{}).
+		WithDefaulter(&ContaboClusterCustomDefaulter{}).
+		WithValidator(&ContaboClusterCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta2-contabocluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=contaboclusters,verbs=create;update,versions=v1beta2,name=mcontabocluster-v1beta2.kb.io,admissionReviewVersions=v1
+
+// ContaboClusterCustomDefaulter defaults the region of the private network of the ContaboClusters.
+type ContaboClusterCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &ContaboClusterCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type ContaboCluster.
+func (d *ContaboClusterCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	contaboCluster, ok := obj.(*infrastructurev1beta2.ContaboCluster)
+	if !ok {
+		return fmt.Errorf("expected a ContaboCluster object but got %T", obj)
+	}
+	contaboclusterlog.Info("Defaulting for ContaboCluster", "name", contaboCluster.GetName())
+
+	if contaboCluster.Spec.PrivateNetwork.Region == "" {
+		contaboCluster.Spec.PrivateNetwork.Region = infrastructurev1beta2.ContaboRegionEU
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta2-contabocluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=contaboclusters,verbs=create;update,versions=v1beta2,name=vcontabocluster-v1beta2.kb.io,admissionReviewVersions=v1
+
+// ContaboClusterCustomValidator validates the ContaboClusters when they are created or updated. The regions are
+// checked against the regions of the ContaboCatalog, see catalog, and the region of the private network cannot
+// change once set as the private network and the machines attached to it cannot move to another region.
+type ContaboClusterCustomValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &ContaboClusterCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ContaboCluster.
+func (v *ContaboClusterCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	contaboCluster, ok := obj.(*infrastructurev1beta2.ContaboCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboCluster object but got %T", obj)
+	}
+	contaboclusterlog.Info("Validation for ContaboCluster upon creation", "name", contaboCluster.GetName())
+
+	return nil, contaboClusterInvalid(contaboCluster, v.validateRegions(ctx, nil, contaboCluster))
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ContaboCluster.
+func (v *ContaboClusterCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCluster, ok := oldObj.(*infrastructurev1beta2.ContaboCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboCluster object for the oldObj but got %T", oldObj)
+	}
+	contaboCluster, ok := newObj.(*infrastructurev1beta2.ContaboCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboCluster object for the newObj but got %T", newObj)
+	}
+	contaboclusterlog.Info("Validation for ContaboCluster upon update", "name", contaboCluster.GetName())
+
+	oldRegion, region := oldCluster.Spec.PrivateNetwork.Region, contaboCluster.Spec.PrivateNetwork.Region
+	if oldRegion != "" && region != oldRegion {
+		return nil, contaboClusterInvalid(contaboCluster, field.ErrorList{
+			field.Invalid(field.NewPath("spec", "privateNetwork", "region"), region, "field is immutable"),
+		})
+	}
+	return nil, contaboClusterInvalid(contaboCluster, v.validateRegions(ctx, oldCluster, contaboCluster))
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ContaboCluster.
+func (v *ContaboClusterCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateRegions checks the region of the private network and the failure domains against the catalog, only the
+// regions added by an update are checked so that a cluster is not rejected once the catalog drops one of them
+func (v *ContaboClusterCustomValidator) validateRegions(ctx context.Context, oldCluster, contaboCluster *infrastructurev1beta2.ContaboCluster) field.ErrorList {
+	var oldRegions []infrastructurev1beta2.ContaboRegion
+	if oldCluster != nil {
+		oldRegions = append(oldRegions, oldCluster.Spec.PrivateNetwork.Region)
+		if oldCluster.Spec.Placement != nil {
+			oldRegions = append(oldRegions, oldCluster.Spec.Placement.FailureDomains...)
+		}
+	}
+
+	c := loadCatalog(ctx, v.Client)
+	var allErrs field.ErrorList
+	if region := contaboCluster.Spec.PrivateNetwork.Region; !slices.Contains(oldRegions, region) {
+		allErrs = append(allErrs, c.validateRegion(region, field.NewPath("spec", "privateNetwork", "region"))...)
+	}
+	if contaboCluster.Spec.Placement != nil {
+		for i, region := range contaboCluster.Spec.Placement.FailureDomains {
+			if !slices.Contains(oldRegions, region) {
+				allErrs = append(allErrs, c.validateRegion(region, field.NewPath("spec", "placement", "failureDomains").Index(i))...)
+			}
+		}
+	}
+	return allErrs
+}
+
+// contaboClusterInvalid returns the Invalid error of the cluster, nil without errors
+func contaboClusterInvalid(contaboCluster *infrastructurev1beta2.ContaboCluster, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(infrastructurev1beta2.GroupVersion.WithKind("ContaboCluster").GroupKind(), contaboCluster.Name, allErrs)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var _ = Describe("ContaboCluster Webhook", func() {
+	var (
+		contaboCluster *infrastructurev1beta2.ContaboCluster
+		contaboCatalog *infrastructurev1beta2.ContaboCatalog
+	)
+
+	BeforeEach(func() {
+		contaboCluster = &infrastructurev1beta2.ContaboCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		}
+		contaboCatalog = &infrastructurev1beta2.ContaboCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: infrastructurev1beta2.ContaboCatalogName},
+			Status: infrastructurev1beta2.ContaboCatalogStatus{
+				Regions: []infrastructurev1beta2.ContaboRegion{infrastructurev1beta2.ContaboRegionEU, infrastructurev1beta2.ContaboRegion("US-central")},
+			},
+		}
+	})
+
+	newValidator := func(objects ...client.Object) *ContaboClusterCustomValidator {
+		return &ContaboClusterCustomValidator{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+	}
+
+	It("should default the region of the private network", func() {
+		Expect((&ContaboClusterCustomDefaulter{}).Default(context.Background(), contaboCluster)).To(Succeed())
+		Expect(contaboCluster.Spec.PrivateNetwork.Region).To(Equal(infrastructurev1beta2.ContaboRegionEU))
+	})
+
+	It("should reject the regions missing from the ContaboCatalog", func() {
+		contaboCluster.Spec.PrivateNetwork.Region = infrastructurev1beta2.ContaboRegionEU
+		contaboCluster.Spec.Placement = &infrastructurev1beta2.ContaboPlacementSpec{
+			FailureDomains: []infrastructurev1beta2.ContaboRegion{infrastructurev1beta2.ContaboRegionEU, "UK"},
+		}
+		_, err := newValidator().ValidateCreate(context.Background(), contaboCluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = newValidator(contaboCatalog).ValidateCreate(context.Background(), contaboCluster)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.placement.failureDomains[1]"))
+		Expect(err.Error()).NotTo(ContainSubstring("spec.privateNetwork.region"))
+	})
+
+	It("should reject the unknown regions", func() {
+		contaboCluster.Spec.PrivateNetwork.Region = "Mars"
+		_, err := newValidator().ValidateCreate(context.Background(), contaboCluster)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.privateNetwork.region"))
+	})
+
+	It("should reject a change of the region of the private network", func() {
+		contaboCluster.Spec.PrivateNetwork.Region = infrastructurev1beta2.ContaboRegionEU
+		updated := contaboCluster.DeepCopy()
+		updated.Spec.PrivateNetwork.Region = "US-central"
+		_, err := newValidator(contaboCatalog).ValidateUpdate(context.Background(), contaboCluster, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("field is immutable"))
+	})
+
+	It("should admit updates keeping a region the ContaboCatalog dropped since", func() {
+		contaboCluster.Spec.PrivateNetwork.Region = "UK"
+		updated := contaboCluster.DeepCopy()
+		updated.Spec.Placement = &infrastructurev1beta2.ContaboPlacementSpec{
+			FailureDomains: []infrastructurev1beta2.ContaboRegion{"UK", "US-central"},
+		}
+		_, err := newValidator(contaboCatalog).ValidateUpdate(context.Background(), contaboCluster, updated)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
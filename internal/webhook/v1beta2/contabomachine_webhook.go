@@ -0,0 +1,214 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var contabomachinelog = logf.Log.WithName("contabomachine-resource")
+
+// SetupContaboMachineWebhookWithManager registers the webhooks for ContaboMachine in the manager.
+func SetupContaboMachineWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&infrastructurev1beta2.ContaboMachine{}).
+		WithDefaulter(&ContaboMachineCustomDefaulter{}).
+		WithValidator(&ContaboMachineCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta2-contabomachine,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=contabomachines,verbs=create,versions=v1beta2,name=mcontabomachine-v1beta2.kb.io,admissionReviewVersions=v1
+
+// ContaboMachineCustomDefaulter defaults the image and product of the ContaboMachines when they are created, so
+// that the instance a machine is created with is recorded in its spec and can be made immutable.
+type ContaboMachineCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &ContaboMachineCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type ContaboMachine.
+func (d *ContaboMachineCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	contaboMachine, ok := obj.(*infrastructurev1beta2.ContaboMachine)
+	if !ok {
+		return fmt.Errorf("expected a ContaboMachine object but got %T", obj)
+	}
+	// The machines moved by clusterctl or restored from a backup keep the instance they already have
+	if request, err := admission.RequestFromContext(ctx); err == nil && request.Operation != admissionv1.Create {
+		return nil
+	}
+	if contaboMachine.Spec.ProviderID != nil {
+		return nil
+	}
+	contabomachinelog.Info("Defaulting for ContaboMachine", "name", contaboMachine.GetName())
+
+	instance := &contaboMachine.Spec.Instance
+	if instance.ImageId == nil {
+		instance.ImageId = ptr.To(infrastructurev1beta2.DefaultImageId)
+	}
+	// The product of a reused instance is the product it was ordered with, it is left empty to reuse any product
+	reuse := instance.Name != nil || ptr.Deref(instance.ProvisioningType, "") == infrastructurev1beta2.ContaboInstanceProvisioningTypeReuseOnly
+	if instance.ProductId == nil && !reuse {
+		instance.ProductId = ptr.To(infrastructurev1beta2.DefaultProductId)
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta2-contabomachine,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=contabomachines,verbs=create;update,versions=v1beta2,name=vcontabomachine-v1beta2.kb.io,admissionReviewVersions=v1
+
+// ContaboMachineCustomValidator validates the ContaboMachines when they are created or updated. The product,
+// image and failure domain are checked against the catalog collected by the controllers, see catalog, and the
+// product and image cannot change once set as the instance is not reinstalled nor upgraded to follow them, except
+// the image rolled out in place from the template of the machine, see rolledOutImage.
+type ContaboMachineCustomValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &ContaboMachineCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ContaboMachine.
+func (v *ContaboMachineCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	contaboMachine, ok := obj.(*infrastructurev1beta2.ContaboMachine)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboMachine object but got %T", obj)
+	}
+	contabomachinelog.Info("Validation for ContaboMachine upon creation", "name", contaboMachine.GetName())
+
+	warnings, allErrs := v.validate(ctx, contaboMachine)
+	return warnings, contaboMachineInvalid(contaboMachine, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ContaboMachine.
+func (v *ContaboMachineCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldMachine, ok := oldObj.(*infrastructurev1beta2.ContaboMachine)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboMachine object for the oldObj but got %T", oldObj)
+	}
+	contaboMachine, ok := newObj.(*infrastructurev1beta2.ContaboMachine)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboMachine object for the newObj but got %T", newObj)
+	}
+	contabomachinelog.Info("Validation for ContaboMachine upon update", "name", contaboMachine.GetName())
+
+	instancePath := field.NewPath("spec", "instance")
+	oldInstance, instance := oldMachine.Spec.Instance, contaboMachine.Spec.Instance
+	var allErrs field.ErrorList
+	if oldInstance.ProductId != nil && ptr.Deref(instance.ProductId, "") != *oldInstance.ProductId {
+		allErrs = append(allErrs, field.Invalid(instancePath.Child("productId"), ptr.Deref(instance.ProductId, ""), "field is immutable"))
+	}
+	if oldInstance.ImageId != nil && ptr.Deref(instance.ImageId, "") != *oldInstance.ImageId && !v.rolledOutImage(ctx, contaboMachine) {
+		allErrs = append(allErrs, field.Invalid(instancePath.Child("imageId"), ptr.Deref(instance.ImageId, ""), "field is immutable"))
+	}
+	if len(allErrs) > 0 {
+		return nil, contaboMachineInvalid(contaboMachine, allErrs)
+	}
+
+	// The references are checked again only when they change, a machine is not rejected once the catalog drops
+	// the product or image it was created with
+	if oldInstance.ProductId != nil {
+		instance.ProductId = nil
+	}
+	if oldInstance.ImageId != nil {
+		instance.ImageId = nil
+	}
+	contaboMachine = contaboMachine.DeepCopy()
+	contaboMachine.Spec.Instance = instance
+	if ptr.Equal(oldMachine.Spec.FailureDomain, contaboMachine.Spec.FailureDomain) {
+		contaboMachine.Spec.FailureDomain = nil
+	}
+	warnings, allErrs := v.validate(ctx, contaboMachine)
+	return warnings, contaboMachineInvalid(contaboMachine, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ContaboMachine.
+func (v *ContaboMachineCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// rolledOutImage reports whether the image of the machine is the image of the ContaboMachineTemplate it was cloned
+// from, rolled out with the ReinstallInPlace strategy: the ContaboMachine controller copies it to the worker machines
+// and reinstalls their instance with it
+func (v *ContaboMachineCustomValidator) rolledOutImage(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) bool {
+	name := contaboMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation]
+	groupKind := contaboMachine.Annotations[clusterv1.TemplateClonedFromGroupKindAnnotation]
+	if v.Client == nil || name == "" || groupKind != infrastructurev1beta2.GroupVersion.WithKind("ContaboMachineTemplate").GroupKind().String() {
+		return false
+	}
+	if _, controlPlane := contaboMachine.Labels[clusterv1.MachineControlPlaneLabel]; controlPlane {
+		return false
+	}
+	template := &infrastructurev1beta2.ContaboMachineTemplate{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: contaboMachine.Namespace, Name: name}, template); err != nil {
+		contabomachinelog.V(1).Info("ContaboMachineTemplate of the ContaboMachine not found", "template", name, "error", err.Error())
+		return false
+	}
+	if !ptr.Equal(template.Spec.Template.Spec.Instance.ImageId, contaboMachine.Spec.Instance.ImageId) {
+		return false
+	}
+	if template.Spec.RolloutStrategy != nil {
+		return *template.Spec.RolloutStrategy == infrastructurev1beta2.ContaboRolloutStrategyReinstallInPlace
+	}
+	_, contaboCluster := owningClusters(ctx, v.Client, contaboMachine)
+	return contaboCluster != nil && contaboCluster.Spec.RolloutStrategy == infrastructurev1beta2.ContaboRolloutStrategyReinstallInPlace
+}
+
+// validate checks the references of the machine against the catalog and the node registration
+func (v *ContaboMachineCustomValidator) validate(ctx context.Context, contaboMachine *infrastructurev1beta2.ContaboMachine) (admission.Warnings, field.ErrorList) {
+	var warnings admission.Warnings
+	specPath := field.NewPath("spec")
+	instancePath := specPath.Child("instance")
+	instance := contaboMachine.Spec.Instance
+	c := loadCatalog(ctx, v.Client)
+
+	allErrs := validateNodeRegistration(contaboMachine.Spec, specPath)
+	allErrs = append(allErrs, validateDNS(contaboMachine.Spec.DNS, specPath.Child("dns"))...)
+	allErrs = append(allErrs, validateAddOns(instance, instancePath)...)
+	if instance.ProductId != nil {
+		productWarnings, productErrs := c.validateProduct(*instance.ProductId, instancePath.Child("productId"))
+		warnings = append(warnings, productWarnings...)
+		allErrs = append(allErrs, productErrs...)
+	}
+	if instance.ImageId != nil {
+		imageWarnings, imageErrs := c.validateImage(*instance.ImageId, instancePath.Child("imageId"))
+		warnings = append(warnings, imageWarnings...)
+		allErrs = append(allErrs, imageErrs...)
+	}
+	if contaboMachine.Spec.FailureDomain != nil {
+		allErrs = append(allErrs, c.validateRegion(infrastructurev1beta2.ContaboRegion(*contaboMachine.Spec.FailureDomain), specPath.Child("failureDomain"))...)
+	}
+	return warnings, allErrs
+}
+
+// contaboMachineInvalid returns the Invalid error of the machine, nil without errors
+func contaboMachineInvalid(contaboMachine *infrastructurev1beta2.ContaboMachine, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(infrastructurev1beta2.GroupVersion.WithKind("ContaboMachine").GroupKind(), contaboMachine.Name, allErrs)
+}
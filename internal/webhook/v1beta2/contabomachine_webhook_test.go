@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+const customImageId = "0d8c6d4e-3c2a-4b1e-9f5a-7e6d5c4b3a21"
+
+var _ = Describe("ContaboMachine Webhook", func() {
+	var (
+		contaboMachine *infrastructurev1beta2.ContaboMachine
+		contaboCatalog *infrastructurev1beta2.ContaboCatalog
+		inventory      *infrastructurev1beta2.ContaboAccountInventory
+	)
+
+	BeforeEach(func() {
+		contaboMachine = &infrastructurev1beta2.ContaboMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "default"},
+		}
+		contaboCatalog = &infrastructurev1beta2.ContaboCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: infrastructurev1beta2.ContaboCatalogName},
+			Status: infrastructurev1beta2.ContaboCatalogStatus{
+				Regions:     []infrastructurev1beta2.ContaboRegion{infrastructurev1beta2.ContaboRegionEU},
+				Products:    []infrastructurev1beta2.ContaboCatalogProduct{{ProductId: "V200"}},
+				Images:      []infrastructurev1beta2.ContaboCatalogImage{{ImageId: infrastructurev1beta2.DefaultImageId}},
+				LastUpdated: ptr.To(metav1.Now()),
+			},
+		}
+		inventory = &infrastructurev1beta2.ContaboAccountInventory{
+			ObjectMeta: metav1.ObjectMeta{Name: infrastructurev1beta2.ContaboAccountInventoryName},
+			Status: infrastructurev1beta2.ContaboAccountInventoryStatus{
+				LastUpdated: ptr.To(metav1.Now()),
+				Images:      []infrastructurev1beta2.ContaboInventoryItem{{Id: customImageId, Name: "ubuntu-hardened"}},
+			},
+		}
+	})
+
+	newValidator := func(objects ...client.Object) *ContaboMachineCustomValidator {
+		return &ContaboMachineCustomValidator{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+	}
+
+	Context("When defaulting a ContaboMachine", func() {
+		createContext := admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+		})
+
+		It("should default the image and the product of a new instance", func() {
+			Expect((&ContaboMachineCustomDefaulter{}).Default(createContext, contaboMachine)).To(Succeed())
+			Expect(contaboMachine.Spec.Instance.ImageId).To(Equal(ptr.To(infrastructurev1beta2.DefaultImageId)))
+			Expect(contaboMachine.Spec.Instance.ProductId).To(Equal(ptr.To(infrastructurev1beta2.DefaultProductId)))
+		})
+
+		It("should keep the given image and product", func() {
+			contaboMachine.Spec.Instance.ImageId = ptr.To(customImageId)
+			contaboMachine.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductCloudVPS20NVMe)
+			Expect((&ContaboMachineCustomDefaulter{}).Default(createContext, contaboMachine)).To(Succeed())
+			Expect(contaboMachine.Spec.Instance.ImageId).To(Equal(ptr.To(customImageId)))
+			Expect(contaboMachine.Spec.Instance.ProductId).To(Equal(ptr.To(infrastructurev1beta2.ContaboProductCloudVPS20NVMe)))
+		})
+
+		It("should not default the product of a reused instance", func() {
+			contaboMachine.Spec.Instance.ProvisioningType = ptr.To(infrastructurev1beta2.ContaboInstanceProvisioningTypeReuseOnly)
+			Expect((&ContaboMachineCustomDefaulter{}).Default(createContext, contaboMachine)).To(Succeed())
+			Expect(contaboMachine.Spec.Instance.ProductId).To(BeNil())
+			Expect(contaboMachine.Spec.Instance.ImageId).NotTo(BeNil())
+		})
+
+		It("should not default the machines which already have an instance", func() {
+			contaboMachine.Spec.ProviderID = ptr.To("contabo://12345")
+			Expect((&ContaboMachineCustomDefaulter{}).Default(createContext, contaboMachine)).To(Succeed())
+			Expect(contaboMachine.Spec.Instance.ImageId).To(BeNil())
+			Expect(contaboMachine.Spec.Instance.ProductId).To(BeNil())
+		})
+	})
+
+	Context("When validating a ContaboMachine", func() {
+		It("should only warn about unknown references while the catalog is not collected", func() {
+			contaboMachine.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductId("V1"))
+			contaboMachine.Spec.Instance.ImageId = ptr.To(customImageId)
+			warnings, err := newValidator().ValidateCreate(context.Background(), contaboMachine)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("product V1"), ContainSubstring("image "+customImageId)))
+		})
+
+		It("should admit the references of the catalog and the custom images", func() {
+			contaboMachine.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductId("V200"))
+			contaboMachine.Spec.Instance.ImageId = ptr.To(customImageId)
+			contaboMachine.Spec.FailureDomain = ptr.To("EU")
+			warnings, err := newValidator(contaboCatalog, inventory).ValidateCreate(context.Background(), contaboMachine)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should reject the references missing from the collected catalog", func() {
+			contaboMachine.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductId("V1"))
+			contaboMachine.Spec.Instance.ImageId = ptr.To("ffffffff-ffff-ffff-ffff-ffffffffffff")
+			contaboMachine.Spec.FailureDomain = ptr.To("UK")
+			_, err := newValidator(contaboCatalog, inventory).ValidateCreate(context.Background(), contaboMachine)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.instance.productId"))
+			Expect(err.Error()).To(ContainSubstring("spec.instance.imageId"))
+			Expect(err.Error()).To(ContainSubstring("spec.failureDomain"))
+		})
+
+		It("should only warn about the references missing from a stale catalog", func() {
+			contaboMachine.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductId("V1"))
+			contaboMachine.Spec.Instance.ImageId = ptr.To("ffffffff-ffff-ffff-ffff-ffffffffffff")
+			contaboCatalog.Status.LastUpdated = ptr.To(metav1.NewTime(time.Now().Add(-CatalogFreshness)))
+			warnings, err := newValidator(contaboCatalog, inventory).ValidateCreate(context.Background(), contaboMachine)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("product V1"), ContainSubstring("image ffffffff-ffff-ffff-ffff-ffffffffffff")))
+
+			By("Warning about the images while the custom images are stale")
+			contaboCatalog.Status.LastUpdated = ptr.To(metav1.Now())
+			inventory.Status.LastUpdated = ptr.To(metav1.NewTime(time.Now().Add(-CatalogFreshness)))
+			warnings, err = newValidator(contaboCatalog, inventory).ValidateCreate(context.Background(), contaboMachine)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.instance.productId"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.instance.imageId"))
+			Expect(warnings).To(ConsistOf(ContainSubstring("image ffffffff-ffff-ffff-ffff-ffffffffffff")))
+		})
+
+		It("should reject the private networking add-on", func() {
+			contaboMachine.Spec.Instance.AddOns = []infrastructurev1beta2.ContaboAddOnSpec{
+				{Id: infrastructurev1beta2.ContaboPrivateNetworkingAddOnId, Quantity: 1},
+			}
+			_, err := newValidator().ValidateCreate(context.Background(), contaboMachine)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.instance.addOns[0].id"))
+		})
+
+		It("should reject changes of the product and the image", func() {
+			contaboMachine.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductCloudVPS10NVMe)
+			contaboMachine.Spec.Instance.ImageId = ptr.To(infrastructurev1beta2.DefaultImageId)
+			updated := contaboMachine.DeepCopy()
+			updated.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductCloudVPS20NVMe)
+			updated.Spec.Instance.ImageId = nil
+			_, err := newValidator().ValidateUpdate(context.Background(), contaboMachine, updated)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.instance.productId: Invalid value: \"V94\": field is immutable"))
+			Expect(err.Error()).To(ContainSubstring("spec.instance.imageId"))
+		})
+
+		It("should admit the image rolled out in place from the template of a worker machine", func() {
+			contaboMachine.Labels = map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}
+			contaboMachine.Annotations = map[string]string{
+				clusterv1.TemplateClonedFromNameAnnotation:      "workers",
+				clusterv1.TemplateClonedFromGroupKindAnnotation: "ContaboMachineTemplate.infrastructure.cluster.x-k8s.io",
+			}
+			contaboMachine.Spec.Instance.ImageId = ptr.To(customImageId)
+			template := &infrastructurev1beta2.ContaboMachineTemplate{ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: "default"}}
+			template.Spec.Template.Spec.Instance.ImageId = ptr.To(infrastructurev1beta2.DefaultImageId)
+			updated := contaboMachine.DeepCopy()
+			updated.Spec.Instance.ImageId = ptr.To(infrastructurev1beta2.DefaultImageId)
+
+			// The Replace rollout strategy leaves the image of the existing machines as is
+			_, err := newValidator(contaboCatalog, inventory, template).ValidateUpdate(context.Background(), contaboMachine, updated)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.instance.imageId"))
+
+			template.Spec.RolloutStrategy = ptr.To(infrastructurev1beta2.ContaboRolloutStrategyReinstallInPlace)
+			_, err = newValidator(contaboCatalog, inventory, template).ValidateUpdate(context.Background(), contaboMachine, updated)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The control plane machines are not reinstalled in place
+			controlPlane := contaboMachine.DeepCopy()
+			controlPlane.Labels[clusterv1.MachineControlPlaneLabel] = ""
+			updated = controlPlane.DeepCopy()
+			updated.Spec.Instance.ImageId = ptr.To(infrastructurev1beta2.DefaultImageId)
+			_, err = newValidator(contaboCatalog, inventory, template).ValidateUpdate(context.Background(), controlPlane, updated)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should admit updates of a machine created with a reference the catalog dropped since", func() {
+			contaboMachine.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductId("V1"))
+			updated := contaboMachine.DeepCopy()
+			updated.Spec.NodeLabels = map[string]string{"node.kubernetes.io/pool": "ingress"}
+			warnings, err := newValidator(contaboCatalog, inventory).ValidateUpdate(context.Background(), contaboMachine, updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,437 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+// MinControlPlaneDiskGb is the disk size under which control plane templates are warned about
+const MinControlPlaneDiskGb = 100
+
+// UnavailableProductFreshness is the time an unavailable product observed by an instance order is rejected at
+// admission, older observations are only warned about as the product may be back in stock
+const UnavailableProductFreshness = 24 * time.Hour
+
+// log is for logging in this package.
+var contabomachinetemplatelog = logf.Log.WithName("contabomachinetemplate-resource")
+
+// SetupContaboMachineTemplateWebhookWithManager registers the webhook for ContaboMachineTemplate in the manager.
+func SetupContaboMachineTemplateWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&infrastructurev1beta2.ContaboMachineTemplate{}).
+		WithValidator(&ContaboMachineTemplateCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta2-contabomachinetemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=contabomachinetemplates,verbs=create;update,versions=v1beta2,name=vcontabomachinetemplate-v1beta2.kb.io,admissionReviewVersions=v1
+
+// ContaboMachineTemplateCustomValidator validates the ContaboMachineTemplates when they are created or updated.
+// Templates rendered by a ClusterClass are checked against the Cluster topology variables and the ContaboCluster
+// of the Cluster, so that bad patch combinations are rejected before any machine is created.
+// The validator never calls the Contabo API: it relies on the built-in product catalog and on the product
+// availability cached in the ContaboCluster status by the instance orders, read from the manager cache, so that
+// admission stays fast and available while the Contabo API is slow or down.
+type ContaboMachineTemplateCustomValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &ContaboMachineTemplateCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ContaboMachineTemplate.
+func (v *ContaboMachineTemplateCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	template, ok := obj.(*infrastructurev1beta2.ContaboMachineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboMachineTemplate object but got %T", obj)
+	}
+	contabomachinetemplatelog.Info("Validation for ContaboMachineTemplate upon creation", "name", template.GetName())
+
+	return v.validate(ctx, template)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ContaboMachineTemplate.
+func (v *ContaboMachineTemplateCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldTemplate, ok := oldObj.(*infrastructurev1beta2.ContaboMachineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboMachineTemplate object for the oldObj but got %T", oldObj)
+	}
+	template, ok := newObj.(*infrastructurev1beta2.ContaboMachineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a ContaboMachineTemplate object for the newObj but got %T", newObj)
+	}
+	contabomachinetemplatelog.Info("Validation for ContaboMachineTemplate upon update", "name", template.GetName())
+
+	warnings, err := v.validate(ctx, template)
+	if err != nil {
+		return warnings, err
+	}
+	_, contaboCluster := owningClusters(ctx, v.Client, template)
+	return append(warnings, rolloutWarnings(oldTemplate, template, contaboCluster)...), nil
+}
+
+// rolloutWarnings reports the in-place updates of the template which are not rolled out to its existing machines
+func rolloutWarnings(oldTemplate, template *infrastructurev1beta2.ContaboMachineTemplate, contaboCluster *infrastructurev1beta2.ContaboCluster) admission.Warnings {
+	oldSpec, spec := oldTemplate.Spec.Template.Spec, template.Spec.Template.Spec
+	if equality.Semantic.DeepEqual(oldSpec, spec) {
+		return nil
+	}
+
+	strategy := infrastructurev1beta2.ContaboRolloutStrategyReplace
+	switch {
+	case template.Spec.RolloutStrategy != nil:
+		strategy = *template.Spec.RolloutStrategy
+	case contaboCluster != nil && contaboCluster.Spec.RolloutStrategy != "":
+		strategy = contaboCluster.Spec.RolloutStrategy
+	}
+	if strategy != infrastructurev1beta2.ContaboRolloutStrategyReinstallInPlace {
+		return admission.Warnings{"the existing machines are not updated, reference a new ContaboMachineTemplate to replace them or use the ReinstallInPlace rollout strategy"}
+	}
+
+	// Only the OS and bootstrap fields are rolled out in place, see the ContaboMachine controller
+	oldSpec.NetworkConfig, oldSpec.DNS, oldSpec.NodeLabels, oldSpec.NodeTaints, oldSpec.PrivateOnly = spec.NetworkConfig, spec.DNS, spec.NodeLabels, spec.NodeTaints, spec.PrivateOnly
+	oldSpec.EnableNodeMonitoring, oldSpec.Instance.ImageId = spec.EnableNodeMonitoring, spec.Instance.ImageId
+	warnings := admission.Warnings{"the instances of the worker machines are reinstalled one at a time, the control plane machines are not updated"}
+	if !equality.Semantic.DeepEqual(oldSpec, spec) {
+		warnings = append(warnings, "only the dns, enableNodeMonitoring, instance.imageId, networkConfig, nodeLabels, nodeTaints and privateOnly fields are rolled out in place, reference a new ContaboMachineTemplate to update the other fields of the existing machines")
+	}
+	return warnings
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ContaboMachineTemplate.
+func (v *ContaboMachineTemplateCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the coherence of the product, region and instance of the rendered template
+func (v *ContaboMachineTemplateCustomValidator) validate(ctx context.Context, template *infrastructurev1beta2.ContaboMachineTemplate) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+	instancePath := field.NewPath("spec", "template", "spec", "instance")
+	instance := template.Spec.Template.Spec.Instance
+
+	_, rendered := template.Labels[clusterv1.ClusterTopologyOwnedLabel]
+	if rendered && instance.Name != nil {
+		allErrs = append(allErrs, field.Forbidden(instancePath.Child("name"),
+			fmt.Sprintf("a template rendered by a ClusterClass is shared by all the machines of a topology, they cannot all use the instance %q", *instance.Name)))
+	}
+	// The provider sets the instance and failure domain of each machine, a template shared by the machines cannot
+	if template.Spec.Template.Spec.ProviderID != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "providerID"),
+			"the provider ID is set by the provider on each machine, use instance.name to reuse a given instance"))
+	}
+	if template.Spec.Template.Spec.FailureDomain != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "failureDomain"),
+			"the failure domain is set by the provider on each machine, use the failureDomain of the Machine or the placement of the ContaboCluster"))
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabels(template.Spec.Template.ObjectMeta.Labels, field.NewPath("spec", "template", "metadata", "labels"))...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(template.Spec.Template.ObjectMeta.Annotations, field.NewPath("spec", "template", "metadata", "annotations"))...)
+
+	// The product and image are checked against the catalog collected by the controllers
+	c := loadCatalog(ctx, v.Client)
+	if instance.ProductId != nil {
+		productWarnings, productErrs := c.validateProduct(*instance.ProductId, instancePath.Child("productId"))
+		warnings = append(warnings, productWarnings...)
+		allErrs = append(allErrs, productErrs...)
+	}
+	if instance.ImageId != nil {
+		imageWarnings, imageErrs := c.validateImage(*instance.ImageId, instancePath.Child("imageId"))
+		warnings = append(warnings, imageWarnings...)
+		allErrs = append(allErrs, imageErrs...)
+	}
+
+	allErrs = append(allErrs, validateNodeRegistration(template.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateDNS(template.Spec.Template.Spec.DNS, field.NewPath("spec", "template", "spec", "dns"))...)
+	if additionalIPv4 := instance.AdditionalIPv4; additionalIPv4 != nil && additionalIPv4.Count > 1 && additionalIPv4.AddOnId == nil {
+		allErrs = append(allErrs, field.Required(instancePath.Child("additionalIPv4", "addOnId"),
+			"the additional IPs add-on provides a single address, the add-on ID is required to order more"))
+	}
+	allErrs = append(allErrs, validateAddOns(instance, instancePath)...)
+
+	// The owning Cluster and ContaboCluster are looked up on a best effort basis, templates may be created first
+	cluster, contaboCluster := owningClusters(ctx, v.Client, template)
+	warnings = append(warnings, softWarnings(template, contaboCluster)...)
+	if contaboCluster != nil {
+		region := contaboCluster.Spec.PrivateNetwork.Region
+		if instance.ProductId != nil && slices.Contains(contaboCluster.Status.UnavailableProducts, string(*instance.ProductId)) {
+			if unavailableProductsFresh(contaboCluster, time.Now()) {
+				allErrs = append(allErrs, field.Invalid(instancePath.Child("productId"), *instance.ProductId,
+					fmt.Sprintf("product is end-of-sale or unavailable in region %s of ContaboCluster %s%s",
+						region, contaboCluster.Name, variablesWithValue(topologyVariables(cluster, template), string(*instance.ProductId)))))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("product %s was reported end-of-sale or unavailable in region %s of ContaboCluster %s more than %s ago, it may be back in stock",
+					*instance.ProductId, region, contaboCluster.Name, UnavailableProductFreshness))
+			}
+		}
+		for _, variable := range topologyVariables(cluster, template) {
+			var value string
+			if err := json.Unmarshal(variable.Value.Raw, &value); err != nil {
+				continue
+			}
+			if !slices.Contains(infrastructurev1beta2.ContaboRegions(), infrastructurev1beta2.ContaboRegion(value)) || infrastructurev1beta2.ContaboRegion(value) == region {
+				continue
+			}
+			allErrs = append(allErrs, field.Invalid(instancePath, value,
+				fmt.Sprintf("topology variable %s selects region %s but the machines are created in region %s of ContaboCluster %s",
+					variable.Name, value, region, contaboCluster.Name)))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	if rendered && cluster != nil && cluster.Spec.Topology.IsDefined() {
+		for _, err := range allErrs {
+			err.Detail += fmt.Sprintf(" (rendered by ClusterClass %s for Cluster %s, check the ClusterClass patches)", classRefName(cluster), cluster.Name)
+		}
+	}
+	return warnings, apierrors.NewInvalid(infrastructurev1beta2.GroupVersion.WithKind("ContaboMachineTemplate").GroupKind(), template.Name, allErrs)
+}
+
+// unavailableProductsFresh returns true when an instance order confirmed the unavailable products of the
+// ContaboCluster within the UnavailableProductFreshness
+func unavailableProductsFresh(contaboCluster *infrastructurev1beta2.ContaboCluster, now time.Time) bool {
+	observed := contaboCluster.Status.UnavailableProductsLastObserved
+	return observed != nil && now.Sub(observed.Time) < UnavailableProductFreshness
+}
+
+// validateNodeRegistration checks that the kubelet accepts the node labels and taints when the node registers
+func validateNodeRegistration(spec infrastructurev1beta2.ContaboMachineSpec, path *field.Path) field.ErrorList {
+	allErrs := metav1validation.ValidateLabels(spec.NodeLabels, path.Child("nodeLabels"))
+	for key := range spec.NodeLabels {
+		if restrictedNodeLabel(key) {
+			allErrs = append(allErrs, field.Invalid(path.Child("nodeLabels"), key,
+				"labels in the kubernetes.io and k8s.io domains cannot be set by the kubelet, use the node.kubernetes.io or kubelet.kubernetes.io prefixes"))
+		}
+	}
+
+	taintEffects := []string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}
+	for i, taint := range spec.NodeTaints {
+		taintPath := path.Child("nodeTaints").Index(i)
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			allErrs = append(allErrs, field.Invalid(taintPath.Child("key"), taint.Key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			allErrs = append(allErrs, field.Invalid(taintPath.Child("value"), taint.Value, msg))
+		}
+		if !slices.Contains(taintEffects, string(taint.Effect)) {
+			allErrs = append(allErrs, field.NotSupported(taintPath.Child("effect"), taint.Effect, taintEffects))
+		}
+	}
+	return allErrs
+}
+
+// validateDNS checks that the nameservers are IP addresses and the search domains are domain names
+func validateDNS(dns *infrastructurev1beta2.ContaboDNSSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if dns == nil {
+		return allErrs
+	}
+	for i, nameserver := range dns.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("nameservers").Index(i), nameserver, "must be an IPv4 or IPv6 address"))
+		}
+	}
+	for i, searchDomain := range dns.SearchDomains {
+		for _, msg := range validation.IsDNS1123Subdomain(strings.TrimSuffix(searchDomain, ".")) {
+			allErrs = append(allErrs, field.Invalid(path.Child("searchDomains").Index(i), searchDomain, msg))
+		}
+	}
+	return allErrs
+}
+
+// validateAddOns checks that the add-ons of the instance are not ordered elsewhere in the spec and that the
+// add-ons added to reused instances are ordered once, as every upgrade adds a single add-on
+func validateAddOns(instance infrastructurev1beta2.ContaboInstanceSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, addOn := range instance.AddOns {
+		addOnPath := path.Child("addOns").Index(i)
+		switch {
+		case addOn.Id == infrastructurev1beta2.ContaboPrivateNetworkingAddOnId:
+			allErrs = append(allErrs, field.Invalid(addOnPath.Child("id"), addOn.Id,
+				"private networking is added by the provider to the instances of the private network of the ContaboCluster"))
+		case instance.AdditionalIPv4 != nil && instance.AdditionalIPv4.AddOnId != nil && addOn.Id == *instance.AdditionalIPv4.AddOnId:
+			allErrs = append(allErrs, field.Invalid(addOnPath.Child("id"), addOn.Id,
+				"the add-on is already ordered by additionalIPv4, set its count there"))
+		}
+		if addOn.Upgrade != "" && addOn.Quantity > 1 {
+			allErrs = append(allErrs, field.Invalid(addOnPath.Child("quantity"), addOn.Quantity,
+				"an add-on added to reused instances with upgrade is ordered once"))
+		}
+	}
+	return allErrs
+}
+
+// restrictedNodeLabel returns true for the labels the NodeRestriction admission plugin forbids the kubelet to set
+func restrictedNodeLabel(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	if strings.HasPrefix(key, "node.kubernetes.io/") || strings.HasPrefix(key, "kubelet.kubernetes.io/") {
+		return false
+	}
+	for _, domain := range []string{"kubernetes.io", "k8s.io"} {
+		if prefix == domain || strings.HasSuffix(prefix, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// softWarnings returns the misconfigurations of the template which will likely work but are suboptimal, they are
+// reported as admission warnings without rejecting the template
+func softWarnings(template *infrastructurev1beta2.ContaboMachineTemplate, contaboCluster *infrastructurev1beta2.ContaboCluster) admission.Warnings {
+	var warnings admission.Warnings
+	instance := template.Spec.Template.Spec.Instance
+
+	if instance.ProductId != nil {
+		productId := *instance.ProductId
+		switch {
+		case slices.Contains(infrastructurev1beta2.ContaboDeprecatedProducts(), productId):
+			warnings = append(warnings, fmt.Sprintf("product %s is from a previous Contabo generation, consider a Cloud VPS or Cloud VDS product of the current catalog", productId))
+		case !slices.Contains(infrastructurev1beta2.ContaboProducts(), productId):
+			// Checked against the ContaboCatalog by validateProduct
+		case isControlPlaneTemplate(template) && infrastructurev1beta2.ContaboProductDiskGb(productId) < MinControlPlaneDiskGb:
+			warnings = append(warnings, fmt.Sprintf("product %s has a %dGB disk, control plane machines should have at least %dGB for etcd and the container images",
+				productId, infrastructurev1beta2.ContaboProductDiskGb(productId), MinControlPlaneDiskGb))
+		}
+	}
+
+	if contaboCluster != nil && contaboCluster.Status.SshKey == nil {
+		// A new ContaboCluster has no SSH key yet, only the failures are reported
+		if condition := meta.FindStatusCondition(contaboCluster.Status.Conditions, infrastructurev1beta2.ClusterSshKeyReadyCondition); condition != nil &&
+			condition.Reason == infrastructurev1beta2.ClusterSshKeyFailedReason {
+			warnings = append(warnings, fmt.Sprintf("ContaboCluster %s has no SSH key (%s), the machines are not created until it is ready", contaboCluster.Name, condition.Message))
+		}
+	}
+	return warnings
+}
+
+// isControlPlaneTemplate returns true for templates rendered by a ClusterClass for the control plane
+func isControlPlaneTemplate(template *infrastructurev1beta2.ContaboMachineTemplate) bool {
+	if _, ok := template.Labels[clusterv1.ClusterTopologyOwnedLabel]; !ok {
+		return false
+	}
+	_, machineDeployment := template.Labels[clusterv1.ClusterTopologyMachineDeploymentNameLabel]
+	_, machinePool := template.Labels[clusterv1.ClusterTopologyMachinePoolNameLabel]
+	return !machineDeployment && !machinePool
+}
+
+// owningClusters returns the Cluster and ContaboCluster of the template or machine from its cluster name label, nil
+// when not found
+func owningClusters(ctx context.Context, reader client.Reader, obj client.Object) (*clusterv1.Cluster, *infrastructurev1beta2.ContaboCluster) {
+	clusterName, ok := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if !ok || reader == nil {
+		return nil, nil
+	}
+	cluster := &clusterv1.Cluster{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: clusterName}, cluster); err != nil {
+		contabomachinetemplatelog.V(1).Info("Cluster not found", "name", obj.GetName(), "cluster", clusterName, "error", err.Error())
+		return nil, nil
+	}
+	infrastructureRef := cluster.Spec.InfrastructureRef
+	if infrastructureRef.Kind != "ContaboCluster" || infrastructureRef.Name == "" {
+		return cluster, nil
+	}
+	contaboCluster := &infrastructurev1beta2.ContaboCluster{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: infrastructureRef.Name}, contaboCluster); err != nil {
+		contabomachinetemplatelog.V(1).Info("ContaboCluster not found", "name", obj.GetName(), "contaboCluster", infrastructureRef.Name, "error", err.Error())
+		return cluster, nil
+	}
+	return cluster, contaboCluster
+}
+
+// topologyVariables returns the Cluster topology variables used to render the template, with the overrides of its
+// MachineDeployment or MachinePool
+func topologyVariables(cluster *clusterv1.Cluster, template *infrastructurev1beta2.ContaboMachineTemplate) []clusterv1.ClusterVariable {
+	if cluster == nil || !cluster.Spec.Topology.IsDefined() {
+		return nil
+	}
+	topology := cluster.Spec.Topology
+	var overrides []clusterv1.ClusterVariable
+	if name, ok := template.Labels[clusterv1.ClusterTopologyMachineDeploymentNameLabel]; ok {
+		for _, machineDeployment := range topology.Workers.MachineDeployments {
+			if machineDeployment.Name == name {
+				overrides = machineDeployment.Variables.Overrides
+			}
+		}
+	} else if name, ok := template.Labels[clusterv1.ClusterTopologyMachinePoolNameLabel]; ok {
+		for _, machinePool := range topology.Workers.MachinePools {
+			if machinePool.Name == name {
+				overrides = machinePool.Variables.Overrides
+			}
+		}
+	} else {
+		overrides = topology.ControlPlane.Variables.Overrides
+	}
+
+	variables := slices.Clone(overrides)
+	for _, variable := range topology.Variables {
+		if !slices.ContainsFunc(overrides, func(override clusterv1.ClusterVariable) bool { return override.Name == variable.Name }) {
+			variables = append(variables, variable)
+		}
+	}
+	return variables
+}
+
+// variablesWithValue describes the topology variables set to the string value, empty when there is none
+func variablesWithValue(variables []clusterv1.ClusterVariable, value string) string {
+	var names []string
+	for _, variable := range variables {
+		var variableValue string
+		if err := json.Unmarshal(variable.Value.Raw, &variableValue); err == nil && variableValue == value {
+			names = append(names, variable.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", set by topology variables %s", strings.Join(names, ", "))
+}
+
+// classRefName returns the namespaced name of the ClusterClass of the Cluster
+func classRefName(cluster *clusterv1.Cluster) string {
+	namespace := cluster.Spec.Topology.ClassRef.Namespace
+	if namespace == "" {
+		namespace = cluster.Namespace
+	}
+	return namespace + "/" + cluster.Spec.Topology.ClassRef.Name
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+)
+
+var _ = Describe("ContaboMachineTemplate Webhook", func() {
+	var (
+		template       *infrastructurev1beta2.ContaboMachineTemplate
+		cluster        *clusterv1.Cluster
+		contaboCluster *infrastructurev1beta2.ContaboCluster
+		validator      *ContaboMachineTemplateCustomValidator
+	)
+
+	BeforeEach(func() {
+		template = &infrastructurev1beta2.ContaboMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "workers-abcde",
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:                          "test",
+					clusterv1.ClusterTopologyOwnedLabel:                 "",
+					clusterv1.ClusterTopologyMachineDeploymentNameLabel: "md-0",
+				},
+			},
+			Spec: infrastructurev1beta2.ContaboMachineTemplateSpec{
+				Template: infrastructurev1beta2.ContaboMachineTemplateResource{
+					Spec: infrastructurev1beta2.ContaboMachineSpec{
+						Instance: infrastructurev1beta2.ContaboInstanceSpec{
+							ProductId: ptr.To(infrastructurev1beta2.ContaboProductCloudVPS10NVMe),
+						},
+					},
+				},
+			},
+		}
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+					APIGroup: infrastructurev1beta2.GroupVersion.Group,
+					Kind:     "ContaboCluster",
+					Name:     "test",
+				},
+				Topology: clusterv1.Topology{
+					ClassRef: clusterv1.ClusterClassRef{Name: "contabo"},
+					Version:  "v1.33.0",
+					Variables: []clusterv1.ClusterVariable{
+						{Name: "workerProduct", Value: apiextensionsv1.JSON{Raw: []byte(`"V91"`)}},
+						{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"EU"`)}},
+					},
+					Workers: clusterv1.WorkersTopology{
+						MachineDeployments: []clusterv1.MachineDeploymentTopology{{Class: "worker", Name: "md-0"}},
+					},
+				},
+			},
+		}
+		contaboCluster = &infrastructurev1beta2.ContaboCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: infrastructurev1beta2.ContaboClusterSpec{
+				PrivateNetwork: infrastructurev1beta2.ContaboPrivateNetworkSpec{Region: infrastructurev1beta2.ContaboRegionEU},
+			},
+		}
+	})
+
+	newValidator := func() *ContaboMachineTemplateCustomValidator {
+		return &ContaboMachineTemplateCustomValidator{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, contaboCluster).Build(),
+		}
+	}
+
+	Context("When creating a ContaboMachineTemplate", func() {
+		It("should admit a coherent rendered template", func() {
+			validator = newValidator()
+			warnings, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should warn about products missing from the catalog", func() {
+			template.Spec.Template.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductId("V1"))
+			validator = newValidator()
+			warnings, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+
+		It("should warn about products of a previous generation", func() {
+			template.Spec.Template.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductId("V45"))
+			validator = newValidator()
+			warnings, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("previous Contabo generation")))
+		})
+
+		It("should warn about small disks on control plane templates only", func() {
+			validator = newValidator()
+			delete(template.Labels, clusterv1.ClusterTopologyMachineDeploymentNameLabel)
+			warnings, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("75GB disk")))
+
+			template.Spec.Template.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductCloudVPS10SSD)
+			warnings, err = validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should warn when the SSH key of the ContaboCluster failed", func() {
+			contaboCluster.Status.Conditions = []metav1.Condition{{
+				Type:    infrastructurev1beta2.ClusterSshKeyReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrastructurev1beta2.ClusterSshKeyFailedReason,
+				Message: "Failed to create SSH key secret",
+			}}
+			validator = newValidator()
+			warnings, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("Failed to create SSH key secret")))
+		})
+
+		It("should reject an instance name on a rendered template", func() {
+			template.Spec.Template.Spec.Instance.Name = ptr.To("worker")
+			validator = newValidator()
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.instance.name"))
+			Expect(err.Error()).To(ContainSubstring("ClusterClass default/contabo"))
+		})
+
+		It("should reject a product unavailable in the region of the ContaboCluster", func() {
+			contaboCluster.Status.UnavailableProducts = []string{"V91"}
+			contaboCluster.Status.UnavailableProductsLastObserved = ptr.To(metav1.NewTime(time.Now().Add(-time.Hour)))
+			validator = newValidator()
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("region EU"))
+			Expect(err.Error()).To(ContainSubstring("set by topology variables workerProduct"))
+		})
+
+		It("should only warn about a product reported unavailable long ago", func() {
+			contaboCluster.Status.UnavailableProducts = []string{"V91"}
+			validator = newValidator()
+			warnings, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("may be back in stock")))
+
+			contaboCluster.Status.UnavailableProductsLastObserved = ptr.To(metav1.NewTime(time.Now().Add(-2 * UnavailableProductFreshness)))
+			validator = newValidator()
+			warnings, err = validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+
+		It("should reject a region variable not matching the ContaboCluster", func() {
+			cluster.Spec.Topology.Workers.MachineDeployments[0].Variables.Overrides = []clusterv1.ClusterVariable{
+				{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"UK"`)}},
+			}
+			validator = newValidator()
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("topology variable region selects region UK"))
+		})
+
+		It("should reject the fields set by the provider on each machine", func() {
+			template.Spec.Template.Spec.ProviderID = ptr.To("contabo://123456789")
+			template.Spec.Template.Spec.FailureDomain = ptr.To("EU")
+			template.Spec.Template.ObjectMeta.Labels = map[string]string{"bad label": "value"}
+			validator = newValidator()
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.providerID"))
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.failureDomain"))
+			Expect(err.Error()).To(ContainSubstring("spec.template.metadata.labels"))
+		})
+
+		It("should reject products missing from the collected ContaboCatalog", func() {
+			template.Spec.Template.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductId("V1"))
+			contaboCatalog := &infrastructurev1beta2.ContaboCatalog{
+				ObjectMeta: metav1.ObjectMeta{Name: infrastructurev1beta2.ContaboCatalogName},
+				Status: infrastructurev1beta2.ContaboCatalogStatus{
+					Products:    []infrastructurev1beta2.ContaboCatalogProduct{{ProductId: infrastructurev1beta2.ContaboProductCloudVPS10NVMe}},
+					LastUpdated: ptr.To(metav1.Now()),
+				},
+			}
+			validator = &ContaboMachineTemplateCustomValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, contaboCluster, contaboCatalog).Build(),
+			}
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.instance.productId"))
+		})
+
+		It("should reject node labels and taints the kubelet cannot register", func() {
+			template.Spec.Template.Spec.NodeLabels = map[string]string{
+				"node.kubernetes.io/pool":         "ingress",
+				"node-role.kubernetes.io/ingress": "",
+			}
+			template.Spec.Template.Spec.NodeTaints = []corev1.Taint{
+				{Key: "dedicated", Value: "ingress", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Effect: "Sometimes"},
+			}
+			validator = newValidator()
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("node-role.kubernetes.io/ingress"))
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.nodeTaints[1].effect"))
+			Expect(err.Error()).NotTo(ContainSubstring("node.kubernetes.io/pool"))
+		})
+
+		It("should require the add-on ID to order several additional IPv4 addresses", func() {
+			template.Spec.Template.Spec.Instance.AdditionalIPv4 = &infrastructurev1beta2.ContaboAdditionalIPv4Spec{Count: 2}
+			validator = newValidator()
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.instance.additionalIPv4.addOnId"))
+
+			template.Spec.Template.Spec.Instance.AdditionalIPv4.AddOnId = ptr.To(int64(1501))
+			_, err = validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject add-ons ordered elsewhere in the spec and upgrades of several add-ons", func() {
+			template.Spec.Template.Spec.Instance.AdditionalIPv4 = &infrastructurev1beta2.ContaboAdditionalIPv4Spec{Count: 2, AddOnId: ptr.To(int64(1501))}
+			template.Spec.Template.Spec.Instance.AddOns = []infrastructurev1beta2.ContaboAddOnSpec{
+				{Id: 1501, Quantity: 1},
+				{Id: infrastructurev1beta2.ContaboPrivateNetworkingAddOnId, Quantity: 1},
+				{Id: 1495, Quantity: 2, Upgrade: infrastructurev1beta2.ContaboAddOnUpgradeBackup},
+				{Id: 1600, Quantity: 4},
+			}
+			validator = newValidator()
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.instance.addOns[0].id"))
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.instance.addOns[1].id"))
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.instance.addOns[2].quantity"))
+			Expect(err.Error()).NotTo(ContainSubstring("addOns[3]"))
+
+			template.Spec.Template.Spec.Instance.AddOns = []infrastructurev1beta2.ContaboAddOnSpec{
+				{Id: 1495, Quantity: 1, Upgrade: infrastructurev1beta2.ContaboAddOnUpgradeBackup},
+				{Id: 1600, Quantity: 4},
+			}
+			_, err = validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject nameservers and search domains that are not addresses and domains", func() {
+			template.Spec.Template.Spec.DNS = &infrastructurev1beta2.ContaboDNSSpec{
+				Nameservers:   []string{"10.0.0.53", "dns.example.com", "2001:db8::53"},
+				SearchDomains: []string{"cluster.internal.", "Not_A_Domain"},
+			}
+			validator = newValidator()
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.dns.nameservers[1]"))
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.dns.searchDomains[1]"))
+			Expect(err.Error()).NotTo(ContainSubstring("nameservers[0]"))
+			Expect(err.Error()).NotTo(ContainSubstring("nameservers[2]"))
+			Expect(err.Error()).NotTo(ContainSubstring("searchDomains[0]"))
+		})
+
+		It("should admit templates without a Cluster", func() {
+			template.Spec.Template.Spec.Instance.Name = nil
+			validator = &ContaboMachineTemplateCustomValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			}
+			_, err := validator.ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When updating a ContaboMachineTemplate in place", func() {
+		It("should warn that the existing machines are only updated with the ReinstallInPlace rollout strategy", func() {
+			validator = newValidator()
+			updated := template.DeepCopy()
+			updated.Spec.Template.Spec.NodeLabels = map[string]string{"node.kubernetes.io/pool": "workers"}
+			warnings, err := validator.ValidateUpdate(context.Background(), template, updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("the existing machines are not updated")))
+
+			contaboCluster.Spec.RolloutStrategy = infrastructurev1beta2.ContaboRolloutStrategyReinstallInPlace
+			validator = newValidator()
+			warnings, err = validator.ValidateUpdate(context.Background(), template, updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("reinstalled one at a time")))
+			Expect(warnings).NotTo(ContainElement(ContainSubstring("only the dns")))
+
+			updated.Spec.Template.Spec.Instance.ImageId = ptr.To(customImageId)
+			warnings, err = validator.ValidateUpdate(context.Background(), template, updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).NotTo(ContainElement(ContainSubstring("only the dns")))
+
+			updated.Spec.Template.Spec.Instance.ProductId = ptr.To(infrastructurev1beta2.ContaboProductCloudVPS20NVMe)
+			warnings, err = validator.ValidateUpdate(context.Background(), template, updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("only the dns")))
+
+			warnings, err = validator.ValidateUpdate(context.Background(), template, template.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+})
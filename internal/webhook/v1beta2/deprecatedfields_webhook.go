@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/deprecation"
+)
+
+// DeprecatedFieldsWebhookPath is the path of the webhook migrating the deprecated fields
+const DeprecatedFieldsWebhookPath = "/mutate-infrastructure-cluster-x-k8s-io-v1beta2-deprecated-fields"
+
+var deprecatedfieldslog = logf.Log.WithName("deprecatedfields-resource")
+
+// SetupDeprecatedFieldsWebhookWithManager registers the webhook migrating the deprecated fields in the manager.
+func SetupDeprecatedFieldsWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(DeprecatedFieldsWebhookPath, &webhook.Admission{
+		Handler: &DeprecatedFieldsMutator{Fields: deprecation.Fields},
+	})
+	return nil
+}
+
+// +kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta2-deprecated-fields,mutating=true,failurePolicy=ignore,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=contaboclusters;contabomachines;contabomachinetemplates,verbs=create;update,versions=v1beta2,name=mdeprecatedfields-v1beta2.kb.io,admissionReviewVersions=v1
+
+// DeprecatedFieldsMutator moves the deprecated fields of the provider resources to their replacement when they are
+// created or updated, and warns the client about each of them. It works on the raw object so that a single webhook
+// serves every kind. A failure does not reject the request, the controllers annotate the resources still using
+// deprecated fields.
+type DeprecatedFieldsMutator struct {
+	Fields []deprecation.Field
+}
+
+var _ admission.Handler = &DeprecatedFieldsMutator{}
+
+// Handle implements admission.Handler.
+func (m *DeprecatedFieldsMutator) Handle(_ context.Context, req admission.Request) admission.Response {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	warnings, err := deprecation.Migrate(m.Fields, req.Kind.Kind, obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(warnings) == 0 {
+		return admission.Allowed("")
+	}
+	deprecatedfieldslog.Info("Migrated deprecated fields", "kind", req.Kind.Kind, "name", req.Name, "namespace", req.Namespace, "fields", len(warnings))
+
+	// The migrated object no longer uses the deprecated fields
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, deprecation.Annotation)
+		}
+	}
+	migrated, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, migrated).WithWarnings(warnings...)
+}
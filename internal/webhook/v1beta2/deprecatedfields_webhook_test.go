@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/internal/deprecation"
+)
+
+var _ = Describe("Deprecated Fields Webhook", func() {
+	var mutator *DeprecatedFieldsMutator
+
+	BeforeEach(func() {
+		mutator = &DeprecatedFieldsMutator{Fields: []deprecation.Field{
+			{Kind: "ContaboMachineTemplate", Path: "spec.template.spec.dns", Replacement: "spec.template.spec.resolvers", Since: "v0.9.0"},
+		}}
+	})
+
+	request := func(kind string, raw string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:   metav1.GroupVersionKind{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta2", Kind: kind},
+			Name:   "workers",
+			Object: runtime.RawExtension{Raw: []byte(raw)},
+		}}
+	}
+
+	It("should migrate the deprecated fields with a warning", func() {
+		response := mutator.Handle(context.Background(), request("ContaboMachineTemplate",
+			`{"metadata":{"name":"workers","annotations":{"infrastructure.cluster.x-k8s.io/deprecated-fields":"spec.template.spec.dns"}},"spec":{"template":{"spec":{"dns":{"nameservers":["10.0.0.53"]}}}}}`))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(ConsistOf(ContainSubstring("spec.template.spec.dns is deprecated since v0.9.0, it was migrated to spec.template.spec.resolvers")))
+
+		operations := map[string]string{}
+		for _, patch := range response.Patches {
+			operations[patch.Path] = patch.Operation
+		}
+		Expect(operations).To(HaveKeyWithValue("/spec/template/spec/dns", "remove"))
+		Expect(operations).To(HaveKeyWithValue("/spec/template/spec/resolvers", "add"))
+		Expect(operations).To(HaveKeyWithValue("/metadata/annotations/infrastructure.cluster.x-k8s.io~1deprecated-fields", "remove"))
+	})
+
+	It("should allow the resources without deprecated fields unchanged", func() {
+		response := mutator.Handle(context.Background(), request("ContaboMachineTemplate", `{"metadata":{"name":"workers"},"spec":{"template":{"spec":{}}}}`))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Patches).To(BeEmpty())
+		Expect(response.Warnings).To(BeEmpty())
+
+		// Fields of other kinds are not migrated
+		response = mutator.Handle(context.Background(), request("ContaboMachine", `{"spec":{"template":{"spec":{"dns":{}}}}}`))
+		Expect(response.Patches).To(BeEmpty())
+	})
+})
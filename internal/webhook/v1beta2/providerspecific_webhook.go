@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrastructurev1beta2 "github.com/ctnr-io/cluster-api-provider-contabo/api/v1beta2"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/service"
+)
+
+// ProviderSpecificWebhookPath is the path of the webhook validating the CreateInstance passthrough properties
+const ProviderSpecificWebhookPath = "/validate-infrastructure-cluster-x-k8s-io-v1beta2-provider-specific"
+
+var providerspecificlog = logf.Log.WithName("providerspecific-resource")
+
+// SetupProviderSpecificWebhookWithManager registers the webhook validating the CreateInstance passthrough properties
+// in the manager.
+func SetupProviderSpecificWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(ProviderSpecificWebhookPath, &webhook.Admission{
+		Handler: &ProviderSpecificValidator{},
+	})
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta2-provider-specific,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=contabomachines;contabomachinetemplates,verbs=create;update,versions=v1beta2,name=vproviderspecific-v1beta2.kb.io,admissionReviewVersions=v1
+
+// ProviderSpecificValidator validates the providerSpecific passthrough properties of the instances against the
+// CreateInstance request schema of the bundled Contabo OpenAPI specification. Unknown properties are allowed with a
+// warning, as a newer Contabo API may support them, while mistyped values are denied as they would fail the order.
+type ProviderSpecificValidator struct{}
+
+var _ admission.Handler = &ProviderSpecificValidator{}
+
+// Handle implements admission.Handler.
+func (v *ProviderSpecificValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	var providerSpecific *apiextensionsv1.JSON
+	var fldPath *field.Path
+	switch req.Kind.Kind {
+	case "ContaboMachine":
+		machine := &infrastructurev1beta2.ContaboMachine{}
+		if err := json.Unmarshal(req.Object.Raw, machine); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		providerSpecific = machine.Spec.Instance.ProviderSpecific
+		fldPath = field.NewPath("spec", "instance", "providerSpecific")
+	case "ContaboMachineTemplate":
+		template := &infrastructurev1beta2.ContaboMachineTemplate{}
+		if err := json.Unmarshal(req.Object.Raw, template); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		providerSpecific = template.Spec.Template.Spec.Instance.ProviderSpecific
+		fldPath = field.NewPath("spec", "template", "spec", "instance", "providerSpecific")
+	}
+	if providerSpecific == nil {
+		return admission.Allowed("")
+	}
+
+	warnings, errs := service.ValidateCreateInstancePassthrough(providerSpecific.Raw, fldPath)
+	if len(errs) == 0 {
+		return admission.Allowed("").WithWarnings(warnings...)
+	}
+	providerspecificlog.Info("Denied invalid providerSpecific properties", "kind", req.Kind.Kind, "name", req.Name, "namespace", req.Namespace, "errors", len(errs))
+
+	invalid := apierrors.NewInvalid(infrastructurev1beta2.GroupVersion.WithKind(req.Kind.Kind).GroupKind(), req.Name, errs)
+	return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{
+		Allowed:  false,
+		Result:   &invalid.ErrStatus,
+		Warnings: warnings,
+	}}
+}
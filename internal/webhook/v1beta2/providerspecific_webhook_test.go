@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("ProviderSpecific Webhook", func() {
+	validator := &ProviderSpecificValidator{}
+
+	request := func(kind string, raw string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:   metav1.GroupVersionKind{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta2", Kind: kind},
+			Name:   "workers",
+			Object: runtime.RawExtension{Raw: []byte(raw)},
+		}}
+	}
+
+	It("should allow the objects without passthrough properties", func() {
+		response := validator.Handle(context.Background(), request("ContaboMachine", `{"spec":{"instance":{"productId":"V76"}}}`))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(BeEmpty())
+	})
+
+	It("should warn about the unknown and provider owned properties", func() {
+		response := validator.Handle(context.Background(), request("ContaboMachineTemplate",
+			`{"spec":{"template":{"spec":{"instance":{"providerSpecific":{"license":"PleskHost","futureProperty":{},"region":"UK"}}}}}}`))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(ConsistOf(
+			ContainSubstring("spec.template.spec.instance.providerSpecific.futureProperty: unknown property"),
+			ContainSubstring("spec.template.spec.instance.providerSpecific.region: set by the provider"),
+		))
+	})
+
+	It("should deny the mistyped properties", func() {
+		response := validator.Handle(context.Background(), request("ContaboMachine",
+			`{"spec":{"instance":{"providerSpecific":{"rootPassword":"secret","futureProperty":1}}}}`))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Reason).To(Equal(metav1.StatusReasonInvalid))
+		Expect(response.Result.Message).To(ContainSubstring("spec.instance.providerSpecific.rootPassword: Invalid value"))
+		Expect(response.Warnings).To(ConsistOf(ContainSubstring("spec.instance.providerSpecific.futureProperty: unknown property")))
+	})
+})
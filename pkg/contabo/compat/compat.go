@@ -0,0 +1,301 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package compat checks that the Contabo API still answers the endpoints the controllers rely on the way the
+// compiled-in client expects, so that a breaking change of the API stops the controller at startup instead of
+// failing the reconciliations at random.
+package compat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// SpecVersion is the version of the Contabo OpenAPI specification the client is generated from
+const SpecVersion = "1.0.0"
+
+// Policy is what the controller does at startup when a critical endpoint is incompatible
+type Policy string
+
+const (
+	// PolicyFail refuses to start the controller
+	PolicyFail Policy = "Fail"
+	// PolicyWarn logs the incompatible endpoints and starts the controller
+	PolicyWarn Policy = "Warn"
+	// PolicySkip does not check the endpoints
+	PolicySkip Policy = "Skip"
+)
+
+// Status is the outcome of the check of an endpoint
+type Status string
+
+const (
+	// StatusCompatible is an endpoint answering as the client expects
+	StatusCompatible Status = "Compatible"
+	// StatusIncompatible is an endpoint missing or answering a response the client cannot use
+	StatusIncompatible Status = "Incompatible"
+	// StatusUnknown is an endpoint which could not be checked, e.g. rate limited or unavailable
+	StatusUnknown Status = "Unknown"
+)
+
+// Result is the outcome of the check of an endpoint
+type Result struct {
+	// Endpoint is the name of the endpoint, e.g. instances
+	Endpoint string
+	// Critical endpoints are required by the provisioning of the machines, the others by optional features
+	Critical bool
+	Status   Status
+	Message  string
+}
+
+// Report is the outcome of the checks of the endpoints
+type Report struct {
+	// SpecVersion is the version of the specification of the compiled-in client
+	SpecVersion string
+	Results     []Result
+}
+
+// Incompatible returns the incompatible endpoints, the critical ones only when critical is set
+func (r Report) Incompatible(critical bool) []Result {
+	incompatible := []Result{}
+	for _, result := range r.Results {
+		if result.Status == StatusIncompatible && (result.Critical || !critical) {
+			incompatible = append(incompatible, result)
+		}
+	}
+	return incompatible
+}
+
+// Err returns an error listing the incompatible critical endpoints, nil when there is none
+func (r Report) Err() error {
+	incompatible := r.Incompatible(true)
+	if len(incompatible) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(incompatible))
+	for _, result := range incompatible {
+		messages = append(messages, fmt.Sprintf("%s: %s", result.Endpoint, result.Message))
+	}
+	return fmt.Errorf("contabo API is incompatible with the client generated from specification %s: %s", r.SpecVersion, strings.Join(messages, "; "))
+}
+
+// CheckPinnedVersion returns an error when the operator pinned the controller to another specification version than
+// the one of the compiled-in client, an empty pinned version accepts any
+func CheckPinnedVersion(pinned string) error {
+	if pinned == "" || strings.TrimPrefix(pinned, "v") == SpecVersion {
+		return nil
+	}
+	return fmt.Errorf("contabo API specification pinned to %s, the controller client is generated from %s", pinned, SpecVersion)
+}
+
+// probe is a read-only request to an endpoint, with the fields its response and the items of its data must have
+type probe struct {
+	endpoint string
+	critical bool
+	do       func(ctx context.Context, c *contaboclient.Client) (*http.Response, error)
+	parse    func(rsp *http.Response) error
+	fields   []string
+	item     []string
+}
+
+// listFields are the fields of the responses of the list endpoints, the pagination drives the listing of all pages
+var listFields = []string{"_pagination", "data"}
+
+// probes are the endpoints the controllers rely on, the requests fetch a single item
+var probes = []probe{
+	{
+		endpoint: "instances",
+		critical: true,
+		do: func(ctx context.Context, c *contaboclient.Client) (*http.Response, error) {
+			return c.RetrieveInstancesList(ctx, &models.RetrieveInstancesListParams{Size: ptr.To(int64(1))})
+		},
+		parse: func(rsp *http.Response) error {
+			_, err := contaboclient.ParseRetrieveInstancesListResponse(rsp)
+			return err
+		},
+		fields: listFields,
+		item:   []string{"instanceId", "displayName", "name", "status", "productId", "region", "ipConfig", "imageId"},
+	},
+	{
+		endpoint: "private-networks",
+		critical: true,
+		do: func(ctx context.Context, c *contaboclient.Client) (*http.Response, error) {
+			return c.RetrievePrivateNetworkList(ctx, &models.RetrievePrivateNetworkListParams{Size: ptr.To(int64(1))})
+		},
+		parse: func(rsp *http.Response) error {
+			_, err := contaboclient.ParseRetrievePrivateNetworkListResponse(rsp)
+			return err
+		},
+		fields: listFields,
+		item:   []string{"privateNetworkId", "name", "region", "cidr", "instances"},
+	},
+	{
+		endpoint: "secrets",
+		critical: true,
+		do: func(ctx context.Context, c *contaboclient.Client) (*http.Response, error) {
+			return c.RetrieveSecretList(ctx, &models.RetrieveSecretListParams{Size: ptr.To(int64(1))})
+		},
+		parse: func(rsp *http.Response) error {
+			_, err := contaboclient.ParseRetrieveSecretListResponse(rsp)
+			return err
+		},
+		fields: listFields,
+		item:   []string{"secretId", "name", "type"},
+	},
+	{
+		endpoint: "images",
+		do: func(ctx context.Context, c *contaboclient.Client) (*http.Response, error) {
+			return c.RetrieveImageList(ctx, &models.RetrieveImageListParams{Size: ptr.To(int64(1))})
+		},
+		parse: func(rsp *http.Response) error {
+			_, err := contaboclient.ParseRetrieveImageListResponse(rsp)
+			return err
+		},
+		fields: listFields,
+		item:   []string{"imageId", "name", "standardImage"},
+	},
+	{
+		endpoint: "instance-audits",
+		do: func(ctx context.Context, c *contaboclient.Client) (*http.Response, error) {
+			return c.RetrieveInstancesAuditsList(ctx, &models.RetrieveInstancesAuditsListParams{Size: ptr.To(int64(1))})
+		},
+		parse: func(rsp *http.Response) error {
+			_, err := contaboclient.ParseRetrieveInstancesAuditsListResponse(rsp)
+			return err
+		},
+		fields: listFields,
+		item:   []string{"instanceId", "action", "traceId"},
+	},
+	{
+		endpoint: "tags",
+		do: func(ctx context.Context, c *contaboclient.Client) (*http.Response, error) {
+			return c.RetrieveTagList(ctx, &models.RetrieveTagListParams{Size: ptr.To(int64(1))})
+		},
+		parse: func(rsp *http.Response) error {
+			_, err := contaboclient.ParseRetrieveTagListResponse(rsp)
+			return err
+		},
+		fields: listFields,
+		item:   []string{"tagId", "name"},
+	},
+}
+
+// Check probes the endpoints the controllers rely on with read-only requests
+func Check(ctx context.Context, c *contaboclient.ClientWithResponses) Report {
+	report := Report{SpecVersion: SpecVersion}
+	client, ok := c.ClientInterface.(*contaboclient.Client)
+	if !ok {
+		for _, p := range probes {
+			report.Results = append(report.Results, Result{Endpoint: p.endpoint, Critical: p.critical, Status: StatusUnknown, Message: "unsupported client"})
+		}
+		return report
+	}
+	for _, p := range probes {
+		status, message := p.check(ctx, client)
+		report.Results = append(report.Results, Result{Endpoint: p.endpoint, Critical: p.critical, Status: status, Message: message})
+	}
+	return report
+}
+
+// check sends the request of the probe and checks its response
+func (p probe) check(ctx context.Context, c *contaboclient.Client) (Status, string) {
+	rsp, err := p.do(ctx, c)
+	if err != nil {
+		return StatusUnknown, fmt.Sprintf("request failed: %v", err)
+	}
+	body, err := io.ReadAll(rsp.Body)
+	_ = rsp.Body.Close()
+	if err != nil {
+		return StatusUnknown, fmt.Sprintf("failed to read response: %v", err)
+	}
+
+	switch {
+	case rsp.StatusCode == http.StatusNotFound || rsp.StatusCode == http.StatusMethodNotAllowed ||
+		rsp.StatusCode == http.StatusGone || rsp.StatusCode == http.StatusNotImplemented:
+		return StatusIncompatible, fmt.Sprintf("endpoint not served, status %d", rsp.StatusCode)
+	case rsp.StatusCode == http.StatusUnauthorized || rsp.StatusCode == http.StatusForbidden ||
+		rsp.StatusCode == http.StatusTooManyRequests || rsp.StatusCode >= 500:
+		return StatusUnknown, fmt.Sprintf("status %d", rsp.StatusCode)
+	case rsp.StatusCode < 200 || rsp.StatusCode >= 300:
+		return StatusIncompatible, fmt.Sprintf("request rejected, status %d: %s", rsp.StatusCode, truncate(string(body), 256))
+	}
+
+	// The typed response must decode, then the fields the controllers read must be present as the decoding leaves
+	// the missing ones empty
+	rsp.Body = io.NopCloser(bytes.NewReader(body))
+	if err := p.parse(rsp); err != nil {
+		return StatusIncompatible, fmt.Sprintf("response does not decode: %v", err)
+	}
+	missing, err := missingFields(body, p.fields, p.item)
+	if err != nil {
+		return StatusIncompatible, err.Error()
+	}
+	if len(missing) > 0 {
+		return StatusIncompatible, fmt.Sprintf("response misses fields %s", strings.Join(missing, ", "))
+	}
+	return StatusCompatible, ""
+}
+
+// missingFields returns the fields missing in the response, and in the first item of its data
+func missingFields(body []byte, fields []string, item []string) ([]string, error) {
+	response := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("response is not an object: %w", err)
+	}
+	missing := []string{}
+	for _, field := range fields {
+		if _, ok := response[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+
+	data := []map[string]json.RawMessage{}
+	if raw, ok := response["data"]; ok {
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, errors.New("response data is not a list of objects")
+		}
+	}
+	// Accounts without the resource cannot tell whether its fields changed
+	if len(data) > 0 {
+		for _, field := range item {
+			if _, ok := data[0][field]; !ok {
+				missing = append(missing, "data[]."+field)
+			}
+		}
+	}
+	slices.Sort(missing)
+	return missing, nil
+}
+
+// truncate cuts the string to the maximum length
+func truncate(s string, maxLength int) string {
+	if len(s) > maxLength {
+		return s[:maxLength]
+	}
+	return s
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+func TestSpecVersionMatchesSpecification(t *testing.T) {
+	spec, err := os.ReadFile("../v1.0.0/openapi.json")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var openapi struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(spec, &openapi); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if openapi.Info.Version != SpecVersion {
+		t.Fatalf("SpecVersion = %s, the client is generated from %s", SpecVersion, openapi.Info.Version)
+	}
+}
+
+func TestCheckPinnedVersion(t *testing.T) {
+	for _, pinned := range []string{"", SpecVersion, "v" + SpecVersion} {
+		if err := CheckPinnedVersion(pinned); err != nil {
+			t.Errorf("CheckPinnedVersion(%q) error = %v", pinned, err)
+		}
+	}
+	if err := CheckPinnedVersion("2.0.0"); err == nil || !strings.Contains(err.Error(), "pinned to 2.0.0") {
+		t.Errorf("CheckPinnedVersion(2.0.0) error = %v", err)
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.AddInstance(models.InstanceResponse{DisplayName: "machine-0"})
+	backend.AddImage(models.ImageResponse{ImageId: "ubuntu", Name: "ubuntu-24.04", StandardImage: true})
+	backend.AddSecret("[capc] cluster", models.SecretResponseTypeSsh, "ssh-ed25519 AAAA")
+	backend.AddPrivateNetwork("[capc] cluster", "EU")
+	backend.AddTag("capc")
+	client, err := backend.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	report := Check(context.Background(), client)
+	if len(report.Results) != len(probes) {
+		t.Fatalf("Check() results = %d, expected %d", len(report.Results), len(probes))
+	}
+	for _, result := range report.Results {
+		if result.Status != StatusCompatible {
+			t.Errorf("Check() %s = %s: %s", result.Endpoint, result.Status, result.Message)
+		}
+	}
+	if err := report.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestCheckIncompatible(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/v1/compute/instances":
+			// A renamed field
+			_, _ = w.Write([]byte(`{"_pagination":{"totalPages":1},"data":[{"id":1,"displayName":"","name":"","status":"running","productId":"V76","region":"EU","ipConfig":{},"imageId":""}]}`))
+		case "/v1/private-networks":
+			// A changed type
+			_, _ = w.Write([]byte(`{"_pagination":{},"data":[{"privateNetworkId":"1"}]}`))
+		case "/v1/secrets":
+			_, _ = w.Write([]byte(`{"_pagination":{},"data":[]}`))
+		case "/v1/compute/images":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/v1/compute/instances/audits":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"unknown parameter size"}`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+	client, err := contaboclient.NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() error = %v", err)
+	}
+
+	report := Check(context.Background(), client)
+	expected := map[string]Status{
+		"instances":        StatusIncompatible,
+		"private-networks": StatusIncompatible,
+		"secrets":          StatusCompatible,
+		"images":           StatusUnknown,
+		"instance-audits":  StatusIncompatible,
+		"tags":             StatusIncompatible,
+	}
+	for _, result := range report.Results {
+		if result.Status != expected[result.Endpoint] {
+			t.Errorf("Check() %s = %s: %s, expected %s", result.Endpoint, result.Status, result.Message, expected[result.Endpoint])
+		}
+	}
+	if result := report.Results[0]; !strings.Contains(result.Message, "data[].instanceId") {
+		t.Errorf("Check() instances message = %s", result.Message)
+	}
+	if incompatible := report.Incompatible(true); len(incompatible) != 2 {
+		t.Errorf("Incompatible(true) = %v", incompatible)
+	}
+	if incompatible := report.Incompatible(false); len(incompatible) != 4 {
+		t.Errorf("Incompatible(false) = %v", incompatible)
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), "instances:") || strings.Contains(err.Error(), "tags:") {
+		t.Errorf("Err() = %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contabo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorMessageLength is the maximum length of the message of an APIError read from a response body which is not
+// an error payload
+const maxErrorMessageLength = 256
+
+var (
+	// ErrNotFound matches the APIError of the requests whose resource does not exist
+	ErrNotFound = errors.New("contabo resource not found")
+
+	// ErrRateLimited matches the APIError of the requests refused by the rate limit of the Contabo API
+	ErrRateLimited = errors.New("contabo api rate limited")
+
+	// ErrConflict matches the APIError of the requests conflicting with the state of the resource
+	ErrConflict = errors.New("contabo resource conflict")
+
+	// ErrUnauthorized matches the APIError of the requests whose access token was rejected
+	ErrUnauthorized = errors.New("contabo api unauthorized")
+)
+
+// APIError is the error payload of a Contabo API response with a non 2xx status code
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"statusCode"`
+	// Message describes the error, the response body when it is not an error payload
+	Message string `json:"message"`
+	// RetryAfter is the delay requested by the Retry-After header of the response, if any
+	RetryAfter time.Duration `json:"-"`
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Message)
+}
+
+// Is matches ErrNotFound, ErrRateLimited, ErrConflict and ErrUnauthorized by status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// Transient reports whether the error is expected to clear on its own, i.e. a rate limit or a server error
+func (e *APIError) Transient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// CheckResponse returns the error of a Contabo API request: the error of the client if any, an APIError decoded from
+// the response when its status code is not 2xx and nil otherwise, e.g.
+//
+//	resp, err := contaboClient.RetrieveInstanceWithResponse(ctx, instanceId, nil)
+//	if err := contabo.CheckResponse(resp, err); errors.Is(err, contabo.ErrNotFound) {
+func CheckResponse[T Response](resp *T, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("no response from the contabo api")
+	}
+	// Every member of Response has the fields, see paramsgen
+	value := reflect.ValueOf(resp).Elem()
+	httpResponse, _ := value.FieldByName("HTTPResponse").Interface().(*http.Response)
+	if httpResponse == nil {
+		return errors.New("no response from the contabo api")
+	}
+	if httpResponse.StatusCode >= 200 && httpResponse.StatusCode < 300 {
+		return nil
+	}
+	return NewAPIError(httpResponse, value.FieldByName("Body").Bytes())
+}
+
+// NewAPIError decodes the error payload of the response body, the body is used as message when it is not one
+func NewAPIError(httpResponse *http.Response, body []byte) *APIError {
+	apiErr := &APIError{}
+	if json.Unmarshal(body, apiErr) != nil || apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
+		if len(apiErr.Message) > maxErrorMessageLength {
+			apiErr.Message = apiErr.Message[:maxErrorMessageLength]
+		}
+	}
+	// The status code of the response prevails over the one of the payload
+	apiErr.StatusCode = httpResponse.StatusCode
+	if seconds, err := strconv.Atoi(httpResponse.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
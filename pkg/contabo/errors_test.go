@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contabo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+func TestCheckResponse(t *testing.T) {
+	ctx := context.Background()
+	backend := fake.NewBackend()
+	client, err := backend.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	resp, err := client.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{})
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		t.Errorf("CheckResponse() error = %v, want none for a 2xx response", err)
+	}
+
+	instanceResp, err := client.RetrieveInstanceWithResponse(ctx, 42, &models.RetrieveInstanceParams{})
+	err = contabo.CheckResponse(instanceResp, err)
+	var apiErr *contabo.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Not Found" {
+		t.Errorf("CheckResponse() error = %v, want the decoded 404 payload", err)
+	}
+	if !errors.Is(err, contabo.ErrNotFound) || errors.Is(err, contabo.ErrConflict) || apiErr.Transient() {
+		t.Errorf("CheckResponse() error = %v, want a permanent ErrNotFound", err)
+	}
+
+	backend.SetFaults(fake.Faults{RateLimitedRequests: 1})
+	resp, err = client.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{})
+	err = contabo.CheckResponse(resp, err)
+	if !errors.Is(err, contabo.ErrRateLimited) || !errors.As(err, &apiErr) || !apiErr.Transient() {
+		t.Errorf("CheckResponse() error = %v, want a transient ErrRateLimited", err)
+	}
+
+	clientErr := errors.New("connection refused")
+	if err := contabo.CheckResponse[contaboclient.RetrieveInstanceResponse](nil, clientErr); err != clientErr {
+		t.Errorf("CheckResponse() error = %v, want the error of the client", err)
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	httpResponse := &http.Response{StatusCode: http.StatusConflict, Header: http.Header{"Retry-After": []string{"30"}}}
+	apiErr := contabo.NewAPIError(httpResponse, []byte(`{"statusCode": 400, "message": "Instance is already being reinstalled"}`))
+	if apiErr.StatusCode != http.StatusConflict || apiErr.RetryAfter != 30*time.Second {
+		t.Errorf("NewAPIError() = %+v, want the status code and the Retry-After of the response", apiErr)
+	}
+	if !errors.Is(apiErr, contabo.ErrConflict) || apiErr.Error() != "status 409: Instance is already being reinstalled" {
+		t.Errorf("NewAPIError() = %v, want an ErrConflict with the message of the payload", apiErr)
+	}
+
+	apiErr = contabo.NewAPIError(&http.Response{StatusCode: http.StatusBadGateway}, []byte("<html>Bad Gateway</html>\n"))
+	if apiErr.Message != "<html>Bad Gateway</html>" || !apiErr.Transient() {
+		t.Errorf("NewAPIError() = %+v, want the body as message of a transient error", apiErr)
+	}
+
+	for _, statusCode := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		apiErr = contabo.NewAPIError(&http.Response{StatusCode: statusCode}, nil)
+		if !errors.Is(apiErr, contabo.ErrUnauthorized) || apiErr.Transient() {
+			t.Errorf("NewAPIError() = %+v, want a permanent ErrUnauthorized", apiErr)
+		}
+	}
+	if errors.Is(apiErr, contabo.ErrNotFound) || errors.Is(contabo.NewAPIError(&http.Response{StatusCode: http.StatusNotFound}, nil), contabo.ErrUnauthorized) {
+		t.Errorf("NewAPIError() matches ErrUnauthorized and ErrNotFound together")
+	}
+}
@@ -0,0 +1,1053 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake implements an in-memory Contabo API backend for controller tests. The backend plugs into the
+// generated client as its HTTP client, so the reconcilers run unchanged against it, and faults can be programmed to
+// test the reconcilers under API failures. It is also an http.Handler serving the API and the OAuth2 token endpoint,
+// e.g. with httptest.NewServer, for the integration tests going through the transports of the manager.
+package fake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// Server is the server URL of the clients of the backend, requests never leave the process
+	Server = "https://api.contabo.fake"
+
+	// PrivateNetworkingAddOnId is the add-on ID of private networking on instances
+	PrivateNetworkingAddOnId = 1477
+
+	// BackupAddOnId is the add-on ID of the automated backups of instances
+	BackupAddOnId = 1495
+
+	// TokenPath is the path of the OAuth2 token endpoint served by the backend, set the token URL of the token
+	// managers to the server URL followed by this path
+	TokenPath = "/auth/realms/contabo/protocol/openid-connect/token"
+)
+
+// ErrorFault answers the requests matching a method and a path with an error
+type ErrorFault struct {
+	// Method is the HTTP method of the requests, all the methods when empty
+	Method string
+
+	// PathPrefix is the prefix of the path of the requests, e.g. /v1/compute/instances, all the paths when empty
+	PathPrefix string
+
+	// StatusCode is the status code of the error responses, e.g. 500 or 503
+	StatusCode int
+
+	// Count is the number of next matching requests answered with the error
+	Count int
+}
+
+// Faults are the failures injected in the responses of the backend
+type Faults struct {
+	// Latency is added to every request
+	Latency time.Duration
+
+	// RateLimitedRequests is the number of next requests answered with 429 Too Many Requests
+	RateLimitedRequests int
+
+	// PartialCreateFailures is the number of next instance creations answered with 500 Internal Server Error after
+	// the instance was created, as when the API fails while the order is processed
+	PartialCreateFailures int
+
+	// CreateFailures is the number of next instance creations answered with 500 Internal Server Error without
+	// creating the instance
+	CreateFailures int
+
+	// IgnoredUnassignments is the number of next private network unassignments answered with success while the
+	// instance stays in the private network, as when Contabo does not apply the unassignment
+	IgnoredUnassignments int
+
+	// BusyAssignments is the number of next private network assignments answered with 409 Conflict, as when Contabo
+	// processes another assignment of the private network
+	BusyAssignments int
+
+	// Errors answer the matching requests with errors, the first fault matching a request with a remaining count
+	// applies. The token requests are not affected.
+	Errors []ErrorFault
+
+	// RejectedTokens is the number of next token requests answered with 401 Unauthorized, as when the credentials
+	// are revoked
+	RejectedTokens int
+}
+
+// Backend is an in-memory Contabo API holding instances, snapshots, private networks, VIPs, secrets, images, tags and
+// data centers
+type Backend struct {
+	mu              sync.Mutex
+	faults          Faults
+	nextId          int64
+	requests        int
+	instances       map[int64]*models.InstanceResponse
+	privateNetworks map[int64]*models.PrivateNetworkResponse
+	vips            map[string]*models.ListVipResponseData
+	secrets         map[int64]*models.SecretResponse
+	images          map[string]*models.ImageResponse
+	tags            map[int64]*models.TagResponse
+	assignments     map[int64][]models.AssignmentResponse
+	snapshots       map[int64][]models.SnapshotResponse
+	dataCenters     []models.DataCenterResponse
+}
+
+// NewBackend returns an empty backend without faults
+func NewBackend() *Backend {
+	return &Backend{
+		nextId:          100000,
+		instances:       map[int64]*models.InstanceResponse{},
+		privateNetworks: map[int64]*models.PrivateNetworkResponse{},
+		vips:            map[string]*models.ListVipResponseData{},
+		secrets:         map[int64]*models.SecretResponse{},
+		images:          map[string]*models.ImageResponse{},
+		tags:            map[int64]*models.TagResponse{},
+		assignments:     map[int64][]models.AssignmentResponse{},
+		snapshots:       map[int64][]models.SnapshotResponse{},
+	}
+}
+
+// NewClient returns a Contabo API client sending its requests to the backend, with the request IDs of the contexts
+func (b *Backend) NewClient() (*contaboclient.ClientWithResponses, error) {
+	return contaboclient.NewClientWithResponses(Server, contaboclient.WithHTTPClient(b), contaboclient.WithRequestEditorFn(contabo.RequestEditor("")))
+}
+
+// SetFaults replaces the injected faults
+func (b *Backend) SetFaults(faults Faults) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.faults = faults
+	b.faults.Errors = slices.Clone(faults.Errors)
+}
+
+// Requests returns the number of requests received, including the failed ones
+func (b *Backend) Requests() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.requests
+}
+
+// AddSecret adds a secret and returns its ID
+func (b *Backend) AddSecret(name string, secretType models.SecretResponseType, value string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.newId()
+	b.secrets[id] = &models.SecretResponse{SecretId: float32(id), Name: name, Type: secretType, Value: value, CreatedAt: time.Now()}
+	return id
+}
+
+// AddImage adds an image
+func (b *Backend) AddImage(image models.ImageResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.images[image.ImageId] = &image
+}
+
+// AddDataCenter adds a data center
+func (b *Backend) AddDataCenter(dataCenter models.DataCenterResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dataCenters = append(b.dataCenters, dataCenter)
+}
+
+// AddPrivateNetwork adds a private network in the region and returns its ID
+func (b *Backend) AddPrivateNetwork(name, region string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.newId()
+	b.privateNetworks[id] = &models.PrivateNetworkResponse{PrivateNetworkId: id, Name: name, Region: region, Cidr: "10.0.0.0/22", CreatedDate: time.Now()}
+	return id
+}
+
+// AddVip adds an unassigned floating IPv4 VIP in the region and returns its ID
+func (b *Backend) AddVip(ip, region string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	vipId := strconv.FormatInt(b.newId(), 10)
+	b.vips[ip] = &models.ListVipResponseData{
+		VipId:     vipId,
+		Region:    region,
+		IpVersion: models.ListVipResponseDataIpVersionV4,
+		Type:      ptr.To(models.ListVipResponseDataTypeFloating),
+		V4:        &models.IpV41{Ip: ip, NetmaskCidr: 32},
+	}
+	return vipId
+}
+
+// AddTag adds a tag and returns its ID
+func (b *Backend) AddTag(name string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.newId()
+	b.tags[id] = &models.TagResponse{TagId: id, Name: name}
+	return id
+}
+
+// AssignTag assigns the tag to the instance
+func (b *Backend) AssignTag(tagId int64, instanceId int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.assignTag(tagId, "instance", strconv.FormatInt(instanceId, 10))
+}
+
+// AddInstance adds an instance, its ID is assigned when zero, and returns its ID
+func (b *Backend) AddInstance(instance models.InstanceResponse) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if instance.InstanceId == 0 {
+		instance.InstanceId = b.newId()
+	}
+	if instance.Name == "" {
+		instance.Name = fmt.Sprintf("vmi%d", instance.InstanceId)
+	}
+	b.instances[instance.InstanceId] = &instance
+	return instance.InstanceId
+}
+
+// RemoveInstance removes an instance and its private network and VIP assignments, as when Contabo deletes a cancelled
+// instance
+func (b *Backend) RemoveInstance(instanceId int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.instances, instanceId)
+	for _, privateNetwork := range b.privateNetworks {
+		privateNetwork.Instances = slices.DeleteFunc(privateNetwork.Instances, func(instance models.Instances) bool {
+			return instance.InstanceId == instanceId
+		})
+	}
+	for _, vip := range b.vips {
+		if vip.ResourceId == strconv.FormatInt(instanceId, 10) {
+			unassignVip(vip)
+		}
+	}
+}
+
+// Instances returns the instances which are not cancelled, ordered by ID
+func (b *Backend) Instances() []models.InstanceResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	instances := []models.InstanceResponse{}
+	for _, instance := range b.instances {
+		if instance.CancelDate == nil {
+			instances = append(instances, *instance)
+		}
+	}
+	slices.SortFunc(instances, func(a, b models.InstanceResponse) int { return int(a.InstanceId - b.InstanceId) })
+	return instances
+}
+
+// Snapshots returns the snapshots of the instance, ordered by creation
+func (b *Backend) Snapshots(instanceId int64) []models.SnapshotResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.snapshots[instanceId])
+}
+
+// Secret returns a copy of the secret, nil when not found
+func (b *Backend) Secret(id int64) *models.SecretResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	secret, ok := b.secrets[id]
+	if !ok {
+		return nil
+	}
+	result := *secret
+	return &result
+}
+
+// PrivateNetwork returns a copy of the private network, nil when not found
+func (b *Backend) PrivateNetwork(id int64) *models.PrivateNetworkResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	privateNetwork, ok := b.privateNetworks[id]
+	if !ok {
+		return nil
+	}
+	result := *privateNetwork
+	result.Instances = slices.Clone(privateNetwork.Instances)
+	return &result
+}
+
+// Vip returns a copy of the VIP, nil when not found
+func (b *Backend) Vip(ip string) *models.ListVipResponseData {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	vip, ok := b.vips[ip]
+	if !ok {
+		return nil
+	}
+	result := *vip
+	return &result
+}
+
+// Do implements contaboclient.HttpRequestDoer
+func (b *Backend) Do(req *http.Request) (*http.Response, error) {
+	b.mu.Lock()
+	b.requests++
+	latency := b.faults.Latency
+	rateLimited := b.faults.RateLimitedRequests > 0
+	if rateLimited {
+		b.faults.RateLimitedRequests--
+	}
+	b.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	if req.URL.Path == TokenPath && req.Method == http.MethodPost {
+		return b.token(req), nil
+	}
+	if statusCode := b.injectedError(req); statusCode != 0 {
+		return response(statusCode, map[string]any{"statusCode": statusCode, "message": http.StatusText(statusCode)}), nil
+	}
+	if rateLimited {
+		return response(http.StatusTooManyRequests, map[string]any{"statusCode": 429, "message": "Too Many Requests"}), nil
+	}
+	// As the Contabo API, refuse the requests without request ID
+	if req.Header.Get(contabo.RequestIDHeader) == "" {
+		return response(http.StatusBadRequest, map[string]any{"statusCode": 400, "message": "x-request-id header is required"}), nil
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.serve(req, strings.Split(strings.Trim(req.URL.Path, "/"), "/"), body), nil
+}
+
+// ServeHTTP implements http.Handler, serving the requests as Do
+func (b *Backend) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	resp, err := b.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// token answers the OAuth2 token requests with the password grant, any credentials are accepted
+func (b *Backend) token(req *http.Request) *http.Response {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.faults.RejectedTokens > 0 {
+		b.faults.RejectedTokens--
+		return response(http.StatusUnauthorized, map[string]any{"error": "invalid_grant", "error_description": "Invalid user credentials"})
+	}
+	if err := req.ParseForm(); err != nil {
+		return badRequest(err)
+	}
+	if req.PostForm.Get("grant_type") != "password" || req.PostForm.Get("client_id") == "" || req.PostForm.Get("username") == "" {
+		return response(http.StatusBadRequest, map[string]any{"error": "invalid_request"})
+	}
+	return response(http.StatusOK, map[string]any{
+		"access_token": fmt.Sprintf("token-%d", b.newId()),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+	})
+}
+
+// injectedError returns the status code of the first error fault matching the request, 0 when none matches
+func (b *Backend) injectedError(req *http.Request) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.faults.Errors {
+		fault := &b.faults.Errors[i]
+		if fault.Count <= 0 || (fault.Method != "" && fault.Method != req.Method) || !strings.HasPrefix(req.URL.Path, fault.PathPrefix) {
+			continue
+		}
+		fault.Count--
+		return fault.StatusCode
+	}
+	return 0
+}
+
+// serve routes the request to the resource handlers
+func (b *Backend) serve(req *http.Request, path []string, body []byte) *http.Response {
+	switch {
+	case len(path) >= 3 && path[0] == "v1" && path[1] == "compute" && path[2] == "instances":
+		return b.serveInstances(req, path[3:], body)
+	case len(path) >= 2 && path[0] == "v1" && path[1] == "private-networks":
+		return b.servePrivateNetworks(req, path[2:])
+	case len(path) >= 2 && path[0] == "v1" && path[1] == "vips":
+		return b.serveVips(req, path[2:])
+	case len(path) == 2 && path[0] == "v1" && path[1] == "secrets" && req.Method == http.MethodGet:
+		return b.listSecrets(req)
+	case len(path) == 2 && path[0] == "v1" && path[1] == "secrets" && req.Method == http.MethodPost:
+		request := models.CreateSecretRequest{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return badRequest(err)
+		}
+		id := b.newId()
+		secret := &models.SecretResponse{SecretId: float32(id), Name: request.Name, Type: models.SecretResponseType(request.Type), Value: request.Value, CreatedAt: time.Now()}
+		b.secrets[id] = secret
+		return response(http.StatusCreated, models.CreateSecretResponse{Data: []models.SecretResponse{*secret}})
+	case len(path) == 3 && path[0] == "v1" && path[1] == "secrets" && req.Method == http.MethodGet:
+		id, _ := strconv.ParseInt(path[2], 10, 64)
+		if secret, ok := b.secrets[id]; ok {
+			return response(http.StatusOK, models.FindSecretResponse{Data: []models.SecretResponse{*secret}})
+		}
+	case len(path) == 3 && path[0] == "v1" && path[1] == "secrets" && req.Method == http.MethodPatch:
+		id, _ := strconv.ParseInt(path[2], 10, 64)
+		if secret, ok := b.secrets[id]; ok {
+			request := models.UpdateSecretRequest{}
+			if err := json.Unmarshal(body, &request); err != nil {
+				return badRequest(err)
+			}
+			if request.Name != nil {
+				secret.Name = *request.Name
+			}
+			if request.Value != nil {
+				secret.Value = *request.Value
+			}
+			return response(http.StatusOK, models.UpdateSecretResponse{})
+		}
+	case len(path) == 3 && path[0] == "v1" && path[1] == "secrets" && req.Method == http.MethodDelete:
+		id, _ := strconv.ParseInt(path[2], 10, 64)
+		if _, ok := b.secrets[id]; ok {
+			delete(b.secrets, id)
+			return response(http.StatusNoContent, nil)
+		}
+	case len(path) >= 2 && path[0] == "v1" && path[1] == "tags":
+		return b.serveTags(req, path[2:], body)
+	case len(path) == 3 && path[0] == "v1" && path[1] == "compute" && path[2] == "images" && req.Method == http.MethodGet:
+		return b.listImages(req)
+	case len(path) == 4 && path[0] == "v1" && path[1] == "compute" && path[2] == "images" && req.Method == http.MethodGet:
+		if image, ok := b.images[path[3]]; ok {
+			return response(http.StatusOK, models.FindImageResponse{Data: []models.ImageResponse{*image}})
+		}
+	case len(path) == 2 && path[0] == "v1" && path[1] == "data-centers" && req.Method == http.MethodGet:
+		page, size := pagination(req.URL.Query().Get("page"), req.URL.Query().Get("size"))
+		return response(http.StatusOK, models.ListDataCenterResponse{
+			UnderscorePagination: paginationMeta(len(b.dataCenters), page, size),
+			Data:                 paginate(b.dataCenters, page, size),
+		})
+	}
+	return notFound()
+}
+
+// listSecrets lists the secrets matching the name and type filters, one page at a time
+func (b *Backend) listSecrets(req *http.Request) *http.Response {
+	query := req.URL.Query()
+	secrets := []models.SecretResponse{}
+	for _, secret := range b.secrets {
+		if query.Has("name") && secret.Name != query.Get("name") {
+			continue
+		}
+		if query.Has("type") && string(secret.Type) != query.Get("type") {
+			continue
+		}
+		secrets = append(secrets, *secret)
+	}
+	slices.SortFunc(secrets, func(a, b models.SecretResponse) int { return int(a.SecretId - b.SecretId) })
+
+	page, size := pagination(query.Get("page"), query.Get("size"))
+	return response(http.StatusOK, models.ListSecretResponse{
+		UnderscorePagination: paginationMeta(len(secrets), page, size),
+		Data:                 paginate(secrets, page, size),
+	})
+}
+
+// listImages lists the images matching the standard image filter, one page at a time
+func (b *Backend) listImages(req *http.Request) *http.Response {
+	query := req.URL.Query()
+	images := []models.ImageResponse{}
+	for _, image := range b.images {
+		if query.Has("standardImage") && strconv.FormatBool(image.StandardImage) != query.Get("standardImage") {
+			continue
+		}
+		images = append(images, *image)
+	}
+	slices.SortFunc(images, func(a, b models.ImageResponse) int { return strings.Compare(a.ImageId, b.ImageId) })
+
+	page, size := pagination(query.Get("page"), query.Get("size"))
+	data := []models.ListImageResponseData{}
+	for _, image := range paginate(images, page, size) {
+		item := models.ListImageResponseData{}
+		if err := convert(image, &item); err != nil {
+			return badRequest(err)
+		}
+		data = append(data, item)
+	}
+	return response(http.StatusOK, models.ListImageResponse{
+		UnderscorePagination: paginationMeta(len(images), page, size),
+		Data:                 data,
+	})
+}
+
+// serveTags handles the tag collection, the creation of tags and the tag assignments
+func (b *Backend) serveTags(req *http.Request, path []string, body []byte) *http.Response {
+	query := req.URL.Query()
+	page, size := pagination(query.Get("page"), query.Get("size"))
+	if len(path) == 0 && req.Method == http.MethodPost {
+		request := models.CreateTagRequest{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return badRequest(err)
+		}
+		id := b.newId()
+		b.tags[id] = &models.TagResponse{TagId: id, Name: request.Name, Color: request.Color}
+		return response(http.StatusCreated, models.CreateTagResponse{Data: []models.CreateTagResponseData{{TagId: id}}})
+	}
+	if len(path) == 0 {
+		if req.Method != http.MethodGet {
+			return notFound()
+		}
+		tags := []models.TagResponse{}
+		for _, tag := range b.tags {
+			// The name filter is a substring match
+			if strings.Contains(tag.Name, query.Get("name")) {
+				tags = append(tags, *tag)
+			}
+		}
+		slices.SortFunc(tags, func(a, b models.TagResponse) int { return int(a.TagId - b.TagId) })
+		return response(http.StatusOK, models.ListTagResponse{
+			UnderscorePagination: paginationMeta(len(tags), page, size),
+			Data:                 paginate(tags, page, size),
+		})
+	}
+
+	id, _ := strconv.ParseInt(path[0], 10, 64)
+	if _, ok := b.tags[id]; !ok || len(path) < 2 || path[1] != "assignments" {
+		return notFound()
+	}
+	switch {
+	case len(path) == 2 && req.Method == http.MethodGet:
+		assignments := []models.AssignmentResponse{}
+		for _, assignment := range b.assignments[id] {
+			if !query.Has("resourceType") || assignment.ResourceType == query.Get("resourceType") {
+				assignments = append(assignments, assignment)
+			}
+		}
+		return response(http.StatusOK, models.ListAssignmentResponse{
+			UnderscorePagination: paginationMeta(len(assignments), page, size),
+			Data:                 paginate(assignments, page, size),
+		})
+	case len(path) == 4 && req.Method == http.MethodPost:
+		b.assignTag(id, path[2], path[3])
+		return response(http.StatusCreated, map[string]any{})
+	case len(path) == 4 && req.Method == http.MethodDelete:
+		b.assignments[id] = slices.DeleteFunc(b.assignments[id], func(assignment models.AssignmentResponse) bool {
+			return assignment.ResourceType == path[2] && assignment.ResourceId == path[3]
+		})
+		return response(http.StatusNoContent, nil)
+	}
+	return notFound()
+}
+
+// assignTag assigns the tag to the resource, once
+func (b *Backend) assignTag(tagId int64, resourceType, resourceId string) {
+	for _, assignment := range b.assignments[tagId] {
+		if assignment.ResourceType == resourceType && assignment.ResourceId == resourceId {
+			return
+		}
+	}
+	b.assignments[tagId] = append(b.assignments[tagId], models.AssignmentResponse{
+		TagId:        tagId,
+		TagName:      b.tags[tagId].Name,
+		ResourceType: resourceType,
+		ResourceId:   resourceId,
+	})
+}
+
+// serveInstances handles the instance collection, instances and instance actions
+func (b *Backend) serveInstances(req *http.Request, path []string, body []byte) *http.Response {
+	if len(path) == 0 {
+		switch req.Method {
+		case http.MethodGet:
+			return b.listInstances(req)
+		case http.MethodPost:
+			return b.createInstance(body)
+		}
+		return notFound()
+	}
+	if path[0] == "audits" || path[0] == "actions" {
+		return response(http.StatusOK, models.ListInstancesAuditResponse{
+			UnderscorePagination: models.PaginationMeta{Page: 1, TotalPages: 1},
+			Data:                 []models.InstancesAuditResponse{},
+		})
+	}
+
+	id, err := strconv.ParseInt(path[0], 10, 64)
+	instance, ok := b.instances[id]
+	if err != nil || !ok {
+		return notFound()
+	}
+	switch {
+	case len(path) == 1 && req.Method == http.MethodGet:
+		return response(http.StatusOK, models.FindInstanceResponse{Data: []models.InstanceResponse{*instance}})
+	case len(path) == 1 && req.Method == http.MethodPatch:
+		request := models.PatchInstanceRequest{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return badRequest(err)
+		}
+		if request.DisplayName != nil {
+			instance.DisplayName = *request.DisplayName
+		}
+		return response(http.StatusOK, models.PatchInstanceResponse{})
+	case len(path) == 1 && req.Method == http.MethodPut:
+		instance.Status = models.InstanceStatusRunning
+		return response(http.StatusOK, models.ReinstallInstanceResponse{})
+	case len(path) == 2 && path[1] == "cancel" && req.Method == http.MethodPost:
+		instance.CancelDate = &openapi_types.Date{Time: time.Now()}
+		return response(http.StatusCreated, models.CancelInstanceResponse{})
+	case len(path) == 2 && path[1] == "upgrade" && req.Method == http.MethodPost:
+		request := models.UpgradeInstanceRequest{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return badRequest(err)
+		}
+		if request.PrivateNetworking != nil {
+			addPrivateNetworking(instance)
+		}
+		if request.Backup != nil {
+			addBackup(instance)
+		}
+		return response(http.StatusOK, models.PatchInstanceResponse{})
+	case len(path) == 3 && path[1] == "actions" && req.Method == http.MethodPost:
+		switch path[2] {
+		case "start", "restart":
+			instance.Status = models.InstanceStatusRunning
+		case "stop", "shutdown":
+			instance.Status = models.InstanceStatusStopped
+		default:
+			return notFound()
+		}
+		return response(http.StatusCreated, map[string]any{"data": []map[string]any{{"instanceId": id, "action": path[2]}}})
+	case len(path) >= 2 && path[1] == "snapshots":
+		return b.serveSnapshots(req, id, path[2:], body)
+	}
+	return notFound()
+}
+
+// serveSnapshots handles the snapshot collection and snapshots of an instance
+func (b *Backend) serveSnapshots(req *http.Request, instanceId int64, path []string, body []byte) *http.Response {
+	switch {
+	case len(path) == 0 && req.Method == http.MethodGet:
+		snapshots := b.snapshots[instanceId]
+		page, size := pagination(req.URL.Query().Get("page"), req.URL.Query().Get("size"))
+		return response(http.StatusOK, models.ListSnapshotResponse{
+			UnderscorePagination: paginationMeta(len(snapshots), page, size),
+			Data:                 slices.Clone(paginate(snapshots, page, size)),
+		})
+	case len(path) == 0 && req.Method == http.MethodPost:
+		request := models.CreateSnapshotRequest{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return badRequest(err)
+		}
+		snapshot := models.SnapshotResponse{
+			SnapshotId:  fmt.Sprintf("snap%d", b.newId()),
+			InstanceId:  instanceId,
+			Name:        request.Name,
+			Description: deref(request.Description),
+			CreatedDate: time.Now(),
+		}
+		b.snapshots[instanceId] = append(b.snapshots[instanceId], snapshot)
+		return response(http.StatusCreated, models.CreateSnapshotResponse{Data: []models.SnapshotResponse{snapshot}})
+	case len(path) == 1 && req.Method == http.MethodDelete:
+		snapshots := b.snapshots[instanceId]
+		index := slices.IndexFunc(snapshots, func(snapshot models.SnapshotResponse) bool { return snapshot.SnapshotId == path[0] })
+		if index < 0 {
+			return notFound()
+		}
+		b.snapshots[instanceId] = slices.Delete(snapshots, index, index+1)
+		return response(http.StatusNoContent, nil)
+	}
+	return notFound()
+}
+
+// listInstances lists the instances matching the display name, name, products and region filters, one page at a time
+func (b *Backend) listInstances(req *http.Request) *http.Response {
+	query := req.URL.Query()
+	instances := []models.InstanceResponse{}
+	for _, instance := range b.instances {
+		if query.Has("displayName") && instance.DisplayName != query.Get("displayName") {
+			continue
+		}
+		if query.Has("name") && instance.Name != query.Get("name") {
+			continue
+		}
+		if query.Has("productIds") && !slices.Contains(strings.Split(query.Get("productIds"), ","), instance.ProductId) {
+			continue
+		}
+		if query.Has("region") && instance.Region != query.Get("region") {
+			continue
+		}
+		instances = append(instances, *instance)
+	}
+	slices.SortFunc(instances, func(a, b models.InstanceResponse) int { return int(a.InstanceId - b.InstanceId) })
+
+	page, size := pagination(query.Get("page"), query.Get("size"))
+	data := []models.ListInstancesResponseData{}
+	for _, instance := range paginate(instances, page, size) {
+		item := models.ListInstancesResponseData{}
+		if err := convert(instance, &item); err != nil {
+			return badRequest(err)
+		}
+		data = append(data, item)
+	}
+	return response(http.StatusOK, models.ListInstancesResponse{
+		UnderscorePagination: paginationMeta(len(instances), page, size),
+		Data:                 data,
+	})
+}
+
+// createInstance creates a running instance, subject to the creation faults
+func (b *Backend) createInstance(body []byte) *http.Response {
+	if b.faults.CreateFailures > 0 {
+		b.faults.CreateFailures--
+		return response(http.StatusInternalServerError, map[string]any{"statusCode": 500, "message": "Internal Server Error"})
+	}
+
+	request := models.CreateInstanceRequest{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return badRequest(err)
+	}
+	id := b.newId()
+	instance := &models.InstanceResponse{
+		InstanceId:  id,
+		Name:        fmt.Sprintf("vmi%d", id),
+		DisplayName: deref(request.DisplayName),
+		ProductId:   deref(request.ProductId),
+		Region:      string(deref(request.Region)),
+		ImageId:     deref(request.ImageId),
+		SshKeys:     deref(request.SshKeys),
+		Status:      models.InstanceStatusRunning,
+		CreatedDate: time.Now(),
+		IpConfig: models.IpConfig{
+			V4: models.IpV4{Ip: fmt.Sprintf("203.0.113.%d", id%250+1), NetmaskCidr: 24, Gateway: "203.0.113.254"},
+		},
+	}
+	if request.AddOns != nil && request.AddOns.PrivateNetworking != nil {
+		addPrivateNetworking(instance)
+	}
+	if request.AddOns != nil && request.AddOns.Backup != nil {
+		addBackup(instance)
+	}
+	if request.AddOns != nil {
+		addAdditionalIps(instance, request.AddOns)
+	}
+	b.instances[id] = instance
+
+	if b.faults.PartialCreateFailures > 0 {
+		b.faults.PartialCreateFailures--
+		return response(http.StatusInternalServerError, map[string]any{"statusCode": 500, "message": "Internal Server Error"})
+	}
+	return response(http.StatusCreated, models.CreateInstanceResponse{Data: []models.CreateInstanceResponseData{{
+		InstanceId:  id,
+		ProductId:   instance.ProductId,
+		Region:      instance.Region,
+		ImageId:     instance.ImageId,
+		SshKeys:     instance.SshKeys,
+		CreatedDate: instance.CreatedDate,
+	}}})
+}
+
+// addAdditionalIps allocates the additional IPv4 addresses ordered with the instance, one for the additional IPs
+// add-on and one per quantity of the other add-ons but the backups, all of them are treated as additional IPv4
+// addresses
+func addAdditionalIps(instance *models.InstanceResponse, addOns *models.CreateInstanceAddons) {
+	count := int64(0)
+	if addOns.AdditionalIps != nil {
+		count++
+	}
+	for _, addOn := range deref(addOns.AddonsIds) {
+		if addOn.Id != BackupAddOnId {
+			count += addOn.Quantity
+		}
+		instance.AddOns = append(instance.AddOns, models.AddOnResponse{Id: addOn.Id, Quantity: addOn.Quantity})
+	}
+	for i := int64(0); i < count; i++ {
+		instance.AdditionalIps = append(instance.AdditionalIps, models.AdditionalIp{
+			V4: models.IpV4{Ip: fmt.Sprintf("198.51.100.%d", (instance.InstanceId*16+i)%250+1), NetmaskCidr: 32, Gateway: instance.IpConfig.V4.Gateway},
+		})
+	}
+}
+
+// servePrivateNetworks handles the private network collection, private networks and instance assignments
+func (b *Backend) servePrivateNetworks(req *http.Request, path []string) *http.Response {
+	if len(path) == 0 && req.Method == http.MethodGet {
+		privateNetworks := []models.PrivateNetworkResponse{}
+		for _, privateNetwork := range b.privateNetworks {
+			if req.URL.Query().Has("name") && privateNetwork.Name != req.URL.Query().Get("name") {
+				continue
+			}
+			privateNetworks = append(privateNetworks, *privateNetwork)
+		}
+		slices.SortFunc(privateNetworks, func(a, b models.PrivateNetworkResponse) int { return int(a.PrivateNetworkId - b.PrivateNetworkId) })
+		page, size := pagination(req.URL.Query().Get("page"), req.URL.Query().Get("size"))
+		data := []models.ListPrivateNetworkResponseData{}
+		for _, privateNetwork := range paginate(privateNetworks, page, size) {
+			item := models.ListPrivateNetworkResponseData{}
+			if err := convert(privateNetwork, &item); err != nil {
+				return badRequest(err)
+			}
+			data = append(data, item)
+		}
+		return response(http.StatusOK, models.ListPrivateNetworkResponse{
+			UnderscorePagination: paginationMeta(len(privateNetworks), page, size),
+			Data:                 data,
+		})
+	}
+	if len(path) == 0 {
+		return notFound()
+	}
+
+	id, err := strconv.ParseInt(path[0], 10, 64)
+	privateNetwork, ok := b.privateNetworks[id]
+	if err != nil || !ok {
+		return notFound()
+	}
+	if len(path) == 1 && req.Method == http.MethodGet {
+		return response(http.StatusOK, models.FindPrivateNetworkResponse{Data: []models.PrivateNetworkResponse{*privateNetwork}})
+	}
+	if len(path) == 1 && req.Method == http.MethodDelete {
+		delete(b.privateNetworks, id)
+		return response(http.StatusNoContent, nil)
+	}
+	if len(path) != 3 || path[1] != "instances" {
+		return notFound()
+	}
+	instanceId, err := strconv.ParseInt(path[2], 10, 64)
+	if _, ok := b.instances[instanceId]; err != nil || !ok {
+		return notFound()
+	}
+	assigned := slices.IndexFunc(privateNetwork.Instances, func(instance models.Instances) bool { return instance.InstanceId == instanceId })
+	switch req.Method {
+	case http.MethodPost:
+		if b.faults.BusyAssignments > 0 {
+			b.faults.BusyAssignments--
+			return response(http.StatusConflict, map[string]any{"statusCode": 409, "message": "Private network is processing another assignment"})
+		}
+		if assigned < 0 {
+			instance := b.instances[instanceId]
+			privateNetwork.Instances = append(privateNetwork.Instances, models.Instances{
+				InstanceId:      instanceId,
+				Name:            instance.Name,
+				DisplayName:     instance.DisplayName,
+				IpConfig:        instance.IpConfig,
+				PrivateIpConfig: models.PrivateIpConfig{V4: []models.IpV4{{Ip: fmt.Sprintf("10.0.0.%d", len(privateNetwork.Instances)+2), NetmaskCidr: 22}}},
+			})
+		}
+		return response(http.StatusCreated, models.AssignInstancePrivateNetworkResponse{})
+	case http.MethodDelete:
+		if b.faults.IgnoredUnassignments > 0 {
+			b.faults.IgnoredUnassignments--
+		} else if assigned >= 0 {
+			privateNetwork.Instances = slices.Delete(privateNetwork.Instances, assigned, assigned+1)
+		}
+		return response(http.StatusCreated, models.UnassignInstancePrivateNetworkResponse{})
+	}
+	return notFound()
+}
+
+// serveVips handles the VIP collection, VIPs and their instance assignments
+func (b *Backend) serveVips(req *http.Request, path []string) *http.Response {
+	query := req.URL.Query()
+	if len(path) == 0 && req.Method == http.MethodGet {
+		vips := []models.ListVipResponseData{}
+		for _, vip := range b.vips {
+			if (query.Has("region") && vip.Region != query.Get("region")) ||
+				(query.Has("ip") && vip.V4.Ip != query.Get("ip")) ||
+				(query.Has("resourceId") && vip.ResourceId != query.Get("resourceId")) ||
+				(query.Has("type") && string(*vip.Type) != query.Get("type")) {
+				continue
+			}
+			vips = append(vips, *vip)
+		}
+		slices.SortFunc(vips, func(a, b models.ListVipResponseData) int { return strings.Compare(a.V4.Ip, b.V4.Ip) })
+		page, size := pagination(query.Get("page"), query.Get("size"))
+		return response(http.StatusOK, models.ListVipResponse{
+			UnderscorePagination: paginationMeta(len(vips), page, size),
+			Data:                 paginate(vips, page, size),
+		})
+	}
+	if len(path) == 0 {
+		return notFound()
+	}
+
+	vip, ok := b.vips[path[0]]
+	if !ok {
+		return notFound()
+	}
+	if len(path) == 1 && req.Method == http.MethodGet {
+		item := models.VipResponse{}
+		if err := convert(vip, &item); err != nil {
+			return badRequest(err)
+		}
+		return response(http.StatusOK, models.FindVipResponse{Data: []models.VipResponse{item}})
+	}
+	if len(path) != 3 || path[1] != string(models.AssignIpParamsResourceTypeInstances) {
+		return notFound()
+	}
+	instanceId, err := strconv.ParseInt(path[2], 10, 64)
+	instance, ok := b.instances[instanceId]
+	if err != nil || !ok {
+		return notFound()
+	}
+	switch req.Method {
+	case http.MethodPost:
+		// As the Contabo API, a VIP assigned to another instance must be unassigned first
+		if vip.ResourceId != "" && vip.ResourceId != path[2] {
+			return response(http.StatusConflict, map[string]any{"statusCode": 409, "message": "VIP is assigned to another resource"})
+		}
+		vip.ResourceId = path[2]
+		vip.ResourceName = instance.Name
+		vip.ResourceDisplayName = instance.DisplayName
+		vip.ResourceType = ptr.To(models.ListVipResponseDataResourceTypeInstances)
+		item := models.VipResponse{}
+		if err := convert(vip, &item); err != nil {
+			return badRequest(err)
+		}
+		return response(http.StatusOK, models.AssignVipResponse{Data: []models.VipResponse{item}})
+	case http.MethodDelete:
+		if vip.ResourceId != path[2] {
+			return notFound()
+		}
+		unassignVip(vip)
+		return response(http.StatusNoContent, nil)
+	}
+	return notFound()
+}
+
+// unassignVip removes the resource a VIP is assigned to
+func unassignVip(vip *models.ListVipResponseData) {
+	vip.ResourceId = ""
+	vip.ResourceName = ""
+	vip.ResourceDisplayName = ""
+	vip.ResourceType = ptr.To(models.ListVipResponseDataResourceTypeNull)
+}
+
+// newId returns a new resource ID, shared by all resources
+func (b *Backend) newId() int64 {
+	b.nextId++
+	return b.nextId
+}
+
+// addPrivateNetworking adds the private networking add-on to the instance
+func addPrivateNetworking(instance *models.InstanceResponse) {
+	if !slices.ContainsFunc(instance.AddOns, func(addOn models.AddOnResponse) bool { return addOn.Id == PrivateNetworkingAddOnId }) {
+		instance.AddOns = append(instance.AddOns, models.AddOnResponse{Id: PrivateNetworkingAddOnId, Quantity: 1})
+	}
+}
+
+// addBackup adds the automated backups add-on to the instance, every upgrade adds one as every upgrade is billed
+func addBackup(instance *models.InstanceResponse) {
+	for i := range instance.AddOns {
+		if instance.AddOns[i].Id == BackupAddOnId {
+			instance.AddOns[i].Quantity++
+			return
+		}
+	}
+	instance.AddOns = append(instance.AddOns, models.AddOnResponse{Id: BackupAddOnId, Quantity: 1})
+}
+
+// pagination returns the requested page and size, the first page of 100 items by default
+func pagination(pageParam, sizeParam string) (int, int) {
+	page, err := strconv.Atoi(pageParam)
+	if err != nil || page < 1 {
+		page = 1
+	}
+	size, err := strconv.Atoi(sizeParam)
+	if err != nil || size < 1 {
+		size = 100
+	}
+	return page, size
+}
+
+// paginate returns the items of the page
+func paginate[T any](items []T, page, size int) []T {
+	start := min((page-1)*size, len(items))
+	return items[start:min(start+size, len(items))]
+}
+
+// paginationMeta describes the page of a list of total items
+func paginationMeta(total, page, size int) models.PaginationMeta {
+	return models.PaginationMeta{
+		Page:          float32(page),
+		Size:          float32(size),
+		TotalElements: float32(total),
+		TotalPages:    float32(math.Ceil(float64(total) / float64(size))),
+	}
+}
+
+// convert copies the JSON fields of a model into another model
+func convert(from, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// deref returns the value of a pointer, the zero value when nil
+func deref[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return *value
+}
+
+// response returns a JSON response
+func response(statusCode int, body any) *http.Response {
+	data, _ := json.Marshal(body)
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+	}
+}
+
+// notFound returns a 404 Not Found response
+func notFound() *http.Response {
+	return response(http.StatusNotFound, map[string]any{"statusCode": 404, "message": "Not Found"})
+}
+
+// badRequest returns a 400 Bad Request response
+func badRequest(err error) *http.Response {
+	return response(http.StatusBadRequest, map[string]any{"statusCode": 400, "message": err.Error()})
+}
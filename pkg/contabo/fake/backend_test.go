@@ -0,0 +1,309 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/auth"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+func createInstanceRequest(displayName string) models.CreateInstanceRequest {
+	return models.CreateInstanceRequest{
+		ProductId:   ptr.To("V76"),
+		Period:      1,
+		Region:      ptr.To(models.EU),
+		SshKeys:     &[]int64{42},
+		DisplayName: ptr.To(displayName),
+		AddOns: &models.CreateInstanceAddons{
+			PrivateNetworking: ptr.To(map[string]interface{}{}),
+		},
+	}
+}
+
+func TestBackendInstances(t *testing.T) {
+	ctx := context.Background()
+	backend := NewBackend()
+	client, err := backend.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	createResp, err := client.CreateInstanceWithResponse(ctx, &models.CreateInstanceParams{}, createInstanceRequest("machine-0"))
+	if err != nil || createResp.JSON201 == nil {
+		t.Fatalf("CreateInstance() status = %d, error = %v", createResp.StatusCode(), err)
+	}
+	instanceId := createResp.JSON201.Data[0].InstanceId
+	backend.AddInstance(models.InstanceResponse{DisplayName: "machine-1", Status: models.InstanceStatusRunning})
+
+	listResp, err := client.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{DisplayName: ptr.To("machine-0")})
+	if err != nil || listResp.JSON200 == nil {
+		t.Fatalf("RetrieveInstancesList() status = %d, error = %v", listResp.StatusCode(), err)
+	}
+	if len(listResp.JSON200.Data) != 1 || listResp.JSON200.Data[0].InstanceId != instanceId {
+		t.Fatalf("RetrieveInstancesList() = %+v, want instance %d", listResp.JSON200.Data, instanceId)
+	}
+	if len(listResp.JSON200.Data[0].AddOns) != 1 || listResp.JSON200.Data[0].AddOns[0].Id != PrivateNetworkingAddOnId {
+		t.Errorf("AddOns = %+v, want private networking", listResp.JSON200.Data[0].AddOns)
+	}
+
+	pageResp, err := client.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{Page: ptr.To(int64(2)), Size: ptr.To(int64(1))})
+	if err != nil || pageResp.JSON200 == nil {
+		t.Fatalf("RetrieveInstancesList() status = %d, error = %v", pageResp.StatusCode(), err)
+	}
+	if len(pageResp.JSON200.Data) != 1 || pageResp.JSON200.Data[0].DisplayName != "machine-1" || pageResp.JSON200.UnderscorePagination.TotalPages != 2 {
+		t.Errorf("RetrieveInstancesList() page 2 = %+v, pagination %+v", pageResp.JSON200.Data, pageResp.JSON200.UnderscorePagination)
+	}
+
+	if _, err := client.Stop(ctx, instanceId, nil); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	instanceResp, err := client.RetrieveInstanceWithResponse(ctx, instanceId, nil)
+	if err != nil || instanceResp.JSON200 == nil {
+		t.Fatalf("RetrieveInstance() status = %d, error = %v", instanceResp.StatusCode(), err)
+	}
+	if instanceResp.JSON200.Data[0].Status != models.InstanceStatusStopped {
+		t.Errorf("Status = %s, want %s", instanceResp.JSON200.Data[0].Status, models.InstanceStatusStopped)
+	}
+
+	cancelResp, err := client.CancelInstanceWithResponse(ctx, instanceId, &models.CancelInstanceParams{}, models.CancelInstanceRequest{})
+	if err != nil || cancelResp.StatusCode() != http.StatusCreated {
+		t.Fatalf("CancelInstance() status = %d, error = %v", cancelResp.StatusCode(), err)
+	}
+	if instances := backend.Instances(); len(instances) != 1 || instances[0].DisplayName != "machine-1" {
+		t.Errorf("Instances() = %+v, want only machine-1", instances)
+	}
+}
+
+func TestBackendPrivateNetworks(t *testing.T) {
+	ctx := context.Background()
+	backend := NewBackend()
+	client, err := backend.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	privateNetworkId := backend.AddPrivateNetwork("network", "EU")
+	instanceId := backend.AddInstance(models.InstanceResponse{Status: models.InstanceStatusRunning})
+
+	if resp, err := client.AssignInstancePrivateNetworkWithResponse(ctx, privateNetworkId, instanceId, nil); err != nil || resp.StatusCode() != http.StatusCreated {
+		t.Fatalf("AssignInstancePrivateNetwork() error = %v", err)
+	}
+	resp, err := client.RetrievePrivateNetworkWithResponse(ctx, privateNetworkId, nil)
+	if err != nil || resp.JSON200 == nil {
+		t.Fatalf("RetrievePrivateNetwork() status = %d, error = %v", resp.StatusCode(), err)
+	}
+	if instances := resp.JSON200.Data[0].Instances; len(instances) != 1 || instances[0].InstanceId != instanceId || len(instances[0].PrivateIpConfig.V4) != 1 {
+		t.Fatalf("Instances = %+v, want instance %d with a private IP", instances, instanceId)
+	}
+
+	if resp, err := client.UnassignInstancePrivateNetworkWithResponse(ctx, privateNetworkId, instanceId, nil); err != nil || resp.StatusCode() != http.StatusCreated {
+		t.Fatalf("UnassignInstancePrivateNetwork() error = %v", err)
+	}
+	if privateNetwork := backend.PrivateNetwork(privateNetworkId); len(privateNetwork.Instances) != 0 {
+		t.Errorf("Instances = %+v, want none", privateNetwork.Instances)
+	}
+}
+
+func TestBackendVips(t *testing.T) {
+	ctx := context.Background()
+	backend := NewBackend()
+	client, err := backend.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	backend.AddVip("192.0.2.10", "EU")
+	first := backend.AddInstance(models.InstanceResponse{Status: models.InstanceStatusRunning})
+	second := backend.AddInstance(models.InstanceResponse{Status: models.InstanceStatusRunning})
+
+	if resp, err := client.AssignIpWithResponse(ctx, "192.0.2.10", models.AssignIpParamsResourceTypeInstances, first, nil); err != nil || resp.JSON200 == nil {
+		t.Fatalf("AssignIp() status = %d, error = %v", resp.StatusCode(), err)
+	}
+	if resp, err := client.AssignIpWithResponse(ctx, "192.0.2.10", models.AssignIpParamsResourceTypeInstances, second, nil); err != nil || resp.StatusCode() != http.StatusConflict {
+		t.Fatalf("AssignIp() to another instance status = %d, error = %v, want 409 Conflict", resp.StatusCode(), err)
+	}
+	listResp, err := client.RetrieveVipListWithResponse(ctx, &models.RetrieveVipListParams{Region: ptr.To("EU")})
+	if err != nil || listResp.JSON200 == nil {
+		t.Fatalf("RetrieveVipList() status = %d, error = %v", listResp.StatusCode(), err)
+	}
+	if vips := listResp.JSON200.Data; len(vips) != 1 || vips[0].ResourceId != strconv.FormatInt(first, 10) {
+		t.Fatalf("RetrieveVipList() = %+v, want the VIP assigned to instance %d", vips, first)
+	}
+
+	backend.RemoveInstance(first)
+	if vip := backend.Vip("192.0.2.10"); vip.ResourceId != "" {
+		t.Errorf("ResourceId = %q, want the VIP unassigned with its instance", vip.ResourceId)
+	}
+}
+
+func TestBackendSnapshots(t *testing.T) {
+	ctx := context.Background()
+	backend := NewBackend()
+	client, err := backend.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	instanceId := backend.AddInstance(models.InstanceResponse{Status: models.InstanceStatusRunning})
+
+	createResp, err := client.CreateSnapshotWithResponse(ctx, instanceId, &models.CreateSnapshotParams{}, models.CreateSnapshotRequest{Name: "capc test"})
+	if err != nil || createResp.JSON201 == nil {
+		t.Fatalf("CreateSnapshot() status = %d, error = %v", createResp.StatusCode(), err)
+	}
+	listResp, err := client.RetrieveSnapshotListWithResponse(ctx, instanceId, &models.RetrieveSnapshotListParams{})
+	if err != nil || listResp.JSON200 == nil || len(listResp.JSON200.Data) != 1 || listResp.JSON200.Data[0].Name != "capc test" {
+		t.Fatalf("RetrieveSnapshotList() status = %d, error = %v", listResp.StatusCode(), err)
+	}
+	deleteResp, err := client.DeleteSnapshotWithResponse(ctx, instanceId, createResp.JSON201.Data[0].SnapshotId, nil)
+	if err != nil || deleteResp.StatusCode() != http.StatusNoContent {
+		t.Fatalf("DeleteSnapshot() status = %d, error = %v", deleteResp.StatusCode(), err)
+	}
+	if snapshots := backend.Snapshots(instanceId); len(snapshots) != 0 {
+		t.Errorf("Snapshots() = %d, want 0", len(snapshots))
+	}
+}
+
+func TestBackendFaults(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		faults        Faults
+		wantStatus    []int
+		wantInstances int
+	}{
+		{
+			name:          "rate limited requests",
+			faults:        Faults{RateLimitedRequests: 2},
+			wantStatus:    []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusCreated},
+			wantInstances: 1,
+		},
+		{
+			name:          "create failures",
+			faults:        Faults{CreateFailures: 1},
+			wantStatus:    []int{http.StatusInternalServerError, http.StatusCreated},
+			wantInstances: 1,
+		},
+		{
+			name:          "partial create failures",
+			faults:        Faults{PartialCreateFailures: 1},
+			wantStatus:    []int{http.StatusInternalServerError, http.StatusCreated},
+			wantInstances: 2,
+		},
+		{
+			name: "error faults",
+			faults: Faults{Errors: []ErrorFault{
+				{Method: http.MethodGet, StatusCode: http.StatusServiceUnavailable, Count: 3},
+				{Method: http.MethodPost, PathPrefix: "/v1/compute/instances", StatusCode: http.StatusBadGateway, Count: 1},
+			}},
+			wantStatus:    []int{http.StatusBadGateway, http.StatusCreated},
+			wantInstances: 1,
+		},
+		{
+			name:          "latency",
+			faults:        Faults{Latency: 10 * time.Millisecond},
+			wantStatus:    []int{http.StatusCreated},
+			wantInstances: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := NewBackend()
+			client, err := backend.NewClient()
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			backend.SetFaults(tt.faults)
+
+			start := time.Now()
+			for i, want := range tt.wantStatus {
+				resp, err := client.CreateInstanceWithResponse(ctx, &models.CreateInstanceParams{}, createInstanceRequest("machine"))
+				if err != nil {
+					t.Fatalf("CreateInstance() error = %v", err)
+				}
+				if resp.StatusCode() != want {
+					t.Errorf("CreateInstance() #%d status = %d, want %d", i, resp.StatusCode(), want)
+				}
+			}
+			if elapsed := time.Since(start); elapsed < tt.faults.Latency {
+				t.Errorf("elapsed = %s, want at least %s", elapsed, tt.faults.Latency)
+			}
+			if instances := backend.Instances(); len(instances) != tt.wantInstances {
+				t.Errorf("Instances() = %d, want %d", len(instances), tt.wantInstances)
+			}
+			if requests := backend.Requests(); requests != len(tt.wantStatus) {
+				t.Errorf("Requests() = %d, want %d", requests, len(tt.wantStatus))
+			}
+		})
+	}
+}
+
+func TestBackendLatencyHonorsContext(t *testing.T) {
+	backend := NewBackend()
+	client, err := backend.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	backend.SetFaults(Faults{Latency: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.RetrieveInstancesListWithResponse(ctx, &models.RetrieveInstancesListParams{}); err == nil {
+		t.Fatal("RetrieveInstancesList() error = nil, want context deadline exceeded")
+	}
+}
+
+func TestBackendServer(t *testing.T) {
+	ctx := context.Background()
+	backend := NewBackend()
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	tokenManager := auth.NewTokenManager("client", "secret", "user@example.com", "password").WithTokenURL(server.URL + TokenPath)
+	client, err := contaboclient.NewClientWithResponses(server.URL,
+		contaboclient.WithHTTPClient(&http.Client{Transport: auth.NewFailoverTransport(nil, auth.NewFailoverTokenManager(tokenManager))}),
+		contaboclient.WithRequestEditorFn(contabo.RequestEditor("")),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() error = %v", err)
+	}
+
+	createResp, err := client.CreateInstanceWithResponse(ctx, &models.CreateInstanceParams{}, createInstanceRequest("machine-0"))
+	if err != nil || createResp.JSON201 == nil {
+		t.Fatalf("CreateInstance() status = %d, error = %v", createResp.StatusCode(), err)
+	}
+	if instances := backend.Instances(); len(instances) != 1 || instances[0].DisplayName != "machine-0" {
+		t.Errorf("Instances() = %+v, want machine-0", instances)
+	}
+
+	tokenManager.Invalidate()
+	backend.SetFaults(Faults{RejectedTokens: 1})
+	if _, err := tokenManager.GetToken(); err == nil {
+		t.Error("GetToken() with rejected credentials error = nil")
+	}
+	if _, err := tokenManager.GetToken(); err != nil {
+		t.Errorf("GetToken() error = %v", err)
+	}
+}
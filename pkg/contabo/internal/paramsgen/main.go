@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command paramsgen generates the Params and Response constraints of the contabo package, the unions of the
+// parameters structs of the generated Contabo API models with the x-request-id header and of the response structs of
+// the generated Contabo API client.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+func main() {
+	models := flag.String("models", "v1.0.0/models", "directory of the generated models package")
+	output := flag.String("output", "params_gen.go", "generated file of the Params constraint")
+	client := flag.String("client", "v1.0.0/client", "directory of the generated client package")
+	responsesOutput := flag.String("responses-output", "responses_gen.go", "generated file of the Response constraint")
+	flag.Parse()
+
+	params, err := structsWithFields(*models, "XRequestId")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := writeUnion(*output, "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models", "models",
+		"Params is the union of the parameters structs of the Contabo API requests", "Params", params); err != nil {
+		log.Fatal(err)
+	}
+
+	responses, err := structsWithFields(*client, "Body", "HTTPResponse")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := writeUnion(*responsesOutput, "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client", "contaboclient",
+		"Response is the union of the response structs of the Contabo API requests", "Response", responses); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeUnion writes the constraint named after the union of the types of the package to the file
+func writeUnion(output, importPath, alias, doc, name string, types []string) error {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by paramsgen. DO NOT EDIT.\n\n")
+	buf.WriteString("package contabo\n\n")
+	if alias == importPath[strings.LastIndex(importPath, "/")+1:] {
+		fmt.Fprintf(&buf, "import %q\n\n", importPath)
+	} else {
+		fmt.Fprintf(&buf, "import %s %q\n\n", alias, importPath)
+	}
+	fmt.Fprintf(&buf, "// %s\n", doc)
+	fmt.Fprintf(&buf, "type %s interface {\n\t", name)
+	for i, typeName := range types {
+		if i > 0 {
+			buf.WriteString(" |\n\t\t")
+		}
+		fmt.Fprintf(&buf, "%s.%s", alias, typeName)
+	}
+	buf.WriteString("\n}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format the generated code: %w", err)
+	}
+	return os.WriteFile(output, source, 0o644)
+}
+
+// structsWithFields returns the sorted names of the structs of the package with all the fields
+func structsWithFields(dir string, fields ...string) ([]string, error) {
+	packages, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+	}
+	names := []string{}
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if ok && hasFields(structType, fields...) {
+						names = append(names, typeSpec.Name.Name)
+					}
+				}
+			}
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// hasFields reports whether the struct has all the fields
+func hasFields(structType *ast.StructType, names ...string) bool {
+	for _, name := range names {
+		if !hasField(structType, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasField reports whether the struct has the field
+func hasField(structType *ast.StructType, name string) bool {
+	for _, field := range structType.Fields.List {
+		for _, fieldName := range field.Names {
+			if fieldName.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
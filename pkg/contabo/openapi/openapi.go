@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openapi validates raw payloads against the schemas of the Contabo OpenAPI specification bundled with the
+// provider, e.g. the properties passed through to the Contabo API which the generated models do not know.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	contaboapi "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0"
+)
+
+const componentRefPrefix = "#/components/schemas/"
+
+// Schema is the subset of an OpenAPI schema object checked by Validate
+type Schema struct {
+	Ref        string             `json:"$ref,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Enum       []any              `json:"enum,omitempty"`
+	MinLength  *int               `json:"minLength,omitempty"`
+	MaxLength  *int               `json:"maxLength,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalProperties is either a boolean or a schema
+	AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+	Required             []string        `json:"required,omitempty"`
+	Items                *Schema         `json:"items,omitempty"`
+	AllOf                []*Schema       `json:"allOf,omitempty"`
+}
+
+// Spec holds the component schemas of an OpenAPI specification
+type Spec struct {
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Load parses an OpenAPI specification
+func Load(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse the OpenAPI specification: %w", err)
+	}
+	return spec, nil
+}
+
+// Contabo returns the Contabo OpenAPI specification the client and the models are generated from
+var Contabo = sync.OnceValues(func() (*Spec, error) {
+	return Load(contaboapi.OpenAPISpec)
+})
+
+// Validate validates the JSON value against a component schema of the specification. The properties unknown to the
+// schema are returned as warnings, as a newer version of the API may support them, while the values not matching
+// the schema, e.g. of another type or outside of an enum, are returned as field errors. When partial is true the
+// required properties of the component are not enforced, e.g. to validate a subset of a request.
+func (s *Spec) Validate(component string, raw []byte, fldPath *field.Path, partial bool) ([]string, field.ErrorList) {
+	schema, ok := s.Components.Schemas[component]
+	if !ok {
+		return nil, field.ErrorList{field.InternalError(fldPath, fmt.Errorf("unknown schema %s", component))}
+	}
+
+	// Numbers are kept as written so that integers are told apart from decimals
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, field.ErrorList{field.Invalid(fldPath, string(raw), fmt.Sprintf("must be valid JSON: %v", err))}
+	}
+
+	v := &validator{spec: s}
+	schemaCopy := *schema
+	if partial {
+		schemaCopy.Required = nil
+	}
+	v.validate(&schemaCopy, value, fldPath)
+	return v.warnings, v.errs
+}
+
+// validator accumulates the warnings and the errors of a validation
+type validator struct {
+	spec     *Spec
+	warnings []string
+	errs     field.ErrorList
+}
+
+func (v *validator) validate(schema *Schema, value any, fldPath *field.Path) {
+	if schema.Ref != "" {
+		resolved, ok := v.spec.Components.Schemas[strings.TrimPrefix(schema.Ref, componentRefPrefix)]
+		if !ok {
+			v.errs = append(v.errs, field.InternalError(fldPath, fmt.Errorf("unknown schema reference %s", schema.Ref)))
+			return
+		}
+		schema = resolved
+	}
+	for _, subSchema := range schema.AllOf {
+		v.validate(subSchema, value, fldPath)
+	}
+	if schema.Type != "" && !v.validateType(schema, value, fldPath) {
+		return
+	}
+
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(allowed any) bool {
+		return fmt.Sprint(allowed) == fmt.Sprint(value)
+	}) {
+		allowed := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			allowed = append(allowed, fmt.Sprint(value))
+		}
+		v.errs = append(v.errs, field.NotSupported(fldPath, value, allowed))
+	}
+
+	switch value := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(value)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			v.errs = append(v.errs, field.Invalid(fldPath, value, fmt.Sprintf("must be at least %d characters", *schema.MinLength)))
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			v.errs = append(v.errs, field.TooLong(fldPath, value, *schema.MaxLength))
+		}
+	case []any:
+		if schema.Items != nil {
+			for i, item := range value {
+				v.validate(schema.Items, item, fldPath.Index(i))
+			}
+		}
+	case map[string]any:
+		v.validateObject(schema, value, fldPath)
+	}
+}
+
+// validateType returns false when the value is not of the type of the schema
+func (v *validator) validateType(schema *Schema, value any, fldPath *field.Path) bool {
+	valid := false
+	switch schema.Type {
+	case "object":
+		_, valid = value.(map[string]any)
+	case "array":
+		_, valid = value.([]any)
+	case "string":
+		_, valid = value.(string)
+	case "boolean":
+		_, valid = value.(bool)
+	case "number":
+		_, valid = value.(json.Number)
+	case "integer":
+		if number, ok := value.(json.Number); ok {
+			_, err := number.Int64()
+			valid = err == nil
+		}
+	default:
+		// Types unknown to the validator are not checked
+		valid = true
+	}
+	if !valid {
+		v.errs = append(v.errs, field.Invalid(fldPath, value, fmt.Sprintf("must be of type %s", schema.Type)))
+	}
+	return valid
+}
+
+func (v *validator) validateObject(schema *Schema, value map[string]any, fldPath *field.Path) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			v.errs = append(v.errs, field.Required(fldPath.Child(name), ""))
+		}
+	}
+
+	// Schemas only made of allOf have their properties checked by the sub-schemas
+	if schema.Type == "" && len(schema.Properties) == 0 {
+		return
+	}
+
+	var additionalProperties *Schema
+	allowAdditionalProperties := false
+	if len(schema.AdditionalProperties) > 0 {
+		if err := json.Unmarshal(schema.AdditionalProperties, &allowAdditionalProperties); err != nil {
+			additionalProperties = &Schema{}
+			if err := json.Unmarshal(schema.AdditionalProperties, additionalProperties); err != nil {
+				v.errs = append(v.errs, field.InternalError(fldPath, fmt.Errorf("invalid additionalProperties: %w", err)))
+				return
+			}
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		propertySchema, ok := schema.Properties[name]
+		switch {
+		case ok:
+			v.validate(propertySchema, value[name], fldPath.Child(name))
+		case additionalProperties != nil:
+			v.validate(additionalProperties, value[name], fldPath.Child(name))
+		case !allowAdditionalProperties:
+			v.warnings = append(v.warnings, fmt.Sprintf("%s: unknown property of the bundled Contabo API specification, it is sent as is", fldPath.Child(name)))
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const testSpec = `{"components":{"schemas":{
+	"Request":{"type":"object","required":["name"],"properties":{
+		"name":{"type":"string","minLength":1,"maxLength":4},
+		"size":{"type":"integer","format":"int64"},
+		"ratio":{"type":"number"},
+		"labels":{"type":"object","additionalProperties":{"type":"string"}},
+		"extra":{"type":"object","additionalProperties":true},
+		"item":{"allOf":[{"$ref":"#/components/schemas/Item"}]},
+		"items":{"type":"array","items":{"$ref":"#/components/schemas/Item"}}
+	}},
+	"Item":{"type":"object","required":["id"],"properties":{"id":{"type":"integer"},"kind":{"type":"string","enum":["a","b"]}}}
+}}}`
+
+func TestValidate(t *testing.T) {
+	spec, err := Load([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		value        string
+		partial      bool
+		wantWarnings []string
+		wantErrs     []string
+	}{
+		{
+			name:  "valid",
+			value: `{"name":"a","size":2,"ratio":0.5,"labels":{"k":"v"},"extra":{"any":[1]},"item":{"id":1,"kind":"a"},"items":[{"id":2}]}`,
+		},
+		{
+			name:     "missing required property",
+			value:    `{"size":2}`,
+			wantErrs: []string{"request.name: Required value"},
+		},
+		{
+			name:    "partial",
+			value:   `{"size":2}`,
+			partial: true,
+		},
+		{
+			name:         "unknown properties",
+			value:        `{"name":"a","unknown":1,"item":{"id":1,"other":true}}`,
+			wantWarnings: []string{"request.item.other: unknown property", "request.unknown: unknown property"},
+		},
+		{
+			name:  "mistyped values",
+			value: `{"name":"abcde","size":1.5,"ratio":"half","labels":{"k":1},"items":[{"kind":"c"}]}`,
+			wantErrs: []string{
+				"request.items[0].id: Required value",
+				"request.items[0].kind: Unsupported value",
+				"request.labels.k: Invalid value: 1: must be of type string",
+				"request.name: Too long",
+				"request.ratio: Invalid value: \"half\": must be of type number",
+				"request.size: Invalid value: 1.5: must be of type integer",
+			},
+		},
+		{
+			name:     "invalid JSON",
+			value:    `{"name":`,
+			wantErrs: []string{"request: Invalid value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, errs := spec.Validate("Request", []byte(tt.value), field.NewPath("request"), tt.partial)
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("warnings = %v, expected %v", warnings, tt.wantWarnings)
+			}
+			for i, warning := range warnings {
+				if !strings.HasPrefix(warning, tt.wantWarnings[i]) {
+					t.Errorf("warning = %q, expected %q", warning, tt.wantWarnings[i])
+				}
+			}
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("errors = %v, expected %v", errs, tt.wantErrs)
+			}
+			for i, err := range errs {
+				if !strings.HasPrefix(err.Error(), tt.wantErrs[i]) {
+					t.Errorf("error = %q, expected %q", err.Error(), tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateUnknownSchema(t *testing.T) {
+	spec, err := Contabo()
+	if err != nil {
+		t.Fatalf("Contabo() error = %v", err)
+	}
+	if _, errs := spec.Validate("Unknown", []byte(`{}`), field.NewPath("request"), false); len(errs) != 1 || errs[0].Type != field.ErrorTypeInternal {
+		t.Errorf("errors = %v, expected an internal error", errs)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package contabo holds the helpers of the generated Contabo API client. Every request of the API requires the
+// x-request-id header, the helpers fill it and the x-trace-id header from the context so that the callers do not.
+// The responses are checked with CheckResponse, which decodes the error payloads into typed errors.
+package contabo
+
+//go:generate go run ./internal/paramsgen -models v1.0.0/models -output params_gen.go -client v1.0.0/client -responses-output responses_gen.go
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/google/uuid"
+
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
+)
+
+const (
+	// RequestIDHeader identifies a request for the Contabo support, required by every request
+	RequestIDHeader = "x-request-id"
+
+	// TraceIDHeader groups requests in the Contabo audits
+	TraceIDHeader = "x-trace-id"
+)
+
+// RequestID returns the request ID of the context, a new UUID v4 when none was set
+func RequestID(ctx context.Context) string {
+	if requestID := contextutil.RequestIDFromContext(ctx); requestID != "" {
+		return requestID
+	}
+	return uuid.New().String()
+}
+
+// TraceID returns the trace ID of the context, nil when none was set
+func TraceID(ctx context.Context) *string {
+	if traceID := contextutil.TraceIDFromContext(ctx); traceID != "" {
+		return &traceID
+	}
+	return nil
+}
+
+// NewParams returns the parameters of a Contabo API request with the request ID and the trace ID of the context,
+// e.g. NewParams[models.CreateInstanceParams](ctx)
+func NewParams[T Params](ctx context.Context) *T {
+	params := new(T)
+	// Every member of Params has the fields, see paramsgen
+	value := reflect.ValueOf(params).Elem()
+	value.FieldByName("XRequestId").SetString(RequestID(ctx))
+	if traceID := TraceID(ctx); traceID != nil {
+		value.FieldByName("XTraceId").Set(reflect.ValueOf(traceID))
+	}
+	return params
+}
+
+// RequestEditor returns the request editor of the Contabo API clients filling the request ID and the trace ID
+// headers missing in the parameters of the requests from the context. The requests without trace ID in their
+// context are traced with the default trace ID, unless empty. The requests are counted on the API call counter of
+// the context, if any, and the request IDs of the requests changing resources on its change recorder.
+func RequestEditor(defaultTraceID string) contaboclient.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		contextutil.CountAPICall(ctx)
+		if req.Header.Get(RequestIDHeader) == "" {
+			req.Header.Set(RequestIDHeader, RequestID(ctx))
+		}
+		if req.Header.Get(TraceIDHeader) == "" {
+			if traceID := TraceID(ctx); traceID != nil {
+				req.Header.Set(TraceIDHeader, *traceID)
+			} else if defaultTraceID != "" {
+				req.Header.Set(TraceIDHeader, defaultTraceID)
+			}
+		}
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			contextutil.RecordChange(ctx, req.Header.Get(RequestIDHeader))
+		}
+		return nil
+	}
+}
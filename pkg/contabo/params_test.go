@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contabo_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
+)
+
+func TestNewParams(t *testing.T) {
+	params := contabo.NewParams[models.CreateInstanceParams](context.Background())
+	if _, err := uuid.Parse(params.XRequestId); err != nil {
+		t.Errorf("NewParams() XRequestId = %q, expected a UUID", params.XRequestId)
+	}
+	if params.XTraceId != nil {
+		t.Errorf("NewParams() XTraceId = %q, expected none", *params.XTraceId)
+	}
+
+	ctx := contextutil.WithTraceID(contextutil.WithRequestID(context.Background(), "request"), "trace")
+	list := contabo.NewParams[models.RetrieveInstancesListParams](ctx)
+	if list.XRequestId != "request" || list.XTraceId == nil || *list.XTraceId != "trace" {
+		t.Errorf("NewParams() = %q, %v, expected the IDs of the context", list.XRequestId, list.XTraceId)
+	}
+}
+
+func TestRequestEditor(t *testing.T) {
+	editor := contabo.RequestEditor("manager")
+	req, _ := http.NewRequest(http.MethodGet, fake.Server, nil)
+	if err := editor(contextutil.WithRequestID(context.Background(), "request"), req); err != nil {
+		t.Fatalf("RequestEditor() error = %v", err)
+	}
+	if req.Header.Get(contabo.RequestIDHeader) != "request" || req.Header.Get(contabo.TraceIDHeader) != "manager" {
+		t.Errorf("RequestEditor() headers = %v", req.Header)
+	}
+
+	// The headers of the parameters are kept
+	req, _ = http.NewRequest(http.MethodGet, fake.Server, nil)
+	req.Header.Set(contabo.RequestIDHeader, "params")
+	req.Header.Set(contabo.TraceIDHeader, "params")
+	if err := editor(contextutil.WithTraceID(context.Background(), "trace"), req); err != nil {
+		t.Fatalf("RequestEditor() error = %v", err)
+	}
+	if req.Header.Get(contabo.RequestIDHeader) != "params" || req.Header.Get(contabo.TraceIDHeader) != "params" {
+		t.Errorf("RequestEditor() headers = %v", req.Header)
+	}
+
+	// The requests are counted on the counter of the context
+	ctx := contextutil.WithAPICallCounter(context.Background())
+	for range 2 {
+		req, _ = http.NewRequest(http.MethodGet, fake.Server, nil)
+		if err := editor(ctx, req); err != nil {
+			t.Fatalf("RequestEditor() error = %v", err)
+		}
+	}
+	if calls := contextutil.APICallsFromContext(ctx); calls != 2 {
+		t.Errorf("RequestEditor() counted %d calls, want 2", calls)
+	}
+
+	// The request IDs of the requests changing resources are recorded, not the ones of the reads
+	ctx = contextutil.WithChangeRecorder(context.Background())
+	req, _ = http.NewRequest(http.MethodPost, fake.Server, nil)
+	req.Header.Set(contabo.RequestIDHeader, "create")
+	if err := editor(ctx, req); err != nil {
+		t.Fatalf("RequestEditor() error = %v", err)
+	}
+	req, _ = http.NewRequest(http.MethodGet, fake.Server, nil)
+	req.Header.Set(contabo.RequestIDHeader, "get")
+	if err := editor(ctx, req); err != nil {
+		t.Fatalf("RequestEditor() error = %v", err)
+	}
+	if requestID := contextutil.LastChangeFromContext(ctx); requestID != "create" {
+		t.Errorf("RequestEditor() recorded the change %q, want create", requestID)
+	}
+}
+
+func TestRequestIDRequired(t *testing.T) {
+	backend := fake.NewBackend()
+	instanceId := backend.AddInstance(models.InstanceResponse{DisplayName: "machine-0"})
+	client, err := contaboclient.NewClientWithResponses(fake.Server, contaboclient.WithHTTPClient(backend))
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() error = %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := client.RetrieveInstanceWithResponse(ctx, instanceId, nil)
+	if err != nil || resp.StatusCode() != http.StatusBadRequest {
+		t.Fatalf("RetrieveInstance() without request ID = %v, %v, expected 400", resp.StatusCode(), err)
+	}
+	resp, err = client.RetrieveInstanceWithResponse(ctx, instanceId, contabo.NewParams[models.RetrieveInstanceParams](ctx))
+	if err != nil || resp.JSON200 == nil {
+		t.Fatalf("RetrieveInstance() with NewParams = %v, %v", resp.StatusCode(), err)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit shares the Contabo API budget of the account between the clusters managed by the controller.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
+)
+
+// waiter is a request waiting for a token
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// FairLimiter limits the Contabo API requests of the account with a token bucket. The requests waiting for a token
+// are queued per cluster and the tokens are handed out round robin across the queues, so that a misbehaving cluster,
+// e.g. crash-looping scale ups, only delays its own requests instead of starving the other clusters.
+// A nil FairLimiter does not limit the requests.
+type FairLimiter struct {
+	limiter *rate.Limiter
+
+	mu     sync.Mutex
+	queues map[string][]*waiter
+	// order lists the clusters with waiting requests, the next token goes to the first one
+	order []string
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+}
+
+// NewFairLimiter returns a limiter allowing qps requests per second with bursts of burst requests, nil when qps is
+// not positive
+func NewFairLimiter(qps float64, burst int) *FairLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &FairLimiter{
+		limiter: rate.NewLimiter(rate.Limit(qps), max(burst, 1)),
+		queues:  map[string][]*waiter{},
+		wake:    make(chan struct{}, 1),
+		cancel:  cancel,
+	}
+	go l.run(ctx)
+	return l
+}
+
+// Close stops handing out tokens, the waiting requests wait for their context to be done
+func (l *FairLimiter) Close() {
+	if l != nil {
+		l.cancel()
+	}
+}
+
+// Wait blocks until a request of the cluster is allowed or the context is done
+func (l *FairLimiter) Wait(ctx context.Context, cluster string) error {
+	if l == nil {
+		return nil
+	}
+
+	w := &waiter{ready: make(chan struct{})}
+	l.mu.Lock()
+	if _, ok := l.queues[cluster]; !ok {
+		l.order = append(l.order, cluster)
+	}
+	l.queues[cluster] = append(l.queues[cluster], w)
+	l.mu.Unlock()
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if w.granted {
+			return nil
+		}
+		l.remove(cluster, w)
+		return ctx.Err()
+	}
+}
+
+// Waiting returns the number of requests waiting for a token per cluster
+func (l *FairLimiter) Waiting() map[string]int {
+	waiting := map[string]int{}
+	if l == nil {
+		return waiting
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for cluster, queue := range l.queues {
+		waiting[cluster] = len(queue)
+	}
+	return waiting
+}
+
+// remove drops a waiter whose context is done, the lock must be held
+func (l *FairLimiter) remove(cluster string, w *waiter) {
+	queue := slices.DeleteFunc(l.queues[cluster], func(queued *waiter) bool {
+		return queued == w
+	})
+	if len(queue) > 0 {
+		l.queues[cluster] = queue
+		return
+	}
+	delete(l.queues, cluster)
+	l.order = slices.DeleteFunc(l.order, func(queued string) bool {
+		return queued == cluster
+	})
+}
+
+// run hands out the tokens to the waiting requests, one cluster after the other
+func (l *FairLimiter) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.wake:
+		}
+
+		for {
+			l.mu.Lock()
+			idle := len(l.order) == 0
+			l.mu.Unlock()
+			if idle {
+				break
+			}
+
+			reservation := l.limiter.Reserve()
+			if delay := reservation.Delay(); delay > 0 {
+				if err := sleep(ctx, delay); err != nil {
+					reservation.Cancel()
+					return
+				}
+			}
+
+			l.mu.Lock()
+			if len(l.order) == 0 {
+				// The waiting requests were cancelled meanwhile, give the token back
+				reservation.Cancel()
+				l.mu.Unlock()
+				break
+			}
+			cluster := l.order[0]
+			queue := l.queues[cluster]
+			w := queue[0]
+			l.order = l.order[1:]
+			if len(queue) > 1 {
+				l.queues[cluster] = queue[1:]
+				l.order = append(l.order, cluster)
+			} else {
+				delete(l.queues, cluster)
+			}
+			w.granted = true
+			close(w.ready)
+			l.mu.Unlock()
+		}
+	}
+}
+
+// sleep waits for the delay or the context to be done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Transport is an http.RoundTripper waiting for the FairLimiter before sending each request, in the queue of the
+// cluster of the request context
+type Transport struct {
+	Base    http.RoundTripper
+	Limiter *FairLimiter
+}
+
+// NewTransport creates a new rate limited transport, using http.DefaultTransport if base is nil
+func NewTransport(base http.RoundTripper, limiter *FairLimiter) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		Base:    base,
+		Limiter: limiter,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Limiter.Wait(req.Context(), contextutil.ClusterFromContext(req.Context())); err != nil {
+		return nil, fmt.Errorf("contabo API request not sent: %w", err)
+	}
+	return t.Base.RoundTrip(req)
+}
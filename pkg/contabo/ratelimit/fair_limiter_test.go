@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contextutil"
+)
+
+func TestFairLimiterServesClustersRoundRobin(t *testing.T) {
+	limiter := NewFairLimiter(50, 1)
+	defer limiter.Close()
+
+	var mu sync.Mutex
+	served := []string{}
+	var wg sync.WaitGroup
+	wait := func(cluster string) {
+		defer wg.Done()
+		if err := limiter.Wait(context.Background(), cluster); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		mu.Lock()
+		served = append(served, cluster)
+		mu.Unlock()
+	}
+
+	// A crash-looping cluster queues many requests before a quiet cluster sends one
+	for range 20 {
+		wg.Add(1)
+		go wait("default/noisy")
+	}
+	time.Sleep(50 * time.Millisecond)
+	wg.Add(1)
+	go wait("default/quiet")
+	wg.Wait()
+
+	for i, cluster := range served {
+		if cluster == "default/quiet" {
+			if i > 6 {
+				t.Fatalf("quiet cluster served after %d requests of the noisy cluster", i)
+			}
+			return
+		}
+	}
+	t.Fatal("quiet cluster was not served")
+}
+
+func TestFairLimiterCancelledWait(t *testing.T) {
+	limiter := NewFairLimiter(1, 1)
+	defer limiter.Close()
+
+	if err := limiter.Wait(context.Background(), "default/a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "default/a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if waiting := limiter.Waiting(); len(waiting) != 0 {
+		t.Fatalf("expected no waiting request, got %v", waiting)
+	}
+}
+
+func TestNilFairLimiterDoesNotLimit(t *testing.T) {
+	limiter := NewFairLimiter(0, 0)
+	if limiter != nil {
+		t.Fatal("expected no limiter without qps")
+	}
+	for range 100 {
+		if err := limiter.Wait(context.Background(), ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestTransportQueuesRequestsOfTheContextCluster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := NewFairLimiter(1, 1)
+	defer limiter.Close()
+	client := &http.Client{Transport: NewTransport(nil, limiter)}
+
+	ctx := contextutil.WithCluster(context.Background(), "default/a")
+	if contextutil.ClusterFromContext(ctx) != "default/a" {
+		t.Fatalf("unexpected cluster %q", contextutil.ClusterFromContext(ctx))
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	// The budget is spent, the next request is not sent before its context is done
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("expected the request to wait for the limiter")
+	}
+}
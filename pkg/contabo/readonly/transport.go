@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readonly freezes the Contabo resources of the account during incidents: the requests changing them are
+// refused before they are sent, the requests reading them go through.
+package readonly
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is returned for the Contabo API requests refused in read-only mode
+var ErrReadOnly = errors.New("contabo API is read-only")
+
+// Transport refuses the requests changing Contabo resources, only GET, HEAD and OPTIONS requests are sent
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport creates a new read-only transport, using http.DefaultTransport if base is nil
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		Base: base,
+	}
+}
+
+// IsSafeMethod returns true for the HTTP methods which do not change the resources
+func IsSafeMethod(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !IsSafeMethod(req.Method) {
+		// The body is owned by the transport even when the request is not sent
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: %s %s not sent", ErrReadOnly, req.Method, req.URL.Path)
+	}
+	return t.Base.RoundTrip(req)
+}
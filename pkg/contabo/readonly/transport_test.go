@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readonly
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+func TestTransport(t *testing.T) {
+	sent := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sent = append(sent, req.Method)
+	}))
+	defer server.Close()
+	httpClient := &http.Client{Transport: NewTransport(nil)}
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		req, _ := http.NewRequest(method, server.URL, nil)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s error = %v", method, err)
+		}
+		_ = resp.Body.Close()
+	}
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req, _ := http.NewRequest(method, server.URL, nil)
+		if _, err := httpClient.Do(req); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s error = %v, expected ErrReadOnly", method, err)
+		}
+	}
+	if len(sent) != 3 {
+		t.Errorf("sent requests = %v, expected the safe methods only", sent)
+	}
+}
+
+func TestTransportContaboClient(t *testing.T) {
+	backend := fake.NewBackend()
+	instanceId := backend.AddInstance(models.InstanceResponse{DisplayName: "machine-0"})
+	client, err := contaboclient.NewClientWithResponses(fake.Server,
+		contaboclient.WithHTTPClient(&http.Client{Transport: NewTransport(roundTripperFunc(backend.Do))}),
+		contaboclient.WithRequestEditorFn(contabo.RequestEditor("")))
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() error = %v", err)
+	}
+
+	resp, err := client.RetrieveInstanceWithResponse(context.Background(), instanceId, nil)
+	if err != nil || resp.JSON200 == nil {
+		t.Fatalf("RetrieveInstance() error = %v", err)
+	}
+	displayName := "renamed"
+	if _, err := client.PatchInstanceWithResponse(context.Background(), instanceId, nil,
+		models.PatchInstanceRequest{DisplayName: &displayName}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("PatchInstance() error = %v, expected ErrReadOnly", err)
+	}
+	if instances := backend.Instances(); instances[0].DisplayName != "machine-0" {
+		t.Errorf("instance display name = %s, expected unchanged", instances[0].DisplayName)
+	}
+}
+
+// roundTripperFunc sends the requests with a function, e.g. to the fake backend
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
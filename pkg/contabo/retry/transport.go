@@ -0,0 +1,230 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retry retries the Contabo API requests refused by the rate limit of the API or failed by a transient error,
+// so that a single 429 or 5xx response does not fail the reconciliation sending it.
+package retry
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultMaxRetries is the default number of retries of a request
+	DefaultMaxRetries = 3
+
+	// DefaultBaseDelay is the default delay before the first retry, doubled for each following retry
+	DefaultBaseDelay = 500 * time.Millisecond
+
+	// MaxDelay is the maximum backoff delay between two attempts
+	MaxDelay = 30 * time.Second
+
+	// MaxRetryAfter is the maximum Retry-After delay waited for, the response of a request asked to wait longer is
+	// returned so that the reconciliation requeues instead of holding its worker
+	MaxRetryAfter = time.Minute
+)
+
+// Reasons of the retries
+const (
+	ReasonRateLimited     = "rate_limited"
+	ReasonServerError     = "server_error"
+	ReasonConnectionError = "connection_error"
+)
+
+// Metrics counts the retries of the Contabo API requests. A nil Metrics records nothing.
+type Metrics struct {
+	retries   *prometheus.CounterVec
+	exhausted *prometheus.CounterVec
+}
+
+// NewMetrics returns the retry metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "capc_contabo_api_retries_total",
+			Help: "Number of Contabo API requests retried per reason",
+		}, []string{"reason"}),
+		exhausted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "capc_contabo_api_retries_exhausted_total",
+			Help: "Number of Contabo API requests still failing after their last retry per reason",
+		}, []string{"reason"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.retries.Describe(ch)
+	m.exhausted.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.retries.Collect(ch)
+	m.exhausted.Collect(ch)
+}
+
+func (m *Metrics) retried(reason string) {
+	if m != nil {
+		m.retries.WithLabelValues(reason).Inc()
+	}
+}
+
+func (m *Metrics) gaveUp(reason string) {
+	if m != nil {
+		m.exhausted.WithLabelValues(reason).Inc()
+	}
+}
+
+// Transport is an http.RoundTripper retrying the requests with an exponential backoff with jitter, or after the
+// Retry-After delay of the response when set. The requests refused with 429 Too Many Requests are always retried as
+// they were not processed, the requests failed with a 5xx status code or a connection error only when their method
+// is idempotent, so that an instance is never ordered twice.
+type Transport struct {
+	Base http.RoundTripper
+	// MaxRetries is the number of retries of a request, 0 disables the retries
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled for each following retry up to MaxDelay
+	BaseDelay time.Duration
+	Metrics   *Metrics
+}
+
+// NewTransport creates a new retrying transport, using http.DefaultTransport if base is nil
+func NewTransport(base http.RoundTripper, maxRetries int, baseDelay time.Duration, metrics *Metrics) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		Base:       base,
+		MaxRetries: maxRetries,
+		BaseDelay:  baseDelay,
+		Metrics:    metrics,
+	}
+}
+
+// IsIdempotentMethod returns true for the HTTP methods which can be sent again without changing the result
+func IsIdempotentMethod(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempt := req
+	for retries := 0; ; retries++ {
+		resp, err := t.Base.RoundTrip(attempt)
+		reason := retryReason(req, resp, err)
+		if reason == "" {
+			return resp, err
+		}
+		delay, ok := t.delay(retries, resp)
+		// The body of the request must be sent again, requests without GetBody cannot be retried
+		if retries >= t.MaxRetries || !ok || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			t.Metrics.gaveUp(reason)
+			return resp, err
+		}
+		if resp != nil {
+			// Drain the body so that the connection is reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+		}
+		t.Metrics.retried(reason)
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		attempt = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if attempt.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryReason returns why the request must be retried, empty when it must not
+func retryReason(req *http.Request, resp *http.Response, err error) string {
+	if err != nil {
+		// A cancelled request is not retried
+		if req.Context().Err() != nil || !IsIdempotentMethod(req.Method) {
+			return ""
+		}
+		return ReasonConnectionError
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return ReasonRateLimited
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if IsIdempotentMethod(req.Method) {
+			return ReasonServerError
+		}
+	}
+	return ""
+}
+
+// delay returns the delay before the next attempt: the Retry-After delay of the response when set, false when it
+// exceeds MaxRetryAfter, and an exponential backoff with jitter otherwise
+func (t *Transport) delay(retries int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return retryAfter, retryAfter <= MaxRetryAfter
+		}
+	}
+	backoff := MaxDelay
+	if retries < 32 {
+		backoff = min(t.BaseDelay<<retries, MaxDelay)
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+	// Equal jitter spreads the retries of the requests refused together while keeping half of the backoff
+	return backoff/2 + rand.N(backoff/2+1), true
+}
+
+// parseRetryAfter parses a Retry-After header holding either seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for the delay or the context to be done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
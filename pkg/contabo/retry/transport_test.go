@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/fake"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+// failingServer answers the first failures requests with the status code and the following ones with 200 OK,
+// recording the bodies of the requests
+func failingServer(t *testing.T, failures int, statusCode int, retryAfter string) (*httptest.Server, *[]string) {
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statusCode)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		failures   int
+		statusCode int
+		retryAfter string
+		expected   int
+		attempts   int
+	}{
+		{name: "rate limited POST", method: http.MethodPost, failures: 2, statusCode: http.StatusTooManyRequests, retryAfter: "0", expected: http.StatusOK, attempts: 3},
+		{name: "server error GET", method: http.MethodGet, failures: 1, statusCode: http.StatusBadGateway, expected: http.StatusOK, attempts: 2},
+		{name: "server error PUT", method: http.MethodPut, failures: 1, statusCode: http.StatusServiceUnavailable, expected: http.StatusOK, attempts: 2},
+		{name: "server error POST", method: http.MethodPost, failures: 1, statusCode: http.StatusInternalServerError, expected: http.StatusInternalServerError, attempts: 1},
+		{name: "client error", method: http.MethodGet, failures: 1, statusCode: http.StatusNotFound, expected: http.StatusNotFound, attempts: 1},
+		{name: "retries exhausted", method: http.MethodGet, failures: 5, statusCode: http.StatusTooManyRequests, expected: http.StatusTooManyRequests, attempts: 4},
+		{name: "Retry-After too long", method: http.MethodGet, failures: 1, statusCode: http.StatusTooManyRequests, retryAfter: "3600", expected: http.StatusTooManyRequests, attempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, bodies := failingServer(t, tt.failures, tt.statusCode, tt.retryAfter)
+			httpClient := &http.Client{Transport: NewTransport(nil, 3, time.Millisecond, nil)}
+
+			req, _ := http.NewRequest(tt.method, server.URL, strings.NewReader("payload"))
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.expected {
+				t.Errorf("status code = %d, expected %d", resp.StatusCode, tt.expected)
+			}
+			if len(*bodies) != tt.attempts {
+				t.Errorf("attempts = %d, expected %d", len(*bodies), tt.attempts)
+			}
+			for _, body := range *bodies {
+				if body != "payload" {
+					t.Errorf("body = %q, expected the body of the request on every attempt", body)
+				}
+			}
+		})
+	}
+}
+
+func TestTransportMetrics(t *testing.T) {
+	server, _ := failingServer(t, 5, http.StatusServiceUnavailable, "")
+	metrics := NewMetrics()
+	httpClient := &http.Client{Transport: NewTransport(nil, 2, time.Millisecond, metrics)}
+
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if retries := testutil.ToFloat64(metrics.retries.WithLabelValues(ReasonServerError)); retries != 2 {
+		t.Errorf("retries = %v, expected 2", retries)
+	}
+	if exhausted := testutil.ToFloat64(metrics.exhausted.WithLabelValues(ReasonServerError)); exhausted != 1 {
+		t.Errorf("exhausted = %v, expected 1", exhausted)
+	}
+}
+
+func TestTransportContextCancelled(t *testing.T) {
+	server, bodies := failingServer(t, 5, http.StatusTooManyRequests, "30")
+	httpClient := &http.Client{Transport: NewTransport(nil, 3, time.Millisecond, nil)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := httpClient.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() error = %v, expected the deadline of the context", err)
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("attempts = %d, expected no retry after the context is done", len(*bodies))
+	}
+}
+
+func TestTransportContaboClient(t *testing.T) {
+	backend := fake.NewBackend()
+	client, err := contaboclient.NewClientWithResponses(fake.Server,
+		contaboclient.WithHTTPClient(&http.Client{Transport: NewTransport(roundTripperFunc(backend.Do), 3, time.Millisecond, nil)}),
+		contaboclient.WithRequestEditorFn(contabo.RequestEditor("")),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() error = %v", err)
+	}
+
+	backend.SetFaults(fake.Faults{RateLimitedRequests: 2})
+	resp, err := client.RetrieveInstancesListWithResponse(context.Background(), &models.RetrieveInstancesListParams{})
+	if err := contabo.CheckResponse(resp, err); err != nil {
+		t.Errorf("CheckResponse() error = %v, expected the rate limited requests to be retried", err)
+	}
+}
+
+// roundTripperFunc sends the requests with a function, e.g. to the fake backend
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
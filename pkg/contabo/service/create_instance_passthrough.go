@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/openapi"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// createInstanceSchema is the component schema of the CreateInstance request
+	createInstanceSchema = "CreateInstanceRequest"
+
+	// addOnsProperty is the property of the add-ons, merged one by one with the add-ons of the provider
+	addOnsProperty = "addOns"
+)
+
+// ProviderOwnedCreateInstanceProperties are the CreateInstance properties always set by the provider, the
+// passthrough properties never override them
+var ProviderOwnedCreateInstanceProperties = []string{
+	"productId", "period", "imageId", "region", "sshKeys", "displayName", "defaultUser", "userData",
+}
+
+// MergeCreateInstancePassthrough returns the body of the CreateInstance request completed with the raw passthrough
+// properties, e.g. properties of a newer API the generated models do not know. The properties owned by the provider
+// and the add-ons it sets are kept, the other passthrough add-ons are added one by one.
+func MergeCreateInstancePassthrough(request models.CreateInstanceRequest, passthrough []byte) ([]byte, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the create instance request: %w", err)
+	}
+	if len(passthrough) == 0 {
+		return body, nil
+	}
+
+	merged := map[string]any{}
+	if err := decodeJSONObject(body, &merged); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the create instance request: %w", err)
+	}
+	properties := map[string]any{}
+	if err := decodeJSONObject(passthrough, &properties); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the passthrough properties: %w", err)
+	}
+	for name, value := range properties {
+		if slices.Contains(ProviderOwnedCreateInstanceProperties, name) {
+			continue
+		}
+		addOns, isAddOns := value.(map[string]any)
+		mergedAddOns, hasAddOns := merged[name].(map[string]any)
+		switch {
+		case name == addOnsProperty && isAddOns && hasAddOns:
+			for addOn, addOnValue := range addOns {
+				if _, ok := mergedAddOns[addOn]; !ok {
+					mergedAddOns[addOn] = addOnValue
+				}
+			}
+		case merged[name] == nil:
+			merged[name] = value
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// decodeJSONObject decodes a JSON object keeping its numbers as written, so that 64-bit IDs are not rounded
+func decodeJSONObject(data []byte, object *map[string]any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(object)
+}
+
+// ValidateCreateInstancePassthrough validates the raw passthrough properties against the CreateInstance request
+// schema of the bundled Contabo OpenAPI specification. Unknown properties and the properties owned by the provider
+// are returned as warnings, values not matching the schema as field errors.
+func ValidateCreateInstancePassthrough(passthrough []byte, fldPath *field.Path) ([]string, field.ErrorList) {
+	if len(passthrough) == 0 {
+		return nil, nil
+	}
+	spec, err := openapi.Contabo()
+	if err != nil {
+		return nil, field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	warnings, errs := spec.Validate(createInstanceSchema, passthrough, fldPath, true)
+
+	properties := map[string]json.RawMessage{}
+	if err := json.Unmarshal(passthrough, &properties); err != nil {
+		// The schema validation already reported the value is not an object
+		return warnings, errs
+	}
+	for _, name := range ProviderOwnedCreateInstanceProperties {
+		if _, ok := properties[name]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s: set by the provider, the passthrough value is ignored", fldPath.Child(name)))
+		}
+	}
+	return warnings, errs
+}
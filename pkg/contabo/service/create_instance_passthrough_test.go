@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestMergeCreateInstancePassthrough(t *testing.T) {
+	body, err := MergeCreateInstancePassthrough(validCreateInstanceRequest(), []byte(
+		`{"license":"PleskHost","region":"UK","applicationId":"a1","addOns":{"backup":{},"privateNetworking":{"ignored":true}},"futureProperty":{"id":9007199254740993}}`))
+	if err != nil {
+		t.Fatalf("MergeCreateInstancePassthrough() error = %v", err)
+	}
+	merged := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &merged); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	expected := map[string]string{
+		"license":        `"PleskHost"`,
+		"region":         `"EU"`,
+		"applicationId":  `"a1"`,
+		"addOns":         `{"backup":{},"privateNetworking":{}}`,
+		"futureProperty": `{"id":9007199254740993}`,
+		"productId":      `"V76"`,
+	}
+	for name, value := range expected {
+		if string(merged[name]) != value {
+			t.Errorf("%s = %s, expected %s", name, merged[name], value)
+		}
+	}
+}
+
+func TestMergeCreateInstancePassthroughEmpty(t *testing.T) {
+	body, err := MergeCreateInstancePassthrough(validCreateInstanceRequest(), nil)
+	if err != nil {
+		t.Fatalf("MergeCreateInstancePassthrough() error = %v", err)
+	}
+	expected, _ := json.Marshal(validCreateInstanceRequest())
+	if string(body) != string(expected) {
+		t.Errorf("body = %s, expected the request unchanged %s", body, expected)
+	}
+}
+
+func TestValidateCreateInstancePassthrough(t *testing.T) {
+	tests := []struct {
+		name         string
+		passthrough  string
+		wantWarnings []string
+		wantErrs     []string
+	}{
+		{
+			name:        "known properties",
+			passthrough: `{"license":"PleskHost","rootPassword":12,"addOns":{"backup":{},"addonsIds":[{"id":1019,"quantity":4}]}}`,
+		},
+		{
+			name:         "unknown properties",
+			passthrough:  `{"futureProperty":true,"addOns":{"futureAddOn":{}}}`,
+			wantWarnings: []string{"providerSpecific.addOns.futureAddOn: unknown property", "providerSpecific.futureProperty: unknown property"},
+		},
+		{
+			name:         "provider owned properties",
+			passthrough:  `{"region":"UK"}`,
+			wantWarnings: []string{"providerSpecific.region: set by the provider"},
+		},
+		{
+			name:        "mistyped properties",
+			passthrough: `{"rootPassword":"secret","period":1.5,"addOns":{"extraStorage":{"ssd":[1]}}}`,
+			wantErrs: []string{
+				"providerSpecific.addOns.extraStorage.ssd[0]: Invalid value: 1: must be of type string",
+				"providerSpecific.period: Invalid value",
+				"providerSpecific.rootPassword: Invalid value: \"secret\": must be of type integer",
+			},
+			wantWarnings: []string{"providerSpecific.period: set by the provider"},
+		},
+		{
+			name:        "enum",
+			passthrough: `{"license":"cPanel1"}`,
+			wantErrs:    []string{"providerSpecific.license: Unsupported value: \"cPanel1\""},
+		},
+		{
+			name:        "required add-on properties",
+			passthrough: `{"addOns":{"addonsIds":[{"id":1019}]}}`,
+			wantErrs:    []string{"providerSpecific.addOns.addonsIds[0].quantity: Required value"},
+		},
+		{
+			name:        "not an object",
+			passthrough: `["license"]`,
+			wantErrs:    []string{"providerSpecific: Invalid value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, errs := ValidateCreateInstancePassthrough([]byte(tt.passthrough), field.NewPath("providerSpecific"))
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("warnings = %v, expected %v", warnings, tt.wantWarnings)
+			}
+			for i, warning := range warnings {
+				if !strings.HasPrefix(warning, tt.wantWarnings[i]) {
+					t.Errorf("warning = %q, expected %q", warning, tt.wantWarnings[i])
+				}
+			}
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("errors = %v, expected %v", errs, tt.wantErrs)
+			}
+			for i, err := range errs {
+				if !strings.HasPrefix(err.Error(), tt.wantErrs[i]) {
+					t.Errorf("error = %q, expected %q", err.Error(), tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo"
+	contaboclient "github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/client"
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+const (
+	// DefaultMaxUserDataSize is the maximum size in bytes of the cloud-init user data sent to the Contabo API
+	DefaultMaxUserDataSize = 64 * 1024
+
+	// MaxDisplayNameLength is the maximum length of an instance display name
+	MaxDisplayNameLength = 255
+
+	// windowsOsType is the image OS type of Windows images
+	windowsOsType = "Windows"
+)
+
+// supportedPeriods are the contract periods in months accepted by the Contabo API
+var supportedPeriods = []string{"1", "3", "6", "12"}
+
+// CreateInstanceValidator checks CreateInstance payloads before they are submitted to the Contabo API,
+// turning API round-trips into instant and clear errors.
+type CreateInstanceValidator struct {
+	// Client is used to check the referenced secrets and image, remote checks are skipped when nil
+	Client *contaboclient.ClientWithResponses
+	// MaxUserDataSize is the maximum size in bytes of the user data
+	MaxUserDataSize int
+}
+
+// NewCreateInstanceValidator creates a new CreateInstanceValidator
+func NewCreateInstanceValidator(client *contaboclient.ClientWithResponses) *CreateInstanceValidator {
+	return &CreateInstanceValidator{
+		Client:          client,
+		MaxUserDataSize: DefaultMaxUserDataSize,
+	}
+}
+
+// Validate checks the CreateInstance request locally, then the referenced SSH keys, root password and image.
+// Validation failures are returned as an aggregate of field errors, API failures as plain errors.
+func (v *CreateInstanceValidator) Validate(ctx context.Context, request models.CreateInstanceRequest) error {
+	allErrs := v.ValidateLocal(request)
+
+	if v.Client != nil {
+		errs, err := v.validateRemote(ctx, request)
+		if err != nil {
+			return err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+
+	return allErrs.ToAggregate()
+}
+
+// ValidateLocal checks the required fields, enums, user data size and add-ons of the CreateInstance request
+func (v *CreateInstanceValidator) ValidateLocal(request models.CreateInstanceRequest) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !slices.Contains(supportedPeriods, fmt.Sprint(request.Period)) {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("period"), request.Period, supportedPeriods))
+	}
+
+	if request.ProductId == nil || strings.TrimSpace(*request.ProductId) == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("productId"), "product is required"))
+	}
+
+	if request.Region == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("region"), "region is required"))
+	} else if !slices.Contains(supportedRegions(), string(*request.Region)) {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("region"), *request.Region, supportedRegions()))
+	}
+
+	if request.ImageId != nil {
+		if _, err := uuid.Parse(*request.ImageId); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("imageId"), *request.ImageId, "must be a UUID"))
+		}
+	}
+
+	if request.DisplayName != nil && len(*request.DisplayName) > MaxDisplayNameLength {
+		allErrs = append(allErrs, field.TooLong(field.NewPath("displayName"), *request.DisplayName, MaxDisplayNameLength))
+	}
+
+	if request.DefaultUser != nil {
+		defaultUsers := []string{
+			string(models.CreateInstanceRequestDefaultUserAdmin),
+			string(models.CreateInstanceRequestDefaultUserAdministrator),
+			string(models.CreateInstanceRequestDefaultUserRoot),
+		}
+		if !slices.Contains(defaultUsers, string(*request.DefaultUser)) {
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("defaultUser"), *request.DefaultUser, defaultUsers))
+		}
+	}
+
+	if request.SshKeys != nil {
+		seen := map[int64]bool{}
+		for i, secretId := range *request.SshKeys {
+			path := field.NewPath("sshKeys").Index(i)
+			switch {
+			case secretId <= 0:
+				allErrs = append(allErrs, field.Invalid(path, secretId, "must be a positive secret ID"))
+			case seen[secretId]:
+				allErrs = append(allErrs, field.Duplicate(path, secretId))
+			}
+			seen[secretId] = true
+		}
+	}
+
+	if request.RootPassword != nil && *request.RootPassword <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("rootPassword"), *request.RootPassword, "must be a positive secret ID"))
+	}
+
+	if request.UserData != nil {
+		maxUserDataSize := v.MaxUserDataSize
+		if maxUserDataSize <= 0 {
+			maxUserDataSize = DefaultMaxUserDataSize
+		}
+		if len(*request.UserData) > maxUserDataSize {
+			allErrs = append(allErrs, field.TooLong(field.NewPath("userData"), "", maxUserDataSize))
+		}
+	}
+
+	allErrs = append(allErrs, validateAddOns(request.AddOns, field.NewPath("addOns"))...)
+
+	return allErrs
+}
+
+// validateAddOns checks the add-ons are consistent with each other
+func validateAddOns(addOns *models.CreateInstanceAddons, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if addOns == nil {
+		return allErrs
+	}
+
+	if addOns.AddonsIds != nil {
+		seen := map[int64]bool{}
+		for i, addOn := range *addOns.AddonsIds {
+			addOnPath := path.Child("addonsIds").Index(i)
+			if addOn.Id <= 0 {
+				allErrs = append(allErrs, field.Invalid(addOnPath.Child("id"), addOn.Id, "must be a positive add-on ID"))
+			} else if seen[addOn.Id] {
+				allErrs = append(allErrs, field.Duplicate(addOnPath.Child("id"), addOn.Id))
+			}
+			if addOn.Quantity <= 0 {
+				allErrs = append(allErrs, field.Invalid(addOnPath.Child("quantity"), addOn.Quantity, "must be positive"))
+			}
+			seen[addOn.Id] = true
+		}
+	}
+
+	// Instances come with either NVMe or SSD disks, extra storage can not mix both
+	if addOns.ExtraStorage != nil && addOns.ExtraStorage.Nvme != nil && addOns.ExtraStorage.Ssd != nil &&
+		len(*addOns.ExtraStorage.Nvme) > 0 && len(*addOns.ExtraStorage.Ssd) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("extraStorage"), "nvme and ssd extra storage can not be combined"))
+	}
+
+	return allErrs
+}
+
+// validateRemote checks the referenced secrets exist with the expected type and the image is compatible with the request
+func (v *CreateInstanceValidator) validateRemote(ctx context.Context, request models.CreateInstanceRequest) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+
+	if request.SshKeys != nil {
+		for i, secretId := range *request.SshKeys {
+			if secretId <= 0 {
+				continue
+			}
+			errs, err := v.validateSecret(ctx, field.NewPath("sshKeys").Index(i), secretId, models.SecretResponseTypeSsh)
+			if err != nil {
+				return nil, err
+			}
+			allErrs = append(allErrs, errs...)
+		}
+	}
+
+	if request.RootPassword != nil && *request.RootPassword > 0 {
+		errs, err := v.validateSecret(ctx, field.NewPath("rootPassword"), *request.RootPassword, models.SecretResponseTypePassword)
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+
+	if request.ImageId != nil {
+		if _, err := uuid.Parse(*request.ImageId); err == nil {
+			errs, err := v.validateImage(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			allErrs = append(allErrs, errs...)
+		}
+	}
+
+	return allErrs, nil
+}
+
+// validateSecret checks the secret exists in the Contabo API with the expected type
+func (v *CreateInstanceValidator) validateSecret(ctx context.Context, path *field.Path, secretId int64, secretType models.SecretResponseType) (field.ErrorList, error) {
+	resp, err := v.Client.RetrieveSecretWithResponse(ctx, secretId, nil)
+	if err := contabo.CheckResponse(resp, err); errors.Is(err, contabo.ErrNotFound) {
+		return field.ErrorList{field.NotFound(path, secretId)}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to retrieve secret %d: %w", secretId, err)
+	}
+	if len(resp.JSON200.Data) == 0 {
+		return nil, fmt.Errorf("failed to retrieve secret %d: not returned by the Contabo API", secretId)
+	}
+	if resp.JSON200.Data[0].Type != secretType {
+		return field.ErrorList{field.Invalid(path, secretId, fmt.Sprintf("secret must be of type %s, got %s", secretType, resp.JSON200.Data[0].Type))}, nil
+	}
+	return nil, nil
+}
+
+// validateImage checks the image exists and is compatible with the default user, SSH keys, user data and license
+func (v *CreateInstanceValidator) validateImage(ctx context.Context, request models.CreateInstanceRequest) (field.ErrorList, error) {
+	path := field.NewPath("imageId")
+
+	resp, err := v.Client.RetrieveImageWithResponse(ctx, *request.ImageId, nil)
+	if err := contabo.CheckResponse(resp, err); errors.Is(err, contabo.ErrNotFound) {
+		return field.ErrorList{field.NotFound(path, *request.ImageId)}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to retrieve image %s: %w", *request.ImageId, err)
+	}
+	if len(resp.JSON200.Data) == 0 {
+		return nil, fmt.Errorf("failed to retrieve image %s: not returned by the Contabo API", *request.ImageId)
+	}
+
+	return validateImageCompatibility(request, resp.JSON200.Data[0].OsType), nil
+}
+
+// validateImageCompatibility checks the request options are supported by the image operating system
+func validateImageCompatibility(request models.CreateInstanceRequest, osType string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if osType == windowsOsType {
+		if request.DefaultUser != nil && *request.DefaultUser != models.CreateInstanceRequestDefaultUserAdministrator {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("defaultUser"), *request.DefaultUser, "Windows images only support the administrator user"))
+		}
+		if request.SshKeys != nil && len(*request.SshKeys) > 0 {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("sshKeys"), "SSH keys are not supported by Windows images"))
+		}
+		if request.UserData != nil && *request.UserData != "" {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("userData"), "cloud-init user data is not supported by Windows images"))
+		}
+		if request.License != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("license"), "cPanel and Plesk licenses require a Linux image"))
+		}
+		return allErrs
+	}
+
+	if request.DefaultUser != nil && *request.DefaultUser == models.CreateInstanceRequestDefaultUserAdministrator {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("defaultUser"), *request.DefaultUser, "the administrator user is only supported by Windows images"))
+	}
+
+	return allErrs
+}
+
+// supportedRegions returns the regions accepted by CreateInstance
+func supportedRegions() []string {
+	return []string{
+		string(models.EU),
+		string(models.USCentral),
+		string(models.USEast),
+		string(models.USWest),
+		string(models.SIN),
+		string(models.UK),
+		string(models.AUS),
+		string(models.JPN),
+		string(models.IND),
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/pkg/contabo/v1.0.0/models"
+)
+
+func validCreateInstanceRequest() models.CreateInstanceRequest {
+	return models.CreateInstanceRequest{
+		ProductId:   ptr.To("V76"),
+		Period:      1,
+		ImageId:     ptr.To("d64d5c6c-9dda-4e38-8174-0ee282474d8a"),
+		Region:      ptr.To(models.EU),
+		SshKeys:     &[]int64{42},
+		DisplayName: ptr.To("capc-eu-cluster-machine"),
+		DefaultUser: ptr.To(models.CreateInstanceRequestDefaultUserAdmin),
+		AddOns: &models.CreateInstanceAddons{
+			PrivateNetworking: ptr.To(map[string]interface{}{}),
+		},
+	}
+}
+
+func TestCreateInstanceValidatorValidateLocal(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(request *models.CreateInstanceRequest)
+		wantErr string
+	}{
+		{
+			name:   "valid request",
+			mutate: func(request *models.CreateInstanceRequest) {},
+		},
+		{
+			name:    "missing product",
+			mutate:  func(request *models.CreateInstanceRequest) { request.ProductId = nil },
+			wantErr: "productId: Required value",
+		},
+		{
+			name:    "unsupported period",
+			mutate:  func(request *models.CreateInstanceRequest) { request.Period = 2 },
+			wantErr: "period: Unsupported value",
+		},
+		{
+			name: "unsupported region",
+			mutate: func(request *models.CreateInstanceRequest) {
+				request.Region = ptr.To(models.CreateInstanceRequestRegion("MARS"))
+			},
+			wantErr: "region: Unsupported value",
+		},
+		{
+			name:    "invalid image",
+			mutate:  func(request *models.CreateInstanceRequest) { request.ImageId = ptr.To("ubuntu") },
+			wantErr: "imageId: Invalid value",
+		},
+		{
+			name:    "duplicate SSH key",
+			mutate:  func(request *models.CreateInstanceRequest) { request.SshKeys = &[]int64{42, 42} },
+			wantErr: "sshKeys[1]: Duplicate value",
+		},
+		{
+			name: "user data too large",
+			mutate: func(request *models.CreateInstanceRequest) {
+				request.UserData = ptr.To(strings.Repeat("a", DefaultMaxUserDataSize+1))
+			},
+			wantErr: "userData: Too long",
+		},
+		{
+			name: "mixed extra storage",
+			mutate: func(request *models.CreateInstanceRequest) {
+				request.AddOns.ExtraStorage = &models.ExtraStorageRequest{Nvme: &[]string{"1"}, Ssd: &[]string{"2"}}
+			},
+			wantErr: "addOns.extraStorage: Forbidden",
+		},
+		{
+			name: "invalid add-on quantity",
+			mutate: func(request *models.CreateInstanceRequest) {
+				request.AddOns.AddonsIds = &[]models.AddOnRequest{{Id: 1019, Quantity: 0}}
+			},
+			wantErr: "addOns.addonsIds[0].quantity: Invalid value",
+		},
+	}
+
+	validator := NewCreateInstanceValidator(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := validCreateInstanceRequest()
+			tt.mutate(&request)
+			err := validator.Validate(context.Background(), request)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateImageCompatibility(t *testing.T) {
+	request := validCreateInstanceRequest()
+	if errs := validateImageCompatibility(request, "Linux"); len(errs) != 0 {
+		t.Fatalf("unexpected errors for Linux image: %v", errs)
+	}
+
+	request.UserData = ptr.To("#cloud-config")
+	errs := validateImageCompatibility(request, windowsOsType)
+	if len(errs) != 3 {
+		t.Fatalf("expected default user, SSH keys and user data errors for Windows image, got %v", errs)
+	}
+}
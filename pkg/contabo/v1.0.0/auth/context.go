@@ -0,0 +1,33 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import "context"
+
+type tokenManagerKey struct{}
+
+// WithTokenManager returns a context whose Contabo API requests are authorized with the given token manager instead
+// of the credentials of the transport, e.g. the credentials of another Contabo account
+func WithTokenManager(ctx context.Context, tokenManager *TokenManager) context.Context {
+	return context.WithValue(ctx, tokenManagerKey{}, tokenManager)
+}
+
+// TokenManagerFromContext returns the token manager set with WithTokenManager, or nil
+func TokenManagerFromContext(ctx context.Context) *TokenManager {
+	tokenManager, _ := ctx.Value(tokenManagerKey{}).(*TokenManager)
+	return tokenManager
+}
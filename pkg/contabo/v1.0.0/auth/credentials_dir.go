@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Files of a credentials directory, named after the keys of the contabo-credentials Secret of the default deployment
+// so that the directory can be this Secret mounted as a volume, the files rendered by the Vault agent or the ones of
+// a CSI secret store
+const (
+	ClientIDFile     = "client-id"
+	ClientSecretFile = "client-secret"
+	APIUserFile      = "api-user"
+	APIPasswordFile  = "api-password"
+
+	// SecondaryFilePrefix prefixes the files of the secondary credentials, e.g. secondary-client-id
+	SecondaryFilePrefix = "secondary-"
+)
+
+// Credentials are the OAuth2 client and the API user of a Contabo account
+type Credentials struct {
+	ClientID     string
+	ClientSecret string
+	APIUser      string
+	APIPassword  string
+}
+
+// IsEmpty returns true when none of the credentials is set
+func (c Credentials) IsEmpty() bool {
+	return c == Credentials{}
+}
+
+// IsComplete returns true when all the credentials are set
+func (c Credentials) IsComplete() bool {
+	return c.ClientID != "" && c.ClientSecret != "" && c.APIUser != "" && c.APIPassword != ""
+}
+
+// ReadCredentialsDir reads the primary and the secondary credentials from the files of dir, trimmed of the
+// surrounding whitespace. The primary credentials must be complete, the secondary ones complete or missing.
+func ReadCredentialsDir(dir string) (primary, secondary Credentials, err error) {
+	if primary, err = readCredentials(dir, ""); err != nil {
+		return Credentials{}, Credentials{}, err
+	}
+	if !primary.IsComplete() {
+		return Credentials{}, Credentials{}, fmt.Errorf("the credentials directory %s must hold the %s, %s, %s and %s files",
+			dir, ClientIDFile, ClientSecretFile, APIUserFile, APIPasswordFile)
+	}
+	if secondary, err = readCredentials(dir, SecondaryFilePrefix); err != nil {
+		return Credentials{}, Credentials{}, err
+	}
+	if !secondary.IsEmpty() && !secondary.IsComplete() {
+		return Credentials{}, Credentials{}, fmt.Errorf("the secondary credentials of the directory %s are incomplete, "+
+			"set all or none of the %s* files", dir, SecondaryFilePrefix)
+	}
+	return primary, secondary, nil
+}
+
+// readCredentials reads the credentials of the files starting with prefix, the missing files are left empty
+func readCredentials(dir, prefix string) (Credentials, error) {
+	var credentials Credentials
+	for name, value := range map[string]*string{
+		ClientIDFile:     &credentials.ClientID,
+		ClientSecretFile: &credentials.ClientSecret,
+		APIUserFile:      &credentials.APIUser,
+		APIPasswordFile:  &credentials.APIPassword,
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, prefix+name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to read the credentials file %s: %w", prefix+name, err)
+		}
+		*value = strings.TrimSpace(string(data))
+	}
+	return credentials, nil
+}
+
+// CredentialsDirWatcher reloads the credentials of the token managers from a credentials directory, so that the
+// credentials rotated by the Vault agent or in the mounted Secret are used without restarting the manager. The files
+// are read again every Interval, as the kubelet and the Vault agent replace them without a reliable change
+// notification. It is a Runnable of the controller-runtime manager running on every replica.
+type CredentialsDirWatcher struct {
+	Dir      string
+	Interval time.Duration
+	// Primary and Secondary are the token managers of the primary and of the secondary credentials, Secondary is nil
+	// when the directory held no secondary credentials at startup
+	Primary   *TokenManager
+	Secondary *TokenManager
+}
+
+// Start reloads the credentials every Interval until the context is done
+func (w *CredentialsDirWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.Reload(); err != nil {
+				authLog.Error(err, "Failed to reload the Contabo credentials, keeping the current ones", "dir", w.Dir)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false, the API requests of every replica are authorized with the credentials
+func (w *CredentialsDirWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Reload reads the credentials directory and replaces the credentials of the token managers which changed. The
+// current credentials are kept when the directory is incomplete, e.g. while the files are being rewritten.
+func (w *CredentialsDirWatcher) Reload() error {
+	primary, secondary, err := ReadCredentialsDir(w.Dir)
+	if err != nil {
+		return err
+	}
+	if w.Primary.SetCredentials(primary) {
+		authLog.Info("Reloaded the Contabo credentials", "dir", w.Dir)
+	}
+	if w.Secondary != nil && secondary.IsComplete() && w.Secondary.SetCredentials(secondary) {
+		authLog.Info("Reloaded the secondary Contabo credentials", "dir", w.Dir)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCredentialsDir(t *testing.T, dir, prefix string, credentials Credentials) {
+	t.Helper()
+	for name, value := range map[string]string{
+		ClientIDFile:     credentials.ClientID,
+		ClientSecretFile: credentials.ClientSecret,
+		APIUserFile:      credentials.APIUser,
+		APIPasswordFile:  credentials.APIPassword,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, prefix+name), []byte(value+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadCredentialsDir(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := ReadCredentialsDir(dir); err == nil || !strings.Contains(err.Error(), ClientIDFile) {
+		t.Errorf("ReadCredentialsDir() of an empty directory error = %v", err)
+	}
+
+	want := Credentials{ClientID: "client", ClientSecret: "secret", APIUser: "user@example.com", APIPassword: "password"}
+	writeCredentialsDir(t, dir, "", want)
+	primary, secondary, err := ReadCredentialsDir(dir)
+	if err != nil {
+		t.Fatalf("ReadCredentialsDir() error = %v", err)
+	}
+	if primary != want || !secondary.IsEmpty() {
+		t.Errorf("ReadCredentialsDir() = %+v, %+v, want %+v and no secondary credentials", primary, secondary, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, SecondaryFilePrefix+ClientIDFile), []byte("other"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadCredentialsDir(dir); err == nil || !strings.Contains(err.Error(), "secondary") {
+		t.Errorf("ReadCredentialsDir() with incomplete secondary credentials error = %v", err)
+	}
+}
+
+func TestCredentialsDirWatcherReload(t *testing.T) {
+	dir := t.TempDir()
+	credentials := Credentials{ClientID: "client", ClientSecret: "secret", APIUser: "user@example.com", APIPassword: "password"}
+	writeCredentialsDir(t, dir, "", credentials)
+	primary := NewTokenManager(credentials.ClientID, credentials.ClientSecret, credentials.APIUser, credentials.APIPassword)
+	primary.accessToken = "token"
+	watcher := &CredentialsDirWatcher{Dir: dir, Primary: primary}
+
+	if err := watcher.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if primary.accessToken != "token" {
+		t.Error("Reload() dropped the access token of unchanged credentials")
+	}
+
+	credentials.APIPassword = "rotated"
+	writeCredentialsDir(t, dir, "", credentials)
+	if err := watcher.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if primary.apiPassword != "rotated" || primary.accessToken != "" {
+		t.Errorf("Reload() kept the password %q and the access token %q of the rotated credentials", primary.apiPassword, primary.accessToken)
+	}
+
+	if err := os.Remove(filepath.Join(dir, APIPasswordFile)); err != nil {
+		t.Fatal(err)
+	}
+	if err := watcher.Reload(); err == nil {
+		t.Error("Reload() of an incomplete directory succeeded")
+	}
+	if primary.apiPassword != "rotated" {
+		t.Errorf("Reload() of an incomplete directory replaced the password with %q", primary.apiPassword)
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FailbackInterval is how long the secondary credentials are used after a failover before the primary credentials
+// are tried again
+const FailbackInterval = 5 * time.Minute
+
+// ErrAccessToken is returned by the requests sent without an access token, as none could be obtained with the
+// credentials
+var ErrAccessToken = errors.New("failed to get access token")
+
+// FailoverTokenManager wraps several token managers (e.g. a primary and a secondary
+// Contabo sub-user) and automatically switches to the next one when the active
+// credentials can no longer obtain a token or are rejected by the API. Once failed
+// over, the primary credentials are tried again every failback interval.
+type FailoverTokenManager struct {
+	mu        sync.RWMutex
+	managers  []*TokenManager
+	active    int
+	onFailure func(index int, err error)
+	// failedOverAt is the time the primary credentials were last found failing while others are active
+	failedOverAt     time.Time
+	failbackInterval time.Duration
+}
+
+// NewFailoverTokenManager creates a failover token manager, the first manager is the primary one
+func NewFailoverTokenManager(managers ...*TokenManager) *FailoverTokenManager {
+	return &FailoverTokenManager{
+		managers:         managers,
+		failbackInterval: FailbackInterval,
+	}
+}
+
+// WithFailbackInterval sets how long other credentials are used after a failover before the primary credentials
+// are tried again
+func (fm *FailoverTokenManager) WithFailbackInterval(interval time.Duration) *FailoverTokenManager {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.failbackInterval = interval
+	return fm
+}
+
+// SetFailureHandler registers a function called whenever credentials can no longer obtain a
+// token or are rejected by the API, e.g. to notify the operators
+func (fm *FailoverTokenManager) SetFailureHandler(handler func(index int, err error)) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.onFailure = handler
+}
+
+// GetToken returns a valid access token and the index of the credentials it was obtained with, failing over to the
+// next credentials if token acquisition fails. The primary credentials are tried first once the failback interval
+// elapsed since the failover.
+func (fm *FailoverTokenManager) GetToken() (string, int, error) {
+	fm.mu.RLock()
+	active := fm.active
+	total := len(fm.managers)
+	failback := active != 0 && time.Since(fm.failedOverAt) >= fm.failbackInterval
+	fm.mu.RUnlock()
+
+	if total == 0 {
+		return "", 0, errors.New("no Contabo credentials configured")
+	}
+
+	if failback {
+		token, err := fm.managers[0].GetToken()
+		if err == nil {
+			fm.failBack(active)
+			return token, 0, nil
+		}
+		authLog.Error(err, "Primary credentials still failing, keeping the active credentials", "credentials", active)
+		fm.mu.Lock()
+		fm.failedOverAt = time.Now()
+		fm.mu.Unlock()
+	}
+
+	var errs []error
+	for i := 0; i < total; i++ {
+		index := (active + i) % total
+		token, err := fm.managers[index].GetToken()
+		if err != nil {
+			authLog.Error(err, "Failed to get access token, trying next credentials", "credentials", index)
+			errs = append(errs, fmt.Errorf("credentials %d: %w", index, err))
+			fm.failed(index, err)
+			continue
+		}
+		if index != active {
+			fm.switchTo(active, index)
+		}
+		return token, index, nil
+	}
+
+	return "", 0, fmt.Errorf("all Contabo credentials failed: %w", errors.Join(errs...))
+}
+
+// MarkUnauthorized is called when the API rejected the token of the given credentials with 401 Unauthorized, it
+// invalidates the cached token and fails over to the next credentials. It returns true if another set of credentials
+// is now active.
+func (fm *FailoverTokenManager) MarkUnauthorized(index int) bool {
+	fm.mu.RLock()
+	total := len(fm.managers)
+	fm.mu.RUnlock()
+
+	if index < 0 || index >= total {
+		return false
+	}
+	fm.managers[index].Invalidate()
+	fm.failed(index, errors.New("access token rejected by the Contabo API"))
+	if total < 2 {
+		return false
+	}
+	return fm.switchTo(index, (index+1)%total)
+}
+
+// ActiveIndex returns the index of the credentials currently in use
+func (fm *FailoverTokenManager) ActiveIndex() int {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+	return fm.active
+}
+
+// failed calls the failure handler, if any
+func (fm *FailoverTokenManager) failed(index int, err error) {
+	fm.mu.RLock()
+	handler := fm.onFailure
+	fm.mu.RUnlock()
+	if handler != nil {
+		handler(index, err)
+	}
+}
+
+// switchTo moves the active credentials from one index to another, unless another
+// goroutine already switched away from the failing credentials
+func (fm *FailoverTokenManager) switchTo(from, to int) bool {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if fm.active != from {
+		// Already failed over by a concurrent request
+		return true
+	}
+	authLog.Info("Failing over to other Contabo credentials", "from", from, "to", to)
+	fm.active = to
+	if to != 0 {
+		fm.failedOverAt = time.Now()
+	}
+	return true
+}
+
+// failBack makes the primary credentials active again, unless another goroutine already switched the credentials
+func (fm *FailoverTokenManager) failBack(from int) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if fm.active != from {
+		return
+	}
+	authLog.Info("Failing back to the primary Contabo credentials", "from", from)
+	fm.active = 0
+}
+
+// FailoverTransport is an http.RoundTripper that authorizes requests with the active
+// credentials of a FailoverTokenManager and retries once with the next credentials
+// when the API answers with 401 Unauthorized. 403 Forbidden answers a request the
+// credentials are not allowed to send, it is returned as is. The requests whose context
+// carries a token manager, set with WithTokenManager, are authorized with it without failover.
+type FailoverTransport struct {
+	Base         http.RoundTripper
+	TokenManager *FailoverTokenManager
+}
+
+// NewFailoverTransport creates a new failover transport, using http.DefaultTransport if base is nil
+func NewFailoverTransport(base http.RoundTripper, tokenManager *FailoverTokenManager) *FailoverTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &FailoverTransport{
+		Base:         base,
+		TokenManager: tokenManager,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *FailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if tokenManager := TokenManagerFromContext(req.Context()); tokenManager != nil {
+		token, err := tokenManager.GetToken()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrAccessToken, err)
+		}
+		resp, err := t.roundTrip(req, token)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized {
+			tokenManager.Invalidate()
+		}
+		return resp, err
+	}
+
+	// The index is the one of the token sent, the active credentials may change concurrently
+	token, index, err := t.TokenManager.GetToken()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAccessToken, err)
+	}
+	resp, err := t.roundTrip(req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Only retry if another set of credentials took over and the body can be replayed
+	if !t.TokenManager.MarkUnauthorized(index) || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay request body: %w", err)
+		}
+		retry.Body = body
+	}
+	if token, _, err = t.TokenManager.GetToken(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAccessToken, err)
+	}
+	return t.roundTrip(retry, token)
+}
+
+// roundTrip sends the request authorized with the access token
+func (t *FailoverTransport) roundTrip(req *http.Request, token string) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.Base.RoundTrip(req)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// apiServer is a Contabo API answering each access token with the status code set for it, 200 by default, and
+// recording the tokens and bodies of the requests
+type apiServer struct {
+	mu          sync.Mutex
+	statusCodes map[string]int
+	tokens      []string
+	bodies      []string
+}
+
+func (s *apiServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	s.tokens = append(s.tokens, token)
+	s.bodies = append(s.bodies, string(body))
+	statusCode := s.statusCodes[token]
+	s.mu.Unlock()
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+}
+
+// newTestFailoverTokenManager returns a failover token manager of primary and secondary credentials, whose access
+// tokens are "primary" and "secondary"
+func newTestFailoverTokenManager(t *testing.T, primary, secondary *tokenEndpoint) *FailoverTokenManager {
+	t.Helper()
+	primary.token, secondary.token = "primary", "secondary"
+	primaryManager, _ := newTestTokenManager(t, primary)
+	secondaryManager, _ := newTestTokenManager(t, secondary)
+	return NewFailoverTokenManager(primaryManager, secondaryManager)
+}
+
+func TestFailoverTransport(t *testing.T) {
+	tests := []struct {
+		name              string
+		primaryStatusCode int
+		apiStatusCodes    map[string]int
+		method            string
+		body              string
+		wantStatusCode    int
+		wantTokens        []string
+		wantActive        int
+	}{
+		{
+			name:           "authorized",
+			method:         http.MethodGet,
+			wantStatusCode: http.StatusOK,
+			wantTokens:     []string{"primary"},
+		},
+		{
+			name:           "unauthorized token",
+			apiStatusCodes: map[string]int{"primary": http.StatusUnauthorized},
+			method:         http.MethodGet,
+			wantStatusCode: http.StatusOK,
+			wantTokens:     []string{"primary", "secondary"},
+			wantActive:     1,
+		},
+		{
+			name:           "unauthorized token replays the body",
+			apiStatusCodes: map[string]int{"primary": http.StatusUnauthorized},
+			method:         http.MethodPost,
+			body:           `{"displayName":"machine-0"}`,
+			wantStatusCode: http.StatusOK,
+			wantTokens:     []string{"primary", "secondary"},
+			wantActive:     1,
+		},
+		{
+			name:           "forbidden request",
+			apiStatusCodes: map[string]int{"primary": http.StatusForbidden},
+			method:         http.MethodPost,
+			body:           `{"displayName":"machine-0"}`,
+			wantStatusCode: http.StatusForbidden,
+			wantTokens:     []string{"primary"},
+		},
+		{
+			name:              "forbidden by the token endpoint",
+			primaryStatusCode: http.StatusForbidden,
+			method:            http.MethodGet,
+			wantStatusCode:    http.StatusOK,
+			wantTokens:        []string{"secondary"},
+			wantActive:        1,
+		},
+		{
+			name:              "unauthorized by the token endpoint",
+			primaryStatusCode: http.StatusUnauthorized,
+			method:            http.MethodGet,
+			wantStatusCode:    http.StatusOK,
+			wantTokens:        []string{"secondary"},
+			wantActive:        1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, secondary := &tokenEndpoint{}, &tokenEndpoint{}
+			primary.statusCode.Store(int32(tt.primaryStatusCode))
+			fm := newTestFailoverTokenManager(t, primary, secondary)
+			api := &apiServer{statusCodes: tt.apiStatusCodes}
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			req, err := http.NewRequest(tt.method, server.URL+"/v1/compute/instances", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			resp, err := NewFailoverTransport(nil, fm).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Errorf("status code = %d, want %d", resp.StatusCode, tt.wantStatusCode)
+			}
+			if !slices.Equal(api.tokens, tt.wantTokens) {
+				t.Errorf("tokens = %v, want %v", api.tokens, tt.wantTokens)
+			}
+			for _, body := range api.bodies {
+				if body != tt.body {
+					t.Errorf("body = %q, want %q", body, tt.body)
+				}
+			}
+			if active := fm.ActiveIndex(); active != tt.wantActive {
+				t.Errorf("ActiveIndex() = %d, want %d", active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestFailoverTransportConcurrentSwitch(t *testing.T) {
+	fm := newTestFailoverTokenManager(t, &tokenEndpoint{delay: 10 * time.Millisecond}, &tokenEndpoint{})
+	var mu sync.Mutex
+	failed := []int{}
+	fm.SetFailureHandler(func(index int, _ error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, index)
+	})
+	api := &apiServer{statusCodes: map[string]int{"primary": http.StatusUnauthorized}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	transport := NewFailoverTransport(nil, fm)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/compute/instances", strings.NewReader("{}"))
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Errorf("RoundTrip() error = %v", err)
+				return
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("status code = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Only the credentials whose token was rejected are marked, the secondary credentials stay active
+	if active := fm.ActiveIndex(); active != 1 {
+		t.Errorf("ActiveIndex() = %d, want 1", active)
+	}
+	for _, index := range failed {
+		if index != 0 {
+			t.Errorf("credentials %d marked as failed, want only the primary ones", index)
+		}
+	}
+}
+
+func TestFailoverTokenManagerFailsBack(t *testing.T) {
+	primary, secondary := &tokenEndpoint{}, &tokenEndpoint{}
+	primary.statusCode.Store(http.StatusUnauthorized)
+	fm := newTestFailoverTokenManager(t, primary, secondary)
+
+	if token, index, err := fm.GetToken(); err != nil || token != "secondary" || index != 1 {
+		t.Fatalf("GetToken() = %q, %d, %v, want secondary", token, index, err)
+	}
+
+	// The primary credentials are only tried again once the failback interval elapsed
+	primary.statusCode.Store(http.StatusOK)
+	if _, index, _ := fm.GetToken(); index != 1 {
+		t.Errorf("GetToken() index = %d before the failback interval, want 1", index)
+	}
+
+	fm.WithFailbackInterval(0)
+	primary.statusCode.Store(http.StatusUnauthorized)
+	if _, index, _ := fm.GetToken(); index != 1 {
+		t.Errorf("GetToken() index = %d with failing primary credentials, want 1", index)
+	}
+	primary.statusCode.Store(http.StatusOK)
+	if token, index, err := fm.GetToken(); err != nil || token != "primary" || index != 0 {
+		t.Errorf("GetToken() = %q, %d, %v, want primary", token, index, err)
+	}
+	if active := fm.ActiveIndex(); active != 0 {
+		t.Errorf("ActiveIndex() = %d, want 0", active)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Results of the access token refreshes
+const (
+	RefreshResultSuccess = "success"
+	RefreshResultFailure = "failure"
+	// RefreshResultCached is a failed refresh served with the cached access token, still valid
+	RefreshResultCached = "cached"
+)
+
+// Metrics reports the access tokens of the token managers, labeled with the name of their credentials. A nil Metrics
+// records nothing.
+type Metrics struct {
+	expiry    *prometheus.GaugeVec
+	refreshes *prometheus.CounterVec
+}
+
+// NewMetrics returns the access token metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		expiry: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "capc_contabo_access_token_expiry_timestamp_seconds",
+			Help: "Expiry of the cached Contabo API access token per credentials, as a Unix timestamp",
+		}, []string{"credentials"}),
+		refreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "capc_contabo_access_token_refreshes_total",
+			Help: "Number of Contabo API access token refreshes per credentials and result",
+		}, []string{"credentials", "result"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.expiry.Describe(ch)
+	m.refreshes.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.expiry.Collect(ch)
+	m.refreshes.Collect(ch)
+}
+
+func (m *Metrics) refreshed(credentials, result string) {
+	if m != nil {
+		m.refreshes.WithLabelValues(credentials, result).Inc()
+	}
+}
+
+func (m *Metrics) expires(credentials string, expiresAt time.Time) {
+	if m != nil {
+		m.expiry.WithLabelValues(credentials).Set(float64(expiresAt.Unix()))
+	}
+}
@@ -18,6 +18,7 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -26,11 +27,15 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 var authLog = log.Log.WithName("contabo-auth")
 
+// DefaultTokenURL is the OAuth2 token endpoint of Contabo
+const DefaultTokenURL = "https://auth.contabo.com/auth/realms/contabo/protocol/openid-connect/token"
+
 // OAuth2TokenResponse represents the response from Contabo's OAuth2 token endpoint
 type OAuth2TokenResponse struct {
 	AccessToken string `json:"access_token"`
@@ -38,7 +43,26 @@ type OAuth2TokenResponse struct {
 	ExpiresIn   int    `json:"expires_in"`
 }
 
-// TokenManager manages OAuth2 token lifecycle with automatic refresh
+// Refresh of the access tokens
+const (
+	// RefreshBefore is how long before its expiry an access token is refreshed, at most a fifth of its lifetime, so
+	// that the requests do not carry a token expiring while they are sent
+	RefreshBefore = time.Minute
+
+	// RefreshRetryInterval is how long the cached access token is served after a failed refresh before refreshing again
+	RefreshRetryInterval = 10 * time.Second
+
+	// tokenRequestTimeout bounds the token requests, the requests waiting for the token would hang with the endpoint
+	tokenRequestTimeout = 30 * time.Second
+)
+
+// ErrTokenEndpointUnavailable is returned when the OAuth2 token endpoint cannot be reached or answers with a server
+// error, a transient failure which does not tell whether the credentials are valid
+var ErrTokenEndpointUnavailable = errors.New("OAuth2 token endpoint unavailable")
+
+// TokenManager manages OAuth2 token lifecycle with automatic refresh. The token is refreshed ahead of its expiry and
+// the concurrent refreshes are deduplicated, a single token request is sent for all the callers. When the token
+// endpoint fails transiently, the cached token is served until it expires instead of failing the requests.
 type TokenManager struct {
 	mu           sync.RWMutex
 	clientID     string
@@ -47,7 +71,15 @@ type TokenManager struct {
 	apiPassword  string
 	accessToken  string
 	expiresAt    time.Time
+	refreshAt    time.Time
 	tokenURL     string
+	// generation changes with the credentials, a token requested with former credentials is not cached
+	generation uint64
+
+	refresh    singleflight.Group
+	httpClient *http.Client
+	metrics    *Metrics
+	name       string
 }
 
 // NewTokenManager creates a new token manager for Contabo OAuth2 authentication
@@ -57,57 +89,118 @@ func NewTokenManager(clientID, clientSecret, apiUser, apiPassword string) *Token
 		clientSecret: clientSecret,
 		apiUser:      apiUser,
 		apiPassword:  apiPassword,
-		tokenURL:     "https://auth.contabo.com/auth/realms/contabo/protocol/openid-connect/token",
+		tokenURL:     DefaultTokenURL,
+		httpClient:   &http.Client{Timeout: tokenRequestTimeout},
 	}
 }
 
+// WithTokenURL sets the OAuth2 token endpoint the access tokens are requested from, e.g. the one of a fake Contabo
+// API server in the integration tests
+func (tm *TokenManager) WithTokenURL(tokenURL string) *TokenManager {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.tokenURL = tokenURL
+	return tm
+}
+
+// WithMetrics reports the access tokens of the token manager in the metrics, labeled with the name of its credentials
+func (tm *TokenManager) WithMetrics(metrics *Metrics, name string) *TokenManager {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.metrics = metrics
+	tm.name = name
+	return tm
+}
+
 // GetToken returns a valid access token, refreshing if necessary
 func (tm *TokenManager) GetToken() (string, error) {
 	tm.mu.RLock()
-	// Check if token is still valid
-	if tm.accessToken != "" && time.Now().Before(tm.expiresAt) {
+	// Check if token is still valid and not due for refresh
+	if tm.accessToken != "" && time.Now().Before(tm.refreshAt) {
 		token := tm.accessToken
 		tm.mu.RUnlock()
 		return token, nil
 	}
 	tm.mu.RUnlock()
 
-	// Need to refresh token
-	return tm.refreshToken()
+	// Need to refresh token, once for all the callers
+	token, err, _ := tm.refresh.Do("", func() (any, error) {
+		return tm.refreshToken()
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.(string), nil
 }
 
-// refreshToken obtains a new access token
+// refreshToken obtains a new access token, or serves the cached one while valid when the token endpoint fails
+// transiently
 func (tm *TokenManager) refreshToken() (string, error) {
-	tm.mu.Lock()
-	defer tm.mu.Unlock()
-
+	tm.mu.RLock()
 	// Double-check in case another goroutine already refreshed
-	if tm.accessToken != "" && time.Now().Before(tm.expiresAt) {
-		return tm.accessToken, nil
+	if tm.accessToken != "" && time.Now().Before(tm.refreshAt) {
+		token := tm.accessToken
+		tm.mu.RUnlock()
+		return token, nil
 	}
-
-	authLog.Info("Refreshing OAuth2 access token")
-
 	data := url.Values{}
 	data.Set("client_id", tm.clientID)
 	data.Set("client_secret", tm.clientSecret)
 	data.Set("username", tm.apiUser)
 	data.Set("password", tm.apiPassword)
 	data.Set("grant_type", "password")
+	tokenURL, generation := tm.tokenURL, tm.generation
+	tm.mu.RUnlock()
+
+	authLog.Info("Refreshing OAuth2 access token")
+	tokenResp, err := tm.requestToken(tokenURL, data)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	now := time.Now()
+	if err != nil {
+		if errors.Is(err, ErrTokenEndpointUnavailable) && tm.accessToken != "" && now.Before(tm.expiresAt) {
+			authLog.Error(err, "Failed to refresh OAuth2 access token, using the cached one until it expires",
+				"expiresAt", tm.expiresAt)
+			tm.refreshAt = now.Add(RefreshRetryInterval)
+			tm.metrics.refreshed(tm.name, RefreshResultCached)
+			return tm.accessToken, nil
+		}
+		tm.metrics.refreshed(tm.name, RefreshResultFailure)
+		return "", err
+	}
+	tm.metrics.refreshed(tm.name, RefreshResultSuccess)
+	if generation != tm.generation {
+		// The credentials were replaced during the request, the next call requests a token with the new ones
+		return tokenResp.AccessToken, nil
+	}
+
+	// Update token and expiration time
+	lifetime := time.Duration(tokenResp.ExpiresIn) * time.Second
+	tm.accessToken = tokenResp.AccessToken
+	tm.expiresAt = now.Add(lifetime)
+	tm.refreshAt = tm.expiresAt.Add(-min(RefreshBefore, lifetime/5))
+	tm.metrics.expires(tm.name, tm.expiresAt)
+
+	authLog.Info("Successfully refreshed OAuth2 access token", "expiresAt", tm.expiresAt)
+
+	return tm.accessToken, nil
+}
 
+// requestToken requests an access token from the token endpoint
+func (tm *TokenManager) requestToken(tokenURL string, data url.Values) (*OAuth2TokenResponse, error) {
 	// Create HTTP request with proper headers
-	req, err := http.NewRequest("POST", tm.tokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return "", fmt.Errorf("failed to create OAuth2 request: %w", err)
+		return nil, fmt.Errorf("failed to create OAuth2 request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := tm.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to request OAuth2 token: %w", err)
+		return nil, fmt.Errorf("%w: failed to request OAuth2 token: %w", ErrTokenEndpointUnavailable, err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -118,24 +211,21 @@ func (tm *TokenManager) refreshToken() (string, error) {
 	if resp.StatusCode != http.StatusOK {
 		// Read all the response body
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("OAuth2 token request failed with status %d: %s", resp.StatusCode, string(body))
+		err := fmt.Errorf("OAuth2 token request failed with status %d: %s", resp.StatusCode, string(body))
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			err = fmt.Errorf("%w: %w", ErrTokenEndpointUnavailable, err)
+		}
+		return nil, err
 	}
 
 	var tokenResp OAuth2TokenResponse
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return "", fmt.Errorf("failed to decode OAuth2 token response: %w", err)
+		return nil, fmt.Errorf("failed to decode OAuth2 token response: %w", err)
 	}
-
-	// Update token and expiration time
-	tm.accessToken = tokenResp.AccessToken
-	tm.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-
-	authLog.Info("Successfully refreshed OAuth2 access token", "expiresAt", tm.expiresAt)
-
-	return tm.accessToken, nil
+	return &tokenResp, nil
 }
 
-// IsTokenValid returns true if the current token is valid (with 5 minute buffer)
+// IsTokenValid returns true if the current token is valid
 func (tm *TokenManager) IsTokenValid() bool {
 	tm.mu.RLock()
 	defer tm.mu.RUnlock()
@@ -148,3 +238,27 @@ func (tm *TokenManager) GetExpirationTime() time.Time {
 	defer tm.mu.RUnlock()
 	return tm.expiresAt
 }
+
+// SetCredentials replaces the credentials of the token manager, the cached access token is dropped so the next
+// GetToken call requests one with the new credentials. It returns false when the credentials did not change.
+func (tm *TokenManager) SetCredentials(credentials Credentials) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if (Credentials{ClientID: tm.clientID, ClientSecret: tm.clientSecret, APIUser: tm.apiUser, APIPassword: tm.apiPassword}) == credentials {
+		return false
+	}
+	tm.clientID, tm.clientSecret = credentials.ClientID, credentials.ClientSecret
+	tm.apiUser, tm.apiPassword = credentials.APIUser, credentials.APIPassword
+	tm.generation++
+	tm.accessToken = ""
+	tm.expiresAt, tm.refreshAt = time.Time{}, time.Time{}
+	return true
+}
+
+// Invalidate drops the cached access token so the next GetToken call requests a new one
+func (tm *TokenManager) Invalidate() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.accessToken = ""
+	tm.expiresAt, tm.refreshAt = time.Time{}, time.Time{}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// tokenEndpoint is an OAuth2 token endpoint answering with the status code set, 200 by default, and the access
+// token set, "token" by default
+type tokenEndpoint struct {
+	requests   atomic.Int32
+	statusCode atomic.Int32
+	delay      time.Duration
+	token      string
+}
+
+func (e *tokenEndpoint) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	e.requests.Add(1)
+	time.Sleep(e.delay)
+	if statusCode := int(e.statusCode.Load()); statusCode != 0 && statusCode != http.StatusOK {
+		w.WriteHeader(statusCode)
+		return
+	}
+	token := e.token
+	if token == "" {
+		token = "token"
+	}
+	_ = json.NewEncoder(w).Encode(OAuth2TokenResponse{AccessToken: token, TokenType: "Bearer", ExpiresIn: 300})
+}
+
+func newTestTokenManager(t *testing.T, endpoint *tokenEndpoint) (*TokenManager, *Metrics) {
+	t.Helper()
+	server := httptest.NewServer(endpoint)
+	t.Cleanup(server.Close)
+	metrics := NewMetrics()
+	return NewTokenManager("client", "secret", "user@example.com", "password").WithTokenURL(server.URL).WithMetrics(metrics, "primary"), metrics
+}
+
+func TestTokenManagerDeduplicatesRefreshes(t *testing.T) {
+	endpoint := &tokenEndpoint{delay: 50 * time.Millisecond}
+	tm, metrics := newTestTokenManager(t, endpoint)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if token, err := tm.GetToken(); err != nil || token != "token" {
+				t.Errorf("GetToken() = %q, %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if requests := endpoint.requests.Load(); requests != 1 {
+		t.Errorf("token requests = %d, want 1", requests)
+	}
+	if got := testutil.ToFloat64(metrics.refreshes.WithLabelValues("primary", RefreshResultSuccess)); got != 1 {
+		t.Errorf("successful refreshes = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.expiry.WithLabelValues("primary")); got != float64(tm.GetExpirationTime().Unix()) {
+		t.Errorf("expiry = %v, want %d", got, tm.GetExpirationTime().Unix())
+	}
+}
+
+func TestTokenManagerRefreshesBeforeExpiry(t *testing.T) {
+	endpoint := &tokenEndpoint{}
+	tm, _ := newTestTokenManager(t, endpoint)
+	if _, err := tm.GetToken(); err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if want := tm.expiresAt.Add(-RefreshBefore); !tm.refreshAt.Equal(want) {
+		t.Errorf("refreshAt = %v, want %v", tm.refreshAt, want)
+	}
+
+	// The token is still valid but due for refresh
+	tm.refreshAt = time.Now().Add(-time.Second)
+	if _, err := tm.GetToken(); err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if requests := endpoint.requests.Load(); requests != 2 {
+		t.Errorf("token requests = %d, want 2", requests)
+	}
+}
+
+func TestTokenManagerFallsBackToCachedToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expired    bool
+		wantErr    bool
+	}{
+		{name: "unavailable endpoint", statusCode: http.StatusServiceUnavailable},
+		{name: "rate limited endpoint", statusCode: http.StatusTooManyRequests},
+		{name: "rejected credentials", statusCode: http.StatusUnauthorized, wantErr: true},
+		{name: "expired token", statusCode: http.StatusServiceUnavailable, expired: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := &tokenEndpoint{}
+			tm, metrics := newTestTokenManager(t, endpoint)
+			if _, err := tm.GetToken(); err != nil {
+				t.Fatalf("GetToken() error = %v", err)
+			}
+			tm.refreshAt = time.Now().Add(-time.Second)
+			if tt.expired {
+				tm.expiresAt = tm.refreshAt
+			}
+			endpoint.statusCode.Store(int32(tt.statusCode))
+
+			token, err := tm.GetToken()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("GetToken() = %q, want an error", token)
+				}
+				if got := testutil.ToFloat64(metrics.refreshes.WithLabelValues("primary", RefreshResultFailure)); got != 1 {
+					t.Errorf("failed refreshes = %v, want 1", got)
+				}
+				return
+			}
+			if err != nil || token != "token" {
+				t.Fatalf("GetToken() = %q, %v, want the cached token", token, err)
+			}
+			if got := testutil.ToFloat64(metrics.refreshes.WithLabelValues("primary", RefreshResultCached)); got != 1 {
+				t.Errorf("cached refreshes = %v, want 1", got)
+			}
+
+			// The refresh is not attempted again before RefreshRetryInterval
+			if _, err := tm.GetToken(); err != nil {
+				t.Fatalf("GetToken() error = %v", err)
+			}
+			if requests := endpoint.requests.Load(); requests != 2 {
+				t.Errorf("token requests = %d, want 2", requests)
+			}
+		})
+	}
+}
+
+func TestTokenManagerUnreachableEndpoint(t *testing.T) {
+	tm := NewTokenManager("client", "secret", "user@example.com", "password").WithTokenURL("http://127.0.0.1:1")
+	if _, err := tm.GetToken(); !errors.Is(err, ErrTokenEndpointUnavailable) {
+		t.Errorf("GetToken() error = %v, want %v", err, ErrTokenEndpointUnavailable)
+	}
+}
@@ -0,0 +1,8 @@
+package contabo
+
+import _ "embed"
+
+// OpenAPISpec is the Contabo OpenAPI specification the client and the models are generated from
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
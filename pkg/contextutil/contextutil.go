@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package contextutil carries the metadata of a reconciliation, the cluster and machine it is for, its request ID and
+// whether it is a dry run, and the count of its Contabo API requests and the last one changing resources through the
+// service and client layers. The lower layers such as the rate limiter, the logs and the metrics label their outputs
+// with it without threading parameters.
+package contextutil
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+)
+
+// The context keys are unexported types so that no other package can collide with them
+type (
+	clusterKey   struct{}
+	machineKey   struct{}
+	requestIDKey struct{}
+	traceIDKey   struct{}
+	dryRunKey    struct{}
+	apiCallsKey  struct{}
+	changeKey    struct{}
+)
+
+// WithCluster returns a context whose Contabo API requests are sent for the cluster, namespace/name of the Cluster
+func WithCluster(ctx context.Context, cluster string) context.Context {
+	return context.WithValue(ctx, clusterKey{}, cluster)
+}
+
+// ClusterFromContext returns the cluster the Contabo API requests of the context are sent for, empty for the
+// requests of the whole account such as the inventory
+func ClusterFromContext(ctx context.Context) string {
+	cluster, _ := ctx.Value(clusterKey{}).(string)
+	return cluster
+}
+
+// WithMachine returns a context whose Contabo API requests are sent for the machine, namespace/name of the
+// ContaboMachine
+func WithMachine(ctx context.Context, machine string) context.Context {
+	return context.WithValue(ctx, machineKey{}, machine)
+}
+
+// MachineFromContext returns the machine the Contabo API requests of the context are sent for, empty when they are
+// not sent for a machine
+func MachineFromContext(ctx context.Context) string {
+	machine, _ := ctx.Value(machineKey{}).(string)
+	return machine
+}
+
+// WithRequestID returns a context whose Contabo API requests are sent with the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID of the context, empty when none was set
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithTraceID returns a context whose Contabo API requests are sent with the trace ID, grouping them in the Contabo
+// audits
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID of the context, empty when none was set
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// WithDryRun returns a context whose changes are only computed and reported, not applied
+func WithDryRun(ctx context.Context, dryRun bool) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, dryRun)
+}
+
+// IsDryRun reports whether the changes of the context must not be applied
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// WithAPICallCounter returns a context counting the Contabo API requests sent with it and its children, e.g. the
+// requests of a reconciliation
+func WithAPICallCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, apiCallsKey{}, new(atomic.Int64))
+}
+
+// CountAPICall counts a Contabo API request sent with the context, nothing is counted without WithAPICallCounter
+func CountAPICall(ctx context.Context) {
+	if calls, ok := ctx.Value(apiCallsKey{}).(*atomic.Int64); ok {
+		calls.Add(1)
+	}
+}
+
+// APICallsFromContext returns the number of Contabo API requests counted since WithAPICallCounter
+func APICallsFromContext(ctx context.Context) int64 {
+	if calls, ok := ctx.Value(apiCallsKey{}).(*atomic.Int64); ok {
+		return calls.Load()
+	}
+	return 0
+}
+
+// WithChangeRecorder returns a context recording the request ID of the last Contabo API request changing resources
+// sent with it and its children, e.g. the order or the reinstall of an instance during a reconciliation
+func WithChangeRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, changeKey{}, new(atomic.Pointer[string]))
+}
+
+// RecordChange records the request ID of a Contabo API request changing resources sent with the context, nothing is
+// recorded without WithChangeRecorder
+func RecordChange(ctx context.Context, requestID string) {
+	if change, ok := ctx.Value(changeKey{}).(*atomic.Pointer[string]); ok {
+		change.Store(&requestID)
+	}
+}
+
+// LastChangeFromContext returns the request ID of the last Contabo API request changing resources recorded since
+// WithChangeRecorder, empty when none was
+func LastChangeFromContext(ctx context.Context) string {
+	if change, ok := ctx.Value(changeKey{}).(*atomic.Pointer[string]); ok {
+		if requestID := change.Load(); requestID != nil {
+			return *requestID
+		}
+	}
+	return ""
+}
+
+// LogValues returns the key-value pairs of the metadata set on the context, for logr.Logger.WithValues
+func LogValues(ctx context.Context) []interface{} {
+	values := []interface{}{}
+	if cluster := ClusterFromContext(ctx); cluster != "" {
+		values = append(values, "cluster", cluster)
+	}
+	if machine := MachineFromContext(ctx); machine != "" {
+		values = append(values, "machine", machine)
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		values = append(values, "requestID", requestID)
+	}
+	if IsDryRun(ctx) {
+		values = append(values, "dryRun", true)
+	}
+	return values
+}
+
+// Logger returns the logger with the metadata of the context
+func Logger(ctx context.Context, log logr.Logger) logr.Logger {
+	if values := LogValues(ctx); len(values) > 0 {
+		return log.WithValues(values...)
+	}
+	return log
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contextutil
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestContextMetadata(t *testing.T) {
+	ctx := context.Background()
+	if ClusterFromContext(ctx) != "" || MachineFromContext(ctx) != "" || RequestIDFromContext(ctx) != "" || IsDryRun(ctx) {
+		t.Fatal("expected no metadata on an empty context")
+	}
+	if values := LogValues(ctx); len(values) != 0 {
+		t.Fatalf("unexpected log values %v", values)
+	}
+
+	ctx = WithCluster(ctx, "default/test")
+	ctx = WithMachine(ctx, "default/test-cp-0")
+	ctx = WithRequestID(ctx, "8a1f0c52-6d3b-4f4e-9d0a-2f6b1c3e5a7d")
+	ctx = WithDryRun(ctx, true)
+	if ClusterFromContext(ctx) != "default/test" {
+		t.Fatalf("unexpected cluster %q", ClusterFromContext(ctx))
+	}
+	if MachineFromContext(ctx) != "default/test-cp-0" {
+		t.Fatalf("unexpected machine %q", MachineFromContext(ctx))
+	}
+	if RequestIDFromContext(ctx) != "8a1f0c52-6d3b-4f4e-9d0a-2f6b1c3e5a7d" {
+		t.Fatalf("unexpected request ID %q", RequestIDFromContext(ctx))
+	}
+	if !IsDryRun(ctx) {
+		t.Fatal("expected a dry run")
+	}
+
+	expected := []interface{}{"cluster", "default/test", "machine", "default/test-cp-0", "requestID", "8a1f0c52-6d3b-4f4e-9d0a-2f6b1c3e5a7d", "dryRun", true}
+	if values := LogValues(ctx); !slices.Equal(values, expected) {
+		t.Fatalf("unexpected log values %v", values)
+	}
+
+	// The values of a parent context are overridden by its children only
+	child := WithDryRun(ctx, false)
+	if IsDryRun(child) || !IsDryRun(ctx) {
+		t.Fatal("expected the dry run flag to be overridden in the child context only")
+	}
+}
+
+func TestAPICallCounter(t *testing.T) {
+	ctx := context.Background()
+	CountAPICall(ctx)
+	if calls := APICallsFromContext(ctx); calls != 0 {
+		t.Fatalf("unexpected %d calls counted without counter", calls)
+	}
+
+	ctx = WithAPICallCounter(ctx)
+	CountAPICall(ctx)
+	// The children of the context count in the same counter
+	CountAPICall(WithMachine(ctx, "default/test-cp-0"))
+	if calls := APICallsFromContext(ctx); calls != 2 {
+		t.Fatalf("unexpected %d calls counted, expected 2", calls)
+	}
+
+	// A new counter starts from zero without changing the parent one
+	child := WithAPICallCounter(ctx)
+	CountAPICall(child)
+	if APICallsFromContext(child) != 1 || APICallsFromContext(ctx) != 2 {
+		t.Fatalf("unexpected calls counted %d and %d", APICallsFromContext(child), APICallsFromContext(ctx))
+	}
+}
+
+func TestChangeRecorder(t *testing.T) {
+	ctx := context.Background()
+	RecordChange(ctx, "lost")
+	if requestID := LastChangeFromContext(ctx); requestID != "" {
+		t.Fatalf("unexpected change %q recorded without recorder", requestID)
+	}
+
+	ctx = WithChangeRecorder(ctx)
+	if requestID := LastChangeFromContext(ctx); requestID != "" {
+		t.Fatalf("unexpected change %q recorded before any request", requestID)
+	}
+	RecordChange(ctx, "first")
+	// The children of the context record in the same recorder, the last change wins
+	RecordChange(WithMachine(ctx, "default/test-cp-0"), "second")
+	if requestID := LastChangeFromContext(ctx); requestID != "second" {
+		t.Fatalf("unexpected change %q recorded, expected second", requestID)
+	}
+}
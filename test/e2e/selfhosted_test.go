@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ctnr-io/cluster-api-provider-contabo/test/utils"
+)
+
+// selfHostedNamespace is the namespace of the cluster pivoted to itself
+const selfHostedNamespace = "contabo-e2e-self-hosted"
+
+// Required Environment Variables for the self-hosted suite:
+// - E2E_SELF_HOSTED=true: Runs the self-hosted suite, skipped otherwise.
+// - E2E_SELF_HOSTED_IMG: Manager image pullable from the workload cluster, the Kind image is not.
+var _ = Describe("Self-hosted management cluster", Ordered, Label("self-hosted"), func() {
+	var bootstrapKubeconfig, workloadKubeconfig string
+	var providerIDs string
+
+	kubectl := func(kubeconfig string, args ...string) (string, error) {
+		return utils.Run(exec.Command("kubectl", append([]string{"--kubeconfig", kubeconfig}, args...)...))
+	}
+	clusterctl := func(args ...string) (string, error) {
+		clusterctlPath := os.Getenv("CLUSTERCTL")
+		if clusterctlPath == "" {
+			clusterctlPath = "clusterctl"
+		}
+		return utils.Run(exec.Command(clusterctlPath, args...))
+	}
+
+	// Helper to wait for all the ContaboMachines of the namespace to be ready
+	waitForMachinesReady := func(kubeconfig string) {
+		Eventually(func(g Gomega) {
+			output, err := kubectl(kubeconfig, "get", "contabomachines", "-n", selfHostedNamespace, "-o", "jsonpath={.items[*].status.ready}")
+			g.Expect(err).NotTo(HaveOccurred())
+			ready := strings.Fields(output)
+			g.Expect(ready).To(HaveLen(2))
+			g.Expect(ready).To(HaveEach("true"))
+		}, 15*time.Minute, 10*time.Second).Should(Succeed())
+	}
+
+	// Helper returning the provider IDs of the ContaboMachines, instances must never be replaced by a pivot
+	getProviderIDs := func(kubeconfig string) string {
+		output, err := kubectl(kubeconfig, "get", "contabomachines", "-n", selfHostedNamespace,
+			"--sort-by=.metadata.name", "-o", "jsonpath={.items[*].spec.providerID}")
+		Expect(err).NotTo(HaveOccurred())
+		return strings.TrimSpace(output)
+	}
+
+	// Helper returning the holder of the controller manager leader election lease
+	getLeaseHolder := func(kubeconfig string) string {
+		output, _ := kubectl(kubeconfig, "get", "lease", fmt.Sprintf("contabo-%s.cluster.x-k8s.io", namespace),
+			"-n", namespace, "-o", "jsonpath={.spec.holderIdentity}")
+		return strings.TrimSpace(output)
+	}
+
+	BeforeAll(func() {
+		if os.Getenv("E2E_SELF_HOSTED") != "true" {
+			Skip("set E2E_SELF_HOSTED=true to run the self-hosted suite")
+		}
+		Expect(os.Getenv("E2E_SELF_HOSTED_IMG")).NotTo(BeEmpty(), "E2E_SELF_HOSTED_IMG is required")
+		bootstrapKubeconfig = os.Getenv("KUBECONFIG")
+		Expect(bootstrapKubeconfig).NotTo(BeEmpty(), "KUBECONFIG of the bootstrap cluster is required")
+		workloadKubeconfig = "/tmp/contabo-e2e-self-hosted.kubeconfig"
+
+		By("deploying the controller-manager on the bootstrap cluster")
+		_, err := utils.Run(exec.Command("make", "deploy", fmt.Sprintf("IMG=%s", projectImage)))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("creating the workload cluster")
+		clusterManifest, err := os.ReadFile("test/fixtures/cluster.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		manifestFile := "/tmp/contabo-e2e-self-hosted.yaml"
+		manifest := strings.ReplaceAll(string(clusterManifest), "namespace: contabo-e2e-test", "namespace: "+selfHostedNamespace)
+		Expect(os.WriteFile(manifestFile, []byte(manifest), 0644)).To(Succeed())
+		defer os.Remove(manifestFile)
+		_, _ = kubectl(bootstrapKubeconfig, "create", "namespace", selfHostedNamespace)
+		_, err = kubectl(bootstrapKubeconfig, "apply", "-f", manifestFile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		if os.Getenv("E2E_SELF_HOSTED") != "true" {
+			return
+		}
+		// The cluster is deleted from whichever cluster manages it
+		for _, kubeconfig := range []string{workloadKubeconfig, bootstrapKubeconfig} {
+			_, _ = kubectl(kubeconfig, "delete", "cluster", "test-cluster", "-n", selfHostedNamespace, "--ignore-not-found=true", "--timeout=15m")
+		}
+	})
+
+	It("provisions the workload cluster", func() {
+		By("waiting for the workload cluster kubeconfig")
+		var kubeconfigB64 string
+		Eventually(func() string {
+			kubeconfigB64, _ = kubectl(bootstrapKubeconfig, "get", "secret", "test-cluster-kubeconfig", "-n", selfHostedNamespace, "-o", "jsonpath={.data.value}")
+			return kubeconfigB64
+		}, 15*time.Minute, 10*time.Second).ShouldNot(BeEmpty())
+		decoded, err := utils.Base64Decode(kubeconfigB64)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(workloadKubeconfig, []byte(decoded), 0600)).To(Succeed())
+
+		By("installing Cilium CNI in the workload cluster")
+		Eventually(func() error {
+			_, err := kubectl(workloadKubeconfig, "get", "nodes")
+			return err
+		}, 15*time.Minute, 10*time.Second).Should(Succeed())
+		_, err = utils.Run(exec.Command("./bin/cilium", "install", "--version", "1.18.2", "--kubeconfig", workloadKubeconfig))
+		Expect(err).NotTo(HaveOccurred())
+
+		waitForMachinesReady(bootstrapKubeconfig)
+		providerIDs = getProviderIDs(bootstrapKubeconfig)
+	})
+
+	It("installs the providers on the workload cluster", func() {
+		By("installing Cluster API core components")
+		_, err := clusterctl("init", "--kubeconfig", workloadKubeconfig, "--core", "cluster-api", "--bootstrap", "kubeadm", "--addon", "helm")
+		Expect(err).NotTo(HaveOccurred())
+
+		By("deploying the controller-manager")
+		_, err = utils.Run(exec.Command("make", "deploy", fmt.Sprintf("IMG=%s", os.Getenv("E2E_SELF_HOSTED_IMG")),
+			fmt.Sprintf("KUBECTL=kubectl --kubeconfig=%s", workloadKubeconfig)))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("waiting for the controller-manager to hold the leader election lease")
+		Eventually(func() string {
+			return getLeaseHolder(workloadKubeconfig)
+		}, 5*time.Minute, 5*time.Second).ShouldNot(BeEmpty())
+	})
+
+	It("moves the cluster to itself", func() {
+		_, err := clusterctl("move", "--kubeconfig", bootstrapKubeconfig, "--to-kubeconfig", workloadKubeconfig, "-n", selfHostedNamespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("verifying the cluster is no longer managed by the bootstrap cluster")
+		output, err := kubectl(bootstrapKubeconfig, "get", "contabomachines", "-n", selfHostedNamespace, "-o", "name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.TrimSpace(output)).To(BeEmpty())
+
+		By("verifying the machines are ready and were not replaced")
+		waitForMachinesReady(workloadKubeconfig)
+		Expect(getProviderIDs(workloadKubeconfig)).To(Equal(providerIDs))
+	})
+
+	It("keeps the machines when the controller-manager restarts", func() {
+		holder := getLeaseHolder(workloadKubeconfig)
+		Expect(holder).NotTo(BeEmpty())
+
+		By("deleting the controller-manager pod")
+		_, err := kubectl(workloadKubeconfig, "delete", "pods", "-n", namespace, "-l", "control-plane=controller-manager", "--wait=false")
+		Expect(err).NotTo(HaveOccurred())
+
+		By("waiting for the new controller-manager to take over the lease")
+		Eventually(func() string {
+			return getLeaseHolder(workloadKubeconfig)
+		}, 5*time.Minute, 5*time.Second).ShouldNot(Or(BeEmpty(), Equal(holder)))
+
+		By("verifying the machines are ready and were not replaced")
+		Consistently(func() string {
+			return getProviderIDs(workloadKubeconfig)
+		}, 2*time.Minute, 10*time.Second).Should(Equal(providerIDs))
+		waitForMachinesReady(workloadKubeconfig)
+	})
+
+	It("moves the cluster back to the bootstrap cluster", func() {
+		_, err := clusterctl("move", "--kubeconfig", workloadKubeconfig, "--to-kubeconfig", bootstrapKubeconfig, "-n", selfHostedNamespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		waitForMachinesReady(bootstrapKubeconfig)
+		Expect(getProviderIDs(bootstrapKubeconfig)).To(Equal(providerIDs))
+	})
+})